@@ -0,0 +1,39 @@
+# -*- coding: utf-8 -*-
+# Generated by the protocol buffer compiler.  DO NOT EDIT!
+# source: releasechecker/v1/checker.proto
+"""Generated protocol buffer code."""
+from google.protobuf import descriptor as _descriptor
+from google.protobuf import descriptor_pool as _descriptor_pool
+from google.protobuf import symbol_database as _symbol_database
+from google.protobuf.internal import builder as _builder
+# @@protoc_insertion_point(imports)
+
+_sym_db = _symbol_database.Default()
+
+
+from google.protobuf import struct_pb2 as google_dot_protobuf_dot_struct__pb2
+
+
+DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x1freleasechecker/v1/checker.proto\x12\x11releasechecker.v1\x1a\x1cgoogle/protobuf/struct.proto\"3\n\x0c\x43heckRequest\x12\x12\n\nrepository\x18\x01 \x01(\t\x12\x0f\n\x07version\x18\x02 \x01(\t\"k\n\x11\x43heckBatchRequest\x12/\n\x06\x63hecks\x18\x01 \x03(\x0b\x32\x1f.releasechecker.v1.CheckRequest\x12\x11\n\tpage_size\x18\x02 \x01(\x05\x12\x12\n\npage_token\x18\x03 \x01(\t\"\x99\x01\n\x0b\x43heckResult\x12\x12\n\nrepository\x18\x01 \x01(\t\x12\x0f\n\x07version\x18\x02 \x01(\t\x12/\n\x08\x61nalysis\x18\x03 \x01(\x0b\x32\x1b.releasechecker.v1.AnalysisH\x00\x12)\n\x05\x65rror\x18\x04 \x01(\x0b\x32\x18.releasechecker.v1.ErrorH\x00\x42\t\n\x07outcome\"\xb8\x01\n\x08\x41nalysis\x12\x0e\n\x06status\x18\x01 \x01(\t\x12\x16\n\x0elatest_version\x18\x02 \x01(\t\x12\x1a\n\x12\x63omparison_version\x18\x03 \x01(\t\x12\x17\n\x0freleases_behind\x18\x04 \x01(\x05\x12\x13\n\x0b\x64rift_score\x18\x05 \x01(\x05\x12\x10\n\x08\x64\x65graded\x18\x06 \x01(\x08\x12(\n\x07\x64\x65tails\x18\x07 \x01(\x0b\x32\x17.google.protobuf.Struct\"c\n\x05\x45rror\x12\x0c\n\x04\x63ode\x18\x01 \x01(\t\x12\x0f\n\x07message\x18\x02 \x01(\t\x12\x11\n\tretryable\x18\x03 \x01(\x08\x12(\n\x07\x64\x65tails\x18\x04 \x01(\x0b\x32\x17.google.protobuf.Struct\"\xa8\x01\n\x12\x43heckBatchResponse\x12/\n\x07results\x18\x01 \x03(\x0b\x32\x1e.releasechecker.v1.CheckResult\x12\x0f\n\x07\x63hecked\x18\x02 \x01(\x05\x12\x11\n\tsucceeded\x18\x03 \x01(\x05\x12\x0e\n\x06\x66\x61iled\x18\x04 \x01(\x05\x12\x14\n\x0ctotal_checks\x18\x05 \x01(\x05\x12\x17\n\x0fnext_page_token\x18\x06 \x01(\t2\x8c\x02\n\x0eReleaseChecker\x12H\n\x05\x43heck\x12\x1f.releasechecker.v1.CheckRequest\x1a\x1e.releasechecker.v1.CheckResult\x12Y\n\nCheckBatch\x12$.releasechecker.v1.CheckBatchRequest\x1a%.releasechecker.v1.CheckBatchResponse\x12U\n\x0b\x43heckStream\x12$.releasechecker.v1.CheckBatchRequest\x1a\x1e.releasechecker.v1.CheckResult0\x01\x42SZQgithub.com/nickromney-org/github-release-version-checker/pkg/api/releasecheckerv1b\x06proto3')
+
+_globals = globals()
+_builder.BuildMessageAndEnumDescriptors(DESCRIPTOR, _globals)
+_builder.BuildTopDescriptorsAndMessages(DESCRIPTOR, 'releasechecker.v1.checker_pb2', _globals)
+if _descriptor._USE_C_DESCRIPTORS == False:
+  DESCRIPTOR._options = None
+  DESCRIPTOR._serialized_options = b'ZQgithub.com/nickromney-org/github-release-version-checker/pkg/api/releasecheckerv1'
+  _globals['_CHECKREQUEST']._serialized_start=84
+  _globals['_CHECKREQUEST']._serialized_end=135
+  _globals['_CHECKBATCHREQUEST']._serialized_start=137
+  _globals['_CHECKBATCHREQUEST']._serialized_end=244
+  _globals['_CHECKRESULT']._serialized_start=247
+  _globals['_CHECKRESULT']._serialized_end=400
+  _globals['_ANALYSIS']._serialized_start=403
+  _globals['_ANALYSIS']._serialized_end=587
+  _globals['_ERROR']._serialized_start=589
+  _globals['_ERROR']._serialized_end=688
+  _globals['_CHECKBATCHRESPONSE']._serialized_start=691
+  _globals['_CHECKBATCHRESPONSE']._serialized_end=859
+  _globals['_RELEASECHECKER']._serialized_start=862
+  _globals['_RELEASECHECKER']._serialized_end=1130
+# @@protoc_insertion_point(module_scope)
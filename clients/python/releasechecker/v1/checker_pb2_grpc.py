@@ -0,0 +1,146 @@
+# Generated by the gRPC Python protocol compiler plugin. DO NOT EDIT!
+"""Client and server classes corresponding to protobuf-defined services."""
+import grpc
+
+from releasechecker.v1 import checker_pb2 as releasechecker_dot_v1_dot_checker__pb2
+
+
+class ReleaseCheckerStub(object):
+    """ReleaseChecker checks pinned versions against their repositories' releases.
+    Clients send one of the proxy's tokens as "authorization: Bearer <token>"
+    metadata when it requires them.
+    """
+
+    def __init__(self, channel):
+        """Constructor.
+
+        Args:
+            channel: A grpc.Channel.
+        """
+        self.Check = channel.unary_unary(
+                '/releasechecker.v1.ReleaseChecker/Check',
+                request_serializer=releasechecker_dot_v1_dot_checker__pb2.CheckRequest.SerializeToString,
+                response_deserializer=releasechecker_dot_v1_dot_checker__pb2.CheckResult.FromString,
+                )
+        self.CheckBatch = channel.unary_unary(
+                '/releasechecker.v1.ReleaseChecker/CheckBatch',
+                request_serializer=releasechecker_dot_v1_dot_checker__pb2.CheckBatchRequest.SerializeToString,
+                response_deserializer=releasechecker_dot_v1_dot_checker__pb2.CheckBatchResponse.FromString,
+                )
+        self.CheckStream = channel.unary_stream(
+                '/releasechecker.v1.ReleaseChecker/CheckStream',
+                request_serializer=releasechecker_dot_v1_dot_checker__pb2.CheckBatchRequest.SerializeToString,
+                response_deserializer=releasechecker_dot_v1_dot_checker__pb2.CheckResult.FromString,
+                )
+
+
+class ReleaseCheckerServicer(object):
+    """ReleaseChecker checks pinned versions against their repositories' releases.
+    Clients send one of the proxy's tokens as "authorization: Bearer <token>"
+    metadata when it requires them.
+    """
+
+    def Check(self, request, context):
+        """Check checks one repository and version.
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def CheckBatch(self, request, context):
+        """CheckBatch checks one page of a manifest; a failed check fails only its
+        own result.
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def CheckStream(self, request, context):
+        """CheckStream checks every page of a manifest, sending each result as soon
+        as it is ready, in any order.
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+
+def add_ReleaseCheckerServicer_to_server(servicer, server):
+    rpc_method_handlers = {
+            'Check': grpc.unary_unary_rpc_method_handler(
+                    servicer.Check,
+                    request_deserializer=releasechecker_dot_v1_dot_checker__pb2.CheckRequest.FromString,
+                    response_serializer=releasechecker_dot_v1_dot_checker__pb2.CheckResult.SerializeToString,
+            ),
+            'CheckBatch': grpc.unary_unary_rpc_method_handler(
+                    servicer.CheckBatch,
+                    request_deserializer=releasechecker_dot_v1_dot_checker__pb2.CheckBatchRequest.FromString,
+                    response_serializer=releasechecker_dot_v1_dot_checker__pb2.CheckBatchResponse.SerializeToString,
+            ),
+            'CheckStream': grpc.unary_stream_rpc_method_handler(
+                    servicer.CheckStream,
+                    request_deserializer=releasechecker_dot_v1_dot_checker__pb2.CheckBatchRequest.FromString,
+                    response_serializer=releasechecker_dot_v1_dot_checker__pb2.CheckResult.SerializeToString,
+            ),
+    }
+    generic_handler = grpc.method_handlers_generic_handler(
+            'releasechecker.v1.ReleaseChecker', rpc_method_handlers)
+    server.add_generic_rpc_handlers((generic_handler,))
+
+
+ # This class is part of an EXPERIMENTAL API.
+class ReleaseChecker(object):
+    """ReleaseChecker checks pinned versions against their repositories' releases.
+    Clients send one of the proxy's tokens as "authorization: Bearer <token>"
+    metadata when it requires them.
+    """
+
+    @staticmethod
+    def Check(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/releasechecker.v1.ReleaseChecker/Check',
+            releasechecker_dot_v1_dot_checker__pb2.CheckRequest.SerializeToString,
+            releasechecker_dot_v1_dot_checker__pb2.CheckResult.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def CheckBatch(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/releasechecker.v1.ReleaseChecker/CheckBatch',
+            releasechecker_dot_v1_dot_checker__pb2.CheckBatchRequest.SerializeToString,
+            releasechecker_dot_v1_dot_checker__pb2.CheckBatchResponse.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def CheckStream(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_stream(request, target, '/releasechecker.v1.ReleaseChecker/CheckStream',
+            releasechecker_dot_v1_dot_checker__pb2.CheckBatchRequest.SerializeToString,
+            releasechecker_dot_v1_dot_checker__pb2.CheckResult.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// actionInputAliases maps action inputs to the flags they set where the names
+// differ; every other input is named after its flag
+var actionInputAliases = map[string]string{"INPUT_VERSION": "compare"}
+
+// actionFailNever is the fail-on input that never fails the step on a status
+const actionFailNever = "never"
+
+var actionCmd = &cobra.Command{
+	Use:   "action",
+	Short: "Run as a GitHub Action step, configured by INPUT_* variables",
+	Long: `Check a version as a GitHub Action step. Each input is read from its
+INPUT_<NAME> variable, as GitHub passes them, and sets the flag of the same name:
+INPUT_REPO sets --repo and INPUT_CRITICAL-DAYS sets --critical-days. The version
+input sets --compare.
+
+Output is in --ci mode: annotations, step outputs in $GITHUB_OUTPUT and the job
+summary in $GITHUB_STEP_SUMMARY. The step fails when the status is fail-on or
+worse: expired unless the input says warning, critical or never. Config files
+listing several repositories are checked as a batch, failing per --aggregate.`,
+	Example: `  # In action.yml, or any step that sets the inputs as variables
+  INPUT_VERSION=2.328.0 INPUT_FAIL-ON=critical github-release-version-checker action`,
+	Args: cobra.NoArgs,
+	RunE: runAction,
+}
+
+func init() {
+	rootCmd.AddCommand(actionCmd)
+}
+
+func runAction(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	flags := rootCmd.Flags()
+	flags.AddFlagSet(rootCmd.PersistentFlags())
+	if err := applyActionInputs(flags, os.LookupEnv); err != nil {
+		return invalidInput(err)
+	}
+	// Inputs can set the shared flags resolved before this command ran
+	if err := resolvePersistentFlags(rootCmd, nil); err != nil {
+		return err
+	}
+	return run(rootCmd, nil)
+}
+
+// applyActionInputs sets flags from the step's inputs, turning on --ci and
+// failing on expired versions unless the fail-on input says otherwise
+func applyActionInputs(flags *pflag.FlagSet, lookup func(string) (string, bool)) error {
+	inputs := flagVariables(flags, lookup, actionInput, actionInputAliases)
+	delete(inputs, "output-file") // Step output goes to the log
+	switch inputs["fail-on"] {
+	case "":
+		inputs["fail-on"] = "expired"
+	case actionFailNever:
+		delete(inputs, "fail-on")
+	}
+	inputs["ci"] = "true"
+	return setFlags(flags, inputs, func(name string) string { return "input " + name })
+}
+
+// actionInput names the variable holding the input for a flag. GitHub
+// upper-cases input names and keeps their hyphens.
+func actionInput(name string) string {
+	return "INPUT_" + strings.ToUpper(name)
+}
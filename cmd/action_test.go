@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func TestApplyActionInputs(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    map[string]string // Flag values
+		wantErr string
+	}{
+		{
+			name: "defaults",
+			want: map[string]string{"ci": "true", "fail-on": "expired", "compare": "[]", "repo": ""},
+		},
+		{
+			name: "inputs",
+			env:  map[string]string{"INPUT_VERSION": "2.328.0", "INPUT_REPO": "k8s", "INPUT_CRITICAL-DAYS": " 7 ", "INPUT_FAIL-ON": "critical"},
+			want: map[string]string{"compare": "[2.328.0]", "repo": "k8s", "critical-days": "7", "fail-on": "critical"},
+		},
+		{
+			name: "never fails",
+			env:  map[string]string{"INPUT_FAIL-ON": "never"},
+			want: map[string]string{"fail-on": ""},
+		},
+		{
+			name: "skipped flags and empty inputs",
+			env:  map[string]string{"INPUT_JSON": "true", "INPUT_REPO": "", "INPUT_CI": "false"},
+			want: map[string]string{"json": "false", "repo": "", "ci": "true"},
+		},
+		{
+			name:    "invalid value",
+			env:     map[string]string{"INPUT_CRITICAL-DAYS": "soon"},
+			wantErr: "invalid input critical-days",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			flags.Bool("ci", false, "")
+			flags.Bool("json", false, "")
+			flags.String("fail-on", "", "")
+			flags.String("repo", "", "")
+			flags.StringSlice("compare", nil, "")
+			flags.Int("critical-days", 12, "")
+
+			err := applyActionInputs(flags, func(name string) (string, bool) {
+				value, ok := tt.env[name]
+				return value, ok
+			})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("applyActionInputs() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for name, want := range tt.want {
+				if got := flags.Lookup(name).Value.String(); got != want {
+					t.Errorf("--%s = %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,243 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/nickromney-org/github-release-version-checker/pkg/checker"
+)
+
+// Aggregation policies deciding whether a batch run passes
+const (
+	aggregateAnyExpired = "any-expired-fails"    // Any expired or unchecked repository fails
+	aggregateWorstOf    = "worst-of"             // Fails if the worst status is critical or expired
+	aggregatePercentage = "percentage-threshold" // Fails if too many repositories are expired or unchecked
+)
+
+// aggregatePolicies lists the accepted --aggregate values
+var aggregatePolicies = []string{aggregateAnyExpired, aggregateWorstOf, aggregatePercentage}
+
+// statusFailed is the overall status when the worst outcome is a failed check
+const statusFailed = "failed"
+
+var (
+	aggregatePolicy    string // --aggregate
+	aggregateThreshold int    // --aggregate-threshold, a percentage
+)
+
+// batchSummary is the overall outcome of a batch run
+type batchSummary struct {
+	Total     int    `json:"total"`
+	Current   int    `json:"current"`
+	Warning   int    `json:"warning"`
+	Critical  int    `json:"critical"`
+	Expired   int    `json:"expired"`
+	Failed    int    `json:"failed"`
+	Degraded  int    `json:"degraded"`       // Checked on incomplete data
+	Waived    int    `json:"waived"`         // Expired, but under an active waiver
+	Overall   string `json:"overall_status"` // Worst status, or "failed"
+	Aggregate string `json:"aggregate"`
+	Threshold int    `json:"threshold_percent,omitempty"`
+	Strict    bool   `json:"strict,omitempty"`
+	Passed    bool   `json:"passed"`
+}
+
+// validateAggregate checks the --aggregate and --aggregate-threshold values
+func validateAggregate(policy string, threshold int) error {
+	valid := false
+	for _, p := range aggregatePolicies {
+		valid = valid || p == policy
+	}
+	if !valid {
+		return fmt.Errorf("invalid aggregate %q: must be one of %s", policy, strings.Join(aggregatePolicies, ", "))
+	}
+	if threshold < 0 || threshold > 100 {
+		return fmt.Errorf("aggregate-threshold must be between 0 and 100")
+	}
+	return nil
+}
+
+// summariseBatch counts statuses and applies the aggregation policy. With strict,
+// repositories that are behind at all count against the batch as expired ones do.
+func summariseBatch(results []batchResult, policy string, threshold int, strict bool) batchSummary {
+	s := batchSummary{Total: len(results), Aggregate: policy, Strict: strict}
+	if policy == aggregatePercentage {
+		s.Threshold = threshold
+	}
+
+	for _, r := range results {
+		if r.Err != nil {
+			s.Failed++
+			continue
+		}
+		if r.Analysis.IsDegraded() {
+			s.Degraded++
+		}
+		if r.Analysis.Waived {
+			s.Waived++
+		}
+		switch r.Analysis.Status() {
+		case checker.StatusCurrent:
+			s.Current++
+		case checker.StatusWarning:
+			s.Warning++
+		case checker.StatusCritical:
+			s.Critical++
+		case checker.StatusExpired:
+			s.Expired++
+		}
+	}
+
+	switch {
+	case s.Failed > 0:
+		s.Overall = statusFailed
+	case s.Expired > 0:
+		s.Overall = string(checker.StatusExpired)
+	case s.Critical > 0:
+		s.Overall = string(checker.StatusCritical)
+	case s.Warning > 0:
+		s.Overall = string(checker.StatusWarning)
+	default:
+		s.Overall = string(checker.StatusCurrent)
+	}
+
+	bad := s.Failed + s.Expired
+	if strict {
+		bad += s.Critical + s.Warning
+	}
+	switch policy {
+	case aggregateWorstOf:
+		s.Passed = bad+s.Critical == 0
+	case aggregatePercentage:
+		s.Passed = bad*100 <= threshold*s.Total
+	default:
+		s.Passed = bad == 0
+	}
+	return s
+}
+
+// err explains why the batch failed, or returns nil if it passed
+func (s batchSummary) err() error {
+	if s.Passed {
+		return nil
+	}
+
+	var problems []string
+	if s.Failed > 0 {
+		problems = append(problems, fmt.Sprintf("%d of %d repositories could not be checked", s.Failed, s.Total))
+	}
+	if s.Expired > 0 {
+		problems = append(problems, fmt.Sprintf("%d of %d repositories expired", s.Expired, s.Total))
+	}
+	if (s.Strict || s.Aggregate == aggregateWorstOf) && s.Critical > 0 {
+		problems = append(problems, fmt.Sprintf("%d of %d repositories critical", s.Critical, s.Total))
+	}
+	if s.Strict && s.Warning > 0 {
+		problems = append(problems, fmt.Sprintf("%d of %d repositories behind", s.Warning, s.Total))
+	}
+	message := strings.Join(problems, "; ")
+	if s.Aggregate == aggregatePercentage {
+		message += fmt.Sprintf(" (more than %d%%)", s.Threshold)
+	}
+	return &exitError{code: 1, err: errors.New(message)}
+}
+
+// statusCounts renders the per-status counts, e.g. "2 current, 1 expired"
+func (s batchSummary) statusCounts() string {
+	counts := []struct {
+		n    int
+		name string
+	}{
+		{s.Current, "current"}, {s.Warning, "warning"}, {s.Critical, "critical"},
+		{s.Expired, "expired"}, {s.Failed, "failed"},
+	}
+	var parts []string
+	for _, c := range counts {
+		if c.n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", c.n, c.name))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// verdict renders the aggregation outcome, e.g. "pass (any-expired-fails)"
+func (s batchSummary) verdict() string {
+	result := "pass"
+	if !s.Passed {
+		result = "fail"
+	}
+	policy := s.Aggregate
+	if s.Aggregate == aggregatePercentage {
+		policy += fmt.Sprintf(", %d%%", s.Threshold)
+	}
+	if s.Strict {
+		policy += ", strict"
+	}
+	return fmt.Sprintf("%s (%s)", result, policy)
+}
+
+// printBatchSummaryTerminal writes the overall summary after the per-repository results
+func printBatchSummaryTerminal(w io.Writer, s batchSummary) {
+	fmt.Fprintln(w)
+	cyan.Fprintln(w, "📊 Summary")
+	cyan.Fprintln(w, "─────────────────────────────────────")
+	fmt.Fprintf(w, "Repositories:   %d (%s)\n", s.Total, s.statusCounts())
+	fmt.Fprintf(w, "Overall status: %s\n", s.Overall)
+	if s.Degraded > 0 {
+		yellow.Fprintf(w, "Incomplete:     %d checked on incomplete release data\n", s.Degraded)
+	}
+	if s.Waived > 0 {
+		yellow.Fprintf(w, "Waived:         %d expired under an active waiver\n", s.Waived)
+	}
+	if s.Passed {
+		green.Fprintf(w, "Result:         %s\n", s.verdict())
+	} else {
+		red.Fprintf(w, "Result:         %s\n", s.verdict())
+	}
+}
+
+// printBatchSummaryCI writes the overall summary as a log group, and to the job
+// summary when running in GitHub Actions
+func printBatchSummaryCI(w io.Writer, s batchSummary) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "::group::📊 Batch Summary")
+	fmt.Fprintf(w, "Repositories: %d (%s)\n", s.Total, s.statusCounts())
+	fmt.Fprintf(w, "Overall status: %s\n", s.Overall)
+	if s.Degraded > 0 {
+		fmt.Fprintf(w, "Incomplete data: %d\n", s.Degraded)
+	}
+	if s.Waived > 0 {
+		fmt.Fprintf(w, "Waived: %d\n", s.Waived)
+	}
+	fmt.Fprintf(w, "Result: %s\n", s.verdict())
+	fmt.Fprintln(w, "::endgroup::")
+
+	if summaryFile := os.Getenv("GITHUB_STEP_SUMMARY"); summaryFile != "" {
+		if err := writeBatchSummaryMarkdown(summaryFile, s); err != nil {
+			fmt.Fprintf(w, "::warning::Failed to write job summary: %v\n", err)
+		}
+	}
+}
+
+// writeBatchSummaryMarkdown appends the overall summary table to the job summary
+func writeBatchSummaryMarkdown(summaryFile string, s batchSummary) error {
+	f, err := os.OpenFile(summaryFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	fmt.Fprintf(f, "## 📊 Batch Summary\n\n")
+	fmt.Fprintf(f, "| Status | Repositories |\n")
+	fmt.Fprintf(f, "|--------|--------------|\n")
+	fmt.Fprintf(f, "| Current | %d |\n", s.Current)
+	fmt.Fprintf(f, "| Behind | %d |\n", s.Warning)
+	fmt.Fprintf(f, "| Critical | %d |\n", s.Critical)
+	fmt.Fprintf(f, "| Expired | %d |\n", s.Expired)
+	fmt.Fprintf(f, "| Failed | %d |\n", s.Failed)
+	fmt.Fprintf(f, "\n**Overall status:** %s · **Result:** %s\n\n---\n\n", s.Overall, s.verdict())
+	return nil
+}
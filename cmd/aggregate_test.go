@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/nickromney-org/github-release-version-checker/pkg/checker"
+)
+
+// statusResult returns a batch result with the given status
+func statusResult(status checker.Status) batchResult {
+	a := &checker.Analysis{LatestVersion: mustParseVersion("2.329.0"), ComparisonVersion: mustParseVersion("2.328.0")}
+	switch status {
+	case checker.StatusCurrent:
+		a.ComparisonVersion = a.LatestVersion
+	case checker.StatusWarning:
+		a.ReleasesBehind = 1
+	case checker.StatusCritical:
+		a.ReleasesBehind, a.IsCritical = 1, true
+	case checker.StatusExpired:
+		a.ReleasesBehind, a.IsExpired = 1, true
+	}
+	return batchResult{Repository: "acme/" + string(status), Analysis: a}
+}
+
+func TestSummariseBatch(t *testing.T) {
+	failed := batchResult{Repository: "acme/missing", Err: errors.New("not found")}
+	mixed := []batchResult{
+		statusResult(checker.StatusCurrent), statusResult(checker.StatusCurrent), statusResult(checker.StatusCurrent),
+		statusResult(checker.StatusCurrent), statusResult(checker.StatusCurrent), statusResult(checker.StatusCurrent),
+		statusResult(checker.StatusCurrent), statusResult(checker.StatusCurrent), statusResult(checker.StatusWarning),
+		statusResult(checker.StatusExpired),
+	}
+
+	tests := []struct {
+		name        string
+		results     []batchResult
+		policy      string
+		threshold   int
+		strict      bool
+		wantOverall string
+		wantPassed  bool
+		wantErr     string
+	}{
+		{
+			name:        "any-expired-fails passes critical",
+			results:     []batchResult{statusResult(checker.StatusCurrent), statusResult(checker.StatusCritical)},
+			policy:      aggregateAnyExpired,
+			wantOverall: "critical",
+			wantPassed:  true,
+		},
+		{
+			name:        "any-expired-fails fails on a failed check",
+			results:     []batchResult{statusResult(checker.StatusCurrent), failed},
+			policy:      aggregateAnyExpired,
+			wantOverall: "failed",
+			wantErr:     "1 of 2 repositories could not be checked",
+		},
+		{
+			name:        "worst-of fails on critical",
+			results:     []batchResult{statusResult(checker.StatusCurrent), statusResult(checker.StatusCritical)},
+			policy:      aggregateWorstOf,
+			wantOverall: "critical",
+			wantErr:     "1 of 2 repositories critical",
+		},
+		{
+			name:        "worst-of passes warnings",
+			results:     []batchResult{statusResult(checker.StatusWarning), statusResult(checker.StatusCurrent)},
+			policy:      aggregateWorstOf,
+			wantOverall: "warning",
+			wantPassed:  true,
+		},
+		{
+			name:        "percentage within threshold",
+			results:     mixed,
+			policy:      aggregatePercentage,
+			threshold:   10,
+			wantOverall: "expired",
+			wantPassed:  true,
+		},
+		{
+			name:        "percentage over threshold",
+			results:     mixed,
+			policy:      aggregatePercentage,
+			threshold:   5,
+			wantOverall: "expired",
+			wantErr:     "1 of 10 repositories expired (more than 5%)",
+		},
+		{
+			name:        "strict fails warnings",
+			results:     []batchResult{statusResult(checker.StatusWarning), statusResult(checker.StatusCurrent)},
+			policy:      aggregateAnyExpired,
+			strict:      true,
+			wantOverall: "warning",
+			wantErr:     "1 of 2 repositories behind",
+		},
+		{
+			name:        "strict percentage counts warnings",
+			results:     mixed,
+			policy:      aggregatePercentage,
+			threshold:   10,
+			strict:      true,
+			wantOverall: "expired",
+			wantErr:     "1 of 10 repositories expired; 1 of 10 repositories behind (more than 10%)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := summariseBatch(tt.results, tt.policy, tt.threshold, tt.strict)
+			if s.Overall != tt.wantOverall || s.Passed != tt.wantPassed {
+				t.Errorf("summariseBatch() overall %s passed %v, want %s %v", s.Overall, s.Passed, tt.wantOverall, tt.wantPassed)
+			}
+			got := ""
+			if err := s.err(); err != nil {
+				got = err.Error()
+			}
+			if got != tt.wantErr {
+				t.Errorf("err() = %q, want %q", got, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSummariseBatch_Waived(t *testing.T) {
+	waived := statusResult(checker.StatusWarning)
+	waived.Analysis.Waived = true
+
+	s := summariseBatch([]batchResult{waived, statusResult(checker.StatusCurrent)}, aggregateAnyExpired, 0, false)
+	if s.Waived != 1 || s.Warning != 1 || !s.Passed {
+		t.Errorf("summariseBatch() = %+v, want 1 waived warning that passes", s)
+	}
+}
+
+func TestValidateAggregate(t *testing.T) {
+	if err := validateAggregate(aggregateWorstOf, 10); err != nil {
+		t.Errorf("validateAggregate(worst-of) error = %v", err)
+	}
+	if err := validateAggregate("majority", 10); err == nil {
+		t.Error("validateAggregate(majority) want error")
+	}
+	if err := validateAggregate(aggregatePercentage, 101); err == nil {
+		t.Error("validateAggregate(threshold 101) want error")
+	}
+}
+
+// TestExecute_AggregateSingleCheck tests that --aggregate is validated when
+// only one repository is checked, not just in batch mode
+func TestExecute_AggregateSingleCheck(t *testing.T) {
+	_, err := executeRoot(t, "-c", "2.328.0", "--offline", "--aggregate", "majority")
+	if err == nil || !strings.Contains(err.Error(), `invalid aggregate "majority"`) {
+		t.Fatalf("error = %v, want invalid aggregate", err)
+	}
+	if code := classifyError(err).Code; code != errorCodeInvalidInput {
+		t.Errorf("error code = %s, want %s", code, errorCodeInvalidInput)
+	}
+}
+
+func TestPrintBatchSummaryTerminal(t *testing.T) {
+	results := []batchResult{statusResult(checker.StatusCurrent), statusResult(checker.StatusExpired)}
+
+	var buf bytes.Buffer
+	printBatchSummaryTerminal(&buf, summariseBatch(results, aggregateAnyExpired, 0, false))
+	out := buf.String()
+	for _, want := range []string{"2 (1 current, 1 expired)", "Overall status: expired", "fail (any-expired-fails)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("summary missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/nickromney-org/github-release-version-checker/internal/config"
+	"github.com/nickromney-org/github-release-version-checker/pkg/checker"
+	"github.com/nickromney-org/github-release-version-checker/pkg/client"
+	"github.com/nickromney-org/github-release-version-checker/pkg/render"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+// issueLabel marks the issues alerts open, so a repeat run finds them
+const issueLabel = "release-version-checker"
+
+// pageSeverities are the PagerDuty severities paged for each status
+var pageSeverities = map[checker.Status]string{
+	checker.StatusWarning:  "warning",
+	checker.StatusCritical: "error",
+	checker.StatusExpired:  "critical",
+}
+
+// issueTracker opens issues; *client.Client implements it
+type issueTracker interface {
+	FindOpenIssue(ctx context.Context, title, label string) (string, error)
+	CreateIssue(ctx context.Context, issue client.Issue) (string, error)
+}
+
+// alerter acts on results that need attention, as the config file's alert
+// rules map their status: notifying, opening an issue, paging, or nothing
+type alerter struct {
+	file   *config.File // Alert rules and settings; nil notifies for everything
+	digest bool         // Notifications go in the --digest instead
+
+	// issues returns the tracker for issue_repo; tests replace it
+	issues func(repoConfig *config.RepositoryConfig) (issueTracker, error)
+}
+
+// newAlerter creates an alerter using the config file routed by hosts
+func newAlerter(hosts *apiHosts, digest bool) *alerter {
+	return &alerter{
+		file:   hosts.file,
+		digest: digest,
+		issues: func(repoConfig *config.RepositoryConfig) (issueTracker, error) {
+			// Always the host's own API: --base-url may be a read-only release proxy
+			host := repoConfig.SourceHost()
+			ghClient := newGitHubClient(hosts.token(host).Value, repoConfig.Owner, repoConfig.Repo)
+			if apiURL := hosts.file.APIURL(host); apiURL != "" {
+				if err := ghClient.SetBaseURL(apiURL); err != nil {
+					return nil, err
+				}
+			}
+			return ghClient, nil
+		},
+	}
+}
+
+// alert runs the actions for the analysis's status, returning every failure
+func (a *alerter) alert(ctx context.Context, analysis *checker.Analysis) error {
+	status := analysis.Status()
+	if status == checker.StatusCurrent {
+		return nil
+	}
+
+	var errs []error
+	for _, action := range a.file.AlertRulesFor(analysis.Repository).Actions(string(status)) {
+		var err error
+		switch action {
+		case config.AlertNotify:
+			if !a.digest {
+				err = notify(ctx, analysis)
+			}
+		case config.AlertIssue:
+			err = a.openIssue(ctx, analysis)
+		case config.AlertPage:
+			err = a.page(ctx, analysis)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s alert: %w", action, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// alertMessage renders the notification message for an analysis, with
+// --notify-template when set
+func alertMessage(analysis *checker.Analysis) (string, error) {
+	tmpl := notifyTemplate
+	if tmpl == nil {
+		var err error
+		if tmpl, err = loadNotifyTemplate(notifyTemplatePath); err != nil {
+			return "", err
+		}
+	}
+	message, err := render.Notification(tmpl, analysis, renderOptions())
+	if err != nil {
+		return "", fmt.Errorf("failed to render notification: %w", err)
+	}
+	return message, nil
+}
+
+// openIssue opens an issue for the analysis in issue_repo, unless one for the
+// same repository and version is still open
+func (a *alerter) openIssue(ctx context.Context, analysis *checker.Analysis) error {
+	repoConfig, err := lookupRepository(a.file.Notifications.IssueRepo)
+	if err != nil {
+		return err
+	}
+	tracker, err := a.issues(repoConfig)
+	if err != nil {
+		return err
+	}
+
+	title := fmt.Sprintf("Update %s from %s", analysis.Repository, types.FormatVersion(analysis.ComparisonVersion))
+	if existing, err := tracker.FindOpenIssue(ctx, title, issueLabel); err != nil || existing != "" {
+		return err
+	}
+	body, err := alertMessage(analysis)
+	if err != nil {
+		return err
+	}
+	_, err = tracker.CreateIssue(ctx, client.Issue{Title: title, Body: body, Labels: []string{issueLabel}})
+	return err
+}
+
+// pageEvent is a PagerDuty Events API v2 trigger
+type pageEvent struct {
+	RoutingKey  string      `json:"routing_key"`
+	EventAction string      `json:"event_action"`
+	DedupKey    string      `json:"dedup_key"` // One incident per repository and version
+	Payload     pagePayload `json:"payload"`
+}
+
+type pagePayload struct {
+	Summary       string         `json:"summary"`
+	Source        string         `json:"source"`
+	Severity      string         `json:"severity"`
+	CustomDetails map[string]any `json:"custom_details,omitempty"`
+}
+
+// page triggers a PagerDuty incident for the analysis. Repeat triggers with
+// the same dedup key join the open incident rather than paging again.
+func (a *alerter) page(ctx context.Context, analysis *checker.Analysis) error {
+	n := a.file.Notifications
+	keyEnv := n.PageRoutingKeyEnv
+	if keyEnv == "" {
+		keyEnv = config.DefaultPageRoutingKeyEnv
+	}
+	routingKey := os.Getenv(keyEnv)
+	if routingKey == "" {
+		return fmt.Errorf("no PagerDuty routing key: set %s", keyEnv)
+	}
+	pageURL := n.PageURL
+	if pageURL == "" {
+		pageURL = config.DefaultPageURL
+	}
+
+	message, err := alertMessage(analysis)
+	if err != nil {
+		return err
+	}
+	status := analysis.Status()
+	event := pageEvent{
+		RoutingKey:  routingKey,
+		EventAction: "trigger",
+		DedupKey:    fmt.Sprintf("%s/%s@%s", issueLabel, analysis.Repository, analysis.ComparisonVersion),
+		Payload: pagePayload{
+			Summary:  fmt.Sprintf("%s %s is %s; latest is %s", analysis.Repository, types.FormatVersion(analysis.ComparisonVersion), status, types.FormatVersion(analysis.LatestVersion)),
+			Source:   analysis.Repository,
+			Severity: pageSeverities[status],
+			CustomDetails: map[string]any{
+				"message":        message,
+				"status":         status,
+				"version":        analysis.ComparisonVersion.String(),
+				"latest_version": analysis.LatestVersion.String(),
+			},
+		},
+	}
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, notifyTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, pageURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to page: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent())
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to page: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("failed to page: PagerDuty returned %s", resp.Status)
+	}
+	return nil
+}
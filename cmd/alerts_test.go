@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/nickromney-org/github-release-version-checker/internal/config"
+	"github.com/nickromney-org/github-release-version-checker/pkg/checker"
+	"github.com/nickromney-org/github-release-version-checker/pkg/client"
+)
+
+// fakeIssues records issues opened, with open titles already filed
+type fakeIssues struct {
+	repository string
+	open       map[string]bool
+	created    []client.Issue
+}
+
+func (f *fakeIssues) FindOpenIssue(ctx context.Context, title, label string) (string, error) {
+	if f.open[title] && label == issueLabel {
+		return "https://github.com/acme/platform/issues/1", nil
+	}
+	return "", nil
+}
+
+func (f *fakeIssues) CreateIssue(ctx context.Context, issue client.Issue) (string, error) {
+	f.created = append(f.created, issue)
+	return "https://github.com/acme/platform/issues/2", nil
+}
+
+// alertServer records what is posted to it, by kind
+type alertServer struct {
+	mu    sync.Mutex
+	posts map[string][]map[string]any
+}
+
+func newAlertServer(t *testing.T) (*alertServer, *httptest.Server) {
+	s := &alertServer{posts: make(map[string][]map[string]any)}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("invalid payload: %v", err)
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.posts[strings.Trim(r.URL.Path, "/")] = append(s.posts[strings.Trim(r.URL.Path, "/")], payload)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	t.Cleanup(server.Close)
+	return s, server
+}
+
+func TestAlerter(t *testing.T) {
+	analysis := func(repository string, expired bool) *checker.Analysis {
+		return &checker.Analysis{
+			Repository:        repository,
+			LatestVersion:     mustParseVersion("2.329.0"),
+			ComparisonVersion: mustParseVersion("2.327.1"),
+			IsExpired:         expired,
+			IsCritical:        !expired,
+			ReleasesBehind:    2,
+		}
+	}
+
+	tests := []struct {
+		name        string
+		analysis    *checker.Analysis
+		digest      bool
+		open        string // Title of an issue already open
+		wantNotify  int
+		wantPage    int
+		wantIssues  int
+		wantErrText string
+	}{
+		{name: "expired runner pages and files an issue", analysis: analysis("actions/runner", true), wantPage: 1, wantIssues: 1},
+		{name: "open issue is not filed again", analysis: analysis("actions/runner", true), open: "Update actions/runner from 2.327.1", wantPage: 1},
+		{name: "critical runner notifies", analysis: analysis("actions/runner", false), wantNotify: 1},
+		{name: "digest takes notifications", analysis: analysis("actions/runner", false), digest: true},
+		{name: "low-stakes tool ignores critical", analysis: analysis("acme/linter", false)},
+		{name: "low-stakes tool notifies when expired", analysis: analysis("acme/linter", true), wantNotify: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			posts, server := newAlertServer(t)
+			t.Setenv(config.DefaultPageRoutingKeyEnv, "routing-key")
+			tmpl, err := loadNotifyTemplate("")
+			if err != nil {
+				t.Fatal(err)
+			}
+			notifyWebhook, notifyTemplate = server.URL+"/notify", tmpl
+			t.Cleanup(func() { notifyWebhook, notifyTemplate = "", nil })
+
+			issues := &fakeIssues{open: map[string]bool{tt.open: true}}
+			a := &alerter{
+				file: &config.File{
+					Notifications: config.NotificationSettings{IssueRepo: "acme/platform", PageURL: server.URL + "/page"},
+					Repositories: []config.FileRepository{
+						{Repo: "runner", Alerts: config.AlertRules{"expired": {config.AlertPage, config.AlertIssue}}},
+						{Repo: "acme/linter", Alerts: config.AlertRules{"warning": {config.AlertIgnore}, "critical": {config.AlertIgnore}}},
+					},
+				},
+				digest: tt.digest,
+				issues: func(repoConfig *config.RepositoryConfig) (issueTracker, error) {
+					issues.repository = repoConfig.FullName()
+					return issues, nil
+				},
+			}
+
+			if err := a.alert(context.Background(), tt.analysis); err != nil {
+				t.Fatalf("alert() error = %v", err)
+			}
+			if got := len(posts.posts["notify"]); got != tt.wantNotify {
+				t.Errorf("notifications = %d, want %d", got, tt.wantNotify)
+			}
+			if got := len(posts.posts["page"]); got != tt.wantPage {
+				t.Errorf("pages = %d, want %d", got, tt.wantPage)
+			}
+			if got := len(issues.created); got != tt.wantIssues {
+				t.Errorf("issues = %d, want %d", got, tt.wantIssues)
+			}
+
+			if tt.wantPage > 0 {
+				event := posts.posts["page"][0]
+				payload, _ := event["payload"].(map[string]any)
+				if event["routing_key"] != "routing-key" || event["event_action"] != "trigger" ||
+					event["dedup_key"] != "release-version-checker/actions/runner@2.327.1" || payload["severity"] != "critical" {
+					t.Errorf("page event = %v", event)
+				}
+			}
+			if tt.wantIssues > 0 {
+				issue := issues.created[0]
+				if issues.repository != "acme/platform" || issue.Title != "Update actions/runner from 2.327.1" ||
+					!strings.Contains(issue.Body, "Expired") || issue.Labels[0] != issueLabel {
+					t.Errorf("issue in %s = %+v", issues.repository, issue)
+				}
+			}
+		})
+	}
+}
+
+func TestAlerter_PageWithoutRoutingKey(t *testing.T) {
+	t.Setenv("TEAM_ROUTING_KEY", "")
+	a := &alerter{file: &config.File{
+		Notifications: config.NotificationSettings{
+			Alerts:            config.AlertRules{"expired": {config.AlertPage}},
+			PageRoutingKeyEnv: "TEAM_ROUTING_KEY",
+		},
+	}}
+	err := a.alert(context.Background(), &checker.Analysis{
+		Repository:        "actions/runner",
+		LatestVersion:     mustParseVersion("2.329.0"),
+		ComparisonVersion: mustParseVersion("2.327.1"),
+		IsExpired:         true,
+	})
+	if err == nil || !strings.Contains(err.Error(), "page alert: no PagerDuty routing key: set TEAM_ROUTING_KEY") {
+		t.Errorf("alert() error = %v", err)
+	}
+}
@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/nickromney-org/github-release-version-checker/internal/config"
+	"github.com/nickromney-org/github-release-version-checker/pkg/checker"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	annotateFile   string
+	annotateFormat string
+	annotatePins   map[string]string
+	annotateToken  string
+	annotateDryRun bool
+)
+
+var annotateCmd = &cobra.Command{
+	Use:   "annotate --file FILE",
+	Short: "Comment each pinned version with its latest release and expiry",
+	Long: `Check each recognised version pin in FILE and add a trailing comment with the
+latest release and when the pinned version expires, such as
+"# latest 2.329.0, expires 14 Nov 2025". The pinned values are never changed,
+and comments from an earlier run are replaced, so the command can run on a schedule.
+
+A pin is recognised when its key names a predefined repository with an optional
+"version" suffix, such as RUNNER_VERSION, kubernetes_version or node. --pin maps
+any other key to a repository. The file format is read as for fix: from the
+extension, or --format.`,
+	Example: `  # Annotate the pins in a YAML file
+  github-release-version-checker annotate --file versions.yaml
+
+  # Preview the comments, naming a pin that is not recognised by its key
+  github-release-version-checker annotate --file versions.env --pin CLI_VERSION=pulumi/pulumi --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: runAnnotate,
+}
+
+func init() {
+	annotateCmd.Flags().StringVarP(&annotateFile, "file", "f", "", "file holding the pinned versions (required)")
+	annotateCmd.Flags().StringVar(&annotateFormat, "format", "", "file format: env, yaml or terraform (default: from the extension)")
+	annotateCmd.Flags().StringToStringVar(&annotatePins, "pin", nil, "repository a key pins, e.g. CLI_VERSION=pulumi/pulumi; repeatable")
+	annotateCmd.Flags().StringVarP(&annotateToken, "token", "t", "", "GitHub token (default: GH_TOKEN, GITHUB_TOKEN, then gh's stored login)")
+	annotateCmd.Flags().BoolVar(&annotateDryRun, "dry-run", false, "print the diff without writing the file")
+	_ = annotateCmd.MarkFlagRequired("file")
+	rootCmd.AddCommand(annotateCmd)
+}
+
+// namedPin is a version pinned under a key
+type namedPin struct {
+	Key string
+	pin
+}
+
+// annotationComment matches a comment added by an earlier annotate run
+var annotationComment = regexp.MustCompile(`\s*# latest \S+(?:, [^#]*)?$`)
+
+// Keys and values of env and YAML pins, as findPin matches them
+var (
+	envPinLine  = regexp.MustCompile(`^\s*(?:export\s+)?([A-Za-z_][\w.-]*)\s*=\s*["']?([^"'\s#]+)`)
+	yamlPinLine = regexp.MustCompile(`^\s*(?:-\s+)?["']?([A-Za-z_][\w.-]*)["']?\s*:\s*["']?([^"'\s#]+)`)
+	tfVariable  = regexp.MustCompile(`^\s*variable\s+"([^"]+)"\s*\{`)
+)
+
+func runAnnotate(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	w := cmd.OutOrStdout()
+
+	format := annotateFormat
+	if format == "" {
+		format = detectPinFormat(annotateFile)
+	}
+	switch format {
+	case pinFormatEnv, pinFormatYAML, pinFormatTerraform:
+	default:
+		return invalidInput(fmt.Errorf("invalid format %q: must be 'env', 'yaml' or 'terraform'", format))
+	}
+
+	data, err := os.ReadFile(annotateFile)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", annotateFile, err)
+	}
+	lines := strings.SplitAfter(string(data), "\n")
+
+	checkers := make(map[string]*checker.Checker)
+	tokens := make(map[string]string)
+	var fixes []pinFix
+	annotated := 0
+	for _, p := range findPins(lines, format) {
+		repoName := pinRepository(p.Key, annotatePins)
+		if repoName == "" {
+			continue
+		}
+		pinned := lines[p.Line][p.Start:p.End]
+		version, err := checker.ParseComparisonVersion(pinned, checker.NormaliseAll)
+		if err != nil {
+			continue
+		}
+		repoConfig, err := lookupRepository(repoName)
+		if err != nil {
+			return invalidInput(fmt.Errorf("%s: %w", p.Key, err))
+		}
+
+		versionChecker, ok := checkers[repoConfig.FullName()]
+		if !ok {
+			ghClient, resolved, err := newAPIHosts(nil, annotateToken).client(repoConfig)
+			if err != nil {
+				return err
+			}
+			versionChecker = newChecker(ghClient, repoConfig)
+			checkers[repoConfig.FullName()] = versionChecker
+			tokens[repoConfig.FullName()] = resolved.Value
+		}
+		analysis, err := analyseTraced(cmd.Context(), versionChecker, repoConfig.FullName(), version.String())
+		if err != nil {
+			return withToken(fmt.Errorf("%s: %w", p.Key, err), tokens[repoConfig.FullName()])
+		}
+
+		annotated++
+		updated := annotateLine(lines[p.Line], pinAnnotation(analysis))
+		if updated != lines[p.Line] {
+			fixes = append(fixes, pinFix{Line: p.Line, Old: lines[p.Line], New: updated})
+			lines[p.Line] = updated
+		}
+	}
+
+	if annotated == 0 {
+		return invalidInput(fmt.Errorf("%s: no recognised version pins; name them with --pin KEY=REPO", annotateFile))
+	}
+	if len(fixes) == 0 {
+		grey.Fprintf(w, "%s is already annotated\n", annotateFile)
+		return nil
+	}
+	for _, fix := range fixes {
+		writeFixDiff(w, annotateFile, fix)
+	}
+	if annotateDryRun {
+		return nil
+	}
+	if err := writeFileKeepMode(annotateFile, []byte(strings.Join(lines, ""))); err != nil {
+		return err
+	}
+	green.Fprintf(w, "✅ Annotated %d of %d pins in %s\n", len(fixes), annotated, annotateFile)
+	return nil
+}
+
+// findPins locates every version-like value pinned under a key, in file order
+func findPins(lines []string, format string) []namedPin {
+	var pins []namedPin
+	if format == pinFormatTerraform {
+		for _, line := range lines {
+			if m := tfVariable.FindStringSubmatch(line); m != nil {
+				if p, err := findTerraformPin(lines, m[1]); err == nil {
+					pins = append(pins, namedPin{Key: m[1], pin: p})
+				}
+			}
+		}
+		return pins
+	}
+
+	pattern := envPinLine
+	if format == pinFormatYAML {
+		pattern = yamlPinLine
+	}
+	for i, line := range lines {
+		if m := pattern.FindStringSubmatchIndex(line); m != nil {
+			pins = append(pins, namedPin{Key: line[m[2]:m[3]], pin: pin{Line: i, Start: m[4], End: m[5]}})
+		}
+	}
+	return pins
+}
+
+// pinRepository returns the repository key pins: one named with --pin, else a
+// predefined repository named by the key, or "" when the key is not recognised
+func pinRepository(key string, named map[string]string) string {
+	if repo, ok := named[key]; ok {
+		return repo
+	}
+	name := strings.ToLower(key)
+	for _, suffix := range []string{"_version", "-version", ".version", "version"} {
+		name = strings.TrimSuffix(name, suffix)
+	}
+	name = strings.NewReplacer("_", "-", ".", "-").Replace(name)
+	if _, err := config.GetPredefinedConfig(name); err != nil {
+		return ""
+	}
+	return name
+}
+
+// pinAnnotation summarises the analysis of a pinned version as a comment
+func pinAnnotation(analysis *checker.Analysis) string {
+	comment := fmt.Sprintf("# latest %s", types.FormatVersion(analysis.LatestVersion))
+	switch {
+	case analysis.ExpiryDate() != nil && analysis.IsExpired:
+		comment += ", expired " + formatDate(*analysis.ExpiryDate())
+	case analysis.ExpiryDate() != nil:
+		comment += ", expires " + formatDate(*analysis.ExpiryDate())
+	case analysis.MinorVersionsBehind == 1:
+		comment += ", 1 minor version behind"
+	case analysis.MinorVersionsBehind > 1:
+		comment += fmt.Sprintf(", %d minor versions behind", analysis.MinorVersionsBehind)
+	}
+	if analysis.Yanked != nil {
+		comment += ", yanked"
+	}
+	return comment
+}
+
+// annotateLine sets the line's trailing annotation, replacing one from an earlier
+// run and keeping the line ending
+func annotateLine(line, comment string) string {
+	content := strings.TrimRight(line, "\r\n")
+	ending := line[len(content):]
+	content = strings.TrimRight(annotationComment.ReplaceAllString(content, ""), " \t")
+	return content + "  " + comment + ending
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/nickromney-org/github-release-version-checker/pkg/checker"
+)
+
+func TestFindPins(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		content string
+		want    []string // key=value
+	}{
+		{"env", pinFormatEnv, "# versions\nexport RUNNER_VERSION=\"v2.327.1\"\nNODE_VERSION=20.10.0 # lts\n", []string{"RUNNER_VERSION=v2.327.1", "NODE_VERSION=20.10.0"}},
+		{"yaml", pinFormatYAML, "tools:\n  kubernetes: '1.31.4'\n  - node: v20.10.0\nname: app\n", []string{"kubernetes=1.31.4", "node=v20.10.0", "name=app"}},
+		{
+			name:    "terraform",
+			format:  pinFormatTerraform,
+			content: "variable \"k8s\" {\n  default = \"1.31.4\"\n}\n\nvariable \"region\" {\n  type = string\n}\n",
+			want:    []string{"k8s=1.31.4"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := strings.SplitAfter(tt.content, "\n")
+			var got []string
+			for _, p := range findPins(lines, tt.format) {
+				got = append(got, p.Key+"="+lines[p.Line][p.Start:p.End])
+			}
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("findPins() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPinRepository(t *testing.T) {
+	named := map[string]string{"CLI_VERSION": "pulumi/pulumi"}
+	tests := map[string]string{
+		"RUNNER_VERSION":         "runner",
+		"actions_runner_version": "actions-runner",
+		"kubernetes-version":     "kubernetes",
+		"k8sVersion":             "k8s",
+		"node":                   "node",
+		"CLI_VERSION":            "pulumi/pulumi",
+		"APP_VERSION":            "",
+		"version":                "",
+	}
+	for key, want := range tests {
+		if got := pinRepository(key, named); got != want {
+			t.Errorf("pinRepository(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestPinAnnotation(t *testing.T) {
+	released := time.Date(2025, 10, 15, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		analysis *checker.Analysis
+		want     string
+	}{
+		{
+			name:     "latest",
+			analysis: &checker.Analysis{LatestVersion: semver.MustParse("2.329.0"), MaxAgeDays: 30},
+			want:     "# latest 2.329.0",
+		},
+		{
+			name:     "expires",
+			analysis: &checker.Analysis{LatestVersion: semver.MustParse("2.329.0"), FirstNewerReleaseDate: &released, MaxAgeDays: 30},
+			want:     "# latest 2.329.0, expires 14 Nov 2025",
+		},
+		{
+			name:     "expired",
+			analysis: &checker.Analysis{LatestVersion: semver.MustParse("2.329.0"), FirstNewerReleaseDate: &released, MaxAgeDays: 30, IsExpired: true},
+			want:     "# latest 2.329.0, expired 14 Nov 2025",
+		},
+		{
+			name:     "versions policy",
+			analysis: &checker.Analysis{LatestVersion: semver.MustParse("1.34.1"), MinorVersionsBehind: 2},
+			want:     "# latest 1.34.1, 2 minor versions behind",
+		},
+		{
+			name:     "four components",
+			analysis: &checker.Analysis{LatestVersion: semver.MustParse("1.2.3+rev.4"), MaxAgeDays: 30},
+			want:     "# latest 1.2.3.4",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pinAnnotation(tt.analysis); got != tt.want {
+				t.Errorf("pinAnnotation() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnnotateLine(t *testing.T) {
+	comment := "# latest 2.329.0, expires 14 Nov 2025"
+	tests := []struct {
+		name string
+		line string
+		want string
+	}{
+		{"bare", "RUNNER_VERSION=2.327.1\n", "RUNNER_VERSION=2.327.1  " + comment + "\n"},
+		{"crlf", "runner: 2.327.1\r\n", "runner: 2.327.1  " + comment + "\r\n"},
+		{"no line ending", "runner: 2.327.1", "runner: 2.327.1  " + comment},
+		{"keeps other comments", "runner: 2.327.1 # pinned for CI\n", "runner: 2.327.1 # pinned for CI  " + comment + "\n"},
+		{"replaces earlier annotation", "runner: 2.327.1  # latest 2.328.0, expires 1 Oct 2025\n", "runner: 2.327.1  " + comment + "\n"},
+		{"replaces bare annotation", "runner: 2.327.1  # latest 2.327.1\n", "runner: 2.327.1  " + comment + "\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := annotateLine(tt.line, comment); got != tt.want {
+				t.Errorf("annotateLine() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
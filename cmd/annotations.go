@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nickromney-org/github-release-version-checker/pkg/checker"
+	"github.com/nickromney-org/github-release-version-checker/pkg/render"
+)
+
+// validAnnotationLevels lists the accepted GitHub Actions annotation levels
+var validAnnotationLevels = map[string]bool{
+	"notice":              true,
+	"warning":             true,
+	"error":               true,
+	render.AnnotationNone: true,
+}
+
+// ciAnnotationLevels is the mapping used by outputCI, resolved from flags in run
+var ciAnnotationLevels = render.DefaultAnnotationLevels
+
+// resolveAnnotationLevels applies --annotation-level overrides on top of the defaults
+func resolveAnnotationLevels(overrides map[string]string, disabled bool) (map[checker.Status]string, error) {
+	levels := make(map[checker.Status]string, len(render.DefaultAnnotationLevels))
+	for status, level := range render.DefaultAnnotationLevels {
+		levels[status] = level
+		if disabled {
+			levels[status] = render.AnnotationNone
+		}
+	}
+	if disabled {
+		return levels, nil
+	}
+
+	keys := make([]string, 0, len(overrides))
+	for key := range overrides {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		status := checker.Status(strings.ToLower(strings.TrimSpace(key)))
+		if _, ok := render.DefaultAnnotationLevels[status]; !ok {
+			return nil, fmt.Errorf("invalid annotation status %q: must be one of current, warning, critical, expired", key)
+		}
+
+		level := strings.ToLower(strings.TrimSpace(overrides[key]))
+		if !validAnnotationLevels[level] {
+			return nil, fmt.Errorf("invalid annotation level %q for %s: must be notice, warning, error or none", overrides[key], status)
+		}
+		levels[status] = level
+	}
+
+	return levels, nil
+}
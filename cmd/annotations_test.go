@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/nickromney-org/github-release-version-checker/pkg/checker"
+	"github.com/nickromney-org/github-release-version-checker/pkg/render"
+)
+
+func TestResolveAnnotationLevels(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides map[string]string
+		disabled  bool
+		want      map[checker.Status]string
+		wantErr   bool
+	}{
+		{
+			name: "defaults",
+			want: render.DefaultAnnotationLevels,
+		},
+		{
+			name:      "softened mapping",
+			overrides: map[string]string{"critical": "notice", "expired": "Warning"},
+			want: map[checker.Status]string{
+				checker.StatusCurrent:  "notice",
+				checker.StatusWarning:  "notice",
+				checker.StatusCritical: "notice",
+				checker.StatusExpired:  "warning",
+			},
+		},
+		{
+			name:      "suppress current",
+			overrides: map[string]string{"current": "none"},
+			want: map[checker.Status]string{
+				checker.StatusCurrent:  "none",
+				checker.StatusWarning:  "notice",
+				checker.StatusCritical: "warning",
+				checker.StatusExpired:  "error",
+			},
+		},
+		{
+			name:     "disabled",
+			disabled: true,
+			want: map[checker.Status]string{
+				checker.StatusCurrent:  "none",
+				checker.StatusWarning:  "none",
+				checker.StatusCritical: "none",
+				checker.StatusExpired:  "none",
+			},
+		},
+		{
+			name:      "invalid status",
+			overrides: map[string]string{"stale": "notice"},
+			wantErr:   true,
+		},
+		{
+			name:      "invalid level",
+			overrides: map[string]string{"expired": "fatal"},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveAnnotationLevels(tt.overrides, tt.disabled)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveAnnotationLevels() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			for status, level := range tt.want {
+				if got[status] != level {
+					t.Errorf("level for %s = %q, want %q", status, got[status], level)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,322 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/nickromney-org/github-release-version-checker/internal/config"
+	"github.com/nickromney-org/github-release-version-checker/pkg/checker"
+	"github.com/nickromney-org/github-release-version-checker/pkg/client"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Repositories checked at once in batch mode. GitHub's secondary rate limits
+// penalise bursts of concurrent requests, so the maximum is kept low.
+const (
+	defaultConcurrency = 4
+	maxConcurrency     = 16
+)
+
+var (
+	concurrency int  // --concurrency
+	keepGoing   bool // --keep-going
+)
+
+// batchJob is one repository to check in batch mode
+type batchJob struct {
+	Config  *config.RepositoryConfig
+	Version string
+}
+
+// batchResult is the outcome of checking one repository
+type batchResult struct {
+	Repository string // owner/repo
+	Analysis   *checker.Analysis
+	Err        error
+}
+
+// checkFunc checks one repository
+type checkFunc func(ctx context.Context, job batchJob) (*checker.Analysis, error)
+
+// batchRepositories returns the config file's repositories when it lists several
+// and --repo was not given, or nil for a single check
+func batchRepositories(flags *pflag.FlagSet, f *config.File) []config.FileRepository {
+	if f == nil || flags.Changed("repo") || len(f.Repositories) < 2 {
+		return nil
+	}
+	return f.Repositories
+}
+
+// runBatch checks every repository in the config file and writes the results
+func runBatch(cmd *cobra.Command, w io.Writer, entries []config.FileRepository, hosts *apiHosts) error {
+	if cmd.Flags().Changed("compare") {
+		return invalidInput(fmt.Errorf("--compare cannot be used when the config file lists several repositories; set each version in the file"))
+	}
+	if cmd.Flags().Changed("vulnerabilities") {
+		return invalidInput(fmt.Errorf("--vulnerabilities cannot be used when checking several repositories; set each repository's vulnerabilities in the file"))
+	}
+	if concurrency < 1 || concurrency > maxConcurrency {
+		return invalidInput(fmt.Errorf("concurrency must be between 1 and %d", maxConcurrency))
+	}
+	if err := validateDigest(digestFlag); err != nil {
+		return invalidInput(err)
+	}
+	if digestFlag != "" && notifyWebhook == "" {
+		return invalidInput(fmt.Errorf("--digest needs --notify-webhook"))
+	}
+	if digestStatePath == "" {
+		digestStatePath = filepath.Join(defaultCacheDir(), "digest.json")
+	}
+
+	jobs := make([]batchJob, 0, len(entries))
+	for i, entry := range entries {
+		repoConfig, err := entry.RepositoryConfig()
+		if err != nil {
+			return &configError{fmt.Errorf("repositories[%d]: %w", i, err)}
+		}
+		if err := applyPolicyFlags(cmd.Flags(), repoConfig); err != nil {
+			return invalidInput(err)
+		}
+		jobs = append(jobs, batchJob{Config: repoConfig, Version: entry.Version})
+	}
+
+	// All clients share one rate-limit budget, so they stop together when it runs out
+	budget := client.NewRateBudget()
+	logger := newTraceLogger(cmd.ErrOrStderr(), verbose)
+	if logger != nil {
+		logger.Info("batch check", "repositories", len(jobs), "concurrency", concurrency)
+	}
+
+	check := func(ctx context.Context, job batchJob) (*checker.Analysis, error) {
+		ghClient, versionChecker, resolved, err := newRepositoryChecker(hosts, job.Config)
+		if err != nil {
+			return nil, &configError{err}
+		}
+		token, tokenSourceName := resolved.Value, resolved.describe()
+		ghClient.Budget = budget
+		if logger != nil {
+			repoLogger := logger.With("repo", job.Config.FullName())
+			ghClient.Logger = repoLogger
+			versionChecker.SetLogger(repoLogger)
+			repoLogger.Info("token resolved", "host", job.Config.SourceHost(), "source", tokenSourceName)
+		}
+
+		if token != "" && !offline {
+			if err := ghClient.CheckAccess(ctx); err != nil {
+				if accessErr := tokenAccessError(err, tokenSourceName, job.Config.FullName()); accessErr != err {
+					return nil, accessErr
+				}
+			}
+		}
+
+		analysis, err := analyseTraced(ctx, versionChecker, job.Config.FullName(), job.Version)
+		if err != nil {
+			return nil, withToken(err, token)
+		}
+		analysis.Repository = job.Config.FullName()
+		analysis.Upstream = job.Config.Upstream
+		analysis.TokenSource = tokenSourceName
+		analysis.Vulnerabilities = job.Config.Vulnerabilities
+		return analysis, nil
+	}
+
+	results, err := checkConcurrently(cmd.Context(), jobs, concurrency, keepGoing, check)
+	if err != nil {
+		if interruptErr := interrupted(cmd.Context()); interruptErr != nil && cmd.Context().Err() != nil {
+			return interruptErr // Aborted by a second signal or the grace period
+		}
+		return err
+	}
+
+	summary := summariseBatch(results, aggregatePolicy, aggregateThreshold, strict)
+	switch {
+	case jsonOutput:
+		err = outputBatchJSON(w, results, summary)
+	case ciOutput:
+		err = outputBatchCI(w, results, summary)
+	default:
+		err = outputBatchTerminal(w, results, summary)
+	}
+	if err != nil {
+		return err
+	}
+	if digestFlag != "" {
+		if err := sendDigest(cmd.Context(), results, time.Now()); err != nil {
+			yellow.Fprintf(cmd.ErrOrStderr(), "⚠️  %v\n", err)
+		}
+	}
+	alerts := newAlerter(hosts, digestFlag != "")
+	for _, result := range results {
+		if result.Analysis == nil {
+			continue
+		}
+		if err := alerts.alert(cmd.Context(), result.Analysis); err != nil {
+			yellow.Fprintf(cmd.ErrOrStderr(), "⚠️  %s: %v\n", result.Repository, err)
+		}
+	}
+	for _, result := range results {
+		if errors.Is(result.Err, errNotChecked) {
+			return interrupted(cmd.Context())
+		}
+	}
+	if summary.Failed == 0 {
+		if err := statusExit(checker.Status(summary.Overall)); err != nil {
+			return err
+		}
+	}
+	if err := summary.err(); err != nil {
+		return err
+	}
+	return degradedExit(summary.Degraded > 0)
+}
+
+// checkConcurrently runs check for each job on up to n workers, returning the
+// results in job order. Unless keepGoing is set, the first failure cancels the
+// remaining checks and is returned; otherwise failures are left in the results.
+// Once shutdown begins, checks running finish and the rest are left unchecked.
+func checkConcurrently(ctx context.Context, jobs []batchJob, n int, keepGoing bool, check checkFunc) ([]batchResult, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]batchResult, len(jobs))
+	indexes := make(chan int)
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	for worker := 0; worker < min(n, len(jobs)); worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				job := jobs[i]
+				analysis, err := check(ctx, job)
+				results[i] = batchResult{Repository: job.Config.FullName(), Analysis: analysis, Err: err}
+				if err != nil && !keepGoing {
+					errOnce.Do(func() {
+						firstErr = fmt.Errorf("%s: %w", job.Config.FullName(), err)
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+
+	stop := stopping(ctx)
+	next, stopped := 0, false
+	for next < len(jobs) && !stopped && ctx.Err() == nil {
+		// Checked first, as a waiting worker would otherwise win the select at random
+		select {
+		case <-stop:
+			stopped = true
+			continue
+		default:
+		}
+		select {
+		case indexes <- next:
+			next++
+		case <-ctx.Done():
+		case <-stop:
+			stopped = true
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	// Leave the rest unchecked, so the results so far are still reported
+	if stopped {
+		for i := next; i < len(jobs); i++ {
+			results[i] = batchResult{Repository: jobs[i].Config.FullName(), Err: errNotChecked}
+		}
+	}
+
+	if firstErr == nil && ctx.Err() != nil {
+		firstErr = ctx.Err() // The caller's context was cancelled
+	}
+	return results, firstErr
+}
+
+// outputBatchJSON writes {"results": [...], "summary": {...}}, with failed checks
+// as {"repository", "error": {...}, "success": false} results. --fields applies to each
+// result and --query to the whole document.
+func outputBatchJSON(w io.Writer, results []batchResult, summary batchSummary) error {
+	analyses := make([]json.RawMessage, 0, len(results))
+	for _, r := range results {
+		if r.Err != nil {
+			data, err := json.Marshal(struct {
+				Repository string    `json:"repository"`
+				Error      jsonError `json:"error"`
+				Success    bool      `json:"success"`
+			}{r.Repository, classifyError(r.Err), false})
+			if err != nil {
+				return err
+			}
+			analyses = append(analyses, data)
+			continue
+		}
+
+		data, err := r.Analysis.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		if len(fieldsFlag) > 0 {
+			if data, err = selectFields(data, fieldsFlag); err != nil {
+				return err
+			}
+		}
+		analyses = append(analyses, data)
+	}
+
+	data, err := json.MarshalIndent(struct {
+		Results []json.RawMessage `json:"results"`
+		Summary batchSummary      `json:"summary"`
+	}{analyses, summary}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeJSONDocument(w, data)
+}
+
+// outputBatchTerminal writes each repository's result under a heading, then the summary
+func outputBatchTerminal(w io.Writer, results []batchResult, summary batchSummary) error {
+	for i, r := range results {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		cyan.Fprintf(w, "📦 %s\n", r.Repository)
+		if r.Err != nil {
+			red.Fprintf(w, "❌ Error: %v\n", r.Err)
+			continue
+		}
+		if err := outputTerminal(w, r.Analysis); err != nil {
+			return err
+		}
+	}
+	printBatchSummaryTerminal(w, summary)
+	return nil
+}
+
+// outputBatchCI writes each repository's CI output in turn, then the summary
+func outputBatchCI(w io.Writer, results []batchResult, summary batchSummary) error {
+	for _, r := range results {
+		fmt.Fprintf(w, "📦 %s\n", r.Repository)
+		if r.Err != nil {
+			fmt.Fprintf(w, "::error title=%s::%s\n", r.Repository, r.Err)
+			continue
+		}
+		if err := outputCI(w, r.Analysis); err != nil {
+			return err
+		}
+	}
+	printBatchSummaryCI(w, summary)
+	return nil
+}
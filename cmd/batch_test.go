@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nickromney-org/github-release-version-checker/internal/config"
+	"github.com/nickromney-org/github-release-version-checker/pkg/checker"
+	"github.com/spf13/pflag"
+)
+
+// batchJobs returns a job per repository name
+func batchJobs(names ...string) []batchJob {
+	jobs := make([]batchJob, len(names))
+	for i, name := range names {
+		jobs[i] = batchJob{Config: &config.RepositoryConfig{Owner: "acme", Repo: name}}
+	}
+	return jobs
+}
+
+func TestCheckConcurrentlyBounded(t *testing.T) {
+	jobs := batchJobs("a", "b", "c", "d", "e", "f", "g", "h")
+
+	var inFlight, peak atomic.Int32
+	check := func(ctx context.Context, job batchJob) (*checker.Analysis, error) {
+		n := inFlight.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		inFlight.Add(-1)
+		return &checker.Analysis{Repository: job.Config.FullName()}, nil
+	}
+
+	results, err := checkConcurrently(context.Background(), jobs, 3, false, check)
+	if err != nil {
+		t.Fatalf("checkConcurrently() error = %v", err)
+	}
+	if p := peak.Load(); p > 3 || p < 2 {
+		t.Errorf("peak concurrency = %d, want 2-3", p)
+	}
+	for i, r := range results {
+		if want := jobs[i].Config.FullName(); r.Repository != want || r.Analysis.Repository != want {
+			t.Errorf("results[%d] = %s, want %s (job order)", i, r.Repository, want)
+		}
+	}
+}
+
+func TestCheckConcurrentlyStopsOnError(t *testing.T) {
+	jobs := batchJobs("bad", "b", "c", "d", "e", "f")
+	errMissing := errors.New("version 9.9.9 does not exist")
+
+	var checked atomic.Int32
+	check := func(ctx context.Context, job batchJob) (*checker.Analysis, error) {
+		checked.Add(1)
+		if job.Config.Repo == "bad" {
+			return nil, errMissing
+		}
+		return &checker.Analysis{}, nil
+	}
+
+	_, err := checkConcurrently(context.Background(), jobs, 1, false, check)
+	if !errors.Is(err, errMissing) || err.Error() != "acme/bad: version 9.9.9 does not exist" {
+		t.Errorf("checkConcurrently() error = %v, want the failing repository's error", err)
+	}
+	if n := checked.Load(); n > 2 {
+		t.Errorf("%d repositories checked after the failure, want the rest skipped", n)
+	}
+}
+
+func TestCheckConcurrentlyKeepGoing(t *testing.T) {
+	jobs := batchJobs("a", "bad", "c")
+	check := func(ctx context.Context, job batchJob) (*checker.Analysis, error) {
+		if job.Config.Repo == "bad" {
+			return nil, errors.New("repository not found")
+		}
+		return &checker.Analysis{}, nil
+	}
+
+	results, err := checkConcurrently(context.Background(), jobs, 2, true, check)
+	if err != nil {
+		t.Fatalf("checkConcurrently() error = %v, want failures left in the results", err)
+	}
+	if results[0].Err != nil || results[1].Err == nil || results[2].Err != nil || results[2].Analysis == nil {
+		t.Errorf("results = %+v, want only acme/bad to fail", results)
+	}
+}
+
+func TestBatchRepositories(t *testing.T) {
+	two := &config.File{Repositories: []config.FileRepository{{Repo: "runner"}, {Repo: "k8s"}}}
+	one := &config.File{Repositories: []config.FileRepository{{Repo: "runner"}}}
+
+	tests := []struct {
+		name string
+		file *config.File
+		args []string
+		want int
+	}{
+		{name: "no config file", file: nil, want: 0},
+		{name: "single repository", file: one, want: 0},
+		{name: "several repositories", file: two, want: 2},
+		{name: "--repo overrides the file", file: two, args: []string{"--repo", "node"}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			flags.String("repo", "", "")
+			if err := flags.Parse(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			if got := batchRepositories(flags, tt.file); len(got) != tt.want {
+				t.Errorf("batchRepositories() = %d repositories, want %d", len(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestOutputBatchJSON(t *testing.T) {
+	results := []batchResult{
+		{Repository: "actions/runner", Analysis: &checker.Analysis{LatestVersion: mustParseVersion("2.329.0"), Repository: "actions/runner"}},
+		{Repository: "kubernetes/kubernetes", Analysis: &checker.Analysis{LatestVersion: mustParseVersion("1.34.1"), Repository: "kubernetes/kubernetes"}},
+		{Repository: "acme/missing", Err: errors.New("repository not found")},
+	}
+
+	var buf bytes.Buffer
+	if err := outputBatchJSON(&buf, results, summariseBatch(results, aggregateAnyExpired, 0, false)); err != nil {
+		t.Fatalf("outputBatchJSON() error = %v", err)
+	}
+
+	var got struct {
+		Results []struct {
+			Repository    string     `json:"repository"`
+			LatestVersion string     `json:"latest_version"`
+			Error         *jsonError `json:"error"`
+			Success       *bool      `json:"success"`
+		} `json:"results"`
+		Summary batchSummary `json:"summary"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if len(got.Results) != 3 || got.Results[1].Repository != "kubernetes/kubernetes" || got.Results[1].LatestVersion != "1.34.1" {
+		t.Errorf("results = %+v", got.Results)
+	}
+	if r := got.Results[2]; r.Repository != "acme/missing" || r.Error == nil || r.Error.Message != "repository not found" || r.Success == nil || *r.Success {
+		t.Errorf("failed result = %+v, want an error entry", r)
+	}
+	if got.Summary.Total != 3 || got.Summary.Failed != 1 || got.Summary.Passed {
+		t.Errorf("summary = %+v", got.Summary)
+	}
+}
+
+func TestCheckConcurrentlyShutdown(t *testing.T) {
+	jobs := batchJobs("a", "b", "c", "d")
+	signals := make(chan os.Signal, 1)
+	ctx, cancel := withShutdown(context.Background(), signals, time.Minute, io.Discard)
+	defer cancel()
+
+	check := func(ctx context.Context, job batchJob) (*checker.Analysis, error) {
+		if job.Config.Repo == "b" {
+			// Shutdown begins mid-check, which still finishes
+			signals <- os.Interrupt
+			<-stopping(ctx)
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+		}
+		return &checker.Analysis{}, nil
+	}
+
+	results, err := checkConcurrently(ctx, jobs, 1, false, check)
+	if err != nil {
+		t.Fatalf("checkConcurrently() error = %v", err)
+	}
+	for i, want := range []error{nil, nil, errNotChecked, errNotChecked} {
+		if results[i].Err != want || results[i].Repository != jobs[i].Config.FullName() {
+			t.Errorf("results[%d] = %s: %v, want %v", i, results[i].Repository, results[i].Err, want)
+		}
+	}
+	if err := interrupted(ctx); ExitCode(err) != 130 {
+		t.Errorf("interrupted() = %v (exit %d), want exit 130", err, ExitCode(err))
+	}
+}
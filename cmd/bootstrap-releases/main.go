@@ -2,31 +2,23 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
+	"github.com/nickromney-org/github-release-version-checker/internal/cache"
 	"github.com/nickromney-org/github-release-version-checker/internal/config"
 	"github.com/nickromney-org/github-release-version-checker/pkg/client"
 )
 
-type ReleaseJSON struct {
-	Version     string    `json:"version"`
-	PublishedAt time.Time `json:"published_at"`
-	URL         string    `json:"url"`
-}
-
-type CacheFile struct {
-	GeneratedAt time.Time     `json:"generated_at"`
-	Releases    []ReleaseJSON `json:"releases"`
-}
-
 func main() {
 	token := flag.String("token", os.Getenv("GITHUB_TOKEN"), "GitHub token")
 	output := flag.String("output", "internal/data/releases.json", "Output file")
 	repo := flag.String("repo", "actions/runner", "Repository to fetch (e.g., 'actions/runner', 'kubernetes', 'pulumi/pulumi')")
+	dryRun := flag.Bool("dry-run", false, "Fetch and report what would be written without writing")
+	includePrereleases := flag.Bool("include-prereleases", false, "Keep prereleases, marked with is_prerelease")
 	flag.Parse()
 
 	// Parse repository
@@ -38,6 +30,7 @@ func main() {
 
 	// Create GitHub client
 	ghClient := client.NewClient(*token, repoConfig.Owner, repoConfig.Repo)
+	ghClient.IncludePrereleases = *includePrereleases
 	ctx := context.Background()
 
 	fmt.Printf("Fetching all releases from %s/%s via GitHub API...\n", repoConfig.Owner, repoConfig.Repo)
@@ -49,34 +42,33 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Convert to JSON-friendly format
-	jsonReleases := make([]ReleaseJSON, len(releases))
-	for i, r := range releases {
-		jsonReleases[i] = ReleaseJSON{
-			Version:     r.Version.String(),
-			PublishedAt: r.PublishedAt,
-			URL:         r.URL,
-		}
-	}
+	repository := repoConfig.FullName()
+	fetch := &cache.FetchMetadata{APIHost: ghClient.APIHost(), Prereleases: *includePrereleases, Pages: ghClient.Pages(), Truncated: ghClient.Truncated()}
 
-	// Build cache file
-	cache := CacheFile{
-		GeneratedAt: time.Now().UTC(),
-		Releases:    jsonReleases,
-	}
-
-	// Write to file
-	file, err := os.Create(*output)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error creating file: %v\n", err)
-		os.Exit(1)
+	if *dryRun {
+		preview, err := cache.PreviewFile(*output, repository, releases, fetch, time.Now())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("🔍 Would write %d releases from %d pages to %s (%d bytes)\n", preview.Releases, fetch.Pages, *output, preview.Size)
+		switch {
+		case !preview.Exists:
+			fmt.Println("   new file")
+		case preview.Unchanged:
+			fmt.Println("   unchanged")
+		default:
+			versions := make([]string, len(preview.New))
+			for i, r := range preview.New {
+				versions[i] = "v" + r.Version.String()
+			}
+			fmt.Printf("   %d new: %s; %d dropped\n", len(preview.New), strings.Join(versions, ", "), preview.Dropped)
+		}
+		return
 	}
-	defer file.Close()
 
-	encoder := json.NewEncoder(file)
-	encoder.SetIndent("", "  ")
-	if err := encoder.Encode(cache); err != nil {
-		fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+	if err := cache.WriteFile(*output, repository, releases, fetch, time.Now()); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/nickromney-org/github-release-version-checker/internal/cache"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	bundleOutput    string
+	bundlePublicKey string
+)
+
+var bundleCmd = &cobra.Command{
+	Use:   "bundle",
+	Short: "Move release lists to machines without network access",
+	Long: `Export the warmed release lists from a connected machine as one file, and import
+it on an air-gapped network, where checks then run with --offline.`,
+}
+
+var bundleExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Write the warmed release lists to a bundle",
+	Long: `Write every release list in the cache directory (see cache warm), with any
+signatures, to a gzipped tarball. The bundle also holds registry.json, indexing the
+repositories it contains, and the JSON Schema of the list format.`,
+	Example: `  github-release-version-checker cache warm --all --sign-key cache.key
+  github-release-version-checker bundle export -o release-bundle.tar.gz`,
+	Args: cobra.NoArgs,
+	RunE: runBundleExport,
+}
+
+var bundleImportCmd = &cobra.Command{
+	Use:   "import FILE",
+	Short: "Load a bundle's release lists into the cache directory",
+	Long: `Load the release lists from a bundle written by bundle export into the cache
+directory, replacing any kept for the same repositories. Every list is validated,
+and with --public-key its signature checked, before any is written, so a bad bundle
+changes nothing. Use - to read the bundle from standard input.`,
+	Example: `  github-release-version-checker bundle import --public-key cache.pub release-bundle.tar.gz
+  github-release-version-checker --offline --repo runner -c 2.328.0`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBundleImport,
+}
+
+func init() {
+	bundleExportCmd.Flags().StringVarP(&bundleOutput, "output", "o", "release-bundle.tar.gz", "bundle file to write, or - for standard output")
+	bundleImportCmd.Flags().StringVar(&bundlePublicKey, "public-key", "", "minisign public key every list must be signed with")
+	bundleCmd.AddCommand(bundleExportCmd, bundleImportCmd)
+	rootCmd.AddCommand(bundleCmd)
+}
+
+func runBundleExport(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	// Build the bundle in memory so a failure leaves no partial file behind
+	var buf bytes.Buffer
+	registry, err := cache.WriteBundle(&buf, defaultCacheDir(), appVersion, time.Now())
+	if err != nil {
+		return err
+	}
+
+	// Report on stderr when the bundle itself goes to stdout
+	w := cmd.OutOrStdout()
+	if bundleOutput == "-" {
+		w = cmd.ErrOrStderr()
+		if _, err := cmd.OutOrStdout().Write(buf.Bytes()); err != nil {
+			return err
+		}
+	} else if err := os.WriteFile(bundleOutput, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+
+	green.Fprintf(w, "✅ Wrote %s: %d release list%s (%s)\n", bundleOutput, len(registry.Repositories), types.PluralSuffix(len(registry.Repositories)), formatSize(buf.Len()))
+	printBundleEntries(w, registry)
+	return nil
+}
+
+func runBundleImport(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	w := cmd.OutOrStdout()
+
+	var key *cache.PublicKey
+	if bundlePublicKey != "" {
+		var err error
+		if key, err = cache.LoadPublicKey(bundlePublicKey); err != nil {
+			return invalidInput(err)
+		}
+	}
+
+	var r io.Reader = cmd.InOrStdin()
+	if args[0] != "-" {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return invalidInput(fmt.Errorf("failed to open bundle: %w", err))
+		}
+		defer f.Close()
+		r = f
+	}
+
+	dir := defaultCacheDir()
+	registry, err := cache.ReadBundle(r, dir, key)
+	if err != nil {
+		return err
+	}
+	green.Fprintf(w, "✅ Imported %d release list%s into %s\n", len(registry.Repositories), types.PluralSuffix(len(registry.Repositories)), dir)
+	printBundleEntries(w, registry)
+	if !registry.CreatedAt.IsZero() {
+		grey.Fprintf(w, "   Exported %s", formatDate(registry.CreatedAt))
+		if registry.ToolVersion != "" {
+			grey.Fprintf(w, " by version %s", registry.ToolVersion)
+		}
+		fmt.Fprintln(w)
+	}
+	return nil
+}
+
+// printBundleEntries lists a bundle's repositories
+func printBundleEntries(w io.Writer, registry *cache.BundleRegistry) {
+	for _, entry := range registry.Repositories {
+		signed := ""
+		if entry.Signed {
+			signed = ", signed"
+		}
+		fmt.Fprintf(w, "   • %s: %d release%s (generated %s%s)\n", entry.Repository, entry.Releases, types.PluralSuffix(entry.Releases), formatDate(entry.GeneratedAt), signed)
+	}
+}
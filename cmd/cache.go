@@ -0,0 +1,451 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nickromney-org/github-release-version-checker/internal/cache"
+	"github.com/nickromney-org/github-release-version-checker/internal/config"
+	"github.com/nickromney-org/github-release-version-checker/pkg/checker"
+	"github.com/nickromney-org/github-release-version-checker/pkg/client"
+	"github.com/nickromney-org/github-release-version-checker/pkg/releaseset"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	warmAll         bool
+	warmToken       string
+	warmConcurrency int
+	warmSignKey     string
+	warmRetention   cache.Retention
+	warmDryRun      bool
+	warmIncremental bool
+	verifyPublicKey string
+
+	cachePublicKeyPath string
+	cachePublicKey     *cache.PublicKey // Resolved from --cache-public-key
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Inspect and maintain release cache files",
+}
+
+var cacheValidateCmd = &cobra.Command{
+	Use:   "validate FILE.json",
+	Short: "Validate a release cache file",
+	Long: `Validate a release cache file before using it with --cache.
+
+Checks that the file matches the cache schema, every version is valid semver,
+release dates are consistent, and no version appears twice.`,
+	Example: `  github-release-version-checker cache validate my-cache.json`,
+	Args:    cobra.ExactArgs(1),
+	RunE:    runCacheValidate,
+}
+
+var cacheWarmCmd = &cobra.Command{
+	Use:   "warm [REPO...]",
+	Short: "Fetch and keep the release lists of repositories",
+	Long: `Fetch every release of each repository and keep it in the cache directory, so
+later checks of those repositories only fetch the most recent releases. Run it
+nightly from cron or a scheduled workflow.
+
+--all warms every repository in the config file. Repositories are fetched
+concurrently and share rate-limit accounting, so once the limit is exhausted the
+rest fail immediately.`,
+	Example: `  github-release-version-checker cache warm --all
+  github-release-version-checker cache warm runner k8s hashicorp/terraform`,
+	RunE: runCacheWarm,
+}
+
+var cacheVerifyCmd = &cobra.Command{
+	Use:   "verify FILE.json...",
+	Short: "Verify the signatures of release cache files",
+	Long: `Verify release cache files against their minisign signatures (FILE.json.minisig),
+for example after downloading them to a runner.
+
+Signatures are minisign's legacy Ed25519 format, made by cache warm --sign-key or
+minisign -S -l.`,
+	Example: `  github-release-version-checker cache verify --public-key cache.pub releases/actions/runner.json`,
+	Args:    cobra.MinimumNArgs(1),
+	RunE:    runCacheVerify,
+}
+
+func init() {
+	cacheWarmCmd.Flags().BoolVar(&warmAll, "all", false, "warm every repository in the config file")
+	cacheWarmCmd.Flags().StringVar(&configFilePath, "config", config.DefaultFileName, "config file listing the repositories for --all, and their hosts")
+	cacheWarmCmd.Flags().StringVarP(&warmToken, "token", "t", "", "GitHub token (default: GH_TOKEN, GITHUB_TOKEN, then gh's stored login)")
+	cacheWarmCmd.Flags().IntVar(&warmConcurrency, "concurrency", defaultConcurrency, "repositories to fetch at once")
+	cacheWarmCmd.Flags().IntVar(&warmRetention.Releases, "keep-releases", 0, "keep only the N most recent releases of each repository (0 keeps all)")
+	cacheWarmCmd.Flags().IntVar(&warmRetention.Years, "keep-years", 0, "keep only releases published in the last N years (0 keeps all)")
+	cacheWarmCmd.Flags().BoolVar(&warmDryRun, "dry-run", false, "fetch and report what would be written (new releases, file size) without writing")
+	cacheWarmCmd.Flags().BoolVar(&warmIncremental, "incremental", false, "fetch only releases published since the newest one kept, merging them into the existing list")
+	cacheWarmCmd.Flags().StringVar(&warmSignKey, "sign-key", "", "unencrypted minisign secret key (minisign -G -W) to sign each file with")
+	cacheVerifyCmd.Flags().StringVar(&verifyPublicKey, "public-key", "", "minisign public key the files were signed with")
+	_ = cacheVerifyCmd.MarkFlagRequired("public-key")
+
+	cacheCmd.AddCommand(cacheValidateCmd)
+	cacheCmd.AddCommand(cacheWarmCmd)
+	cacheCmd.AddCommand(cacheVerifyCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
+
+func runCacheValidate(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	w := cmd.OutOrStdout()
+
+	result, err := cache.ValidateFile(args[0])
+	if err != nil {
+		return err
+	}
+
+	if result.Valid() {
+		green.Fprintf(w, "✅ %s is valid (%d releases)\n", args[0], result.Releases)
+		return nil
+	}
+
+	red.Fprintf(w, "❌ %s has %d problem%s:\n", args[0], len(result.Issues), types.PluralSuffix(len(result.Issues)))
+	for _, issue := range result.Issues {
+		fmt.Fprintf(w, "  • %s\n", issue)
+	}
+
+	return fmt.Errorf("cache validation failed")
+}
+
+// warmRepositories resolves the repositories to warm, by the repository whose
+// releases are fetched, without duplicates
+func warmRepositories(f *config.File, args []string) ([]string, error) {
+	var names []string
+	if warmAll {
+		if f == nil {
+			return nil, invalidInput(fmt.Errorf("--all needs a config file listing repositories"))
+		}
+		for i, entry := range f.Repositories {
+			repoConfig, err := entry.RepositoryConfig()
+			if err != nil {
+				return nil, &configError{fmt.Errorf("repositories[%d]: %w", i, err)}
+			}
+			names = append(names, repoConfig.SourceName())
+		}
+	}
+	for _, arg := range args {
+		repoConfig, err := lookupRepository(arg)
+		if err != nil {
+			return nil, invalidInput(err)
+		}
+		names = append(names, repoConfig.SourceName())
+	}
+	if len(names) == 0 {
+		return nil, invalidInput(fmt.Errorf("name repositories to warm, or use --all"))
+	}
+
+	var unique []string
+	for _, name := range names {
+		if !containsString(unique, strings.ToLower(name)) {
+			unique = append(unique, strings.ToLower(name))
+		}
+	}
+	return unique, nil
+}
+
+func runCacheWarm(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	w := cmd.OutOrStdout()
+	if warmConcurrency < 1 || warmConcurrency > maxConcurrency {
+		return invalidInput(fmt.Errorf("concurrency must be between 1 and %d", maxConcurrency))
+	}
+	if err := warmRetention.Validate(); err != nil {
+		return invalidInput(err)
+	}
+	// The config file lists repositories for --all and the hosts of any repository
+	f, err := loadConfigFile(cmd.Flags())
+	if err != nil {
+		return &configError{err}
+	}
+	repositories, err := warmRepositories(f, args)
+	if err != nil {
+		return err
+	}
+	var key *cache.SecretKey
+	if warmSignKey != "" {
+		if key, err = cache.LoadSecretKey(warmSignKey); err != nil {
+			return invalidInput(err)
+		}
+	}
+
+	// All clients share one rate-limit budget, so they stop together when it runs out
+	hosts := newAPIHosts(f, warmToken)
+	budget := client.NewRateBudget()
+	opts := warmOptions{retention: warmRetention, key: key, dryRun: warmDryRun, incremental: warmIncremental}
+	results := warmCaches(cmd.Context(), repositories, defaultCacheDir(), warmConcurrency, opts, func(repository string) (releaseLister, error) {
+		repoConfig, err := config.ParseRepositoryString(repository)
+		if err != nil {
+			return nil, err
+		}
+		ghClient, _, err := hosts.client(repoConfig)
+		if err != nil {
+			return nil, err
+		}
+		ghClient.Budget = budget
+		return ghClient, nil
+	})
+
+	failed := 0
+	for i, result := range results {
+		switch {
+		case result.err != nil:
+			failed++
+			red.Fprintf(w, "❌ %s: %v\n", repositories[i], withToken(result.err, hosts.repositoryToken(repositories[i]).Value))
+		case result.preview != nil:
+			printPreview(w, repositories[i], result.path, result.fetch, result.preview)
+		default:
+			green.Fprintf(w, "✅ %s\n", repositories[i])
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d repositories could not be warmed", failed, len(repositories))
+	}
+	return nil
+}
+
+// printPreview reports what a dry run would have written for repository
+func printPreview(w io.Writer, repository, path string, fetch *cache.FetchMetadata, preview *cache.Preview) {
+	fetched := ""
+	if fetch != nil {
+		fetched = fmt.Sprintf(" from %d page%s", fetch.Pages, types.PluralSuffix(fetch.Pages))
+	}
+	cyan.Fprintf(w, "🔍 %s: %d release%s%s → %s (%s)\n", repository, preview.Releases, types.PluralSuffix(preview.Releases), fetched, path, formatSize(preview.Size))
+
+	switch {
+	case !preview.Exists:
+		fmt.Fprintf(w, "   new file\n")
+	case preview.Unchanged:
+		fmt.Fprintf(w, "   unchanged\n")
+	default:
+		versions := make([]string, 0, len(preview.New))
+		for _, r := range preview.New {
+			versions = append(versions, "v"+r.Version.String())
+		}
+		if len(versions) > 10 {
+			versions = append(versions[:10], fmt.Sprintf("and %d more", len(preview.New)-10))
+		}
+		if len(preview.New) > 0 {
+			fmt.Fprintf(w, "   %d new: %s\n", len(preview.New), strings.Join(versions, ", "))
+		}
+		if preview.Dropped > 0 {
+			fmt.Fprintf(w, "   %d dropped\n", preview.Dropped)
+		}
+	}
+}
+
+// formatSize returns a byte count for people, e.g. "48.2 KB"
+func formatSize(bytes int) string {
+	switch {
+	case bytes < 1024:
+		return fmt.Sprintf("%d bytes", bytes)
+	case bytes < 1024*1024:
+		return fmt.Sprintf("%.1f KB", float64(bytes)/1024)
+	default:
+		return fmt.Sprintf("%.1f MB", float64(bytes)/(1024*1024))
+	}
+}
+
+// releaseLister fetches every release of a repository; *client.Client implements it
+type releaseLister interface {
+	GetAllReleases(ctx context.Context) ([]types.Release, error)
+}
+
+// fetchDescriber reports how the last fetch went; *client.Client implements it
+type fetchDescriber interface {
+	APIHost() string
+	Pages() int
+	Truncated() bool
+}
+
+// fetchMetadata describes the fetch lister just made, or returns nil if it cannot
+func fetchMetadata(lister releaseLister) *cache.FetchMetadata {
+	describer, ok := lister.(fetchDescriber)
+	if !ok {
+		return nil
+	}
+	return &cache.FetchMetadata{APIHost: describer.APIHost(), Pages: describer.Pages(), Truncated: describer.Truncated()}
+}
+
+// fetchParameters returns how a client fetches releases, to compare with how a
+// warmed list was fetched; the host is left empty if unknown
+func fetchParameters(ghClient any) cache.FetchMetadata {
+	var params cache.FetchMetadata
+	if describer, ok := ghClient.(fetchDescriber); ok {
+		params.APIHost = describer.APIHost()
+	}
+	if c, ok := ghClient.(*client.Client); ok {
+		params.Prereleases = c.IncludePrereleases
+	}
+	return params
+}
+
+// warmOptions control how warmCaches saves releases
+type warmOptions struct {
+	retention cache.Retention
+	key       *cache.SecretKey // Signs each file, if set
+	dryRun    bool             // Previews each file instead of writing it
+
+	// Fetches only releases newer than an existing list's, if the lister can
+	incremental bool
+}
+
+// warmResult is the outcome of warming one repository
+type warmResult struct {
+	path    string
+	fetch   *cache.FetchMetadata
+	preview *cache.Preview // Set for dry runs
+	err     error
+}
+
+// warmCaches fetches each repository's releases on up to n workers and writes
+// those opts.retention keeps under dir, returning the outcome per repository
+func warmCaches(ctx context.Context, repositories []string, dir string, n int, opts warmOptions, newLister func(repository string) (releaseLister, error)) []warmResult {
+	results := make([]warmResult, len(repositories))
+	sem := make(chan struct{}, n)
+	var wg sync.WaitGroup
+	for i, repository := range repositories {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, repository string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			lister, err := newLister(repository)
+			if err != nil {
+				results[i].err = err
+				return
+			}
+			result := warmResult{path: cache.WarmPath(dir, repository)}
+			releases, fetch, err := warmFetch(ctx, lister, result.path, repository, opts)
+			if err != nil {
+				results[i].err = err
+				return
+			}
+			result.fetch = fetch
+			releases = opts.retention.Apply(releases, time.Now())
+			if opts.dryRun {
+				result.preview, result.err = cache.PreviewFile(result.path, repository, releases, result.fetch, time.Now())
+			} else if result.err = cache.WriteFile(result.path, repository, releases, result.fetch, time.Now()); result.err == nil && opts.key != nil {
+				result.err = cache.SignFile(result.path, opts.key, time.Now())
+			}
+			results[i] = result
+		}(i, repository)
+	}
+	wg.Wait()
+	return results
+}
+
+// warmFetch fetches a repository's releases and describes the fetch. With
+// opts.incremental, a lister that can and an existing list fetched the same
+// way, only releases published since the list's newest are fetched and merged
+// into it; otherwise every release is.
+func warmFetch(ctx context.Context, lister releaseLister, path, repository string, opts warmOptions) ([]types.Release, *cache.FetchMetadata, error) {
+	incremental, ok := lister.(checker.IncrementalLister)
+	if !opts.incremental || !ok {
+		releases, err := lister.GetAllReleases(ctx)
+		return releases, fetchMetadata(lister), err
+	}
+
+	// Signed lists are verified before being merged and signed again
+	var key *cache.PublicKey
+	if opts.key != nil {
+		key = opts.key.Public()
+	}
+	cached, previous, err := cache.ReadFile(path, repository, key)
+	if err != nil || len(cached) == 0 || previous == nil || previous.Mismatch(fetchParameters(lister)) != "" {
+		releases, err := lister.GetAllReleases(ctx)
+		return releases, fetchMetadata(lister), err
+	}
+
+	since := cached[0].PublishedAt
+	for _, r := range cached {
+		if r.PublishedAt.After(since) {
+			since = r.PublishedAt
+		}
+	}
+	fetched, err := incremental.GetReleasesSince(ctx, since)
+	if err != nil {
+		return nil, nil, err
+	}
+	fetch := fetchMetadata(lister)
+	if fetch != nil {
+		fetch.Truncated = previous.Truncated // Older releases come from the earlier fetch
+	}
+	return releaseset.Merge(fetched, cached), fetch, nil
+}
+
+// warmedReleases returns the releases cache warm kept for the repository whose
+// releases repoConfig checks, or nil if there are none. Lists fetched
+// differently from the check (see fetchParameters), and with --cache-public-key
+// lists that fail verification, are skipped with a warning and releases are fetched.
+func warmedReleases(repoConfig *config.RepositoryConfig, want cache.FetchMetadata) []types.Release {
+	path := cache.WarmPath(defaultCacheDir(), repoConfig.SourceName())
+	if _, err := os.Stat(path); err != nil {
+		return nil
+	}
+	releases, fetch, err := cache.ReadFile(path, repoConfig.SourceName(), cachePublicKey)
+	if err != nil {
+		if cachePublicKey != nil {
+			yellow.Fprintf(os.Stderr, "⚠️  Ignoring unverified release cache: %v\n", err)
+		}
+		return nil
+	}
+	if fetch != nil {
+		if mismatch := fetch.Mismatch(want); mismatch != "" {
+			yellow.Fprintf(os.Stderr, "⚠️  Ignoring release cache %s: %s\n", path, mismatch)
+			return nil
+		}
+	}
+	return releases
+}
+
+// cachedReleases returns the warmed releases for repoConfig. Offline, the
+// embedded cache is used when there are none, since nothing can be fetched.
+func cachedReleases(repoConfig *config.RepositoryConfig, want cache.FetchMetadata) []types.Release {
+	releases := warmedReleases(repoConfig, want)
+	if releases != nil || !offline {
+		return releases
+	}
+	for _, file := range cache.EmbeddedFiles() {
+		if file.Repository != repoConfig.SourceName() {
+			continue
+		}
+		if releases, err := file.Load(); err == nil {
+			return releases
+		}
+	}
+	return nil
+}
+
+func runCacheVerify(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	w := cmd.OutOrStdout()
+
+	key, err := cache.LoadPublicKey(verifyPublicKey)
+	if err != nil {
+		return invalidInput(err)
+	}
+	failed := 0
+	for _, path := range args {
+		if err := cache.VerifyFile(path, key); err != nil {
+			failed++
+			red.Fprintf(w, "❌ %v\n", err)
+			continue
+		}
+		green.Fprintf(w, "✅ %s\n", path)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d files failed verification", failed, len(args))
+	}
+	return nil
+}
@@ -0,0 +1,306 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nickromney-org/github-release-version-checker/internal/cache"
+	"github.com/nickromney-org/github-release-version-checker/internal/config"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+// fakeLister returns fixed releases, or an error
+type fakeLister struct {
+	releases []types.Release
+	err      error
+}
+
+func (f fakeLister) GetAllReleases(ctx context.Context) ([]types.Release, error) {
+	return f.releases, f.err
+}
+
+func TestWarmCaches(t *testing.T) {
+	// defaultCacheDir is under HOME on macOS and XDG_CACHE_HOME elsewhere
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	releases := []types.Release{
+		{Version: mustParseVersion("2.329.0"), PublishedAt: time.Date(2025, 10, 14, 0, 0, 0, 0, time.UTC), URL: "https://example.com/v2.329.0"},
+	}
+	listers := map[string]releaseLister{
+		"actions/runner":        fakeLister{releases: releases},
+		"kubernetes/kubernetes": fakeLister{err: errors.New("rate limit exhausted")},
+	}
+
+	repositories := []string{"actions/runner", "kubernetes/kubernetes"}
+	results := warmCaches(context.Background(), repositories, defaultCacheDir(), 2, warmOptions{}, func(repository string) (releaseLister, error) {
+		return listers[repository], nil
+	})
+	if results[0].err != nil || results[1].err == nil {
+		t.Fatalf("warmCaches() = %+v, want only kubernetes to fail", results)
+	}
+
+	// Checks of the warmed repository, and forks of it, use the kept releases
+	got := warmedReleases(&config.ConfigActionsRunner, cache.FetchMetadata{})
+	if len(got) != 1 || got[0].Version.String() != "2.329.0" {
+		t.Errorf("warmedReleases(runner) = %+v", got)
+	}
+	fork := &config.RepositoryConfig{Owner: "mycorp", Repo: "runner-fork", Upstream: "actions/runner"}
+	if got := warmedReleases(fork, cache.FetchMetadata{}); len(got) != 1 {
+		t.Errorf("warmedReleases(fork) = %+v", got)
+	}
+	if got := warmedReleases(&config.ConfigKubernetes, cache.FetchMetadata{}); got != nil {
+		t.Errorf("warmedReleases(k8s) = %+v, want none", got)
+	}
+}
+
+func TestWarmCaches_Signed(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	private := ed25519.NewKeyFromSeed(bytes.Repeat([]byte{1}, ed25519.SeedSize))
+	key := &cache.SecretKey{ID: [8]byte{1, 2, 3, 4, 5, 6, 7, 8}, Key: private}
+	releases := []types.Release{
+		{Version: mustParseVersion("2.329.0"), PublishedAt: time.Date(2025, 10, 14, 0, 0, 0, 0, time.UTC), URL: "https://example.com/v2.329.0"},
+	}
+	results := warmCaches(context.Background(), []string{"actions/runner"}, defaultCacheDir(), 1, warmOptions{key: key}, func(string) (releaseLister, error) {
+		return fakeLister{releases: releases}, nil
+	})
+	if results[0].err != nil {
+		t.Fatalf("warmCaches() = %+v", results)
+	}
+
+	cachePublicKey = key.Public()
+	defer func() { cachePublicKey = nil }()
+	if got := warmedReleases(&config.ConfigActionsRunner, cache.FetchMetadata{}); len(got) != 1 {
+		t.Fatalf("warmedReleases() of a signed file = %+v", got)
+	}
+
+	// A file that no longer matches its signature is not used
+	path := cache.WarmPath(defaultCacheDir(), "actions/runner")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, bytes.Replace(data, []byte("2.329.0"), []byte("2.399.0"), 1), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := warmedReleases(&config.ConfigActionsRunner, cache.FetchMetadata{}); got != nil {
+		t.Errorf("warmedReleases() of a tampered file = %+v, want none", got)
+	}
+}
+
+// TestWarmCaches_SignedSwapped tests that a signed list copied over another
+// repository's is not used for it
+func TestWarmCaches_SignedSwapped(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	private := ed25519.NewKeyFromSeed(bytes.Repeat([]byte{1}, ed25519.SeedSize))
+	key := &cache.SecretKey{ID: [8]byte{1, 2, 3, 4, 5, 6, 7, 8}, Key: private}
+	listers := map[string]releaseLister{
+		"actions/runner": fakeLister{releases: []types.Release{
+			{Version: mustParseVersion("2.329.0"), PublishedAt: time.Date(2025, 10, 14, 0, 0, 0, 0, time.UTC), URL: "https://example.com/v2.329.0"},
+		}},
+		"kubernetes/kubernetes": fakeLister{releases: []types.Release{
+			{Version: mustParseVersion("1.34.1"), PublishedAt: time.Date(2025, 9, 9, 0, 0, 0, 0, time.UTC), URL: "https://example.com/v1.34.1"},
+		}},
+	}
+	repositories := []string{"actions/runner", "kubernetes/kubernetes"}
+	results := warmCaches(context.Background(), repositories, defaultCacheDir(), 2, warmOptions{key: key}, func(repository string) (releaseLister, error) {
+		return listers[repository], nil
+	})
+	if results[0].err != nil || results[1].err != nil {
+		t.Fatalf("warmCaches() = %+v", results)
+	}
+
+	cachePublicKey = key.Public()
+	defer func() { cachePublicKey = nil }()
+	if got := warmedReleases(&config.ConfigKubernetes, cache.FetchMetadata{}); len(got) != 1 {
+		t.Fatalf("warmedReleases(k8s) = %+v, want its own release", got)
+	}
+
+	// Swap the runner's signed list in for kubernetes
+	for _, suffix := range []string{"", cache.SignaturePath("")} {
+		data, err := os.ReadFile(results[0].path + suffix)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(results[1].path+suffix, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := warmedReleases(&config.ConfigKubernetes, cache.FetchMetadata{}); got != nil {
+		t.Errorf("warmedReleases(k8s) of the runner's signed list = %+v, want none", got)
+	}
+	if got := warmedReleases(&config.ConfigActionsRunner, cache.FetchMetadata{}); len(got) != 1 {
+		t.Errorf("warmedReleases(runner) = %+v, want its own release", got)
+	}
+}
+
+// describedLister is a fakeLister that reports its fetch like *client.Client
+type describedLister struct {
+	fakeLister
+	host string
+}
+
+func (d describedLister) APIHost() string { return d.host }
+func (d describedLister) Pages() int      { return 1 }
+func (d describedLister) Truncated() bool { return false }
+
+func TestWarmedReleases_FetchMismatch(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	releases := []types.Release{
+		{Version: mustParseVersion("2.329.0"), PublishedAt: time.Date(2025, 10, 14, 0, 0, 0, 0, time.UTC), URL: "https://example.com/v2.329.0"},
+	}
+	results := warmCaches(context.Background(), []string{"actions/runner"}, defaultCacheDir(), 1, warmOptions{}, func(string) (releaseLister, error) {
+		return describedLister{fakeLister: fakeLister{releases: releases}, host: "ghe.example.com"}, nil
+	})
+	if results[0].err != nil {
+		t.Fatalf("warmCaches() = %+v", results)
+	}
+
+	if got := warmedReleases(&config.ConfigActionsRunner, cache.FetchMetadata{APIHost: "ghe.example.com"}); len(got) != 1 {
+		t.Errorf("warmedReleases() from the same host = %+v", got)
+	}
+	if got := warmedReleases(&config.ConfigActionsRunner, cache.FetchMetadata{APIHost: "api.github.com"}); got != nil {
+		t.Errorf("warmedReleases() from another host = %+v, want none", got)
+	}
+}
+
+func TestWarmCaches_DryRun(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	releases := []types.Release{
+		{Version: mustParseVersion("2.329.0"), PublishedAt: time.Date(2025, 10, 14, 0, 0, 0, 0, time.UTC), URL: "https://example.com/v2.329.0"},
+	}
+	results := warmCaches(context.Background(), []string{"actions/runner"}, defaultCacheDir(), 1, warmOptions{dryRun: true}, func(string) (releaseLister, error) {
+		return describedLister{fakeLister: fakeLister{releases: releases}, host: "api.github.com"}, nil
+	})
+	if results[0].err != nil || results[0].preview == nil || results[0].preview.Releases != 1 {
+		t.Fatalf("warmCaches() = %+v, want a preview of 1 release", results)
+	}
+	if _, err := os.Stat(results[0].path); !os.IsNotExist(err) {
+		t.Errorf("dry run wrote %s: %v", results[0].path, err)
+	}
+
+	var out bytes.Buffer
+	printPreview(&out, "actions/runner", results[0].path, results[0].fetch, results[0].preview)
+	for _, want := range []string{"actions/runner: 1 release from 1 page", "runner.json (", "new file"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("preview missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+// incrementalLister is a describedLister that can fetch releases since a time
+type incrementalLister struct {
+	describedLister
+	since *time.Time // Set to the time asked for
+}
+
+func (l incrementalLister) GetReleasesSince(ctx context.Context, since time.Time) ([]types.Release, error) {
+	*l.since = since
+	var newer []types.Release
+	for _, r := range l.releases {
+		if !r.PublishedAt.Before(since) {
+			newer = append(newer, r)
+		}
+	}
+	return newer, nil
+}
+
+func TestWarmCaches_Incremental(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	published := time.Date(2025, 10, 14, 0, 0, 0, 0, time.UTC)
+	old := []types.Release{
+		{Version: mustParseVersion("2.328.0"), PublishedAt: published.AddDate(0, -2, 0), URL: "https://example.com/v2.328.0"},
+		{Version: mustParseVersion("2.329.0"), PublishedAt: published, URL: "https://example.com/v2.329.0"},
+	}
+	warm := func(releases []types.Release, incremental bool) (warmResult, *time.Time) {
+		var since time.Time
+		lister := incrementalLister{describedLister: describedLister{fakeLister: fakeLister{releases: releases}, host: "api.github.com"}, since: &since}
+		results := warmCaches(context.Background(), []string{"actions/runner"}, defaultCacheDir(), 1, warmOptions{incremental: incremental}, func(string) (releaseLister, error) {
+			return lister, nil
+		})
+		return results[0], &since
+	}
+
+	// Without a list to build on, every release is fetched
+	if result, since := warm(old, true); result.err != nil || !since.IsZero() {
+		t.Fatalf("first warm = %+v, since %v; want a full fetch", result, since)
+	}
+
+	// Releases since the newest kept are merged in; the lister only knows the new one
+	newRelease := types.Release{Version: mustParseVersion("2.330.0"), PublishedAt: published.AddDate(0, 0, 7), URL: "https://example.com/v2.330.0"}
+	result, since := warm([]types.Release{newRelease, old[1]}, true)
+	if result.err != nil || !since.Equal(published) {
+		t.Fatalf("incremental warm = %+v, since %v; want since %v", result, since, published)
+	}
+	got, _, err := cache.ReadFile(result.path, "", nil)
+	if err != nil || len(got) != 3 || got[0].Version.String() != "2.330.0" {
+		t.Errorf("merged list = %+v, %v; want 3 releases, newest 2.330.0", got, err)
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	tests := []struct {
+		bytes int
+		want  string
+	}{
+		{512, "512 bytes"},
+		{49357, "48.2 KB"},
+		{3 * 1024 * 1024, "3.0 MB"},
+	}
+	for _, tt := range tests {
+		if got := formatSize(tt.bytes); got != tt.want {
+			t.Errorf("formatSize(%d) = %q, want %q", tt.bytes, got, tt.want)
+		}
+	}
+}
+
+func TestCachedReleases_Offline(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	defer func(previous bool) { offline = previous }(offline)
+
+	runner, err := config.ParseRepositoryString("actions/runner")
+	if err != nil {
+		t.Fatal(err)
+	}
+	custom, err := config.ParseRepositoryString("mycorp/tool")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Online, the checker falls back to the embedded cache itself
+	offline = false
+	if got := cachedReleases(runner, cache.FetchMetadata{}); got != nil {
+		t.Errorf("online cachedReleases() = %d releases, want none without a warmed list", len(got))
+	}
+
+	// Offline, nothing can be fetched, so the embedded cache is used directly
+	offline = true
+	if got := cachedReleases(runner, cache.FetchMetadata{}); len(got) == 0 {
+		t.Error("offline cachedReleases() for the runner is empty, want the embedded cache")
+	}
+	if got := cachedReleases(custom, cache.FetchMetadata{}); got != nil {
+		t.Errorf("offline cachedReleases() for an unwarmed repository = %d releases, want none", len(got))
+	}
+
+	// Warmed lists, e.g. from bundle import, are preferred
+	releases := []types.Release{
+		{Version: mustParseVersion("1.4.0"), PublishedAt: time.Date(2025, 10, 14, 0, 0, 0, 0, time.UTC), URL: "https://example.com/v1.4.0"},
+	}
+	if err := cache.WriteFile(cache.WarmPath(defaultCacheDir(), "mycorp/tool"), "mycorp/tool", releases, nil, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if got := cachedReleases(custom, cache.FetchMetadata{}); len(got) != 1 {
+		t.Errorf("offline cachedReleases() = %d releases, want the warmed 1", len(got))
+	}
+}
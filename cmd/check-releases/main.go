@@ -2,92 +2,205 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
+	"strings"
 
+	"github.com/nickromney-org/github-release-version-checker/internal/cache"
 	"github.com/nickromney-org/github-release-version-checker/internal/config"
-	"github.com/nickromney-org/github-release-version-checker/internal/data"
-	"github.com/nickromney-org/github-release-version-checker/pkg/checker"
 	"github.com/nickromney-org/github-release-version-checker/pkg/client"
-	"github.com/nickromney-org/github-release-version-checker/pkg/types"
 )
 
+// recentCount is how many recent releases are fetched to count how far behind a cache is
+const recentCount = 100
+
+// Exit codes: a stale cache takes precedence over one that could not be checked
+const (
+	exitStale  = 1
+	exitFailed = 2
+)
+
+// result is the freshness of one embedded cache
+type result struct {
+	Repository     string `json:"repository"`
+	File           string `json:"file"`
+	EmbeddedLatest string `json:"embedded_latest,omitempty"`
+	Latest         string `json:"latest,omitempty"`
+	ReleasesBehind int    `json:"releases_behind"`
+	Current        bool   `json:"current"`
+	Error          string `json:"error,omitempty"`
+}
+
 func main() {
 	token := flag.String("token", os.Getenv("GITHUB_TOKEN"), "GitHub token")
-	repo := flag.String("repo", "actions/runner", "Repository to check (e.g., 'actions/runner', 'kubernetes')")
+	repo := flag.String("repo", "", "Only check the embedded cache of this repository (e.g., 'actions/runner', 'kubernetes')")
+	jsonOutput := flag.Bool("json", false, "Output results as JSON")
+	ciOutput := flag.Bool("ci", false, "Output GitHub Actions annotations and a job summary")
 	flag.Parse()
 
-	// Parse repository
-	repoConfig, err := config.ParseRepositoryString(*repo)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: invalid repository %q: %v\n", *repo, err)
-		os.Exit(1)
+	files := cache.EmbeddedFiles()
+	if *repo != "" {
+		repoConfig, err := config.ParseRepositoryString(*repo)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid repository %q: %v\n", *repo, err)
+			os.Exit(1)
+		}
+		files = filterFiles(files, repoConfig.FullName())
+		if len(files) == 0 {
+			fmt.Fprintf(os.Stderr, "Error: %s has no embedded cache\n", repoConfig.FullName())
+			os.Exit(1)
+		}
 	}
 
-	// Create GitHub client
-	ghClient := client.NewClient(*token, repoConfig.Owner, repoConfig.Repo)
 	ctx := context.Background()
-
-	// Load embedded releases
-	embeddedData, err := data.LoadEmbeddedReleases()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error loading embedded releases: %v\n", err)
-		os.Exit(1)
+	results := make([]result, len(files))
+	stale, failed := 0, 0
+	for i, f := range files {
+		results[i] = checkFile(ctx, *token, f)
+		switch {
+		case results[i].Error != "":
+			failed++
+		case !results[i].Current:
+			stale++
+		}
 	}
 
-	// Convert data.Release to types.Release
-	embedded := make([]types.Release, len(embeddedData))
-	for i, r := range embeddedData {
-		embedded[i] = types.Release{
-			Version:     r.Version,
-			PublishedAt: r.PublishedAt,
-			URL:         r.URL,
+	switch {
+	case *jsonOutput:
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(struct {
+			Current bool     `json:"current"`
+			Stale   int      `json:"stale"`
+			Failed  int      `json:"failed"`
+			Results []result `json:"results"`
+		}{stale == 0 && failed == 0, stale, failed, results}); err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+			os.Exit(1)
+		}
+	case *ciOutput:
+		printCI(results)
+	default:
+		printText(results)
+		if len(results) > 1 {
+			fmt.Printf("\n%d of %d embedded caches current (%d stale, %d failed)\n", len(results)-stale-failed, len(results), stale, failed)
 		}
 	}
 
-	// Find latest embedded version using helper
-	if len(embedded) == 0 {
-		fmt.Fprintf(os.Stderr, "Error: No embedded releases found\n")
-		os.Exit(1)
+	switch {
+	case stale > 0:
+		os.Exit(exitStale)
+	case failed > 0:
+		os.Exit(exitFailed)
 	}
+}
 
-	latestEmbeddedRelease := checker.FindLatestRelease(embedded)
-	if latestEmbeddedRelease == nil {
-		fmt.Fprintf(os.Stderr, "Error: Could not find latest embedded release\n")
-		os.Exit(1)
+// filterFiles returns the embedded caches of repository
+func filterFiles(files []cache.EmbeddedFile, repository string) []cache.EmbeddedFile {
+	var matched []cache.EmbeddedFile
+	for _, f := range files {
+		if strings.EqualFold(f.Repository, repository) {
+			matched = append(matched, f)
+		}
 	}
-	latestEmbedded := latestEmbeddedRelease.Version.String()
+	return matched
+}
 
-	// Fetch 5 most recent from API
-	recent, err := ghClient.GetRecentReleases(ctx, 5)
+// checkFile compares an embedded cache with the repository's recent releases
+func checkFile(ctx context.Context, token string, f cache.EmbeddedFile) result {
+	r := result{Repository: f.Repository, File: f.Path}
+
+	embedded, err := f.Load()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error fetching recent releases: %v\n", err)
-		os.Exit(1)
+		r.Error = fmt.Sprintf("loading embedded releases: %v", err)
+		return r
 	}
 
-	// Check if latest embedded is in recent 5
-	found := false
-	for _, r := range recent {
-		if r.Version.String() == latestEmbedded {
-			found = true
-			break
+	owner, repo, _ := strings.Cut(f.Repository, "/")
+	recent, err := client.NewClient(token, owner, repo).GetRecentReleases(ctx, recentCount)
+	if err != nil {
+		r.Error = fmt.Sprintf("fetching recent releases: %v", err)
+		return r
+	}
+
+	freshness := cache.CheckFreshness(embedded, recent)
+	r.ReleasesBehind = freshness.ReleasesBehind
+	r.Current = freshness.Current
+	if freshness.CachedLatest != nil {
+		r.EmbeddedLatest = freshness.CachedLatest.Version.String()
+	}
+	if freshness.Latest != nil {
+		r.Latest = freshness.Latest.Version.String()
+	}
+	return r
+}
+
+// behind describes how many releases a cache is behind
+func (r result) behind() string {
+	if r.ReleasesBehind >= recentCount {
+		return fmt.Sprintf("%d+", recentCount)
+	}
+	return fmt.Sprintf("%d", r.ReleasesBehind)
+}
+
+func printText(results []result) {
+	for _, r := range results {
+		switch {
+		case r.Error != "":
+			fmt.Printf("❌ %s: %s\n", r.File, r.Error)
+		case r.Current:
+			fmt.Printf("✅ Cache is current for %s (latest: %s)\n", r.Repository, r.EmbeddedLatest)
+		default:
+			fmt.Printf("⚠️  Cache needs update for %s (latest embedded: %s, latest available: %s, %s releases behind)\n",
+				r.Repository, r.EmbeddedLatest, r.Latest, r.behind())
 		}
 	}
+}
 
-	if found {
-		fmt.Printf("✅ Cache is current (latest: %s)\n", latestEmbedded)
-		os.Exit(0)
+// printCI writes an annotation per cache, and a summary table to
+// $GITHUB_STEP_SUMMARY when it is set
+func printCI(results []result) {
+	for _, r := range results {
+		switch {
+		case r.Error != "":
+			fmt.Printf("::error file=%s,title=Embedded cache check failed::%s: %s\n", r.File, r.Repository, r.Error)
+		case r.Current:
+			fmt.Printf("::notice file=%s,title=Embedded cache current::%s is at %s (%s releases behind %s)\n",
+				r.File, r.Repository, r.EmbeddedLatest, r.behind(), r.Latest)
+		default:
+			fmt.Printf("::warning file=%s,title=Embedded cache stale::%s is at %s, %s releases behind %s\n",
+				r.File, r.Repository, r.EmbeddedLatest, r.behind(), r.Latest)
+		}
 	}
 
-	// Get latest available version from API using helper
-	latestAvailableRelease := checker.FindLatestRelease(recent)
-	latestAvailable := ""
-	if latestAvailableRelease != nil {
-		latestAvailable = latestAvailableRelease.Version.String()
+	path := os.Getenv("GITHUB_STEP_SUMMARY")
+	if path == "" {
+		return
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: cannot write job summary: %v\n", err)
+		return
 	}
+	defer f.Close()
 
-	fmt.Printf("⚠️  Cache needs update (latest embedded: %s, latest available: %s)\n",
-		latestEmbedded, latestAvailable)
-	os.Exit(1)
+	var b strings.Builder
+	b.WriteString("## Embedded Release Caches\n\n")
+	b.WriteString("| Repository | File | Embedded | Latest | Behind | Status |\n")
+	b.WriteString("|------------|------|----------|--------|--------|--------|\n")
+	for _, r := range results {
+		status := "✅ Current"
+		switch {
+		case r.Error != "":
+			status = "❌ " + r.Error
+		case !r.Current:
+			status = "⚠️ Needs update"
+		}
+		fmt.Fprintf(&b, "| %s | `%s` | %s | %s | %s | %s |\n", r.Repository, r.File, r.EmbeddedLatest, r.Latest, r.behind(), status)
+	}
+	if _, err := f.WriteString(b.String() + "\n"); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: cannot write job summary: %v\n", err)
+	}
 }
@@ -0,0 +1,386 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/nickromney-org/github-release-version-checker/internal/config"
+	"github.com/nickromney-org/github-release-version-checker/pkg/checker"
+	"github.com/nickromney-org/github-release-version-checker/pkg/render"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+// compareFlag holds every --compare value; several are checked together
+var compareFlag []string
+
+// compareResult is the outcome of checking one of several comparison versions
+type compareResult struct {
+	Version  string // As given on the command line
+	Analysis *checker.Analysis
+	Err      error // A *checker.VersionNotFoundError; other errors stop the run
+}
+
+// compareSummary counts the statuses of several comparison versions
+type compareSummary struct {
+	Total    int `json:"total"`
+	Current  int `json:"current"`
+	Warning  int `json:"warning"`
+	Critical int `json:"critical"`
+	Expired  int `json:"expired"`
+	NotFound int `json:"not_found"`
+}
+
+// memoClient remembers a client's responses, so checking several versions of
+// one repository fetches its releases once
+type memoClient struct {
+	checker.GitHubClient
+
+	latest    *types.Release
+	latestErr error
+	latestSet bool
+	all       []types.Release
+	allErr    error
+	allSet    bool
+	recent    map[int][]types.Release
+}
+
+func (m *memoClient) GetLatestRelease(ctx context.Context) (*types.Release, error) {
+	if !m.latestSet {
+		m.latest, m.latestErr = m.GitHubClient.GetLatestRelease(ctx)
+		m.latestSet = true
+	}
+	return m.latest, m.latestErr
+}
+
+func (m *memoClient) GetAllReleases(ctx context.Context) ([]types.Release, error) {
+	if !m.allSet {
+		m.all, m.allErr = m.GitHubClient.GetAllReleases(ctx)
+		m.allSet = m.allErr == nil
+		return m.all, m.allErr
+	}
+	return m.all, nil
+}
+
+func (m *memoClient) GetRecentReleases(ctx context.Context, count int) ([]types.Release, error) {
+	if releases, ok := m.recent[count]; ok {
+		return releases, nil
+	}
+	releases, err := m.GitHubClient.GetRecentReleases(ctx, count)
+	if err != nil {
+		return nil, err
+	}
+	if m.recent == nil {
+		m.recent = make(map[int][]types.Release)
+	}
+	m.recent[count] = releases
+	return releases, nil
+}
+
+// Truncated reports whether the wrapped client cut the release list short
+func (m *memoClient) Truncated() bool {
+	reporter, ok := m.GitHubClient.(checker.TruncationReporter)
+	return ok && reporter.Truncated()
+}
+
+// uniqueVersions drops repeated and empty --compare values, keeping their order
+func uniqueVersions(values []string) []string {
+	var versions []string
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" && !containsString(versions, v) {
+			versions = append(versions, v)
+		}
+	}
+	return versions
+}
+
+// runMultiCompare checks several versions against one repository and writes a
+// combined report
+func runMultiCompare(cmd *cobra.Command, w io.Writer, repoConfig *config.RepositoryConfig, versions []string, hosts *apiHosts) error {
+	// Reject unparseable versions before fetching anything
+	for _, v := range versions {
+		if _, isChannel := checker.ParseChannel(v); isChannel {
+			continue
+		}
+		if _, err := checker.ParseComparisonVersion(v, normalisation); err != nil {
+			return err
+		}
+	}
+
+	ghClient, resolved, err := hosts.client(repoConfig)
+	if err != nil {
+		return &configError{err}
+	}
+	token, tokenSourceName := resolved.Value, resolved.describe()
+	versionChecker := newChecker(&memoClient{GitHubClient: ghClient}, repoConfig)
+	if logger := newTraceLogger(cmd.ErrOrStderr(), verbose); logger != nil {
+		ghClient.Logger = logger
+		versionChecker.SetLogger(logger)
+		logger.Info("token resolved", "source", tokenSourceName)
+	}
+
+	if token != "" && !offline {
+		if err := ghClient.CheckAccess(cmd.Context()); err != nil {
+			if accessErr := tokenAccessError(err, tokenSourceName, repoConfig.FullName()); accessErr != err {
+				return accessErr
+			}
+		}
+	}
+
+	results := make([]compareResult, 0, len(versions))
+	for _, v := range versions {
+		analysis, err := analyseTraced(cmd.Context(), versionChecker, repoConfig.FullName(), v)
+		var notFound *checker.VersionNotFoundError
+		if err != nil && !errors.As(err, &notFound) {
+			err = withToken(err, token)
+			if ciOutput && !jsonOutput {
+				return outputCIError(w, err)
+			}
+			return err
+		}
+		if analysis != nil {
+			analysis.Repository = repoConfig.FullName()
+			analysis.Upstream = repoConfig.Upstream
+			analysis.TokenSource = tokenSourceName
+		}
+		results = append(results, compareResult{Version: v, Analysis: analysis, Err: err})
+	}
+	sortCompareResults(results)
+
+	summary := summariseCompare(results)
+	switch {
+	case jsonOutput:
+		err = outputCompareJSON(w, repoConfig.FullName(), results, summary)
+	case ciOutput:
+		err = outputCompareCI(w, results, summary)
+	default:
+		err = outputCompareTerminal(w, repoConfig.FullName(), results, summary)
+	}
+	if err != nil {
+		return err
+	}
+	return compareExit(results, summary)
+}
+
+// sortCompareResults orders results newest version first; versions that are not
+// releases go last, in the order given
+func sortCompareResults(results []compareResult) {
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i].Analysis, results[j].Analysis
+		if a == nil || b == nil {
+			return a != nil
+		}
+		return types.CompareVersions(a.ComparisonVersion, b.ComparisonVersion) > 0
+	})
+}
+
+// summariseCompare counts the statuses of the checked versions
+func summariseCompare(results []compareResult) compareSummary {
+	s := compareSummary{Total: len(results)}
+	for _, r := range results {
+		if r.Analysis == nil {
+			s.NotFound++
+			continue
+		}
+		switch r.Analysis.Status() {
+		case checker.StatusCurrent:
+			s.Current++
+		case checker.StatusWarning:
+			s.Warning++
+		case checker.StatusCritical:
+			s.Critical++
+		case checker.StatusExpired:
+			s.Expired++
+		}
+	}
+	return s
+}
+
+// compareExit fails the run when a version is not a release, or with --strict
+// when any version is behind; otherwise --exit-degraded applies as for one version
+func compareExit(results []compareResult, s compareSummary) error {
+	if s.NotFound > 0 {
+		var missing []string
+		for _, r := range results {
+			if r.Analysis == nil {
+				missing = append(missing, r.Version)
+			}
+		}
+		return &exitError{code: 1, err: fmt.Errorf("%d of %d versions are not releases: %s", s.NotFound, s.Total, strings.Join(missing, ", "))}
+	}
+	if strict && s.Current < s.Total {
+		return &exitError{code: 1, err: fmt.Errorf("%d of %d versions are behind and --strict requires the latest version", s.Total-s.Current, s.Total)}
+	}
+	degraded := false
+	for _, r := range results {
+		degraded = degraded || r.Analysis.IsDegraded()
+	}
+	return degradedExit(degraded)
+}
+
+// compareLatest returns the latest version and its release date from the first
+// checked version's analysis
+func compareLatest(results []compareResult) (*checker.Analysis, bool) {
+	for _, r := range results {
+		if r.Analysis != nil {
+			return r.Analysis, true
+		}
+	}
+	return nil, false
+}
+
+// compareRow returns the table cells for one result: version, released,
+// releases behind, expiry and status
+func compareRow(r compareResult) [5]string {
+	if r.Analysis == nil {
+		return [5]string{r.Version, "-", "-", "-", "Not a release"}
+	}
+	a := r.Analysis
+	row := [5]string{types.FormatVersion(a.ComparisonVersion), "-", fmt.Sprintf("%d", a.ReleasesBehind), "-", render.StatusText(a.Status())}
+	if a.ComparisonReleasedAt != nil {
+		row[1] = formatDate(*a.ComparisonReleasedAt)
+	}
+	if expiry := a.ExpiryDate(); expiry != nil {
+		row[3] = formatDate(*expiry)
+	}
+	return row
+}
+
+// compareTableFormat lays out the combined table's columns
+const compareTableFormat = "%-12s %-14s %-7s %-14s %s"
+
+// outputCompareTerminal writes the combined table, newest version first
+func outputCompareTerminal(w io.Writer, repo string, results []compareResult, s compareSummary) error {
+	if latest, ok := compareLatest(results); ok {
+		fmt.Fprintln(w, types.FormatVersion(latest.LatestVersion))
+	}
+	fmt.Fprintln(w)
+	cyan.Fprintf(w, "📦 %s: %d versions\n", repo, s.Total)
+	fmt.Fprintln(w, strings.TrimRight(fmt.Sprintf(compareTableFormat, "Version", "Release Date", "Behind", "Expiry Date", "Status"), " "))
+	fmt.Fprintln(w, strings.Repeat("─", 61))
+	for _, r := range results {
+		row := compareRow(r)
+		line := fmt.Sprintf(compareTableFormat, row[0], row[1], row[2], row[3], "")
+		fmt.Fprint(w, line)
+		if r.Analysis == nil {
+			red.Fprintln(w, "❌ "+row[4])
+			continue
+		}
+		status := r.Analysis.Status()
+		render.StatusColour(status).Fprintf(w, "%s %s\n", render.StatusIcon(status), row[4])
+	}
+	for _, r := range results {
+		if r.Analysis != nil && r.Analysis.IsDegraded() {
+			yellow.Fprintf(w, "\n⚠️  Incomplete release data: %s\n", render.DescribeDegraded(r.Analysis.DegradedReasons))
+			break
+		}
+	}
+	return nil
+}
+
+// outputCompareCI writes the latest version, the combined table as a log group,
+// and an annotation per version
+func outputCompareCI(w io.Writer, results []compareResult, s compareSummary) error {
+	latest, ok := compareLatest(results)
+	if ok {
+		fmt.Fprintln(w, types.FormatVersion(latest.LatestVersion))
+	}
+
+	if outputFile := os.Getenv("GITHUB_OUTPUT"); outputFile != "" && ok {
+		outputs := [][2]string{
+			{"latest_version", latest.LatestVersion.String()},
+			{"status", compareWorstStatus(s)},
+			{"versions_checked", fmt.Sprintf("%d", s.Total)},
+		}
+		if err := appendGitHubOutputs(outputFile, outputs); err != nil {
+			fmt.Fprintf(w, "::warning::Failed to write step outputs: %v\n", err)
+		}
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "::group::📊 Version Check")
+	fmt.Fprintf(w, compareTableFormat+"\n", "Version", "Release Date", "Behind", "Expiry Date", "Status")
+	for _, r := range results {
+		row := compareRow(r)
+		fmt.Fprintf(w, compareTableFormat+"\n", row[0], row[1], row[2], row[3], row[4])
+	}
+	fmt.Fprintln(w, "::endgroup::")
+
+	for _, r := range results {
+		if r.Analysis == nil {
+			fmt.Fprintf(w, "::error title=%s::version %s is not a release\n", r.Version, r.Version)
+			continue
+		}
+		status := r.Analysis.Status()
+		message := fmt.Sprintf("%s Version %s: %s", render.StatusIcon(status), types.FormatVersion(r.Analysis.ComparisonVersion), r.Analysis.Message)
+		if annotation := render.Annotation(ciAnnotationLevels, status, message); annotation != "" {
+			fmt.Fprintln(w, annotation)
+		}
+	}
+	return nil
+}
+
+// compareWorstStatus returns the worst status among the checked versions, or
+// "failed" when a version is not a release
+func compareWorstStatus(s compareSummary) string {
+	switch {
+	case s.NotFound > 0:
+		return statusFailed
+	case s.Expired > 0:
+		return string(checker.StatusExpired)
+	case s.Critical > 0:
+		return string(checker.StatusCritical)
+	case s.Warning > 0:
+		return string(checker.StatusWarning)
+	default:
+		return string(checker.StatusCurrent)
+	}
+}
+
+// outputCompareJSON writes {"repository", "results": [...], "summary": {...}}, with
+// versions that are not releases as {"version", "error": {...}, "success": false}.
+// --fields applies to each result and --query to the whole document.
+func outputCompareJSON(w io.Writer, repo string, results []compareResult, s compareSummary) error {
+	analyses := make([]json.RawMessage, 0, len(results))
+	for _, r := range results {
+		if r.Analysis == nil {
+			data, err := json.Marshal(struct {
+				Version string    `json:"version"`
+				Error   jsonError `json:"error"`
+				Success bool      `json:"success"`
+			}{r.Version, classifyError(r.Err), false})
+			if err != nil {
+				return err
+			}
+			analyses = append(analyses, data)
+			continue
+		}
+
+		data, err := r.Analysis.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		if len(fieldsFlag) > 0 {
+			if data, err = selectFields(data, fieldsFlag); err != nil {
+				return err
+			}
+		}
+		analyses = append(analyses, data)
+	}
+
+	data, err := json.MarshalIndent(struct {
+		Repository string            `json:"repository"`
+		Results    []json.RawMessage `json:"results"`
+		Summary    compareSummary    `json:"summary"`
+	}{repo, analyses, s}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeJSONDocument(w, data)
+}
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nickromney-org/github-release-version-checker/pkg/checker"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+// countingClient returns fixed releases and counts the calls made
+type countingClient struct {
+	releases []types.Release
+	calls    int
+}
+
+func (c *countingClient) GetLatestRelease(ctx context.Context) (*types.Release, error) {
+	c.calls++
+	return &c.releases[0], nil
+}
+
+func (c *countingClient) GetAllReleases(ctx context.Context) ([]types.Release, error) {
+	c.calls++
+	return c.releases, nil
+}
+
+func (c *countingClient) GetRecentReleases(ctx context.Context, count int) ([]types.Release, error) {
+	c.calls++
+	return c.releases[:min(count, len(c.releases))], nil
+}
+
+func TestMemoClient(t *testing.T) {
+	now := time.Now()
+	inner := &countingClient{releases: []types.Release{
+		{Version: mustParseVersion("2.329.0"), PublishedAt: now.AddDate(0, 0, -5)},
+		{Version: mustParseVersion("2.328.0"), PublishedAt: now.AddDate(0, 0, -40)},
+		{Version: mustParseVersion("2.327.1"), PublishedAt: now.AddDate(0, 0, -70)},
+	}}
+	versionChecker := checker.NewChecker(&memoClient{GitHubClient: inner}, checker.Config{
+		CriticalAgeDays: 12,
+		MaxAgeDays:      30,
+		NoCache:         true,
+	})
+
+	if _, err := versionChecker.Analyse(context.Background(), "2.328.0"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	first := inner.calls
+	for _, v := range []string{"2.327.1", "2.329.0"} {
+		if _, err := versionChecker.Analyse(context.Background(), v); err != nil {
+			t.Fatalf("unexpected error for %s: %v", v, err)
+		}
+	}
+	if inner.calls != first {
+		t.Errorf("later analyses made %d more requests, want none", inner.calls-first)
+	}
+}
+
+func TestUniqueVersions(t *testing.T) {
+	got := uniqueVersions([]string{"2.328.0", "", "2.326.0", "2.328.0", " "})
+	want := []string{"2.328.0", "2.326.0"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("uniqueVersions = %v, want %v", got, want)
+	}
+}
+
+// compareStatusResult returns a compare result for version with the given status
+func compareStatusResult(version string, status checker.Status) compareResult {
+	r := statusResult(status)
+	if status != checker.StatusCurrent {
+		r.Analysis.ComparisonVersion = mustParseVersion(version)
+	}
+	return compareResult{Version: version, Analysis: r.Analysis}
+}
+
+func TestSummariseCompare(t *testing.T) {
+	missing := compareResult{Version: "2.999.0", Err: &checker.VersionNotFoundError{Version: mustParseVersion("2.999.0"), Latest: mustParseVersion("2.329.0")}}
+	results := []compareResult{
+		compareStatusResult("2.326.0", checker.StatusExpired),
+		missing,
+		compareStatusResult("2.329.0", checker.StatusCurrent),
+		compareStatusResult("2.328.0", checker.StatusWarning),
+	}
+	sortCompareResults(results)
+
+	var order []string
+	for _, r := range results {
+		order = append(order, r.Version)
+	}
+	if want := []string{"2.329.0", "2.328.0", "2.326.0", "2.999.0"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("order = %v, want %v", order, want)
+	}
+
+	s := summariseCompare(results)
+	want := compareSummary{Total: 4, Current: 1, Warning: 1, Expired: 1, NotFound: 1}
+	if s != want {
+		t.Errorf("summary = %+v, want %+v", s, want)
+	}
+	if got := compareWorstStatus(s); got != statusFailed {
+		t.Errorf("worst status = %q, want %q", got, statusFailed)
+	}
+
+	err := compareExit(results, s)
+	if err == nil || ExitCode(err) != 1 || !strings.Contains(err.Error(), "1 of 4 versions are not releases: 2.999.0") {
+		t.Errorf("compareExit = %v, want not-release failure", err)
+	}
+}
+
+func TestOutputCompareJSON(t *testing.T) {
+	results := []compareResult{
+		compareStatusResult("2.328.0", checker.StatusWarning),
+		{Version: "2.999.0", Err: &checker.VersionNotFoundError{Version: mustParseVersion("2.999.0"), Latest: mustParseVersion("2.329.0")}},
+	}
+
+	var buf bytes.Buffer
+	if err := outputCompareJSON(&buf, "actions/runner", results, summariseCompare(results)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var doc struct {
+		Repository string           `json:"repository"`
+		Results    []map[string]any `json:"results"`
+		Summary    compareSummary   `json:"summary"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if doc.Repository != "actions/runner" || len(doc.Results) != 2 {
+		t.Fatalf("got repository %q with %d results", doc.Repository, len(doc.Results))
+	}
+	if doc.Results[0]["comparison_version"] != "2.328.0" || doc.Results[0]["status"] != "warning" {
+		t.Errorf("first result = %v", doc.Results[0])
+	}
+	failed := doc.Results[1]
+	if failed["version"] != "2.999.0" || failed["success"] != false {
+		t.Errorf("second result = %v", failed)
+	}
+	if code := failed["error"].(map[string]any)["code"]; code != errorCodeVersionNotFound {
+		t.Errorf("error code = %v, want %s", code, errorCodeVersionNotFound)
+	}
+	if doc.Summary.Warning != 1 || doc.Summary.NotFound != 1 {
+		t.Errorf("summary = %+v", doc.Summary)
+	}
+}
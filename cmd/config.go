@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/nickromney-org/github-release-version-checker/internal/config"
+	"github.com/spf13/pflag"
+)
+
+// configFilePath is the --config flag value
+var configFilePath string
+
+// configWaivers are the config file's waivers, matched to each repository checked
+var configWaivers []config.FileWaiver
+
+// configYanked are the config file's known-bad releases, matched to each repository checked
+var configYanked []config.FileYanked
+
+// configNoteKeywords are the config file's shared release note keywords
+var configNoteKeywords []config.FileNoteKeyword
+
+// loadConfigFile loads --config, or the default config file if one exists in
+// the working directory. Returns nil when there is no config file to use.
+func loadConfigFile(flags *pflag.FlagSet) (*config.File, error) {
+	path := configFilePath
+	if !flags.Changed("config") {
+		path = config.DefaultFileName
+		if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+	}
+	return config.LoadFile(path)
+}
+
+// applyConfigFile fills in settings from the config file that were not set by flags
+func applyConfigFile(flags *pflag.FlagSet, f *config.File) {
+	n := f.Notifications
+	if !flags.Changed("annotation-level") && len(n.AnnotationLevels) > 0 {
+		annotationLevels = n.AnnotationLevels
+	}
+	if !flags.Changed("no-annotations") && n.NoAnnotations {
+		noAnnotations = true
+	}
+	if !flags.Changed("summary-exclude") && len(n.SummaryExclude) > 0 {
+		summaryExclude = n.SummaryExclude
+	}
+	if !flags.Changed("summary-template") && n.SummaryTemplate != "" {
+		summaryTemplate = n.SummaryTemplate
+	}
+	if !flags.Changed("notify-template") && n.NotifyTemplate != "" {
+		notifyTemplatePath = n.NotifyTemplate
+	}
+	configWaivers = f.Waivers
+	configYanked = f.Yanked
+	configNoteKeywords = f.NoteKeywords
+}
+
+// configRepository returns the repository the config file selects for a single
+// check, or nil when --repo is given or the file lists none
+func configRepository(flags *pflag.FlagSet, f *config.File) (*config.RepositoryConfig, string, error) {
+	if f == nil || flags.Changed("repo") || len(f.Repositories) == 0 {
+		return nil, "", nil
+	}
+
+	entry := f.Repositories[0]
+	repoConfig, err := entry.RepositoryConfig()
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid repository in config file: %w", err)
+	}
+	return repoConfig, entry.Version, nil
+}
+
+// configuredToken resolves the token for host using the config file's token
+// settings for it, unless a token was passed with -t
+func configuredToken(f *config.File, flagToken, host string) resolvedToken {
+	if f == nil || flagToken != "" {
+		return detectGitHubToken(flagToken, host)
+	}
+
+	settings := f.TokenFor(host)
+	switch settings.Source {
+	case config.TokenSourceNone:
+		return resolvedToken{Source: tokenSourceNone}
+	case config.TokenSourceEnv:
+		if token := os.Getenv(settings.Env); token != "" {
+			return resolvedToken{Value: token, Source: tokenSourceEnv, Detail: settings.Env}
+		}
+		return resolvedToken{Source: tokenSourceNone}
+	case config.TokenSourceGH:
+		if token, path, err := readGHHostsToken(host); err == nil && token != "" {
+			return resolvedToken{Value: token, Source: tokenSourceGHHosts, Detail: path}
+		}
+		if token, err := getGitHubCLIToken(host); err == nil {
+			return resolvedToken{Value: token, Source: tokenSourceGHCLI}
+		}
+		return resolvedToken{Source: tokenSourceNone}
+	default:
+		return detectGitHubToken(flagToken, host)
+	}
+}
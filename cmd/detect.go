@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/nickromney-org/github-release-version-checker/pkg/checker"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+// Comparison version sources for --detect
+const (
+	detectGit  = "git"  // The highest semver tag reachable from HEAD
+	detectFile = "file" // The first line of a file, VERSION by default
+)
+
+// defaultVersionFile is read by --detect file
+const defaultVersionFile = "VERSION"
+
+// detectFlag is the --detect value
+var detectFlag string
+
+// detectVersion reads the comparison version from source: "git", "file" or
+// "file:PATH". It returns the version and where it came from.
+func detectVersion(ctx context.Context, source string) (string, string, error) {
+	kind, path, _ := strings.Cut(source, ":")
+	switch {
+	case kind == detectGit && path == "":
+		return detectGitVersion(ctx)
+	case kind == detectFile:
+		if path == "" {
+			path = defaultVersionFile
+		}
+		return detectFileVersion(path)
+	default:
+		return "", "", fmt.Errorf("invalid detect source %q: must be 'git', 'file' or 'file:PATH'", source)
+	}
+}
+
+// detectGitVersion returns the highest semver tag reachable from HEAD, preferring
+// stable versions to prereleases
+func detectGitVersion(ctx context.Context) (string, string, error) {
+	out, err := runGit(ctx, "tag", "--merged", "HEAD")
+	if err != nil {
+		return "", "", err
+	}
+
+	var best, bestStable *semver.Version
+	var bestTag, bestStableTag string
+	for _, tag := range strings.Fields(out) {
+		v, err := checker.ParseComparisonVersion(tag, checker.NormaliseAll)
+		if err != nil {
+			continue
+		}
+		if best == nil || types.CompareVersions(v, best) > 0 {
+			best, bestTag = v, tag
+		}
+		if v.Prerelease() == "" && (bestStable == nil || types.CompareVersions(v, bestStable) > 0) {
+			bestStable, bestStableTag = v, tag
+		}
+	}
+	switch {
+	case bestStable != nil:
+		return bestStable.String(), "git tag " + bestStableTag, nil
+	case best != nil:
+		return best.String(), "git tag " + bestTag, nil
+	default:
+		return "", "", fmt.Errorf("no semver tags reachable from HEAD")
+	}
+}
+
+// detectFileVersion returns the first non-empty line of path
+func detectFileVersion(path string) (string, string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read version file: %w", err)
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line, path, nil
+		}
+	}
+	return "", "", fmt.Errorf("version file %s is empty", path)
+}
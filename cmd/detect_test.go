@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDetectVersion_Git(t *testing.T) {
+	tests := []struct {
+		name     string
+		tags     string
+		want     string
+		wantFrom string
+		wantErr  string
+	}{
+		{"highest stable", "v1.2.0\nv1.10.0\nv1.3.0-rc.1\nnightly\n", "1.10.0", "git tag v1.10.0", ""},
+		{"prerelease when nothing stable", "v2.0.0-beta.1\nv2.0.0-alpha.3\n", "2.0.0-beta.1", "git tag v2.0.0-beta.1", ""},
+		{"no semver tags", "nightly\nlatest\n", "", "", "no semver tags"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeGit(t, map[string]string{"tag --merged HEAD": tt.tags})
+			got, from, err := detectVersion(context.Background(), "git")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("detectVersion() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want || from != tt.wantFrom {
+				t.Errorf("detectVersion() = %q from %q, want %q from %q", got, from, tt.want, tt.wantFrom)
+			}
+		})
+	}
+}
+
+func TestDetectVersion_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "VERSION")
+	if err := os.WriteFile(path, []byte("\n  v2.328.0 \n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, from, err := detectVersion(context.Background(), "file:"+path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "v2.328.0" || from != path {
+		t.Errorf("detectVersion() = %q from %q", got, from)
+	}
+
+	if _, _, err := detectVersion(context.Background(), "svn"); err == nil || !strings.Contains(err.Error(), "invalid detect source") {
+		t.Errorf("detectVersion(svn) error = %v", err)
+	}
+}
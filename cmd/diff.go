@@ -0,0 +1,273 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/nickromney-org/github-release-version-checker/internal/cache"
+	"github.com/nickromney-org/github-release-version-checker/pkg/releaseset"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+// Snapshot kinds understood by the diff command
+const (
+	snapshotCache    = "cache"
+	snapshotAnalysis = "analysis"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff OLD.json NEW.json",
+	Short: "Compare two cache files or two JSON analysis outputs",
+	Long: `Compare two release cache files, or two outputs of --json, and print what changed.
+
+For cache files, new, removed and modified releases are listed.
+For analysis outputs, changes to the latest version, status and releases behind are listed,
+along with any releases that appeared in the timeline.`,
+	Example: `  # Review a cache refresh before committing
+  github-release-version-checker diff old-releases.json internal/data/releases.json
+
+  # Compare yesterday's scheduled check with today's
+  github-release-version-checker diff yesterday.json today.json`,
+	Args: cobra.ExactArgs(2),
+	RunE: runDiff,
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+}
+
+// analysisSnapshot is the subset of the --json output compared by diff
+type analysisSnapshot struct {
+	LatestVersion     string            `json:"latest_version"`
+	ComparisonVersion string            `json:"comparison_version"`
+	Status            string            `json:"status"`
+	ReleasesBehind    int               `json:"releases_behind"`
+	RecentReleases    []snapshotRelease `json:"recent_releases"`
+}
+
+// snapshotRelease is a timeline entry in an analysis output
+type snapshotRelease struct {
+	Version string `json:"version"`
+}
+
+// releaseChange describes a release present in both caches with different metadata
+type releaseChange struct {
+	Old types.Release
+	New types.Release
+}
+
+// cacheDiff holds the differences between two cache files
+type cacheDiff struct {
+	Added   []types.Release
+	Removed []types.Release
+	Changed []releaseChange
+}
+
+// IsEmpty reports whether the two caches were identical
+func (d cacheDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	w := cmd.OutOrStdout()
+
+	oldKind, err := detectSnapshotKind(args[0])
+	if err != nil {
+		return err
+	}
+	newKind, err := detectSnapshotKind(args[1])
+	if err != nil {
+		return err
+	}
+	if oldKind != newKind {
+		return fmt.Errorf("cannot compare a %s file (%s) with a %s file (%s)", oldKind, args[0], newKind, args[1])
+	}
+
+	fmt.Fprintf(w, "Comparing %s → %s (%s)\n\n", args[0], args[1], oldKind)
+
+	if oldKind == snapshotCache {
+		oldReleases, err := cache.LoadFile(args[0])
+		if err != nil {
+			return err
+		}
+		newReleases, err := cache.LoadFile(args[1])
+		if err != nil {
+			return err
+		}
+		printCacheDiff(w, diffCaches(oldReleases, newReleases))
+		return nil
+	}
+
+	oldAnalysis, err := loadAnalysisSnapshot(args[0])
+	if err != nil {
+		return err
+	}
+	newAnalysis, err := loadAnalysisSnapshot(args[1])
+	if err != nil {
+		return err
+	}
+	for _, line := range diffAnalyses(oldAnalysis, newAnalysis) {
+		fmt.Fprintln(w, line)
+	}
+	return nil
+}
+
+// detectSnapshotKind determines whether a file is a cache file or an analysis output
+func detectSnapshotKind(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return "", fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	if _, ok := fields["releases"]; ok {
+		return snapshotCache, nil
+	}
+	if _, ok := fields["latest_version"]; ok {
+		return snapshotAnalysis, nil
+	}
+	return "", fmt.Errorf("%s is neither a cache file nor an analysis output", path)
+}
+
+func loadAnalysisSnapshot(path string) (*analysisSnapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var snapshot analysisSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &snapshot, nil
+}
+
+// diffCaches compares two sets of releases keyed by version
+func diffCaches(oldReleases, newReleases []types.Release) cacheDiff {
+	oldByVersion := make(map[string]types.Release, len(oldReleases))
+	for _, r := range oldReleases {
+		oldByVersion[r.Version.String()] = r
+	}
+	newByVersion := make(map[string]types.Release, len(newReleases))
+	for _, r := range newReleases {
+		newByVersion[r.Version.String()] = r
+	}
+
+	var diff cacheDiff
+	for key, r := range newByVersion {
+		old, ok := oldByVersion[key]
+		if !ok {
+			diff.Added = append(diff.Added, r)
+			continue
+		}
+		if !old.PublishedAt.Equal(r.PublishedAt) || old.URL != r.URL {
+			diff.Changed = append(diff.Changed, releaseChange{Old: old, New: r})
+		}
+	}
+	for key, r := range oldByVersion {
+		if _, ok := newByVersion[key]; !ok {
+			diff.Removed = append(diff.Removed, r)
+		}
+	}
+
+	releaseset.SortByVersion(diff.Added)
+	releaseset.SortByVersion(diff.Removed)
+	sort.Slice(diff.Changed, func(i, j int) bool {
+		return releaseset.Compare(diff.Changed[i].New, diff.Changed[j].New) > 0
+	})
+
+	return diff
+}
+
+func printCacheDiff(w io.Writer, diff cacheDiff) {
+	if diff.IsEmpty() {
+		fmt.Fprintln(w, "No differences")
+		return
+	}
+
+	if len(diff.Added) > 0 {
+		green.Fprintf(w, "New releases (%d):\n", len(diff.Added))
+		for _, r := range diff.Added {
+			fmt.Fprintf(w, "  + %-12s Released %s\n", types.FormatVersion(r.Version), formatDate(r.PublishedAt))
+		}
+	}
+
+	if len(diff.Removed) > 0 {
+		red.Fprintf(w, "Removed releases (%d):\n", len(diff.Removed))
+		for _, r := range diff.Removed {
+			fmt.Fprintf(w, "  - %-12s Released %s\n", types.FormatVersion(r.Version), formatDate(r.PublishedAt))
+		}
+	}
+
+	if len(diff.Changed) > 0 {
+		yellow.Fprintf(w, "Changed releases (%d):\n", len(diff.Changed))
+		for _, c := range diff.Changed {
+			if !c.Old.PublishedAt.Equal(c.New.PublishedAt) {
+				fmt.Fprintf(w, "  ~ %-12s Released %s → %s\n", types.FormatVersion(c.New.Version), formatDate(c.Old.PublishedAt), formatDate(c.New.PublishedAt))
+			}
+			if c.Old.URL != c.New.URL {
+				fmt.Fprintf(w, "  ~ %-12s URL %s → %s\n", types.FormatVersion(c.New.Version), c.Old.URL, c.New.URL)
+			}
+		}
+	}
+}
+
+// diffAnalyses describes the changes between two analysis outputs, one line per change
+func diffAnalyses(oldAnalysis, newAnalysis *analysisSnapshot) []string {
+	var lines []string
+
+	if oldAnalysis.LatestVersion != newAnalysis.LatestVersion {
+		lines = append(lines, fmt.Sprintf("Latest version: v%s → v%s", oldAnalysis.LatestVersion, newAnalysis.LatestVersion))
+	}
+	if oldAnalysis.ComparisonVersion != newAnalysis.ComparisonVersion {
+		lines = append(lines, fmt.Sprintf("Comparison version: %s → %s",
+			displayVersion(oldAnalysis.ComparisonVersion), displayVersion(newAnalysis.ComparisonVersion)))
+	}
+	if oldAnalysis.Status != newAnalysis.Status {
+		lines = append(lines, fmt.Sprintf("Status: %s → %s", oldAnalysis.Status, newAnalysis.Status))
+	}
+	if oldAnalysis.ReleasesBehind != newAnalysis.ReleasesBehind {
+		lines = append(lines, fmt.Sprintf("Releases behind: %d → %d", oldAnalysis.ReleasesBehind, newAnalysis.ReleasesBehind))
+	}
+
+	seen := make(map[string]bool, len(oldAnalysis.RecentReleases))
+	for _, r := range oldAnalysis.RecentReleases {
+		seen[r.Version] = true
+	}
+	var added []*semver.Version
+	for _, r := range newAnalysis.RecentReleases {
+		if seen[r.Version] {
+			continue
+		}
+		if v, err := types.ParseVersion(r.Version); err == nil {
+			added = append(added, v)
+		}
+	}
+	sort.Sort(sort.Reverse(semver.Collection(added)))
+	for _, v := range added {
+		lines = append(lines, fmt.Sprintf("New release: v%s", v))
+	}
+
+	if len(lines) == 0 {
+		lines = append(lines, "No differences")
+	}
+	return lines
+}
+
+// displayVersion formats an optional version string for diff output
+func displayVersion(v string) string {
+	if v == "" {
+		return "(none)"
+	}
+	return "v" + v
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+func diffTestRelease(version string, published time.Time) types.Release {
+	return types.Release{
+		Version:     mustParseVersion(version),
+		PublishedAt: published,
+		URL:         "https://github.com/actions/runner/releases/tag/v" + version,
+	}
+}
+
+func TestDiffCaches(t *testing.T) {
+	base := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	oldReleases := []types.Release{
+		diffTestRelease("2.329.0", base),
+		diffTestRelease("2.328.0", base.AddDate(0, 0, -30)),
+		diffTestRelease("2.327.0", base.AddDate(0, 0, -60)),
+	}
+	newReleases := []types.Release{
+		diffTestRelease("2.331.0", base.AddDate(0, 0, 20)),
+		diffTestRelease("2.330.0", base.AddDate(0, 0, 10)),
+		diffTestRelease("2.329.0", base.AddDate(0, 0, 1)),
+		diffTestRelease("2.328.0", base.AddDate(0, 0, -30)),
+	}
+
+	diff := diffCaches(oldReleases, newReleases)
+
+	if len(diff.Added) != 2 {
+		t.Fatalf("expected 2 added releases, got %d", len(diff.Added))
+	}
+	if diff.Added[0].Version.String() != "2.331.0" || diff.Added[1].Version.String() != "2.330.0" {
+		t.Errorf("expected added releases newest first, got %s, %s", diff.Added[0].Version, diff.Added[1].Version)
+	}
+	if len(diff.Removed) != 1 || diff.Removed[0].Version.String() != "2.327.0" {
+		t.Errorf("expected 2.327.0 to be removed, got %v", diff.Removed)
+	}
+	if len(diff.Changed) != 1 || diff.Changed[0].New.Version.String() != "2.329.0" {
+		t.Errorf("expected 2.329.0 to be changed, got %v", diff.Changed)
+	}
+	if diff.IsEmpty() {
+		t.Error("expected diff to be non-empty")
+	}
+
+	if !diffCaches(oldReleases, oldReleases).IsEmpty() {
+		t.Error("expected identical caches to produce an empty diff")
+	}
+}
+
+func TestDiffAnalyses(t *testing.T) {
+	oldAnalysis := &analysisSnapshot{
+		LatestVersion:     "2.329.0",
+		ComparisonVersion: "2.328.0",
+		Status:            "warning",
+		ReleasesBehind:    1,
+		RecentReleases:    []snapshotRelease{{Version: "2.329.0"}},
+	}
+
+	newAnalysis := *oldAnalysis
+	newAnalysis.LatestVersion = "2.330.0"
+	newAnalysis.Status = "critical"
+	newAnalysis.ReleasesBehind = 2
+	newAnalysis.RecentReleases = []snapshotRelease{{Version: "2.329.0"}, {Version: "2.330.0"}}
+
+	lines := diffAnalyses(oldAnalysis, &newAnalysis)
+	expected := []string{
+		"Latest version: v2.329.0 → v2.330.0",
+		"Status: warning → critical",
+		"Releases behind: 1 → 2",
+		"New release: v2.330.0",
+	}
+	if len(lines) != len(expected) {
+		t.Fatalf("expected %d lines, got %d: %v", len(expected), len(lines), lines)
+	}
+	for i := range expected {
+		if lines[i] != expected[i] {
+			t.Errorf("line %d: expected %q, got %q", i, expected[i], lines[i])
+		}
+	}
+
+	if lines := diffAnalyses(oldAnalysis, oldAnalysis); len(lines) != 1 || lines[0] != "No differences" {
+		t.Errorf("expected no differences, got %v", lines)
+	}
+}
+
+func TestDetectSnapshotKind(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		wantErr bool
+	}{
+		{"cache file", `{"generated_at": "2025-10-31T00:00:00Z", "releases": []}`, snapshotCache, false},
+		{"analysis output", `{"latest_version": "2.329.0", "status": "current"}`, snapshotAnalysis, false},
+		{"unknown", `{"foo": "bar"}`, "", true},
+		{"invalid json", `not json`, "", true},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, string(rune('a'+i))+".json")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("failed to write fixture: %v", err)
+			}
+
+			got, err := detectSnapshotKind(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("detectSnapshotKind() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("detectSnapshotKind() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nickromney-org/github-release-version-checker/pkg/checker"
+	"github.com/nickromney-org/github-release-version-checker/pkg/render"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+// Digest schedules for --digest
+const digestDaily = "daily"
+
+var (
+	digestFlag      string
+	digestStatePath string
+)
+
+// statusChange is a repository's status moving between batch runs
+type statusChange struct {
+	Repository  string `json:"repository"`
+	From        string `json:"from,omitempty"` // Empty the first time a repository is seen
+	To          string `json:"to"`
+	Version     string `json:"version"`
+	Recommended string `json:"recommended"`
+}
+
+// digestState carries statuses and unsent changes between batch runs
+type digestState struct {
+	Statuses map[string]string `json:"statuses"` // Last status per repository
+	Pending  []statusChange    `json:"pending,omitempty"`
+	LastSent string            `json:"last_sent,omitempty"` // YYYY-MM-DD (UTC) of the last digest
+}
+
+// validateDigest checks a --digest value
+func validateDigest(schedule string) error {
+	if schedule != "" && schedule != digestDaily {
+		return fmt.Errorf("invalid digest %q: must be '%s'", schedule, digestDaily)
+	}
+	return nil
+}
+
+// loadDigestState reads the digest state, or returns an empty one if there is none yet
+func loadDigestState(path string) (*digestState, error) {
+	state := &digestState{}
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return nil, fmt.Errorf("failed to read digest state: %w", err)
+	default:
+		if err := json.Unmarshal(data, state); err != nil {
+			return nil, fmt.Errorf("invalid digest state %s: %w", path, err)
+		}
+	}
+	if state.Statuses == nil {
+		state.Statuses = map[string]string{}
+	}
+	return state, nil
+}
+
+// save writes the digest state, creating its directory
+func (s *digestState) save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to write digest state: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write digest state: %w", err)
+	}
+	return nil
+}
+
+// record queues the status changes since the last run
+func (s *digestState) record(results []batchResult) {
+	for _, result := range results {
+		analysis := result.Analysis
+		if analysis == nil || analysis.ComparisonVersion == nil {
+			continue
+		}
+		status := string(analysis.Status())
+		previous, seen := s.Statuses[result.Repository]
+		s.Statuses[result.Repository] = status
+		// A repository first seen while current is not news
+		if previous == status || (!seen && status == string(checker.StatusCurrent)) {
+			continue
+		}
+		s.Pending = append(s.Pending, statusChange{
+			Repository:  result.Repository,
+			From:        previous,
+			To:          status,
+			Version:     types.FormatVersion(analysis.ComparisonVersion),
+			Recommended: types.FormatVersion(analysis.Recommended()),
+		})
+	}
+}
+
+// due reports whether a digest should be sent at now: there are changes and none
+// has been sent today
+func (s *digestState) due(now time.Time) bool {
+	return len(s.Pending) > 0 && s.LastSent != now.UTC().Format(time.DateOnly)
+}
+
+// digestMessage summarises the pending changes in one notification
+func digestMessage(changes []statusChange) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "📋 Release check digest: %d status change%s\n", len(changes), types.PluralSuffix(len(changes)))
+	for _, c := range changes {
+		to := checker.Status(c.To)
+		from := "new"
+		if c.From != "" {
+			from = strings.ToLower(render.StatusText(checker.Status(c.From)))
+		}
+		fmt.Fprintf(&b, "%s %s v%s: %s → %s", render.StatusIcon(to), c.Repository, c.Version, from, strings.ToLower(render.StatusText(to)))
+		if to != checker.StatusCurrent && c.Recommended != c.Version {
+			fmt.Fprintf(&b, ", update to v%s", c.Recommended)
+		}
+		b.WriteString("\n")
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// sendDigest records the batch's status changes and posts them as one digest
+// when one is due; the state is saved either way so changes are not lost
+func sendDigest(ctx context.Context, results []batchResult, now time.Time) error {
+	state, err := loadDigestState(digestStatePath)
+	if err != nil {
+		return err
+	}
+	state.record(results)
+	if state.due(now) {
+		if err := postWebhook(ctx, digestMessage(state.Pending)); err != nil {
+			if saveErr := state.save(digestStatePath); saveErr != nil {
+				return saveErr
+			}
+			return err
+		}
+		state.Pending = nil
+		state.LastSent = now.UTC().Format(time.DateOnly)
+	}
+	return state.save(digestStatePath)
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nickromney-org/github-release-version-checker/pkg/checker"
+)
+
+// digestResult is a batch result for repository at comparison against 2.329.0
+func digestResult(repository, comparison string, expired bool) batchResult {
+	analysis := &checker.Analysis{
+		LatestVersion:     mustParseVersion("2.329.0"),
+		ComparisonVersion: mustParseVersion(comparison),
+		IsLatest:          comparison == "2.329.0",
+		IsExpired:         expired,
+	}
+	if !analysis.IsLatest {
+		analysis.ReleasesBehind = 1
+	}
+	return batchResult{Repository: repository, Analysis: analysis}
+}
+
+func TestDigestState_Record(t *testing.T) {
+	state := &digestState{Statuses: map[string]string{"actions/runner": "warning", "nodejs/node": "current"}}
+	state.record([]batchResult{
+		digestResult("actions/runner", "2.328.0", true), // warning -> expired
+		digestResult("nodejs/node", "2.329.0", false),   // unchanged
+		digestResult("pulumi/pulumi", "2.329.0", false), // new and current: not news
+		digestResult("hashicorp/terraform", "2.328.0", false),
+		{Repository: "kubernetes/kubernetes", Err: context.Canceled},
+	})
+
+	want := "📋 Release check digest: 2 status changes\n" +
+		"🚨 actions/runner v2.328.0: behind → expired, update to v2.329.0\n" +
+		"⚠️  hashicorp/terraform v2.328.0: new → behind, update to v2.329.0"
+	if got := digestMessage(state.Pending); got != want {
+		t.Errorf("digestMessage() =\n%s\nwant\n%s", got, want)
+	}
+	if state.Statuses["pulumi/pulumi"] != "current" || state.Statuses["actions/runner"] != "expired" {
+		t.Errorf("statuses = %v", state.Statuses)
+	}
+}
+
+func TestSendDigest(t *testing.T) {
+	var posts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("invalid payload: %v", err)
+		}
+		posts = append(posts, payload["text"])
+	}))
+	defer server.Close()
+
+	notifyWebhook, digestStatePath = server.URL, filepath.Join(t.TempDir(), "state", "digest.json")
+	t.Cleanup(func() { notifyWebhook, digestStatePath = "", "" })
+
+	day1 := time.Date(2025, 10, 20, 9, 0, 0, 0, time.UTC)
+	runs := []struct {
+		at        time.Time
+		results   []batchResult
+		wantPosts int
+	}{
+		{day1, []batchResult{digestResult("actions/runner", "2.328.0", false)}, 1},
+		{day1.Add(time.Hour), []batchResult{digestResult("actions/runner", "2.328.0", true)}, 1}, // Held until tomorrow
+		{day1.Add(2 * time.Hour), []batchResult{digestResult("actions/runner", "2.329.0", false)}, 1},
+		{day1.AddDate(0, 0, 1), []batchResult{digestResult("actions/runner", "2.329.0", false)}, 2},
+		{day1.AddDate(0, 0, 2), []batchResult{digestResult("actions/runner", "2.329.0", false)}, 2}, // Nothing changed
+	}
+	for i, run := range runs {
+		if err := sendDigest(context.Background(), run.results, run.at); err != nil {
+			t.Fatalf("run %d: sendDigest() error = %v", i, err)
+		}
+		if len(posts) != run.wantPosts {
+			t.Fatalf("run %d: %d posts, want %d", i, len(posts), run.wantPosts)
+		}
+	}
+
+	want := "📋 Release check digest: 2 status changes\n" +
+		"🚨 actions/runner v2.328.0: behind → expired, update to v2.329.0\n" +
+		"✅ actions/runner v2.329.0: expired → current"
+	if posts[1] != want {
+		t.Errorf("second digest =\n%s\nwant\n%s", posts[1], want)
+	}
+}
+
+func TestValidateDigest(t *testing.T) {
+	if err := validateDigest("daily"); err != nil {
+		t.Errorf("validateDigest(daily) error = %v", err)
+	}
+	if err := validateDigest("hourly"); err == nil {
+		t.Error("expected an error for hourly")
+	}
+}
@@ -0,0 +1,296 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/nickromney-org/github-release-version-checker/pkg/checker"
+	"github.com/nickromney-org/github-release-version-checker/pkg/render"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+// Report formats understood by the distribution command
+const (
+	distributionTerminal = "terminal"
+	distributionJSON     = "json"
+	distributionMarkdown = "markdown"
+)
+
+// statusUnknown marks versions in service that are not releases of the repository
+const statusUnknown = "unknown"
+
+var (
+	distributionRepo   string
+	distributionToken  string
+	distributionFormat string
+)
+
+var distributionCmd = &cobra.Command{
+	Use:   "distribution [FILE]",
+	Short: "Report how a fleet's versions are spread across statuses",
+	Long: `Read the versions running across a fleet, with optional counts, and report how
+many are on the latest release, critical or expired, and the oldest version in service.
+
+Each line holds a version and an optional count, separated by spaces, tabs or a comma;
+a version without a count counts once, and repeated versions are added together.
+Blank lines and lines starting with # are ignored. Reads standard input when FILE is
+omitted or "-".`,
+	Example: `  # Counts per version
+  printf '2.329.0 40\n2.328.0 12\n2.326.0 3\n' | github-release-version-checker distribution
+
+  # One line per runner, as a markdown table for a job summary
+  github-release-version-checker distribution runners.txt --format markdown >> "$GITHUB_STEP_SUMMARY"`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runDistribution,
+}
+
+func init() {
+	distributionCmd.Flags().StringVarP(&distributionRepo, "repo", "r", "", "repository the versions belong to (default: actions/runner)")
+	distributionCmd.Flags().StringVarP(&distributionToken, "token", "t", "", "GitHub token (default: GH_TOKEN, GITHUB_TOKEN, then gh's stored login)")
+	distributionCmd.Flags().StringVar(&distributionFormat, "format", distributionTerminal, "report format: terminal, json or markdown")
+	rootCmd.AddCommand(distributionCmd)
+}
+
+// versionCount is how many instances run one version
+type versionCount struct {
+	Version *semver.Version
+	Count   int
+}
+
+// distributionEntry is one version's share of the fleet
+type distributionEntry struct {
+	Version        string  `json:"version"`
+	Count          int     `json:"count"`
+	Percent        float64 `json:"percent"`
+	Status         string  `json:"status"`
+	ReleasesBehind int     `json:"releases_behind"`
+}
+
+// distributionReport summarises the versions in service across a fleet
+type distributionReport struct {
+	Repository      string              `json:"repository"`
+	LatestVersion   string              `json:"latest_version"`
+	Total           int                 `json:"total"`
+	LatestPercent   float64             `json:"latest_percent"`
+	WarningPercent  float64             `json:"warning_percent"`
+	CriticalPercent float64             `json:"critical_percent"`
+	ExpiredPercent  float64             `json:"expired_percent"`
+	UnknownPercent  float64             `json:"unknown_percent"`
+	OldestVersion   string              `json:"oldest_version"`
+	Versions        []distributionEntry `json:"versions"`
+}
+
+func runDistribution(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	w := cmd.OutOrStdout()
+
+	switch distributionFormat {
+	case distributionTerminal, distributionJSON, distributionMarkdown:
+	default:
+		return fmt.Errorf("invalid format %q: must be 'terminal', 'json' or 'markdown'", distributionFormat)
+	}
+
+	in := cmd.InOrStdin()
+	name := "standard input"
+	if len(args) == 1 && args[0] != "-" {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", args[0], err)
+		}
+		defer f.Close()
+		in, name = f, args[0]
+	}
+	counts, err := parseVersionCounts(in)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	if len(counts) == 0 {
+		return fmt.Errorf("%s: no versions", name)
+	}
+
+	repoName := distributionRepo
+	if repoName == "" {
+		repoName = "actions/runner"
+	}
+	repoConfig, err := lookupRepository(repoName)
+	if err != nil {
+		return err
+	}
+
+	ghClient, resolved, err := newAPIHosts(nil, distributionToken).client(repoConfig)
+	if err != nil {
+		return err
+	}
+	token := resolved.Value
+	versionChecker := newChecker(&memoClient{GitHubClient: ghClient}, repoConfig)
+
+	analyses := make(map[string]*checker.Analysis, len(counts))
+	for _, c := range counts {
+		analysis, err := analyseTraced(cmd.Context(), versionChecker, repoConfig.FullName(), c.Version.Original())
+		var notFound *checker.VersionNotFoundError
+		switch {
+		case errors.As(err, &notFound):
+			continue
+		case err != nil:
+			return withToken(err, token)
+		}
+		analyses[c.Version.String()] = analysis
+	}
+
+	report := buildDistribution(repoConfig.FullName(), counts, analyses)
+	switch distributionFormat {
+	case distributionJSON:
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, string(data))
+	case distributionMarkdown:
+		printDistributionMarkdown(w, report)
+	default:
+		printDistributionTerminal(w, report)
+	}
+	return nil
+}
+
+// parseVersionCounts reads "VERSION [COUNT]" lines, adding up repeated versions.
+// Versions are normalised as --compare values are.
+func parseVersionCounts(r io.Reader) ([]versionCount, error) {
+	var counts []versionCount
+	index := make(map[string]int)
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.FieldsFunc(text, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' })
+		if len(fields) > 2 {
+			return nil, fmt.Errorf("line %d: want a version and an optional count, got %q", line, text)
+		}
+
+		version, err := checker.ParseComparisonVersion(fields[0], checker.NormaliseAll)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		count := 1
+		if len(fields) == 2 {
+			if count, err = strconv.Atoi(fields[1]); err != nil || count < 0 {
+				return nil, fmt.Errorf("line %d: invalid count %q", line, fields[1])
+			}
+		}
+
+		key := version.String()
+		if i, ok := index[key]; ok {
+			counts[i].Count += count
+			continue
+		}
+		index[key] = len(counts)
+		counts = append(counts, versionCount{Version: version, Count: count})
+	}
+	return counts, scanner.Err()
+}
+
+// buildDistribution works out each status's share of the fleet from the analyses
+// of its versions, keyed by version; versions without an analysis are unknown
+func buildDistribution(repo string, counts []versionCount, analyses map[string]*checker.Analysis) distributionReport {
+	report := distributionReport{Repository: repo}
+	for _, c := range counts {
+		report.Total += c.Count
+	}
+
+	sorted := append([]versionCount(nil), counts...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return types.CompareVersions(sorted[i].Version, sorted[j].Version) > 0
+	})
+
+	byStatus := make(map[string]int)
+	for _, c := range sorted {
+		entry := distributionEntry{
+			Version: c.Version.String(),
+			Count:   c.Count,
+			Percent: percentOf(c.Count, report.Total),
+			Status:  statusUnknown,
+		}
+		if analysis, ok := analyses[c.Version.String()]; ok {
+			entry.Status = string(analysis.Status())
+			entry.ReleasesBehind = analysis.ReleasesBehind
+			report.LatestVersion = analysis.LatestVersion.String()
+			if c.Count > 0 {
+				report.OldestVersion = entry.Version
+			}
+		}
+		byStatus[entry.Status] += c.Count
+		report.Versions = append(report.Versions, entry)
+	}
+
+	report.LatestPercent = percentOf(byStatus[string(checker.StatusCurrent)], report.Total)
+	report.WarningPercent = percentOf(byStatus[string(checker.StatusWarning)], report.Total)
+	report.CriticalPercent = percentOf(byStatus[string(checker.StatusCritical)], report.Total)
+	report.ExpiredPercent = percentOf(byStatus[string(checker.StatusExpired)], report.Total)
+	report.UnknownPercent = percentOf(byStatus[statusUnknown], report.Total)
+	return report
+}
+
+// percentOf returns n as a percentage of total, to one decimal place
+func percentOf(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(n)*1000/float64(total)) / 10
+}
+
+// distributionStatusText names a version's status in the report
+func distributionStatusText(status string) string {
+	if status == statusUnknown {
+		return "Not a release"
+	}
+	return render.StatusText(checker.Status(status))
+}
+
+// printDistributionTerminal writes the report for the terminal
+func printDistributionTerminal(w io.Writer, r distributionReport) {
+	cyan.Fprintf(w, "📊 %s: %d instances\n", r.Repository, r.Total)
+	cyan.Fprintln(w, "─────────────────────────────────────")
+	fmt.Fprintf(w, "Latest version: %s\n", r.LatestVersion)
+	green.Fprintf(w, "On latest:      %.1f%%\n", r.LatestPercent)
+	fmt.Fprintf(w, "Behind:         %.1f%%\n", r.WarningPercent)
+	yellow.Fprintf(w, "Critical:       %.1f%%\n", r.CriticalPercent)
+	red.Fprintf(w, "Expired:        %.1f%%\n", r.ExpiredPercent)
+	if r.UnknownPercent > 0 {
+		fmt.Fprintf(w, "Not a release:  %.1f%%\n", r.UnknownPercent)
+	}
+	fmt.Fprintf(w, "Oldest in use:  %s\n", r.OldestVersion)
+
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "%-12s %7s %7s  %s\n", "Version", "Count", "Share", "Status")
+	for _, e := range r.Versions {
+		fmt.Fprintf(w, "%-12s %7d %6.1f%%  %s\n", e.Version, e.Count, e.Percent, distributionStatusText(e.Status))
+	}
+}
+
+// printDistributionMarkdown writes the report as markdown, e.g. for a job summary
+func printDistributionMarkdown(w io.Writer, r distributionReport) {
+	fmt.Fprintf(w, "## Version Distribution: %s\n\n", r.Repository)
+	fmt.Fprintf(w, "%d instances; latest version is **%s**, oldest in use is **%s**.\n\n", r.Total, r.LatestVersion, r.OldestVersion)
+	fmt.Fprintln(w, "| On latest | Behind | Critical | Expired | Not a release |")
+	fmt.Fprintln(w, "|----------:|-------:|---------:|--------:|--------------:|")
+	fmt.Fprintf(w, "| %.1f%% | %.1f%% | %.1f%% | %.1f%% | %.1f%% |\n\n",
+		r.LatestPercent, r.WarningPercent, r.CriticalPercent, r.ExpiredPercent, r.UnknownPercent)
+	fmt.Fprintln(w, "| Version | Count | Share | Status |")
+	fmt.Fprintln(w, "|---------|------:|------:|--------|")
+	for _, e := range r.Versions {
+		fmt.Fprintf(w, "| %s | %d | %.1f%% | %s |\n", e.Version, e.Count, e.Percent, distributionStatusText(e.Status))
+	}
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/nickromney-org/github-release-version-checker/pkg/checker"
+)
+
+func TestParseVersionCounts(t *testing.T) {
+	input := `# runner versions
+2.329.0 40
+v2.328.0,10
+
+2.328.0	2
+2.326.0
+ V2.326.0 `
+
+	counts, err := parseVersionCounts(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]int{"2.329.0": 40, "2.328.0": 12, "2.326.0": 2}
+	if len(counts) != len(want) {
+		t.Fatalf("got %d versions, want %d", len(counts), len(want))
+	}
+	for _, c := range counts {
+		if want[c.Version.String()] != c.Count {
+			t.Errorf("%s: count %d, want %d", c.Version, c.Count, want[c.Version.String()])
+		}
+	}
+}
+
+func TestParseVersionCounts_Errors(t *testing.T) {
+	tests := []struct {
+		input   string
+		wantErr string
+	}{
+		{"2.328.0 many", `line 1: invalid count "many"`},
+		{"2.328.0\n2.327.0 -1", `line 2: invalid count "-1"`},
+		{"2.328.0 1 2", "line 1: want a version and an optional count"},
+		{"latest 3", "line 1: invalid comparison version"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := parseVersionCounts(strings.NewReader(tt.input))
+			if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want prefix %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBuildDistribution(t *testing.T) {
+	counts, err := parseVersionCounts(strings.NewReader("2.328.0 12\n2.329.0 40\n2.326.0 3\n2.999.0 1\n2.327.0 4\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	analyses := map[string]*checker.Analysis{
+		"2.329.0": compareStatusResult("2.329.0", checker.StatusCurrent).Analysis,
+		"2.328.0": compareStatusResult("2.328.0", checker.StatusWarning).Analysis,
+		"2.327.0": compareStatusResult("2.327.0", checker.StatusCritical).Analysis,
+		"2.326.0": compareStatusResult("2.326.0", checker.StatusExpired).Analysis,
+	}
+
+	report := buildDistribution("actions/runner", counts, analyses)
+
+	if report.Total != 60 || report.LatestVersion != "2.329.0" || report.OldestVersion != "2.326.0" {
+		t.Errorf("total %d, latest %s, oldest %s; want 60, 2.329.0, 2.326.0", report.Total, report.LatestVersion, report.OldestVersion)
+	}
+	percents := []struct {
+		name      string
+		got, want float64
+	}{
+		{"latest", report.LatestPercent, 66.7},
+		{"warning", report.WarningPercent, 20},
+		{"critical", report.CriticalPercent, 6.7},
+		{"expired", report.ExpiredPercent, 5},
+		{"unknown", report.UnknownPercent, 1.7},
+	}
+	for _, p := range percents {
+		if p.got != p.want {
+			t.Errorf("%s percent = %v, want %v", p.name, p.got, p.want)
+		}
+	}
+
+	var order []string
+	for _, e := range report.Versions {
+		order = append(order, e.Version+"="+e.Status)
+	}
+	want := "2.999.0=unknown 2.329.0=current 2.328.0=warning 2.327.0=critical 2.326.0=expired"
+	if got := strings.Join(order, " "); got != want {
+		t.Errorf("versions = %s, want %s", got, want)
+	}
+
+	var buf bytes.Buffer
+	printDistributionMarkdown(&buf, report)
+	for _, line := range []string{
+		"## Version Distribution: actions/runner",
+		"| 66.7% | 20.0% | 6.7% | 5.0% | 1.7% |",
+		"| 2.326.0 | 3 | 5.0% | Expired |",
+		"| 2.999.0 | 1 | 1.7% | Not a release |",
+	} {
+		if !strings.Contains(buf.String(), line) {
+			t.Errorf("markdown missing %q:\n%s", line, buf.String())
+		}
+	}
+}
@@ -0,0 +1,321 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/nickromney-org/github-release-version-checker/internal/data"
+	"github.com/nickromney-org/github-release-version-checker/pkg/client"
+	"github.com/nickromney-org/github-release-version-checker/pkg/render"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+// checkResult is the outcome of a single doctor check
+type checkResult string
+
+const (
+	checkPass checkResult = "pass"
+	checkWarn checkResult = "warn"
+	checkFail checkResult = "fail"
+)
+
+// doctorCheck is one line of the doctor report
+type doctorCheck struct {
+	Name   string
+	Result checkResult
+	Detail string
+	Hint   string // Remediation shown for warnings and failures
+}
+
+// embeddedCacheMaxAgeDays is how old the embedded cache may be before doctor
+// warns, when GitHub cannot be asked for the latest release
+const embeddedCacheMaxAgeDays = 90
+
+var (
+	doctorToken string
+	doctorCache string
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose token, API and cache problems",
+	Long: `Check the environment the version checker depends on and suggest fixes.
+
+Checks token validity and scopes, GitHub API reachability, rate-limit status,
+GitHub CLI presence, cache directory writability, and embedded cache freshness.
+Exits non-zero if any check fails.`,
+	Example: `  github-release-version-checker doctor
+  github-release-version-checker doctor --cache ./releases.json`,
+	Args: cobra.NoArgs,
+	RunE: runDoctor,
+}
+
+func init() {
+	doctorCmd.Flags().StringVarP(&doctorToken, "token", "t", "", "GitHub token (default: GH_TOKEN, GITHUB_TOKEN, then gh's stored login)")
+	doctorCmd.Flags().StringVar(&doctorCache, "cache", "", "custom cache file whose directory should be writable")
+	rootCmd.AddCommand(doctorCmd)
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	w := cmd.OutOrStdout()
+	ctx := cmd.Context()
+
+	resolved := detectGitHubToken(doctorToken, defaultGitHubHost)
+	token := resolved.Value
+	ghClient := newGitHubClient(token, "actions", "runner")
+
+	checks := []doctorCheck{checkTokenPresent(token, resolved.describe())}
+
+	status, err := ghClient.GetAuthStatus(ctx)
+	checks = append(checks, checkAPI(token, status, err)...)
+	checks = append(checks, checkGitHubCLI())
+
+	cacheDir := filepath.Dir(doctorCache)
+	if doctorCache == "" {
+		cacheDir = defaultCacheDir()
+	}
+	checks = append(checks, checkCacheDir(cacheDir))
+
+	// Only ask GitHub for the latest release if the API is usable
+	var latest *types.Release
+	if err == nil && status.Remaining > 0 {
+		latest, _ = ghClient.GetLatestRelease(ctx)
+	}
+	checks = append(checks, checkEmbeddedCache(latest, time.Now()))
+
+	failures := printDoctorReport(w, checks)
+	if failures > 0 {
+		return fmt.Errorf("doctor found %d problem%s", failures, types.PluralSuffix(failures))
+	}
+	return nil
+}
+
+// printDoctorReport writes the checks with remediation hints, returning the failure count
+func printDoctorReport(w io.Writer, checks []doctorCheck) int {
+	cyan.Fprintln(w, "🩺 Environment Check")
+	cyan.Fprintln(w, "─────────────────────────────────────")
+
+	failures := 0
+	for _, check := range checks {
+		switch check.Result {
+		case checkPass:
+			green.Fprintf(w, "✅ %-18s", check.Name)
+		case checkWarn:
+			yellow.Fprintf(w, "⚠️  %-18s", check.Name)
+		case checkFail:
+			red.Fprintf(w, "❌ %-18s", check.Name)
+			failures++
+		}
+		fmt.Fprintf(w, " %s\n", check.Detail)
+
+		if check.Hint != "" && check.Result != checkPass {
+			fmt.Fprintf(w, "   💡 %s\n", check.Hint)
+		}
+	}
+	return failures
+}
+
+// checkTokenPresent reports whether any token source supplied a token, and which
+func checkTokenPresent(token, source string) doctorCheck {
+	check := doctorCheck{Name: "GitHub token"}
+	if token == "" {
+		check.Result = checkWarn
+		check.Detail = "not found, using unauthenticated requests (60 per hour)"
+		check.Hint = "use -t, set GH_TOKEN or GITHUB_TOKEN, add api.github.com to ~/.netrc, or run gh auth login"
+		return check
+	}
+	check.Result = checkPass
+	check.Detail = "found via " + source
+	return check
+}
+
+// checkAPI turns the rate limit response into reachability, token and rate-limit checks
+func checkAPI(token string, status *client.AuthStatus, err error) []doctorCheck {
+	if errors.Is(err, client.ErrBadCredentials) {
+		return []doctorCheck{
+			{Name: "API reachability", Result: checkPass, Detail: "api.github.com responded"},
+			{
+				Name:   "Token validity",
+				Result: checkFail,
+				Detail: "GitHub rejected the token (401 Bad credentials)",
+				Hint:   "create a new token at https://github.com/settings/tokens or run gh auth login",
+			},
+		}
+	}
+	if err != nil {
+		return []doctorCheck{{
+			Name:   "API reachability",
+			Result: checkFail,
+			Detail: err.Error(),
+			Hint:   "check network access to api.github.com, and HTTPS_PROXY if you are behind a proxy",
+		}}
+	}
+
+	checks := []doctorCheck{{Name: "API reachability", Result: checkPass, Detail: "api.github.com responded"}}
+
+	if token != "" {
+		checks = append(checks, doctorCheck{Name: "Token validity", Result: checkPass, Detail: "accepted by GitHub"})
+
+		scopes := doctorCheck{Name: "Token scopes", Result: checkPass}
+		switch {
+		case !status.ScopesReported:
+			scopes.Detail = "not reported (fine-grained, GitHub App or Actions token)"
+		case len(status.Scopes) == 0:
+			scopes.Detail = "none (enough for public repositories)"
+		default:
+			scopes.Detail = strings.Join(status.Scopes, ", ")
+		}
+		checks = append(checks, scopes)
+	}
+
+	return append(checks, checkRateLimit(status))
+}
+
+// checkRateLimit warns when few API requests remain
+func checkRateLimit(status *client.AuthStatus) doctorCheck {
+	check := doctorCheck{
+		Name:   "Rate limit",
+		Result: checkPass,
+		Detail: fmt.Sprintf("%s/%s requests left", formatThousands(status.Remaining), formatThousands(status.Limit)),
+	}
+	if !status.Reset.IsZero() {
+		check.Detail += ", resets " + formatTimestamp(status.Reset.UTC())
+	}
+
+	switch {
+	case status.Remaining == 0:
+		check.Result = checkFail
+		check.Hint = "wait for the reset, or authenticate to raise the limit to 5,000 per hour"
+	case status.Limit > 0 && status.Remaining*10 < status.Limit:
+		check.Result = checkWarn
+		check.Hint = "fewer than 10% of requests left; large repositories may need several"
+	}
+	return check
+}
+
+// checkGitHubCLI reports whether the gh CLI is installed and logged in
+func checkGitHubCLI() doctorCheck {
+	check := doctorCheck{Name: "GitHub CLI"}
+	if _, err := exec.LookPath("gh"); err != nil {
+		check.Result = checkWarn
+		check.Detail = "gh not found (optional, used to find a token)"
+		check.Hint = "install from https://cli.github.com"
+		return check
+	}
+	if _, err := getGitHubCLIToken(defaultGitHubHost); err != nil {
+		check.Result = checkWarn
+		check.Detail = "installed but not logged in"
+		check.Hint = "run gh auth login"
+		return check
+	}
+	check.Result = checkPass
+	check.Detail = "installed and logged in"
+	return check
+}
+
+// defaultCacheDir is where cache files live when --cache is not given
+func defaultCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return os.TempDir()
+	}
+	return filepath.Join(dir, "github-release-version-checker")
+}
+
+// checkCacheDir checks that dir, or its nearest existing parent, is writable
+// without creating anything that is not cleaned up
+func checkCacheDir(dir string) doctorCheck {
+	check := doctorCheck{Name: "Cache directory", Detail: dir}
+
+	existing := dir
+	for {
+		info, err := os.Stat(existing)
+		if err == nil {
+			if !info.IsDir() {
+				check.Result = checkFail
+				check.Detail = fmt.Sprintf("%s is not a directory", existing)
+				check.Hint = "point --cache at a file inside a directory"
+				return check
+			}
+			break
+		}
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			break
+		}
+		existing = parent
+	}
+
+	f, err := os.CreateTemp(existing, ".doctor-*")
+	if err != nil {
+		check.Result = checkFail
+		check.Detail = fmt.Sprintf("%s is not writable", existing)
+		check.Hint = "fix the directory permissions or point --cache at a writable location"
+		return check
+	}
+	f.Close()
+	os.Remove(f.Name())
+
+	check.Result = checkPass
+	check.Detail = dir + " is writable"
+	return check
+}
+
+// checkEmbeddedCache compares the embedded release data with the latest release on GitHub
+func checkEmbeddedCache(latest *types.Release, now time.Time) doctorCheck {
+	check := doctorCheck{Name: "Embedded cache"}
+
+	generatedAt, err := data.EmbeddedGeneratedAt()
+	if err != nil {
+		check.Result = checkFail
+		check.Detail = fmt.Sprintf("cannot be read: %v", err)
+		check.Hint = "rebuild the binary"
+		return check
+	}
+	embedded, err := data.LoadEmbeddedReleases()
+	if err != nil || len(embedded) == 0 {
+		check.Result = checkFail
+		check.Detail = "contains no releases"
+		check.Hint = "rebuild the binary"
+		return check
+	}
+
+	embeddedLatest := embedded[0].Version
+	for _, r := range embedded {
+		if types.CompareVersions(r.Version, embeddedLatest) > 0 {
+			embeddedLatest = r.Version
+		}
+	}
+
+	return evaluateEmbeddedCache(generatedAt, embeddedLatest, latest, now)
+}
+
+// evaluateEmbeddedCache judges embedded cache freshness from its age and latest version
+func evaluateEmbeddedCache(generatedAt time.Time, embeddedLatest *semver.Version, latest *types.Release, now time.Time) doctorCheck {
+	ageDays := int(now.Sub(generatedAt).Hours() / 24)
+	check := doctorCheck{
+		Name:   "Embedded cache",
+		Result: checkPass,
+		Detail: fmt.Sprintf("generated %s (%s), latest v%s", formatDate(generatedAt), render.FormatDaysAgo(ageDays), embeddedLatest),
+	}
+
+	switch {
+	case latest != nil && types.CompareVersions(latest.Version, embeddedLatest) > 0:
+		check.Result = checkWarn
+		check.Detail += fmt.Sprintf(", GitHub has v%s", types.FormatVersion(latest.Version))
+		check.Hint = "newer releases are fetched from the API at run time; rebuild or use --cache for offline use"
+	case latest == nil && ageDays > embeddedCacheMaxAgeDays:
+		check.Result = checkWarn
+		check.Hint = fmt.Sprintf("older than %d days and GitHub could not be checked; rebuild to refresh it", embeddedCacheMaxAgeDays)
+	}
+	return check
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nickromney-org/github-release-version-checker/pkg/client"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+func TestCheckAPI(t *testing.T) {
+	tests := []struct {
+		name   string
+		token  string
+		status *client.AuthStatus
+		err    error
+		want   map[string]checkResult
+	}{
+		{
+			name:  "unreachable",
+			token: "ghp_test",
+			err:   errors.New("dial tcp: no such host"),
+			want:  map[string]checkResult{"API reachability": checkFail},
+		},
+		{
+			name:  "bad credentials",
+			token: "ghp_test",
+			err:   client.ErrBadCredentials,
+			want:  map[string]checkResult{"API reachability": checkPass, "Token validity": checkFail},
+		},
+		{
+			name:   "healthy token",
+			token:  "ghp_test",
+			status: &client.AuthStatus{ScopesReported: true, Remaining: 4900, Limit: 5000},
+			want: map[string]checkResult{
+				"API reachability": checkPass,
+				"Token validity":   checkPass,
+				"Token scopes":     checkPass,
+				"Rate limit":       checkPass,
+			},
+		},
+		{
+			name:   "unauthenticated and nearly exhausted",
+			status: &client.AuthStatus{Remaining: 5, Limit: 60},
+			want:   map[string]checkResult{"API reachability": checkPass, "Rate limit": checkWarn},
+		},
+		{
+			name:   "rate limit exhausted",
+			status: &client.AuthStatus{Remaining: 0, Limit: 60},
+			want:   map[string]checkResult{"API reachability": checkPass, "Rate limit": checkFail},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checks := checkAPI(tt.token, tt.status, tt.err)
+			if len(checks) != len(tt.want) {
+				t.Fatalf("got %d checks, want %d: %+v", len(checks), len(tt.want), checks)
+			}
+			for _, check := range checks {
+				if want, ok := tt.want[check.Name]; !ok || check.Result != want {
+					t.Errorf("%s = %s, want %s", check.Name, check.Result, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckCacheDir(t *testing.T) {
+	dir := t.TempDir()
+
+	// A directory that does not exist yet is judged by its nearest parent
+	if check := checkCacheDir(filepath.Join(dir, "missing", "cache")); check.Result != checkPass {
+		t.Errorf("expected pass for creatable directory, got %+v", check)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "missing")); !os.IsNotExist(err) {
+		t.Error("expected checkCacheDir not to create directories")
+	}
+
+	file := filepath.Join(dir, "file")
+	if err := os.WriteFile(file, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if check := checkCacheDir(file); check.Result != checkFail {
+		t.Errorf("expected fail for a file, got %+v", check)
+	}
+}
+
+func TestEvaluateEmbeddedCache(t *testing.T) {
+	now := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	generatedAt := now.AddDate(0, 0, -100)
+	embeddedLatest := mustParseVersion("2.329.0")
+
+	tests := []struct {
+		name   string
+		latest *types.Release
+		want   checkResult
+	}{
+		{name: "matches GitHub", latest: &types.Release{Version: mustParseVersion("2.329.0")}, want: checkPass},
+		{name: "behind GitHub", latest: &types.Release{Version: mustParseVersion("2.330.0")}, want: checkWarn},
+		{name: "old and GitHub unknown", want: checkWarn},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := evaluateEmbeddedCache(generatedAt, embeddedLatest, tt.latest, now)
+			if check.Result != tt.want {
+				t.Errorf("result = %s, want %s (%s)", check.Result, tt.want, check.Detail)
+			}
+		})
+	}
+}
+
+func TestPrintDoctorReport(t *testing.T) {
+	var buf bytes.Buffer
+	failures := printDoctorReport(&buf, []doctorCheck{
+		{Name: "GitHub token", Result: checkPass, Detail: "found", Hint: "not shown"},
+		{Name: "Rate limit", Result: checkFail, Detail: "0/60 requests left", Hint: "wait for the reset"},
+	})
+
+	if failures != 1 {
+		t.Errorf("failures = %d, want 1", failures)
+	}
+	output := buf.String()
+	if strings.Contains(output, "not shown") {
+		t.Errorf("expected hints to be hidden for passing checks, got:\n%s", output)
+	}
+	if !strings.Contains(output, "💡 wait for the reset") {
+		t.Errorf("expected remediation hint, got:\n%s", output)
+	}
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// envSkippedFlags are flags environment variables cannot set: the modes reading
+// them choose their own output
+var envSkippedFlags = map[string]bool{
+	"ci": true, "json": true, "fields": true, "query": true, "version": true, "help": true,
+}
+
+// flagVariables returns flag values, by flag name, read from the environment
+// variable variable(name) for each flag and from the variables aliases name.
+// Empty variables are unset.
+func flagVariables(flags *pflag.FlagSet, lookup func(string) (string, bool), variable func(string) string, aliases map[string]string) map[string]string {
+	get := func(name string) (string, bool) {
+		value, ok := lookup(name)
+		value = strings.TrimSpace(value)
+		return value, ok && value != ""
+	}
+
+	values := make(map[string]string)
+	flags.VisitAll(func(f *pflag.Flag) {
+		if envSkippedFlags[f.Name] {
+			return
+		}
+		if value, ok := get(variable(f.Name)); ok {
+			values[f.Name] = value
+		}
+	})
+	for name, flag := range aliases {
+		if value, ok := get(name); ok {
+			values[flag] = value
+		}
+	}
+	return values
+}
+
+// setFlags sets each flag to its value, in name order so the first invalid
+// one is always the one reported
+func setFlags(flags *pflag.FlagSet, values map[string]string, describe func(string) string) error {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := flags.Set(name, values[name]); err != nil {
+			return fmt.Errorf("invalid %s: %w", describe(name), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,262 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/nickromney-org/github-release-version-checker/pkg/checker"
+	"github.com/nickromney-org/github-release-version-checker/pkg/client"
+)
+
+// Error codes in the JSON error envelope
+const (
+	errorCodeInvalidInput       = "invalid_input"        // Bad flags or arguments
+	errorCodeInvalidConfig      = "invalid_config"       // Unreadable or invalid config file
+	errorCodeInvalidVersion     = "invalid_version"      // Comparison version is not semver
+	errorCodeVersionNotFound    = "version_not_found"    // Comparison version is not a release
+	errorCodeRepositoryNotFound = "repository_not_found" // Missing, or hidden from the token
+	errorCodeBadCredentials     = "bad_credentials"      // Token rejected
+	errorCodeNoAccess           = "no_access"            // Token cannot read the repository
+	errorCodeRateLimited        = "rate_limited"         // Primary or secondary rate limit
+	errorCodeNetwork            = "network"              // GitHub could not be reached
+	errorCodeInternal           = "internal"             // Anything else
+)
+
+// jsonError is the error object in the JSON error envelope
+type jsonError struct {
+	Code      string         `json:"code"`
+	Message   string         `json:"message"`
+	Details   map[string]any `json:"details,omitempty"`
+	Retryable bool           `json:"retryable"` // Whether the same command may succeed later
+}
+
+// inputError marks an error as caused by the command line
+type inputError struct{ err error }
+
+func (e *inputError) Error() string { return e.err.Error() }
+func (e *inputError) Unwrap() error { return e.err }
+
+// invalidInput marks err as a problem with flags or arguments
+func invalidInput(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &inputError{err}
+}
+
+// configError marks an error as caused by the config file
+type configError struct{ err error }
+
+func (e *configError) Error() string { return e.err.Error() }
+func (e *configError) Unwrap() error { return e.err }
+
+// explainedError replaces an error's message with an explanation, keeping the
+// original for errors.Is and errors.As
+type explainedError struct {
+	message string
+	cause   error
+}
+
+func (e *explainedError) Error() string { return e.message }
+func (e *explainedError) Unwrap() error { return e.cause }
+
+// requestError records whether the failed GitHub requests carried a token
+type requestError struct {
+	err           error
+	authenticated bool
+}
+
+func (e *requestError) Error() string { return e.err.Error() }
+func (e *requestError) Unwrap() error { return e.err }
+
+// withToken records whether err came from requests made with a token
+func withToken(err error, token string) error {
+	if err == nil {
+		return nil
+	}
+	return &requestError{err: err, authenticated: token != ""}
+}
+
+// rateLimitDetails returns the structured details of a rate-limit error
+func rateLimitDetails(err error, limits *client.RateLimitDetails) map[string]any {
+	details := map[string]any{"secondary": limits.Secondary}
+	if limits.Limit > 0 {
+		details["limit"] = limits.Limit
+		details["remaining"] = limits.Remaining
+	}
+	if !limits.Reset.IsZero() {
+		details["reset"] = limits.Reset.UTC().Format(time.RFC3339)
+	}
+	if limits.RetryAfter > 0 {
+		details["retry_after_seconds"] = int(limits.RetryAfter.Seconds())
+	}
+	var request *requestError
+	if errors.As(err, &request) {
+		details["authenticated"] = request.authenticated
+	}
+	return details
+}
+
+// classifyError turns an error into the JSON error object
+func classifyError(err error) jsonError {
+	e := jsonError{Code: errorCodeInternal, Message: err.Error()}
+
+	var input *inputError
+	var config *configError
+	var notFound *checker.VersionNotFoundError
+	var invalidVersion *checker.InvalidVersionError
+	var noChannel *checker.ChannelNotFoundError
+	var urlErr *url.Error
+	var netErr net.Error
+
+	switch {
+	case errors.As(err, &input):
+		e.Code = errorCodeInvalidInput
+	case errors.As(err, &config):
+		e.Code = errorCodeInvalidConfig
+	case errors.As(err, &notFound):
+		e.Code = errorCodeVersionNotFound
+		e.Details = map[string]any{
+			"version":        notFound.Version.String(),
+			"latest_version": notFound.Latest.String(),
+		}
+		if len(notFound.Nearest) > 0 {
+			nearest := make([]string, len(notFound.Nearest))
+			for i, v := range notFound.Nearest {
+				nearest[i] = v.String()
+			}
+			e.Details["nearest_versions"] = nearest
+		}
+	case errors.As(err, &noChannel):
+		e.Code = errorCodeVersionNotFound
+		e.Details = map[string]any{
+			"channel":        noChannel.Channel,
+			"behind":         noChannel.Behind,
+			"available":      noChannel.Available,
+			"latest_version": noChannel.Latest.String(),
+		}
+	case errors.As(err, &invalidVersion):
+		e.Code = errorCodeInvalidVersion
+		e.Details = map[string]any{"input": invalidVersion.Input, "normalised": invalidVersion.Normalised}
+		if len(invalidVersion.Applied) > 0 {
+			e.Details["normalisation"] = invalidVersion.Applied
+		}
+	case errors.Is(err, semver.ErrInvalidSemVer):
+		e.Code = errorCodeInvalidVersion
+	case client.IsRateLimited(err):
+		limits, _ := client.RateLimitInfo(err)
+		e.Code = errorCodeRateLimited
+		e.Details = rateLimitDetails(err, limits)
+		e.Retryable = true
+	case errors.Is(err, client.ErrBadCredentials):
+		e.Code = errorCodeBadCredentials
+	case errors.Is(err, client.ErrNoAccess):
+		e.Code = errorCodeNoAccess
+	case errors.Is(err, client.ErrNotFound):
+		e.Code = errorCodeRepositoryNotFound
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &urlErr), errors.As(err, &netErr):
+		e.Code = errorCodeNetwork
+		e.Retryable = true
+	}
+	return e
+}
+
+// outputErrorJSON writes the JSON error envelope: {"success": false, "error": {...}}
+func outputErrorJSON(w io.Writer, err error) {
+	data, marshalErr := json.MarshalIndent(struct {
+		Success bool      `json:"success"`
+		Error   jsonError `json:"error"`
+	}{false, classifyError(err)}, "", "  ")
+	if marshalErr != nil {
+		fmt.Fprintf(w, "{\"success\": false, \"error\": {\"code\": %q, \"message\": %q, \"retryable\": false}}\n", errorCodeInternal, err.Error())
+		return
+	}
+	fmt.Fprintln(w, string(data))
+}
+
+// printRateLimitHelp explains a rate-limit failure in the terminal
+func printRateLimitHelp(w io.Writer, limits *client.RateLimitDetails, authenticated bool) {
+	red.Fprintf(w, "\n❌ Error: Unable to fetch release information from GitHub API\n\n")
+
+	switch {
+	case limits.Secondary:
+		yellow.Fprintln(w, "⚠️  GitHub API Secondary Rate Limit Exceeded")
+		yellow.Fprintln(w)
+		yellow.Fprintln(w, "   GitHub asked for a pause after too many requests in a short time.")
+		if limits.RetryAfter > 0 {
+			yellow.Fprintf(w, "   Retry after: %s\n", limits.RetryAfter.Round(time.Second))
+		}
+	case authenticated:
+		yellow.Fprintln(w, "⚠️  GitHub API Rate Limit Exceeded")
+		yellow.Fprintln(w)
+		if limits.Limit > 0 {
+			yellow.Fprintf(w, "   The token's limit of %d requests per hour has been used up.\n", limits.Limit)
+		} else {
+			yellow.Fprintln(w, "   The token's hourly request limit has been used up.")
+		}
+	default:
+		yellow.Fprintln(w, "⚠️  GitHub API Rate Limit Exceeded")
+		yellow.Fprintln(w)
+		yellow.Fprintln(w, "   Unauthenticated requests are limited to 60 per hour.")
+		yellow.Fprintln(w, "   Authenticated requests get 5,000 per hour.")
+		yellow.Fprintln(w)
+		yellow.Fprintln(w, "💡 Authentication options (auto-detected in order):")
+		yellow.Fprintln(w, "   1. Use the -t flag: github-release-version-checker -t YOUR_TOKEN")
+		yellow.Fprintln(w, "   2. Set GH_TOKEN or GITHUB_TOKEN environment variable")
+		yellow.Fprintln(w, "   3. GitHub CLI: gh auth login (hosts.yml read automatically)")
+		yellow.Fprintln(w, "   4. GitHub Actions: GITHUB_TOKEN is auto-available")
+		yellow.Fprintln(w)
+		yellow.Fprintln(w, "   Create a token at: https://github.com/settings/tokens")
+		yellow.Fprintln(w, "   (Only needs 'public_repo' read access)")
+	}
+
+	if !limits.Reset.IsZero() {
+		yellow.Fprintf(w, "\n   Rate limit resets in: %s (%s)\n",
+			time.Until(limits.Reset).Round(time.Second), limits.Reset.UTC().Format("15:04 UTC"))
+	}
+}
+
+// outputCIError reports an error in CI mode. Rate limits get an annotation with
+// their details; every error sets the error step outputs.
+func outputCIError(w io.Writer, err error) error {
+	e := classifyError(err)
+	if outputFile := os.Getenv("GITHUB_OUTPUT"); outputFile != "" {
+		if writeErr := appendGitHubOutputs(outputFile, ciErrorOutputs(e)); writeErr != nil {
+			fmt.Fprintf(w, "::warning::Failed to write step outputs: %v\n", writeErr)
+		}
+	}
+
+	if e.Code != errorCodeRateLimited {
+		return fmt.Errorf("%v", err)
+	}
+	var fields []string
+	for _, key := range sortedKeys(e.Details) {
+		fields = append(fields, fmt.Sprintf("%s=%v", key, e.Details[key]))
+	}
+	fmt.Fprintf(w, "::error title=GitHub API rate limit exceeded::%s (%s)\n", e.Message, strings.Join(fields, ", "))
+	return &exitError{code: 1}
+}
+
+// ciErrorOutputs returns the step outputs for an error: its code, whether it is
+// retryable and, for rate limits, the limit details
+func ciErrorOutputs(e jsonError) [][2]string {
+	outputs := [][2]string{
+		{"error_code", e.Code},
+		{"error_retryable", fmt.Sprintf("%t", e.Retryable)},
+	}
+	if e.Code == errorCodeRateLimited {
+		for _, key := range sortedKeys(e.Details) {
+			outputs = append(outputs, [2]string{"rate_limit_" + key, fmt.Sprintf("%v", e.Details[key])})
+		}
+	}
+	return outputs
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/nickromney-org/github-release-version-checker/pkg/checker"
+	"github.com/nickromney-org/github-release-version-checker/pkg/client"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name          string
+		err           error
+		wantCode      string
+		wantRetryable bool
+	}{
+		{"invalid input", invalidInput(errors.New("max-width must be non-negative")), errorCodeInvalidInput, false},
+		{"invalid config", &configError{errors.New("bad YAML")}, errorCodeInvalidConfig, false},
+		{"invalid version", fmt.Errorf("invalid comparison version %q: %w", "x", semver.ErrInvalidSemVer), errorCodeInvalidVersion, false},
+		{"channel not found", &checker.ChannelNotFoundError{Channel: "latest-5", Behind: 5, Available: 2, Latest: mustParseVersion("2.0.0")}, errorCodeVersionNotFound, false},
+		{"version not found", fmt.Errorf("analyse: %w", &checker.VersionNotFoundError{Version: mustParseVersion("1.0.0"), Latest: mustParseVersion("2.0.0")}), errorCodeVersionNotFound, false},
+		{"rate limited", fmt.Errorf("failed to fetch releases: %w", client.ErrRateLimitExhausted), errorCodeRateLimited, true},
+		{"bad credentials", tokenAccessError(client.ErrBadCredentials, "flag", "acme/app"), errorCodeBadCredentials, false},
+		{"no access", tokenAccessError(client.ErrNoAccess, "flag", "acme/app"), errorCodeNoAccess, false},
+		{"repository not found", tokenAccessError(client.ErrNotFound, "flag", "acme/private"), errorCodeRepositoryNotFound, false},
+		{"network", &url.Error{Op: "Get", URL: "https://api.github.com", Err: errors.New("connection refused")}, errorCodeNetwork, true},
+		{"timeout", fmt.Errorf("failed to fetch releases: %w", context.DeadlineExceeded), errorCodeNetwork, true},
+		{"other", errors.New("unexpected"), errorCodeInternal, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := classifyError(tt.err)
+			if got.Code != tt.wantCode || got.Retryable != tt.wantRetryable {
+				t.Errorf("classifyError() = %+v, want code %q retryable %v", got, tt.wantCode, tt.wantRetryable)
+			}
+			if got.Message != tt.err.Error() {
+				t.Errorf("Message = %q, want %q", got.Message, tt.err.Error())
+			}
+		})
+	}
+}
+
+func TestClassifyErrorVersionNotFoundDetails(t *testing.T) {
+	err := &checker.VersionNotFoundError{Version: mustParseVersion("2.327.99"), Latest: mustParseVersion("2.329.0"),
+		Nearest: []*semver.Version{mustParseVersion("2.327.1"), mustParseVersion("2.328.0")}}
+	got := classifyError(err)
+	if got.Details["version"] != "2.327.99" || got.Details["latest_version"] != "2.329.0" {
+		t.Errorf("Details = %v", got.Details)
+	}
+	if nearest, _ := got.Details["nearest_versions"].([]string); strings.Join(nearest, " ") != "2.327.1 2.328.0" {
+		t.Errorf("nearest_versions = %v, want 2.327.1 2.328.0", got.Details["nearest_versions"])
+	}
+}
+
+func TestClassifyErrorRateLimitDetails(t *testing.T) {
+	reset := time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC)
+	err := withToken(fmt.Errorf("failed to list releases: %w", &client.RateLimitExhaustedError{Limit: 60, Reset: reset}), "")
+
+	got := classifyError(err)
+	want := map[string]any{
+		"limit":         60,
+		"remaining":     0,
+		"reset":         "2025-01-02T15:04:05Z",
+		"secondary":     false,
+		"authenticated": false,
+	}
+	if !reflect.DeepEqual(got.Details, want) {
+		t.Errorf("Details = %v, want %v", got.Details, want)
+	}
+
+	outputs := ciErrorOutputs(got)
+	wantOutputs := [][2]string{
+		{"error_code", "rate_limited"},
+		{"error_retryable", "true"},
+		{"rate_limit_authenticated", "false"},
+		{"rate_limit_limit", "60"},
+		{"rate_limit_remaining", "0"},
+		{"rate_limit_reset", "2025-01-02T15:04:05Z"},
+		{"rate_limit_secondary", "false"},
+	}
+	if !reflect.DeepEqual(outputs, wantOutputs) {
+		t.Errorf("ciErrorOutputs() = %v, want %v", outputs, wantOutputs)
+	}
+}
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/nickromney-org/github-release-version-checker/pkg/checker"
+	"github.com/nickromney-org/github-release-version-checker/pkg/render"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+// exitDegraded is the exit code for results based on incomplete data, with --exit-degraded
+const exitDegraded = 3
+
+// Exit codes with --exit-status, distinct for each outcome so schedulers such
+// as Kubernetes can act on them; other failures exit 1
+const (
+	exitInvalid     = 2  // Invalid flags, arguments or config file
+	exitUnavailable = 4  // Could not check, but may succeed later, e.g. rate limited
+	exitWarning     = 10 // Behind the latest release
+	exitCritical    = 11
+	exitExpired     = 12
+)
+
+var (
+	exitDegradedFlag bool   // --exit-degraded
+	strict           bool   // --strict
+	failOn           string // --fail-on
+	exitStatus       bool   // --exit-status
+)
+
+// exitError ends the run with a specific exit code. A nil err exits quietly.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	if e.err == nil {
+		return fmt.Sprintf("exit status %d", e.code)
+	}
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+// ExitCode returns the process exit code for an error returned by Execute
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+	if exitStatus {
+		switch e := classifyError(err); {
+		case e.Code == errorCodeInvalidInput || e.Code == errorCodeInvalidConfig || e.Code == errorCodeInvalidVersion:
+			return exitInvalid
+		case e.Retryable:
+			return exitUnavailable
+		}
+	}
+	return 1
+}
+
+// isQuietExit reports whether err only carries an exit code, with nothing to print
+func isQuietExit(err error) bool {
+	var exitErr *exitError
+	return errors.As(err, &exitErr) && exitErr.err == nil
+}
+
+// degradedExit returns the --exit-degraded error when the results are degraded
+func degradedExit(degraded bool) error {
+	if exitDegradedFlag && degraded {
+		return &exitError{code: exitDegraded}
+	}
+	return nil
+}
+
+// strictExit fails a check that is not on the latest version when --strict is set
+func strictExit(analysis *checker.Analysis) error {
+	if !strict || analysis.ComparisonVersion == nil || analysis.Status() == checker.StatusCurrent {
+		return nil
+	}
+	return &exitError{code: 1, err: fmt.Errorf("version %s is %s and --strict requires the latest version (%s)",
+		types.FormatVersion(analysis.ComparisonVersion), strings.ToLower(render.StatusText(analysis.Status())), types.FormatVersion(analysis.LatestVersion))}
+}
+
+// parseFailOn validates --fail-on: a status other than current, or empty
+func parseFailOn(value string) error {
+	switch checker.Status(value) {
+	case "", checker.StatusWarning, checker.StatusCritical, checker.StatusExpired:
+		return nil
+	}
+	return fmt.Errorf("invalid fail-on %q: must be 'warning', 'critical' or 'expired'", value)
+}
+
+// failOnExit fails a check whose status is --fail-on or worse
+func failOnExit(analysis *checker.Analysis) error {
+	if failOn == "" || analysis.ComparisonVersion == nil || !analysis.Status().AtLeast(checker.Status(failOn)) {
+		return nil
+	}
+	return &exitError{code: 1, err: fmt.Errorf("version %s is %s and --fail-on %s fails the check (latest: %s)",
+		types.FormatVersion(analysis.ComparisonVersion), strings.ToLower(render.StatusText(analysis.Status())), failOn, types.FormatVersion(analysis.LatestVersion))}
+}
+
+// statusExit exits with the status's code when --exit-status is set, and
+// nothing is wrong besides
+func statusExit(status checker.Status) error {
+	if !exitStatus {
+		return nil
+	}
+	switch status {
+	case checker.StatusWarning:
+		return &exitError{code: exitWarning}
+	case checker.StatusCritical:
+		return &exitError{code: exitCritical}
+	case checker.StatusExpired:
+		return &exitError{code: exitExpired}
+	}
+	return nil
+}
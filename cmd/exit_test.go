@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/nickromney-org/github-release-version-checker/pkg/checker"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		want      int
+		wantQuiet bool
+	}{
+		{name: "success", err: nil, want: 0},
+		{name: "plain error", err: errors.New("boom"), want: 1},
+		{name: "degraded", err: &exitError{code: exitDegraded}, want: exitDegraded, wantQuiet: true},
+		{name: "wrapped with message", err: fmt.Errorf("check: %w", &exitError{code: 4, err: errors.New("boom")}), want: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.want {
+				t.Errorf("ExitCode() = %d, want %d", got, tt.want)
+			}
+			if got := isQuietExit(tt.err); got != tt.wantQuiet {
+				t.Errorf("isQuietExit() = %v, want %v", got, tt.wantQuiet)
+			}
+		})
+	}
+}
+
+func TestDegradedExit(t *testing.T) {
+	defer func(v bool) { exitDegradedFlag = v }(exitDegradedFlag)
+
+	exitDegradedFlag = false
+	if err := degradedExit(true); err != nil {
+		t.Errorf("degradedExit() without --exit-degraded = %v, want nil", err)
+	}
+	exitDegradedFlag = true
+	if err := degradedExit(false); err != nil {
+		t.Errorf("degradedExit(false) = %v, want nil", err)
+	}
+	if code := ExitCode(degradedExit(true)); code != exitDegraded {
+		t.Errorf("degradedExit(true) exit code = %d, want %d", code, exitDegraded)
+	}
+}
+
+func TestStrictExit(t *testing.T) {
+	defer func(v bool) { strict = v }(strict)
+
+	latest := mustParseVersion("2.329.0")
+	current := &checker.Analysis{LatestVersion: latest, ComparisonVersion: latest}
+	behind := &checker.Analysis{LatestVersion: latest, ComparisonVersion: mustParseVersion("2.328.0"), ReleasesBehind: 1}
+	noComparison := &checker.Analysis{LatestVersion: latest}
+
+	strict = false
+	if err := strictExit(behind); err != nil {
+		t.Errorf("strictExit() without --strict = %v, want nil", err)
+	}
+
+	strict = true
+	for _, a := range []*checker.Analysis{current, noComparison} {
+		if err := strictExit(a); err != nil {
+			t.Errorf("strictExit(%s) = %v, want nil", a.Status(), err)
+		}
+	}
+	err := strictExit(behind)
+	if err == nil || err.Error() != "version 2.328.0 is behind and --strict requires the latest version (2.329.0)" {
+		t.Errorf("strictExit(behind) = %v", err)
+	}
+}
+
+func TestFailOnExit(t *testing.T) {
+	defer func(v string) { failOn = v }(failOn)
+
+	latest := mustParseVersion("2.329.0")
+	behind := &checker.Analysis{LatestVersion: latest, ComparisonVersion: mustParseVersion("2.328.0"), ReleasesBehind: 1}
+	critical := &checker.Analysis{LatestVersion: latest, ComparisonVersion: mustParseVersion("2.328.0"), ReleasesBehind: 1, IsCritical: true}
+	expired := &checker.Analysis{LatestVersion: latest, ComparisonVersion: mustParseVersion("2.327.1"), ReleasesBehind: 2, IsExpired: true}
+
+	tests := []struct {
+		failOn   string
+		analysis *checker.Analysis
+		wantFail bool
+	}{
+		{"", expired, false},
+		{"expired", critical, false},
+		{"expired", expired, true},
+		{"critical", behind, false},
+		{"critical", critical, true},
+		{"critical", expired, true},
+		{"warning", behind, true},
+		{"warning", &checker.Analysis{LatestVersion: latest, ComparisonVersion: latest}, false},
+	}
+	for _, tt := range tests {
+		failOn = tt.failOn
+		if err := failOnExit(tt.analysis); (err != nil) != tt.wantFail {
+			t.Errorf("--fail-on %q with %s: failOnExit() = %v, want failure %t", tt.failOn, tt.analysis.Status(), err, tt.wantFail)
+		}
+	}
+
+	if err := parseFailOn("current"); err == nil {
+		t.Error("parseFailOn(current) succeeded, want an error")
+	}
+}
+
+func TestExitStatus(t *testing.T) {
+	defer func(v bool) { exitStatus = v }(exitStatus)
+
+	exitStatus = true
+	for status, want := range map[checker.Status]int{
+		checker.StatusCurrent:  0,
+		checker.StatusWarning:  exitWarning,
+		checker.StatusCritical: exitCritical,
+		checker.StatusExpired:  exitExpired,
+	} {
+		if got := ExitCode(statusExit(status)); got != want {
+			t.Errorf("ExitCode(statusExit(%s)) = %d, want %d", status, got, want)
+		}
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "invalid input", err: invalidInput(errors.New("bad flag")), want: exitInvalid},
+		{name: "invalid config", err: &configError{errors.New("bad file")}, want: exitInvalid},
+		{name: "timed out", err: fmt.Errorf("fetch: %w", context.DeadlineExceeded), want: exitUnavailable},
+		{name: "other failure", err: errors.New("boom"), want: 1},
+		{name: "exit code kept", err: &exitError{code: exitDegraded}, want: exitDegraded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.want {
+				t.Errorf("ExitCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	exitStatus = false
+	if err := statusExit(checker.StatusExpired); err != nil {
+		t.Errorf("statusExit() without --exit-status = %v, want nil", err)
+	}
+	if got := ExitCode(invalidInput(errors.New("bad flag"))); got != 1 {
+		t.Errorf("ExitCode() without --exit-status = %d, want 1", got)
+	}
+}
@@ -0,0 +1,293 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/nickromney-org/github-release-version-checker/pkg/checker"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+// Pin file formats understood by the fix command
+const (
+	pinFormatEnv       = "env"       // KEY=VALUE lines: .env files and Terraform .tfvars
+	pinFormatYAML      = "yaml"      // key: value
+	pinFormatTerraform = "terraform" // variable "key" { default = "value" }
+)
+
+var (
+	fixFile   string
+	fixKey    string
+	fixFormat string
+	fixRepo   string
+	fixToken  string
+	fixDryRun bool
+
+	fixCreatePR bool
+	fixBase     string
+	fixBranch   string
+)
+
+var fixCmd = &cobra.Command{
+	Use:   "fix --file FILE --key KEY",
+	Short: "Update a pinned version that is critical or expired",
+	Long: `Read the version pinned under KEY in FILE, check it, and when it is critical,
+expired or yanked rewrite it to the recommended version (the latest release that
+is not yanked), printing the change as a diff.
+Versions that are current or only behind are left alone.
+
+The file format comes from its extension, or --format:
+  env        KEY=VALUE lines (.env, .tfvars and anything else), with optional export
+  yaml       key: value (.yaml, .yml); the first line with the key is used
+  terraform  the default of variable "KEY" (.tf)
+
+Quotes and a leading "v" in the pinned value are kept.
+
+With --create-pr the change is committed on a new branch, pushed to origin and
+opened as a pull request against the current branch (or --base), with the analysis
+as its description. The token needs write access to the origin repository.`,
+	Example: `  # Bump the runner version in a dotenv file
+  github-release-version-checker fix --file versions.env --key RUNNER_VERSION
+
+  # Preview a Terraform variable bump
+  github-release-version-checker fix --file variables.tf --key kubernetes_version --repo k8s --dry-run
+
+  # Open a pull request for the bump from a scheduled workflow
+  github-release-version-checker fix --file versions.env --key RUNNER_VERSION --create-pr --base main`,
+	Args: cobra.NoArgs,
+	RunE: runFix,
+}
+
+func init() {
+	fixCmd.Flags().StringVarP(&fixFile, "file", "f", "", "file holding the pinned version (required)")
+	fixCmd.Flags().StringVarP(&fixKey, "key", "k", "", "variable or key the version is pinned under (required)")
+	fixCmd.Flags().StringVar(&fixFormat, "format", "", "file format: env, yaml or terraform (default: from the extension)")
+	fixCmd.Flags().StringVarP(&fixRepo, "repo", "r", "", "repository the version belongs to (default: actions/runner)")
+	fixCmd.Flags().StringVarP(&fixToken, "token", "t", "", "GitHub token (default: GH_TOKEN, GITHUB_TOKEN, then gh's stored login)")
+	fixCmd.Flags().BoolVar(&fixDryRun, "dry-run", false, "print the diff without writing the file")
+	fixCmd.Flags().BoolVar(&fixCreatePR, "create-pr", false, "commit the change on a new branch, push it and open a pull request")
+	fixCmd.Flags().StringVar(&fixBase, "base", "", "branch the pull request merges into (default: the current branch)")
+	fixCmd.Flags().StringVar(&fixBranch, "branch", "", "branch to create for the pull request (default: release-checker/KEY-VERSION)")
+	fixCmd.MarkFlagsMutuallyExclusive("dry-run", "create-pr")
+	_ = fixCmd.MarkFlagRequired("file")
+	_ = fixCmd.MarkFlagRequired("key")
+	rootCmd.AddCommand(fixCmd)
+}
+
+// pin is where a version is pinned in a file
+type pin struct {
+	Line  int // Zero-based line index
+	Start int // Byte offsets of the version within the line
+	End   int
+}
+
+// pinFix is a rewrite of a pinned version
+type pinFix struct {
+	Line     int // Zero-based line index
+	Old, New string
+}
+
+func runFix(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	w := cmd.OutOrStdout()
+
+	format := fixFormat
+	if format == "" {
+		format = detectPinFormat(fixFile)
+	}
+	switch format {
+	case pinFormatEnv, pinFormatYAML, pinFormatTerraform:
+	default:
+		return invalidInput(fmt.Errorf("invalid format %q: must be 'env', 'yaml' or 'terraform'", format))
+	}
+
+	data, err := os.ReadFile(fixFile)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", fixFile, err)
+	}
+	lines := strings.SplitAfter(string(data), "\n")
+	p, err := findPin(lines, format, fixKey)
+	if err != nil {
+		return invalidInput(fmt.Errorf("%s: %w", fixFile, err))
+	}
+	pinned := lines[p.Line][p.Start:p.End]
+	version, err := checker.ParseComparisonVersion(pinned, checker.NormaliseAll)
+	if err != nil {
+		return invalidInput(fmt.Errorf("%s: %s is not a version: %w", fixFile, fixKey, err))
+	}
+
+	repoName := fixRepo
+	if repoName == "" {
+		repoName = "actions/runner"
+	}
+	repoConfig, err := lookupRepository(repoName)
+	if err != nil {
+		return invalidInput(err)
+	}
+
+	token := detectGitHubToken(fixToken, defaultGitHubHost).Value
+	if fixCreatePR && token == "" {
+		return invalidInput(fmt.Errorf("--create-pr needs a GitHub token with write access to open the pull request"))
+	}
+	owner, repo := repoConfig.Source()
+	ghClient := newGitHubClient(token, owner, repo)
+	analysis, err := analyseTraced(cmd.Context(), newChecker(ghClient, repoConfig), repoConfig.FullName(), version.String())
+	if err != nil {
+		return withToken(err, token)
+	}
+
+	status := string(analysis.Status())
+	if analysis.Yanked != nil {
+		status = "yanked"
+	}
+	needsFix := status == "yanked" || status == string(checker.StatusCritical) || status == string(checker.StatusExpired)
+	if !needsFix || types.CompareVersions(analysis.Recommended(), analysis.ComparisonVersion) == 0 {
+		fmt.Fprintf(w, "%s=%s is %s; nothing to fix\n", fixKey, pinned, status)
+		return nil
+	}
+
+	recommended := pinnedVersion(pinned, types.FormatVersion(analysis.Recommended()))
+	fix, updated := applyPin(lines, p, recommended)
+	writeFixDiff(w, fixFile, fix)
+	if fixDryRun {
+		return nil
+	}
+	if err := writeFileKeepMode(fixFile, []byte(updated)); err != nil {
+		return err
+	}
+	green.Fprintf(w, "✅ Updated %s from %s (%s) to %s\n", fixKey, pinned, status, recommended)
+	if !fixCreatePR {
+		return nil
+	}
+
+	origin, err := originRepository(cmd.Context())
+	if err != nil {
+		return err
+	}
+	return createBumpPR(cmd.Context(), w, newGitHubClient(token, origin.Owner, origin.Repo), bumpPR{
+		File:     fixFile,
+		Key:      fixKey,
+		From:     pinned,
+		To:       recommended,
+		Base:     fixBase,
+		Branch:   fixBranch,
+		Analysis: analysis,
+		Options:  renderOptions(),
+	})
+}
+
+// detectPinFormat chooses the pin file format from the file extension
+func detectPinFormat(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return pinFormatYAML
+	case ".tf":
+		return pinFormatTerraform
+	default:
+		return pinFormatEnv
+	}
+}
+
+// findPin locates the version pinned under key in the file's lines, which keep
+// their line endings
+func findPin(lines []string, format, key string) (pin, error) {
+	quoted := regexp.QuoteMeta(key)
+	var pattern *regexp.Regexp
+	switch format {
+	case pinFormatYAML:
+		pattern = regexp.MustCompile(`^\s*(?:-\s+)?` + quoted + `\s*:\s*["']?([^"'\s#]+)`)
+	case pinFormatTerraform:
+		return findTerraformPin(lines, key)
+	default:
+		pattern = regexp.MustCompile(`^\s*(?:export\s+)?` + quoted + `\s*=\s*["']?([^"'\s#]+)`)
+	}
+
+	for i, line := range lines {
+		if m := pattern.FindStringSubmatchIndex(line); m != nil {
+			return pin{Line: i, Start: m[2], End: m[3]}, nil
+		}
+	}
+	return pin{}, fmt.Errorf("no %s found", key)
+}
+
+// findTerraformPin locates the default of variable "key", which may be on the
+// block's first line, as in variable "key" { default = "1.0.0" }
+func findTerraformPin(lines []string, key string) (pin, error) {
+	start := regexp.MustCompile(`^\s*variable\s+"` + regexp.QuoteMeta(key) + `"\s*\{`)
+	def := regexp.MustCompile(`^\s*default\s*=\s*"([^"]+)"`)
+
+	depth := 0
+	for i, line := range lines {
+		if depth == 0 {
+			m := start.FindStringIndex(line)
+			if m == nil {
+				continue
+			}
+			rest := line[m[1]:]
+			if d := def.FindStringSubmatchIndex(rest); d != nil {
+				return pin{Line: i, Start: m[1] + d[2], End: m[1] + d[3]}, nil
+			}
+			depth = 1 + strings.Count(rest, "{") - strings.Count(rest, "}")
+			if depth <= 0 {
+				return pin{}, fmt.Errorf("variable %q has no string default", key)
+			}
+			continue
+		}
+		if m := def.FindStringSubmatchIndex(line); m != nil && depth == 1 {
+			return pin{Line: i, Start: m[2], End: m[3]}, nil
+		}
+		depth += strings.Count(line, "{") - strings.Count(line, "}")
+		if depth <= 0 {
+			return pin{}, fmt.Errorf("variable %q has no string default", key)
+		}
+	}
+	return pin{}, fmt.Errorf("no variable %q found", key)
+}
+
+// pinnedVersion formats version the way the pinned value was, keeping a leading "v"
+func pinnedVersion(pinned, version string) string {
+	if strings.HasPrefix(pinned, "v") || strings.HasPrefix(pinned, "V") {
+		return pinned[:1] + version
+	}
+	return version
+}
+
+// applyPin replaces the pinned version, returning the changed line and the new content
+func applyPin(lines []string, p pin, version string) (pinFix, string) {
+	old := lines[p.Line]
+	updated := old[:p.Start] + version + old[p.End:]
+
+	var b strings.Builder
+	for i, line := range lines {
+		if i == p.Line {
+			line = updated
+		}
+		b.WriteString(line)
+	}
+	return pinFix{Line: p.Line, Old: old, New: updated}, b.String()
+}
+
+// writeFixDiff prints the change as a unified diff
+func writeFixDiff(w io.Writer, path string, fix pinFix) {
+	fmt.Fprintf(w, "--- %s\n+++ %s\n", path, path)
+	cyan.Fprintf(w, "@@ -%d +%d @@\n", fix.Line+1, fix.Line+1)
+	red.Fprintf(w, "-%s\n", strings.TrimRight(fix.Old, "\r\n"))
+	green.Fprintf(w, "+%s\n", strings.TrimRight(fix.New, "\r\n"))
+}
+
+// writeFileKeepMode replaces the file's content, keeping its permissions
+func writeFileKeepMode(path string, data []byte) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, data, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+func TestFindPin(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		content string
+		key     string
+		want    string
+		wantErr string
+	}{
+		{"env", pinFormatEnv, "FOO=1\nRUNNER_VERSION=2.327.1\n", "RUNNER_VERSION", "2.327.1", ""},
+		{"env export quoted", pinFormatEnv, "export RUNNER_VERSION=\"v2.327.1\" # pinned\n", "RUNNER_VERSION", "v2.327.1", ""},
+		{"env prefix is not the key", pinFormatEnv, "MY_RUNNER_VERSION=1.0.0\nRUNNER_VERSION=2.0.0\n", "RUNNER_VERSION", "2.0.0", ""},
+		{"tfvars", pinFormatEnv, "kubernetes_version = \"1.31.4\"\n", "kubernetes_version", "1.31.4", ""},
+		{"yaml nested", pinFormatYAML, "tools:\n  runner_version: '2.327.1'\n", "runner_version", "2.327.1", ""},
+		{"yaml list item", pinFormatYAML, "- node: v20.10.0\n", "node", "v20.10.0", ""},
+		{
+			name:    "terraform",
+			format:  pinFormatTerraform,
+			content: "variable \"other\" {\n  default = \"9.9.9\"\n}\n\nvariable \"k8s\" {\n  type    = string\n  validation {\n    default = \"nope\"\n  }\n  default = \"1.31.4\"\n}\n",
+			key:     "k8s",
+			want:    "1.31.4",
+		},
+		{"terraform one line", pinFormatTerraform, "variable \"k8s\" { default = \"1.31.4\" }\n", "k8s", "1.31.4", ""},
+		{"terraform one line without default", pinFormatTerraform, "variable \"k8s\" { type = string }\nvariable \"k8s_other\" {\n  default = \"9.9.9\"\n}\n", "k8s", "", "no string default"},
+		{"terraform without default", pinFormatTerraform, "variable \"k8s\" {\n  type = string\n}\n", "k8s", "", "no string default"},
+		{"missing key", pinFormatEnv, "FOO=1\n", "RUNNER_VERSION", "", "no RUNNER_VERSION found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := strings.SplitAfter(tt.content, "\n")
+			p, err := findPin(lines, tt.format, tt.key)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("findPin() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := lines[p.Line][p.Start:p.End]; got != tt.want {
+				t.Errorf("pinned = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyPin(t *testing.T) {
+	content := "# versions\nexport RUNNER_VERSION=\"v2.327.1\" # pinned\nOTHER=1"
+	lines := strings.SplitAfter(content, "\n")
+	p, err := findPin(lines, pinFormatEnv, "RUNNER_VERSION")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fix, updated := applyPin(lines, p, pinnedVersion("v2.327.1", "2.329.0"))
+	want := "# versions\nexport RUNNER_VERSION=\"v2.329.0\" # pinned\nOTHER=1"
+	if updated != want {
+		t.Errorf("updated content = %q, want %q", updated, want)
+	}
+
+	var buf bytes.Buffer
+	writeFixDiff(&buf, "versions.env", fix)
+	wantDiff := "--- versions.env\n+++ versions.env\n@@ -2 +2 @@\n" +
+		"-export RUNNER_VERSION=\"v2.327.1\" # pinned\n" +
+		"+export RUNNER_VERSION=\"v2.329.0\" # pinned\n"
+	if buf.String() != wantDiff {
+		t.Errorf("diff = %q, want %q", buf.String(), wantDiff)
+	}
+}
+
+func TestPinnedVersion(t *testing.T) {
+	tests := []struct {
+		name        string
+		pinned      string
+		recommended string
+		want        string
+	}{
+		{"bare", "2.327.1", "2.329.0", "2.329.0"},
+		{"keeps v", "v2.327.1", "2.329.0", "v2.329.0"},
+		{"four components", "1.2.3.3", "1.2.3.4", "1.2.3.4"},
+		{"four components with v", "v10.0.19041.1", "10.0.19041.2", "v10.0.19041.2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recommended, err := types.ParseVersion(tt.recommended)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := pinnedVersion(tt.pinned, types.FormatVersion(recommended)); got != tt.want {
+				t.Errorf("pinnedVersion() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectPinFormat(t *testing.T) {
+	tests := map[string]string{
+		"versions.env":     pinFormatEnv,
+		"prod.tfvars":      pinFormatEnv,
+		"values.YAML":      pinFormatYAML,
+		"ci.yml":           pinFormatYAML,
+		"variables.tf":     pinFormatTerraform,
+		"no-extension-pin": pinFormatEnv,
+	}
+	for path, want := range tests {
+		if got := detectPinFormat(path); got != want {
+			t.Errorf("detectPinFormat(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
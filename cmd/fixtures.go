@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/nickromney-org/github-release-version-checker/internal/config"
+	"github.com/nickromney-org/github-release-version-checker/internal/testing/githubtest"
+	"github.com/spf13/cobra"
+)
+
+// fixtureRepositories are recorded when record-fixtures is given none
+var fixtureRepositories = []config.RepositoryConfig{
+	config.ConfigActionsRunner,
+	config.ConfigKubernetes,
+	config.ConfigPulumi,
+	config.ConfigNodeJS,
+}
+
+var (
+	fixturesDir      string
+	fixturesToken    string
+	fixturesMaxPages int
+)
+
+var recordFixturesCmd = &cobra.Command{
+	Use:   "record-fixtures [REPOSITORY...]",
+	Short: "Record GitHub API responses as test fixtures",
+	Long: `Fetch releases, drafts and prereleases included, and the marked latest release
+for each repository, and write them as fixtures for the fake GitHub API used in
+tests. Records the predefined repositories when none are given.`,
+	Example: `  github-release-version-checker record-fixtures
+  github-release-version-checker record-fixtures hashicorp/terraform --max-pages 2`,
+	Hidden: true,
+	RunE:   runRecordFixtures,
+}
+
+func init() {
+	recordFixturesCmd.Flags().StringVar(&fixturesDir, "dir", "internal/testing/githubtest/fixtures", "directory to write fixtures to")
+	recordFixturesCmd.Flags().StringVarP(&fixturesToken, "token", "t", "", "GitHub token (default: GH_TOKEN, GITHUB_TOKEN, then gh's stored login)")
+	recordFixturesCmd.Flags().IntVar(&fixturesMaxPages, "max-pages", 10, "pages of 100 releases to record per repository")
+	rootCmd.AddCommand(recordFixturesCmd)
+}
+
+func runRecordFixtures(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	w := cmd.OutOrStdout()
+
+	repos := fixtureRepositories
+	if len(args) > 0 {
+		repos = nil
+		for _, arg := range args {
+			repoConfig, err := lookupRepository(arg)
+			if err != nil {
+				return err
+			}
+			repos = append(repos, *repoConfig)
+		}
+	}
+
+	headers := http.Header{"User-Agent": []string{userAgent()}}
+	if token := detectGitHubToken(fixturesToken, defaultGitHubHost).Value; token != "" {
+		headers.Set("Authorization", "Bearer "+token)
+	}
+
+	for _, repoConfig := range repos {
+		fixture, err := githubtest.Record(cmd.Context(), http.DefaultClient, "https://api.github.com", headers, repoConfig.FullName(), fixturesMaxPages)
+		if err != nil {
+			return fmt.Errorf("failed to record %s: %w", repoConfig.FullName(), err)
+		}
+		path, err := fixture.Save(fixturesDir)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "Recorded %d releases of %s to %s\n", len(fixture.Releases), repoConfig.FullName(), path)
+	}
+	return nil
+}
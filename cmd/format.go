@@ -2,35 +2,42 @@ package cmd
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 	"time"
+
+	"github.com/nickromney-org/github-release-version-checker/pkg/render"
 )
 
-// formatUKDate formats a date in UK format: "06 May 2025"
-func formatUKDate(t time.Time) string {
-	return t.Format("02 Jan 2006")
-}
+// activeDateFormat is the date format used for display, resolved from --date-format
+var activeDateFormat = render.DateFormats["uk"]
 
-// formatDaysAgo returns a human-readable string for days
-func formatDaysAgo(days int) string {
-	if days < 0 {
-		return formatDaysInFuture(-days)
-	}
-	if days == 0 {
-		return "today"
+// resolveDateFormat accepts a preset name or a Go time layout
+func resolveDateFormat(value string) (render.DateFormat, error) {
+	if preset, ok := render.DateFormats[strings.ToLower(strings.TrimSpace(value))]; ok {
+		return preset, nil
 	}
-	if days == 1 {
-		return "1 day ago"
+
+	// Custom layouts must at least reference the year
+	if !strings.Contains(value, "2006") && !strings.Contains(value, "06") {
+		names := make([]string, 0, len(render.DateFormats))
+		for name := range render.DateFormats {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return render.DateFormat{}, fmt.Errorf("invalid date format %q: use a preset (%s) or a Go time layout such as \"2006-01-02\"",
+			value, strings.Join(names, ", "))
 	}
-	return fmt.Sprintf("%d days ago", days)
+
+	return render.DateFormat{Date: value, Timestamp: value + " 15:04:05 MST"}, nil
 }
 
-// formatDaysInFuture returns a human-readable string for future days
-func formatDaysInFuture(days int) string {
-	if days == 0 {
-		return "today"
-	}
-	if days == 1 {
-		return "1 day"
-	}
-	return fmt.Sprintf("%d days", days)
+// formatDate formats a date using the configured --date-format
+func formatDate(t time.Time) string {
+	return t.Format(activeDateFormat.Date)
+}
+
+// formatTimestamp formats a date and time using the configured --date-format
+func formatTimestamp(t time.Time) string {
+	return t.Format(activeDateFormat.Timestamp)
 }
@@ -5,34 +5,38 @@ import (
 	"time"
 )
 
-func TestFormatUKDate(t *testing.T) {
+func TestResolveDateFormat(t *testing.T) {
+	date := time.Date(2024, 7, 5, 14, 30, 0, 0, time.UTC)
+
 	tests := []struct {
-		name     string
-		date     time.Time
-		expected string
+		name          string
+		value         string
+		wantDate      string
+		wantTimestamp string
+		wantErr       bool
 	}{
-		{
-			name:     "standard date",
-			date:     time.Date(2024, 7, 25, 0, 0, 0, 0, time.UTC),
-			expected: "25 Jul 2024",
-		},
-		{
-			name:     "single digit day",
-			date:     time.Date(2024, 10, 5, 0, 0, 0, 0, time.UTC),
-			expected: "05 Oct 2024",
-		},
-		{
-			name:     "december",
-			date:     time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC),
-			expected: "31 Dec 2024",
-		},
+		{"uk preset", "uk", "05 Jul 2024", "5 Jul 2024 14:30:00 UTC", false},
+		{"us preset", "US", "Jul 05, 2024", "Jul 5, 2024 14:30:00 UTC", false},
+		{"eu preset", "eu", "05.07.2024", "05.07.2024 14:30:00 UTC", false},
+		{"iso preset", "iso", "2024-07-05", "2024-07-05T14:30:00Z", false},
+		{"custom layout", "2006/01/02", "2024/07/05", "2024/07/05 14:30:00 UTC", false},
+		{"invalid layout", "dd/mm/yyyy", "", "", true},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := formatUKDate(tt.date)
-			if result != tt.expected {
-				t.Errorf("expected %q, got %q", tt.expected, result)
+			format, err := resolveDateFormat(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveDateFormat() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := date.Format(format.Date); got != tt.wantDate {
+				t.Errorf("date = %q, want %q", got, tt.wantDate)
+			}
+			if got := date.Format(format.Timestamp); got != tt.wantTimestamp {
+				t.Errorf("timestamp = %q, want %q", got, tt.wantTimestamp)
 			}
 		})
 	}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nickromney-org/github-release-version-checker/internal/cache"
+	"github.com/spf13/cobra"
+)
+
+var (
+	generateRoot      string
+	generateToken     string
+	generateRetention cache.Retention
+	generateDryRun    bool
+)
+
+var generateCacheCmd = &cobra.Command{
+	Use:   "generate-cache",
+	Short: "Regenerate the embedded release caches from the GitHub API",
+	Long: `Fetch every release of each repository with an embedded cache and rewrite its
+cache file in the source tree, for maintainers keeping the embedded data current.
+
+Releases are written newest first in a fixed order, and a file whose releases are
+unchanged is left alone, so the diff only shows new or edited releases. go generate
+runs it from the module root.`,
+	Example: `  go generate ./...
+  github-release-version-checker generate-cache --root ~/src/github-release-version-checker`,
+	Args: cobra.NoArgs,
+	RunE: runGenerateCache,
+}
+
+func init() {
+	generateCacheCmd.Flags().StringVar(&generateRoot, "root", ".", "module root holding the embedded cache files")
+	generateCacheCmd.Flags().IntVar(&generateRetention.Releases, "keep-releases", 0, "keep only the N most recent releases of each repository (0 keeps all)")
+	generateCacheCmd.Flags().IntVar(&generateRetention.Years, "keep-years", 0, "keep only releases published in the last N years (0 keeps all)")
+	generateCacheCmd.Flags().BoolVar(&generateDryRun, "dry-run", false, "fetch and report what would be written (new releases, file size) without writing")
+	generateCacheCmd.Flags().StringVarP(&generateToken, "token", "t", "", "GitHub token (default: GH_TOKEN, GITHUB_TOKEN, then gh's stored login)")
+
+	rootCmd.AddCommand(generateCacheCmd)
+}
+
+func runGenerateCache(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	w := cmd.OutOrStdout()
+	if err := generateRetention.Validate(); err != nil {
+		return invalidInput(err)
+	}
+	if _, err := os.Stat(filepath.Join(generateRoot, "go.mod")); err != nil {
+		return invalidInput(fmt.Errorf("%s is not the module root; run from it or set --root", generateRoot))
+	}
+
+	token := detectGitHubToken(generateToken, defaultGitHubHost).Value
+	caches := cache.EmbeddedFiles()
+	results := generateCaches(cmd.Context(), generateRoot, caches, generateRetention, generateDryRun, time.Now(), func(repository string) releaseLister {
+		owner, repo, _ := strings.Cut(repository, "/")
+		return newGitHubClient(token, owner, repo)
+	})
+
+	failed := 0
+	for i, result := range results {
+		switch {
+		case result.err != nil:
+			failed++
+			red.Fprintf(w, "❌ %s: %v\n", caches[i].Path, withToken(result.err, token))
+		case result.preview != nil:
+			printPreview(w, caches[i].Repository, caches[i].Path, result.fetch, result.preview)
+		case result.changed:
+			green.Fprintf(w, "✅ %s: %d releases (updated)\n", caches[i].Path, result.releases)
+		default:
+			fmt.Fprintf(w, "✅ %s: %d releases (unchanged)\n", caches[i].Path, result.releases)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d embedded caches could not be generated", failed, len(caches))
+	}
+	return nil
+}
+
+// generateResult is the outcome of regenerating one embedded cache
+type generateResult struct {
+	releases int
+	changed  bool
+	fetch    *cache.FetchMetadata
+	preview  *cache.Preview // Set for dry runs
+	err      error
+}
+
+// generateCaches fetches and rewrites (or with dryRun, previews) each embedded
+// cache under root, in order, keeping the releases retention keeps
+func generateCaches(ctx context.Context, root string, caches []cache.EmbeddedFile, retention cache.Retention, dryRun bool, now time.Time, newLister func(repository string) releaseLister) []generateResult {
+	results := make([]generateResult, len(caches))
+	for i, c := range caches {
+		lister := newLister(c.Repository)
+		releases, err := lister.GetAllReleases(ctx)
+		if err != nil {
+			results[i].err = err
+			continue
+		}
+		releases = retention.Apply(releases, now)
+		path := filepath.Join(root, c.Path)
+		results[i].releases = len(releases)
+		results[i].fetch = fetchMetadata(lister)
+		if dryRun {
+			results[i].preview, results[i].err = cache.PreviewFile(path, c.Repository, releases, results[i].fetch, now)
+			continue
+		}
+		results[i].changed, results[i].err = cache.GenerateFile(path, c.Repository, releases, results[i].fetch, now)
+	}
+	return results
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nickromney-org/github-release-version-checker/internal/cache"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+func TestGenerateCaches(t *testing.T) {
+	root := t.TempDir()
+	releases := []types.Release{
+		{Version: mustParseVersion("2.329.0"), PublishedAt: time.Date(2025, 10, 14, 0, 0, 0, 0, time.UTC), URL: "https://example.com/v2.329.0"},
+	}
+	caches := []cache.EmbeddedFile{
+		{Repository: "actions/runner", Path: filepath.Join("internal", "data", "releases.json")},
+		{Repository: "kubernetes/kubernetes", Path: filepath.Join("internal", "cache", "data", "kubernetes.json")},
+	}
+	listers := map[string]releaseLister{
+		"actions/runner":        fakeLister{releases: releases},
+		"kubernetes/kubernetes": fakeLister{err: errors.New("rate limit exhausted")},
+	}
+	generate := func(dryRun bool) []generateResult {
+		return generateCaches(context.Background(), root, caches, cache.Retention{}, dryRun, time.Now(), func(repository string) releaseLister {
+			return listers[repository]
+		})
+	}
+
+	if results := generate(true); results[0].preview == nil || results[0].changed {
+		t.Fatalf("dry run generateCaches() = %+v, want a preview", results)
+	}
+	if _, err := os.Stat(filepath.Join(root, caches[0].Path)); !os.IsNotExist(err) {
+		t.Fatalf("dry run wrote the cache: %v", err)
+	}
+
+	results := generate(false)
+	if !results[0].changed || results[0].releases != 1 || results[1].err == nil {
+		t.Fatalf("generateCaches() = %+v, want runner written and kubernetes failed", results)
+	}
+	if results := generate(false); results[0].changed {
+		t.Errorf("second generateCaches() = %+v, want runner unchanged", results)
+	}
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/nickromney-org/github-release-version-checker/internal/config"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+var (
+	goModFile string   // --go-mod
+	goModules []string // --go-module
+)
+
+var (
+	goDirective        = regexp.MustCompile(`^go\s+(\S+)`)
+	toolchainDirective = regexp.MustCompile(`^toolchain\s+go(\S+)`)
+	requireLine        = regexp.MustCompile(`^(?:require\s+)?(\S+)\s+(v\S+)`)
+	pseudoVersion      = regexp.MustCompile(`\d{14}-[0-9a-f]{12}$`)
+)
+
+// goModule is a module --go-module asks to check
+type goModule struct {
+	Path string // Module path, e.g. github.com/spf13/cobra
+	Repo string // Repository its releases come from
+}
+
+// parseGoModules reads --go-module values: a module path, with =OWNER/REPO for
+// modules that are not named after their GitHub repository
+func parseGoModules(values []string) ([]goModule, error) {
+	modules := make([]goModule, 0, len(values))
+	for _, value := range values {
+		path, repo, named := strings.Cut(value, "=")
+		if !named {
+			repo = moduleRepository(path)
+		}
+		if path == "" || repo == "" {
+			return nil, fmt.Errorf("invalid --go-module %q: give the repository as MODULE=OWNER/REPO", value)
+		}
+		modules = append(modules, goModule{Path: path, Repo: repo})
+	}
+	return modules, nil
+}
+
+// moduleRepository returns owner/repo for a module on github.com, or ""
+func moduleRepository(path string) string {
+	parts := strings.Split(path, "/")
+	if len(parts) < 3 || parts[0] != "github.com" {
+		return ""
+	}
+	return parts[1] + "/" + parts[2]
+}
+
+// goModRepositories lists the repositories to check for a go.mod: the Go
+// toolchain, from the toolchain directive or else the go directive, and each
+// module in modules at the version go.mod selects. Pseudo-versions cannot be
+// compared with releases, so their modules are reported to w and skipped.
+func goModRepositories(w io.Writer, path string, modules []goModule) ([]config.FileRepository, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var goVersion, toolchain string
+	required := make(map[string]string)
+	inRequire := false
+	for _, line := range strings.Split(string(data), "\n") {
+		line, _, _ = strings.Cut(line, "//")
+		line = strings.TrimSpace(line)
+		switch {
+		case inRequire && line == ")":
+			inRequire = false
+		case inRequire:
+			if m := requireLine.FindStringSubmatch(line); m != nil {
+				required[m[1]] = m[2]
+			}
+		case line == "require (":
+			inRequire = true
+		case strings.HasPrefix(line, "require "):
+			if m := requireLine.FindStringSubmatch(line); m != nil {
+				required[m[1]] = m[2]
+			}
+		default:
+			if m := goDirective.FindStringSubmatch(line); m != nil {
+				goVersion = m[1]
+			}
+			if m := toolchainDirective.FindStringSubmatch(line); m != nil {
+				toolchain = m[1]
+			}
+		}
+	}
+
+	var entries []config.FileRepository
+	if toolchain == "" {
+		toolchain = goVersion
+	}
+	if toolchain != "" {
+		version, err := types.ParseVersion(toolchain)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid Go version %q: %w", path, toolchain, err)
+		}
+		entries = append(entries, config.FileRepository{Repo: "go", Version: version.String()})
+	}
+
+	for _, module := range modules {
+		version, ok := required[module.Path]
+		if !ok {
+			return nil, fmt.Errorf("%s: module %s is not required", path, module.Path)
+		}
+		if pseudoVersion.MatchString(strings.TrimSuffix(version, "+incompatible")) {
+			yellow.Fprintf(w, "⚠️  Skipping %s: %s is a pseudo-version, not a release\n", module.Path, version)
+			continue
+		}
+		entries = append(entries, config.FileRepository{Repo: module.Repo, Version: version})
+	}
+
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("%s: no Go version or modules to check", path)
+	}
+	return entries, nil
+}
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testGoMod = `module example.com/app
+
+go 1.22.0
+
+toolchain go1.22.5
+
+require github.com/spf13/cobra v1.8.0 // indirect
+
+require (
+	github.com/google/go-github/v57 v57.0.0
+	golang.org/x/oauth2 v0.15.0
+	github.com/acme/unreleased v0.0.0-20240101120000-abcdef123456
+)
+`
+
+func TestParseGoModules(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  []string
+		want    string
+		wantErr bool
+	}{
+		{"github module", []string{"github.com/google/go-github/v57"}, "github.com/google/go-github/v57=google/go-github", false},
+		{"named repository", []string{"golang.org/x/oauth2=golang/oauth2"}, "golang.org/x/oauth2=golang/oauth2", false},
+		{"unknown repository", []string{"golang.org/x/oauth2"}, "", true},
+		{"empty module", []string{"=golang/oauth2"}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseGoModules(tt.values)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseGoModules() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got[0].Path+"="+got[0].Repo != tt.want {
+				t.Errorf("parseGoModules() = %+v, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGoModRepositories(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "go.mod")
+	if err := os.WriteFile(path, []byte(testGoMod), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		modules []string
+		want    []string // repo@version
+		warning string
+		wantErr string
+	}{
+		{name: "toolchain only", want: []string{"go@1.22.5"}},
+		{
+			name:    "modules",
+			modules: []string{"github.com/spf13/cobra", "golang.org/x/oauth2=golang/oauth2"},
+			want:    []string{"go@1.22.5", "spf13/cobra@v1.8.0", "golang/oauth2@v0.15.0"},
+		},
+		{
+			name:    "pseudo-version skipped",
+			modules: []string{"github.com/acme/unreleased"},
+			want:    []string{"go@1.22.5"},
+			warning: "pseudo-version",
+		},
+		{name: "module not required", modules: []string{"github.com/acme/missing"}, wantErr: "not required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			modules, err := parseGoModules(tt.modules)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var warnings bytes.Buffer
+			entries, err := goModRepositories(&warnings, path, modules)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("goModRepositories() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var got []string
+			for _, e := range entries {
+				got = append(got, e.Repo+"@"+e.Version)
+			}
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("goModRepositories() = %v, want %v", got, tt.want)
+			}
+			if !strings.Contains(warnings.String(), tt.warning) {
+				t.Errorf("warnings = %q, want containing %q", warnings.String(), tt.warning)
+			}
+		})
+	}
+}
+
+func TestGoModRepositories_GoDirective(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "go.mod")
+	if err := os.WriteFile(path, []byte("module example.com/app\n\ngo 1.21\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := goModRepositories(&bytes.Buffer{}, path, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Version != "1.21.0" {
+		t.Errorf("goModRepositories() = %+v, want go 1.21.0", entries)
+	}
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"sync"
+
+	"github.com/nickromney-org/github-release-version-checker/internal/config"
+	"github.com/nickromney-org/github-release-version-checker/pkg/client"
+)
+
+// apiHosts routes repositories to the API host their releases are on,
+// github.com or a GitHub Enterprise Server, resolving each host's token once
+// so repositories on several hosts can be checked concurrently
+type apiHosts struct {
+	file      *config.File // Host API URLs and token settings; nil without a config file
+	flagToken string       // -t, used for every host when given
+
+	mu     sync.Mutex
+	tokens map[string]resolvedToken
+}
+
+// newAPIHosts routes repositories using the config file's hosts, if any
+func newAPIHosts(f *config.File, flagToken string) *apiHosts {
+	return &apiHosts{file: f, flagToken: flagToken, tokens: make(map[string]resolvedToken)}
+}
+
+// token returns host's token, resolving it on first use
+func (h *apiHosts) token(host string) resolvedToken {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if resolved, ok := h.tokens[host]; ok {
+		return resolved
+	}
+	resolved := configuredToken(h.file, h.flagToken, host)
+	h.tokens[host] = resolved
+	return resolved
+}
+
+// client creates an API client for the releases repoConfig checks, on their
+// host with that host's token, or on --base-url with only the -t token
+func (h *apiHosts) client(repoConfig *config.RepositoryConfig) (*client.Client, resolvedToken, error) {
+	host := repoConfig.SourceHost()
+	apiURL := h.file.APIURL(host)
+	var resolved resolvedToken
+	if baseURLFlag != "" {
+		// The base URL is not GitHub, so it gets only a token given for it with
+		// -t, never one detected for GitHub
+		apiURL = baseURLFlag
+		resolved = resolvedToken{Source: tokenSourceNone}
+		if h.flagToken != "" {
+			resolved = resolvedToken{Value: h.flagToken, Source: tokenSourceFlag}
+		}
+	} else {
+		resolved = h.token(host)
+	}
+	owner, repo := repoConfig.Source()
+	ghClient := newGitHubClient(resolved.Value, owner, repo)
+	if apiURL != "" {
+		if err := ghClient.SetBaseURL(apiURL); err != nil {
+			return nil, resolvedToken{}, err
+		}
+	}
+	return ghClient, resolved, nil
+}
+
+// repositoryToken returns the token for a repository named as by SourceName,
+// e.g. "github.example.com/tools/runner"
+func (h *apiHosts) repositoryToken(name string) resolvedToken {
+	repoConfig, err := config.ParseRepositoryString(name)
+	if err != nil {
+		return h.token(config.DefaultHost)
+	}
+	return h.token(repoConfig.SourceHost())
+}
@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/nickromney-org/github-release-version-checker/internal/config"
+)
+
+func TestAPIHosts_Client(t *testing.T) {
+	t.Setenv("GH_TOKEN", "")
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("PUBLIC_TOKEN", "public-token")
+	t.Setenv("GHE_TOKEN", "ghe-token")
+
+	hosts := newAPIHosts(&config.File{
+		Token: config.TokenSettings{Source: config.TokenSourceEnv, Env: "PUBLIC_TOKEN"},
+		Hosts: []config.HostSettings{
+			{Name: "github.example.com", Token: config.TokenSettings{Source: config.TokenSourceEnv, Env: "GHE_TOKEN"}},
+			{Name: "ghe.internal", APIURL: "https://api.ghe.internal/", Token: config.TokenSettings{Source: config.TokenSourceNone}},
+		},
+	}, "")
+
+	tests := []struct {
+		repo      string
+		upstream  string
+		wantHost  string
+		wantToken string
+	}{
+		{repo: "actions/runner", wantHost: "api.github.com", wantToken: "public-token"},
+		{repo: "github.example.com/tools/node", wantHost: "github.example.com", wantToken: "ghe-token"},
+		{repo: "ghe.internal/tools/node", wantHost: "api.ghe.internal"},
+		// A mirror checks its upstream's releases, on the upstream's host
+		{repo: "github.example.com/tools/runner", upstream: "actions/runner", wantHost: "api.github.com", wantToken: "public-token"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.repo, func(t *testing.T) {
+			repoConfig, err := config.ParseRepositoryString(tt.repo)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.upstream != "" {
+				if err := repoConfig.SetUpstream(tt.upstream); err != nil {
+					t.Fatal(err)
+				}
+			}
+			ghClient, resolved, err := hosts.client(repoConfig)
+			if err != nil {
+				t.Fatalf("client() error = %v", err)
+			}
+			if got := ghClient.APIHost(); got != tt.wantHost {
+				t.Errorf("APIHost() = %q, want %q", got, tt.wantHost)
+			}
+			if resolved.Value != tt.wantToken {
+				t.Errorf("token = %q, want %q", resolved.Value, tt.wantToken)
+			}
+		})
+	}
+
+	// -t is used for every host
+	flagHosts := newAPIHosts(nil, "flag-token")
+	if got := flagHosts.token("github.example.com"); got.Value != "flag-token" || got.Source != tokenSourceFlag {
+		t.Errorf("token() = %+v, want the flag token", got)
+	}
+}
+
+func TestAPIHosts_ClientBaseURL(t *testing.T) {
+	defer func(flag string) { baseURLFlag = flag }(baseURLFlag)
+	baseURLFlag = "http://releases.internal:8080/"
+
+	// --base-url wins over every host, so a proxy can serve them all
+	hosts := newAPIHosts(nil, "token")
+	for _, name := range []string{"actions/runner", "github.example.com/tools/runner"} {
+		repoConfig, err := config.ParseRepositoryString(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ghClient, _, err := hosts.client(repoConfig)
+		if err != nil {
+			t.Fatalf("client(%s) error = %v", name, err)
+		}
+		if got := ghClient.APIHost(); got != "releases.internal:8080" {
+			t.Errorf("client(%s) APIHost() = %q, want the --base-url host", name, got)
+		}
+	}
+}
+
+func TestAPIHosts_ClientBaseURLToken(t *testing.T) {
+	defer func(flag string) { baseURLFlag = flag }(baseURLFlag)
+	baseURLFlag = "http://releases.internal:8080/"
+	t.Setenv("GITHUB_TOKEN", "github-token")
+	repoConfig, err := config.ParseRepositoryString("actions/runner")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		flagToken string
+		want      resolvedToken
+	}{
+		{name: "detected token withheld", want: resolvedToken{Source: tokenSourceNone}},
+		{name: "-t sent", flagToken: "proxy-token", want: resolvedToken{Value: "proxy-token", Source: tokenSourceFlag}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, resolved, err := newAPIHosts(nil, tt.flagToken).client(repoConfig)
+			if err != nil {
+				t.Fatalf("client() error = %v", err)
+			}
+			if resolved != tt.want {
+				t.Errorf("token = %+v, want %+v", resolved, tt.want)
+			}
+		})
+	}
+}
+
+// TestExecute_BaseURLWithoutToken tests that a token detected for GitHub is
+// never sent to --base-url
+func TestExecute_BaseURLWithoutToken(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "ghp_detected")
+	t.Setenv("GH_TOKEN", "ghp_detected")
+	var mu sync.Mutex
+	var authorizations []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		authorizations = append(authorizations, r.Header.Get("Authorization"))
+		mu.Unlock()
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	_, _ = executeRoot(t, "--base-url", server.URL+"/", "-c", "2.328.0", "--no-cache", "--json")
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(authorizations) == 0 {
+		t.Fatal("no requests reached --base-url")
+	}
+	for _, authorization := range authorizations {
+		if authorization != "" {
+			t.Errorf("Authorization = %q sent to --base-url, want none", authorization)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strings"
+
+	"github.com/nickromney-org/github-release-version-checker/pkg/client"
+)
+
+var (
+	userAgentFlag  string
+	headerFlags    []string
+	requestHeaders http.Header // Parsed --header values
+	baseURLFlag    string      // API base URL for release checks, e.g. a proxy
+)
+
+// userAgent returns the User-Agent sent to GitHub, embedding the tool version by default
+func userAgent() string {
+	if userAgentFlag != "" {
+		return userAgentFlag
+	}
+	return "github-release-version-checker/" + appVersion
+}
+
+// parseHeaders parses "Name: Value" pairs from --header
+func parseHeaders(values []string) (http.Header, error) {
+	headers := http.Header{}
+	for _, value := range values {
+		name, v, ok := strings.Cut(value, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" || strings.ContainsAny(name, " \t") {
+			return nil, fmt.Errorf("invalid header %q: use 'Name: Value'", value)
+		}
+		headers.Add(textproto.CanonicalMIMEHeaderKey(name), strings.TrimSpace(v))
+	}
+	return headers, nil
+}
+
+// validateBaseURL checks --base-url is an http(s) URL
+func validateBaseURL(rawURL string) error {
+	if rawURL == "" {
+		return nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("invalid --base-url %q: must be an http(s) URL", rawURL)
+	}
+	return nil
+}
+
+// newGitHubClient creates an API client with the configured User-Agent and headers
+func newGitHubClient(token, owner, repo string) *client.Client {
+	ghClient := client.NewClient(token, owner, repo)
+	ghClient.UserAgent = userAgent()
+	ghClient.Headers = requestHeaders
+	if tracer != nil {
+		ghClient.Instrument = tracer.Instrument
+	}
+	return ghClient
+}
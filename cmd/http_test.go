@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestParseHeaders(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  []string
+		want    http.Header
+		wantErr bool
+	}{
+		{
+			name:   "trims and canonicalises",
+			values: []string{"x-proxy-team:  platform ", "X-Trace: a", "X-Trace: b"},
+			want:   http.Header{"X-Proxy-Team": {"platform"}, "X-Trace": {"a", "b"}},
+		},
+		{
+			name:   "empty value",
+			values: []string{"X-Empty:"},
+			want:   http.Header{"X-Empty": {""}},
+		},
+		{name: "missing colon", values: []string{"X-Proxy-Team platform"}, wantErr: true},
+		{name: "missing name", values: []string{": platform"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseHeaders(tt.values)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseHeaders() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseHeaders() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserAgent(t *testing.T) {
+	defer func(flag, version string) { userAgentFlag, appVersion = flag, version }(userAgentFlag, appVersion)
+
+	userAgentFlag, appVersion = "", "1.2.3"
+	if got := userAgent(); got != "github-release-version-checker/1.2.3" {
+		t.Errorf("userAgent() = %q", got)
+	}
+	userAgentFlag = "corp-scanner/7"
+	if got := userAgent(); got != "corp-scanner/7" {
+		t.Errorf("userAgent() = %q", got)
+	}
+}
+
+func TestValidateBaseURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		wantErr bool
+	}{
+		{url: ""},
+		{url: "http://releases.internal:8080/"},
+		{url: "https://github.example.com/api/v3"},
+		{url: "releases.internal:8080", wantErr: true},
+		{url: "ftp://releases.internal/", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		if err := validateBaseURL(tt.url); (err != nil) != tt.wantErr {
+			t.Errorf("validateBaseURL(%q) error = %v, wantErr %v", tt.url, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+	"github.com/nickromney-org/github-release-version-checker/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// initOptions are the choices used to build a starter config file
+type initOptions struct {
+	Repos       []string // repo or repo@version
+	Policy      string   // Applied to every repository; blank keeps each default
+	TokenSource string
+	TokenEnv    string
+	Annotations bool
+}
+
+var (
+	initRepos       []string
+	initPolicy      string
+	initTokenSource string
+	initTokenEnv    string
+	initAnnotations bool
+	initOutput      string
+	initForce       bool
+	initYes         bool
+)
+
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Write a starter config file",
+	Long: `Write a starter config file with the repositories to check, their policies,
+the token source, and CI notification settings.
+
+Prompts for each setting when run in a terminal without --repo; otherwise
+uses the flags, falling back to defaults. Flags given on later runs still
+override the file.`,
+	Example: `  # Answer a few questions
+  github-release-version-checker init
+
+  # Non-interactive
+  github-release-version-checker init --repo runner@2.328.0 --repo k8s@1.31.0 --token-source env`,
+	Args: cobra.NoArgs,
+	RunE: runInit,
+}
+
+func init() {
+	initCmd.Flags().StringSliceVar(&initRepos, "repo", nil, "repository to check, optionally with the version in use (e.g., k8s@1.31.0); repeatable")
+	initCmd.Flags().StringVar(&initPolicy, "policy", "", "policy for every repository: 'days' or 'versions' (default: each repository's own)")
+	initCmd.Flags().StringVar(&initTokenSource, "token-source", config.TokenSourceAuto, "where to find the GitHub token: auto, env, gh or none")
+	initCmd.Flags().StringVar(&initTokenEnv, "token-env", "GITHUB_TOKEN", "environment variable holding the token when --token-source is env")
+	initCmd.Flags().BoolVar(&initAnnotations, "annotations", true, "write CI annotations for each status")
+	initCmd.Flags().StringVarP(&initOutput, "output", "o", config.DefaultFileName, "config file to write")
+	initCmd.Flags().BoolVar(&initForce, "force", false, "overwrite an existing config file")
+	initCmd.Flags().BoolVarP(&initYes, "yes", "y", false, "do not prompt; use flags and defaults")
+	rootCmd.AddCommand(initCmd)
+}
+
+func runInit(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	w := cmd.OutOrStdout()
+
+	if !initForce {
+		if _, err := os.Stat(initOutput); !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("%s already exists; use --force to overwrite it", initOutput)
+		}
+	}
+
+	opts := initOptions{
+		Repos:       initRepos,
+		Policy:      initPolicy,
+		TokenSource: initTokenSource,
+		TokenEnv:    initTokenEnv,
+		Annotations: initAnnotations,
+	}
+	if len(opts.Repos) == 0 {
+		opts.Repos = []string{config.ConfigActionsRunner.FullName()}
+	}
+
+	if !initYes && !cmd.Flags().Changed("repo") && stdinIsTerminal() {
+		var err error
+		if opts, err = promptInitOptions(cmd.InOrStdin(), w, opts); err != nil {
+			return err
+		}
+	}
+
+	fileConfig, err := buildInitConfig(opts)
+	if err != nil {
+		return err
+	}
+
+	content, err := fileConfig.Marshal()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(initOutput, content, 0644); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+
+	green.Fprintf(w, "✅ Wrote %s\n", initOutput)
+	for _, r := range fileConfig.Repositories {
+		if r.Version != "" {
+			fmt.Fprintf(w, "   • %s (v%s)\n", r.Repo, strings.TrimPrefix(r.Version, "v"))
+		} else {
+			fmt.Fprintf(w, "   • %s\n", r.Repo)
+		}
+	}
+	if initOutput != config.DefaultFileName {
+		fmt.Fprintf(w, "   Use it with: github-release-version-checker --config %s\n", initOutput)
+	}
+	return nil
+}
+
+// buildInitConfig turns the chosen options into a validated config file
+func buildInitConfig(opts initOptions) (*config.File, error) {
+	f := &config.File{
+		Token: config.TokenSettings{Source: opts.TokenSource},
+	}
+	if opts.TokenSource == config.TokenSourceEnv {
+		f.Token.Env = opts.TokenEnv
+	}
+	if !opts.Annotations {
+		f.Notifications.NoAnnotations = true
+	}
+
+	for _, spec := range opts.Repos {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+		repo, version, _ := strings.Cut(spec, "@")
+		f.Repositories = append(f.Repositories, config.FileRepository{
+			Repo:    repo,
+			Version: version,
+			Policy:  opts.Policy,
+		})
+	}
+	if len(f.Repositories) == 0 {
+		return nil, fmt.Errorf("at least one repository is required")
+	}
+
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// promptInitOptions asks for each setting, offering the current options as defaults
+func promptInitOptions(r io.Reader, w io.Writer, defaults initOptions) (initOptions, error) {
+	scanner := bufio.NewScanner(r)
+	ask := func(question, def string) (string, error) {
+		fmt.Fprintf(w, "%s [%s]: ", question, def)
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil {
+				return "", fmt.Errorf("failed to read answer: %w", err)
+			}
+			return def, nil // End of input: accept the remaining defaults
+		}
+		if answer := strings.TrimSpace(scanner.Text()); answer != "" {
+			return answer, nil
+		}
+		return def, nil
+	}
+
+	opts := defaults
+
+	answer, err := ask("Repositories to check (comma-separated, repo or repo@version)", strings.Join(defaults.Repos, ","))
+	if err != nil {
+		return opts, err
+	}
+	opts.Repos = strings.Split(answer, ",")
+
+	policyDefault := defaults.Policy
+	if policyDefault == "" {
+		policyDefault = "default"
+	}
+	if answer, err = ask("Policy for all repositories (days, versions or default)", policyDefault); err != nil {
+		return opts, err
+	}
+	opts.Policy = ""
+	if answer != "default" {
+		opts.Policy = answer
+	}
+
+	if answer, err = ask("Token source (auto, env, gh, none)", defaults.TokenSource); err != nil {
+		return opts, err
+	}
+	opts.TokenSource = answer
+	if opts.TokenSource == config.TokenSourceEnv {
+		if opts.TokenEnv, err = ask("Environment variable holding the token", defaults.TokenEnv); err != nil {
+			return opts, err
+		}
+	}
+
+	annotationsDefault := "Y/n"
+	if !defaults.Annotations {
+		annotationsDefault = "y/N"
+	}
+	if answer, err = ask("Write CI annotations for each status?", annotationsDefault); err != nil {
+		return opts, err
+	}
+	switch strings.ToLower(answer) {
+	case "y", "yes":
+		opts.Annotations = true
+	case "n", "no":
+		opts.Annotations = false
+	}
+
+	return opts, nil
+}
+
+// stdinIsTerminal reports whether prompts can be answered interactively
+func stdinIsTerminal() bool {
+	fd := os.Stdin.Fd()
+	return isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd)
+}
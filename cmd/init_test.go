@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nickromney-org/github-release-version-checker/internal/config"
+)
+
+func TestBuildInitConfig(t *testing.T) {
+	f, err := buildInitConfig(initOptions{
+		Repos:       []string{"runner@2.328.0", " k8s "},
+		Policy:      "versions",
+		TokenSource: config.TokenSourceEnv,
+		TokenEnv:    "RELEASE_TOKEN",
+	})
+	if err != nil {
+		t.Fatalf("buildInitConfig() error = %v", err)
+	}
+
+	want := []config.FileRepository{
+		{Repo: "runner", Version: "2.328.0", Policy: "versions"},
+		{Repo: "k8s", Policy: "versions"},
+	}
+	if len(f.Repositories) != len(want) {
+		t.Fatalf("got %d repositories, want %d", len(f.Repositories), len(want))
+	}
+	for i := range want {
+		if !reflect.DeepEqual(f.Repositories[i], want[i]) {
+			t.Errorf("repositories[%d] = %+v, want %+v", i, f.Repositories[i], want[i])
+		}
+	}
+	if f.Token.Env != "RELEASE_TOKEN" || !f.Notifications.NoAnnotations {
+		t.Errorf("unexpected settings: %+v", f)
+	}
+
+	if _, err := buildInitConfig(initOptions{Repos: []string{"runner"}, TokenSource: "vault"}); err == nil {
+		t.Error("expected error for invalid token source, got nil")
+	}
+}
+
+func TestPromptInitOptions(t *testing.T) {
+	defaults := initOptions{
+		Repos:       []string{"actions/runner"},
+		TokenSource: config.TokenSourceAuto,
+		TokenEnv:    "GITHUB_TOKEN",
+		Annotations: true,
+	}
+
+	// Accept the default repository, pick a policy and env token, decline annotations
+	input := strings.NewReader("\ndays\nenv\nRELEASE_TOKEN\nn\n")
+	var out bytes.Buffer
+
+	opts, err := promptInitOptions(input, &out, defaults)
+	if err != nil {
+		t.Fatalf("promptInitOptions() error = %v", err)
+	}
+
+	if strings.Join(opts.Repos, ",") != "actions/runner" || opts.Policy != "days" {
+		t.Errorf("unexpected repositories or policy: %+v", opts)
+	}
+	if opts.TokenSource != config.TokenSourceEnv || opts.TokenEnv != "RELEASE_TOKEN" || opts.Annotations {
+		t.Errorf("unexpected token or annotation answers: %+v", opts)
+	}
+	if !strings.Contains(out.String(), "Token source (auto, env, gh, none) [auto]: ") {
+		t.Errorf("expected prompt with default, got %q", out.String())
+	}
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// jobVariablePrefix starts the name of every variable configuring the job command
+const jobVariablePrefix = "RELEASE_CHECKER_"
+
+// jobVariableAliases maps variables to the flags they set where the names
+// differ; every other variable is named after its flag
+var jobVariableAliases = map[string]string{jobVariablePrefix + "VERSION": "compare"}
+
+var jobCmd = &cobra.Command{
+	Use:   "job",
+	Short: "Run once in a container, configured by RELEASE_CHECKER_* variables",
+	Long: `Check a version once, as a container entrypoint or Kubernetes CronJob. Each flag
+is read from its RELEASE_CHECKER_<FLAG> variable, upper-cased with hyphens as
+underscores: RELEASE_CHECKER_REPO sets --repo and RELEASE_CHECKER_CRITICAL_DAYS
+sets --critical-days. RELEASE_CHECKER_VERSION sets --compare.
+
+Logs are JSON lines on stdout, one per analysis besides the start and finish of
+the job. The analysis JSON is written to RELEASE_CHECKER_OUTPUT_FILE, typically
+a mounted volume, and discarded when it is unset. The exit code tells outcomes
+apart, as with --exit-status:
+
+  0   current
+  1   failed for another reason
+  2   invalid flags, variables or config file
+  3   based on incomplete data, with RELEASE_CHECKER_EXIT_DEGRADED=true
+  4   could not check, but may succeed later, e.g. rate limited
+  10  warning
+  11  critical
+  12  expired`,
+	Example: `  RELEASE_CHECKER_REPO=k8s RELEASE_CHECKER_VERSION=1.31.0 \
+    RELEASE_CHECKER_OUTPUT_FILE=/results/k8s.json github-release-version-checker job`,
+	Args: cobra.NoArgs,
+	RunE: runJob,
+}
+
+func init() {
+	rootCmd.AddCommand(jobCmd)
+}
+
+func runJob(cmd *cobra.Command, args []string) (err error) {
+	cmd.SilenceUsage = true
+
+	exitStatus = true // Before the variables, so invalid ones exit 2
+	logJSON = cmd.OutOrStdout()
+	logger := jobLogger()
+	started := time.Now()
+	logger.Info("job started", "version", appVersion)
+	defer func() {
+		attrs := []any{"exit_code", ExitCode(err), "duration", time.Since(started).String()}
+		if err != nil && !isQuietExit(err) {
+			attrs = append(attrs, "error", err.Error(), "error_code", classifyError(err).Code)
+		}
+		logger.Info("job finished", attrs...)
+	}()
+
+	flags := rootCmd.Flags()
+	flags.AddFlagSet(rootCmd.PersistentFlags())
+	if err := applyJobVariables(flags, os.LookupEnv); err != nil {
+		return invalidInput(err)
+	}
+	// Variables can set the shared flags resolved before this command ran
+	if err := resolvePersistentFlags(rootCmd, nil); err != nil {
+		return err
+	}
+	return run(rootCmd, nil)
+}
+
+// applyJobVariables sets flags from the job's variables, turning on --json and
+// --exit-status, and discarding the analysis unless an output file is set
+func applyJobVariables(flags *pflag.FlagSet, lookup func(string) (string, bool)) error {
+	values := flagVariables(flags, lookup, jobVariable, jobVariableAliases)
+	if values["output-file"] == "" {
+		values["output-file"] = os.DevNull
+	}
+	values["json"] = "true"
+	values["exit-status"] = "true"
+	return setFlags(flags, values, jobVariable)
+}
+
+// jobVariable names the variable holding the value for a flag
+func jobVariable(name string) string {
+	return jobVariablePrefix + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func TestApplyJobVariables(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    map[string]string // Flag values
+		wantErr string
+	}{
+		{
+			name: "defaults",
+			want: map[string]string{"json": "true", "exit-status": "true", "output-file": os.DevNull, "compare": "[]"},
+		},
+		{
+			name: "variables",
+			env: map[string]string{
+				"RELEASE_CHECKER_VERSION":       "1.31.0",
+				"RELEASE_CHECKER_REPO":          "k8s",
+				"RELEASE_CHECKER_CRITICAL_DAYS": "7",
+				"RELEASE_CHECKER_OUTPUT_FILE":   "/results/k8s.json",
+			},
+			want: map[string]string{"compare": "[1.31.0]", "repo": "k8s", "critical-days": "7", "output-file": "/results/k8s.json"},
+		},
+		{
+			name: "skipped flags",
+			env:  map[string]string{"RELEASE_CHECKER_JSON": "false", "RELEASE_CHECKER_CI": "true"},
+			want: map[string]string{"json": "true", "ci": "false"},
+		},
+		{
+			name:    "invalid value",
+			env:     map[string]string{"RELEASE_CHECKER_CRITICAL_DAYS": "soon"},
+			wantErr: "invalid RELEASE_CHECKER_CRITICAL_DAYS",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			flags.Bool("ci", false, "")
+			flags.Bool("json", false, "")
+			flags.Bool("exit-status", false, "")
+			flags.String("output-file", "", "")
+			flags.String("repo", "", "")
+			flags.StringSlice("compare", nil, "")
+			flags.Int("critical-days", 12, "")
+
+			err := applyJobVariables(flags, func(name string) (string, bool) {
+				value, ok := tt.env[name]
+				return value, ok
+			})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("applyJobVariables() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for name, want := range tt.want {
+				if got := flags.Lookup(name).Value.String(); got != want {
+					t.Errorf("--%s = %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+}
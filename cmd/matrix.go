@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/nickromney-org/github-release-version-checker/pkg/checker"
+	"github.com/nickromney-org/github-release-version-checker/pkg/render"
+	"github.com/spf13/cobra"
+)
+
+var (
+	matrixRepo   string
+	matrixToken  string
+	matrixFormat string
+	matrixLines  int
+)
+
+var matrixCmd = &cobra.Command{
+	Use:   "matrix",
+	Short: "Show the support status of each minor version line",
+	Long: `Group a repository's stable releases by minor version line and show, for each
+line, its first release, latest patch, age and support status. The status is that of
+the line's latest patch under the repository's policy.`,
+	Example: `  # The newest 10 lines of the runner
+  github-release-version-checker matrix
+
+  # Every Kubernetes line, as a markdown table for a job summary
+  github-release-version-checker matrix --repo kubernetes --lines 0 --format markdown >> "$GITHUB_STEP_SUMMARY"`,
+	Args: cobra.NoArgs,
+	RunE: runMatrix,
+}
+
+func init() {
+	matrixCmd.Flags().StringVarP(&matrixRepo, "repo", "r", "", "repository to show (default: actions/runner)")
+	matrixCmd.Flags().StringVarP(&matrixToken, "token", "t", "", "GitHub token (default: GH_TOKEN, GITHUB_TOKEN, then gh's stored login)")
+	matrixCmd.Flags().StringVar(&matrixFormat, "format", distributionTerminal, "report format: terminal, json or markdown")
+	matrixCmd.Flags().IntVar(&matrixLines, "lines", 10, "newest minor lines to show (0 for all)")
+	rootCmd.AddCommand(matrixCmd)
+}
+
+// matrixReport is the JSON form of a support matrix
+type matrixReport struct {
+	Repository string `json:"repository"`
+	*checker.SupportMatrix
+}
+
+func runMatrix(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+	w := cmd.OutOrStdout()
+
+	switch matrixFormat {
+	case distributionTerminal, distributionJSON, distributionMarkdown:
+	default:
+		return fmt.Errorf("invalid format %q: must be 'terminal', 'json' or 'markdown'", matrixFormat)
+	}
+	if matrixLines < 0 {
+		return invalidInput(fmt.Errorf("--lines must be non-negative"))
+	}
+
+	repoName := matrixRepo
+	if repoName == "" {
+		repoName = "actions/runner"
+	}
+	repoConfig, err := lookupRepository(repoName)
+	if err != nil {
+		return err
+	}
+
+	ghClient, resolved, err := newAPIHosts(nil, matrixToken).client(repoConfig)
+	if err != nil {
+		return err
+	}
+	matrix, err := newChecker(ghClient, repoConfig).Matrix(cmd.Context(), matrixLines)
+	if err != nil {
+		return withToken(err, resolved.Value)
+	}
+
+	report := matrixReport{Repository: repoConfig.FullName(), SupportMatrix: matrix}
+	switch matrixFormat {
+	case distributionJSON:
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, string(data))
+	case distributionMarkdown:
+		printMatrixMarkdown(w, report)
+	default:
+		printMatrixTerminal(w, report)
+	}
+	return nil
+}
+
+// matrixExpiry describes when a line's latest patch expires, if it does
+func matrixExpiry(line checker.MinorLine) string {
+	if line.ExpiresAt == nil {
+		return "-"
+	}
+	return formatDate(*line.ExpiresAt)
+}
+
+// printMatrixTerminal writes the matrix for the terminal
+func printMatrixTerminal(w io.Writer, r matrixReport) {
+	cyan.Fprintf(w, "📅 %s: support by minor line (latest %s, %s policy)\n", r.Repository, r.LatestVersion, r.PolicyType)
+	cyan.Fprintln(w, "─────────────────────────────────────")
+	fmt.Fprintf(w, "%-8s %-14s %-14s %8s %8s  %-12s %s\n", "Line", "First release", "Latest patch", "Releases", "Age", "Expires", "Status")
+	for _, line := range r.Lines {
+		render.StatusColour(line.Status).Fprintf(w, "%-8s %-14s %-14s %8d %7dd  %-12s %s\n",
+			line.Line, line.FirstRelease, line.LatestPatch, line.Releases, line.AgeDays, matrixExpiry(line), render.StatusText(line.Status))
+	}
+}
+
+// printMatrixMarkdown writes the matrix as markdown, e.g. for a job summary
+func printMatrixMarkdown(w io.Writer, r matrixReport) {
+	fmt.Fprintf(w, "## Support Matrix: %s\n\n", r.Repository)
+	fmt.Fprintf(w, "Latest version is **%s**; statuses follow the %s policy.\n\n", r.LatestVersion, r.PolicyType)
+	fmt.Fprintln(w, "| Line | First release | Latest patch | Releases | Age (days) | Expires | Status |")
+	fmt.Fprintln(w, "|------|---------------|--------------|---------:|-----------:|---------|--------|")
+	for _, line := range r.Lines {
+		fmt.Fprintf(w, "| %s | %s | %s | %d | %d | %s | %s %s |\n", line.Line, line.FirstRelease, line.LatestPatch,
+			line.Releases, line.AgeDays, matrixExpiry(line), render.StatusIcon(line.Status), render.StatusText(line.Status))
+	}
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/nickromney-org/github-release-version-checker/pkg/checker"
+)
+
+func TestPrintMatrix(t *testing.T) {
+	expires := time.Date(2025, 11, 13, 0, 0, 0, 0, time.UTC)
+	report := matrixReport{
+		Repository: "actions/runner",
+		SupportMatrix: &checker.SupportMatrix{
+			LatestVersion: semver.MustParse("2.329.0"),
+			PolicyType:    "days",
+			Lines: []checker.MinorLine{
+				{Line: "2.329", FirstRelease: semver.MustParse("2.329.0"), LatestPatch: semver.MustParse("2.329.0"), Releases: 1, AgeDays: 5, Status: checker.StatusCurrent},
+				{Line: "2.328", FirstRelease: semver.MustParse("2.328.0"), LatestPatch: semver.MustParse("2.328.1"), Releases: 2, AgeDays: 60, Status: checker.StatusWarning, ExpiresAt: &expires},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	printMatrixMarkdown(&buf, report)
+	for _, line := range []string{
+		"## Support Matrix: actions/runner",
+		"| 2.329 | 2.329.0 | 2.329.0 | 1 | 5 | - | ✅ Current |",
+		"| 2.328 | 2.328.0 | 2.328.1 | 2 | 60 | 13 Nov 2025 | ⚠️  Behind |",
+	} {
+		if !strings.Contains(buf.String(), line) {
+			t.Errorf("markdown missing %q:\n%s", line, buf.String())
+		}
+	}
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct {
+		Repository string `json:"repository"`
+		Lines      []struct {
+			Line   string `json:"line"`
+			Status string `json:"status"`
+		} `json:"lines"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded.Repository != "actions/runner" || len(decoded.Lines) != 2 || decoded.Lines[1].Status != "warning" {
+		t.Errorf("JSON = %s (%v)", data, err)
+	}
+}
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// netrcEntry is one machine (or the default) in a .netrc file
+type netrcEntry struct {
+	Machine  string // Empty for the default entry
+	Login    string
+	Password string
+}
+
+// netrcPath returns the .netrc file to read: $NETRC, else ~/.netrc (~/_netrc on Windows)
+func netrcPath() (string, error) {
+	if path := os.Getenv("NETRC"); path != "" {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	name := ".netrc"
+	if runtime.GOOS == "windows" {
+		name = "_netrc"
+	}
+	return filepath.Join(home, name), nil
+}
+
+// netrcMachines returns the API host and the host itself, the machine names a
+// netrc file may use for a GitHub host
+func netrcMachines(host string) []string {
+	if host == defaultGitHubHost {
+		return []string{"api.github.com", host}
+	}
+	return []string{host}
+}
+
+// parseNetrc parses .netrc content. Macro definitions are skipped.
+func parseNetrc(content string) []netrcEntry {
+	var entries []netrcEntry
+	var current *netrcEntry
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	inMacro := false
+	for scanner.Scan() {
+		line := scanner.Text()
+		if inMacro {
+			// A macro definition runs until the next blank line
+			inMacro = strings.TrimSpace(line) != ""
+			continue
+		}
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		for i := 0; i < len(fields); i++ {
+			value := func() string {
+				if i+1 < len(fields) {
+					i++
+					return fields[i]
+				}
+				return ""
+			}
+			switch fields[i] {
+			case "machine":
+				entries = append(entries, netrcEntry{Machine: value()})
+				current = &entries[len(entries)-1]
+			case "default":
+				entries = append(entries, netrcEntry{})
+				current = &entries[len(entries)-1]
+			case "login":
+				if current != nil {
+					current.Login = value()
+				}
+			case "password":
+				if current != nil {
+					current.Password = value()
+				}
+			case "account":
+				value()
+			case "macdef":
+				value()
+				inMacro = true
+				i = len(fields)
+			}
+		}
+	}
+	return entries
+}
+
+// readNetrcToken reads the password for host from the .netrc file, returning the
+// file path read. The default entry is not used, so a token is never sent to
+// GitHub that was meant for another machine.
+func readNetrcToken(host string) (string, string, error) {
+	path, err := netrcPath()
+	if err != nil {
+		return "", "", err
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", path, err
+	}
+
+	entries := parseNetrc(string(content))
+	for _, machine := range netrcMachines(host) {
+		for _, entry := range entries {
+			if strings.EqualFold(entry.Machine, machine) && entry.Password != "" {
+				return entry.Password, path, nil
+			}
+		}
+	}
+	return "", path, fmt.Errorf("no password for %s in %s", host, path)
+}
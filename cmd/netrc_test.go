@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseNetrc(t *testing.T) {
+	content := `# CI credentials
+machine example.com login alice password secret
+macdef init
+cd /pub
+password ignored
+
+machine api.github.com
+    login x-access-token
+    password ghp_netrc
+default login anonymous password guest
+`
+	want := []netrcEntry{
+		{Machine: "example.com", Login: "alice", Password: "secret"},
+		{Machine: "api.github.com", Login: "x-access-token", Password: "ghp_netrc"},
+		{Login: "anonymous", Password: "guest"},
+	}
+	if got := parseNetrc(content); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseNetrc() = %+v, want %+v", got, want)
+	}
+}
+
+func TestReadNetrcToken(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		host    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "api.github.com",
+			content: "machine api.github.com login x password ghp_api\nmachine github.com login x password ghp_web\n",
+			host:    defaultGitHubHost,
+			want:    "ghp_api",
+		},
+		{
+			name:    "github.com fallback",
+			content: "machine github.com login x password ghp_web\n",
+			host:    defaultGitHubHost,
+			want:    "ghp_web",
+		},
+		{
+			name:    "enterprise host",
+			content: "machine api.github.com password ghp_api\nmachine ghe.example.com password ghp_ghe\n",
+			host:    "ghe.example.com",
+			want:    "ghp_ghe",
+		},
+		{
+			name:    "default entry is not used",
+			content: "default login anonymous password guest\n",
+			host:    defaultGitHubHost,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ".netrc")
+			if err := os.WriteFile(path, []byte(tt.content), 0600); err != nil {
+				t.Fatal(err)
+			}
+			t.Setenv("NETRC", path)
+
+			got, gotPath, err := readNetrcToken(tt.host)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readNetrcToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("readNetrcToken() = %q, want %q", got, tt.want)
+			}
+			if gotPath != path {
+				t.Errorf("readNetrcToken() path = %q, want %q", gotPath, path)
+			}
+		})
+	}
+}
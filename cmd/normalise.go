@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nickromney-org/github-release-version-checker/pkg/checker"
+)
+
+// normaliseSteps maps --normalise values to the clean-ups they enable
+var normaliseSteps = map[string]checker.Normalisation{
+	"trim-space":  checker.NormaliseTrimSpace,
+	"v-prefix":    checker.NormaliseUppercaseV,
+	"strip-build": checker.NormaliseStripBuild,
+}
+
+var (
+	normaliseFlag []string              // --normalise
+	normalisation checker.Normalisation // Resolved from --normalise
+)
+
+// parseNormalisation resolves --normalise values; "none" disables every clean-up
+func parseNormalisation(values []string) (checker.Normalisation, error) {
+	var n checker.Normalisation
+	for _, value := range values {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if value == "none" {
+			if len(values) > 1 {
+				return 0, fmt.Errorf("--normalise none cannot be combined with other values")
+			}
+			return 0, nil
+		}
+		step, ok := normaliseSteps[value]
+		if !ok {
+			return 0, fmt.Errorf("invalid normalise value %q: must be trim-space, v-prefix, strip-build or none", value)
+		}
+		n |= step
+	}
+	return n, nil
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/nickromney-org/github-release-version-checker/pkg/checker"
+)
+
+func TestParseNormalisation(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  []string
+		want    checker.Normalisation
+		wantErr bool
+	}{
+		{name: "default", values: []string{"trim-space", "v-prefix", "strip-build"}, want: checker.NormaliseAll},
+		{name: "subset", values: []string{"trim-space"}, want: checker.NormaliseTrimSpace},
+		{name: "none", values: []string{"none"}, want: 0},
+		{name: "none with others", values: []string{"none", "trim-space"}, wantErr: true},
+		{name: "unknown", values: []string{"lowercase"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseNormalisation(tt.values)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseNormalisation() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseNormalisation() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
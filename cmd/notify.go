@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"text/template"
+	"time"
+
+	"github.com/nickromney-org/github-release-version-checker/pkg/checker"
+	"github.com/nickromney-org/github-release-version-checker/pkg/render"
+)
+
+var (
+	notifyWebhook      string
+	notifyTemplatePath string
+	notifyTemplate     *template.Template // Resolved from flags in run
+)
+
+// notifyTimeout bounds a webhook post, so a slow endpoint cannot hold up the check
+const notifyTimeout = 10 * time.Second
+
+// loadNotifyTemplate parses a Go text/template file for notifications, or the
+// default message when path is empty
+func loadNotifyTemplate(path string) (*template.Template, error) {
+	funcs := render.TemplateFuncs(render.Options{})
+	if path == "" {
+		return template.New("notification").Funcs(funcs).Parse(render.DefaultNotificationTemplate)
+	}
+	tmpl, err := template.New(filepath.Base(path)).Funcs(funcs).ParseFiles(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse notification template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// notify posts the analysis to --notify-webhook when the version needs attention.
+// The message goes in a "text" field, which Slack and Teams incoming webhooks show.
+func notify(ctx context.Context, analysis *checker.Analysis) error {
+	if notifyWebhook == "" || notifyTemplate == nil || analysis.Status() == checker.StatusCurrent {
+		return nil
+	}
+	message, err := render.Notification(notifyTemplate, analysis, renderOptions())
+	if err != nil {
+		return fmt.Errorf("failed to render notification: %w", err)
+	}
+	return postWebhook(ctx, message)
+}
+
+// postWebhook posts message to --notify-webhook as {"text": message}
+func postWebhook(ctx context.Context, message string) error {
+	body, err := json.Marshal(map[string]string{"text": message})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, notifyTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, notifyWebhook, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to send notification: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent())
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send notification: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("failed to send notification: webhook returned %s", resp.Status)
+	}
+	return nil
+}
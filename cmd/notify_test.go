@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nickromney-org/github-release-version-checker/pkg/checker"
+)
+
+func TestNotify(t *testing.T) {
+	templatePath := filepath.Join(t.TempDir(), "notify.tmpl")
+	if err := os.WriteFile(templatePath, []byte("<!subteam^ONCALL> {{ .Analysis.Repository }} is {{ .Status }}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	expired := &checker.Analysis{
+		Repository:        "actions/runner",
+		LatestVersion:     mustParseVersion("2.329.0"),
+		ComparisonVersion: mustParseVersion("2.327.1"),
+		IsExpired:         true,
+		ReleasesBehind:    2,
+	}
+	current := &checker.Analysis{
+		LatestVersion:     mustParseVersion("2.329.0"),
+		ComparisonVersion: mustParseVersion("2.329.0"),
+		IsLatest:          true,
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		analysis *checker.Analysis
+		want     string // Posted text; empty for no post
+	}{
+		{"default message", "", expired, "🚨 actions/runner v2.327.1: Expired. Update to v2.329.0."},
+		{"custom template", templatePath, expired, "<!subteam^ONCALL> actions/runner is expired"},
+		{"current is not posted", "", current, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var payload map[string]string
+				if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+					t.Errorf("invalid payload: %v", err)
+				}
+				got = payload["text"]
+			}))
+			defer server.Close()
+
+			tmpl, err := loadNotifyTemplate(tt.template)
+			if err != nil {
+				t.Fatalf("loadNotifyTemplate() error = %v", err)
+			}
+			notifyWebhook, notifyTemplate = server.URL, tmpl
+			t.Cleanup(func() { notifyWebhook, notifyTemplate = "", nil })
+
+			if err := notify(context.Background(), tt.analysis); err != nil {
+				t.Fatalf("notify() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("posted %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNotify_WebhookError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "no_service", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	tmpl, err := loadNotifyTemplate("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	notifyWebhook, notifyTemplate = server.URL, tmpl
+	t.Cleanup(func() { notifyWebhook, notifyTemplate = "", nil })
+
+	err = notify(context.Background(), &checker.Analysis{
+		LatestVersion:     mustParseVersion("2.329.0"),
+		ComparisonVersion: mustParseVersion("2.328.0"),
+		ReleasesBehind:    1,
+	})
+	if err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("notify() error = %v, want the webhook status", err)
+	}
+}
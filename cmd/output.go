@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	colour "github.com/fatih/color"
+)
+
+// openOutput returns the writer for command results: the given default writer,
+// or the file at path when --output-file is set. Colour is disabled for files.
+func openOutput(defaultWriter io.Writer, path string) (io.Writer, func(), error) {
+	if path == "" {
+		return defaultWriter, func() {}, nil
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create output file: %w", err)
+	}
+	colour.NoColor = true
+
+	return f, func() { f.Close() }, nil
+}
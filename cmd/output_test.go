@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	colour "github.com/fatih/color"
+)
+
+func TestOpenOutput(t *testing.T) {
+	t.Run("default writer", func(t *testing.T) {
+		var buf bytes.Buffer
+		w, closeOutput, err := openOutput(&buf, "")
+		if err != nil {
+			t.Fatalf("openOutput() error = %v", err)
+		}
+		defer closeOutput()
+
+		if w != &buf {
+			t.Error("expected the default writer to be returned")
+		}
+	})
+
+	t.Run("output file", func(t *testing.T) {
+		noColour := colour.NoColor
+		defer func() { colour.NoColor = noColour }()
+
+		path := filepath.Join(t.TempDir(), "result.json")
+		w, closeOutput, err := openOutput(&bytes.Buffer{}, path)
+		if err != nil {
+			t.Fatalf("openOutput() error = %v", err)
+		}
+
+		analysis := summaryTestAnalysis()
+		if err := outputJSON(w, analysis); err != nil {
+			t.Fatalf("outputJSON() error = %v", err)
+		}
+		closeOutput()
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("failed to read output file: %v", err)
+		}
+		if !bytes.Contains(data, []byte(`"latest_version": "2.329.0"`)) {
+			t.Errorf("expected JSON in output file, got:\n%s", data)
+		}
+		if !colour.NoColor {
+			t.Error("expected colour to be disabled when writing to a file")
+		}
+	})
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/nickromney-org/github-release-version-checker/internal/config"
+	"github.com/nickromney-org/github-release-version-checker/pkg/checker"
+	"github.com/nickromney-org/github-release-version-checker/pkg/client"
+	"github.com/nickromney-org/github-release-version-checker/pkg/render"
+)
+
+// runGit runs git in the working directory and returns its trimmed output;
+// tests replace it
+var runGit = func(ctx context.Context, args ...string) (string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// pullRequestCreator opens pull requests; *client.Client implements it
+type pullRequestCreator interface {
+	CreatePullRequest(ctx context.Context, pr client.PullRequest) (string, error)
+}
+
+// bumpPR is a version bump to propose as a pull request
+type bumpPR struct {
+	File     string
+	Key      string
+	From, To string // As pinned in the file
+	Base     string // Branch to merge into; empty for the current branch
+	Branch   string // Branch to create; empty for a name from the key and version
+	Analysis *checker.Analysis
+	Options  render.Options // For the analysis summary in the description
+}
+
+// branchUnsafe matches characters not allowed in generated branch names
+var branchUnsafe = regexp.MustCompile(`[^a-z0-9._-]+`)
+
+// bumpBranch names the branch for a bump, e.g. "release-checker/runner_version-v2.329.0"
+func bumpBranch(key, version string) string {
+	name := strings.Trim(branchUnsafe.ReplaceAllString(strings.ToLower(key), "-"), "-")
+	return fmt.Sprintf("release-checker/%s-%s", name, version)
+}
+
+// originRepository returns the GitHub repository the origin remote points at
+func originRepository(ctx context.Context) (*config.RepositoryConfig, error) {
+	remote, err := runGit(ctx, "remote", "get-url", "origin")
+	if err != nil {
+		return nil, err
+	}
+	// git@github.com:owner/repo.git -> github.com/owner/repo
+	remote = strings.TrimSuffix(remote, ".git")
+	remote = strings.Replace(remote, "git@github.com:", "github.com/", 1)
+	repoConfig, err := config.ParseRepositoryString(remote)
+	if err != nil {
+		return nil, fmt.Errorf("origin is not a GitHub repository: %w", err)
+	}
+	return repoConfig, nil
+}
+
+// createBumpPR commits the updated file on a new branch, pushes it to origin and
+// opens a pull request with the analysis as its description
+func createBumpPR(ctx context.Context, w io.Writer, prs pullRequestCreator, bump bumpPR) error {
+	base := bump.Base
+	if base == "" {
+		current, err := runGit(ctx, "rev-parse", "--abbrev-ref", "HEAD")
+		if err != nil {
+			return err
+		}
+		base = current
+	}
+	branch := bump.Branch
+	if branch == "" {
+		branch = bumpBranch(bump.Key, bump.To)
+	}
+	title := fmt.Sprintf("Bump %s to %s", bump.Key, bump.To)
+
+	for _, args := range [][]string{
+		{"checkout", "-b", branch},
+		{"add", "--", bump.File},
+		{"commit", "-m", title},
+		{"push", "-u", "origin", branch},
+	} {
+		if _, err := runGit(ctx, args...); err != nil {
+			return err
+		}
+	}
+
+	url, err := prs.CreatePullRequest(ctx, client.PullRequest{
+		Title: title,
+		Body:  bumpPRBody(bump),
+		Head:  branch,
+		Base:  base,
+	})
+	if err != nil {
+		return err
+	}
+	green.Fprintf(w, "🔀 Opened %s\n", url)
+	return nil
+}
+
+// bumpPRBody describes the bump, followed by the analysis job summary
+func bumpPRBody(bump bumpPR) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Bumps `%s` in `%s` from %s to %s.\n\n", bump.Key, bump.File, bump.From, bump.To)
+	if review := render.DescribeReview(bump.Analysis); review != "" {
+		fmt.Fprintf(&b, "> [!WARNING]\n> %s.\n\n", review)
+	}
+	b.WriteString(render.Summary(bump.Analysis, bump.Options))
+	b.WriteString("*Opened by `github-release-version-checker fix --create-pr`.*\n")
+	return b.String()
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nickromney-org/github-release-version-checker/pkg/checker"
+	"github.com/nickromney-org/github-release-version-checker/pkg/client"
+)
+
+// fakePRs records the pull request it is asked to open
+type fakePRs struct {
+	got client.PullRequest
+}
+
+func (f *fakePRs) CreatePullRequest(ctx context.Context, pr client.PullRequest) (string, error) {
+	f.got = pr
+	return "https://github.com/acme/infra/pull/12", nil
+}
+
+// fakeGit replaces runGit for a test, recording commands and answering from outputs
+func fakeGit(t *testing.T, outputs map[string]string) *[]string {
+	t.Helper()
+	var calls []string
+	original := runGit
+	runGit = func(ctx context.Context, args ...string) (string, error) {
+		call := strings.Join(args, " ")
+		calls = append(calls, call)
+		return outputs[call], nil
+	}
+	t.Cleanup(func() { runGit = original })
+	return &calls
+}
+
+func TestCreateBumpPR(t *testing.T) {
+	calls := fakeGit(t, map[string]string{"rev-parse --abbrev-ref HEAD": "main"})
+	prs := &fakePRs{}
+	analysis := &checker.Analysis{
+		LatestVersion:     mustParseVersion("2.329.0"),
+		ComparisonVersion: mustParseVersion("2.327.1"),
+		IsExpired:         true,
+		ReleasesBehind:    2,
+		FirstNewerVersion: mustParseVersion("2.328.0"),
+	}
+
+	var out bytes.Buffer
+	err := createBumpPR(context.Background(), &out, prs, bumpPR{
+		File: "versions.env", Key: "RUNNER_VERSION", From: "v2.327.1", To: "v2.329.0", Analysis: analysis,
+	})
+	if err != nil {
+		t.Fatalf("createBumpPR() error = %v", err)
+	}
+
+	wantCalls := []string{
+		"rev-parse --abbrev-ref HEAD",
+		"checkout -b release-checker/runner_version-v2.329.0",
+		"add -- versions.env",
+		"commit -m Bump RUNNER_VERSION to v2.329.0",
+		"push -u origin release-checker/runner_version-v2.329.0",
+	}
+	if !reflect.DeepEqual(*calls, wantCalls) {
+		t.Errorf("git calls = %q, want %q", *calls, wantCalls)
+	}
+	if prs.got.Base != "main" || prs.got.Head != "release-checker/runner_version-v2.329.0" || prs.got.Title != "Bump RUNNER_VERSION to v2.329.0" {
+		t.Errorf("pull request = %+v", prs.got)
+	}
+	for _, want := range []string{"from v2.327.1 to v2.329.0", "Runner Version Status: Expired"} {
+		if !strings.Contains(prs.got.Body, want) {
+			t.Errorf("body missing %q:\n%s", want, prs.got.Body)
+		}
+	}
+	if !strings.Contains(out.String(), "https://github.com/acme/infra/pull/12") {
+		t.Errorf("output = %q", out.String())
+	}
+}
+
+func TestOriginRepository(t *testing.T) {
+	tests := map[string]string{
+		"git@github.com:acme/infra.git":     "acme/infra",
+		"https://github.com/acme/infra.git": "acme/infra",
+		"https://github.com/acme/infra":     "acme/infra",
+	}
+	for remote, want := range tests {
+		t.Run(remote, func(t *testing.T) {
+			fakeGit(t, map[string]string{"remote get-url origin": remote})
+			repoConfig, err := originRepository(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if repoConfig.FullName() != want {
+				t.Errorf("originRepository() = %s, want %s", repoConfig.FullName(), want)
+			}
+		})
+	}
+}
+
+func TestBumpBranch(t *testing.T) {
+	if got := bumpBranch("Kubernetes Version!", "1.34.1"); got != "release-checker/kubernetes-version-1.34.1" {
+		t.Errorf("bumpBranch() = %q", got)
+	}
+}
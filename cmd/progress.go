@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/mattn/go-isatty"
+	"github.com/nickromney-org/github-release-version-checker/pkg/client"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+// progressDelay is how long a fetch must run before the spinner appears,
+// so fast checks served from the embedded cache stay silent
+const progressDelay = 500 * time.Millisecond
+
+// spinnerFrames are the animation frames for the progress spinner
+var spinnerFrames = []rune("⠋⠙⠹⠸⠼⠴⠦⠧⠇⠏")
+
+// progressReporter draws a spinner with page counts on a terminal while releases are fetched
+type progressReporter struct {
+	w       io.Writer
+	mu      sync.Mutex
+	latest  client.Progress
+	started time.Time
+	frame   int
+	drawn   bool
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// progressEnabled reports whether progress should be shown: only on an interactive
+// terminal, and never in quiet, JSON or CI modes or while tracing to stderr
+func progressEnabled() bool {
+	if quiet || jsonOutput || ciOutput || verbose >= verbosityCache {
+		return false
+	}
+	fd := os.Stderr.Fd()
+	return isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd)
+}
+
+// startProgress starts the spinner on w; call Stop when the fetch is finished
+func startProgress(w io.Writer) *progressReporter {
+	p := &progressReporter{
+		w:       w,
+		started: time.Now(),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go p.loop()
+	return p
+}
+
+// Update records the latest page fetched; it matches client.ProgressFunc
+func (p *progressReporter) Update(progress client.Progress) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.latest = progress
+}
+
+// Stop halts the spinner and clears its line
+func (p *progressReporter) Stop() {
+	close(p.stop)
+	<-p.done
+}
+
+func (p *progressReporter) loop() {
+	defer close(p.done)
+
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stop:
+			p.mu.Lock()
+			if p.drawn {
+				fmt.Fprint(p.w, "\r\033[K")
+			}
+			p.mu.Unlock()
+			return
+		case <-ticker.C:
+			if time.Since(p.started) < progressDelay {
+				continue
+			}
+			p.mu.Lock()
+			fmt.Fprintf(p.w, "\r\033[K%c %s", spinnerFrames[p.frame%len(spinnerFrames)], formatProgress(p.latest))
+			p.frame++
+			p.drawn = true
+			p.mu.Unlock()
+		}
+	}
+}
+
+// formatProgress describes a fetch in progress, e.g.
+// "Fetching releases: page 3 (245 releases, 4,980/5,000 API requests left)"
+func formatProgress(progress client.Progress) string {
+	if progress.Page == 0 {
+		return "Fetching releases..."
+	}
+
+	msg := fmt.Sprintf("Fetching releases: page %d (%d release%s", progress.Page, progress.Releases, types.PluralSuffix(progress.Releases))
+	if progress.RateLimit > 0 {
+		msg += fmt.Sprintf(", %s/%s API requests left", formatThousands(progress.RateRemaining), formatThousands(progress.RateLimit))
+	}
+	return msg + ")"
+}
+
+// formatThousands formats n with comma thousands separators
+func formatThousands(n int) string {
+	s := fmt.Sprintf("%d", n)
+	if n < 0 {
+		return "-" + formatThousands(-n)
+	}
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/nickromney-org/github-release-version-checker/pkg/client"
+)
+
+func TestFormatProgress(t *testing.T) {
+	tests := []struct {
+		name     string
+		progress client.Progress
+		want     string
+	}{
+		{
+			name: "before first page",
+			want: "Fetching releases...",
+		},
+		{
+			name:     "with rate limit",
+			progress: client.Progress{Page: 3, Releases: 245, RateRemaining: 4980, RateLimit: 5000},
+			want:     "Fetching releases: page 3 (245 releases, 4,980/5,000 API requests left)",
+		},
+		{
+			name:     "without rate limit",
+			progress: client.Progress{Page: 1, Releases: 1},
+			want:     "Fetching releases: page 1 (1 release)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatProgress(tt.progress); got != tt.want {
+				t.Errorf("formatProgress() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProgressReporter_SilentWhenFast(t *testing.T) {
+	var buf bytes.Buffer
+	p := startProgress(&buf)
+	p.Update(client.Progress{Page: 1, Releases: 5})
+	p.Stop()
+
+	if buf.Len() != 0 {
+		t.Errorf("expected no output for a fast fetch, got %q", buf.String())
+	}
+}
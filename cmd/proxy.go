@@ -0,0 +1,339 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nickromney-org/github-release-version-checker/internal/config"
+	"github.com/nickromney-org/github-release-version-checker/internal/proxy"
+	"github.com/nickromney-org/github-release-version-checker/internal/telemetry"
+	"github.com/nickromney-org/github-release-version-checker/pkg/checker"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+)
+
+var (
+	proxyListen   string
+	proxyTTL      time.Duration
+	proxyToken    string
+	proxyUpstream string
+	proxyAuthFile string
+	proxyAllow    []string
+	proxyLogs     string
+	proxyWebhook  string
+	proxyGRPC     string
+)
+
+// proxyShutdownGrace is how long requests in flight may finish once shutdown begins
+const proxyShutdownGrace = 10 * time.Second
+
+var proxyCmd = &cobra.Command{
+	Use:   "proxy",
+	Short: "Serve cached release lists to other checkers",
+	Long: `Serve GitHub's release endpoints from a shared cache, so every checker inside a
+team or company can point at one endpoint with --base-url and share one upstream
+rate limit. Responses are refreshed upstream once --ttl has passed, conditionally,
+so unchanged releases cost no rate limit, and are served stale if GitHub is
+unreachable. Only repositories, their releases and latest releases are served.
+
+Before exposing the proxy beyond localhost, require clients to send a bearer token
+(--auth-tokens-file, one token per line; clients pass theirs with -t) and limit the
+repositories they may read (--allow-repo), so it cannot be used as an open relay
+for the proxy's own GitHub token.
+
+POST /check/batch checks a whole manifest in one round trip: send
+{"checks": [{"repository": "actions/runner", "version": "2.328.0"}, ...]} and each
+result holds the analysis, as --json prints it, or its own error. Results are paged
+with ?page= and ?per_page= (at most 100), as on GitHub.
+
+Every request is logged on stderr with its repository, status, cache state and
+latency; --log-format json writes one JSON object per line for log pipelines.
+
+/healthz and /readyz serve liveness and readiness probes without a token. The
+proxy is ready while GitHub is reachable, or while it has responses to serve.
+
+With --webhook-secret-file, POST /webhooks/github accepts GitHub release webhooks
+signed with that secret and drops the repository's cached responses, so a new
+release is served at once rather than after --ttl.
+
+With --grpc-listen, the releasechecker.v1.ReleaseChecker gRPC service runs the same
+checks on a second address (Check, CheckBatch and CheckStream), with the standard
+gRPC health service for probes; clients send their proxy token as "authorization"
+metadata. The definition is in api/releasechecker/v1/checker.proto.`,
+	Example: `  github-release-version-checker proxy --listen :8080 --ttl 15m \
+    --auth-tokens-file /etc/release-proxy/tokens --allow-repo 'actions/*' --allow-repo kubernetes/kubernetes
+  github-release-version-checker --base-url http://releases.internal:8080/ -t "$PROXY_TOKEN" -c 2.328.0`,
+	Args: cobra.NoArgs,
+	RunE: runProxy,
+}
+
+func init() {
+	proxyCmd.Flags().StringVar(&proxyListen, "listen", "127.0.0.1:8080", "address to listen on")
+	proxyCmd.Flags().DurationVar(&proxyTTL, "ttl", proxy.DefaultTTL, "how long responses are served before refreshing them upstream")
+	proxyCmd.Flags().StringVarP(&proxyToken, "token", "t", "", "GitHub token used upstream (default: $GITHUB_TOKEN or gh auth token)")
+	proxyCmd.Flags().StringVar(&proxyUpstream, "upstream-url", proxy.DefaultUpstream, "API base URL to refresh from, e.g. https://github.example.com/api/v3/")
+	proxyCmd.Flags().StringVar(&proxyAuthFile, "auth-tokens-file", "", "file of bearer tokens clients must send one of, one per line")
+	proxyCmd.Flags().StringArrayVar(&proxyAllow, "allow-repo", nil, "repository clients may read, as owner/repo or a glob like actions/*; repeatable (default: any)")
+	proxyCmd.Flags().StringVar(&proxyLogs, "log-format", "text", "format of the request and upstream logs on stderr: text or json")
+	proxyCmd.Flags().StringVar(&proxyGRPC, "grpc-listen", "", "address to serve the gRPC API on, e.g. :9090 (default: not served)")
+	proxyCmd.Flags().StringVar(&proxyWebhook, "webhook-secret-file", "", "file holding the secret GitHub signs release webhooks with; enables POST /webhooks/github")
+	rootCmd.AddCommand(proxyCmd)
+}
+
+func runProxy(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	upstream, err := url.Parse(proxyUpstream)
+	if err != nil {
+		return invalidInput(fmt.Errorf("invalid --upstream-url: %w", err))
+	}
+	host := config.DefaultHost
+	if proxyUpstream != proxy.DefaultUpstream {
+		host = upstream.Hostname()
+	}
+	resolved := detectGitHubToken(proxyToken, host)
+
+	server, err := proxy.New(proxyUpstream, resolved.Value, proxyTTL)
+	if err != nil {
+		return invalidInput(err)
+	}
+	server.UserAgent = userAgent()
+	for _, pattern := range proxyAllow {
+		if err := proxy.ValidatePattern(pattern); err != nil {
+			return invalidInput(err)
+		}
+	}
+	server.Repositories = proxyAllow
+	if proxyAuthFile != "" {
+		if server.Tokens, err = readAuthTokens(proxyAuthFile); err != nil {
+			return err
+		}
+	}
+	if proxyWebhook != "" {
+		if server.WebhookSecret, err = readWebhookSecret(proxyWebhook); err != nil {
+			return err
+		}
+	}
+	if server.Logger, err = newProxyLogger(cmd.ErrOrStderr(), proxyLogs); err != nil {
+		return invalidInput(err)
+	}
+
+	listener, err := net.Listen("tcp", proxyListen)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", proxyListen, err)
+	}
+
+	if resolved.Value == "" {
+		yellow.Fprintln(cmd.ErrOrStderr(), "⚠️  No GitHub token found: upstream requests are limited to 60 an hour")
+	}
+	server.Check = proxyCheck(loopbackURL(listener.Addr()), firstToken(server.Tokens))
+
+	var grpcListener net.Listener
+	if proxyGRPC != "" {
+		if grpcListener, err = net.Listen("tcp", proxyGRPC); err != nil {
+			listener.Close()
+			return fmt.Errorf("failed to listen on %s: %w", proxyGRPC, err)
+		}
+	}
+
+	if len(server.Tokens) == 0 && (!isLoopback(listener.Addr()) || (grpcListener != nil && !isLoopback(grpcListener.Addr()))) {
+		yellow.Fprintln(cmd.ErrOrStderr(), "⚠️  Listening beyond localhost without --auth-tokens-file: anyone who can reach the proxy can use its GitHub token to read releases")
+	}
+	cyan.Fprintf(cmd.OutOrStdout(), "Serving releases from %s on http://%s/ (refreshing every %s)\n", server.Upstream, listener.Addr(), proxyTTL)
+
+	ctx, stop := stopContext(cmd.Context())
+	defer stop()
+	if grpcListener == nil {
+		return serveProxy(ctx, listener, server)
+	}
+
+	cyan.Fprintf(cmd.OutOrStdout(), "Serving the gRPC API on %s\n", grpcListener.Addr())
+	grpcErr := make(chan error, 1)
+	go func() {
+		grpcErr <- serveGRPC(ctx, grpcListener, server.NewGRPCServer())
+		// Either server stopping stops both
+		stop()
+	}()
+	err = serveProxy(ctx, listener, server)
+	stop()
+	return errors.Join(err, <-grpcErr)
+}
+
+// readWebhookSecret reads the webhook secret in path, without surrounding whitespace
+func readWebhookSecret(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read webhook secret: %w", err)
+	}
+	secret := strings.TrimSpace(string(data))
+	if secret == "" {
+		return "", invalidInput(fmt.Errorf("no webhook secret in %s", path))
+	}
+	return secret, nil
+}
+
+// newProxyLogger logs to w as logfmt-style text or, for log pipelines, one JSON
+// object per line
+func newProxyLogger(w io.Writer, format string) (*slog.Logger, error) {
+	switch format {
+	case "text":
+		return slog.New(slog.NewTextHandler(w, nil)), nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(w, nil)), nil
+	}
+	return nil, fmt.Errorf("invalid --log-format %q: use text or json", format)
+}
+
+// readAuthTokens reads the bearer tokens in path, one per line, skipping blank
+// lines and # comments
+func readAuthTokens(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read auth tokens: %w", err)
+	}
+	var tokens []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		tokens = append(tokens, line)
+	}
+	if len(tokens) == 0 {
+		return nil, invalidInput(fmt.Errorf("no tokens in %s", path))
+	}
+	return tokens, nil
+}
+
+// proxyCheck runs the checks posted to the proxy, reading releases through the
+// proxy itself at baseURL, so they share its cache, token and allowlist
+func proxyCheck(baseURL, token string) proxy.CheckFunc {
+	return func(ctx context.Context, check proxy.BatchCheck) proxy.BatchResult {
+		// Each check is traced on its own, not as part of the proxy's whole run
+		ctx, span := tracer.StartTrace(ctx, "check", telemetry.KindServer,
+			telemetry.String("repository", check.Repository),
+			telemetry.String("comparison_version", check.Version))
+		defer span.End()
+
+		result := proxy.BatchResult{Repository: check.Repository, Version: check.Version}
+		analysis, err := func() (*checker.Analysis, error) {
+			repoConfig, err := lookupRepository(check.Repository)
+			if err != nil {
+				return nil, invalidInput(err)
+			}
+			owner, repo := repoConfig.Source()
+			ghClient := newGitHubClient(token, owner, repo)
+			if err := ghClient.SetBaseURL(baseURL); err != nil {
+				return nil, err
+			}
+			analysis, err := analyseTraced(ctx, newChecker(ghClient, repoConfig), repoConfig.FullName(), check.Version)
+			if err != nil {
+				return nil, err
+			}
+			analysis.Repository = repoConfig.FullName()
+			analysis.Upstream = repoConfig.Upstream
+			return analysis, nil
+		}()
+		if err == nil {
+			result.Result, err = analysis.MarshalJSON()
+		}
+		if err != nil {
+			span.RecordError(err)
+			result.Error = classifyError(err)
+			return result
+		}
+		result.Success = true
+		return result
+	}
+}
+
+// loopbackURL returns the proxy's own URL for addr, through the loopback
+// interface when it listens on every address
+func loopbackURL(addr net.Addr) string {
+	tcp, ok := addr.(*net.TCPAddr)
+	if !ok {
+		return "http://" + addr.String() + "/"
+	}
+	ip := tcp.IP
+	if ip == nil || ip.IsUnspecified() {
+		ip = net.IPv4(127, 0, 0, 1)
+	}
+	return "http://" + net.JoinHostPort(ip.String(), strconv.Itoa(tcp.Port)) + "/"
+}
+
+// firstToken returns the first of tokens, or "" if there are none
+func firstToken(tokens []string) string {
+	if len(tokens) == 0 {
+		return ""
+	}
+	return tokens[0]
+}
+
+// isLoopback reports whether addr only accepts local connections
+func isLoopback(addr net.Addr) bool {
+	tcp, ok := addr.(*net.TCPAddr)
+	return ok && tcp.IP.IsLoopback()
+}
+
+// serveProxy serves handler on listener until ctx is cancelled, then shuts
+// down, letting requests in flight finish
+func serveProxy(ctx context.Context, listener net.Listener, handler http.Handler) error {
+	srv := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+	errs := make(chan error, 1)
+	go func() { errs <- srv.Serve(listener) }()
+
+	select {
+	case err := <-errs:
+		return fmt.Errorf("proxy stopped: %w", err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), proxyShutdownGrace)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shut down proxy: %w", err)
+	}
+	if err := <-errs; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// serveGRPC serves g on listener until ctx is cancelled, then stops it, letting
+// calls in flight finish for up to proxyShutdownGrace
+func serveGRPC(ctx context.Context, listener net.Listener, g *grpc.Server) error {
+	errs := make(chan error, 1)
+	go func() { errs <- g.Serve(listener) }()
+
+	select {
+	case err := <-errs:
+		return fmt.Errorf("gRPC server stopped: %w", err)
+	case <-ctx.Done():
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		g.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(proxyShutdownGrace):
+		g.Stop()
+	}
+	// Serve reports ErrServerStopped if shutdown began before it started
+	if err := <-errs; err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+		return err
+	}
+	return nil
+}
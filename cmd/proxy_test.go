@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/nickromney-org/github-release-version-checker/internal/proxy"
+	"github.com/nickromney-org/github-release-version-checker/internal/testing/githubtest"
+)
+
+func TestReadAuthTokens(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+		wantErr bool
+	}{
+		{name: "one per line", content: "alpha\n  bravo  \n", want: []string{"alpha", "bravo"}},
+		{name: "skips comments and blanks", content: "# team tokens\n\nalpha\r\n", want: []string{"alpha"}},
+		{name: "empty", content: "# none yet\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "tokens")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			got, err := readAuthTokens(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readAuthTokens() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readAuthTokens() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := readAuthTokens(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("readAuthTokens() of a missing file succeeded")
+	}
+}
+
+func TestReadWebhookSecret(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "secret")
+	if err := os.WriteFile(path, []byte("s3cret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := readWebhookSecret(path); err != nil || got != "s3cret" {
+		t.Errorf("readWebhookSecret() = %q, %v, want s3cret", got, err)
+	}
+
+	empty := filepath.Join(dir, "empty")
+	if err := os.WriteFile(empty, []byte(" \n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readWebhookSecret(empty); err == nil {
+		t.Error("readWebhookSecret() of an empty file succeeded")
+	}
+}
+
+func TestIsLoopback(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{ip: "127.0.0.1", want: true},
+		{ip: "::1", want: true},
+		{ip: "0.0.0.0", want: false},
+		{ip: "10.1.2.3", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := isLoopback(&net.TCPAddr{IP: net.ParseIP(tt.ip)}); got != tt.want {
+			t.Errorf("isLoopback(%s) = %v, want %v", tt.ip, got, tt.want)
+		}
+	}
+}
+
+func TestNewProxyLogger(t *testing.T) {
+	for _, format := range []string{"text", "json"} {
+		if _, err := newProxyLogger(io.Discard, format); err != nil {
+			t.Errorf("newProxyLogger(%s) error = %v", format, err)
+		}
+	}
+	if _, err := newProxyLogger(io.Discard, "logfmt"); err == nil {
+		t.Error("newProxyLogger(logfmt) succeeded")
+	}
+}
+
+func TestProxyCheck(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	upstream := githubtest.NewServer(githubtest.MustLoadFixture("actions/runner"))
+	defer upstream.Close()
+	server, err := proxy.New(upstream.URL, "", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server.Tokens = []string{"alpha"}
+	front := httptest.NewServer(server)
+	defer front.Close()
+	check := proxyCheck(front.URL+"/", "alpha")
+
+	tests := []struct {
+		name      string
+		check     proxy.BatchCheck
+		wantError string // JSON error code; "" for success
+	}{
+		{name: "current version", check: proxy.BatchCheck{Repository: "actions/runner", Version: "2.329.0"}},
+		{name: "predefined name", check: proxy.BatchCheck{Repository: "runner", Version: "2.328.0"}},
+		{name: "unknown version", check: proxy.BatchCheck{Repository: "actions/runner", Version: "9.9.9"}, wantError: errorCodeVersionNotFound},
+		{name: "invalid repository", check: proxy.BatchCheck{Repository: "actions/"}, wantError: errorCodeInvalidInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := check(context.Background(), tt.check)
+			if result.Repository != tt.check.Repository || result.Version != tt.check.Version {
+				t.Errorf("result for %s@%s, want %s@%s", result.Repository, result.Version, tt.check.Repository, tt.check.Version)
+			}
+			if tt.wantError == "" {
+				var analysis map[string]any
+				if !result.Success || json.Unmarshal(result.Result, &analysis) != nil || analysis["repository"] != "actions/runner" {
+					t.Errorf("result = %+v, want a successful analysis of actions/runner", result)
+				}
+				return
+			}
+			if e, ok := result.Error.(jsonError); result.Success || !ok || e.Code != tt.wantError {
+				t.Errorf("result = %+v, want error %s", result, tt.wantError)
+			}
+		})
+	}
+
+	// Checks read through the proxy, so repeating one reaches GitHub no more
+	before := len(upstream.Requests())
+	check(context.Background(), tests[0].check)
+	if after := len(upstream.Requests()); after != before {
+		t.Errorf("repeated check sent %d upstream requests, want none", after-before)
+	}
+}
+
+func TestLoopbackURL(t *testing.T) {
+	tests := []struct {
+		addr net.Addr
+		want string
+	}{
+		{addr: &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 8080}, want: "http://127.0.0.1:8080/"},
+		{addr: &net.TCPAddr{IP: net.ParseIP("0.0.0.0"), Port: 8080}, want: "http://127.0.0.1:8080/"},
+		{addr: &net.TCPAddr{IP: net.ParseIP("::"), Port: 8080}, want: "http://127.0.0.1:8080/"},
+		{addr: &net.TCPAddr{IP: net.ParseIP("::1"), Port: 8080}, want: "http://[::1]:8080/"},
+	}
+
+	for _, tt := range tests {
+		if got := loopbackURL(tt.addr); got != tt.want {
+			t.Errorf("loopbackURL(%s) = %s, want %s", tt.addr, got, tt.want)
+		}
+	}
+}
+
+func TestServeGRPC(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := proxy.New("", "", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- serveGRPC(ctx, listener, s.NewGRPCServer()) }()
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("serveGRPC() error = %v, want nil after shutdown", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("serveGRPC() did not stop")
+	}
+}
@@ -0,0 +1,243 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	fieldsFlag []string // --fields
+	queryFlag  string   // --query
+)
+
+// queryStep is one step of a jq-style path: .key, [N] or []
+type queryStep struct {
+	Key     string
+	Index   int
+	IsIndex bool
+	Iterate bool
+}
+
+// parseQuery parses a jq-style path such as .recent_releases[].version.
+// Only object keys, array indexes and [] iteration are supported.
+func parseQuery(query string) ([]queryStep, error) {
+	query = strings.TrimSpace(query)
+	if !strings.HasPrefix(query, ".") {
+		return nil, fmt.Errorf("invalid query %q: must start with '.'", query)
+	}
+
+	var steps []queryStep
+	rest := query
+	if rest == "." {
+		return nil, nil // Identity
+	}
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end == -1 {
+				end = len(rest)
+			}
+			key := rest[:end]
+			if key == "" {
+				if end < len(rest) && rest[end] == '[' {
+					continue // ".[0]" is the same as "[0]"
+				}
+				return nil, fmt.Errorf("invalid query %q: empty key", query)
+			}
+			steps = append(steps, queryStep{Key: key})
+			rest = rest[end:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end == -1 {
+				return nil, fmt.Errorf("invalid query %q: missing ']'", query)
+			}
+			inner := rest[1:end]
+			if inner == "" {
+				steps = append(steps, queryStep{Iterate: true})
+			} else {
+				index, err := strconv.Atoi(inner)
+				if err != nil {
+					return nil, fmt.Errorf("invalid query %q: index %q is not a number", query, inner)
+				}
+				steps = append(steps, queryStep{Index: index, IsIndex: true})
+			}
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("invalid query %q: unexpected %q", query, rest[0])
+		}
+	}
+	return steps, nil
+}
+
+// evalQuery applies the steps to a decoded JSON value, returning every match.
+// Missing keys and out-of-range indexes give null, as in jq.
+func evalQuery(value any, steps []queryStep) ([]any, error) {
+	values := []any{value}
+	for _, step := range steps {
+		var next []any
+		for _, v := range values {
+			switch {
+			case step.Iterate:
+				items, ok := v.([]any)
+				if !ok {
+					return nil, fmt.Errorf("cannot iterate over %s", jsonTypeName(v))
+				}
+				next = append(next, items...)
+			case step.IsIndex:
+				if v == nil {
+					next = append(next, nil)
+					continue
+				}
+				items, ok := v.([]any)
+				if !ok {
+					return nil, fmt.Errorf("cannot index %s with a number", jsonTypeName(v))
+				}
+				index := step.Index
+				if index < 0 {
+					index += len(items)
+				}
+				if index < 0 || index >= len(items) {
+					next = append(next, nil)
+				} else {
+					next = append(next, items[index])
+				}
+			default:
+				if v == nil {
+					next = append(next, nil)
+					continue
+				}
+				obj, ok := v.(map[string]any)
+				if !ok {
+					return nil, fmt.Errorf("cannot index %s with %q", jsonTypeName(v), step.Key)
+				}
+				next = append(next, obj[step.Key])
+			}
+		}
+		values = next
+	}
+	return values, nil
+}
+
+// jsonTypeName names a decoded JSON value's type for error messages
+func jsonTypeName(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case map[string]any:
+		return "an object"
+	case []any:
+		return "an array"
+	case string:
+		return "a string"
+	case bool:
+		return "a boolean"
+	default:
+		return "a number"
+	}
+}
+
+// decodeJSON decodes data keeping numbers exactly as written
+func decodeJSON(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// selectFields reduces a JSON object to the given top-level fields, in the order given
+func selectFields(data []byte, fields []string) ([]byte, error) {
+	value, err := decodeJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	obj, ok := value.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("--fields needs a JSON object, got %s", jsonTypeName(value))
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, field := range fields {
+		v, ok := obj[field]
+		if !ok {
+			return nil, fmt.Errorf("unknown field %q (available: %s)", field, strings.Join(sortedKeys(obj), ", "))
+		}
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(field)
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(encoded)
+	}
+	buf.WriteByte('}')
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, buf.Bytes(), "", "  "); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// sortedKeys returns an object's keys in sorted order
+func sortedKeys(obj map[string]any) []string {
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// writeQueryResults writes each match on its own line: strings raw (like jq -r),
+// everything else as compact JSON
+func writeQueryResults(w io.Writer, results []any) error {
+	for _, result := range results {
+		if s, ok := result.(string); ok {
+			fmt.Fprintln(w, s)
+			continue
+		}
+		encoded, err := json.Marshal(result)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, string(encoded))
+	}
+	return nil
+}
+
+// writeJSONDocument writes a JSON document, applying --query if set
+func writeJSONDocument(w io.Writer, data []byte) error {
+	if queryFlag == "" {
+		fmt.Fprintln(w, string(data))
+		return nil
+	}
+
+	steps, err := parseQuery(queryFlag)
+	if err != nil {
+		return err
+	}
+	value, err := decodeJSON(data)
+	if err != nil {
+		return err
+	}
+	results, err := evalQuery(value, steps)
+	if err != nil {
+		return fmt.Errorf("query %q: %w", queryFlag, err)
+	}
+	return writeQueryResults(w, results)
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+)
+
+const queryTestJSON = `{
+  "latest_version": "2.329.0",
+  "status": "warning",
+  "releases_behind": 2,
+  "is_latest": false,
+  "first_newer_version": null,
+  "recent_releases": [
+    {"version": "2.329.0", "is_latest": true},
+    {"version": "2.328.0", "is_latest": false}
+  ]
+}`
+
+func TestQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    string
+		wantErr bool
+	}{
+		{name: "string printed raw", query: ".latest_version", want: "2.329.0\n"},
+		{name: "number", query: ".releases_behind", want: "2\n"},
+		{name: "boolean", query: ".is_latest", want: "false\n"},
+		{name: "missing key is null", query: ".nope", want: "null\n"},
+		{name: "iterate", query: ".recent_releases[].version", want: "2.329.0\n2.328.0\n"},
+		{name: "index", query: ".recent_releases[1].version", want: "2.328.0\n"},
+		{name: "negative index", query: ".recent_releases[-1].is_latest", want: "false\n"},
+		{name: "out of range is null", query: ".recent_releases[5]", want: "null\n"},
+		{name: "object as compact JSON", query: ".recent_releases[0]", want: `{"is_latest":true,"version":"2.329.0"}` + "\n"},
+		{name: "iterate a string", query: ".status[]", wantErr: true},
+		{name: "no leading dot", query: "status", wantErr: true},
+		{name: "unclosed bracket", query: ".recent_releases[0", wantErr: true},
+		{name: "bad index", query: ".recent_releases[x]", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func(q string) { queryFlag = q }(queryFlag)
+			queryFlag = tt.query
+
+			var buf bytes.Buffer
+			err := writeJSONDocument(&buf, []byte(queryTestJSON))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("writeJSONDocument() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && buf.String() != tt.want {
+				t.Errorf("writeJSONDocument() = %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectFields(t *testing.T) {
+	got, err := selectFields([]byte(queryTestJSON), []string{"status", "latest_version"})
+	if err != nil {
+		t.Fatalf("selectFields() error = %v", err)
+	}
+	want := "{\n  \"status\": \"warning\",\n  \"latest_version\": \"2.329.0\"\n}"
+	if string(got) != want {
+		t.Errorf("selectFields() = %s, want %s", got, want)
+	}
+
+	if _, err := selectFields([]byte(queryTestJSON), []string{"version"}); err == nil {
+		t.Error("selectFields() with an unknown field: want error")
+	}
+}
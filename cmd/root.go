@@ -1,39 +1,77 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
-	"os/exec"
+	"os/signal"
 	"strings"
+	"syscall"
 	"time"
 
-	"github.com/Masterminds/semver/v3"
 	colour "github.com/fatih/color"
 	"github.com/nickromney-org/github-release-version-checker/internal/cache"
 	"github.com/nickromney-org/github-release-version-checker/internal/config"
 	"github.com/nickromney-org/github-release-version-checker/internal/policy"
 	"github.com/nickromney-org/github-release-version-checker/pkg/checker"
 	"github.com/nickromney-org/github-release-version-checker/pkg/client"
+	pkgpolicy "github.com/nickromney-org/github-release-version-checker/pkg/policy"
+	"github.com/nickromney-org/github-release-version-checker/pkg/render"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 var (
 	comparisonVersion string
 	criticalAgeDays   int
 	maxAgeDays        int
-	verbose           bool
+	verbose           int
 	jsonOutput        bool
 	ciOutput          bool
 	quiet             bool
 	githubToken       string
 	showVersion       bool
 	noCache           bool
+	offline           bool
+	annotationLevels  map[string]string
+	noAnnotations     bool
+	summaryTemplate   string
+	summaryExclude    []string
+	outputFilePath    string
+	dateFormatFlag    string
+	timelineWindow    int
+	timelineMinRows   int
+	timelineMaxRows   int
+	maxNewer          int
+	columnsFlag       []string
+	maxTableWidth     int
 
 	// New flags for multi-repository support
 	repository  string
 	cachePath   string
+	analysisDir string
+	notFoundTTL time.Duration
 	policyType  string
 	maxVersions int
+	ordering    string
+	track       string
+	channel     string
+	channelTags map[string]string
+	zeroMajor   string
+	maintenance string
+	latestFrom  string
+	upstream    string
+	suffix      string
+	assets      []string
+	platform    string
+	minAge      int
+	unknownVer  string
+	vulns       int
+
+	analysisCache checker.AnalysisCache // Resolved from --analysis-cache
 
 	// Version information (set via SetVersionInfo from main)
 	appVersion = "dev"
@@ -88,185 +126,481 @@ Supports multiple repositories with both time-based (days) and version-based
 
   # CI mode for GitHub Actions
   github-release-version-checker --repo kubernetes/kubernetes -c 1.28.0 --ci`,
-	RunE: run,
+	PersistentPreRunE: resolvePersistentFlags,
+	RunE:              run,
 }
 
 func init() {
-	rootCmd.Flags().StringVarP(&comparisonVersion, "compare", "c", "", "version to compare against (e.g., 2.327.1)")
+	rootCmd.SetFlagErrorFunc(flagError)
+	rootCmd.PersistentFlags().StringVar(&userAgentFlag, "user-agent", "", "User-Agent for GitHub API requests (default: github-release-version-checker/<version>)")
+	rootCmd.PersistentFlags().StringArrayVar(&headerFlags, "header", nil, "extra header for GitHub API requests, as 'Name: Value'; repeatable")
+	rootCmd.PersistentFlags().StringVar(&baseURLFlag, "base-url", "", "API base URL to read releases from, e.g. a release proxy (default: the repository's host)")
+	rootCmd.PersistentFlags().StringVar(&dateFormatFlag, "date-format", "uk", "date format: preset (uk, us, eu, iso) or Go time layout (e.g., 2006-01-02)")
+	rootCmd.Flags().StringSliceVarP(&compareFlag, "compare", "c", nil, "version to compare against (e.g., 2.327.1, 2.327 for its latest patch, or latest-1 for the newest release one minor version behind); repeat or comma-separate to check several in one table")
+	rootCmd.Flags().StringVar(&detectFlag, "detect", "", "read the version to compare from the working directory: git (highest semver tag reachable from HEAD), file (./VERSION) or file:PATH")
+	rootCmd.MarkFlagsMutuallyExclusive("compare", "detect")
+	rootCmd.Flags().StringVar(&terraformDir, "terraform", "", "check a Terraform root module: Terraform against required_version and each provider in .terraform.lock.hcl")
+	rootCmd.Flags().StringToStringVar(&providerRepos, "provider-repo", nil, "repository a Terraform provider is released from, e.g. example.com/acme/widget=acme/tf-widget; repeatable")
+	rootCmd.Flags().StringVar(&goModFile, "go-mod", "", "check a go.mod: the Go toolchain and each --go-module at its selected version")
+	rootCmd.Flags().StringArrayVar(&goModules, "go-module", nil, "module to check with --go-mod, as a github.com path or MODULE=OWNER/REPO; repeatable")
+	rootCmd.Flags().StringSliceVar(&normaliseFlag, "normalise", []string{"trim-space", "v-prefix", "strip-build"}, "clean-ups applied to --compare before parsing: trim-space, v-prefix (accept V1.2.3), strip-build (+metadata), or none")
 	rootCmd.Flags().IntVarP(&criticalAgeDays, "critical-days", "d", 12, "days before critical warning")
 	rootCmd.Flags().IntVarP(&maxAgeDays, "max-days", "m", 30, "days before version expires")
-	rootCmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "verbose output")
+	rootCmd.Flags().CountVarP(&verbose, "verbose", "v", "verbose output; repeat for tracing (-vv cache decisions, -vvv HTTP requests and skipped releases)")
 	rootCmd.Flags().BoolVar(&jsonOutput, "json", false, "output as JSON")
+	rootCmd.Flags().StringSliceVar(&fieldsFlag, "fields", nil, "JSON fields to output (e.g., latest_version,status); implies --json")
+	rootCmd.Flags().StringVar(&queryFlag, "query", "", "jq-style path to print (e.g., '.recent_releases[].version'); strings are printed raw; implies --json")
 	rootCmd.Flags().BoolVar(&ciOutput, "ci", false, "format output for CI/GitHub Actions")
 	rootCmd.Flags().BoolVarP(&quiet, "quiet", "q", false, "quiet output (suppress expiry table)")
-	rootCmd.Flags().StringVarP(&githubToken, "token", "t", os.Getenv("GITHUB_TOKEN"), "GitHub token (or GITHUB_TOKEN env var)")
+	rootCmd.Flags().IntVar(&timelineWindow, "timeline-window", checker.DefaultTimelineWindowDays, "days of releases to show in the timeline table")
+	rootCmd.Flags().IntVar(&timelineMinRows, "timeline-min-rows", checker.DefaultTimelineMinRows, "minimum releases to show in the timeline table")
+	rootCmd.Flags().StringSliceVar(&columnsFlag, "columns", nil, "timeline table columns to show: version, released, expires, status, and optionally gap")
+	rootCmd.Flags().IntVar(&maxTableWidth, "max-width", 0, "maximum timeline table width in characters; longer rows are truncated (0 = no limit)")
+	rootCmd.Flags().IntVar(&timelineMaxRows, "timeline-max-rows", 0, "maximum releases to show in the timeline table, newest kept (0 = no limit)")
+	rootCmd.Flags().IntVar(&maxNewer, "max-newer", 0, "maximum newer releases to list, newest kept; total_newer still counts them all (0 = no limit)")
+	rootCmd.Flags().StringVarP(&outputFilePath, "output-file", "o", "", "write results to a file instead of stdout")
+	rootCmd.Flags().StringVarP(&githubToken, "token", "t", "", "GitHub token (default: GH_TOKEN, GITHUB_TOKEN, then gh's stored login)")
 	rootCmd.Flags().BoolVar(&showVersion, "version", false, "show version information")
+	rootCmd.Flags().StringVar(&configFilePath, "config", config.DefaultFileName, "config file (created by init; used if present)")
+	rootCmd.Flags().IntVar(&concurrency, "concurrency", defaultConcurrency, fmt.Sprintf("repositories to check at once when the config file lists several (1-%d)", maxConcurrency))
+	rootCmd.Flags().BoolVar(&keepGoing, "keep-going", false, "in batch mode, report per-repository errors inline and check the rest")
+	rootCmd.Flags().StringVar(&aggregatePolicy, "aggregate", aggregateAnyExpired, "how batch results decide the exit code: any-expired-fails, worst-of, percentage-threshold")
+	rootCmd.Flags().IntVar(&aggregateThreshold, "aggregate-threshold", 10, "percentage of repositories that may be expired or unchecked with --aggregate percentage-threshold")
+	rootCmd.Flags().StringVar(&latestFrom, "latest-from", "", "which release is latest when GitHub marks one other than the highest version: highest (default) or marked")
+	rootCmd.Flags().BoolVar(&strict, "strict", false, "exit non-zero unless on the latest version (warnings fail too)")
+	rootCmd.Flags().BoolVar(&exitStatus, "exit-status", false, fmt.Sprintf("exit with a code per outcome: %d warning, %d critical, %d expired, %d invalid input, %d unavailable (e.g. rate limited)",
+		exitWarning, exitCritical, exitExpired, exitInvalid, exitUnavailable))
+	rootCmd.Flags().StringVar(&failOn, "fail-on", "", "exit non-zero when a single check's status is this or worse: warning, critical or expired")
+	rootCmd.Flags().BoolVar(&exitDegradedFlag, "exit-degraded", false, fmt.Sprintf("exit with code %d when results are based on incomplete data (e.g., a truncated release list)", exitDegraded))
 	rootCmd.Flags().BoolVarP(&noCache, "no-cache", "n", false, "bypass embedded cache and always fetch from GitHub API")
+	rootCmd.Flags().BoolVar(&offline, "offline", false, "check against cached releases only (from cache warm, bundle import or the embedded cache), without calling the API")
+	rootCmd.Flags().StringToStringVar(&annotationLevels, "annotation-level", nil, "CI annotation level per status (e.g., warning=notice,critical=warning,expired=error; levels: notice, warning, error, none)")
+	rootCmd.Flags().BoolVar(&noAnnotations, "no-annotations", false, "suppress CI status annotations")
+	rootCmd.Flags().StringVar(&summaryTemplate, "summary-template", "", "path to a Go template for the GitHub job summary")
+	rootCmd.Flags().StringVar(&notifyWebhook, "notify-webhook", "", "Slack, Teams or other webhook URL to post to when the version needs attention")
+	rootCmd.Flags().StringVar(&notifyTemplatePath, "notify-template", "", "path to a Go template for --notify-webhook messages")
+	rootCmd.Flags().StringVar(&digestFlag, "digest", "", "in batch mode, post status changes to --notify-webhook as one digest: daily")
+	rootCmd.Flags().StringVar(&digestStatePath, "digest-state", "", "file tracking statuses between digests (default digest.json in the cache directory)")
+	rootCmd.Flags().StringSliceVar(&summaryExclude, "summary-exclude", nil, "job summary sections to omit: header, table, action, updates, timestamp")
 
 	// Multi-repository support flags
 	rootCmd.Flags().StringVarP(&repository, "repo", "r", "", "repository to check (format: owner/repo, e.g., 'kubernetes/kubernetes', 'pulumi/pulumi')")
+	// Lock files name their own repositories and versions; a group of more than
+	// two flags would make every pair exclusive, so each conflict is declared alone
+	rootCmd.MarkFlagsMutuallyExclusive("terraform", "go-mod")
+	for _, lockFile := range []string{"terraform", "go-mod"} {
+		for _, flag := range []string{"repo", "compare", "detect"} {
+			rootCmd.MarkFlagsMutuallyExclusive(lockFile, flag)
+		}
+	}
 	rootCmd.Flags().StringVar(&cachePath, "cache", "", "path to custom cache file")
+	rootCmd.Flags().StringVar(&analysisDir, "analysis-cache", "", "directory to keep analyses in, reused while the releases, settings and date are unchanged")
+	rootCmd.Flags().StringVar(&cachePublicKeyPath, "cache-public-key", "", "minisign public key; release lists kept by cache warm are used only if signed with it")
+	rootCmd.Flags().DurationVar(&notFoundTTL, "not-found-ttl", 10*time.Minute, "how long --analysis-cache remembers a version that does not exist, so repeated checks skip fetching releases (0 disables)")
 	rootCmd.Flags().StringVar(&policyType, "policy", "", "policy type: 'days' or 'versions' (auto-detected if not specified)")
 	rootCmd.Flags().IntVar(&maxVersions, "max-versions", 3, "maximum minor versions behind before expiry (for version-based policy)")
+	rootCmd.Flags().StringVar(&zeroMajor, "zero-major", "", "how version-based policies treat 0.x versions: minor-breaking (minor bumps are breaking, default) or semver")
+	rootCmd.Flags().StringVar(&maintenance, "maintenance-window", "", "when updates roll out, e.g. 'first tuesday monthly' or 'every wednesday'; days policies report the last window before expiry")
+	rootCmd.Flags().StringVar(&upstream, "upstream", "", "for forks and mirrors: check this repository's releases (owner/repo) while reporting under --repo")
+	rootCmd.Flags().StringVar(&suffix, "version-suffix", "", "regular expression for a fork-specific suffix stripped from compared versions and release tags, e.g. '-corp\\.\\d+'")
+	rootCmd.Flags().StringVar(&ordering, "ordering", "", "which releases are newer: semver (any higher version, default) or date (higher versions published later, for repos that backport)")
+	rootCmd.Flags().StringVar(&track, "track", "", "which releases can be the latest: stable (default) or prerelease (release candidates count too)")
+	rootCmd.Flags().StringVar(&channel, "channel", "", "release channel to check, e.g. beta or rc, for repos releasing parallel channels; stable releases count on every channel")
+	rootCmd.Flags().IntVar(&minAge, "min-release-age", 0, "days a release must have been out before it is recommended, e.g. 3 to skip day-zero releases")
+	rootCmd.Flags().IntVar(&vulns, "vulnerabilities", 0, fmt.Sprintf("known vulnerabilities in the compared version that updating fixes, e.g. from a scanner; each adds %d to drift_score", checker.DriftWeightVulnerability))
+	rootCmd.Flags().StringVar(&unknownVer, "unknown-version-policy", "", "what a compared version that is not a release, such as a custom build, results in: error (default), expired or warning")
+	rootCmd.Flags().StringSliceVar(&assets, "require-assets", nil, "only recommend releases with an asset for each platform, e.g. linux-x64,linux-arm64,win-x64, in case assets are published late")
+	rootCmd.Flags().StringVar(&platform, "platform", "", "only recommend releases that shipped assets for this OS/ARCH, e.g. linux/arm64")
+	rootCmd.Flags().StringToStringVar(&channelTags, "channel-pattern", nil, "assign tags matching a regular expression to a release channel, e.g. beta='-beta\\.'; repeatable")
 }
 
 func Execute() error {
-	return rootCmd.Execute()
+	// Write through colour's writers, which translate ANSI colour codes on Windows
+	// consoles that do not process them natively
+	rootCmd.SetOut(colour.Output)
+	rootCmd.SetErr(colour.Error)
+	return execute(context.Background(), os.Args[1:])
 }
 
-// detectGitHubToken attempts to find a GitHub token from multiple sources
-func detectGitHubToken(providedToken string) string {
-	// 1. Use explicitly provided token (via -t flag or GITHUB_TOKEN env var)
-	//    Note: GITHUB_TOKEN is automatically available in GitHub Actions
-	if providedToken != "" {
-		return providedToken
+// execute runs the command line args and reports any error: as the JSON error
+// envelope on stdout when JSON output is asked for, otherwise on stderr
+func execute(ctx context.Context, args []string) error {
+	// Errors are printed here so exit codes without a message stay quiet, and in
+	// JSON mode so is usage, so stdout holds only the envelope
+	rootCmd.SilenceErrors = true
+	rootCmd.SilenceUsage = jsonRequested(args)
+	rootCmd.SetArgs(args)
+
+	// Trace the run when an OTLP endpoint is configured
+	ctx, span := startTracing(ctx, rootCmd.ErrOrStderr())
+
+	// Stop gracefully on SIGINT and SIGTERM, e.g. when a CronJob is terminated
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	ctx, cancel := withShutdown(ctx, signals, shutdownGrace, rootCmd.ErrOrStderr())
+	defer cancel()
+
+	err := rootCmd.ExecuteContext(ctx)
+	finishTracing(span, err, rootCmd.ErrOrStderr())
+	if err != nil && (jsonOutput || jsonRequested(args)) {
+		// Flag and setup errors come before run writes its own envelope; exit
+		// errors are outcomes the JSON output has already reported
+		var exitErr *exitError
+		if !errors.As(err, &exitErr) {
+			outputErrorJSON(rootCmd.OutOrStdout(), err)
+		}
+		err = &exitError{code: ExitCode(err)}
 	}
+	if err != nil && !isQuietExit(err) {
+		fmt.Fprintln(rootCmd.ErrOrStderr(), "Error:", err)
+	}
+	return err
+}
 
-	// 2. Try to get token from GitHub CLI
-	ghToken, err := getGitHubCLIToken()
-	if err == nil && ghToken != "" {
-		return ghToken
+// jsonRequested reports whether args ask for JSON output, with --json, --fields
+// or --query, even if parsing them stops before the flag is reached
+func jsonRequested(args []string) bool {
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+		name, value, hasValue := strings.Cut(arg, "=")
+		switch name {
+		case "--json":
+			if !hasValue || value != "false" {
+				return true
+			}
+		case "--fields", "--query":
+			return true
+		}
 	}
+	return false
+}
 
-	// 3. No token found - will use unauthenticated requests
-	return ""
+// flagError marks a flag that cannot be parsed as invalid input
+func flagError(cmd *cobra.Command, err error) error {
+	return invalidInput(err)
 }
 
-// getGitHubCLIToken attempts to retrieve a token from the GitHub CLI
-func getGitHubCLIToken() (string, error) {
-	cmd := exec.Command("gh", "auth", "token")
-	output, err := cmd.Output()
+// resolvePersistentFlags applies flags shared by all commands
+func resolvePersistentFlags(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	format, err := resolveDateFormat(dateFormatFlag)
 	if err != nil {
-		return "", err
+		return invalidInput(err)
 	}
+	activeDateFormat = format
 
-	token := strings.TrimSpace(string(output))
-	if token == "" {
-		return "", fmt.Errorf("gh auth token returned empty")
+	if requestHeaders, err = parseHeaders(headerFlags); err != nil {
+		return invalidInput(err)
 	}
-
-	return token, nil
+	return invalidInput(validateBaseURL(baseURLFlag))
 }
 
-func run(cmd *cobra.Command, args []string) error {
+func run(cmd *cobra.Command, args []string) (err error) {
 	// Disable automatic usage printing on error
 	cmd.SilenceUsage = true
 
+	// Resolve where results are written
+	w, closeOutput, err := openOutput(cmd.OutOrStdout(), outputFilePath)
+	if err != nil {
+		w = cmd.OutOrStdout()
+	} else {
+		defer closeOutput()
+	}
+
+	// In JSON mode every error is written as a JSON error envelope instead of
+	// to stderr. Exit errors are outcomes the output has already reported.
+	defer func() {
+		var exitErr *exitError
+		if err == nil || !jsonOutput || errors.As(err, &exitErr) {
+			return
+		}
+		outputErrorJSON(w, err)
+		err = &exitError{code: ExitCode(err)}
+	}()
+	if err != nil {
+		return err
+	}
+
 	// Show version if requested
 	if showVersion {
-		fmt.Printf("github-release-version-checker %s\n", appVersion)
-		fmt.Printf("Build time: %s\n", buildTime)
-		fmt.Printf("Git commit: %s\n", gitCommit)
+		fmt.Fprintf(w, "github-release-version-checker %s\n", appVersion)
+		fmt.Fprintf(w, "Build time: %s\n", buildTime)
+		fmt.Fprintf(w, "Git commit: %s\n", gitCommit)
 		return nil
 	}
 
+	// Load the config file, if any; flags take precedence over its settings
+	fileConfig, err := loadConfigFile(cmd.Flags())
+	if err != nil {
+		return &configError{err}
+	}
+	if fileConfig != nil {
+		applyConfigFile(cmd.Flags(), fileConfig)
+	}
+
 	// Validate inputs
+	if offline && noCache {
+		return invalidInput(fmt.Errorf("--offline and --no-cache cannot be used together"))
+	}
 	if criticalAgeDays >= maxAgeDays {
-		return fmt.Errorf("critical-days (%d) must be less than max-days (%d)", criticalAgeDays, maxAgeDays)
+		return invalidInput(fmt.Errorf("critical-days (%d) must be less than max-days (%d)", criticalAgeDays, maxAgeDays))
+	}
+	if err := parseFailOn(failOn); err != nil {
+		return invalidInput(err)
+	}
+	if err := validateAggregate(aggregatePolicy, aggregateThreshold); err != nil {
+		return invalidInput(err)
 	}
 
-	// Auto-detect GitHub token from multiple sources if not provided
-	token := detectGitHubToken(githubToken)
-
-	// Resolve repository configuration
-	var repoConfig *config.RepositoryConfig
-	var err error
+	// Resolve comparison version normalisation
+	if normalisation, err = parseNormalisation(normaliseFlag); err != nil {
+		return invalidInput(err)
+	}
+	versions := uniqueVersions(compareFlag)
+	if len(versions) == 1 {
+		comparisonVersion = versions[0]
+	}
 
-	if repository != "" {
-		// Try predefined config first (for short names like "node", "k8s")
-		repoConfig, err = config.GetPredefinedConfig(repository)
+	// Read the comparison version from the working repository
+	if detectFlag != "" {
+		detected, from, err := detectVersion(cmd.Context(), detectFlag)
 		if err != nil {
-			// Not a predefined config, try parsing as owner/repo or URL
-			repoConfig, err = config.ParseRepositoryString(repository)
-			if err != nil {
-				return fmt.Errorf("invalid repository: %w", err)
+			return invalidInput(err)
+		}
+		comparisonVersion = detected
+		if verbose > 0 {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Comparing %s from %s\n", detected, from)
+		}
+	}
+
+	// Resolve CI annotation levels
+	levels, err := resolveAnnotationLevels(annotationLevels, noAnnotations)
+	if err != nil {
+		return invalidInput(err)
+	}
+	ciAnnotationLevels = levels
+
+	// --fields and --query select from the JSON output
+	if len(fieldsFlag) > 0 || queryFlag != "" {
+		if ciOutput {
+			return invalidInput(fmt.Errorf("--fields and --query cannot be used with --ci"))
+		}
+		if queryFlag != "" {
+			if _, err := parseQuery(queryFlag); err != nil {
+				return invalidInput(err)
 			}
 		}
-	} else {
-		// Default to actions/runner
-		repoConfig = &config.ConfigActionsRunner
+		jsonOutput = true
 	}
 
-	// Override policy type if specified
-	if policyType != "" {
-		switch strings.ToLower(policyType) {
-		case "days":
-			repoConfig.PolicyType = config.PolicyTypeDays
-		case "versions":
-			repoConfig.PolicyType = config.PolicyTypeVersions
-		default:
-			return fmt.Errorf("invalid policy type %q: must be 'days' or 'versions'", policyType)
+	// Resolve timeline table layout
+	columns, err := resolveTableColumns(columnsFlag)
+	if err != nil {
+		return invalidInput(err)
+	}
+	activeTableColumns = columns
+	if maxTableWidth < 0 {
+		return invalidInput(fmt.Errorf("max-width must be non-negative"))
+	}
+
+	// Resolve job summary customisation
+	if err := validateSummarySections(summaryExclude); err != nil {
+		return invalidInput(err)
+	}
+	if summaryTemplate != "" {
+		tmpl, err := loadSummaryTemplate(summaryTemplate)
+		if err != nil {
+			return invalidInput(err)
 		}
+		ciSummaryTemplate = tmpl
+	}
+	if notifyWebhook != "" {
+		tmpl, err := loadNotifyTemplate(notifyTemplatePath)
+		if err != nil {
+			return invalidInput(err)
+		}
+		notifyTemplate = tmpl
 	}
 
-	// Override max versions if specified and using version policy
-	if cmd.Flags().Changed("max-versions") {
-		repoConfig.MaxVersionsBehind = maxVersions
+	// Reuse analyses between runs; entries from earlier days can never match
+	if analysisDir != "" {
+		fileCache := checker.NewFileAnalysisCache(analysisDir)
+		if _, err := fileCache.Prune(time.Now().Add(-24 * time.Hour)); err != nil {
+			return invalidInput(fmt.Errorf("invalid analysis cache: %w", err))
+		}
+		analysisCache = fileCache
 	}
 
-	// Override critical/max days if specified and using days policy
-	if repoConfig.PolicyType == config.PolicyTypeDays {
-		if cmd.Flags().Changed("critical-days") {
-			repoConfig.CriticalDays = criticalAgeDays
+	if cachePublicKeyPath != "" {
+		key, err := cache.LoadPublicKey(cachePublicKeyPath)
+		if err != nil {
+			return invalidInput(err)
+		}
+		cachePublicKey = key
+	}
+
+	// Route each repository to its API host, auto-detecting each host's token
+	// from multiple sources if not provided
+	hosts := newAPIHosts(fileConfig, githubToken)
+
+	// Check the Terraform version and providers of a root module together
+	if terraformDir != "" {
+		entries, err := terraformRepositories(cmd.ErrOrStderr(), terraformDir, providerRepos)
+		if err != nil {
+			return invalidInput(err)
 		}
-		if cmd.Flags().Changed("max-days") {
-			repoConfig.MaxDays = maxAgeDays
+		return runBatch(cmd, w, entries, hosts)
+	}
+
+	// Check the Go toolchain and chosen modules of a go.mod together
+	if goModFile != "" {
+		modules, err := parseGoModules(goModules)
+		if err != nil {
+			return invalidInput(err)
+		}
+		entries, err := goModRepositories(cmd.ErrOrStderr(), goModFile, modules)
+		if err != nil {
+			return invalidInput(err)
 		}
+		return runBatch(cmd, w, entries, hosts)
+	}
+	if len(goModules) > 0 {
+		return invalidInput(fmt.Errorf("--go-module needs --go-mod"))
 	}
 
-	// Create GitHub client
-	ghClient := client.NewClient(token, repoConfig.Owner, repoConfig.Repo)
+	// Check every repository in the config file when it lists several
+	if entries := batchRepositories(cmd.Flags(), fileConfig); entries != nil {
+		return runBatch(cmd, w, entries, hosts)
+	}
+	if digestFlag != "" {
+		return invalidInput(fmt.Errorf("--digest needs a config file listing several repositories"))
+	}
 
-	// Create cache manager (not used yet, but will be in future phases)
-	_ = cache.NewManager(cachePath)
+	// Resolve repository configuration
+	repoConfig, configVersion, err := configRepository(cmd.Flags(), fileConfig)
+	if err != nil {
+		return &configError{err}
+	}
+	if repoConfig != nil {
+		// Use the config file's repository and version unless overridden
+		if !cmd.Flags().Changed("compare") && detectFlag == "" {
+			comparisonVersion = configVersion
+		} else {
+			// The file's vulnerabilities are those of its version
+			repoConfig.Vulnerabilities = 0
+		}
+	} else if repository != "" {
+		if repoConfig, err = lookupRepository(repository); err != nil {
+			return invalidInput(err)
+		}
+	} else {
+		// Default to actions/runner
+		repoConfig = &config.ConfigActionsRunner
+	}
 
-	// Create policy
-	pol := policy.NewPolicy(repoConfig)
+	if err := applyPolicyFlags(cmd.Flags(), repoConfig); err != nil {
+		return invalidInput(err)
+	}
 
-	// Create checker with policy
-	versionChecker := checker.NewCheckerWithPolicy(ghClient, checker.Config{
-		CriticalAgeDays: repoConfig.CriticalDays,
-		MaxAgeDays:      repoConfig.MaxDays,
-		NoCache:         noCache,
-	}, pol)
+	// Several versions are checked together and reported in one table
+	if len(versions) > 1 {
+		if cmd.Flags().Changed("vulnerabilities") {
+			return invalidInput(fmt.Errorf("--vulnerabilities counts one version's vulnerabilities, so cannot be used with several --compare versions"))
+		}
+		return runMultiCompare(cmd, w, repoConfig, versions, hosts)
+	}
+
+	ghClient, versionChecker, resolved, err := newRepositoryChecker(hosts, repoConfig)
+	if err != nil {
+		return &configError{err}
+	}
+	token, tokenSourceName := resolved.Value, resolved.describe()
+
+	// Trace cache decisions and API calls at higher verbosity
+	if logger := newTraceLogger(cmd.ErrOrStderr(), verbose); logger != nil {
+		ghClient.Logger = logger
+		versionChecker.SetLogger(logger)
+		logger.Info("token resolved", "source", tokenSourceName)
+	}
+
+	// Check the token can read the repository before fetching releases, so
+	// permission problems are reported clearly rather than as a generic 403
+	if token != "" && !offline {
+		if err := ghClient.CheckAccess(cmd.Context()); err != nil {
+			if accessErr := tokenAccessError(err, tokenSourceName, repoConfig.FullName()); accessErr != err {
+				return accessErr
+			}
+			// Other failures (network, rate limits) are reported by the analysis below
+		}
+	}
+
+	// Show progress on interactive terminals while releases are fetched
+	var progress *progressReporter
+	if progressEnabled() {
+		progress = startProgress(cmd.ErrOrStderr())
+		ghClient.Progress = progress.Update
+	}
 
 	// Run analysis
-	analysis, err := versionChecker.Analyse(cmd.Context(), comparisonVersion)
+	analysis, err := analyseTraced(cmd.Context(), versionChecker, repoConfig.FullName(), comparisonVersion)
+	if progress != nil {
+		progress.Stop()
+		ghClient.Progress = nil
+	}
+	if analysis != nil {
+		analysis.Repository = repoConfig.FullName()
+		analysis.Upstream = repoConfig.Upstream
+		analysis.TokenSource = tokenSourceName
+		analysis.Vulnerabilities = repoConfig.Vulnerabilities
+	}
 	if err != nil {
-		// For JSON output, return error as JSON
+		err = withToken(err, token)
+
+		// For JSON output, the error envelope is written by the caller
 		if jsonOutput {
-			outputErrorJSON(err)
-			os.Exit(1)
+			return err
 		}
 
 		// For CI output, return error immediately without formatting
 		if ciOutput {
-			return fmt.Errorf("%v", err)
+			return outputCIError(w, err)
 		}
 
 		// If invalid semantic version format, show helpful context
-		if strings.Contains(err.Error(), "invalid comparison version") {
-			red.Printf("\n❌ Error: %v\n\n", err)
+		var invalidVersion *checker.InvalidVersionError
+		if errors.As(err, &invalidVersion) {
+			red.Fprintf(w, "\n❌ Error: %v\n\n", err)
+
+			// A remembered miss already names the latest release; fetching more would defeat it
+			var notFound *checker.VersionNotFoundError
+			if errors.As(err, &notFound) && notFound.Cached {
+				yellow.Fprintf(w, "💡 Use v%s\n", notFound.Latest)
+				return &exitError{code: 1}
+			}
 
 			// Fetch latest release to show helpful info
 			latestRelease, fetchErr := ghClient.GetLatestRelease(cmd.Context())
 			if fetchErr == nil {
-				yellow.Println("ℹ️  Semantic Version format: MAJOR.MINOR.PATCH")
-				yellow.Printf("   Example: 2.326.0\n\n")
-				yellow.Printf("💡 Most recent version is: v%s (Released %s)\n", latestRelease.Version, formatUKDate(latestRelease.PublishedAt))
+				yellow.Fprintln(w, "ℹ️  Semantic Version format: MAJOR.MINOR.PATCH")
+				yellow.Fprintf(w, "   Example: 2.326.0, or a channel such as latest-1\n\n")
+				yellow.Fprintf(w, "💡 Most recent version is: v%s (Released %s)\n", types.FormatVersion(latestRelease.Version), formatDate(latestRelease.PublishedAt))
 			}
 
-			os.Exit(1)
+			return &exitError{code: 1}
 		}
 
 		// If version doesn't exist, show helpful context instead of just erroring
 		if strings.Contains(err.Error(), "does not exist in GitHub releases") {
-			red.Printf("\n❌ Error: %v\n\n", err)
+			red.Fprintf(w, "\n❌ Error: %v\n\n", err)
 
 			// Fetch latest release to show helpful info
 			latestRelease, fetchErr := ghClient.GetLatestRelease(cmd.Context())
 			if fetchErr == nil {
-				yellow.Printf("💡 Use v%s (Released %s)\n", latestRelease.Version, formatUKDate(latestRelease.PublishedAt))
+				yellow.Fprintf(w, "💡 Use v%s (Released %s)\n", types.FormatVersion(latestRelease.Version), formatDate(latestRelease.PublishedAt))
 
 				// Show recent releases table if we can fetch them
 				allReleases, fetchErr := ghClient.GetAllReleases(cmd.Context())
@@ -276,632 +610,392 @@ func run(cmd *cobra.Command, args []string) error {
 						LatestVersion: latestRelease.Version,
 					}
 					// Calculate recent releases for display
-					tempChecker := checker.NewChecker(ghClient, checker.Config{})
+					tempChecker := checker.NewChecker(ghClient, checker.Config{
+						TimelineWindowDays: timelineWindow,
+						TimelineMinRows:    timelineMinRows,
+						TimelineMaxRows:    timelineMaxRows,
+					})
 					tempAnalysis.RecentReleases = tempChecker.CalculateRecentReleases(allReleases, latestRelease.Version, latestRelease.Version)
 
-					printExpiryTable(tempAnalysis, comparisonVersion)
+					fmt.Fprint(w, render.Timeline(tempAnalysis, comparisonVersion, renderOptions()))
 				}
 			}
 
-			os.Exit(1) // Exit with error code after showing helpful context
+			return &exitError{code: 1} // Exit with error code after showing helpful context
+		}
+		// Rate limits are explained with the limit and when it resets
+		if limits, ok := client.RateLimitInfo(err); ok {
+			printRateLimitHelp(w, limits, token != "")
+			return &exitError{code: 1}
 		}
-		// Check if it's an API error (rate limiting, network, etc.)
-		if strings.Contains(err.Error(), "failed to fetch") || strings.Contains(err.Error(), "failed to get") || strings.Contains(err.Error(), "failed to list") {
-			red.Printf("\n❌ Error: Unable to fetch release information from GitHub API\n\n")
-
-			// Check if it's specifically a rate limit error
-			if strings.Contains(err.Error(), "rate limit") {
-				yellow.Println("⚠️  GitHub API Rate Limit Exceeded")
-				yellow.Println()
-				yellow.Println("   Unauthenticated requests are limited to 60 per hour.")
-				yellow.Println("   Authenticated requests get 5,000 per hour.")
-				yellow.Println()
-				yellow.Println("💡 Authentication options (auto-detected in order):")
-				yellow.Println("   1. Use the -t flag: github-release-version-checker -t YOUR_TOKEN")
-				yellow.Println("   2. Set GITHUB_TOKEN environment variable")
-				yellow.Println("   3. GitHub CLI: gh auth login (automatically detected)")
-				yellow.Println("   4. GitHub Actions: GITHUB_TOKEN is auto-available")
-				yellow.Println()
-				yellow.Println("   Create a token at: https://github.com/settings/tokens")
-				yellow.Println("   (Only needs 'public_repo' read access)")
-
-				// Extract rate limit reset time if available
-				if strings.Contains(err.Error(), "rate reset in") {
-					start := strings.Index(err.Error(), "rate reset in")
-					if start != -1 {
-						resetInfo := err.Error()[start:]
-						end := strings.Index(resetInfo, "]")
-						if end != -1 {
-							resetTime := resetInfo[:end]
-							yellow.Printf("\n   Rate limit resets in: %s\n", strings.TrimPrefix(resetTime, "rate reset in "))
-						}
-					}
-				}
-			} else {
-				// Other API errors (network, etc.)
-				yellow.Println("ℹ️  Possible causes:")
-				yellow.Println("   • Network connectivity issues")
-				yellow.Println("   • GitHub API temporarily unavailable")
-				yellow.Println("   • Firewall blocking api.github.com")
-				yellow.Println()
-				yellow.Printf("   Error details: %v\n", err)
-			}
 
-			os.Exit(1)
+		// Other API errors (network, etc.)
+		if strings.Contains(err.Error(), "failed to fetch") || strings.Contains(err.Error(), "failed to get") || strings.Contains(err.Error(), "failed to list") {
+			red.Fprintf(w, "\n❌ Error: Unable to fetch release information from GitHub API\n\n")
+			yellow.Fprintln(w, "ℹ️  Possible causes:")
+			yellow.Fprintln(w, "   • Network connectivity issues")
+			yellow.Fprintln(w, "   • GitHub API temporarily unavailable")
+			yellow.Fprintln(w, "   • Firewall blocking api.github.com")
+			yellow.Fprintln(w)
+			yellow.Fprintf(w, "   Error details: %v\n", err)
+
+			return &exitError{code: 1}
 		}
 
+		if errors.Is(err, checker.ErrNoCachedReleases) {
+			return fmt.Errorf("%w for %s: run cache warm or bundle import first", err, repoConfig.SourceName())
+		}
 		return fmt.Errorf("analysis failed: %w", err)
 	}
 
 	// Output results
-	if jsonOutput {
-		return outputJSON(analysis)
-	}
-
-	if ciOutput {
-		return outputCI(analysis)
+	switch {
+	case jsonOutput:
+		err = outputJSON(w, analysis)
+	case ciOutput:
+		err = outputCI(w, analysis)
+	default:
+		err = outputTerminal(w, analysis)
 	}
-
-	return outputTerminal(analysis)
-}
-
-func outputJSON(analysis *checker.Analysis) error {
-	data, err := analysis.MarshalJSON()
 	if err != nil {
 		return err
 	}
-	fmt.Println(string(data))
-	return nil
-}
-
-func outputErrorJSON(err error) {
-	errorJSON := fmt.Sprintf(`{
-  "error": %q,
-  "success": false
-}`, err.Error())
-	fmt.Println(errorJSON)
+	if err := newAlerter(hosts, false).alert(cmd.Context(), analysis); err != nil {
+		yellow.Fprintf(cmd.ErrOrStderr(), "⚠️  %v\n", err)
+	}
+	if analysis.ComparisonVersion != nil {
+		if err := statusExit(analysis.Status()); err != nil {
+			return err
+		}
+	}
+	if err := strictExit(analysis); err != nil {
+		return err
+	}
+	if err := failOnExit(analysis); err != nil {
+		return err
+	}
+	return degradedExit(analysis.IsDegraded())
 }
 
-func outputCI(analysis *checker.Analysis) error {
-	// Always print latest version first (for script compatibility)
-	fmt.Println(analysis.LatestVersion)
-
-	// If no comparison, we're done
-	if analysis.ComparisonVersion == nil {
-		return nil
+// lookupRepository resolves a --repo value: a predefined short name such as
+// "node" or "k8s", an owner/repo pair, or a GitHub URL
+func lookupRepository(name string) (*config.RepositoryConfig, error) {
+	if repoConfig, err := config.GetPredefinedConfig(name); err == nil {
+		return repoConfig, nil
 	}
+	repoConfig, err := config.ParseRepositoryString(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid repository: %w", err)
+	}
+	return repoConfig, nil
+}
 
-	status := analysis.Status()
-	icon := getStatusIcon(status)
-
-	// Build status line (same as terminal output but without colours)
-	var statusLine string
-	if analysis.IsLatest {
-		comparisonDate := ""
-		if analysis.ComparisonReleasedAt != nil {
-			comparisonDate = fmt.Sprintf(" (%s)", formatUKDate(*analysis.ComparisonReleasedAt))
+// applyPolicyFlags overrides the repository's policy with any policy flags given
+func applyPolicyFlags(flags *pflag.FlagSet, repoConfig *config.RepositoryConfig) error {
+	// Check a fork or mirror against its upstream, starting from the upstream's policy
+	if upstream != "" {
+		if err := repoConfig.SetUpstream(upstream); err != nil {
+			return err
 		}
-		statusLine = fmt.Sprintf("Version %s%s is the latest version",
-			analysis.ComparisonVersion,
-			comparisonDate)
-	} else {
-		comparisonDate := ""
-		if analysis.ComparisonReleasedAt != nil {
-			comparisonDate = fmt.Sprintf(" (%s)", formatUKDate(*analysis.ComparisonReleasedAt))
-		}
-
-		expiryInfo := ""
-		if analysis.FirstNewerReleaseDate != nil {
-			expiryDate := analysis.FirstNewerReleaseDate.AddDate(0, 0, 30)
-
-			if analysis.IsExpired {
-				expiryInfo = fmt.Sprintf(" EXPIRED %s", formatUKDate(expiryDate))
-			} else if analysis.IsCritical {
-				daysLeft := 30 - analysis.DaysSinceUpdate
-				expiryInfo = fmt.Sprintf(" EXPIRES %s (%d days)", formatUKDate(expiryDate), daysLeft)
-			} else {
-				expiryInfo = fmt.Sprintf(" expires %s", formatUKDate(expiryDate))
-			}
+	}
+	if suffix != "" {
+		if err := repoConfig.SetVersionSuffix(suffix); err != nil {
+			return err
 		}
+	}
 
-		latestDate := ""
-		for _, r := range analysis.RecentReleases {
-			if r.IsLatest {
-				latestDate = fmt.Sprintf(" (Released %s)", formatUKDate(r.ReleasedAt))
-				break
-			}
+	// Override policy type if specified
+	if policyType != "" {
+		switch strings.ToLower(policyType) {
+		case "days":
+			repoConfig.PolicyType = config.PolicyTypeDays
+		case "versions":
+			repoConfig.PolicyType = config.PolicyTypeVersions
+		default:
+			return fmt.Errorf("invalid policy type %q: must be 'days' or 'versions'", policyType)
 		}
+	}
 
-		statusLine = fmt.Sprintf("Version %s%s%s: Update to v%s%s",
-			analysis.ComparisonVersion,
-			comparisonDate,
-			expiryInfo,
-			analysis.LatestVersion,
-			latestDate)
-	}
-
-	// Print GitHub Actions workflow commands
-	fmt.Println()
-	fmt.Println("::group::📊 Runner Version Check")
-	fmt.Printf("Latest version: v%s\n", analysis.LatestVersion)
-	fmt.Printf("Your version: v%s\n", analysis.ComparisonVersion)
-	fmt.Printf("Status: %s\n", getStatusText(status))
-	fmt.Println("::endgroup::")
-	fmt.Println()
-
-	// Use appropriate workflow command based on status
-	switch status {
-	case checker.StatusExpired:
-		fmt.Printf("::error title=Runner Version Expired::%s %s\n", icon, statusLine)
-	case checker.StatusCritical:
-		fmt.Printf("::warning title=Runner Version Critical::%s %s\n", icon, statusLine)
-	case checker.StatusWarning:
-		fmt.Printf("::notice title=Runner Version Behind::%s %s\n", icon, statusLine)
-	case checker.StatusCurrent:
-		fmt.Printf("::notice title=Runner Version Current::%s %s\n", icon, statusLine)
-	}
-
-	// Print expiry table
-	if len(analysis.RecentReleases) > 0 {
-		fmt.Println()
-		fmt.Println("::group::📅 Release Expiry Timeline")
-		fmt.Printf("%-10s %-14s %-14s %s\n", "Version", "Release Date", "Expiry Date", "Status")
-
-		for _, release := range analysis.RecentReleases {
-			versionStr := release.Version.String()
-			releasedStr := formatUKDate(release.ReleasedAt)
-
-			var expiresStr string
-			var statusStr string
-
-			if release.IsLatest {
-				expiresStr = "-"
-				daysAgo := int(time.Since(release.ReleasedAt).Hours() / 24)
-				statusStr = fmt.Sprintf("Latest (%s)", formatDaysAgo(daysAgo))
-			} else if release.ExpiresAt != nil {
-				expiresStr = formatUKDate(*release.ExpiresAt)
-
-				if release.IsExpired {
-					daysExpired := -release.DaysUntilExpiry
-					statusStr = fmt.Sprintf("Expired %s", formatDaysAgo(daysExpired))
-				} else {
-					statusStr = fmt.Sprintf("Valid (%s left)", formatDaysInFuture(release.DaysUntilExpiry))
-				}
-			}
-
-			arrow := ""
-			if analysis.ComparisonVersion != nil && release.Version.Equal(analysis.ComparisonVersion) {
-				arrow = "  [Your version]"
-			}
+	// Override which release counts as latest
+	if latestFrom != "" {
+		switch checker.LatestPreference(latestFrom) {
+		case checker.LatestHighest, checker.LatestMarked:
+			repoConfig.LatestFrom = latestFrom
+		default:
+			return fmt.Errorf("invalid latest-from %q: must be 'highest' or 'marked'", latestFrom)
+		}
+	}
 
-			fmt.Printf("  %-10s %-14s %-14s %s%s\n", versionStr, releasedStr, expiresStr, statusStr, arrow)
+	// Override how newer releases are chosen
+	if ordering != "" {
+		switch checker.Ordering(ordering) {
+		case checker.OrderingSemver, checker.OrderingDate:
+			repoConfig.Ordering = ordering
+		default:
+			return fmt.Errorf("invalid ordering %q: must be 'semver' or 'date'", ordering)
 		}
+	}
 
-		// Add timestamp
-		now := time.Now().UTC()
-		timestamp := now.Format("2 Jan 2006 15:04:05 MST")
-		fmt.Printf("\n  Checked at: %s\n", timestamp)
+	// Override whether prereleases can be the latest
+	if track != "" {
+		switch checker.Track(track) {
+		case checker.TrackStable, checker.TrackPrerelease:
+			repoConfig.Track = track
+		default:
+			return fmt.Errorf("invalid track %q: must be 'stable' or 'prerelease'", track)
+		}
+	}
 
-		fmt.Println("::endgroup::")
+	// Override what versions that are not releases result in
+	if unknownVer != "" {
+		switch checker.UnknownVersionPolicy(unknownVer) {
+		case checker.UnknownVersionError, checker.UnknownVersionExpired, checker.UnknownVersionWarning:
+			repoConfig.UnknownVersion = unknownVer
+		default:
+			return fmt.Errorf("invalid unknown-version-policy %q: must be 'error', 'expired' or 'warning'", unknownVer)
+		}
 	}
 
-	// Write markdown summary to $GITHUB_STEP_SUMMARY
-	if summaryFile := os.Getenv("GITHUB_STEP_SUMMARY"); summaryFile != "" {
-		if err := writeGitHubSummary(summaryFile, analysis); err != nil {
-			fmt.Printf("::warning::Failed to write job summary: %v\n", err)
+	// Override the release channel and how tags are assigned to channels
+	if len(channelTags) > 0 {
+		if err := repoConfig.SetChannels(channelTags); err != nil {
+			return err
 		}
 	}
+	if channel != "" {
+		repoConfig.Channel = strings.ToLower(channel)
+	}
+	if repoConfig.Channel != "" && repoConfig.Track == string(checker.TrackPrerelease) {
+		return fmt.Errorf("--track prerelease and --channel cannot both be set")
+	}
 
-	return nil
-}
+	// Override how 0.x versions are counted
+	if zeroMajor != "" {
+		switch zeroMajor {
+		case "minor-breaking", "semver":
+			repoConfig.ZeroMajor = zeroMajor
+		default:
+			return fmt.Errorf("invalid zero-major %q: must be 'minor-breaking' or 'semver'", zeroMajor)
+		}
+	}
 
-func writeGitHubSummary(summaryFile string, analysis *checker.Analysis) error {
-	f, err := os.OpenFile(summaryFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	if err != nil {
-		return err
+	// Override the maintenance calendar
+	if maintenance != "" {
+		if err := repoConfig.SetMaintenanceWindow(maintenance); err != nil {
+			return err
+		}
 	}
-	defer f.Close()
 
-	status := analysis.Status()
-	statusEmoji := getStatusIcon(status)
-	statusText := getStatusText(status)
-
-	// Write markdown summary
-	fmt.Fprintf(f, "## %s Runner Version Status: %s\n\n", statusEmoji, statusText)
-
-	// Summary table
-	fmt.Fprintf(f, "| Metric | Value |\n")
-	fmt.Fprintf(f, "|--------|-------|\n")
-	fmt.Fprintf(f, "| Current Version | v%s |\n", analysis.ComparisonVersion)
-	fmt.Fprintf(f, "| Latest Version | v%s |\n", analysis.LatestVersion)
-	fmt.Fprintf(f, "| Status | %s %s |\n", statusEmoji, statusText)
-	fmt.Fprintf(f, "| Releases Behind | %d |\n", analysis.ReleasesBehind)
-
-	if analysis.DaysSinceUpdate > 0 {
-		if analysis.IsExpired {
-			daysOver := analysis.DaysSinceUpdate - analysis.MaxAgeDays
-			fmt.Fprintf(f, "| Days Overdue | %d |\n", daysOver)
-		} else {
-			daysLeft := analysis.MaxAgeDays - analysis.DaysSinceUpdate
-			fmt.Fprintf(f, "| Days Until Expiry | %d |\n", daysLeft)
+	// Override how long releases soak before they are recommended
+	if flags.Changed("min-release-age") {
+		if minAge < 0 {
+			return fmt.Errorf("--min-release-age must be non-negative")
 		}
+		repoConfig.MinReleaseAgeDays = minAge
 	}
 
-	// Action required section
-	if status == checker.StatusExpired {
-		fmt.Fprintf(f, "\n### ⚠️ Action Required\n\n")
-		fmt.Fprintf(f, "**Update to v%s or later immediately.** ", analysis.FirstNewerVersion)
-		fmt.Fprintf(f, "GitHub will not queue jobs to runners with expired versions.\n")
-	} else if status == checker.StatusCritical {
-		daysLeft := analysis.MaxAgeDays - analysis.DaysSinceUpdate
-		fmt.Fprintf(f, "\n### ⚠️ Update Soon\n\n")
-		fmt.Fprintf(f, "Version expires in **%d days**. Update to v%s or later.\n", daysLeft, analysis.FirstNewerVersion)
-	} else if status == checker.StatusWarning {
-		fmt.Fprintf(f, "\n### ℹ️ Update Available\n\n")
-		fmt.Fprintf(f, "A newer version (v%s) is available.\n", analysis.LatestVersion)
+	// Override the known vulnerabilities counted in the drift score
+	if flags.Changed("vulnerabilities") {
+		if vulns < 0 {
+			return fmt.Errorf("--vulnerabilities must be non-negative")
+		}
+		repoConfig.Vulnerabilities = vulns
 	}
 
-	// Available updates
-	if len(analysis.NewerReleases) > 0 {
-		fmt.Fprintf(f, "\n### 📦 Available Updates\n\n")
-		for _, release := range analysis.NewerReleases {
-			releasedDaysAgo := int(time.Since(release.PublishedAt).Hours() / 24)
-			fmt.Fprintf(f, "- [v%s](%s) - Released %s (%d days ago)\n",
-				release.Version,
-				release.URL,
-				formatUKDate(release.PublishedAt),
-				releasedDaysAgo)
+	// Override the platforms recommended releases must have assets for
+	if len(assets) > 0 {
+		if err := repoConfig.SetRequiredAssets(assets); err != nil {
+			return err
 		}
 	}
 
-	// Add timestamp
-	now := time.Now().UTC()
-	timestamp := now.Format("2 Jan 2006 15:04:05 MST")
-	fmt.Fprintf(f, "\n*Checked at: %s*\n", timestamp)
+	// Override the platform recommended releases must have shipped for
+	if platform != "" {
+		if err := repoConfig.SetPlatform(platform); err != nil {
+			return fmt.Errorf("invalid --platform: %w", err)
+		}
+	}
 
-	fmt.Fprintf(f, "\n---\n\n")
+	// Override max versions if specified and using version policy
+	if flags.Changed("max-versions") {
+		repoConfig.MaxVersionsBehind = maxVersions
+	}
 
+	// Override critical/max days if specified and using days policy; lts policies
+	// are critical that many days before end of life
+	if repoConfig.PolicyType == config.PolicyTypeDays || repoConfig.PolicyType == config.PolicyTypeLTS {
+		if flags.Changed("critical-days") {
+			repoConfig.CriticalDays = criticalAgeDays
+		}
+	}
+	if repoConfig.PolicyType == config.PolicyTypeDays && flags.Changed("max-days") {
+		repoConfig.MaxDays = maxAgeDays
+	}
 	return nil
 }
 
-func getStatusText(status checker.Status) string {
-	switch status {
-	case checker.StatusCurrent:
-		return "Current"
-	case checker.StatusWarning:
-		return "Behind"
-	case checker.StatusCritical:
-		return "Critical"
-	case checker.StatusExpired:
-		return "Expired"
-	default:
-		return "Unknown"
+// repositoryPolicy creates the repository's policy, with its support schedule
+// for lts policies
+func repositoryPolicy(repoConfig *config.RepositoryConfig) pkgpolicy.VersionPolicy {
+	versionPolicy := policy.NewPolicy(repoConfig)
+	if lts, ok := versionPolicy.(*pkgpolicy.LTSPolicy); ok && repoConfig.ScheduleURL != "" {
+		lts.Schedule = supportSchedule(os.Stderr, repoConfig.ScheduleURL)
 	}
+	return versionPolicy
 }
 
-func outputTerminal(analysis *checker.Analysis) error {
-	// Always print latest version first (for script compatibility)
-	fmt.Println(analysis.LatestVersion)
-
-	// If no comparison version provided
-	if analysis.ComparisonVersion == nil {
-		// In verbose mode, show recent releases table
-		if verbose && len(analysis.RecentReleases) > 0 {
-			fmt.Println()
-			printExpiryTable(analysis, "")
-		}
-		return nil
+// newRepositoryChecker creates the GitHub client, on the repository's API
+// host, and policy checker for a repository, returning the host's token
+func newRepositoryChecker(hosts *apiHosts, repoConfig *config.RepositoryConfig) (*client.Client, *checker.Checker, resolvedToken, error) {
+	ghClient, resolved, err := hosts.client(repoConfig)
+	if err != nil {
+		return nil, nil, resolvedToken{}, err
 	}
 
-	// Print status
-	fmt.Println()
-	printStatus(analysis)
+	// Create cache manager (not used yet, but will be in future phases)
+	_ = cache.NewManager(cachePath)
 
-	// Print expiry table unless quiet mode
-	if !quiet {
-		printExpiryTable(analysis, "")
-	}
+	return ghClient, newChecker(ghClient, repoConfig), resolved, nil
+}
 
-	// Print verbose details if requested
-	if verbose {
-		fmt.Println()
-		printDetails(analysis)
+// newChecker creates the policy checker for a repository, fetching through ghClient
+func newChecker(ghClient checker.GitHubClient, repoConfig *config.RepositoryConfig) *checker.Checker {
+	// Tracking prereleases, or a channel of them, needs the client to keep them
+	tracksPrereleases := checker.Track(repoConfig.Track) == checker.TrackPrerelease ||
+		repoConfig.Channel != "" && repoConfig.Channel != checker.ReleaseChannelStable
+	if c, ok := ghClient.(*client.Client); ok {
+		c.IncludePrereleases = c.IncludePrereleases || tracksPrereleases
+		c.TagSuffix = repoConfig.VersionSuffixPattern()
 	}
-
-	return nil
+	versionChecker := checker.NewCheckerWithPolicy(ghClient, checker.Config{
+		CriticalAgeDays: repoConfig.CriticalDays,
+		MaxAgeDays:      repoConfig.MaxDays,
+		NoCache:         noCache,
+		CachedReleases:  cachedReleases(repoConfig, fetchParameters(ghClient)),
+		Offline:         offline,
+		NotFoundTTL:     notFoundTTL,
+
+		LatestPreference: checker.LatestPreference(repoConfig.LatestFrom),
+		Ordering:         checker.Ordering(repoConfig.Ordering),
+		Track:            checker.Track(repoConfig.Track),
+		Channel:          repoConfig.Channel,
+		ChannelPatterns:  repoConfig.ChannelPatterns(),
+		UnknownVersion:   checker.UnknownVersionPolicy(repoConfig.UnknownVersion),
+		Normalisation:    normalisation,
+		VersionSuffix:    repoConfig.VersionSuffixPattern(),
+
+		TimelineWindowDays: timelineWindow,
+		TimelineMinRows:    timelineMinRows,
+		TimelineMaxRows:    timelineMaxRows,
+		MaxNewer:           maxNewer,
+
+		Waivers: config.WaiversFor(configWaivers, repoConfig.FullName()),
+		Yanked:  config.YankedFor(configYanked, repoConfig.FullName()),
+
+		NoteKeywords:   config.NoteKeywordsFor(configNoteKeywords, repoConfig),
+		RequiredAssets: repoConfig.RequiredAssets,
+		Platform:       repoConfig.Platform,
+		MinReleaseAge:  time.Duration(repoConfig.MinReleaseAgeDays) * 24 * time.Hour,
+	}, repositoryPolicy(repoConfig))
+	if tracer != nil {
+		versionChecker.SetHooks(tracingHooks())
+	}
+	if analysisCache != nil {
+		versionChecker.SetAnalysisCache(analysisCache, repoConfig.FullName())
+	}
+	return versionChecker
 }
 
-func printStatus(analysis *checker.Analysis) {
-	status := analysis.Status()
-	icon := getStatusIcon(status)
-	colourFunc := getStatusColour(status)
-
-	var statusLine string
-
-	if analysis.ComparisonVersion == nil {
-		// No comparison - just show latest
-		statusLine = fmt.Sprintf("%s Latest version: v%s", icon, analysis.LatestVersion)
-	} else if analysis.IsLatest {
-		// On latest version
-		if analysis.ComparisonReleasedAt != nil {
-			statusLine = fmt.Sprintf("%s Version %s (%s) is the latest version",
-				icon,
-				analysis.ComparisonVersion,
-				formatUKDate(*analysis.ComparisonReleasedAt))
-		} else {
-			statusLine = fmt.Sprintf("%s Version %s is the latest version",
-				icon,
-				analysis.ComparisonVersion)
-		}
-	} else {
-		// Behind - construct full status line
-		comparisonDate := ""
-		if analysis.ComparisonReleasedAt != nil {
-			comparisonDate = fmt.Sprintf(" (%s)", formatUKDate(*analysis.ComparisonReleasedAt))
-		}
-
-		// Check if using version-based policy
-		isVersionPolicy := analysis.PolicyType == "versions"
-
-		expiryInfo := ""
-		if isVersionPolicy {
-			// For version-based policies, show version skew info
-			if analysis.IsExpired {
-				expiryInfo = fmt.Sprintf(" UNSUPPORTED (%d minor versions behind)", analysis.MinorVersionsBehind)
-			} else if analysis.IsCritical {
-				expiryInfo = fmt.Sprintf(" CRITICAL (%d minor versions behind)", analysis.MinorVersionsBehind)
-			} else if analysis.MinorVersionsBehind > 0 {
-				expiryInfo = fmt.Sprintf(" (%d minor versions behind)", analysis.MinorVersionsBehind)
-			}
-		} else {
-			// For days-based policies, show expiry dates
-			if analysis.FirstNewerReleaseDate != nil {
-				expiryDate := analysis.FirstNewerReleaseDate.AddDate(0, 0, 30)
-
-				if analysis.IsExpired {
-					expiryInfo = fmt.Sprintf(" EXPIRED %s", formatUKDate(expiryDate))
-				} else if analysis.IsCritical {
-					daysLeft := 30 - analysis.DaysSinceUpdate
-					expiryInfo = fmt.Sprintf(" EXPIRES %s (%d days)", formatUKDate(expiryDate), daysLeft)
-				} else {
-					expiryInfo = fmt.Sprintf(" expires %s", formatUKDate(expiryDate))
-				}
-			}
-		}
-
-		latestDate := ""
-		for _, r := range analysis.RecentReleases {
-			if r.IsLatest {
-				latestDate = fmt.Sprintf(" (Released %s)", formatUKDate(r.ReleasedAt))
-				break
-			}
-		}
-
-		statusLine = fmt.Sprintf("%s Version %s%s%s: Update to v%s%s",
-			icon,
-			analysis.ComparisonVersion,
-			comparisonDate,
-			expiryInfo,
-			analysis.LatestVersion,
-			latestDate)
+// renderOptions returns the rendering options set by flags
+func renderOptions() render.Options {
+	return render.Options{
+		DateFormat:       activeDateFormat,
+		Columns:          activeTableColumns,
+		MaxWidth:         maxTableWidth,
+		Quiet:            quiet,
+		Details:          verbose >= verbosityDetails,
+		AnnotationLevels: ciAnnotationLevels,
+		SummaryExclude:   summaryExclude,
 	}
-
-	colourFunc.Println(statusLine)
 }
 
-func printExpiryTable(analysis *checker.Analysis, phantomVersionStr string) {
-	if len(analysis.RecentReleases) == 0 {
-		return
+func outputJSON(w io.Writer, analysis *checker.Analysis) error {
+	data, err := render.JSON(analysis)
+	if err != nil {
+		return err
 	}
-
-	isVersionPolicy := analysis.PolicyType == "versions"
-
-	fmt.Println()
-	if isVersionPolicy {
-		cyan.Println("📋 Release Timeline")
-		cyan.Println("─────────────────────────────────────────────────────")
-		fmt.Printf("%-12s %-14s %s\n", "Version", "Release Date", "Status")
-	} else {
-		cyan.Println("📅 Release Expiry Timeline")
-		cyan.Println("─────────────────────────────────────────────────────")
-		fmt.Printf("%-10s %-14s %-14s %s\n", "Version", "Release Date", "Expiry Date", "Status")
+	if len(fieldsFlag) > 0 {
+		if data, err = selectFields(data, fieldsFlag); err != nil {
+			return err
+		}
 	}
+	return writeJSONDocument(w, data)
+}
 
-	// Parse phantom version if provided
-	var phantomVersion *semver.Version
-	if phantomVersionStr != "" {
-		if v, err := semver.NewVersion(phantomVersionStr); err == nil {
-			phantomVersion = v
+func outputCI(w io.Writer, analysis *checker.Analysis) error {
+	fmt.Fprint(w, render.CI(analysis, renderOptions()))
+
+	// Expose step outputs via $GITHUB_OUTPUT
+	if outputFile := os.Getenv("GITHUB_OUTPUT"); outputFile != "" {
+		if err := writeGitHubOutput(outputFile, analysis); err != nil {
+			fmt.Fprintf(w, "::warning::Failed to write step outputs: %v\n", err)
 		}
 	}
 
-	phantomPrinted := false
-	for i, release := range analysis.RecentReleases {
-		// Check if we should print phantom version before this release
-		if phantomVersion != nil && !phantomPrinted && phantomVersion.LessThan(release.Version) {
-			// Print phantom version row
-			bold := colour.New(colour.Bold)
-			if isVersionPolicy {
-				bold.Printf("%-12s %-14s %s\n", phantomVersion.String(), "-", "❌ Does Not Exist  ← Your requested version")
-			} else {
-				bold.Printf("%-10s %-14s %-14s %s\n", phantomVersion.String(), "-", "-", "❌ Does Not Exist  ← Your requested version")
+	// Write markdown summary to $GITHUB_STEP_SUMMARY
+	if analysis.ComparisonVersion != nil {
+		if summaryFile := os.Getenv("GITHUB_STEP_SUMMARY"); summaryFile != "" {
+			if err := writeGitHubSummary(summaryFile, analysis); err != nil {
+				fmt.Fprintf(w, "::warning::Failed to write job summary: %v\n", err)
 			}
-			phantomPrinted = true
 		}
+	}
 
-		versionStr := release.Version.String()
-		releasedStr := formatUKDate(release.ReleasedAt)
-
-		var expiresStr string
-		var statusStr string
-
-		if isVersionPolicy {
-			// For version-based policies, show version skew info
-			daysAgo := int(time.Since(release.ReleasedAt).Hours() / 24)
-			expiresStr = formatDaysAgo(daysAgo)
+	return nil
+}
 
-			if release.IsLatest {
-				// Check if latest is also a .0 release
-				if release.Version.Patch() == 0 {
-					statusStr = "✅ Latest  ← Minor release"
-				} else {
-					statusStr = "✅ Latest"
-				}
-			} else {
-				// Check if this is a .0 release (first release of a minor version)
-				isMinorRelease := release.Version.Patch() == 0
-
-				// Calculate minor version difference from LATEST version
-				minorDiff := int(analysis.LatestVersion.Minor()) - int(release.Version.Minor())
-				if release.Version.Major() == analysis.LatestVersion.Major() {
-					if minorDiff > 0 {
-						if isMinorRelease {
-							statusStr = fmt.Sprintf("-%d minor  ← Minor release", minorDiff)
-						} else {
-							statusStr = fmt.Sprintf("-%d minor", minorDiff)
-						}
-					} else if minorDiff == 0 {
-						// Same minor, check patch difference
-						patchDiff := int(analysis.LatestVersion.Patch()) - int(release.Version.Patch())
-						if patchDiff > 0 {
-							if isMinorRelease {
-								statusStr = fmt.Sprintf("-%d patch  ← Minor release", patchDiff)
-							} else {
-								statusStr = fmt.Sprintf("-%d patch", patchDiff)
-							}
-						} else {
-							statusStr = "Same as latest"
-						}
-					} else {
-						// Release is newer than latest? Shouldn't happen but handle it
-						statusStr = ""
-					}
-				} else {
-					// Different major version
-					statusStr = fmt.Sprintf("v%d", release.Version.Major())
-				}
-			}
-		} else {
-			// For days-based policies, show expiry info
-			if release.IsLatest {
-				expiresStr = "-"
-				daysAgo := int(time.Since(release.ReleasedAt).Hours() / 24)
-				statusStr = fmt.Sprintf("✅ Latest (%s)", formatDaysAgo(daysAgo))
-			} else if release.ExpiresAt != nil {
-				expiresStr = formatUKDate(*release.ExpiresAt)
-
-				if release.IsExpired {
-					daysExpired := -release.DaysUntilExpiry
-					statusStr = fmt.Sprintf("❌ Expired %s", formatDaysAgo(daysExpired))
-				} else {
-					statusStr = fmt.Sprintf("✅ Valid (%s left)", formatDaysInFuture(release.DaysUntilExpiry))
-				}
-			}
-		}
+// writeGitHubOutput appends step outputs for downstream workflow steps
+func writeGitHubOutput(outputFile string, analysis *checker.Analysis) error {
+	return appendGitHubOutputs(outputFile, render.Outputs(analysis))
+}
 
-		// Mark user's version with bold and arrow
-		arrow := ""
-		isUserVersion := analysis.ComparisonVersion != nil && release.Version.Equal(analysis.ComparisonVersion)
-		if isUserVersion {
-			arrow = "  ← Your version"
-			// Format the whole line in bold
-			bold := colour.New(colour.Bold)
-			if isVersionPolicy {
-				bold.Printf("%-12s %-14s %-16s %s%s\n", versionStr, releasedStr, expiresStr, statusStr, arrow)
-			} else {
-				bold.Printf("%-10s %-14s %-14s %s%s\n", versionStr, releasedStr, expiresStr, statusStr, arrow)
-			}
-		} else {
-			if isVersionPolicy {
-				fmt.Printf("%-12s %-14s %s%s\n", versionStr, releasedStr, statusStr, arrow)
-			} else {
-				fmt.Printf("%-10s %-14s %-14s %s%s\n", versionStr, releasedStr, expiresStr, statusStr, arrow)
-			}
-		}
+// appendGitHubOutputs appends name=value step outputs to $GITHUB_OUTPUT
+func appendGitHubOutputs(outputFile string, outputs [][2]string) error {
+	f, err := os.OpenFile(outputFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
 
-		// Check if phantom should be printed after this (if it's the last release and phantom is greater)
-		if phantomVersion != nil && !phantomPrinted && i == len(analysis.RecentReleases)-1 {
-			// Print phantom version row
-			bold := colour.New(colour.Bold)
-			if isVersionPolicy {
-				bold.Printf("%-12s %-14s %-16s %s\n", phantomVersion.String(), "-", "-", "❌ Does Not Exist  ← Your requested version")
-			} else {
-				bold.Printf("%-10s %-14s %-14s %s\n", phantomVersion.String(), "-", "-", "❌ Does Not Exist  ← Your requested version")
-			}
-			phantomPrinted = true
+	for _, output := range outputs {
+		if _, err := fmt.Fprintf(f, "%s=%s\n", output[0], output[1]); err != nil {
+			return err
 		}
 	}
 
-	// Add timestamp footer
-	now := time.Now().UTC()
-	timestamp := now.Format("2 Jan 2006 15:04:05 MST")
-	grey.Printf("\nChecked at: %s\n", timestamp)
+	return nil
 }
 
-func printDetails(analysis *checker.Analysis) {
-	cyan.Println("📊 Detailed Analysis")
-	cyan.Println("─────────────────────────────────────")
-
-	fmt.Printf("  Current version:      v%s\n", analysis.ComparisonVersion)
-	fmt.Printf("  Latest version:       v%s\n", analysis.LatestVersion)
-	fmt.Printf("  Status:               %s\n", analysis.Status())
-	fmt.Printf("  Releases behind:      %d\n", analysis.ReleasesBehind)
-
-	if analysis.FirstNewerVersion != nil {
-		fmt.Printf("  First newer release:  v%s\n", analysis.FirstNewerVersion)
-		if analysis.FirstNewerReleaseDate != nil {
-			fmt.Printf("  Released on:          %s\n", analysis.FirstNewerReleaseDate.Format("2006-01-02"))
-			fmt.Printf("  Days since update:    %d\n", analysis.DaysSinceUpdate)
-
-			if analysis.DaysSinceUpdate < maxAgeDays {
-				daysLeft := maxAgeDays - analysis.DaysSinceUpdate
-				fmt.Printf("  Days until expired:   %d\n", daysLeft)
-			} else {
-				daysOver := analysis.DaysSinceUpdate - maxAgeDays
-				fmt.Printf("  Days overdue:         %d\n", daysOver)
-			}
-		}
-	}
-
-	// Show available updates
-	if len(analysis.NewerReleases) > 0 {
-		fmt.Println()
-		cyan.Println("📋 Available Updates")
-		cyan.Println("─────────────────────────────────────")
-		for _, release := range analysis.NewerReleases {
-			releaseDate := release.PublishedAt.Format("2006-01-02")
-			daysAgo := int(time.Since(release.PublishedAt).Hours() / 24)
-			fmt.Printf("  • v%s (%s, %d days ago)\n", release.Version, releaseDate, daysAgo)
+// writeGitHubSummary appends the job summary, from --summary-template if set
+func writeGitHubSummary(summaryFile string, analysis *checker.Analysis) error {
+	summary := render.Summary(analysis, renderOptions())
+	if ciSummaryTemplate != nil {
+		var err error
+		if summary, err = render.SummaryTemplate(ciSummaryTemplate, analysis, renderOptions()); err != nil {
+			return err
 		}
 	}
-}
 
-func getStatusIcon(status checker.Status) string {
-	switch status {
-	case checker.StatusCurrent:
-		return "✅"
-	case checker.StatusWarning:
-		return "⚠️ "
-	case checker.StatusCritical:
-		return "🔶"
-	case checker.StatusExpired:
-		return "🚨"
-	default:
-		return "ℹ️ "
+	f, err := os.OpenFile(summaryFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
 	}
+	defer f.Close()
+	_, err = f.WriteString(summary)
+	return err
 }
 
-func getStatusColour(status checker.Status) *colour.Color {
-	switch status {
-	case checker.StatusCurrent:
-		return green
-	case checker.StatusWarning:
-		return yellow
-	case checker.StatusCritical:
-		return yellow
-	case checker.StatusExpired:
-		return red
-	default:
-		return cyan
-	}
+func outputTerminal(w io.Writer, analysis *checker.Analysis) error {
+	_, err := fmt.Fprint(w, render.Terminal(analysis, renderOptions()))
+	return err
 }
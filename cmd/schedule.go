@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/nickromney-org/github-release-version-checker/pkg/policy"
+)
+
+// supportScheduleTTL is how long a fetched support schedule is reused
+const supportScheduleTTL = 24 * time.Hour
+
+// supportScheduleTimeout bounds fetching a support schedule
+const supportScheduleTimeout = 10 * time.Second
+
+// loadedSchedule is the outcome of loading one schedule
+type loadedSchedule struct {
+	schedule policy.LTSSchedule
+	err      error
+}
+
+// supportSchedules holds each schedule loaded this run, or why it could not be,
+// by URL; batch checks share them
+var supportSchedules = struct {
+	sync.Mutex
+	byURL map[string]loadedSchedule
+}{byURL: make(map[string]loadedSchedule)}
+
+// supportSchedule returns the schedule at url, loading it once per run. The
+// first failure is reported to w; checks then fall back to counting versions.
+func supportSchedule(w io.Writer, url string) policy.LTSSchedule {
+	supportSchedules.Lock()
+	defer supportSchedules.Unlock()
+	if loaded, ok := supportSchedules.byURL[url]; ok {
+		return loaded.schedule
+	}
+	schedule, err := loadSupportSchedule(context.Background(), defaultCacheDir(), url)
+	if err != nil {
+		yellow.Fprintf(w, "⚠️  %v; counting major versions instead\n", err)
+	}
+	supportSchedules.byURL[url] = loadedSchedule{schedule, err}
+	return schedule
+}
+
+// loadSupportSchedule returns the release schedule at url, reusing a copy in dir
+// fetched within supportScheduleTTL. A stale copy is used when the fetch fails,
+// and any copy when offline.
+func loadSupportSchedule(ctx context.Context, dir, url string) (policy.LTSSchedule, error) {
+	sum := sha256.Sum256([]byte(url))
+	path := filepath.Join(dir, "schedules", hex.EncodeToString(sum[:8])+".json")
+	cached, cachedErr := os.ReadFile(path)
+	fresh := false
+	if info, err := os.Stat(path); err == nil {
+		fresh = time.Since(info.ModTime()) < supportScheduleTTL
+	}
+
+	data := cached
+	if cachedErr != nil && offline {
+		return nil, fmt.Errorf("no cached release schedule for %s to check offline", url)
+	}
+	if cachedErr != nil || !fresh && !offline {
+		fetched, err := fetchSupportSchedule(ctx, url)
+		switch {
+		case err == nil:
+			data = fetched
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err == nil {
+				_ = os.WriteFile(path, fetched, 0o644) // Best effort; the next run fetches again
+			}
+		case cachedErr != nil:
+			return nil, err
+		}
+	}
+
+	schedule, err := policy.ParseNodeSchedule(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", url, err)
+	}
+	return schedule, nil
+}
+
+// fetchSupportSchedule downloads a release schedule
+func fetchSupportSchedule(ctx context.Context, url string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, supportScheduleTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch release schedule: %w", err)
+	}
+	req.Header.Set("User-Agent", userAgent())
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch release schedule: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch release schedule: %s returned %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch release schedule: %w", err)
+	}
+	return data, nil
+}
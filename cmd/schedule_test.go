@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadSupportSchedule(t *testing.T) {
+	body := `{"v20": {"start": "2023-04-18", "lts": "2023-10-24", "maintenance": "2024-10-22", "end": "2026-04-30"}}`
+	requests := 0
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+	dir := t.TempDir()
+
+	load := func() error {
+		schedule, err := loadSupportSchedule(context.Background(), dir, server.URL)
+		if err == nil && len(schedule) != 1 {
+			t.Errorf("schedule = %+v, want v20", schedule)
+		}
+		return err
+	}
+
+	if err := load(); err != nil || requests != 1 {
+		t.Fatalf("first load: err %v, %d requests; want a fetch", err, requests)
+	}
+	if err := load(); err != nil || requests != 1 {
+		t.Errorf("fresh copy: err %v, %d requests; want the cached copy", err, requests)
+	}
+
+	// A stale copy is refreshed, and used when the refresh fails
+	files, _ := filepath.Glob(filepath.Join(dir, "schedules", "*.json"))
+	if len(files) != 1 {
+		t.Fatalf("cached files = %v, want one", files)
+	}
+	stale := time.Now().Add(-2 * supportScheduleTTL)
+	if err := os.Chtimes(files[0], stale, stale); err != nil {
+		t.Fatal(err)
+	}
+	status = http.StatusBadGateway
+	if err := load(); err != nil || requests != 2 {
+		t.Errorf("stale copy with a failed refresh: err %v, %d requests; want the stale copy", err, requests)
+	}
+
+	// Offline, a stale copy is used without fetching, and nothing is an error
+	offline = true
+	defer func() { offline = false }()
+	if err := load(); err != nil || requests != 2 {
+		t.Errorf("offline: err %v, %d requests; want the stale copy", err, requests)
+	}
+	if _, err := loadSupportSchedule(context.Background(), t.TempDir(), server.URL); err == nil || requests != 2 {
+		t.Errorf("offline without a copy: err %v, %d requests; want an error without fetching", err, requests)
+	}
+
+	offline = false
+	if _, err := loadSupportSchedule(context.Background(), t.TempDir(), server.URL); err == nil {
+		t.Error("failed fetch without a copy succeeded, want an error")
+	}
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// shutdownGrace is how long in-flight work may run after the first signal
+// before it is cancelled; a second signal cancels it at once
+const shutdownGrace = 30 * time.Second
+
+// errNotChecked marks batch jobs skipped because shutdown began
+var errNotChecked = errors.New("not checked: interrupted")
+
+// shutdown tracks a graceful stop: after the first signal no new work starts,
+// while work already running may finish
+type shutdown struct {
+	stopping chan struct{} // Closed on the first signal
+	once     sync.Once
+	signal   os.Signal
+}
+
+type shutdownKey struct{}
+
+// withShutdown returns a context carrying a shutdown that begins on the first
+// value from signals. The context itself is cancelled after grace, or on a
+// second signal, to abort work that does not finish.
+func withShutdown(parent context.Context, signals <-chan os.Signal, grace time.Duration, w io.Writer) (context.Context, context.CancelFunc) {
+	s := &shutdown{stopping: make(chan struct{})}
+	ctx, cancel := context.WithCancel(context.WithValue(parent, shutdownKey{}, s))
+
+	go func() {
+		var sig os.Signal
+		select {
+		case sig = <-signals:
+		case <-ctx.Done():
+			return
+		}
+		s.once.Do(func() {
+			s.signal = sig
+			close(s.stopping)
+		})
+		yellow.Fprintf(w, "⚠️  Received %s: finishing in-flight work (signal again to abort)\n", sig)
+
+		timer := time.NewTimer(grace)
+		defer timer.Stop()
+		select {
+		case <-signals:
+		case <-timer.C:
+		case <-ctx.Done():
+		}
+		cancel()
+	}()
+	return ctx, cancel
+}
+
+// stopping returns a channel closed once shutdown begins, or nil (never
+// closed) when ctx carries no shutdown
+func stopping(ctx context.Context) <-chan struct{} {
+	if s, ok := ctx.Value(shutdownKey{}).(*shutdown); ok {
+		return s.stopping
+	}
+	return nil
+}
+
+// stopContext returns a context cancelled once shutdown begins, for servers
+// that stop accepting work then
+func stopContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	stopCtx, cancel := context.WithCancel(ctx)
+	go func() {
+		select {
+		case <-stopping(ctx):
+			cancel()
+		case <-stopCtx.Done():
+		}
+	}()
+	return stopCtx, cancel
+}
+
+// interrupted returns an error exiting 128 plus the signal number, as shells
+// report, if shutdown has begun
+func interrupted(ctx context.Context) error {
+	s, ok := ctx.Value(shutdownKey{}).(*shutdown)
+	if !ok {
+		return nil
+	}
+	select {
+	case <-s.stopping:
+	default:
+		return nil
+	}
+	code := 1
+	if sig, ok := s.signal.(syscall.Signal); ok {
+		code = 128 + int(sig)
+	}
+	return &exitError{code: code, err: fmt.Errorf("interrupted by %s", s.signal)}
+}
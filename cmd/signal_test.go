@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestWithShutdown(t *testing.T) {
+	tests := []struct {
+		name   string
+		second bool // Send a second signal rather than wait out the grace period
+		grace  time.Duration
+	}{
+		{name: "second signal aborts", second: true, grace: time.Minute},
+		{name: "grace period aborts", grace: 10 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signals := make(chan os.Signal, 2)
+			ctx, cancel := withShutdown(context.Background(), signals, tt.grace, io.Discard)
+			defer cancel()
+
+			if err := interrupted(ctx); err != nil {
+				t.Fatalf("interrupted() before a signal = %v", err)
+			}
+			signals <- syscall.SIGTERM
+			<-stopping(ctx)
+			if ctx.Err() != nil && tt.second {
+				t.Fatal("context cancelled by the first signal")
+			}
+			if err := interrupted(ctx); ExitCode(err) != 143 {
+				t.Errorf("interrupted() = %v (exit %d), want exit 143", err, ExitCode(err))
+			}
+
+			if tt.second {
+				signals <- syscall.SIGTERM
+			}
+			select {
+			case <-ctx.Done():
+			case <-time.After(5 * time.Second):
+				t.Fatal("context not cancelled")
+			}
+		})
+	}
+}
+
+func TestStopContext(t *testing.T) {
+	signals := make(chan os.Signal, 1)
+	ctx, cancel := withShutdown(context.Background(), signals, time.Minute, io.Discard)
+	defer cancel()
+
+	stopCtx, stop := stopContext(ctx)
+	defer stop()
+	signals <- os.Interrupt
+	select {
+	case <-stopCtx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("stop context not cancelled when shutdown began")
+	}
+	if ctx.Err() != nil {
+		t.Error("work context cancelled when shutdown began")
+	}
+
+	// Without a shutdown, nothing stops
+	if stopping(context.Background()) != nil || interrupted(context.Background()) != nil {
+		t.Error("shutdown found in a plain context")
+	}
+}
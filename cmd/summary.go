@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/nickromney-org/github-release-version-checker/pkg/render"
+)
+
+// ciSummaryTemplate is the user-supplied job summary template, resolved from flags in run
+var ciSummaryTemplate *template.Template
+
+// validateSummarySections checks --summary-exclude values against the known sections
+func validateSummarySections(names []string) error {
+	for _, name := range names {
+		if !containsString(render.SummarySections, strings.ToLower(strings.TrimSpace(name))) {
+			return fmt.Errorf("invalid summary section %q: must be one of %s", name, strings.Join(render.SummarySections, ", "))
+		}
+	}
+	return nil
+}
+
+// loadSummaryTemplate parses a Go text/template file for the job summary
+func loadSummaryTemplate(path string) (*template.Template, error) {
+	tmpl, err := template.New(filepath.Base(path)).Funcs(render.TemplateFuncs(render.Options{})).ParseFiles(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse summary template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// containsString reports whether a slice contains a string
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nickromney-org/github-release-version-checker/pkg/checker"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+func summaryTestAnalysis() *checker.Analysis {
+	released := time.Now().AddDate(0, 0, -35)
+	return &checker.Analysis{
+		LatestVersion:     mustParseVersion("2.329.0"),
+		ComparisonVersion: mustParseVersion("2.327.0"),
+		IsExpired:         true,
+		ReleasesBehind:    2,
+		DaysSinceUpdate:   35,
+		MaxAgeDays:        30,
+		FirstNewerVersion: mustParseVersion("2.328.0"),
+		NewerReleases: []types.Release{
+			{Version: mustParseVersion("2.328.0"), PublishedAt: released, URL: "https://example.com/2.328.0"},
+		},
+	}
+}
+
+func TestWriteGitHubSummary_ExcludeSections(t *testing.T) {
+	summaryFile := filepath.Join(t.TempDir(), "summary.md")
+
+	summaryExclude = []string{"updates", "timestamp"}
+	defer func() { summaryExclude = nil }()
+
+	if err := writeGitHubSummary(summaryFile, summaryTestAnalysis()); err != nil {
+		t.Fatalf("writeGitHubSummary() error = %v", err)
+	}
+
+	data, err := os.ReadFile(summaryFile)
+	if err != nil {
+		t.Fatalf("failed to read summary: %v", err)
+	}
+	summary := string(data)
+
+	if !strings.Contains(summary, "| Days Overdue | 5 |") {
+		t.Errorf("expected summary table, got:\n%s", summary)
+	}
+	if strings.Contains(summary, "Available Updates") {
+		t.Errorf("expected updates section to be omitted, got:\n%s", summary)
+	}
+	if strings.Contains(summary, "Checked at") {
+		t.Errorf("expected timestamp to be omitted, got:\n%s", summary)
+	}
+}
+
+func TestWriteGitHubSummary_Template(t *testing.T) {
+	dir := t.TempDir()
+	templateFile := filepath.Join(dir, "summary.tmpl")
+	summaryFile := filepath.Join(dir, "summary.md")
+
+	content := "{{ .StatusIcon }} v{{ .Analysis.ComparisonVersion }} is {{ .Status }} ({{ .DaysOverdue }} days overdue)\n" +
+		"{{ range .Analysis.NewerReleases }}- v{{ .Version }}\n{{ end }}"
+	if err := os.WriteFile(templateFile, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+
+	tmpl, err := loadSummaryTemplate(templateFile)
+	if err != nil {
+		t.Fatalf("loadSummaryTemplate() error = %v", err)
+	}
+	ciSummaryTemplate = tmpl
+	defer func() { ciSummaryTemplate = nil }()
+
+	if err := writeGitHubSummary(summaryFile, summaryTestAnalysis()); err != nil {
+		t.Fatalf("writeGitHubSummary() error = %v", err)
+	}
+
+	data, err := os.ReadFile(summaryFile)
+	if err != nil {
+		t.Fatalf("failed to read summary: %v", err)
+	}
+
+	expected := "🚨 v2.327.0 is expired (5 days overdue)\n- v2.328.0\n"
+	if string(data) != expected {
+		t.Errorf("unexpected summary:\n got: %q\nwant: %q", string(data), expected)
+	}
+}
+
+func TestValidateSummarySections(t *testing.T) {
+	if err := validateSummarySections([]string{"updates", "Action"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := validateSummarySections([]string{"footer"}); err == nil {
+		t.Error("expected error for unknown section, got nil")
+	}
+}
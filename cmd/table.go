@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nickromney-org/github-release-version-checker/pkg/render"
+)
+
+// activeTableColumns are the timeline columns to show, resolved from --columns in run
+var activeTableColumns = render.Columns
+
+// resolveTableColumns validates --columns values, dropping duplicates
+func resolveTableColumns(names []string) ([]string, error) {
+	if len(names) == 0 {
+		return render.Columns, nil
+	}
+
+	valid := append(append([]string(nil), render.Columns...), render.OptionalColumns...)
+	var columns []string
+	for _, name := range names {
+		column := strings.ToLower(strings.TrimSpace(name))
+		if !containsString(valid, column) {
+			return nil, fmt.Errorf("invalid column %q: must be one of %s", name, strings.Join(valid, ", "))
+		}
+		if !containsString(columns, column) {
+			columns = append(columns, column)
+		}
+	}
+	return columns, nil
+}
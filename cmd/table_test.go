@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResolveTableColumns(t *testing.T) {
+	columns, err := resolveTableColumns([]string{"Version", "status", "version"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(columns, ",") != "version,status" {
+		t.Errorf("got %v, want [version status]", columns)
+	}
+
+	// Optional columns are only shown when chosen
+	if columns, err := resolveTableColumns([]string{"version", "gap"}); err != nil || strings.Join(columns, ",") != "version,gap" {
+		t.Errorf("got %v, %v, want [version gap]", columns, err)
+	}
+	if columns, _ := resolveTableColumns(nil); containsString(columns, "gap") {
+		t.Errorf("default columns %v include gap", columns)
+	}
+
+	if _, err := resolveTableColumns([]string{"notes"}); err == nil {
+		t.Error("expected error for unknown column, got nil")
+	}
+}
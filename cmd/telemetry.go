@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/nickromney-org/github-release-version-checker/internal/telemetry"
+	"github.com/nickromney-org/github-release-version-checker/pkg/checker"
+)
+
+// traceExportTimeout bounds how long exporting spans may delay exit
+const traceExportTimeout = 5 * time.Second
+
+// tracer records OpenTelemetry spans when an OTLP endpoint is configured in the
+// environment; nil otherwise
+var tracer *telemetry.Tracer
+
+// stopExport stops exporting spans in the background, once any export under
+// way has finished
+var stopExport = func() {}
+
+// startTracing enables tracing from the OTEL_* environment variables, exports
+// ended spans in batches while the run lasts, so a long-running proxy sends them
+// as it goes, and starts the span covering the whole run. A bad configuration is
+// reported and ignored.
+func startTracing(ctx context.Context, stderr io.Writer) (context.Context, *telemetry.Span) {
+	t, err := telemetry.FromEnv(os.Getenv, appVersion)
+	if err != nil {
+		fmt.Fprintf(stderr, "Warning: tracing disabled: %v\n", err)
+		return ctx, nil
+	}
+	tracer = t
+	if tracer != nil {
+		exportCtx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			tracer.ExportEvery(exportCtx, http.DefaultClient, telemetry.ExportInterval, func(err error) {
+				fmt.Fprintf(stderr, "Warning: %v\n", err)
+			})
+		}()
+		stopExport = func() {
+			cancel()
+			<-done
+		}
+	}
+	return tracer.Start(ctx, "github-release-version-checker", telemetry.KindInternal)
+}
+
+// finishTracing ends the run's span and exports the spans not yet sent
+func finishTracing(span *telemetry.Span, err error, stderr io.Writer) {
+	if tracer == nil {
+		return
+	}
+	stopExport()
+	if err != nil {
+		span.SetAttributes(telemetry.Int("process.exit.code", ExitCode(err)))
+		if !isQuietExit(err) {
+			span.RecordError(err)
+		}
+	}
+	span.End()
+
+	ctx, cancel := context.WithTimeout(context.Background(), traceExportTimeout)
+	defer cancel()
+	if exportErr := tracer.Export(ctx, http.DefaultClient); exportErr != nil {
+		fmt.Fprintf(stderr, "Warning: %v\n", exportErr)
+	}
+}
+
+// analyseTraced runs an analysis inside a span recording its outcome
+func analyseTraced(ctx context.Context, versionChecker *checker.Checker, repo, version string) (*checker.Analysis, error) {
+	ctx, span := tracer.Start(ctx, "analyse", telemetry.KindInternal,
+		telemetry.String("repository", repo),
+		telemetry.String("comparison_version", version),
+	)
+	defer span.End()
+
+	analysis, err := versionChecker.Analyse(ctx, version)
+	logger := jobLogger()
+	if err != nil {
+		span.RecordError(err)
+		if logger != nil {
+			logger.Error("analysis failed", "repository", repo, "version", version,
+				"error", err.Error(), "error_code", classifyError(err).Code)
+		}
+		return analysis, err
+	}
+	if logger != nil {
+		logger.Info("analysis", "repository", repo, "version", version,
+			"status", analysis.Status(),
+			"latest", analysis.LatestVersion.String(),
+			"releases_behind", analysis.ReleasesBehind,
+			"drift_score", analysis.DriftScore(),
+			"degraded", analysis.IsDegraded(),
+		)
+	}
+	span.SetAttributes(
+		telemetry.String("latest_version", analysis.LatestVersion.String()),
+		telemetry.String("status", string(analysis.Status())),
+		telemetry.Int("releases_behind", analysis.ReleasesBehind),
+		telemetry.Int("drift_score", analysis.DriftScore()),
+		telemetry.Bool("degraded", analysis.IsDegraded()),
+	)
+	return analysis, nil
+}
+
+// tracingHooks records the checker's release fetches as spans, and its cache
+// decisions and policy results as events on the analysis span
+func tracingHooks() checker.Hooks {
+	fetches := make(map[checker.FetchKind]*telemetry.Span)
+	return checker.Hooks{
+		OnFetchStart: func(ctx context.Context, e checker.FetchEvent) {
+			_, fetches[e.Kind] = tracer.Start(ctx, "fetch releases", telemetry.KindInternal,
+				telemetry.String("fetch.kind", string(e.Kind)))
+		},
+		OnFetchEnd: func(ctx context.Context, e checker.FetchEvent) {
+			span := fetches[e.Kind]
+			delete(fetches, e.Kind)
+			span.SetAttributes(telemetry.Int("releases", e.Releases))
+			span.RecordError(e.Err)
+			span.End()
+		},
+		OnCacheDecision: func(ctx context.Context, e checker.CacheEvent) {
+			telemetry.SpanFromContext(ctx).AddEvent("embedded cache "+string(e.Decision),
+				telemetry.String("reason", e.Reason))
+		},
+		OnPolicyEvaluated: func(ctx context.Context, e checker.PolicyEvent) {
+			telemetry.SpanFromContext(ctx).AddEvent("policy evaluated",
+				telemetry.String("policy.type", e.PolicyType),
+				telemetry.Bool("policy.expired", e.Result.IsExpired),
+				telemetry.Bool("policy.critical", e.Result.IsCritical),
+			)
+		},
+	}
+}
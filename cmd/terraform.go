@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/nickromney-org/github-release-version-checker/internal/config"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+// terraformLockFile is the dependency lock file in a Terraform root module
+const terraformLockFile = ".terraform.lock.hcl"
+
+// terraformRepo is where Terraform itself is released
+const terraformRepo = "hashicorp/terraform"
+
+// Public registries whose providers are released from owner/terraform-provider-name
+var terraformRegistries = []string{"registry.terraform.io", "registry.opentofu.org"}
+
+var (
+	terraformDir  string            // --terraform
+	providerRepos map[string]string // --provider-repo
+)
+
+var (
+	lockProvider    = regexp.MustCompile(`^\s*provider\s+"([^"]+)"\s*\{`)
+	lockVersion     = regexp.MustCompile(`^\s*version\s*=\s*"([^"]+)"`)
+	requiredVersion = regexp.MustCompile(`^\s*required_version\s*=\s*"([^"]+)"`)
+)
+
+// terraformRepositories lists the repositories to check for the Terraform root
+// module in dir: Terraform at the lowest version required_version allows, and each
+// provider locked in .terraform.lock.hcl. Providers that cannot be mapped to a
+// repository are reported to w and skipped.
+func terraformRepositories(w io.Writer, dir string, overrides map[string]string) ([]config.FileRepository, error) {
+	var entries []config.FileRepository
+
+	constraint, file, err := findRequiredVersion(dir)
+	if err != nil {
+		return nil, err
+	}
+	if constraint != "" {
+		floor, err := constraintFloor(constraint)
+		if err != nil {
+			return nil, fmt.Errorf("%s: required_version: %w", file, err)
+		}
+		entries = append(entries, config.FileRepository{Repo: terraformRepo, Version: floor.String()})
+	}
+
+	providers, err := readLockFile(filepath.Join(dir, terraformLockFile))
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range providers {
+		repo := providerRepository(p.Source, overrides)
+		if repo == "" {
+			yellow.Fprintf(w, "⚠️  Skipping provider %s: no repository known; name one with --provider-repo %s=OWNER/REPO\n", p.Source, p.Source)
+			continue
+		}
+		entries = append(entries, config.FileRepository{Repo: repo, Version: p.Version})
+	}
+
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no required_version or locked providers found in %s", dir)
+	}
+	return entries, nil
+}
+
+// lockedProvider is a provider version selected in the lock file
+type lockedProvider struct {
+	Source  string // e.g. registry.terraform.io/hashicorp/aws
+	Version string
+}
+
+// readLockFile returns the providers in a .terraform.lock.hcl; a missing file has none
+func readLockFile(path string) ([]lockedProvider, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lock file: %w", err)
+	}
+
+	var providers []lockedProvider
+	var current *lockedProvider
+	depth := 0
+	for _, line := range strings.Split(string(data), "\n") {
+		if depth == 0 {
+			if m := lockProvider.FindStringSubmatch(line); m != nil {
+				current = &lockedProvider{Source: strings.ToLower(m[1])}
+				depth = strings.Count(line, "{") - strings.Count(line, "}")
+			}
+			continue
+		}
+		if m := lockVersion.FindStringSubmatch(line); m != nil && depth == 1 && current.Version == "" {
+			current.Version = m[1]
+		}
+		depth += strings.Count(line, "{") - strings.Count(line, "}")
+		if depth <= 0 {
+			if current.Version == "" {
+				return nil, fmt.Errorf("%s: provider %q has no version", path, current.Source)
+			}
+			providers = append(providers, *current)
+			depth = 0
+		}
+	}
+	return providers, nil
+}
+
+// findRequiredVersion returns the first required_version constraint in dir's .tf
+// files, in name order, and the file it is in
+func findRequiredVersion(dir string) (string, string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.tf"))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to list Terraform files: %w", err)
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			if m := requiredVersion.FindStringSubmatch(line); m != nil {
+				return m[1], file, nil
+			}
+		}
+	}
+	return "", "", nil
+}
+
+// constraintFloor returns the lowest version a Terraform version constraint such
+// as ">= 1.5.0, < 2.0.0" or "~> 1.6" allows
+func constraintFloor(constraint string) (*semver.Version, error) {
+	var floor *semver.Version
+	for _, part := range strings.Split(constraint, ",") {
+		part = strings.TrimSpace(part)
+		op := ""
+		for _, candidate := range []string{">=", "~>", "<=", "!=", ">", "<", "="} {
+			if strings.HasPrefix(part, candidate) {
+				op = candidate
+				break
+			}
+		}
+		switch op {
+		case "", "=", ">=", ">", "~>":
+		default:
+			continue // Upper bounds and exclusions do not raise the floor
+		}
+		v, err := types.ParseVersion(strings.TrimSpace(strings.TrimPrefix(part, op)))
+		if err != nil {
+			return nil, fmt.Errorf("invalid constraint %q: %w", part, err)
+		}
+		if floor == nil || types.CompareVersions(v, floor) > 0 {
+			floor = v
+		}
+	}
+	if floor == nil {
+		return nil, fmt.Errorf("constraint %q has no lower bound", constraint)
+	}
+	return floor, nil
+}
+
+// providerRepository returns the repository a provider is released from: one
+// named with --provider-repo, or owner/terraform-provider-name for providers on
+// a public registry. It returns "" when neither applies.
+func providerRepository(source string, overrides map[string]string) string {
+	for key, repo := range overrides {
+		if strings.EqualFold(key, source) || strings.EqualFold(terraformRegistries[0]+"/"+key, source) {
+			return repo
+		}
+	}
+	parts := strings.Split(source, "/")
+	if len(parts) != 3 || parts[1] == "builtin" {
+		return ""
+	}
+	for _, registry := range terraformRegistries {
+		if parts[0] == registry {
+			return parts[1] + "/terraform-provider-" + parts[2]
+		}
+	}
+	return ""
+}
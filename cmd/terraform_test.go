@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nickromney-org/github-release-version-checker/internal/config"
+)
+
+const testLockFile = `# This file is maintained automatically by "terraform init".
+
+provider "registry.terraform.io/hashicorp/aws" {
+  version     = "5.31.0"
+  constraints = ">= 5.0.0"
+  hashes = [
+    "h1:abc=",
+  ]
+}
+
+provider "registry.terraform.io/integrations/github" {
+  version = "6.2.1"
+}
+
+provider "tf.example.com/acme/widget" {
+  version = "0.4.0"
+}
+`
+
+func TestConstraintFloor(t *testing.T) {
+	tests := []struct {
+		constraint string
+		want       string
+		wantErr    string
+	}{
+		{"1.6.2", "1.6.2", ""},
+		{"= 1.6.2", "1.6.2", ""},
+		{">= 1.5.0, < 2.0.0", "1.5.0", ""},
+		{"~> 1.6", "1.6.0", ""},
+		{">= 1.3, >= 1.5.7, != 1.6.0", "1.5.7", ""},
+		{"< 2.0.0", "", "no lower bound"},
+		{">= banana", "", "invalid constraint"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			got, err := constraintFloor(tt.constraint)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("constraintFloor() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("constraintFloor() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProviderRepository(t *testing.T) {
+	overrides := map[string]string{
+		"tf.example.com/acme/widget": "acme/tf-widget",
+		"hashicorp/google-beta":      "hashicorp/terraform-provider-google-beta-fork",
+	}
+	tests := map[string]string{
+		"registry.terraform.io/hashicorp/aws":          "hashicorp/terraform-provider-aws",
+		"registry.opentofu.org/integrations/github":    "integrations/terraform-provider-github",
+		"tf.example.com/acme/widget":                   "acme/tf-widget",
+		"registry.terraform.io/hashicorp/google-beta":  "hashicorp/terraform-provider-google-beta-fork",
+		"terraform.io/builtin/terraform":               "",
+		"tf.example.com/acme/gadget":                   "",
+		"registry.terraform.io/hashicorp/aws/too/deep": "",
+	}
+	for source, want := range tests {
+		if got := providerRepository(source, overrides); got != want {
+			t.Errorf("providerRepository(%q) = %q, want %q", source, got, want)
+		}
+	}
+}
+
+func TestTerraformRepositories(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile(terraformLockFile, testLockFile)
+	writeFile("main.tf", "resource \"null_resource\" \"x\" {}\n")
+	writeFile("versions.tf", "terraform {\n  required_version = \">= 1.5.0, < 2.0.0\"\n}\n")
+
+	var warnings bytes.Buffer
+	got, err := terraformRepositories(&warnings, dir, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []config.FileRepository{
+		{Repo: "hashicorp/terraform", Version: "1.5.0"},
+		{Repo: "hashicorp/terraform-provider-aws", Version: "5.31.0"},
+		{Repo: "integrations/terraform-provider-github", Version: "6.2.1"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("terraformRepositories() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i].Repo != want[i].Repo || got[i].Version != want[i].Version {
+			t.Errorf("entry %d = %s@%s, want %s@%s", i, got[i].Repo, got[i].Version, want[i].Repo, want[i].Version)
+		}
+	}
+	if !strings.Contains(warnings.String(), "Skipping provider tf.example.com/acme/widget") {
+		t.Errorf("warnings = %q, want the unmapped provider skipped", warnings.String())
+	}
+
+	if _, err := terraformRepositories(&warnings, t.TempDir(), nil); err == nil || !strings.Contains(err.Error(), "no required_version or locked providers") {
+		t.Errorf("empty module error = %v", err)
+	}
+}
+
+func TestReadLockFile_MissingVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), terraformLockFile)
+	if err := os.WriteFile(path, []byte("provider \"registry.terraform.io/hashicorp/aws\" {\n  hashes = []\n}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readLockFile(path); err == nil || !strings.Contains(err.Error(), "has no version") {
+		t.Errorf("readLockFile() error = %v, want a missing version", err)
+	}
+}
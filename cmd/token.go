@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/nickromney-org/github-release-version-checker/pkg/client"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultGitHubHost is the host tokens are looked up for unless GHES is in use
+const defaultGitHubHost = "github.com"
+
+// tokenSource records where the GitHub token came from
+type tokenSource string
+
+const (
+	tokenSourceFlag    tokenSource = "flag"     // -t/--token
+	tokenSourceEnv     tokenSource = "env"      // GH_TOKEN, GITHUB_TOKEN or a configured variable
+	tokenSourceGHHosts tokenSource = "gh-hosts" // gh's hosts.yml, read directly
+	tokenSourceNetrc   tokenSource = "netrc"    // ~/.netrc or $NETRC
+	tokenSourceGHCLI   tokenSource = "gh-cli"   // gh auth token (keyring-stored tokens)
+	tokenSourceNone    tokenSource = "none"     // Unauthenticated requests
+)
+
+// resolvedToken is a GitHub token and where it came from
+type resolvedToken struct {
+	Value  string
+	Source tokenSource
+	Detail string // Environment variable or file the token was read from
+}
+
+// describe returns the source for messages, e.g. "env (GH_TOKEN)"
+func (t resolvedToken) describe() string {
+	if t.Detail != "" {
+		return fmt.Sprintf("%s (%s)", t.Source, t.Detail)
+	}
+	return string(t.Source)
+}
+
+// tokenEnvVars returns the environment variables gh reads for a host, in priority order
+func tokenEnvVars(host string) []string {
+	if host == defaultGitHubHost {
+		return []string{"GH_TOKEN", "GITHUB_TOKEN"}
+	}
+	return []string{"GH_ENTERPRISE_TOKEN", "GITHUB_ENTERPRISE_TOKEN"}
+}
+
+// detectGitHubToken attempts to find a GitHub token for host from multiple sources,
+// in the same order as the gh CLI so both tools use the same credentials
+func detectGitHubToken(flagToken string, host string) resolvedToken {
+	// 1. Use explicitly provided token (via -t flag)
+	if flagToken != "" {
+		return resolvedToken{Value: flagToken, Source: tokenSourceFlag}
+	}
+
+	// 2. Environment variables (GITHUB_TOKEN is automatically available in GitHub Actions)
+	for _, name := range tokenEnvVars(host) {
+		if token := os.Getenv(name); token != "" {
+			return resolvedToken{Value: token, Source: tokenSourceEnv, Detail: name}
+		}
+	}
+
+	// 3. gh's hosts.yml, without needing gh installed
+	if token, path, err := readGHHostsToken(host); err == nil && token != "" {
+		return resolvedToken{Value: token, Source: tokenSourceGHHosts, Detail: path}
+	}
+
+	// 4. .netrc, for automation that already provisions one
+	if token, path, err := readNetrcToken(host); err == nil {
+		return resolvedToken{Value: token, Source: tokenSourceNetrc, Detail: path}
+	}
+
+	// 5. gh CLI, for tokens kept in the system keyring rather than hosts.yml
+	if token, err := getGitHubCLIToken(host); err == nil && token != "" {
+		return resolvedToken{Value: token, Source: tokenSourceGHCLI}
+	}
+
+	// 6. No token found - will use unauthenticated requests
+	return resolvedToken{Source: tokenSourceNone}
+}
+
+// ghConfigDir returns the gh CLI configuration directory, following gh's own lookup
+func ghConfigDir() (string, error) {
+	if dir := os.Getenv("GH_CONFIG_DIR"); dir != "" {
+		return dir, nil
+	}
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "gh"), nil
+	}
+	if runtime.GOOS == "windows" {
+		if dir := os.Getenv("AppData"); dir != "" {
+			return filepath.Join(dir, "GitHub CLI"), nil
+		}
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "gh"), nil
+}
+
+// ghHostEntry is one host in gh's hosts.yml
+type ghHostEntry struct {
+	OAuthToken string `yaml:"oauth_token"`
+	User       string `yaml:"user"`
+	Users      map[string]struct {
+		OAuthToken string `yaml:"oauth_token"`
+	} `yaml:"users"`
+}
+
+// readGHHostsToken reads the token for host from gh's hosts.yml, returning the file path read
+func readGHHostsToken(host string) (string, string, error) {
+	dir, err := ghConfigDir()
+	if err != nil {
+		return "", "", err
+	}
+	path := filepath.Join(dir, "hosts.yml")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", path, err
+	}
+
+	var hosts map[string]ghHostEntry
+	if err := yaml.Unmarshal(data, &hosts); err != nil {
+		return "", path, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	entry, ok := hosts[host]
+	if !ok {
+		return "", path, fmt.Errorf("no entry for %s in %s", host, path)
+	}
+	if entry.OAuthToken != "" {
+		return entry.OAuthToken, path, nil
+	}
+	// Multi-account layout: the active user's token
+	return entry.Users[entry.User].OAuthToken, path, nil
+}
+
+// ghExecutable finds the gh CLI. The Windows installers put gh.exe under Program
+// Files, which services such as self-hosted runners may not have on PATH.
+func ghExecutable() (string, error) {
+	path, err := exec.LookPath("gh")
+	if err == nil || runtime.GOOS != "windows" {
+		return path, err
+	}
+	for _, candidate := range windowsGHPaths(os.Getenv) {
+		if info, statErr := os.Stat(candidate); statErr == nil && !info.IsDir() {
+			return candidate, nil
+		}
+	}
+	return "", err
+}
+
+// windowsGHPaths lists where the gh installers (MSI, winget, per-user) put gh.exe
+func windowsGHPaths(getenv func(string) string) []string {
+	var paths []string
+	for _, dir := range []struct{ env, sub string }{
+		{"ProgramFiles", "GitHub CLI"},
+		{"ProgramFiles(x86)", "GitHub CLI"},
+		{"LocalAppData", filepath.Join("Programs", "GitHub CLI")},
+	} {
+		if base := getenv(dir.env); base != "" {
+			paths = append(paths, filepath.Join(base, dir.sub, "gh.exe"))
+		}
+	}
+	return paths
+}
+
+// getGitHubCLIToken attempts to retrieve a token for host from the GitHub CLI
+func getGitHubCLIToken(host string) (string, error) {
+	gh, err := ghExecutable()
+	if err != nil {
+		return "", err
+	}
+	cmd := exec.Command(gh, "auth", "token", "--hostname", host)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+
+	token := strings.TrimSpace(string(output))
+	if token == "" {
+		return "", fmt.Errorf("gh auth token returned empty")
+	}
+
+	return token, nil
+}
+
+// tokenAccessError explains a failed up-front token check in terms of the token's source
+func tokenAccessError(err error, source string, repo string) error {
+	switch {
+	case errors.Is(err, client.ErrBadCredentials):
+		return &explainedError{fmt.Sprintf("GitHub token from %s was rejected (bad credentials); check it has not expired or been revoked", source), err}
+	case errors.Is(err, client.ErrNoAccess):
+		return fmt.Errorf("GitHub token from %s cannot read %s: fine-grained tokens need read access to this repository's contents, and tokens for SSO organisations must be authorised (%w)", source, repo, err)
+	case errors.Is(err, client.ErrNotFound):
+		return &explainedError{fmt.Sprintf("%s not found, or the GitHub token from %s cannot see it: private repositories need the 'repo' scope (classic tokens) or repository access (fine-grained tokens)", repo, source), err}
+	default:
+		return err
+	}
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nickromney-org/github-release-version-checker/pkg/client"
+)
+
+func TestResolvedTokenDescribe(t *testing.T) {
+	if got := (resolvedToken{Source: tokenSourceEnv, Detail: "RELEASE_TOKEN"}).describe(); got != "env (RELEASE_TOKEN)" {
+		t.Errorf("describe() = %q", got)
+	}
+	if got := (resolvedToken{Source: tokenSourceGHCLI}).describe(); got != "gh-cli" {
+		t.Errorf("describe() = %q", got)
+	}
+}
+
+func TestTokenAccessError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "bad credentials", err: client.ErrBadCredentials, want: "token from flag was rejected"},
+		{name: "fine-grained token without access", err: fmt.Errorf("%w: Resource not accessible by personal access token", client.ErrNoAccess), want: "cannot read owner/private"},
+		{name: "hidden repository", err: client.ErrNotFound, want: "private repositories need the 'repo' scope"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tokenAccessError(tt.err, "flag", "owner/private")
+			if !strings.Contains(got.Error(), tt.want) {
+				t.Errorf("tokenAccessError() = %q, want containing %q", got, tt.want)
+			}
+		})
+	}
+
+	other := errors.New("dial tcp: no such host")
+	if got := tokenAccessError(other, "flag", "owner/repo"); got != other {
+		t.Errorf("expected other errors to pass through unchanged, got %v", got)
+	}
+}
+
+func TestWindowsGHPaths(t *testing.T) {
+	env := map[string]string{
+		"ProgramFiles": `C:\Program Files`,
+		"LocalAppData": `C:\Users\runner\AppData\Local`,
+	}
+	got := windowsGHPaths(func(name string) string { return env[name] })
+	want := []string{
+		filepath.Join(`C:\Program Files`, "GitHub CLI", "gh.exe"),
+		filepath.Join(`C:\Users\runner\AppData\Local`, "Programs", "GitHub CLI", "gh.exe"),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("windowsGHPaths() = %v, want %v", got, want)
+	}
+}
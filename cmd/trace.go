@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"io"
+	"log/slog"
+)
+
+// Verbosity levels for -v, -vv and -vvv
+const (
+	verbosityDetails = 1 // Detailed analysis and timeline
+	verbosityCache   = 2 // Cache decisions
+	verbosityTrace   = 3 // Every HTTP request and skipped release
+)
+
+// logJSON receives trace and analysis logs as JSON lines in place of the text
+// trace on stderr, when set by the job command
+var logJSON io.Writer
+
+// newTraceLogger returns a logger writing to w for the given verbosity,
+// or nil when tracing is not requested
+func newTraceLogger(w io.Writer, verbosity int) *slog.Logger {
+	if verbosity < verbosityCache {
+		return nil
+	}
+
+	level := slog.LevelInfo
+	if verbosity >= verbosityTrace {
+		level = slog.LevelDebug
+	}
+
+	if logJSON != nil {
+		return slog.New(slog.NewJSONHandler(logJSON, &slog.HandlerOptions{Level: level}))
+	}
+	return slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{Level: level}))
+}
+
+// jobLogger returns the logger for job records, such as each analysis, or nil
+// outside the job command
+func jobLogger() *slog.Logger {
+	if logJSON == nil {
+		return nil
+	}
+	return slog.New(slog.NewJSONHandler(logJSON, nil))
+}
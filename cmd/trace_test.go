@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+)
+
+func TestNewTraceLogger(t *testing.T) {
+	tests := []struct {
+		verbosity int
+		wantNil   bool
+		wantInfo  bool
+		wantDebug bool
+	}{
+		{verbosity: 0, wantNil: true},
+		{verbosity: 1, wantNil: true},
+		{verbosity: 2, wantInfo: true},
+		{verbosity: 3, wantInfo: true, wantDebug: true},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		logger := newTraceLogger(&buf, tt.verbosity)
+		if (logger == nil) != tt.wantNil {
+			t.Fatalf("verbosity %d: logger nil = %v, want %v", tt.verbosity, logger == nil, tt.wantNil)
+		}
+		if logger == nil {
+			continue
+		}
+		if got := logger.Enabled(context.Background(), slog.LevelInfo); got != tt.wantInfo {
+			t.Errorf("verbosity %d: info enabled = %v, want %v", tt.verbosity, got, tt.wantInfo)
+		}
+		if got := logger.Enabled(context.Background(), slog.LevelDebug); got != tt.wantDebug {
+			t.Errorf("verbosity %d: debug enabled = %v, want %v", tt.verbosity, got, tt.wantDebug)
+		}
+	}
+}
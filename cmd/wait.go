@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/nickromney-org/github-release-version-checker/pkg/checker"
+	"github.com/nickromney-org/github-release-version-checker/pkg/releaseset"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+// exitTimeout is the exit code when wait gives up, as timeout(1) uses
+const exitTimeout = 124
+
+// minWaitPoll keeps polling within unauthenticated rate limits
+const minWaitPoll = 10 * time.Second
+
+// waitRecentReleases is how many of the newest releases each poll fetches
+const waitRecentReleases = 10
+
+var (
+	waitRepo      string
+	waitToken     string
+	waitAfter     string
+	waitTimeout   time.Duration
+	waitPoll      time.Duration
+	waitPlatforms []string
+)
+
+var waitCmd = &cobra.Command{
+	Use:   "wait",
+	Short: "Wait until a newer release is published",
+	Long: `Poll a repository until a stable release newer than --until-newer-than is
+published, optionally with assets for every --require-assets platform, then print
+its version on stdout. Progress goes to stderr, so pipelines can capture the version
+and trigger follow-on automation when a release lands.
+
+Exits 124 if --timeout passes first. Errors on the first poll, such as a missing
+repository, fail at once; later ones are reported and polling carries on.`,
+	Example: `  # Block for up to 2 hours until a runner newer than 2.328.0 is out
+  github-release-version-checker wait --repo actions/runner --until-newer-than 2.328.0 --timeout 2h --poll 5m
+
+  # Wait for the Linux and Windows assets too, then use the version
+  version=$(github-release-version-checker wait --until-newer-than 2.328.0 --require-assets linux-x64,win-x64)`,
+	Args: cobra.NoArgs,
+	RunE: runWait,
+}
+
+func init() {
+	waitCmd.Flags().StringVarP(&waitRepo, "repo", "r", "", "repository to watch (default: actions/runner)")
+	waitCmd.Flags().StringVarP(&waitToken, "token", "t", "", "GitHub token (default: GH_TOKEN, GITHUB_TOKEN, then gh's stored login)")
+	waitCmd.Flags().StringVar(&waitAfter, "until-newer-than", "", "wait for a release newer than this version (required)")
+	waitCmd.Flags().DurationVar(&waitTimeout, "timeout", time.Hour, "how long to wait before giving up (0 waits indefinitely)")
+	waitCmd.Flags().DurationVar(&waitPoll, "poll", 5*time.Minute, "how often to check for a release (at least 10s)")
+	waitCmd.Flags().StringSliceVar(&waitPlatforms, "require-assets", nil, "also wait for an asset for each platform, e.g. linux-x64,linux-arm64,win-x64")
+	_ = waitCmd.MarkFlagRequired("until-newer-than")
+	rootCmd.AddCommand(waitCmd)
+}
+
+// releaseWatcher is what wait polls; *client.Client implements it
+type releaseWatcher interface {
+	GetRecentReleases(ctx context.Context, count int) ([]types.Release, error)
+	checker.AssetLister
+}
+
+func runWait(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	after, err := types.ParseVersion(waitAfter)
+	if err != nil {
+		return invalidInput(fmt.Errorf("invalid --until-newer-than %q: %w", waitAfter, err))
+	}
+	if waitPoll < minWaitPoll {
+		return invalidInput(fmt.Errorf("--poll must be at least %s", minWaitPoll))
+	}
+	if waitTimeout < 0 {
+		return invalidInput(fmt.Errorf("--timeout must be non-negative"))
+	}
+	for _, p := range waitPlatforms {
+		if strings.TrimSpace(p) == "" {
+			return invalidInput(fmt.Errorf("--require-assets cannot include an empty platform"))
+		}
+	}
+
+	repoName := waitRepo
+	if repoName == "" {
+		repoName = "actions/runner"
+	}
+	repoConfig, err := lookupRepository(repoName)
+	if err != nil {
+		return invalidInput(err)
+	}
+	ghClient, resolved, err := newAPIHosts(nil, waitToken).client(repoConfig)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := stopContext(cmd.Context())
+	defer cancel()
+	if waitTimeout > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeout(ctx, waitTimeout)
+		defer cancelTimeout()
+	}
+
+	grey.Fprintf(cmd.ErrOrStderr(), "⏳ Waiting for %s to publish a release newer than %s (checking every %s)\n", repoConfig.FullName(), after, waitPoll)
+	release, err := waitForRelease(ctx, cmd.ErrOrStderr(), ghClient, after, waitPlatforms, waitPoll)
+	if err != nil {
+		if stopErr := interrupted(cmd.Context()); stopErr != nil {
+			return stopErr
+		}
+		if errors.Is(err, context.DeadlineExceeded) {
+			return &exitError{code: exitTimeout, err: fmt.Errorf("no %s release newer than %s within %s", repoConfig.FullName(), after, waitTimeout)}
+		}
+		return withToken(err, resolved.Value)
+	}
+
+	green.Fprintf(cmd.ErrOrStderr(), "✅ %s v%s was published %s\n", repoConfig.FullName(), types.FormatVersion(release.Version), formatDate(release.PublishedAt))
+	fmt.Fprintln(cmd.OutOrStdout(), types.FormatVersion(release.Version))
+	if outputFile := os.Getenv("GITHUB_OUTPUT"); outputFile != "" {
+		return appendGitHubOutputs(outputFile, [][2]string{
+			{"version", types.FormatVersion(release.Version)},
+			{"published_at", release.PublishedAt.UTC().Format(time.RFC3339)},
+			{"url", release.URL},
+		})
+	}
+	return nil
+}
+
+// waitForRelease polls until the newest stable release is newer than after and,
+// when platforms are given, has an asset for each. An error on the first poll is
+// returned; later ones are reported to w and retried.
+func waitForRelease(ctx context.Context, w io.Writer, watcher releaseWatcher, after *semver.Version, platforms []string, poll time.Duration) (*types.Release, error) {
+	for first := true; ; first = false {
+		release, err := pollRelease(ctx, w, watcher, after, platforms)
+		if err != nil && (first || ctx.Err() != nil) {
+			return nil, err
+		}
+		if err != nil {
+			yellow.Fprintf(w, "⚠️  %v; retrying in %s\n", err, poll)
+		}
+		if release != nil {
+			return release, nil
+		}
+
+		timer := time.NewTimer(poll)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// pollRelease returns the newest stable release if it is newer than after and
+// has every platform's assets, or nil to keep waiting
+func pollRelease(ctx context.Context, w io.Writer, watcher releaseWatcher, after *semver.Version, platforms []string) (*types.Release, error) {
+	releases, err := watcher.GetRecentReleases(ctx, waitRecentReleases)
+	if err != nil {
+		return nil, err
+	}
+	newest := releaseset.Latest(releaseset.NewerThan(releaseset.FilterStable(releases), after))
+	if newest == nil || len(platforms) == 0 {
+		return newest, nil
+	}
+
+	names, err := watcher.GetReleaseAssets(ctx, newest.Version)
+	if err != nil {
+		return nil, err
+	}
+	if missing := checker.MissingAssets(names, platforms); len(missing) > 0 {
+		grey.Fprintf(w, "⏳ v%s is published; waiting for assets for %s\n", types.FormatVersion(newest.Version), strings.Join(missing, ", "))
+		return nil, nil
+	}
+	return newest, nil
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+	"github.com/spf13/pflag"
+)
+
+// fakeWatcher answers each poll from the next entry in polls, repeating the last
+type fakeWatcher struct {
+	polls  []watchPoll
+	n      int
+	assets map[string][]string
+}
+
+// watchPoll is one poll's release list, or its error
+type watchPoll struct {
+	versions []string
+	err      error
+}
+
+func (f *fakeWatcher) GetRecentReleases(ctx context.Context, count int) ([]types.Release, error) {
+	p := f.polls[min(f.n, len(f.polls)-1)]
+	f.n++
+	if p.err != nil {
+		return nil, p.err
+	}
+	var releases []types.Release
+	for _, v := range p.versions {
+		releases = append(releases, types.Release{Version: semver.MustParse(v), PublishedAt: time.Now()})
+	}
+	return releases, nil
+}
+
+func (f *fakeWatcher) GetReleaseAssets(ctx context.Context, version *semver.Version) ([]string, error) {
+	return f.assets[version.String()], nil
+}
+
+func TestWaitForRelease(t *testing.T) {
+	after := semver.MustParse("2.328.0")
+	errFetch := errors.New("failed to list releases: connection reset")
+
+	tests := []struct {
+		name      string
+		polls     []watchPoll
+		assets    map[string][]string
+		platforms []string
+		want      string
+		wantPolls int
+		wantErr   error
+	}{
+		{name: "already published", polls: []watchPoll{{versions: []string{"2.329.0", "2.328.0"}}}, want: "2.329.0", wantPolls: 1},
+		{
+			name:      "published later",
+			polls:     []watchPoll{{versions: []string{"2.328.0"}}, {versions: []string{"2.328.0"}}, {versions: []string{"2.330.0-rc.1", "2.328.0"}}, {versions: []string{"2.329.0", "2.328.0"}}},
+			want:      "2.329.0",
+			wantPolls: 4,
+		},
+		{
+			name:      "waits for assets",
+			polls:     []watchPoll{{versions: []string{"2.329.0"}}, {versions: []string{"2.329.0"}}},
+			assets:    map[string][]string{"2.329.0": {"actions-runner-linux-x64-2.329.0.tar.gz"}},
+			platforms: []string{"linux-x64", "win-x64"},
+			wantErr:   context.DeadlineExceeded,
+		},
+		{
+			name:      "assets published",
+			polls:     []watchPoll{{versions: []string{"2.329.0"}}},
+			assets:    map[string][]string{"2.329.0": {"actions-runner-linux-x64-2.329.0.tar.gz", "actions-runner-win-x64-2.329.0.zip"}},
+			platforms: []string{"linux-x64", "win-x64"},
+			want:      "2.329.0",
+			wantPolls: 1,
+		},
+		{name: "first poll fails", polls: []watchPoll{{err: errFetch}}, wantErr: errFetch},
+		{name: "later failures retried", polls: []watchPoll{{versions: []string{"2.328.0"}}, {err: errFetch}, {versions: []string{"2.329.0"}}}, want: "2.329.0", wantPolls: 3},
+		{name: "times out", polls: []watchPoll{{versions: []string{"2.328.0", "2.327.1"}}}, wantErr: context.DeadlineExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+			watcher := &fakeWatcher{polls: tt.polls, assets: tt.assets}
+
+			var buf bytes.Buffer
+			release, err := waitForRelease(ctx, &buf, watcher, after, tt.platforms, time.Millisecond)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("waitForRelease() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(tt.platforms) > 0 && tt.wantErr != nil && !strings.Contains(buf.String(), "waiting for assets for win-x64") {
+				t.Errorf("output = %q, want the missing assets reported", buf.String())
+			}
+			if err != nil {
+				return
+			}
+			if got := release.Version.String(); got != tt.want {
+				t.Errorf("waitForRelease() = %s, want %s", got, tt.want)
+			}
+			if watcher.n != tt.wantPolls {
+				t.Errorf("polled %d times, want %d", watcher.n, tt.wantPolls)
+			}
+		})
+	}
+}
+
+// TestExecute_WaitGitHubOutput tests that wait writes a four-component version
+// to $GITHUB_OUTPUT the way the project does, not in its semver form
+func TestExecute_WaitGitHubOutput(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `[{"tag_name": "v10.0.19041.4", "published_at": "2025-10-14T00:00:00Z", "html_url": "https://example.com/v10.0.19041.4"}]`)
+	}))
+	defer server.Close()
+	outputFile := filepath.Join(t.TempDir(), "github_output")
+	t.Setenv("GITHUB_OUTPUT", outputFile)
+	t.Cleanup(func() {
+		waitRepo, waitAfter = "", ""
+		waitCmd.Flags().VisitAll(func(f *pflag.Flag) { f.Changed = false })
+	})
+
+	out, err := executeRoot(t, "wait", "--base-url", server.URL+"/", "--repo", "owner/repo", "--until-newer-than", "10.0.19041.1", "--timeout", "10s", "--poll", "10s")
+	if err != nil {
+		t.Fatalf("wait error = %v\n%s", err, out)
+	}
+	data, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("failed to read output file: %v", err)
+	}
+	if !strings.Contains(string(data), "version=10.0.19041.4\n") {
+		t.Errorf("outputs = %q, want version=10.0.19041.4", data)
+	}
+}
@@ -0,0 +1,243 @@
+package cache
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// BundleFormat is the layout version of bundles written by WriteBundle;
+// ReadBundle rejects bundles from newer versions
+const BundleFormat = 1
+
+// Paths within a bundle
+const (
+	bundleRegistryPath = "registry.json"
+	bundleSchemaPath   = "schema/cache.schema.json"
+	bundleReleasesDir  = "releases"
+)
+
+// maxBundleEntry bounds each file read from a bundle
+const maxBundleEntry = 64 << 20
+
+//go:embed cache.schema.json
+var cacheSchema []byte
+
+// Schema returns the JSON Schema of cache files
+func Schema() []byte {
+	return cacheSchema
+}
+
+// BundleRegistry indexes the release lists in a bundle
+type BundleRegistry struct {
+	Format       int           `json:"format"`
+	CreatedAt    time.Time     `json:"created_at"`
+	ToolVersion  string        `json:"tool_version,omitempty"`
+	Repositories []BundleEntry `json:"repositories"`
+}
+
+// BundleEntry is one repository's release list in a bundle
+type BundleEntry struct {
+	Repository  string    `json:"repository"` // As warmed, e.g. "actions/runner" or "github.example.com/tools/node"
+	Path        string    `json:"path"`       // Within the bundle, e.g. "releases/actions/runner.json"
+	Releases    int       `json:"releases"`
+	GeneratedAt time.Time `json:"generated_at"`
+	Signed      bool      `json:"signed,omitempty"` // A minisign signature is alongside
+}
+
+// WriteBundle writes every release list warmed under dir (see WarmPath), with
+// any signatures, to w as a gzipped tarball with a registry indexing them and
+// the cache file schema, for import on machines without network access
+func WriteBundle(w io.Writer, dir, toolVersion string, createdAt time.Time) (*BundleRegistry, error) {
+	registry := &BundleRegistry{Format: BundleFormat, CreatedAt: createdAt.UTC(), ToolVersion: toolVersion}
+	files := map[string][]byte{}
+
+	root := filepath.Join(dir, bundleReleasesDir)
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(p, ".json") {
+			return err
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return fmt.Errorf("failed to read cache %s: %w", p, err)
+		}
+		cacheData, releases, err := parseCacheData(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", p, err)
+		}
+
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		entry := BundleEntry{
+			Path:        filepath.ToSlash(rel),
+			Releases:    len(releases),
+			GeneratedAt: cacheData.GeneratedAt,
+		}
+		entry.Repository = strings.TrimSuffix(strings.TrimPrefix(entry.Path, bundleReleasesDir+"/"), ".json")
+		files[entry.Path] = data
+		if signature, err := os.ReadFile(SignaturePath(p)); err == nil {
+			files[SignaturePath(entry.Path)] = signature
+			entry.Signed = true
+		}
+		registry.Repositories = append(registry.Repositories, entry)
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) || err == nil && len(registry.Repositories) == 0 {
+		return nil, fmt.Errorf("no release lists under %s to bundle", root)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := json.MarshalIndent(registry, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	write := func(name string, data []byte) error {
+		header := &tar.Header{Name: name, Mode: 0o644, Size: int64(len(data)), ModTime: registry.CreatedAt, Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+
+	// The registry comes first so readers can reject an unsupported format early
+	if err := write(bundleRegistryPath, append(index, '\n')); err != nil {
+		return nil, fmt.Errorf("failed to write bundle: %w", err)
+	}
+	if err := write(bundleSchemaPath, cacheSchema); err != nil {
+		return nil, fmt.Errorf("failed to write bundle: %w", err)
+	}
+	for _, entry := range registry.Repositories {
+		if err := write(entry.Path, files[entry.Path]); err != nil {
+			return nil, fmt.Errorf("failed to write bundle: %w", err)
+		}
+		if entry.Signed {
+			if err := write(SignaturePath(entry.Path), files[SignaturePath(entry.Path)]); err != nil {
+				return nil, fmt.Errorf("failed to write bundle: %w", err)
+			}
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write bundle: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write bundle: %w", err)
+	}
+	return registry, nil
+}
+
+// ReadBundle imports a bundle written by WriteBundle into dir, where checks
+// look for warmed releases. Every release list is validated, and its signature
+// checked if key is set, before any is written, so a bad bundle changes nothing.
+func ReadBundle(r io.Reader, dir string, key *PublicKey) (*BundleRegistry, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bundle: %w", err)
+	}
+	tr := tar.NewReader(gz)
+
+	files := map[string][]byte{}
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid bundle: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, err := io.ReadAll(io.LimitReader(tr, maxBundleEntry+1))
+		if err != nil {
+			return nil, fmt.Errorf("invalid bundle: %w", err)
+		}
+		if len(data) > maxBundleEntry {
+			return nil, fmt.Errorf("invalid bundle: %s is too large", header.Name)
+		}
+		files[header.Name] = data
+	}
+
+	data, ok := files[bundleRegistryPath]
+	if !ok {
+		return nil, fmt.Errorf("invalid bundle: no %s", bundleRegistryPath)
+	}
+	var registry BundleRegistry
+	if err := json.Unmarshal(data, &registry); err != nil {
+		return nil, fmt.Errorf("invalid bundle: %s: %w", bundleRegistryPath, err)
+	}
+	if registry.Format < 1 || registry.Format > BundleFormat {
+		return nil, fmt.Errorf("unsupported bundle format %d: this version reads format %d", registry.Format, BundleFormat)
+	}
+
+	// Check everything before writing anything
+	for _, entry := range registry.Repositories {
+		if err := checkBundleEntry(entry, files, key); err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.Repository, err)
+		}
+	}
+
+	for _, entry := range registry.Repositories {
+		target := filepath.Join(dir, filepath.FromSlash(entry.Path))
+		if err := writeAtomic(target, files[entry.Path]); err != nil {
+			return nil, err
+		}
+		// A signature left from an earlier list would no longer match
+		signature := SignaturePath(target)
+		if entry.Signed {
+			err = writeAtomic(signature, files[SignaturePath(entry.Path)])
+		} else if err = os.Remove(signature); errors.Is(err, fs.ErrNotExist) {
+			err = nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &registry, nil
+}
+
+// checkBundleEntry checks an entry's path stays within the release lists, and
+// that its list is valid and, if key is set, signed by it
+func checkBundleEntry(entry BundleEntry, files map[string][]byte, key *PublicKey) error {
+	clean := path.Clean(entry.Path)
+	if clean != entry.Path || strings.Contains(clean, `\`) || !strings.HasPrefix(clean, bundleReleasesDir+"/") || !strings.HasSuffix(clean, ".json") {
+		return fmt.Errorf("invalid path %q in bundle", entry.Path)
+	}
+	data, ok := files[entry.Path]
+	if !ok {
+		return fmt.Errorf("%s missing from bundle", entry.Path)
+	}
+	result, err := Validate(data)
+	if err != nil {
+		return err
+	}
+	if !result.Valid() {
+		return fmt.Errorf("invalid release list: %s", result.Issues[0])
+	}
+
+	if key == nil {
+		return nil
+	}
+	signature, ok := files[SignaturePath(entry.Path)]
+	if !ok {
+		return fmt.Errorf("not signed")
+	}
+	return Verify(data, signature, key)
+}
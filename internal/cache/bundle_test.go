@@ -0,0 +1,179 @@
+package cache
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+// writeBundleFiles writes a gzipped tarball of files, for hand-made bundles
+func writeBundleFiles(t *testing.T, files map[string]string) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return &buf
+}
+
+func TestBundleRoundTrip(t *testing.T) {
+	secret, _ := secretKeyFile(t, 1, false)
+	key, err := ParseSecretKey(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	source := t.TempDir()
+	releases := []types.Release{
+		{Version: semver.MustParse("2.329.0"), PublishedAt: time.Date(2025, 10, 14, 0, 0, 0, 0, time.UTC), URL: "https://example.com/v2.329.0"},
+	}
+	runner := WarmPath(source, "actions/runner")
+	if err := WriteFile(runner, "actions/runner", releases, nil, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if err := SignFile(runner, key, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFile(WarmPath(source, "github.example.com/tools/node"), "github.example.com/tools/node", releases, nil, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	created := time.Date(2025, 10, 20, 9, 0, 0, 0, time.UTC)
+	registry, err := WriteBundle(&buf, source, "1.2.3", created)
+	if err != nil {
+		t.Fatalf("WriteBundle() error = %v", err)
+	}
+	if len(registry.Repositories) != 2 || registry.Repositories[0].Repository != "actions/runner" || !registry.Repositories[0].Signed ||
+		registry.Repositories[1].Repository != "github.example.com/tools/node" || registry.Repositories[1].Releases != 1 {
+		t.Fatalf("registry = %+v", registry.Repositories)
+	}
+	bundle := buf.Bytes()
+
+	// Signed lists import when verified; the unsigned one cannot be
+	if _, err := ReadBundle(bytes.NewReader(bundle), t.TempDir(), key.Public()); err == nil || !strings.Contains(err.Error(), "not signed") {
+		t.Errorf("ReadBundle() with a key error = %v, want the unsigned list rejected", err)
+	}
+
+	target := t.TempDir()
+	// A stale signature for a list imported unsigned must not be left behind
+	stale := SignaturePath(WarmPath(target, "github.example.com/tools/node"))
+	if err := os.MkdirAll(filepath.Dir(stale), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(stale, []byte("stale"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	imported, err := ReadBundle(bytes.NewReader(bundle), target, nil)
+	if err != nil {
+		t.Fatalf("ReadBundle() error = %v", err)
+	}
+	if imported.Format != BundleFormat || !imported.CreatedAt.Equal(created) || imported.ToolVersion != "1.2.3" {
+		t.Errorf("imported registry = %+v", imported)
+	}
+	if _, err := LoadVerifiedFile(WarmPath(target, "actions/runner"), "actions/runner", key.Public()); err != nil {
+		t.Errorf("imported runner list does not verify: %v", err)
+	}
+	if got, err := LoadFile(WarmPath(target, "github.example.com/tools/node")); err != nil || len(got) != 1 {
+		t.Errorf("imported node list = %v, %v", got, err)
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Errorf("stale signature left behind: %v", err)
+	}
+}
+
+func TestWriteBundle_Empty(t *testing.T) {
+	if _, err := WriteBundle(&bytes.Buffer{}, t.TempDir(), "dev", time.Now()); err == nil || !strings.Contains(err.Error(), "no release lists") {
+		t.Errorf("WriteBundle() error = %v, want no release lists", err)
+	}
+}
+
+func TestReadBundle_Invalid(t *testing.T) {
+	list := `{"generated_at": "2025-10-20T00:00:00Z", "releases": [{"version": "2.329.0", "published_at": "2025-10-14T00:00:00Z", "url": "https://example.com"}]}`
+	registry := func(format int, path string) string {
+		data, _ := json.Marshal(BundleRegistry{Format: format, Repositories: []BundleEntry{{Repository: "actions/runner", Path: path}}})
+		return string(data)
+	}
+
+	tests := []struct {
+		name    string
+		files   map[string]string
+		wantErr string
+	}{
+		{name: "no registry", files: map[string]string{"releases/actions/runner.json": list}, wantErr: "no registry.json"},
+		{name: "newer format", files: map[string]string{"registry.json": registry(BundleFormat+1, "releases/actions/runner.json")}, wantErr: "unsupported bundle format"},
+		{name: "path outside releases", files: map[string]string{"registry.json": registry(1, "releases/../../evil.json"), "releases/../../evil.json": list}, wantErr: "invalid path"},
+		{name: "missing list", files: map[string]string{"registry.json": registry(1, "releases/actions/runner.json")}, wantErr: "missing from bundle"},
+		{name: "invalid list", files: map[string]string{"registry.json": registry(1, "releases/actions/runner.json"), "releases/actions/runner.json": `{"releases": []}`}, wantErr: "invalid release list"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			_, err := ReadBundle(writeBundleFiles(t, tt.files), dir, nil)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("ReadBundle() error = %v, want containing %q", err, tt.wantErr)
+			}
+			if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+				t.Errorf("a rejected bundle wrote %d entries", len(entries))
+			}
+		})
+	}
+
+	if _, err := ReadBundle(strings.NewReader("not a bundle"), t.TempDir(), nil); err == nil {
+		t.Error("ReadBundle() of a non-gzip stream succeeded")
+	}
+}
+
+func TestSchema_MatchesValidator(t *testing.T) {
+	var schema struct {
+		Properties map[string]json.RawMessage `json:"properties"`
+	}
+	if err := json.Unmarshal(Schema(), &schema); err != nil {
+		t.Fatalf("schema is not valid JSON: %v", err)
+	}
+	var releases struct {
+		Items struct {
+			Properties map[string]json.RawMessage `json:"properties"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(schema.Properties["releases"], &releases); err != nil {
+		t.Fatal(err)
+	}
+
+	for fields, known := range map[*map[string]json.RawMessage]map[string]bool{
+		&schema.Properties:         knownCacheFields,
+		&releases.Items.Properties: knownReleaseFields,
+	} {
+		if len(*fields) != len(known) {
+			t.Errorf("schema lists %d fields, validator knows %d", len(*fields), len(known))
+		}
+		for name := range *fields {
+			if !known[name] {
+				t.Errorf("schema field %q is unknown to the validator", name)
+			}
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package cache
+
+import (
+	"path/filepath"
+
+	"github.com/nickromney-org/github-release-version-checker/internal/config"
+	"github.com/nickromney-org/github-release-version-checker/internal/data"
+	"github.com/nickromney-org/github-release-version-checker/pkg/releaseset"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+// EmbeddedFile is a release cache built into the binary
+type EmbeddedFile struct {
+	Repository string // owner/repo
+	Path       string // Source file, relative to the module root
+
+	load func() ([]types.Release, error)
+}
+
+// Load returns the releases built into the binary from the file
+func (f EmbeddedFile) Load() ([]types.Release, error) {
+	return f.load()
+}
+
+// EmbeddedFiles lists the release caches built into the binary: the runner
+// releases the checker starts from, and each predefined repository's cache
+func EmbeddedFiles() []EmbeddedFile {
+	files := []EmbeddedFile{{
+		Repository: "actions/runner",
+		Path:       filepath.Join("internal", "data", "releases.json"),
+		load:       loadRunnerReleases,
+	}}
+	for _, repoConfig := range config.PredefinedConfigs() {
+		if !repoConfig.CacheEnabled {
+			continue
+		}
+		repoConfig := repoConfig
+		files = append(files, EmbeddedFile{
+			Repository: repoConfig.FullName(),
+			Path:       filepath.Join("internal", "cache", filepath.FromSlash(repoConfig.CachePath)),
+			load:       func() ([]types.Release, error) { return NewManager("").LoadCache(&repoConfig) },
+		})
+	}
+	return files
+}
+
+func loadRunnerReleases() ([]types.Release, error) {
+	embedded, err := data.LoadEmbeddedReleases()
+	if err != nil {
+		return nil, err
+	}
+	releases := make([]types.Release, len(embedded))
+	for i, r := range embedded {
+		releases[i] = types.Release{Version: r.Version, PublishedAt: r.PublishedAt, URL: r.URL, Prerelease: r.Prerelease}
+	}
+	return releases, nil
+}
+
+// freshWithin is how near the top of the recent releases the newest cached
+// one must be for checks to use the cache, as in the checker
+const freshWithin = 5
+
+// Freshness compares a cache with the most recent releases from the API
+type Freshness struct {
+	CachedLatest   *types.Release // Newest cached release
+	Latest         *types.Release // Newest release from the API
+	ReleasesBehind int            // Recent releases newer than CachedLatest
+	Current        bool           // Whether checks can use the cache without fetching every release
+}
+
+// CheckFreshness compares cached releases with recent ones, newest first as
+// the API lists them. ReleasesBehind counts at most len(recent).
+func CheckFreshness(cached, recent []types.Release) Freshness {
+	freshness := Freshness{CachedLatest: releaseset.Latest(cached), Latest: releaseset.Latest(recent)}
+	if freshness.CachedLatest == nil {
+		freshness.ReleasesBehind = len(recent)
+		return freshness
+	}
+	freshness.ReleasesBehind = len(releaseset.NewerThan(recent, freshness.CachedLatest.Version))
+	for i, r := range recent {
+		if i < freshWithin && types.CompareVersions(r.Version, freshness.CachedLatest.Version) == 0 {
+			freshness.Current = true
+		}
+	}
+	return freshness
+}
@@ -0,0 +1,56 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+func TestEmbeddedFiles(t *testing.T) {
+	for _, f := range EmbeddedFiles() {
+		if _, err := os.Stat(filepath.Join("..", "..", f.Path)); err != nil {
+			t.Errorf("embedded cache for %s: %v", f.Repository, err)
+		}
+		if releases, err := f.Load(); err != nil || len(releases) == 0 {
+			t.Errorf("Load() for %s = %d releases, %v", f.Repository, len(releases), err)
+		}
+	}
+}
+
+func TestCheckFreshness(t *testing.T) {
+	release := func(version string) types.Release {
+		return types.Release{Version: semver.MustParse(version), PublishedAt: time.Now()}
+	}
+	cached := []types.Release{release("2.328.0"), release("2.327.0")}
+
+	tests := []struct {
+		name        string
+		recent      []types.Release
+		wantBehind  int
+		wantCurrent bool
+	}{
+		{"up to date", []types.Release{release("2.328.0"), release("2.327.0")}, 0, true},
+		{"one behind", []types.Release{release("2.329.0"), release("2.328.0")}, 1, true},
+		{"five behind", []types.Release{release("2.333.0"), release("2.332.0"), release("2.331.0"), release("2.330.0"), release("2.329.0"), release("2.328.0")}, 5, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CheckFreshness(cached, tt.recent)
+			if got.ReleasesBehind != tt.wantBehind || got.Current != tt.wantCurrent {
+				t.Errorf("CheckFreshness() = %d behind, current %v; want %d, %v", got.ReleasesBehind, got.Current, tt.wantBehind, tt.wantCurrent)
+			}
+			if got.CachedLatest.Version.String() != "2.328.0" || got.Latest.Version.String() != tt.recent[0].Version.String() {
+				t.Errorf("CheckFreshness() latest = %s and %s", got.CachedLatest.Version, got.Latest.Version)
+			}
+		})
+	}
+
+	if got := CheckFreshness(nil, cached); got.Current || got.ReleasesBehind != 2 {
+		t.Errorf("CheckFreshness() of an empty cache = %+v", got)
+	}
+}
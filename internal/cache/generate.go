@@ -0,0 +1,52 @@
+package cache
+
+import (
+	"time"
+
+	"github.com/nickromney-org/github-release-version-checker/pkg/releaseset"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+// GenerateFile writes releases to the cache file at path in a stable order,
+// most recently published first. A file that already lists exactly these
+// releases is left alone, so regenerated caches only change when releases do.
+// It reports whether the file was written.
+func GenerateFile(path, repository string, releases []types.Release, fetch *FetchMetadata, generatedAt time.Time) (bool, error) {
+	releases = sortedForCache(releases)
+	if existing, existingFetch, err := ReadFile(path, repository, nil); err == nil && sameReleases(sortedForCache(existing), releases) && sameFetch(existingFetch, fetch) {
+		return false, nil
+	}
+	if err := WriteFile(path, repository, releases, fetch, generatedAt.Truncate(time.Second)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// sortedForCache returns a copy of releases by date, then version, newest first
+func sortedForCache(releases []types.Release) []types.Release {
+	sorted := append([]types.Release(nil), releases...)
+	releaseset.SortByVersion(sorted)
+	releaseset.SortByDate(sorted)
+	return sorted
+}
+
+// sameReleases reports whether a and b list the same releases in the same order
+func sameReleases(a, b []types.Release) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Version.String() != b[i].Version.String() || !a[i].PublishedAt.Equal(b[i].PublishedAt) || a[i].URL != b[i].URL || a[i].Prerelease != b[i].Prerelease {
+			return false
+		}
+	}
+	return true
+}
+
+// sameFetch reports whether a and b record the same fetch
+func sameFetch(a, b *FetchMetadata) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,46 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+func TestGenerateFile(t *testing.T) {
+	published := time.Date(2025, 10, 14, 12, 0, 0, 0, time.UTC)
+	releases := []types.Release{
+		{Version: semver.MustParse("2.327.1"), PublishedAt: published.AddDate(0, -3, 0), URL: "https://example.com/v2.327.1"},
+		{Version: semver.MustParse("2.329.0"), PublishedAt: published, URL: "https://example.com/v2.329.0"},
+		{Version: semver.MustParse("2.328.0"), PublishedAt: published.AddDate(0, -2, 0), URL: "https://example.com/v2.328.0"},
+	}
+	path := filepath.Join(t.TempDir(), "releases.json")
+
+	changed, err := GenerateFile(path, "actions/runner", releases, nil, published.Add(1500*time.Millisecond))
+	if err != nil || !changed {
+		t.Fatalf("GenerateFile() = %v, %v, want a new file", changed, err)
+	}
+	first, _ := os.ReadFile(path)
+	loaded, err := LoadFile(path)
+	if err != nil || loaded[0].Version.String() != "2.329.0" || loaded[2].Version.String() != "2.327.1" {
+		t.Fatalf("LoadFile() = %+v, %v, want newest first", loaded, err)
+	}
+
+	// The same releases in another order leave the file, and its timestamp, alone
+	reordered := []types.Release{releases[1], releases[2], releases[0]}
+	changed, err = GenerateFile(path, "actions/runner", reordered, nil, published.AddDate(0, 0, 1))
+	if err != nil || changed {
+		t.Errorf("GenerateFile() of unchanged releases = %v, %v, want no change", changed, err)
+	}
+	if second, _ := os.ReadFile(path); string(second) != string(first) {
+		t.Errorf("unchanged releases rewrote the file:\n%s", second)
+	}
+
+	newer := append(reordered, types.Release{Version: semver.MustParse("2.330.0"), PublishedAt: published.AddDate(0, 0, 7), URL: "https://example.com/v2.330.0"})
+	if changed, err := GenerateFile(path, "actions/runner", newer, nil, published.AddDate(0, 0, 7)); err != nil || !changed {
+		t.Errorf("GenerateFile() with a new release = %v, %v, want a change", changed, err)
+	}
+}
@@ -5,11 +5,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
-	"github.com/Masterminds/semver/v3"
 	"github.com/nickromney-org/github-release-version-checker/internal/config"
-	"github.com/nickromney-org/github-release-version-checker/internal/version"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
 )
 
 //go:embed data/*.json
@@ -17,29 +18,34 @@ var embeddedCaches embed.FS
 
 // CacheData represents the structure of a cache file
 type CacheData struct {
-	GeneratedAt time.Time     `json:"generated_at"`
-	Repository  string        `json:"repository,omitempty"`
-	Releases    []jsonRelease `json:"releases"`
+	GeneratedAt time.Time      `json:"generated_at"`
+	Repository  string         `json:"repository,omitempty"`
+	Fetch       *FetchMetadata `json:"fetch,omitempty"`
+	Releases    []jsonRelease  `json:"releases"`
 }
 
 // jsonRelease is the JSON representation of a release
 type jsonRelease struct {
-	Version     string    `json:"version"`
-	PublishedAt time.Time `json:"published_at"`
-	URL         string    `json:"url"`
+	Version      string    `json:"version"`
+	PublishedAt  time.Time `json:"published_at"`
+	URL          string    `json:"url"`
+	IsPrerelease bool      `json:"is_prerelease,omitempty"`
+	Platforms    []string  `json:"platforms,omitempty"` // Platforms with assets, e.g. linux/arm64
 }
 
-// toVersionRelease converts jsonRelease to version.Release
-func (jr *jsonRelease) toVersionRelease() (version.Release, error) {
-	ver, err := semver.NewVersion(jr.Version)
+// toRelease converts jsonRelease to types.Release
+func (jr *jsonRelease) toRelease() (types.Release, error) {
+	ver, err := types.ParseVersion(jr.Version)
 	if err != nil {
-		return version.Release{}, fmt.Errorf("invalid version %q: %w", jr.Version, err)
+		return types.Release{}, fmt.Errorf("invalid version %q: %w", jr.Version, err)
 	}
 
-	return version.Release{
+	return types.Release{
 		Version:     ver,
 		PublishedAt: jr.PublishedAt,
 		URL:         jr.URL,
+		Prerelease:  jr.IsPrerelease,
+		Platforms:   jr.Platforms,
 	}, nil
 }
 
@@ -54,7 +60,7 @@ func NewManager(customPath string) *Manager {
 }
 
 // LoadCache loads releases for a repository
-func (m *Manager) LoadCache(repoConfig *config.RepositoryConfig) ([]version.Release, error) {
+func (m *Manager) LoadCache(repoConfig *config.RepositoryConfig) ([]types.Release, error) {
 	// Priority: custom cache > embedded cache > no cache
 
 	if m.customCachePath != "" {
@@ -68,7 +74,66 @@ func (m *Manager) LoadCache(repoConfig *config.RepositoryConfig) ([]version.Rele
 	return nil, nil // No cache available
 }
 
-func (m *Manager) loadEmbeddedCache(path string) ([]version.Release, error) {
+// LoadFile loads releases from a cache file on disk
+func LoadFile(path string) ([]types.Release, error) {
+	return NewManager(path).loadCustomCache(path)
+}
+
+// WarmPath returns where warmed releases for repository (owner/repo) are kept under dir
+func WarmPath(dir, repository string) string {
+	return filepath.Join(dir, "releases", strings.ToLower(repository)+".json")
+}
+
+// WriteFile writes releases to a cache file with how they were fetched (if
+// known), creating its directory and replacing any existing file atomically
+func WriteFile(path, repository string, releases []types.Release, fetch *FetchMetadata, generatedAt time.Time) error {
+	data, err := encodeCache(repository, releases, fetch, generatedAt)
+	if err != nil {
+		return err
+	}
+	return writeAtomic(path, data)
+}
+
+// writeAtomic writes data to path through a temporary file, creating its
+// directory, so readers never see a partly written file
+func writeAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write cache %s: %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write cache %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write cache %s: %w", path, err)
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// encodeCache returns the contents of a cache file listing releases
+func encodeCache(repository string, releases []types.Release, fetch *FetchMetadata, generatedAt time.Time) ([]byte, error) {
+	cacheData := CacheData{
+		GeneratedAt: generatedAt.UTC(),
+		Repository:  repository,
+		Fetch:       fetch,
+		Releases:    make([]jsonRelease, len(releases)),
+	}
+	for i, r := range releases {
+		cacheData.Releases[i] = jsonRelease{Version: r.Version.String(), PublishedAt: r.PublishedAt, URL: r.URL, IsPrerelease: r.Prerelease, Platforms: r.Platforms}
+	}
+	data, err := json.MarshalIndent(cacheData, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+func (m *Manager) loadEmbeddedCache(path string) ([]types.Release, error) {
 	data, err := embeddedCaches.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read embedded cache %s: %w", path, err)
@@ -77,7 +142,7 @@ func (m *Manager) loadEmbeddedCache(path string) ([]version.Release, error) {
 	return m.parseCache(data)
 }
 
-func (m *Manager) loadCustomCache(path string) ([]version.Release, error) {
+func (m *Manager) loadCustomCache(path string) ([]types.Release, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read custom cache %s: %w", path, err)
@@ -86,16 +151,22 @@ func (m *Manager) loadCustomCache(path string) ([]version.Release, error) {
 	return m.parseCache(data)
 }
 
-func (m *Manager) parseCache(data []byte) ([]version.Release, error) {
+func (m *Manager) parseCache(data []byte) ([]types.Release, error) {
+	_, releases, err := parseCacheData(data)
+	return releases, err
+}
+
+// parseCacheData parses a cache file, skipping invalid releases
+func parseCacheData(data []byte) (*CacheData, []types.Release, error) {
 	var cacheData CacheData
 	if err := json.Unmarshal(data, &cacheData); err != nil {
-		return nil, fmt.Errorf("failed to parse cache: %w", err)
+		return nil, nil, fmt.Errorf("failed to parse cache: %w", err)
 	}
 
-	// Convert jsonRelease to version.Release
-	releases := make([]version.Release, 0, len(cacheData.Releases))
+	// Convert jsonRelease to types.Release
+	releases := make([]types.Release, 0, len(cacheData.Releases))
 	for _, jr := range cacheData.Releases {
-		rel, err := jr.toVersionRelease()
+		rel, err := jr.toRelease()
 		if err != nil {
 			// Skip invalid releases but continue processing
 			continue
@@ -103,5 +174,5 @@ func (m *Manager) parseCache(data []byte) ([]version.Release, error) {
 		releases = append(releases, rel)
 	}
 
-	return releases, nil
+	return &cacheData, releases, nil
 }
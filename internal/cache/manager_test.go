@@ -2,10 +2,14 @@ package cache
 
 import (
 	"os"
+	"path/filepath"
+	"slices"
 	"testing"
 	"time"
 
+	"github.com/Masterminds/semver/v3"
 	"github.com/nickromney-org/github-release-version-checker/internal/config"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
 )
 
 func TestManager_LoadCache_Embedded(t *testing.T) {
@@ -258,3 +262,31 @@ func TestNewManager(t *testing.T) {
 		})
 	}
 }
+
+func TestWriteFile_RoundTrip(t *testing.T) {
+	published := time.Date(2025, 10, 14, 12, 0, 0, 0, time.UTC)
+	releases := []types.Release{
+		{Version: semver.MustParse("2.329.0"), PublishedAt: published, URL: "https://github.com/actions/runner/releases/tag/v2.329.0", Prerelease: true,
+			Platforms: []string{"linux/amd64", "linux/arm64"}},
+		{Version: semver.MustParse("2.328.0"), PublishedAt: published.AddDate(0, -2, 0), URL: "https://github.com/actions/runner/releases/tag/v2.328.0"},
+	}
+	path := WarmPath(t.TempDir(), "Actions/Runner")
+	if filepath.Base(filepath.Dir(path)) != "actions" || filepath.Base(path) != "runner.json" {
+		t.Errorf("WarmPath() = %s", path)
+	}
+
+	if err := WriteFile(path, "actions/runner", releases, nil, published); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	loaded, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	if len(loaded) != 2 || loaded[0].Version.String() != "2.329.0" || !loaded[1].PublishedAt.Equal(releases[1].PublishedAt) ||
+		!loaded[0].Prerelease || loaded[1].Prerelease || !slices.Equal(loaded[0].Platforms, releases[0].Platforms) || loaded[1].Platforms != nil {
+		t.Errorf("LoadFile() = %+v", loaded)
+	}
+	if result, err := ValidateFile(path); err != nil || !result.Valid() {
+		t.Errorf("ValidateFile() = %+v, %v", result, err)
+	}
+}
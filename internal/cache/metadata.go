@@ -0,0 +1,65 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+// FetchMetadata records how a cache file's releases were fetched, so a check
+// whose fetch would differ does not use them
+type FetchMetadata struct {
+	APIHost     string `json:"api_host"`             // e.g. api.github.com, or a GitHub Enterprise Server
+	Prereleases bool   `json:"prereleases"`          // Whether prereleases were kept
+	TagFilter   string `json:"tag_filter,omitempty"` // Tags kept, as a regular expression; empty for all
+	Pages       int    `json:"pages"`                // Release pages fetched
+	Truncated   bool   `json:"truncated,omitempty"`  // Stopped at the page limit with releases left
+}
+
+// Mismatch describes how releases fetched as m differ from those a check
+// fetching as want would see, or returns "" if they can serve it. Hosts are
+// only compared when both are known. Kept prereleases are marked, so such a
+// cache can still serve a check that excludes them.
+func (m *FetchMetadata) Mismatch(want FetchMetadata) string {
+	var problems []string
+	if m.APIHost != "" && want.APIHost != "" && !strings.EqualFold(m.APIHost, want.APIHost) {
+		problems = append(problems, fmt.Sprintf("fetched from %s, not %s", m.APIHost, want.APIHost))
+	}
+	if want.Prereleases && !m.Prereleases {
+		problems = append(problems, "prereleases excluded")
+	}
+	if m.TagFilter != want.TagFilter {
+		problems = append(problems, fmt.Sprintf("tag filter %q, not %q", m.TagFilter, want.TagFilter))
+	}
+	return strings.Join(problems, "; ")
+}
+
+// ReadFile loads releases and fetch metadata from repository's cache file on
+// disk, checking its signature first if key is set. A file listing another
+// repository's releases is rejected, as is, when signed, one naming none, so a
+// signed list copied to another repository's path is not used. An empty
+// repository skips the check. Metadata is nil for files written before it
+// was recorded.
+func ReadFile(path, repository string, key *PublicKey) ([]types.Release, *FetchMetadata, error) {
+	var data []byte
+	var err error
+	if key != nil {
+		data, err = readVerified(path, key)
+	} else if data, err = os.ReadFile(path); err != nil {
+		err = fmt.Errorf("failed to read cache %s: %w", path, err)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cacheData, releases, err := parseCacheData(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	if repository != "" && !strings.EqualFold(cacheData.Repository, repository) && (key != nil || cacheData.Repository != "") {
+		return nil, nil, fmt.Errorf("%s: lists releases of %q, not %s", path, cacheData.Repository, repository)
+	}
+	return releases, cacheData.Fetch, nil
+}
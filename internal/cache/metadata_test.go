@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+func TestFetchMetadata_Mismatch(t *testing.T) {
+	fetched := FetchMetadata{APIHost: "api.github.com", Pages: 3}
+
+	tests := []struct {
+		name string
+		want FetchMetadata
+		same bool
+	}{
+		{"same parameters", FetchMetadata{APIHost: "api.github.com"}, true},
+		{"host differs in case", FetchMetadata{APIHost: "API.GitHub.com"}, true},
+		{"unknown host", FetchMetadata{}, true},
+		{"other host", FetchMetadata{APIHost: "ghe.example.com"}, false},
+		{"prereleases wanted", FetchMetadata{APIHost: "api.github.com", Prereleases: true}, false},
+		{"tag filter wanted", FetchMetadata{APIHost: "api.github.com", TagFilter: `^v2\.`}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fetched.Mismatch(tt.want); (got == "") != tt.same {
+				t.Errorf("Mismatch(%+v) = %q, want same = %v", tt.want, got, tt.same)
+			}
+		})
+	}
+
+	withPrereleases := FetchMetadata{APIHost: "api.github.com", Prereleases: true}
+	if got := withPrereleases.Mismatch(FetchMetadata{APIHost: "api.github.com"}); got != "" {
+		t.Errorf("Mismatch() for a stable check = %q, want marked prereleases to serve it", got)
+	}
+}
+
+func TestReadFile_Metadata(t *testing.T) {
+	releases := []types.Release{
+		{Version: semver.MustParse("2.329.0"), PublishedAt: time.Date(2025, 10, 14, 0, 0, 0, 0, time.UTC), URL: "https://example.com/v2.329.0"},
+	}
+	dir := t.TempDir()
+	fetch := &FetchMetadata{APIHost: "ghe.example.com", Pages: 10, Truncated: true}
+
+	withMetadata := filepath.Join(dir, "with.json")
+	if err := WriteFile(withMetadata, "actions/runner", releases, fetch, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	got, gotFetch, err := ReadFile(withMetadata, "", nil)
+	if err != nil || len(got) != 1 || gotFetch == nil || *gotFetch != *fetch {
+		t.Errorf("ReadFile() = %v, %+v, %v, want the written metadata", got, gotFetch, err)
+	}
+	if result, err := ValidateFile(withMetadata); err != nil || !result.Valid() {
+		t.Errorf("ValidateFile() = %+v, %v, want metadata accepted", result, err)
+	}
+	if mismatch := gotFetch.Mismatch(FetchMetadata{APIHost: "api.github.com"}); !strings.Contains(mismatch, "ghe.example.com") {
+		t.Errorf("Mismatch() = %q, want the cache's host", mismatch)
+	}
+
+	// Files written before metadata was recorded still load
+	without := filepath.Join(dir, "without.json")
+	if err := WriteFile(without, "actions/runner", releases, nil, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if got, gotFetch, err := ReadFile(without, "", nil); err != nil || len(got) != 1 || gotFetch != nil {
+		t.Errorf("ReadFile() without metadata = %v, %+v, %v", got, gotFetch, err)
+	}
+}
@@ -0,0 +1,51 @@
+package cache
+
+import (
+	"time"
+
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+// Preview describes what writing releases to a cache file would do, for dry runs
+type Preview struct {
+	Releases  int             // Releases the file would list
+	New       []types.Release // Releases the existing file lacks, newest first
+	Dropped   int             // Releases in the existing file that would be gone
+	Size      int             // Bytes the file would take
+	Exists    bool            // Whether the file exists now
+	Unchanged bool            // Whether it already lists these releases, fetched the same way
+}
+
+// PreviewFile reports what WriteFile (or GenerateFile) with the same arguments
+// would write to path, without touching it
+func PreviewFile(path, repository string, releases []types.Release, fetch *FetchMetadata, generatedAt time.Time) (*Preview, error) {
+	releases = sortedForCache(releases)
+	data, err := encodeCache(repository, releases, fetch, generatedAt)
+	if err != nil {
+		return nil, err
+	}
+	preview := &Preview{Releases: len(releases), Size: len(data)}
+
+	existing, existingFetch, err := ReadFile(path, repository, nil)
+	if err != nil {
+		preview.New = releases
+		return preview, nil
+	}
+	preview.Exists = true
+	preview.Unchanged = sameReleases(sortedForCache(existing), releases) && sameFetch(existingFetch, fetch)
+
+	had := make(map[string]bool, len(existing))
+	for _, r := range existing {
+		had[r.Version.String()] = true
+	}
+	kept := 0
+	for _, r := range releases {
+		if had[r.Version.String()] {
+			kept++
+		} else {
+			preview.New = append(preview.New, r)
+		}
+	}
+	preview.Dropped = len(existing) - kept
+	return preview, nil
+}
@@ -0,0 +1,51 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+func TestPreviewFile(t *testing.T) {
+	published := time.Date(2025, 10, 14, 12, 0, 0, 0, time.UTC)
+	release := func(version string, daysAgo int) types.Release {
+		return types.Release{Version: semver.MustParse(version), PublishedAt: published.AddDate(0, 0, -daysAgo), URL: "https://example.com/v" + version}
+	}
+	path := filepath.Join(t.TempDir(), "releases.json")
+	old := []types.Release{release("2.328.0", 60), release("2.327.0", 90)}
+
+	preview, err := PreviewFile(path, "actions/runner", old, nil, published)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if preview.Exists || len(preview.New) != 2 || preview.Size == 0 {
+		t.Errorf("PreviewFile() of a new file = %+v", preview)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("PreviewFile() created the file: %v", err)
+	}
+
+	if err := WriteFile(path, "actions/runner", old, nil, published); err != nil {
+		t.Fatal(err)
+	}
+	before, _ := os.ReadFile(path)
+	if preview, err := PreviewFile(path, "actions/runner", old, nil, published); err != nil || !preview.Unchanged || preview.Size != len(before) {
+		t.Errorf("PreviewFile() of the same releases = %+v, %v, want unchanged at %d bytes", preview, err, len(before))
+	}
+
+	updated := []types.Release{release("2.329.0", 0), release("2.328.0", 60)}
+	preview, err = PreviewFile(path, "actions/runner", updated, nil, published)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if preview.Unchanged || len(preview.New) != 1 || preview.New[0].Version.String() != "2.329.0" || preview.Dropped != 1 {
+		t.Errorf("PreviewFile() of updated releases = %+v, want 2.329.0 new and 1 dropped", preview)
+	}
+	if after, _ := os.ReadFile(path); string(after) != string(before) {
+		t.Error("PreviewFile() changed the existing file")
+	}
+}
@@ -0,0 +1,43 @@
+package cache
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+// Retention limits the releases a cache file keeps when it is saved; zero
+// fields keep everything. The most recent release is always kept.
+type Retention struct {
+	Releases int // Keep only the N most recently published releases
+	Years    int // Keep only releases published in the last N years
+}
+
+// Validate reports negative limits
+func (r Retention) Validate() error {
+	if r.Releases < 0 {
+		return fmt.Errorf("releases to keep must not be negative, got %d", r.Releases)
+	}
+	if r.Years < 0 {
+		return fmt.Errorf("years to keep must not be negative, got %d", r.Years)
+	}
+	return nil
+}
+
+// Apply returns the releases r keeps as of now, most recently published first
+func (r Retention) Apply(releases []types.Release, now time.Time) []types.Release {
+	kept := sortedForCache(releases)
+	if r.Years > 0 {
+		cutoff := now.AddDate(-r.Years, 0, 0)
+		n := 0
+		for n < len(kept) && (n == 0 || !kept[n].PublishedAt.Before(cutoff)) {
+			n++
+		}
+		kept = kept[:n]
+	}
+	if r.Releases > 0 && len(kept) > r.Releases {
+		kept = kept[:r.Releases]
+	}
+	return kept
+}
@@ -0,0 +1,50 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+func TestRetention_Apply(t *testing.T) {
+	now := time.Date(2025, 10, 20, 0, 0, 0, 0, time.UTC)
+	release := func(version string, published time.Time) types.Release {
+		return types.Release{Version: semver.MustParse(version), PublishedAt: published}
+	}
+	releases := []types.Release{
+		release("1.0.0", now.AddDate(-5, 0, 0)),
+		release("3.0.0", now.AddDate(0, -1, 0)),
+		release("2.0.0", now.AddDate(-2, 0, 0)),
+		release("2.1.0", now.AddDate(-1, -6, 0)),
+	}
+	stale := []types.Release{release("1.0.0", now.AddDate(-5, 0, 0)), release("0.9.0", now.AddDate(-6, 0, 0))}
+
+	tests := []struct {
+		name      string
+		retention Retention
+		releases  []types.Release
+		want      []string
+	}{
+		{"keep everything", Retention{}, releases, []string{"3.0.0", "2.1.0", "2.0.0", "1.0.0"}},
+		{"last 2 releases", Retention{Releases: 2}, releases, []string{"3.0.0", "2.1.0"}},
+		{"last 2 years", Retention{Years: 2}, releases, []string{"3.0.0", "2.1.0", "2.0.0"}},
+		{"both limits", Retention{Releases: 1, Years: 2}, releases, []string{"3.0.0"}},
+		{"inactive repository keeps its latest", Retention{Years: 1}, stale, []string{"1.0.0"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.retention.Apply(tt.releases, now)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Apply() kept %d releases, want %v", len(got), tt.want)
+			}
+			for i, want := range tt.want {
+				if got[i].Version.String() != want {
+					t.Errorf("Apply()[%d] = %s, want %s", i, got[i].Version, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package cache
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+// Cache files are signed in minisign's legacy Ed25519 format, so signatures can
+// also be made with `minisign -S -l` and checked with `minisign -V`.
+
+var (
+	legacyAlgorithm    = []byte("Ed")
+	prehashedAlgorithm = []byte("ED")
+)
+
+const (
+	keyIDSize         = 8
+	publicKeySize     = 2 + keyIDSize + ed25519.PublicKeySize
+	signatureSize     = 2 + keyIDSize + ed25519.SignatureSize
+	secretKeySize     = 2 + 2 + 2 + 32 + 8 + 8 + keyIDSize + ed25519.PrivateKeySize + 32
+	secretKeyIDOffset = 2 + 2 + 2 + 32 + 8 + 8
+
+	trustedCommentPrefix = "trusted comment: "
+)
+
+// ErrSignatureMismatch reports a cache file whose signature does not verify
+var ErrSignatureMismatch = errors.New("signature does not match")
+
+// PublicKey verifies cache signatures
+type PublicKey struct {
+	ID  [keyIDSize]byte
+	Key ed25519.PublicKey
+}
+
+// SecretKey signs cache files
+type SecretKey struct {
+	ID  [keyIDSize]byte
+	Key ed25519.PrivateKey
+}
+
+// SignaturePath returns where the signature of a cache file is kept
+func SignaturePath(path string) string {
+	return path + ".minisig"
+}
+
+// ParsePublicKey parses a minisign public key, either the key file or its
+// base64 line on its own
+func ParsePublicKey(text string) (*PublicKey, error) {
+	data, err := decodeKeyLine(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+	if len(data) != publicKeySize || !bytes.Equal(data[:2], legacyAlgorithm) {
+		return nil, fmt.Errorf("invalid public key: not a minisign Ed25519 key")
+	}
+	key := &PublicKey{Key: ed25519.PublicKey(data[2+keyIDSize:])}
+	copy(key.ID[:], data[2:2+keyIDSize])
+	return key, nil
+}
+
+// LoadPublicKey reads a minisign public key file
+func LoadPublicKey(path string) (*PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read public key: %w", err)
+	}
+	return ParsePublicKey(string(data))
+}
+
+// ParseSecretKey parses a minisign secret key file. The key must be stored
+// unencrypted (`minisign -G -W`), since the tool has no password prompt.
+func ParseSecretKey(text string) (*SecretKey, error) {
+	data, err := decodeKeyLine(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid secret key: %w", err)
+	}
+	if len(data) != secretKeySize || !bytes.Equal(data[:2], legacyAlgorithm) {
+		return nil, fmt.Errorf("invalid secret key: not a minisign Ed25519 key")
+	}
+	if data[2] != 0 || data[3] != 0 {
+		return nil, fmt.Errorf("secret key is encrypted; create an unencrypted key with minisign -G -W")
+	}
+
+	offset := secretKeyIDOffset + keyIDSize
+	private := ed25519.PrivateKey(bytes.Clone(data[offset : offset+ed25519.PrivateKeySize]))
+	if !bytes.Equal(ed25519.NewKeyFromSeed(private.Seed()), private) {
+		return nil, fmt.Errorf("invalid secret key: corrupt key data")
+	}
+	key := &SecretKey{Key: private}
+	copy(key.ID[:], data[secretKeyIDOffset:offset])
+	return key, nil
+}
+
+// LoadSecretKey reads an unencrypted minisign secret key file
+func LoadSecretKey(path string) (*SecretKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secret key: %w", err)
+	}
+	return ParseSecretKey(string(data))
+}
+
+// Public returns the key that verifies signatures made with k
+func (k *SecretKey) Public() *PublicKey {
+	return &PublicKey{ID: k.ID, Key: k.Key.Public().(ed25519.PublicKey)}
+}
+
+// Sign returns a minisign signature of data, with trustedComment signed alongside it
+func Sign(data []byte, key *SecretKey, trustedComment string) []byte {
+	signature := append(bytes.Clone(legacyAlgorithm), key.ID[:]...)
+	signature = append(signature, ed25519.Sign(key.Key, data)...)
+	global := ed25519.Sign(key.Key, append(bytes.Clone(signature[2+keyIDSize:]), trustedComment...))
+
+	var b strings.Builder
+	b.WriteString("untrusted comment: signature from github-release-version-checker\n")
+	b.WriteString(base64.StdEncoding.EncodeToString(signature) + "\n")
+	b.WriteString(trustedCommentPrefix + trustedComment + "\n")
+	b.WriteString(base64.StdEncoding.EncodeToString(global) + "\n")
+	return []byte(b.String())
+}
+
+// Verify checks a minisign signature of data
+func Verify(data, signature []byte, key *PublicKey) error {
+	lines := strings.Split(strings.TrimSpace(string(signature)), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[2], trustedCommentPrefix) {
+		return fmt.Errorf("invalid signature: not a minisign signature")
+	}
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[1]))
+	if err != nil || len(sig) != signatureSize {
+		return fmt.Errorf("invalid signature: not a minisign signature")
+	}
+	if bytes.Equal(sig[:2], prehashedAlgorithm) {
+		return fmt.Errorf("prehashed signatures are not supported; sign with minisign -S -l")
+	}
+	if !bytes.Equal(sig[:2], legacyAlgorithm) {
+		return fmt.Errorf("invalid signature: unknown algorithm %q", sig[:2])
+	}
+	if !bytes.Equal(sig[2:2+keyIDSize], key.ID[:]) {
+		return fmt.Errorf("signature is from a different key")
+	}
+	if !ed25519.Verify(key.Key, data, sig[2+keyIDSize:]) {
+		return ErrSignatureMismatch
+	}
+
+	global, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[3]))
+	if err != nil {
+		return fmt.Errorf("invalid signature: %w", err)
+	}
+	trustedComment := strings.TrimSuffix(strings.TrimPrefix(lines[2], trustedCommentPrefix), "\r")
+	if !ed25519.Verify(key.Key, append(bytes.Clone(sig[2+keyIDSize:]), trustedComment...), global) {
+		return fmt.Errorf("trusted comment: %w", ErrSignatureMismatch)
+	}
+	return nil
+}
+
+// SignFile writes the signature of the cache file at path next to it
+func SignFile(path string, key *SecretKey, signedAt time.Time) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read cache %s: %w", path, err)
+	}
+	comment := fmt.Sprintf("timestamp:%d\tfile:%s", signedAt.Unix(), filepath.Base(path))
+	if err := os.WriteFile(SignaturePath(path), Sign(data, key, comment), 0o644); err != nil {
+		return fmt.Errorf("failed to write signature: %w", err)
+	}
+	return nil
+}
+
+// VerifyFile checks the cache file at path against its signature
+func VerifyFile(path string, key *PublicKey) error {
+	_, err := readVerified(path, key)
+	return err
+}
+
+// LoadVerifiedFile loads releases from repository's cache file on disk after
+// checking its signature and that it lists repository's releases
+func LoadVerifiedFile(path, repository string, key *PublicKey) ([]types.Release, error) {
+	releases, _, err := ReadFile(path, repository, key)
+	return releases, err
+}
+
+// readVerified reads a cache file once, so the bytes parsed are the bytes verified
+func readVerified(path string, key *PublicKey) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache %s: %w", path, err)
+	}
+	signature, err := os.ReadFile(SignaturePath(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signature of %s: %w", path, err)
+	}
+	if err := Verify(data, signature, key); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return data, nil
+}
+
+// decodeKeyLine returns the decoded key from a minisign key file, skipping
+// its untrusted comment
+func decodeKeyLine(text string) ([]byte, error) {
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "untrusted comment:") {
+			continue
+		}
+		return base64.StdEncoding.DecodeString(line)
+	}
+	return nil, fmt.Errorf("no key found")
+}
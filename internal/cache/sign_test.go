@@ -0,0 +1,182 @@
+package cache
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+// secretKeyFile returns a minisign secret key file for seed, and its public key file
+func secretKeyFile(t *testing.T, seed byte, encrypted bool) (string, string) {
+	t.Helper()
+	private := ed25519.NewKeyFromSeed(bytes.Repeat([]byte{seed}, ed25519.SeedSize))
+	id := bytes.Repeat([]byte{seed + 1}, keyIDSize)
+
+	secret := append([]byte("Ed"), 0, 0)
+	if encrypted {
+		secret = append([]byte("Ed"), "Sc"...)
+	}
+	secret = append(secret, "B2"...)
+	secret = append(secret, make([]byte, 32+8+8)...)
+	secret = append(secret, id...)
+	secret = append(secret, private...)
+	secret = append(secret, make([]byte, 32)...) // Checksum, not checked
+
+	public := append(append([]byte("Ed"), id...), private.Public().(ed25519.PublicKey)...)
+	return "untrusted comment: minisign secret key\n" + base64.StdEncoding.EncodeToString(secret) + "\n",
+		"untrusted comment: minisign public key\n" + base64.StdEncoding.EncodeToString(public) + "\n"
+}
+
+func TestParseSecretKey(t *testing.T) {
+	secret, public := secretKeyFile(t, 1, false)
+	key, err := ParseSecretKey(secret)
+	if err != nil {
+		t.Fatalf("ParseSecretKey() error = %v", err)
+	}
+	want, err := ParsePublicKey(public)
+	if err != nil {
+		t.Fatalf("ParsePublicKey() error = %v", err)
+	}
+	if got := key.Public(); got.ID != want.ID || !got.Key.Equal(want.Key) {
+		t.Errorf("Public() = %+v, want %+v", got, want)
+	}
+
+	encrypted, _ := secretKeyFile(t, 1, true)
+	if _, err := ParseSecretKey(encrypted); err == nil || !strings.Contains(err.Error(), "minisign -G -W") {
+		t.Errorf("ParseSecretKey(encrypted) error = %v, want advice to use an unencrypted key", err)
+	}
+	if _, err := ParseSecretKey(public); err == nil {
+		t.Error("ParseSecretKey(public key) succeeded, want an error")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	secret, _ := secretKeyFile(t, 1, false)
+	key, err := ParseSecretKey(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherSecret, _ := secretKeyFile(t, 2, false)
+	other, err := ParseSecretKey(otherSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := []byte(`{"releases": []}`)
+	signature := Sign(data, key, "timestamp:1760000000\tfile:runner.json")
+	lines := strings.Split(string(signature), "\n")
+
+	prehashed := bytes.Clone(signature)
+	sig, _ := base64.StdEncoding.DecodeString(lines[1])
+	copy(sig, "ED")
+	prehashed = bytes.Replace(prehashed, []byte(lines[1]), []byte(base64.StdEncoding.EncodeToString(sig)), 1)
+
+	tests := []struct {
+		name      string
+		data      []byte
+		signature []byte
+		key       *PublicKey
+		wantErr   string
+	}{
+		{"valid", data, signature, key.Public(), ""},
+		{"tampered data", []byte(`{"releases": [1]}`), signature, key.Public(), "does not match"},
+		{"tampered comment", data, bytes.Replace(signature, []byte("runner.json"), []byte("other.json"), 1), key.Public(), "trusted comment"},
+		{"different key", data, signature, other.Public(), "different key"},
+		{"prehashed", data, prehashed, key.Public(), "minisign -S -l"},
+		{"not a signature", data, []byte("hello"), key.Public(), "not a minisign signature"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(tt.data, tt.signature, tt.key)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Verify() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Verify() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadVerifiedFile(t *testing.T) {
+	secret, _ := secretKeyFile(t, 1, false)
+	key, err := ParseSecretKey(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "runner.json")
+	releases := []types.Release{
+		{Version: semver.MustParse("2.329.0"), PublishedAt: time.Date(2025, 10, 14, 0, 0, 0, 0, time.UTC), URL: "https://example.com/v2.329.0"},
+	}
+	if err := WriteFile(path, "actions/runner", releases, nil, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadVerifiedFile(path, "actions/runner", key.Public()); err == nil {
+		t.Error("LoadVerifiedFile() of an unsigned file succeeded, want an error")
+	}
+	if err := SignFile(path, key, time.Now()); err != nil {
+		t.Fatalf("SignFile() error = %v", err)
+	}
+	got, err := LoadVerifiedFile(path, "actions/runner", key.Public())
+	if err != nil || len(got) != 1 {
+		t.Fatalf("LoadVerifiedFile() = %v, %v", got, err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if err := os.WriteFile(path, bytes.Replace(data, []byte("2.329.0"), []byte("2.399.0"), 1), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadVerifiedFile(path, "actions/runner", key.Public()); !errors.Is(err, ErrSignatureMismatch) {
+		t.Errorf("LoadVerifiedFile() of a tampered file error = %v, want ErrSignatureMismatch", err)
+	}
+}
+
+func TestLoadVerifiedFile_OtherRepository(t *testing.T) {
+	secret, _ := secretKeyFile(t, 1, false)
+	key, err := ParseSecretKey(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	releases := []types.Release{
+		{Version: semver.MustParse("2.329.0"), PublishedAt: time.Date(2025, 10, 14, 0, 0, 0, 0, time.UTC), URL: "https://example.com/v2.329.0"},
+	}
+	tests := []struct {
+		name       string
+		written    string // Repository the file names
+		repository string // Repository it is loaded for
+		wantErr    bool
+	}{
+		{name: "same repository", written: "actions/runner", repository: "actions/runner"},
+		{name: "differently cased", written: "Actions/Runner", repository: "actions/runner"},
+		{name: "other repository", written: "actions/runner", repository: "actions/checkout", wantErr: true},
+		{name: "no repository", written: "", repository: "actions/runner", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "releases.json")
+			if err := WriteFile(path, tt.written, releases, nil, time.Now()); err != nil {
+				t.Fatal(err)
+			}
+			if err := SignFile(path, key, time.Now()); err != nil {
+				t.Fatal(err)
+			}
+			_, err := LoadVerifiedFile(path, tt.repository, key.Public())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("LoadVerifiedFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
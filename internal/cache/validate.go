@@ -0,0 +1,222 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+// knownCacheFields lists the top-level fields of a cache file
+var knownCacheFields = map[string]bool{
+	"generated_at": true,
+	"repository":   true,
+	"fetch":        true,
+	"releases":     true,
+}
+
+// knownReleaseFields lists the fields of a release entry
+var knownReleaseFields = map[string]bool{
+	"version":       true,
+	"published_at":  true,
+	"url":           true,
+	"is_prerelease": true,
+	"platforms":     true,
+}
+
+// ValidationIssue describes a single problem found in a cache file
+type ValidationIssue struct {
+	Index   int    // Release index, or -1 for file-level issues
+	Version string // Release version as written in the file (if known)
+	Message string
+}
+
+// String formats the issue for display
+func (i ValidationIssue) String() string {
+	if i.Index < 0 {
+		return i.Message
+	}
+	if i.Version != "" {
+		return fmt.Sprintf("releases[%d] (%s): %s", i.Index, i.Version, i.Message)
+	}
+	return fmt.Sprintf("releases[%d]: %s", i.Index, i.Message)
+}
+
+// ValidationResult summarises the validation of a cache file
+type ValidationResult struct {
+	Releases int // Number of release entries in the file
+	Issues   []ValidationIssue
+}
+
+// Valid reports whether no issues were found
+func (r *ValidationResult) Valid() bool {
+	return len(r.Issues) == 0
+}
+
+// ValidateFile validates a cache file on disk
+func ValidateFile(path string) (*ValidationResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache %s: %w", path, err)
+	}
+	return Validate(data)
+}
+
+// Validate checks cache contents for schema conformity, unparseable versions,
+// inconsistent dates and duplicate versions. An error is returned only when the
+// data is not a JSON object at all.
+func Validate(data []byte) (*ValidationResult, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("failed to parse cache: %w", err)
+	}
+
+	result := &ValidationResult{}
+	fileIssue := func(format string, args ...interface{}) {
+		result.Issues = append(result.Issues, ValidationIssue{Index: -1, Message: fmt.Sprintf(format, args...)})
+	}
+
+	for _, name := range sortedKeys(fields) {
+		if !knownCacheFields[name] {
+			fileIssue("unknown top-level field %q", name)
+		}
+	}
+
+	var generatedAt time.Time
+	if raw, ok := fields["generated_at"]; !ok {
+		fileIssue("missing \"generated_at\" (RFC 3339 timestamp of when the cache was built)")
+	} else if err := json.Unmarshal(raw, &generatedAt); err != nil {
+		fileIssue("\"generated_at\" is not an RFC 3339 timestamp: %s", string(raw))
+	}
+
+	raw, ok := fields["releases"]
+	if !ok {
+		fileIssue("missing \"releases\" array")
+		return result, nil
+	}
+
+	var entries []map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &entries); err != nil {
+		fileIssue("\"releases\" must be an array of objects: %v", err)
+		return result, nil
+	}
+	result.Releases = len(entries)
+
+	type parsedEntry struct {
+		index       int
+		version     *semver.Version
+		publishedAt time.Time
+	}
+	var parsed []parsedEntry
+	seen := make(map[string]int)
+
+	for i, entry := range entries {
+		versionStr := ""
+		issue := func(format string, args ...interface{}) {
+			result.Issues = append(result.Issues, ValidationIssue{
+				Index:   i,
+				Version: versionStr,
+				Message: fmt.Sprintf(format, args...),
+			})
+		}
+
+		if rawVersion, ok := entry["version"]; ok {
+			if err := json.Unmarshal(rawVersion, &versionStr); err != nil {
+				issue("\"version\" must be a string, got %s", string(rawVersion))
+			}
+		}
+
+		for _, name := range sortedKeys(entry) {
+			if !knownReleaseFields[name] {
+				issue("unknown field %q", name)
+			}
+		}
+
+		var ver *semver.Version
+		if versionStr == "" {
+			if _, ok := entry["version"]; !ok {
+				issue("missing \"version\"")
+			}
+		} else if v, err := types.ParseVersion(versionStr); err != nil {
+			issue("version is not valid semver (expected MAJOR.MINOR.PATCH): %v", err)
+		} else {
+			ver = v
+			key := v.String()
+			if first, dup := seen[key]; dup {
+				issue("duplicate of releases[%d]; remove one of the entries", first)
+			} else {
+				seen[key] = i
+			}
+		}
+
+		var publishedAt time.Time
+		if rawDate, ok := entry["published_at"]; !ok {
+			issue("missing \"published_at\"")
+		} else if err := json.Unmarshal(rawDate, &publishedAt); err != nil {
+			issue("\"published_at\" is not an RFC 3339 timestamp: %s", string(rawDate))
+		} else if publishedAt.IsZero() {
+			issue("\"published_at\" is the zero time")
+		} else if !generatedAt.IsZero() && publishedAt.After(generatedAt) {
+			issue("published %s, after the cache was generated (%s)",
+				publishedAt.Format(time.RFC3339), generatedAt.Format(time.RFC3339))
+		}
+
+		var url string
+		if rawURL, ok := entry["url"]; !ok {
+			issue("missing \"url\"")
+		} else if err := json.Unmarshal(rawURL, &url); err != nil || url == "" {
+			issue("\"url\" must be a non-empty string")
+		}
+
+		if rawPlatforms, ok := entry["platforms"]; ok {
+			var platforms []string
+			if err := json.Unmarshal(rawPlatforms, &platforms); err != nil {
+				issue("\"platforms\" must be an array of strings")
+			}
+			for _, platform := range platforms {
+				if normalised, err := types.ParsePlatform(platform); err != nil || normalised != platform {
+					issue("platform %q is not OS/ARCH as Go names them, e.g. linux/arm64", platform)
+				}
+			}
+		}
+
+		if ver != nil && !publishedAt.IsZero() {
+			parsed = append(parsed, parsedEntry{index: i, version: ver, publishedAt: publishedAt})
+		}
+	}
+
+	// Within a release line, a higher patch must not predate a lower one
+	sort.Slice(parsed, func(a, b int) bool {
+		return types.CompareVersions(parsed[a].version, parsed[b].version) < 0
+	})
+	for j := 1; j < len(parsed); j++ {
+		prev, cur := parsed[j-1], parsed[j]
+		if prev.version.Major() != cur.version.Major() || prev.version.Minor() != cur.version.Minor() {
+			continue
+		}
+		if cur.publishedAt.Before(prev.publishedAt) {
+			result.Issues = append(result.Issues, ValidationIssue{
+				Index:   cur.index,
+				Version: cur.version.Original(),
+				Message: fmt.Sprintf("published %s, before the lower version %s (%s); check the dates",
+					cur.publishedAt.Format(time.RFC3339), prev.version, prev.publishedAt.Format(time.RFC3339)),
+			})
+		}
+	}
+
+	return result, nil
+}
+
+// sortedKeys returns map keys in a stable order for deterministic reporting
+func sortedKeys(m map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
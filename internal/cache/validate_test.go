@@ -0,0 +1,130 @@
+package cache
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidate_EmbeddedCacheIsValid(t *testing.T) {
+	data, err := embeddedCaches.ReadFile("data/actions-runner.json")
+	if err != nil {
+		t.Fatalf("failed to read embedded cache: %v", err)
+	}
+
+	result, err := Validate(data)
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if !result.Valid() {
+		t.Errorf("expected embedded cache to be valid, got issues: %v", result.Issues)
+	}
+	if result.Releases == 0 {
+		t.Error("expected releases to be counted")
+	}
+}
+
+func TestValidate_Issues(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		wantIssues  int
+		wantMessage string
+	}{
+		{
+			name:        "missing generated_at",
+			content:     `{"releases": []}`,
+			wantIssues:  1,
+			wantMessage: "missing \"generated_at\"",
+		},
+		{
+			name:        "missing releases",
+			content:     `{"generated_at": "2025-10-31T00:00:00Z"}`,
+			wantIssues:  1,
+			wantMessage: "missing \"releases\"",
+		},
+		{
+			name:        "unknown top-level field",
+			content:     `{"generated_at": "2025-10-31T00:00:00Z", "releases": [], "extra": 1}`,
+			wantIssues:  1,
+			wantMessage: "unknown top-level field \"extra\"",
+		},
+		{
+			name: "invalid semver",
+			content: `{"generated_at": "2025-10-31T00:00:00Z", "releases": [
+				{"version": "latest", "published_at": "2025-10-01T00:00:00Z", "url": "https://example.com"}
+			]}`,
+			wantIssues:  1,
+			wantMessage: "releases[0] (latest): version is not valid semver",
+		},
+		{
+			name: "duplicate versions",
+			content: `{"generated_at": "2025-10-31T00:00:00Z", "releases": [
+				{"version": "1.0.0", "published_at": "2025-10-01T00:00:00Z", "url": "https://example.com"},
+				{"version": "v1.0.0", "published_at": "2025-10-01T00:00:00Z", "url": "https://example.com"}
+			]}`,
+			wantIssues:  1,
+			wantMessage: "duplicate of releases[0]",
+		},
+		{
+			name: "published after generation",
+			content: `{"generated_at": "2025-10-31T00:00:00Z", "releases": [
+				{"version": "1.0.0", "published_at": "2025-11-01T00:00:00Z", "url": "https://example.com"}
+			]}`,
+			wantIssues:  1,
+			wantMessage: "after the cache was generated",
+		},
+		{
+			name: "patch predates lower patch",
+			content: `{"generated_at": "2025-10-31T00:00:00Z", "releases": [
+				{"version": "1.0.1", "published_at": "2025-09-01T00:00:00Z", "url": "https://example.com"},
+				{"version": "1.0.0", "published_at": "2025-10-01T00:00:00Z", "url": "https://example.com"}
+			]}`,
+			wantIssues:  1,
+			wantMessage: "before the lower version 1.0.0",
+		},
+		{
+			name: "invalid platform",
+			content: `{"generated_at": "2025-10-31T00:00:00Z", "releases": [
+				{"version": "1.0.0", "published_at": "2025-10-01T00:00:00Z", "url": "https://example.com", "platforms": ["linux/arm64", "win-x64"]}
+			]}`,
+			wantIssues:  1,
+			wantMessage: "platform \"win-x64\" is not OS/ARCH",
+		},
+		{
+			name: "missing fields",
+			content: `{"generated_at": "2025-10-31T00:00:00Z", "releases": [
+				{"version": "1.0.0"}
+			]}`,
+			wantIssues:  2,
+			wantMessage: "missing \"published_at\"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Validate([]byte(tt.content))
+			if err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+			if len(result.Issues) != tt.wantIssues {
+				t.Fatalf("expected %d issues, got %d: %v", tt.wantIssues, len(result.Issues), result.Issues)
+			}
+
+			found := false
+			for _, issue := range result.Issues {
+				if strings.Contains(issue.String(), tt.wantMessage) {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("expected an issue containing %q, got %v", tt.wantMessage, result.Issues)
+			}
+		})
+	}
+}
+
+func TestValidate_InvalidJSON(t *testing.T) {
+	if _, err := Validate([]byte("not json")); err == nil {
+		t.Error("expected error for invalid JSON, got nil")
+	}
+}
@@ -0,0 +1,159 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Alert actions a status can be mapped to
+const (
+	AlertNotify = "notify" // Post to the notification webhook
+	AlertIssue  = "issue"  // Open an issue in notifications.issue_repo
+	AlertPage   = "page"   // Trigger a PagerDuty incident
+	AlertIgnore = "ignore" // Nothing; the status is still reported
+)
+
+// DefaultPageURL is PagerDuty's Events API v2 endpoint
+const DefaultPageURL = "https://events.pagerduty.com/v2/enqueue"
+
+// DefaultPageRoutingKeyEnv names the environment variable holding the
+// PagerDuty routing key, which is a secret and so never in the file
+const DefaultPageRoutingKeyEnv = "PAGERDUTY_ROUTING_KEY"
+
+// alertStatuses are the statuses alerts can be set for; current never alerts
+var alertStatuses = []string{"warning", "critical", "expired"}
+
+// AlertActions are the actions for one status. In YAML they are one action or
+// a list, e.g. `expired: page` or `expired: [page, issue]`.
+type AlertActions []string
+
+// UnmarshalYAML accepts a single action as well as a list
+func (a *AlertActions) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*a = AlertActions{node.Value}
+		return nil
+	}
+	var actions []string
+	if err := node.Decode(&actions); err != nil {
+		return err
+	}
+	*a = actions
+	return nil
+}
+
+// AlertRules map statuses (warning, critical, expired) to actions
+type AlertRules map[string]AlertActions
+
+// DefaultAlertRules notify for every status that needs attention
+func DefaultAlertRules() AlertRules {
+	return AlertRules{"warning": {AlertNotify}, "critical": {AlertNotify}, "expired": {AlertNotify}}
+}
+
+// validate checks every status and action is known, and that ignore stands alone
+func (r AlertRules) validate(field string) error {
+	for status, actions := range r {
+		if !slices.Contains(alertStatuses, status) {
+			return fmt.Errorf("%s: invalid status %q: must be one of %s", field, status, strings.Join(alertStatuses, ", "))
+		}
+		if len(actions) == 0 {
+			return fmt.Errorf("%s.%s: no actions", field, status)
+		}
+		for _, action := range actions {
+			switch action {
+			case AlertNotify, AlertIssue, AlertPage:
+			case AlertIgnore:
+				if len(actions) > 1 {
+					return fmt.Errorf("%s.%s: ignore cannot be combined with other actions", field, status)
+				}
+			default:
+				return fmt.Errorf("%s.%s: invalid action %q: must be notify, issue, page or ignore", field, status, action)
+			}
+		}
+	}
+	return nil
+}
+
+// uses reports whether any status maps to action
+func (r AlertRules) uses(action string) bool {
+	for _, actions := range r {
+		if slices.Contains(actions, action) {
+			return true
+		}
+	}
+	return false
+}
+
+// Actions returns the actions for status, none for ignore or an unmapped status
+func (r AlertRules) Actions(status string) []string {
+	actions := r[status]
+	if slices.Equal(actions, AlertActions{AlertIgnore}) {
+		return nil
+	}
+	return actions
+}
+
+// AlertRulesFor returns the alert rules for the repository fullName (owner/repo):
+// its own, over notifications.alerts, over the defaults. A nil file uses the defaults.
+func (f *File) AlertRulesFor(fullName string) AlertRules {
+	rules := DefaultAlertRules()
+	if f == nil {
+		return rules
+	}
+	for status, actions := range f.Notifications.Alerts {
+		rules[status] = actions
+	}
+	for _, r := range f.Repositories {
+		repoConfig, err := r.RepositoryConfig()
+		if err != nil || !strings.EqualFold(repoConfig.FullName(), fullName) {
+			continue
+		}
+		for status, actions := range r.Alerts {
+			rules[status] = actions
+		}
+		break
+	}
+	return rules
+}
+
+// validateAlerts checks the alert rules, and that the settings the actions need are set
+func (f *File) validateAlerts() error {
+	n := f.Notifications
+	if err := n.Alerts.validate("notifications.alerts"); err != nil {
+		return err
+	}
+	uses := func(action string) bool {
+		if n.Alerts.uses(action) {
+			return true
+		}
+		for _, r := range f.Repositories {
+			if r.Alerts.uses(action) {
+				return true
+			}
+		}
+		return false
+	}
+	for i, r := range f.Repositories {
+		if err := r.Alerts.validate(fmt.Sprintf("repositories[%d].alerts", i)); err != nil {
+			return err
+		}
+	}
+
+	if n.IssueRepo != "" {
+		if _, err := resolveRepo(n.IssueRepo); err != nil {
+			return fmt.Errorf("notifications.issue_repo: %w", err)
+		}
+	} else if uses(AlertIssue) {
+		return fmt.Errorf("notifications.issue_repo is required for issue alerts")
+	}
+	if n.PageURL != "" {
+		u, err := url.Parse(n.PageURL)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return fmt.Errorf("invalid notifications.page_url %q: must be an http(s) URL", n.PageURL)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFile_AlertRulesFor(t *testing.T) {
+	content := `notifications:
+  issue_repo: acme/platform
+  alerts:
+    warning: ignore
+    expired: [notify, issue]
+repositories:
+  - repo: runner
+    version: 2.328.0
+    alerts:
+      critical: notify
+      expired: [page, issue]
+  - repo: acme/linter
+    alerts:
+      critical: ignore
+      expired: notify
+`
+	path := filepath.Join(t.TempDir(), DefaultFileName)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		file       *File
+		repository string
+		want       map[string][]string // Status to actions; nil for none
+	}{
+		{
+			name:       "repository rules over file rules",
+			file:       f,
+			repository: "actions/runner",
+			want:       map[string][]string{"warning": nil, "critical": {AlertNotify}, "expired": {AlertPage, AlertIssue}},
+		},
+		{
+			name:       "ignore hides a status",
+			file:       f,
+			repository: "acme/linter",
+			want:       map[string][]string{"warning": nil, "critical": nil, "expired": {AlertNotify}},
+		},
+		{
+			name:       "unlisted repository uses the file rules",
+			file:       f,
+			repository: "kubernetes/kubernetes",
+			want:       map[string][]string{"warning": nil, "critical": {AlertNotify}, "expired": {AlertNotify, AlertIssue}},
+		},
+		{
+			name:       "no file notifies for everything",
+			repository: "actions/runner",
+			want:       map[string][]string{"warning": {AlertNotify}, "critical": {AlertNotify}, "expired": {AlertNotify}, "current": nil},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules := tt.file.AlertRulesFor(tt.repository)
+			for status, want := range tt.want {
+				got := rules.Actions(status)
+				if len(got) == 0 && len(want) == 0 {
+					continue
+				}
+				if !reflect.DeepEqual([]string(got), want) {
+					t.Errorf("Actions(%s) = %v, want %v", status, got, want)
+				}
+			}
+		})
+	}
+}
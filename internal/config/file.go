@@ -0,0 +1,552 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/nickromney-org/github-release-version-checker/pkg/checker"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultFileName is the config file looked for in the working directory
+const DefaultFileName = ".release-checker.yaml"
+
+// Token sources accepted in a config file
+const (
+	TokenSourceAuto = "auto" // Flag, environment, gh hosts.yml, .netrc, then gh CLI
+	TokenSourceEnv  = "env"  // A named environment variable only
+	TokenSourceGH   = "gh"   // The GitHub CLI only
+	TokenSourceNone = "none" // Unauthenticated requests
+)
+
+// File is the on-disk configuration written by `init`
+type File struct {
+	Repositories  []FileRepository     `yaml:"repositories"`
+	Token         TokenSettings        `yaml:"token,omitempty"`
+	Hosts         []HostSettings       `yaml:"hosts,omitempty"`
+	Notifications NotificationSettings `yaml:"notifications,omitempty"`
+	Waivers       []FileWaiver         `yaml:"waivers,omitempty"`
+	Yanked        []FileYanked         `yaml:"yanked,omitempty"`
+	NoteKeywords  []FileNoteKeyword    `yaml:"note_keywords,omitempty"`
+}
+
+// FileRepository is one repository to check, with optional policy overrides
+type FileRepository struct {
+	Repo         string `yaml:"repo"`                    // Predefined name, owner/repo or GitHub URL
+	Host         string `yaml:"host,omitempty"`          // GitHub Enterprise Server host, e.g. "github.example.com"
+	Version      string `yaml:"version,omitempty"`       // Version in use, compared against the latest
+	Policy       string `yaml:"policy,omitempty"`        // "days" or "versions"
+	CriticalDays int    `yaml:"critical_days,omitempty"` // For days policies
+	MaxDays      int    `yaml:"max_days,omitempty"`      // For days policies
+	MaxVersions  int    `yaml:"max_versions,omitempty"`  // For versions policies
+	LatestFrom   string `yaml:"latest_from,omitempty"`   // "highest" or "marked"
+	Ordering     string `yaml:"ordering,omitempty"`      // "semver" or "date"
+	Track        string `yaml:"track,omitempty"`         // "stable" or "prerelease"
+	Channel      string `yaml:"channel,omitempty"`       // Release channel checked, e.g. "beta"
+	ZeroMajor    string `yaml:"zero_major,omitempty"`    // "minor-breaking" or "semver", for 0.x versions
+
+	Channels map[string]string `yaml:"channels,omitempty"` // Tag patterns by release channel, e.g. beta: '-beta\.'
+
+	MaintenanceWindow string `yaml:"maintenance_window,omitempty"` // e.g. "first tuesday monthly", for days policies
+
+	Upstream      string `yaml:"upstream,omitempty"`       // For forks and mirrors: the repository whose releases are checked
+	VersionSuffix string `yaml:"version_suffix,omitempty"` // Regular expression for a fork-specific version suffix
+
+	Alerts AlertRules `yaml:"alerts,omitempty"` // Actions per status, over notifications.alerts
+
+	NoteKeywords []FileNoteKeyword `yaml:"note_keywords,omitempty"` // Added to the shared note_keywords
+
+	RequiredAssets []string `yaml:"required_assets,omitempty"` // Platforms recommended releases need assets for, e.g. linux-x64
+	Platform       string   `yaml:"platform,omitempty"`        // OS/ARCH recommended releases must have shipped for, e.g. linux/arm64
+	MinReleaseAge  int      `yaml:"min_release_age,omitempty"` // Days a release must have been out before it is recommended
+
+	UnknownVersion string `yaml:"unknown_version,omitempty"` // "error", "expired" or "warning", for versions that are not releases
+
+	Vulnerabilities int `yaml:"vulnerabilities,omitempty"` // Known vulnerabilities in version that updating fixes, from a scanner
+}
+
+// FileWaiver is an approved exemption letting one version of a repository run
+// past its expiry, as a warning, until the waiver lapses. With type rollback it
+// instead sets aside a newer release upstream is rolling back.
+type FileWaiver struct {
+	Repo       string `yaml:"repo"`           // As in repositories
+	Version    string `yaml:"version"`        // The exempted version, or the release being rolled back
+	Expires    string `yaml:"expires"`        // YYYY-MM-DD; the waiver lapses at the start of this day (UTC)
+	Reason     string `yaml:"reason"`         // Why the version cannot be updated yet
+	ApprovedBy string `yaml:"approved_by"`    // Who approved the exemption
+	Type       string `yaml:"type,omitempty"` // WaiverTypeVersion (default) or WaiverTypeRollback
+}
+
+// Waiver types
+const (
+	WaiverTypeVersion  = "version"  // Exempts the version in use from expiry
+	WaiverTypeRollback = "rollback" // Sets aside a newer release upstream is rolling back
+)
+
+// FileYanked is a known-bad release of a repository, e.g. one with a regression,
+// that is never recommended
+type FileYanked struct {
+	Repo    string `yaml:"repo"`    // As in repositories
+	Version string `yaml:"version"` // The bad release
+	Reason  string `yaml:"reason"`  // What is wrong with it
+}
+
+// TokenSettings chooses where the GitHub token comes from
+type TokenSettings struct {
+	Source string `yaml:"source,omitempty"` // auto, env, gh or none
+	Env    string `yaml:"env,omitempty"`    // Variable name when source is env
+}
+
+// HostSettings configures a GitHub Enterprise Server that repositories name
+// with host. Hosts not listed use https://<name>/api/v3/ and tokens found for them.
+type HostSettings struct {
+	Name   string        `yaml:"name"`              // e.g. "github.example.com"
+	APIURL string        `yaml:"api_url,omitempty"` // Default: https://<name>/api/v3/
+	Token  TokenSettings `yaml:"token,omitempty"`   // Default: auto, for this host
+}
+
+// APIURL returns the REST API base URL of host: empty for github.com, the
+// listed api_url, or https://<host>/api/v3/
+func (f *File) APIURL(host string) string {
+	if host == "" || host == DefaultHost {
+		return ""
+	}
+	for _, h := range f.hosts() {
+		if h.Name == host && h.APIURL != "" {
+			return h.APIURL
+		}
+	}
+	return "https://" + host + "/api/v3/"
+}
+
+// hosts returns the configured hosts; a nil file has none
+func (f *File) hosts() []HostSettings {
+	if f == nil {
+		return nil
+	}
+	return f.Hosts
+}
+
+// TokenFor returns the token settings for host: the top-level token for
+// github.com, otherwise the host's own (auto when it is not listed)
+func (f *File) TokenFor(host string) TokenSettings {
+	if f == nil {
+		return TokenSettings{}
+	}
+	if host == "" || host == DefaultHost {
+		return f.Token
+	}
+	for _, h := range f.hosts() {
+		if h.Name == host {
+			return h.Token
+		}
+	}
+	return TokenSettings{}
+}
+
+// NotificationSettings controls CI annotations and the job summary
+type NotificationSettings struct {
+	AnnotationLevels map[string]string `yaml:"annotation_levels,omitempty"`
+	NoAnnotations    bool              `yaml:"no_annotations,omitempty"`
+	SummaryExclude   []string          `yaml:"summary_exclude,omitempty"`
+	SummaryTemplate  string            `yaml:"summary_template,omitempty"`
+	NotifyTemplate   string            `yaml:"notify_template,omitempty"` // The webhook URL is a secret, so is only a flag
+
+	Alerts            AlertRules `yaml:"alerts,omitempty"`               // Actions per status; default: notify for all
+	IssueRepo         string     `yaml:"issue_repo,omitempty"`           // Where issue alerts are opened, e.g. acme/platform
+	PageURL           string     `yaml:"page_url,omitempty"`             // Default: PagerDuty's Events API v2
+	PageRoutingKeyEnv string     `yaml:"page_routing_key_env,omitempty"` // Default: PAGERDUTY_ROUTING_KEY
+}
+
+// LoadFile reads and validates a config file
+func LoadFile(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var f File
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true) // Catch misspelt keys rather than silently ignoring them
+	if err := decoder.Decode(&f); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	if err := f.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	return &f, nil
+}
+
+// Validate checks repositories, policies and the token source
+func (f *File) Validate() error {
+	for i, r := range f.Repositories {
+		if _, err := r.RepositoryConfig(); err != nil {
+			return fmt.Errorf("repositories[%d]: %w", i, err)
+		}
+	}
+
+	for i, w := range f.Waivers {
+		if _, _, err := w.resolve(); err != nil {
+			return fmt.Errorf("waivers[%d]: %w", i, err)
+		}
+	}
+
+	for i, y := range f.Yanked {
+		if _, _, err := y.resolve(); err != nil {
+			return fmt.Errorf("yanked[%d]: %w", i, err)
+		}
+	}
+
+	if err := validateNoteKeywords("note_keywords", f.NoteKeywords); err != nil {
+		return err
+	}
+
+	if err := f.Token.validate("token"); err != nil {
+		return err
+	}
+	if err := f.validateAlerts(); err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool, len(f.Hosts))
+	for i, h := range f.Hosts {
+		if err := h.validate(); err != nil {
+			return fmt.Errorf("hosts[%d]: %w", i, err)
+		}
+		if seen[h.Name] {
+			return fmt.Errorf("hosts[%d]: %s is listed more than once", i, h.Name)
+		}
+		seen[h.Name] = true
+	}
+	return nil
+}
+
+// validate checks the token source; field names the settings in errors
+func (t TokenSettings) validate(field string) error {
+	switch t.Source {
+	case "", TokenSourceAuto, TokenSourceGH, TokenSourceNone:
+	case TokenSourceEnv:
+		if t.Env == "" {
+			return fmt.Errorf("%s.env is required when %s.source is %q", field, field, TokenSourceEnv)
+		}
+	default:
+		return fmt.Errorf("invalid %s.source %q: must be auto, env, gh or none", field, t.Source)
+	}
+	return nil
+}
+
+// validate checks the host name, API URL and token source
+func (h HostSettings) validate() error {
+	if err := validateHost(h.Name); err != nil {
+		return err
+	}
+	if h.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if h.Name == DefaultHost {
+		return fmt.Errorf("%s is configured by the top-level token, not hosts", DefaultHost)
+	}
+	if h.APIURL != "" {
+		u, err := url.Parse(h.APIURL)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return fmt.Errorf("invalid api_url %q: must be an http(s) URL", h.APIURL)
+		}
+	}
+	return h.Token.validate("token")
+}
+
+// validateHost checks a host is a bare lower-case name, without scheme or path
+func validateHost(host string) error {
+	if strings.ContainsAny(host, "/: ") || host != strings.ToLower(host) {
+		return fmt.Errorf("invalid host %q: use a lower-case host name such as github.example.com", host)
+	}
+	return nil
+}
+
+// RepositoryConfig resolves the repository and applies the file's policy overrides
+func (r FileRepository) RepositoryConfig() (*RepositoryConfig, error) {
+	resolved, err := resolveRepo(r.Repo)
+	if err != nil {
+		return nil, err
+	}
+	repoConfig := *resolved // Copy so predefined configs are not modified
+
+	if r.Host != "" {
+		if err := validateHost(r.Host); err != nil {
+			return nil, err
+		}
+		if repoConfig.Host != "" && repoConfig.Host != r.Host {
+			return nil, fmt.Errorf("host %q does not match the repository's host %q", r.Host, repoConfig.Host)
+		}
+		repoConfig.Host = r.Host
+		if r.Host == DefaultHost {
+			repoConfig.Host = ""
+		}
+	}
+	if r.Upstream != "" {
+		if err := repoConfig.SetUpstream(r.Upstream); err != nil {
+			return nil, err
+		}
+	}
+	if r.VersionSuffix != "" {
+		if err := repoConfig.SetVersionSuffix(r.VersionSuffix); err != nil {
+			return nil, err
+		}
+	}
+
+	switch strings.ToLower(r.Policy) {
+	case "":
+	case string(PolicyTypeDays):
+		repoConfig.PolicyType = PolicyTypeDays
+	case string(PolicyTypeVersions):
+		repoConfig.PolicyType = PolicyTypeVersions
+	default:
+		return nil, fmt.Errorf("invalid policy %q: must be 'days' or 'versions'", r.Policy)
+	}
+
+	// Switching a repository's policy type needs thresholds for the new type
+	if repoConfig.PolicyType == PolicyTypeDays && repoConfig.MaxDays == 0 {
+		repoConfig.CriticalDays = ConfigActionsRunner.CriticalDays
+		repoConfig.MaxDays = ConfigActionsRunner.MaxDays
+	}
+	if repoConfig.PolicyType == PolicyTypeVersions && repoConfig.MaxVersionsBehind == 0 {
+		repoConfig.MaxVersionsBehind = 3
+	}
+
+	if r.CriticalDays > 0 {
+		repoConfig.CriticalDays = r.CriticalDays
+	}
+	if r.MaxDays > 0 {
+		repoConfig.MaxDays = r.MaxDays
+	}
+	if r.MaxVersions > 0 {
+		repoConfig.MaxVersionsBehind = r.MaxVersions
+	}
+	switch r.LatestFrom {
+	case "":
+	case "highest", "marked":
+		repoConfig.LatestFrom = r.LatestFrom
+	default:
+		return nil, fmt.Errorf("invalid latest_from %q: must be 'highest' or 'marked'", r.LatestFrom)
+	}
+	switch r.Ordering {
+	case "":
+	case "semver", "date":
+		repoConfig.Ordering = r.Ordering
+	default:
+		return nil, fmt.Errorf("invalid ordering %q: must be 'semver' or 'date'", r.Ordering)
+	}
+	switch r.Track {
+	case "":
+	case "stable", "prerelease":
+		repoConfig.Track = r.Track
+	default:
+		return nil, fmt.Errorf("invalid track %q: must be 'stable' or 'prerelease'", r.Track)
+	}
+	switch r.UnknownVersion {
+	case "":
+	case "error", "expired", "warning":
+		repoConfig.UnknownVersion = r.UnknownVersion
+	default:
+		return nil, fmt.Errorf("invalid unknown_version %q: must be 'error', 'expired' or 'warning'", r.UnknownVersion)
+	}
+	if err := repoConfig.SetChannels(r.Channels); err != nil {
+		return nil, err
+	}
+	if err := validateNoteKeywords("note_keywords", r.NoteKeywords); err != nil {
+		return nil, err
+	}
+	repoConfig.NoteKeywords = r.NoteKeywords
+	if err := repoConfig.SetRequiredAssets(r.RequiredAssets); err != nil {
+		return nil, err
+	}
+	if err := repoConfig.SetPlatform(r.Platform); err != nil {
+		return nil, err
+	}
+	if r.MinReleaseAge < 0 {
+		return nil, fmt.Errorf("min_release_age must be non-negative")
+	}
+	repoConfig.MinReleaseAgeDays = r.MinReleaseAge
+	if r.Vulnerabilities < 0 {
+		return nil, fmt.Errorf("vulnerabilities must be non-negative")
+	}
+	repoConfig.Vulnerabilities = r.Vulnerabilities
+	repoConfig.Channel = strings.ToLower(r.Channel)
+	if repoConfig.Channel != "" && repoConfig.Track == "prerelease" {
+		return nil, fmt.Errorf("track and channel cannot both be set")
+	}
+	switch r.ZeroMajor {
+	case "":
+	case "minor-breaking", "semver":
+		repoConfig.ZeroMajor = r.ZeroMajor
+	default:
+		return nil, fmt.Errorf("invalid zero_major %q: must be 'minor-breaking' or 'semver'", r.ZeroMajor)
+	}
+	if r.MaintenanceWindow != "" {
+		if err := repoConfig.SetMaintenanceWindow(r.MaintenanceWindow); err != nil {
+			return nil, err
+		}
+	}
+
+	if repoConfig.PolicyType == PolicyTypeDays && repoConfig.CriticalDays >= repoConfig.MaxDays {
+		return nil, fmt.Errorf("critical_days (%d) must be less than max_days (%d)", repoConfig.CriticalDays, repoConfig.MaxDays)
+	}
+	return &repoConfig, nil
+}
+
+// resolveRepo looks up a predefined name, owner/repo or GitHub URL
+func resolveRepo(repo string) (*RepositoryConfig, error) {
+	if repo == "" {
+		return nil, fmt.Errorf("repo is required")
+	}
+	resolved, err := GetPredefinedConfig(repo)
+	if err != nil {
+		return ParseRepositoryString(repo)
+	}
+	return resolved, nil
+}
+
+// resolve validates the waiver, returning the repository's full name and the
+// waiver for the checker
+func (w FileWaiver) resolve() (string, checker.Waiver, error) {
+	repoConfig, err := resolveRepo(w.Repo)
+	if err != nil {
+		return "", checker.Waiver{}, err
+	}
+	if w.Version == "" {
+		return "", checker.Waiver{}, fmt.Errorf("version is required")
+	}
+	version, err := semver.NewVersion(w.Version)
+	if err != nil {
+		return "", checker.Waiver{}, fmt.Errorf("invalid version %q: %w", w.Version, err)
+	}
+	expires, err := time.Parse(time.DateOnly, w.Expires)
+	if err != nil {
+		return "", checker.Waiver{}, fmt.Errorf("invalid expires %q: use YYYY-MM-DD", w.Expires)
+	}
+	if strings.TrimSpace(w.Reason) == "" {
+		return "", checker.Waiver{}, fmt.Errorf("reason is required")
+	}
+	if strings.TrimSpace(w.ApprovedBy) == "" {
+		return "", checker.Waiver{}, fmt.Errorf("approved_by is required")
+	}
+	switch w.Type {
+	case "", WaiverTypeVersion, WaiverTypeRollback:
+	default:
+		return "", checker.Waiver{}, fmt.Errorf("invalid type %q: must be %q or %q", w.Type, WaiverTypeVersion, WaiverTypeRollback)
+	}
+	return repoConfig.FullName(), checker.Waiver{
+		Version:    version,
+		Expires:    expires,
+		Reason:     w.Reason,
+		ApprovedBy: w.ApprovedBy,
+		Rollback:   w.Type == WaiverTypeRollback,
+	}, nil
+}
+
+// WaiversFor returns the valid waivers for the repository fullName (owner/repo)
+func WaiversFor(waivers []FileWaiver, fullName string) []checker.Waiver {
+	var matched []checker.Waiver
+	for _, w := range waivers {
+		repository, waiver, err := w.resolve()
+		if err == nil && strings.EqualFold(repository, fullName) {
+			matched = append(matched, waiver)
+		}
+	}
+	return matched
+}
+
+// resolve validates the entry, returning the repository's full name and the
+// yanked release for the checker
+func (y FileYanked) resolve() (string, checker.YankedRelease, error) {
+	repoConfig, err := resolveRepo(y.Repo)
+	if err != nil {
+		return "", checker.YankedRelease{}, err
+	}
+	if y.Version == "" {
+		return "", checker.YankedRelease{}, fmt.Errorf("version is required")
+	}
+	version, err := semver.NewVersion(y.Version)
+	if err != nil {
+		return "", checker.YankedRelease{}, fmt.Errorf("invalid version %q: %w", y.Version, err)
+	}
+	if strings.TrimSpace(y.Reason) == "" {
+		return "", checker.YankedRelease{}, fmt.Errorf("reason is required")
+	}
+	return repoConfig.FullName(), checker.YankedRelease{Version: version, Reason: y.Reason}, nil
+}
+
+// YankedFor returns the valid yanked releases for the repository fullName (owner/repo)
+func YankedFor(yanked []FileYanked, fullName string) []checker.YankedRelease {
+	var matched []checker.YankedRelease
+	for _, y := range yanked {
+		repository, release, err := y.resolve()
+		if err == nil && strings.EqualFold(repository, fullName) {
+			matched = append(matched, release)
+		}
+	}
+	return matched
+}
+
+// fileHeader documents the config file format at the top of files written by Marshal
+const fileHeader = `# github-release-version-checker configuration
+#
+# repositories: repositories to check. repo is a predefined name (runner, k8s,
+#   node, pulumi), owner/repo or a GitHub URL. version is the version you run.
+#   policy (days or versions) and its thresholds override the repository defaults.
+#   latest_from: highest (default) or marked, for repositories whose release marked
+#   latest on GitHub is not always the highest version. ordering: semver (default)
+#   or date, so releases published before yours (backports) do not count as newer.
+#   zero_major: minor-breaking (default; 0.x minor bumps are breaking) or semver.
+#   maintenance_window: when updates roll out, e.g. "first tuesday monthly" or
+#   "every wednesday", so days policies report the last window before expiry.
+#   upstream: for forks and mirrors, the repository (predefined name or owner/repo)
+#   whose releases and policy are used, while results are reported under repo.
+#   version_suffix: regular expression for a fork-specific suffix stripped from
+#   version before comparing, e.g. '-corp\.\d+'.
+#   required_assets: platforms, e.g. [linux-x64, win-x64], the recommended release
+#   must have assets for; releases still missing them are not recommended.
+#   platform: OS/ARCH, e.g. linux/arm64, the recommended release must have shipped
+#   assets for; releases that never did are not recommended.
+#   min_release_age: days a release must have been out before it is recommended.
+# token.source: auto (flag, GH_TOKEN/GITHUB_TOKEN, gh, .netrc), env (token.env variable),
+#   gh (GitHub CLI) or none (unauthenticated, 60 requests per hour).
+# notifications: CI annotation levels per status (notice, warning, error, none)
+#   and job summary sections to omit (header, table, action, updates, timestamp).
+#   notify_template: Go template for --notify-webhook messages.
+# waivers: approved exemptions, each with repo, version, expires (YYYY-MM-DD),
+#   reason and approved_by. An expired version with a waiver is reported as a
+#   warning until the waiver expires; waivers are shown in every output.
+#   type: rollback instead names a newer release upstream is rolling back, which
+#   is set aside until the waiver expires, so it escalates no status meanwhile.
+# yanked: known-bad releases, each with repo, version and reason. They are never
+#   recommended, and running one is reported as a warning.
+# note_keywords: regular expressions looked for, case-insensitively, in the notes
+#   of newer releases, each with a pattern, an optional name and escalate (warning,
+#   critical or expired), the status a match raises the result to. Repositories
+#   can add their own note_keywords.
+#
+# Command-line flags override these settings.
+
+`
+
+// Marshal renders the config as commented YAML
+func (f *File) Marshal() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(fileHeader)
+
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(f); err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,286 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFileRoundTrip(t *testing.T) {
+	f := &File{
+		Repositories: []FileRepository{
+			{Repo: "runner", Version: "2.328.0", MaxDays: 14, CriticalDays: 7},
+			{Repo: "kubernetes/kubernetes", Version: "1.31.0"},
+		},
+		Token:         TokenSettings{Source: TokenSourceEnv, Env: "RELEASE_TOKEN"},
+		Notifications: NotificationSettings{AnnotationLevels: map[string]string{"expired": "warning"}},
+	}
+
+	content, err := f.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if !strings.HasPrefix(string(content), "# github-release-version-checker configuration") {
+		t.Error("expected commented header")
+	}
+
+	path := filepath.Join(t.TempDir(), DefaultFileName)
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	if len(loaded.Repositories) != 2 || loaded.Repositories[0].MaxDays != 14 {
+		t.Errorf("unexpected repositories: %+v", loaded.Repositories)
+	}
+	if loaded.Token.Env != "RELEASE_TOKEN" || loaded.Notifications.AnnotationLevels["expired"] != "warning" {
+		t.Errorf("unexpected settings: %+v", loaded)
+	}
+}
+
+func TestLoadFile_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "unknown key", content: "repositories:\n  - repo: runner\n    max_day: 10\n", wantErr: "max_day"},
+		{name: "bad repository", content: "repositories:\n  - repo: not-a-repo\n", wantErr: "repositories[0]"},
+		{name: "bad policy", content: "repositories:\n  - repo: runner\n    policy: weeks\n", wantErr: "invalid policy"},
+		{name: "env source without variable", content: "token:\n  source: env\n", wantErr: "token.env is required"},
+		{name: "bad latest_from", content: "repositories:\n  - repo: runner\n    latest_from: newest\n", wantErr: "invalid latest_from"},
+		{name: "bad track", content: "repositories:\n  - repo: runner\n    track: nightly\n", wantErr: "invalid track"},
+		{name: "bad channel pattern", content: "repositories:\n  - repo: runner\n    channels:\n      beta: '('\n", wantErr: "invalid pattern for channel beta"},
+		{name: "track and channel", content: "repositories:\n  - repo: runner\n    track: prerelease\n    channel: rc\n", wantErr: "cannot both be set"},
+		{name: "bad ordering", content: "repositories:\n  - repo: runner\n    ordering: alphabetical\n", wantErr: "invalid ordering"},
+		{name: "bad zero_major", content: "repositories:\n  - repo: runner\n    zero_major: loose\n", wantErr: "invalid zero_major"},
+		{name: "bad maintenance_window", content: "repositories:\n  - repo: runner\n    maintenance_window: fortnightly\n", wantErr: "invalid maintenance window"},
+		{name: "bad upstream", content: "repositories:\n  - repo: mycorp/runner-fork\n    upstream: not-a-repo\n", wantErr: "invalid upstream"},
+		{name: "bad version_suffix", content: "repositories:\n  - repo: mycorp/runner-fork\n    version_suffix: \"-corp(\"\n", wantErr: "invalid version suffix"},
+		{name: "bad thresholds", content: "repositories:\n  - repo: runner\n    critical_days: 40\n", wantErr: "must be less than"},
+		{name: "waiver without reason", content: "waivers:\n  - repo: runner\n    version: 2.328.0\n    expires: 2025-12-31\n    approved_by: ops\n", wantErr: "waivers[0]: reason is required"},
+		{name: "bad waiver type", content: "waivers:\n  - repo: runner\n    version: 2.328.0\n    expires: 2025-12-31\n    reason: freeze\n    approved_by: ops\n    type: permanent\n", wantErr: "invalid type \"permanent\""},
+		{name: "waiver without approver", content: "waivers:\n  - repo: runner\n    version: 2.328.0\n    expires: 2025-12-31\n    reason: freeze\n", wantErr: "approved_by is required"},
+		{name: "bad waiver expiry", content: "waivers:\n  - repo: runner\n    version: 2.328.0\n    expires: 31/12/2025\n    reason: freeze\n    approved_by: ops\n", wantErr: "invalid expires"},
+		{name: "yanked without reason", content: "yanked:\n  - repo: runner\n    version: 2.329.0\n", wantErr: "yanked[0]: reason is required"},
+		{name: "bad yanked version", content: "yanked:\n  - repo: runner\n    version: latest\n    reason: regression\n", wantErr: "invalid version"},
+		{name: "bad host", content: "repositories:\n  - repo: tools/runner\n    host: https://github.example.com\n", wantErr: "invalid host"},
+		{name: "conflicting host", content: "repositories:\n  - repo: github.example.com/tools/runner\n    host: ghe.internal\n", wantErr: "does not match"},
+		{name: "host without name", content: "hosts:\n  - api_url: https://github.example.com/api/v3/\n", wantErr: "hosts[0]: name is required"},
+		{name: "github.com host", content: "hosts:\n  - name: github.com\n", wantErr: "top-level token"},
+		{name: "duplicate host", content: "hosts:\n  - name: github.example.com\n  - name: github.example.com\n", wantErr: "listed more than once"},
+		{name: "bad api_url", content: "hosts:\n  - name: github.example.com\n    api_url: github.example.com/api/v3\n", wantErr: "invalid api_url"},
+		{name: "host env source without variable", content: "hosts:\n  - name: github.example.com\n    token:\n      source: env\n", wantErr: "hosts[0]: token.env is required"},
+		{name: "bad waiver version", content: "waivers:\n  - repo: runner\n    version: latest\n    expires: 2025-12-31\n    reason: freeze\n    approved_by: ops\n", wantErr: "invalid version"},
+		{name: "note keyword without pattern", content: "note_keywords:\n  - name: security\n", wantErr: "note_keywords[0]: pattern is required"},
+		{name: "bad note keyword pattern", content: "repositories:\n  - repo: runner\n    note_keywords:\n      - pattern: \"deprecat(\"\n", wantErr: "note_keywords[0]: invalid pattern"},
+		{name: "bad note keyword escalation", content: "note_keywords:\n  - pattern: security\n    escalate: current\n", wantErr: "invalid escalate \"current\""},
+		{name: "empty required asset", content: "repositories:\n  - repo: runner\n    required_assets: [linux-x64, \"\"]\n", wantErr: "empty platform"},
+		{name: "bad platform", content: "repositories:\n  - repo: runner\n    platform: linux-arm64\n", wantErr: "invalid platform"},
+		{name: "bad unknown_version", content: "repositories:\n  - repo: runner\n    unknown_version: ignore\n", wantErr: "invalid unknown_version"},
+		{name: "negative min_release_age", content: "repositories:\n  - repo: runner\n    min_release_age: -3\n", wantErr: "min_release_age must be non-negative"},
+		{name: "negative vulnerabilities", content: "repositories:\n  - repo: runner\n    vulnerabilities: -1\n", wantErr: "vulnerabilities must be non-negative"},
+		{name: "bad alert status", content: "notifications:\n  alerts:\n    current: notify\n", wantErr: "notifications.alerts: invalid status \"current\""},
+		{name: "bad alert action", content: "repositories:\n  - repo: runner\n    alerts:\n      expired: email\n", wantErr: "repositories[0].alerts.expired: invalid action \"email\""},
+		{name: "ignore with other actions", content: "notifications:\n  alerts:\n    warning: [ignore, notify]\n", wantErr: "ignore cannot be combined"},
+		{name: "issue alert without issue_repo", content: "repositories:\n  - repo: runner\n    alerts:\n      expired: [page, issue]\n", wantErr: "issue_repo is required"},
+		{name: "bad issue_repo", content: "notifications:\n  issue_repo: platform\n", wantErr: "notifications.issue_repo"},
+		{name: "bad page_url", content: "notifications:\n  page_url: events.pagerduty.com\n", wantErr: "invalid notifications.page_url"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), DefaultFileName)
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := LoadFile(path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadFile() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFileRepository_RepositoryConfig(t *testing.T) {
+	repoConfig, err := FileRepository{Repo: "runner", MaxDays: 14, CriticalDays: 7}.RepositoryConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repoConfig.MaxDays != 14 || repoConfig.CriticalDays != 7 {
+		t.Errorf("overrides not applied: %+v", repoConfig)
+	}
+	if ConfigActionsRunner.MaxDays != 30 {
+		t.Error("predefined config was modified")
+	}
+
+	// Switching a versions repository to days picks up default thresholds
+	repoConfig, err = FileRepository{Repo: "owner/tool", Policy: "days"}.RepositoryConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repoConfig.PolicyType != PolicyTypeDays || repoConfig.MaxDays != 30 || repoConfig.CriticalDays != 12 {
+		t.Errorf("expected default days thresholds, got %+v", repoConfig)
+	}
+
+	repoConfig, err = FileRepository{Repo: "owner/tool", LatestFrom: "marked", Ordering: "date", Track: "prerelease", UnknownVersion: "warning", Platform: "Linux/aarch64"}.RepositoryConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repoConfig.LatestFrom != "marked" || repoConfig.Ordering != "date" || repoConfig.Track != "prerelease" {
+		t.Errorf("LatestFrom, Ordering, Track = %q, %q, %q, want marked, date, prerelease", repoConfig.LatestFrom, repoConfig.Ordering, repoConfig.Track)
+	}
+	if repoConfig.UnknownVersion != "warning" {
+		t.Errorf("UnknownVersion = %q, want warning", repoConfig.UnknownVersion)
+	}
+	if repoConfig.Platform != "linux/arm64" {
+		t.Errorf("Platform = %q, want linux/arm64", repoConfig.Platform)
+	}
+
+	repoConfig, err = FileRepository{Repo: "kubernetes", Channel: "Beta", Channels: map[string]string{"rc": `-rc\.`, "beta": `-beta\.`}}.RepositoryConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if patterns := repoConfig.ChannelPatterns(); repoConfig.Channel != "beta" || len(patterns) != 2 || patterns[0].Name != "beta" {
+		t.Errorf("Channel, ChannelPatterns() = %q, %+v; want beta and patterns in name order", repoConfig.Channel, patterns)
+	}
+
+	repoConfig, err = FileRepository{Repo: "runner", MaintenanceWindow: "first tuesday monthly"}.RepositoryConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repoConfig.MaintenanceWindow != "first tuesday monthly" {
+		t.Errorf("MaintenanceWindow = %q, want first tuesday monthly", repoConfig.MaintenanceWindow)
+	}
+
+	// A repository on a GitHub Enterprise Server can name its host separately
+	repoConfig, err = FileRepository{Repo: "tools/runner", Host: "github.example.com"}.RepositoryConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repoConfig.SourceName() != "github.example.com/tools/runner" {
+		t.Errorf("SourceName() = %q, want github.example.com/tools/runner", repoConfig.SourceName())
+	}
+
+	// Policy overrides apply on top of the upstream's policy
+	repoConfig, err = FileRepository{Repo: "mycorp/runner-fork", Upstream: "runner", VersionSuffix: `-corp\.\d+`, MaxDays: 21}.RepositoryConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repoConfig.FullName() != "mycorp/runner-fork" || repoConfig.Upstream != "actions/runner" || repoConfig.MaxDays != 21 || repoConfig.VersionSuffix != `-corp\.\d+` {
+		t.Errorf("unexpected fork config: %+v", repoConfig)
+	}
+}
+
+func TestFile_Hosts(t *testing.T) {
+	f := &File{
+		Token: TokenSettings{Source: TokenSourceEnv, Env: "GITHUB_TOKEN"},
+		Hosts: []HostSettings{
+			{Name: "github.example.com", Token: TokenSettings{Source: TokenSourceEnv, Env: "GHE_TOKEN"}},
+			{Name: "ghe.internal", APIURL: "https://api.ghe.internal/"},
+		},
+	}
+
+	tests := []struct {
+		host      string
+		wantURL   string
+		wantToken TokenSettings
+	}{
+		{host: "", wantToken: f.Token},
+		{host: DefaultHost, wantToken: f.Token},
+		{host: "github.example.com", wantURL: "https://github.example.com/api/v3/", wantToken: TokenSettings{Source: TokenSourceEnv, Env: "GHE_TOKEN"}},
+		{host: "ghe.internal", wantURL: "https://api.ghe.internal/"},
+		{host: "unlisted.example.com", wantURL: "https://unlisted.example.com/api/v3/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			if got := f.APIURL(tt.host); got != tt.wantURL {
+				t.Errorf("APIURL() = %q, want %q", got, tt.wantURL)
+			}
+			if got := f.TokenFor(tt.host); got != tt.wantToken {
+				t.Errorf("TokenFor() = %+v, want %+v", got, tt.wantToken)
+			}
+		})
+	}
+
+	// Without a config file, only the API URL convention applies
+	var none *File
+	if got := none.APIURL("github.example.com"); got != "https://github.example.com/api/v3/" {
+		t.Errorf("nil APIURL() = %q", got)
+	}
+	if got := none.TokenFor(DefaultHost); got != (TokenSettings{}) {
+		t.Errorf("nil TokenFor() = %+v, want auto", got)
+	}
+}
+
+func TestWaiversFor(t *testing.T) {
+	waivers := []FileWaiver{
+		{Repo: "runner", Version: "2.328.0", Expires: "2025-12-31", Reason: "freeze", ApprovedBy: "ops"},
+		{Repo: "https://github.com/Actions/Runner", Version: "2.327.1", Expires: "2025-11-30", Reason: "pinned", ApprovedBy: "sec"},
+		{Repo: "runner", Version: "2.330.0", Expires: "2025-11-07", Reason: "upstream rollback", ApprovedBy: "ops", Type: WaiverTypeRollback},
+		{Repo: "k8s", Version: "1.31.0", Expires: "2025-12-31", Reason: "upgrade planned", ApprovedBy: "platform"},
+	}
+
+	got := WaiversFor(waivers, "actions/runner")
+	if len(got) != 3 {
+		t.Fatalf("WaiversFor() returned %d waivers, want 3", len(got))
+	}
+	if got[0].Version.String() != "2.328.0" || got[0].ApprovedBy != "ops" || got[0].Rollback {
+		t.Errorf("first waiver = %+v", got[0])
+	}
+	if want := time.Date(2025, 11, 30, 0, 0, 0, 0, time.UTC); !got[1].Expires.Equal(want) {
+		t.Errorf("Expires = %s, want %s", got[1].Expires, want)
+	}
+	if !got[2].Rollback {
+		t.Errorf("third waiver = %+v, want a rollback", got[2])
+	}
+	if got := WaiversFor(waivers, "nodejs/node"); len(got) != 0 {
+		t.Errorf("WaiversFor(nodejs/node) = %+v, want none", got)
+	}
+}
+
+func TestNoteKeywordsFor(t *testing.T) {
+	repoConfig, err := FileRepository{
+		Repo:         "runner",
+		NoteKeywords: []FileNoteKeyword{{Pattern: "deprecat"}},
+	}.RepositoryConfig()
+	if err != nil {
+		t.Fatalf("RepositoryConfig() error = %v", err)
+	}
+	shared := []FileNoteKeyword{
+		{Name: "security", Pattern: "security|CVE-", Escalate: "Critical"},
+		{Pattern: "broken("}, // Invalid: skipped
+	}
+
+	got := NoteKeywordsFor(shared, repoConfig)
+	if len(got) != 2 {
+		t.Fatalf("NoteKeywordsFor() returned %d keywords, want 2", len(got))
+	}
+	if got[0].Name != "security" || got[0].Escalate != "critical" || !got[0].Pattern.MatchString("fixes cve-2026-1234") {
+		t.Errorf("first keyword = %+v, want security escalating to critical, matching case-insensitively", got[0])
+	}
+	if got[1].Name != "deprecat" || got[1].Escalate != "" {
+		t.Errorf("second keyword = %+v, want the repository's own, named by its pattern", got[1])
+	}
+}
+
+func TestYankedFor(t *testing.T) {
+	yanked := []FileYanked{
+		{Repo: "runner", Version: "2.329.0", Reason: "jobs hang on Windows"},
+		{Repo: "k8s", Version: "1.31.0", Reason: "etcd regression"},
+		{Repo: "runner", Version: "2.328.0"}, // Invalid: no reason
+	}
+
+	got := YankedFor(yanked, "Actions/Runner")
+	if len(got) != 1 || got[0].Version.String() != "2.329.0" || got[0].Reason != "jobs hang on Windows" {
+		t.Errorf("YankedFor() = %+v", got)
+	}
+	if got := YankedFor(yanked, "nodejs/node"); len(got) != 0 {
+		t.Errorf("YankedFor(nodejs/node) = %+v, want none", got)
+	}
+}
@@ -0,0 +1,63 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/nickromney-org/github-release-version-checker/pkg/checker"
+)
+
+// FileNoteKeyword is a keyword looked for in the notes of newer releases, e.g.
+// security fixes, that can raise the reported status
+type FileNoteKeyword struct {
+	Name     string `yaml:"name,omitempty"`     // Shown when it matches; default: the pattern
+	Pattern  string `yaml:"pattern"`            // Regular expression, matched case-insensitively
+	Escalate string `yaml:"escalate,omitempty"` // warning, critical or expired; empty only flags the match
+}
+
+// resolve validates the keyword and compiles it for the checker
+func (k FileNoteKeyword) resolve() (checker.NoteKeyword, error) {
+	if k.Pattern == "" {
+		return checker.NoteKeyword{}, fmt.Errorf("pattern is required")
+	}
+	re, err := regexp.Compile("(?i)" + k.Pattern)
+	if err != nil {
+		return checker.NoteKeyword{}, fmt.Errorf("invalid pattern %q: %w", k.Pattern, err)
+	}
+	keyword := checker.NoteKeyword{
+		Name:     k.Name,
+		Pattern:  re,
+		Escalate: checker.Status(strings.ToLower(k.Escalate)),
+	}
+	if keyword.Name == "" {
+		keyword.Name = k.Pattern
+	}
+	switch keyword.Escalate {
+	case "", checker.StatusWarning, checker.StatusCritical, checker.StatusExpired:
+	default:
+		return checker.NoteKeyword{}, fmt.Errorf("invalid escalate %q: must be 'warning', 'critical' or 'expired'", k.Escalate)
+	}
+	return keyword, nil
+}
+
+// validateNoteKeywords checks each keyword, naming the first invalid one by field
+func validateNoteKeywords(field string, keywords []FileNoteKeyword) error {
+	for i, k := range keywords {
+		if _, err := k.resolve(); err != nil {
+			return fmt.Errorf("%s[%d]: %w", field, i, err)
+		}
+	}
+	return nil
+}
+
+// NoteKeywordsFor returns the valid shared keywords followed by the repository's own
+func NoteKeywordsFor(shared []FileNoteKeyword, repoConfig *RepositoryConfig) []checker.NoteKeyword {
+	var keywords []checker.NoteKeyword
+	for _, k := range append(append([]FileNoteKeyword(nil), shared...), repoConfig.NoteKeywords...) {
+		if keyword, err := k.resolve(); err == nil {
+			keywords = append(keywords, keyword)
+		}
+	}
+	return keywords
+}
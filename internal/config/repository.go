@@ -2,7 +2,13 @@ package config
 
 import (
 	"fmt"
+	"regexp"
+	"sort"
 	"strings"
+
+	"github.com/nickromney-org/github-release-version-checker/pkg/checker"
+	"github.com/nickromney-org/github-release-version-checker/pkg/policy"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
 )
 
 // PolicyType defines the type of expiry policy
@@ -11,6 +17,7 @@ type PolicyType string
 const (
 	PolicyTypeDays     PolicyType = "days"     // Time-based: expires after N days
 	PolicyTypeVersions PolicyType = "versions" // Version-based: expires after N minor versions
+	PolicyTypeLTS      PolicyType = "lts"      // Schedule-based: expires when the major line reaches end of life
 )
 
 // RepositoryConfig defines a GitHub repository and its version policy
@@ -18,11 +25,77 @@ type RepositoryConfig struct {
 	Owner string // GitHub owner (e.g., "actions", "kubernetes")
 	Repo  string // GitHub repo (e.g., "runner", "kubernetes")
 
+	// GitHub Enterprise Server host the repository lives on, e.g.
+	// "github.example.com"; empty for github.com
+	Host string
+
 	// Policy configuration
 	PolicyType        PolicyType
 	CriticalDays      int // For PolicyTypeDays
 	MaxDays           int // For PolicyTypeDays
-	MaxVersionsBehind int // For PolicyTypeVersions
+	MaxVersionsBehind int // For PolicyTypeVersions, and PolicyTypeLTS without a schedule
+
+	// For PolicyTypeLTS: the release schedule, in the format of the nodejs/Release
+	// repository's schedule.json
+	ScheduleURL string
+
+	// Which release is latest when GitHub marks one other than the highest
+	// version: "highest" (default when empty) or "marked"
+	LatestFrom string
+
+	// Which releases are newer than the version in use: "semver" (default when
+	// empty) or "date", for repositories that backport onto older branches
+	Ordering string
+
+	// Which releases can be the latest: "stable" (default when empty) or
+	// "prerelease", for early adopters tracking release candidates
+	Track string
+
+	// For repositories releasing parallel channels: the release channel checked,
+	// e.g. "beta" (empty follows Track), and tag patterns, as regular
+	// expressions by channel name, for tags the prerelease part misfiles
+	Channel  string
+	Channels map[string]string
+
+	// How versions policies treat 0.x versions: "minor-breaking" (default when
+	// empty; minor bumps are breaking) or "semver"
+	ZeroMajor string
+
+	// When updates are rolled out, e.g. "first tuesday monthly", so days
+	// policies report the last window before expiry; empty for any day
+	MaintenanceWindow string
+
+	// For forks and mirrors: the repository (owner/repo) whose releases are
+	// checked while results are reported under this one; empty for this one
+	Upstream     string
+	UpstreamHost string // Host of the upstream; empty for github.com
+
+	// Fork-specific suffix stripped from compared versions before comparing with
+	// the upstream's releases, and from release tags, as a regular expression,
+	// e.g. `-corp\.\d+`
+	VersionSuffix string
+
+	// Keywords looked for in the notes of newer releases, added to any shared ones
+	NoteKeywords []FileNoteKeyword
+
+	// Platforms the recommended release must have an asset for, matched as part
+	// of asset names, e.g. "linux-x64"; empty skips the check
+	RequiredAssets []string
+
+	// Platform the recommended release must have shipped for, as OS/ARCH, e.g.
+	// "linux/arm64"; empty for any
+	Platform string
+
+	// Days a release must have been out before it is recommended; 0 for none
+	MinReleaseAgeDays int
+
+	// What a version in use that is not a release, such as a custom build,
+	// results in: "error" (default when empty), "expired" or "warning"
+	UnknownVersion string
+
+	// Known vulnerabilities in the version in use that updating fixes, from a
+	// scanner; counted in the drift score
+	Vulnerabilities int
 
 	// Cache configuration
 	CachePath    string // Path to embedded cache file
@@ -62,13 +135,32 @@ var (
 	ConfigNodeJS = RepositoryConfig{
 		Owner:             "nodejs",
 		Repo:              "node",
-		PolicyType:        PolicyTypeVersions,
-		MaxVersionsBehind: 3, // Support last 3 major versions (e.g., Current + 2 LTS)
+		PolicyType:        PolicyTypeLTS,
+		CriticalDays:      90,
+		MaxVersionsBehind: 3, // Without the schedule: last 3 major versions (e.g., Current + 2 LTS)
+		ScheduleURL:       "https://raw.githubusercontent.com/nodejs/Release/main/schedule.json",
 		CachePath:         "data/nodejs.json",
 		CacheEnabled:      false, // Will be enabled when cache is created
 	}
+
+	// Go has no GitHub releases; actions/go-versions publishes one per Go
+	// release for setup-go, tagged with a build suffix
+	ConfigGo = RepositoryConfig{
+		Owner:             "actions",
+		Repo:              "go-versions",
+		PolicyType:        PolicyTypeVersions,
+		MaxVersionsBehind: 2, // Each release is supported until two newer ones are out
+		VersionSuffix:     `-\d+`,
+		CachePath:         "data/go.json",
+		CacheEnabled:      false, // Will be enabled when cache is created
+	}
 )
 
+// PredefinedConfigs returns each predefined config once, without aliases
+func PredefinedConfigs() []RepositoryConfig {
+	return []RepositoryConfig{ConfigActionsRunner, ConfigKubernetes, ConfigPulumi, ConfigNodeJS, ConfigGo}
+}
+
 // GetPredefinedConfig returns a predefined config by name
 func GetPredefinedConfig(name string) (*RepositoryConfig, error) {
 	configs := map[string]RepositoryConfig{
@@ -80,6 +172,8 @@ func GetPredefinedConfig(name string) (*RepositoryConfig, error) {
 		"pulumi":         ConfigPulumi,
 		"nodejs":         ConfigNodeJS,
 		"node":           ConfigNodeJS, // Alias
+		"go":             ConfigGo,
+		"golang":         ConfigGo, // Alias
 	}
 
 	config, ok := configs[strings.ToLower(name)]
@@ -89,54 +183,218 @@ func GetPredefinedConfig(name string) (*RepositoryConfig, error) {
 	return &config, nil
 }
 
-// ParseRepositoryString parses "owner/repo" format or URL
+// DefaultHost is the host repositories live on unless another is named
+const DefaultHost = "github.com"
+
+// ParseRepositoryString parses "owner/repo" format or URL. Repositories on a
+// GitHub Enterprise Server are named with their host, as
+// "github.example.com/owner/repo" or a URL.
 func ParseRepositoryString(repoStr string) (*RepositoryConfig, error) {
-	// Check if it's a GitHub URL
-	if strings.Contains(repoStr, "github.com") {
-		// Extract owner/repo from URL
-		// https://github.com/owner/repo -> owner/repo
-		parts := strings.Split(repoStr, "github.com/")
-		if len(parts) == 2 {
-			repoStr = strings.TrimSuffix(parts[1], "/")
-			repoStr = strings.Split(repoStr, "/releases")[0]
-			repoStr = strings.Split(repoStr, "/tags")[0]
-		}
-	}
+	host, repoStr := splitHost(repoStr)
 
 	// Parse as owner/repo
 	parts := strings.Split(repoStr, "/")
-	if len(parts) != 2 {
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
 		return nil, fmt.Errorf("invalid repository format: %s (expected: owner/repo)", repoStr)
 	}
 
 	// Check if this matches a predefined config
 	fullName := fmt.Sprintf("%s/%s", parts[0], parts[1])
 
-	// Return predefined config if it exists
-	if fullName == "actions/runner" {
-		return &ConfigActionsRunner, nil
-	}
-	if fullName == "kubernetes/kubernetes" {
-		return &ConfigKubernetes, nil
-	}
-	if fullName == "pulumi/pulumi" {
-		return &ConfigPulumi, nil
-	}
-	if fullName == "nodejs/node" {
-		return &ConfigNodeJS, nil
-	}
-
 	// Default to version-based policy with conservative defaults
-	return &RepositoryConfig{
+	resolved := RepositoryConfig{
 		Owner:             parts[0],
 		Repo:              parts[1],
 		PolicyType:        PolicyTypeVersions,
 		MaxVersionsBehind: 3,
 		CacheEnabled:      false, // No embedded cache for custom repos
-	}, nil
+	}
+
+	// Use the predefined config if one exists; mirrors on other hosts share its policy
+	for _, predefined := range PredefinedConfigs() {
+		if predefined.FullName() == fullName {
+			resolved = predefined
+		}
+	}
+	resolved.Host = host
+	return &resolved, nil
+}
+
+// splitHost separates the host from a repository URL or "host/owner/repo",
+// returning an empty host for github.com
+func splitHost(repoStr string) (string, string) {
+	rest := repoStr
+	if _, after, ok := strings.Cut(rest, "://"); ok {
+		rest = after
+	}
+	host, path, ok := strings.Cut(rest, "/")
+	// Owners cannot contain dots, so a dotted first segment is a host
+	if !ok || !strings.Contains(host, ".") {
+		return "", repoStr
+	}
+
+	// https://github.com/owner/repo/releases/tag/v1 -> owner/repo
+	path = strings.TrimSuffix(path, "/")
+	path = strings.Split(path, "/releases")[0]
+	path = strings.Split(path, "/tags")[0]
+
+	host = strings.ToLower(host)
+	if host == DefaultHost || host == "www."+DefaultHost {
+		host = ""
+	}
+	return host, path
 }
 
 // FullName returns the full repository name (owner/repo)
 func (c *RepositoryConfig) FullName() string {
 	return fmt.Sprintf("%s/%s", c.Owner, c.Repo)
 }
+
+// SetUpstream checks releases of upstream (a predefined name, owner/repo or
+// GitHub URL) instead of this repository's, taking the upstream's policy and
+// cache; set policy overrides afterwards
+func (c *RepositoryConfig) SetUpstream(upstream string) error {
+	resolved, err := GetPredefinedConfig(upstream)
+	if err != nil {
+		if resolved, err = ParseRepositoryString(upstream); err != nil {
+			return fmt.Errorf("invalid upstream: %w", err)
+		}
+	}
+	owner, repo, host := c.Owner, c.Repo, c.Host
+	*c = *resolved
+	c.Owner, c.Repo, c.Host = owner, repo, host
+	c.Upstream, c.UpstreamHost = resolved.FullName(), resolved.Host
+	return nil
+}
+
+// Source returns the owner and repo whose releases are checked: the upstream
+// for forks and mirrors, otherwise this repository
+func (c *RepositoryConfig) Source() (string, string) {
+	if owner, repo, ok := strings.Cut(c.Upstream, "/"); ok {
+		return owner, repo
+	}
+	return c.Owner, c.Repo
+}
+
+// SourceHost returns the host whose API is queried for releases: the
+// upstream's for forks and mirrors, otherwise this repository's
+func (c *RepositoryConfig) SourceHost() string {
+	host := c.Host
+	if c.Upstream != "" {
+		host = c.UpstreamHost
+	}
+	if host == "" {
+		return DefaultHost
+	}
+	return host
+}
+
+// SourceName returns the owner/repo whose releases are checked, prefixed with
+// its host when that is not github.com, e.g. "github.example.com/tools/runner"
+func (c *RepositoryConfig) SourceName() string {
+	owner, repo := c.Source()
+	if host := c.SourceHost(); host != DefaultHost {
+		return host + "/" + owner + "/" + repo
+	}
+	return owner + "/" + repo
+}
+
+// SetVersionSuffix sets the fork-specific version suffix after checking it compiles
+func (c *RepositoryConfig) SetVersionSuffix(pattern string) error {
+	if _, err := compileVersionSuffix(pattern); err != nil {
+		return err
+	}
+	c.VersionSuffix = pattern
+	return nil
+}
+
+// VersionSuffixPattern returns VersionSuffix anchored to the end of a version,
+// or nil when there is none
+func (c *RepositoryConfig) VersionSuffixPattern() *regexp.Regexp {
+	re, err := compileVersionSuffix(c.VersionSuffix)
+	if err != nil {
+		return nil
+	}
+	return re
+}
+
+// SetRequiredAssets sets the platforms recommended releases must have assets for
+func (c *RepositoryConfig) SetRequiredAssets(platforms []string) error {
+	for _, p := range platforms {
+		if strings.TrimSpace(p) == "" {
+			return fmt.Errorf("required assets cannot include an empty platform")
+		}
+	}
+	c.RequiredAssets = platforms
+	return nil
+}
+
+// SetPlatform sets the platform recommended releases must have shipped for,
+// normalised, e.g. "Linux/aarch64" to "linux/arm64"
+func (c *RepositoryConfig) SetPlatform(platform string) error {
+	if platform == "" {
+		c.Platform = ""
+		return nil
+	}
+	normalised, err := types.ParsePlatform(platform)
+	if err != nil {
+		return err
+	}
+	c.Platform = normalised
+	return nil
+}
+
+// SetChannels validates and sets the tag patterns for release channels
+func (c *RepositoryConfig) SetChannels(patterns map[string]string) error {
+	for name, pattern := range patterns {
+		if name == "" {
+			return fmt.Errorf("channel pattern %q has no channel name", pattern)
+		}
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid pattern for channel %s: %w", name, err)
+		}
+	}
+	c.Channels = patterns
+	return nil
+}
+
+// ChannelPatterns returns the channel tag patterns in name order, so the
+// first match is the same on every run
+func (c *RepositoryConfig) ChannelPatterns() []checker.ChannelPattern {
+	names := make([]string, 0, len(c.Channels))
+	for name := range c.Channels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var patterns []checker.ChannelPattern
+	for _, name := range names {
+		re, err := regexp.Compile(c.Channels[name])
+		if err != nil {
+			continue
+		}
+		patterns = append(patterns, checker.ChannelPattern{Name: name, Pattern: re})
+	}
+	return patterns
+}
+
+// compileVersionSuffix anchors pattern to the end of a version; "" gives nil
+func compileVersionSuffix(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	re, err := regexp.Compile(`(?:` + pattern + `)$`)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version suffix %q: %w", pattern, err)
+	}
+	return re, nil
+}
+
+// SetMaintenanceWindow sets the maintenance calendar after checking it parses
+func (c *RepositoryConfig) SetMaintenanceWindow(spec string) error {
+	if _, err := policy.ParseCalendar(spec); err != nil {
+		return err
+	}
+	c.MaintenanceWindow = spec
+	return nil
+}
@@ -68,6 +68,20 @@ func TestGetPredefinedConfig(t *testing.T) {
 			wantRepo:  "node",
 			wantErr:   false,
 		},
+		{
+			name:      "go",
+			input:     "go",
+			wantOwner: "actions",
+			wantRepo:  "go-versions",
+			wantErr:   false,
+		},
+		{
+			name:      "golang alias",
+			input:     "golang",
+			wantOwner: "actions",
+			wantRepo:  "go-versions",
+			wantErr:   false,
+		},
 		{
 			name:    "unknown",
 			input:   "unknown-repo",
@@ -192,6 +206,68 @@ func TestParseRepositoryString(t *testing.T) {
 	}
 }
 
+func TestParseRepositoryString_Host(t *testing.T) {
+	tests := []struct {
+		input          string
+		wantHost       string
+		wantSourceName string
+		wantPolicy     PolicyType
+	}{
+		{input: "owner/repo", wantSourceName: "owner/repo", wantPolicy: PolicyTypeVersions},
+		{input: "github.com/owner/repo", wantSourceName: "owner/repo", wantPolicy: PolicyTypeVersions},
+		{input: "https://www.github.com/owner/repo", wantSourceName: "owner/repo", wantPolicy: PolicyTypeVersions},
+		{input: "github.example.com/tools/node", wantHost: "github.example.com", wantSourceName: "github.example.com/tools/node", wantPolicy: PolicyTypeVersions},
+		{input: "https://GitHub.Example.com/tools/node/releases/tag/v1.0.0", wantHost: "github.example.com", wantSourceName: "github.example.com/tools/node", wantPolicy: PolicyTypeVersions},
+		// A mirror of a predefined repository shares its policy
+		{input: "github.example.com/actions/runner", wantHost: "github.example.com", wantSourceName: "github.example.com/actions/runner", wantPolicy: PolicyTypeDays},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			repoConfig, err := ParseRepositoryString(tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if repoConfig.Host != tt.wantHost {
+				t.Errorf("Host = %q, want %q", repoConfig.Host, tt.wantHost)
+			}
+			if got := repoConfig.SourceName(); got != tt.wantSourceName {
+				t.Errorf("SourceName() = %q, want %q", got, tt.wantSourceName)
+			}
+			if repoConfig.PolicyType != tt.wantPolicy {
+				t.Errorf("PolicyType = %q, want %q", repoConfig.PolicyType, tt.wantPolicy)
+			}
+		})
+	}
+	if ConfigActionsRunner.Host != "" {
+		t.Error("predefined config was modified")
+	}
+}
+
+func TestRepositoryConfig_SourceHost(t *testing.T) {
+	// A mirror on an internal host checks releases on github.com
+	repoConfig, err := ParseRepositoryString("github.example.com/tools/runner")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := repoConfig.SourceHost(); got != "github.example.com" {
+		t.Errorf("SourceHost() = %q, want github.example.com", got)
+	}
+	if err := repoConfig.SetUpstream("runner"); err != nil {
+		t.Fatal(err)
+	}
+	if repoConfig.Host != "github.example.com" || repoConfig.SourceHost() != DefaultHost || repoConfig.SourceName() != "actions/runner" {
+		t.Errorf("after SetUpstream: Host = %q, SourceHost() = %q, SourceName() = %q", repoConfig.Host, repoConfig.SourceHost(), repoConfig.SourceName())
+	}
+
+	// An upstream on another internal host is checked there
+	if err := repoConfig.SetUpstream("ghe.internal/tools/runner"); err != nil {
+		t.Fatal(err)
+	}
+	if got := repoConfig.SourceName(); got != "ghe.internal/tools/runner" {
+		t.Errorf("SourceName() = %q, want ghe.internal/tools/runner", got)
+	}
+}
+
 func TestRepositoryConfig_FullName(t *testing.T) {
 	config := &RepositoryConfig{
 		Owner: "kubernetes",
@@ -206,6 +282,57 @@ func TestRepositoryConfig_FullName(t *testing.T) {
 	}
 }
 
+func TestRepositoryConfig_SetUpstream(t *testing.T) {
+	repoConfig, err := ParseRepositoryString("mycorp/runner-fork")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := repoConfig.SetUpstream("runner"); err != nil {
+		t.Fatalf("SetUpstream() error = %v", err)
+	}
+
+	if repoConfig.FullName() != "mycorp/runner-fork" {
+		t.Errorf("FullName() = %s, want mycorp/runner-fork", repoConfig.FullName())
+	}
+	if owner, repo := repoConfig.Source(); owner != "actions" || repo != "runner" {
+		t.Errorf("Source() = %s/%s, want actions/runner", owner, repo)
+	}
+	if repoConfig.Upstream != "actions/runner" || repoConfig.PolicyType != PolicyTypeDays || repoConfig.MaxDays != 30 {
+		t.Errorf("expected the runner policy, got %+v", repoConfig)
+	}
+	if ConfigActionsRunner.Owner != "actions" {
+		t.Error("predefined config was modified")
+	}
+
+	if err := repoConfig.SetUpstream("not-a-repo"); err == nil {
+		t.Error("expected an error for an invalid upstream")
+	}
+}
+
+func TestRepositoryConfig_VersionSuffix(t *testing.T) {
+	repoConfig := &RepositoryConfig{Owner: "mycorp", Repo: "runner-fork"}
+	if repoConfig.VersionSuffixPattern() != nil {
+		t.Error("expected no pattern without a suffix")
+	}
+	if err := repoConfig.SetVersionSuffix(`-corp\.(`); err == nil {
+		t.Error("expected an error for an invalid pattern")
+	}
+	if err := repoConfig.SetVersionSuffix(`-corp\.\d+`); err != nil {
+		t.Fatalf("SetVersionSuffix() error = %v", err)
+	}
+
+	tests := map[string]string{
+		"2.328.0-corp.3":      "2.328.0",
+		"2.328.0":             "2.328.0",
+		"2.328.0-corp.3-rc.1": "2.328.0-corp.3-rc.1", // Only a trailing suffix is stripped
+	}
+	for input, want := range tests {
+		if got := repoConfig.VersionSuffixPattern().ReplaceAllString(input, ""); got != want {
+			t.Errorf("stripping %q = %q, want %q", input, got, want)
+		}
+	}
+}
+
 func TestPredefinedConfigs(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -232,8 +359,14 @@ func TestPredefinedConfigs(t *testing.T) {
 			wantCache:  false, // Will be enabled in Phase 3.1
 		},
 		{
-			name:       "nodejs uses versions policy",
+			name:       "nodejs uses lts policy",
 			config:     ConfigNodeJS,
+			wantPolicy: PolicyTypeLTS,
+			wantCache:  false, // Will be enabled when cache is created
+		},
+		{
+			name:       "go uses versions policy",
+			config:     ConfigGo,
 			wantPolicy: PolicyTypeVersions,
 			wantCache:  false, // Will be enabled when cache is created
 		},
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/Masterminds/semver/v3"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
 )
 
 //go:embed releases.json
@@ -16,14 +17,16 @@ type Release struct {
 	Version     *semver.Version
 	PublishedAt time.Time
 	URL         string
+	Prerelease  bool
 }
 
 type CachedReleases struct {
 	GeneratedAt time.Time `json:"generated_at"`
 	Releases    []struct {
-		Version     string    `json:"version"`
-		PublishedAt time.Time `json:"published_at"`
-		URL         string    `json:"url"`
+		Version      string    `json:"version"`
+		PublishedAt  time.Time `json:"published_at"`
+		URL          string    `json:"url"`
+		IsPrerelease bool      `json:"is_prerelease"`
 	} `json:"releases"`
 }
 
@@ -36,7 +39,7 @@ func LoadEmbeddedReleases() ([]Release, error) {
 
 	releases := make([]Release, 0, len(cached.Releases))
 	for _, r := range cached.Releases {
-		ver, err := semver.NewVersion(r.Version)
+		ver, err := types.ParseVersion(r.Version)
 		if err != nil {
 			// Skip invalid versions
 			continue
@@ -45,8 +48,18 @@ func LoadEmbeddedReleases() ([]Release, error) {
 			Version:     ver,
 			PublishedAt: r.PublishedAt,
 			URL:         r.URL,
+			Prerelease:  r.IsPrerelease,
 		})
 	}
 
 	return releases, nil
 }
+
+// EmbeddedGeneratedAt returns when the embedded releases were generated
+func EmbeddedGeneratedAt() (time.Time, error) {
+	var cached CachedReleases
+	if err := json.Unmarshal(releasesJSON, &cached); err != nil {
+		return time.Time{}, err
+	}
+	return cached.GeneratedAt, nil
+}
@@ -26,3 +26,13 @@ func TestLoadEmbeddedReleases(t *testing.T) {
 		t.Error("first release has empty URL")
 	}
 }
+
+func TestEmbeddedGeneratedAt(t *testing.T) {
+	generatedAt, err := EmbeddedGeneratedAt()
+	if err != nil {
+		t.Fatalf("EmbeddedGeneratedAt failed: %v", err)
+	}
+	if generatedAt.IsZero() {
+		t.Error("expected generated_at to be set")
+	}
+}
@@ -1,3 +1,7 @@
+// Package github is the original GitHub client, kept while callers move to
+// pkg/client.
+//
+// Deprecated: use pkg/client.
 package github
 
 import (
@@ -12,6 +16,8 @@ import (
 )
 
 // Client wraps the GitHub API client
+//
+// Deprecated: use client.Client.
 type Client struct {
 	gh    *gh.Client
 	Owner string
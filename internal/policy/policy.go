@@ -10,9 +10,21 @@ import (
 func NewPolicy(repoConfig *config.RepositoryConfig) policy.VersionPolicy {
 	switch repoConfig.PolicyType {
 	case config.PolicyTypeDays:
-		return policy.NewDaysPolicy(repoConfig.CriticalDays, repoConfig.MaxDays)
+		p := policy.NewDaysPolicy(repoConfig.CriticalDays, repoConfig.MaxDays)
+		if repoConfig.MaintenanceWindow != "" {
+			// Validated when the config was loaded
+			p.Calendar, _ = policy.ParseCalendar(repoConfig.MaintenanceWindow)
+		}
+		return p
 	case config.PolicyTypeVersions:
-		return policy.NewVersionsPolicy(repoConfig.MaxVersionsBehind)
+		p := policy.NewVersionsPolicy(repoConfig.MaxVersionsBehind)
+		p.ZeroMajor = policy.ZeroMajor(repoConfig.ZeroMajor)
+		return p
+	case config.PolicyTypeLTS:
+		// The schedule is fetched by the caller; until then versions are counted
+		p := policy.NewLTSPolicy(nil, repoConfig.CriticalDays)
+		p.Fallback = policy.NewVersionsPolicy(repoConfig.MaxVersionsBehind)
+		return p
 	default:
 		// Default to days-based
 		return policy.NewDaysPolicy(12, 30)
@@ -0,0 +1,76 @@
+package proxy
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// recorder captures the status and size of a response for the access log
+type recorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (r *recorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += n
+	return n, err
+}
+
+// endpoint names what a request path asks for: repository, releases, latest,
+// batch or probe, or "" when the proxy does not serve it
+func endpoint(path string) string {
+	switch path {
+	case "/healthz", "/readyz":
+		return "probe"
+	case BatchPath:
+		return "batch"
+	case WebhookPath:
+		return "webhook"
+	}
+	if _, ok := routePath(path); !ok {
+		return ""
+	}
+	switch {
+	case strings.HasSuffix(strings.TrimRight(path, "/"), "/releases/latest"):
+		return "latest"
+	case strings.HasSuffix(strings.TrimRight(path, "/"), "/releases"):
+		return "releases"
+	}
+	return "repository"
+}
+
+// logAccess logs one request served, with its repository, cache state and latency
+func (s *Server) logAccess(r *http.Request, rec *recorder, latency time.Duration) {
+	repository, _ := routePath(r.URL.Path)
+	attrs := []slog.Attr{
+		slog.String("method", r.Method),
+		slog.String("path", r.URL.Path),
+		slog.String("endpoint", endpoint(r.URL.Path)),
+		slog.Int("status", rec.status),
+		slog.Int("bytes", rec.bytes),
+		slog.Float64("latency_ms", float64(latency.Microseconds())/1000),
+		slog.String("remote", r.RemoteAddr),
+	}
+	if repository != "" {
+		attrs = append(attrs, slog.String("repo", repository))
+	}
+	if r.URL.RawQuery != "" {
+		attrs = append(attrs, slog.String("query", r.URL.RawQuery))
+	}
+	if state := rec.Header().Get("X-Cache"); state != "" {
+		attrs = append(attrs, slog.String("cache", state))
+	}
+	if agent := r.UserAgent(); agent != "" {
+		attrs = append(attrs, slog.String("user_agent", agent))
+	}
+	s.Logger.LogAttrs(r.Context(), slog.LevelInfo, "request", attrs...)
+}
@@ -0,0 +1,90 @@
+package proxy
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"testing"
+)
+
+// syncBuffer is a bytes.Buffer safe for the server's goroutines to log to
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func TestServer_AccessLog(t *testing.T) {
+	_, proxy, _ := newTestProxy(t)
+	var logs syncBuffer
+	proxy.Config.Handler.(*Server).Logger = slog.New(slog.NewJSONHandler(&logs, nil))
+
+	get(t, proxy.URL+"/repos/actions/runner/releases?per_page=5", nil)
+	get(t, proxy.URL+"/repos/actions/runner/releases?per_page=5", nil)
+	get(t, proxy.URL+"/repos/actions/runner/releases/latest", nil)
+	get(t, proxy.URL+"/user", nil)
+	get(t, proxy.URL+"/healthz", nil)
+
+	want := []map[string]any{
+		{"repo": "actions/runner", "endpoint": "releases", "status": 200.0, "cache": cacheMiss, "query": "per_page=5"},
+		{"repo": "actions/runner", "endpoint": "releases", "status": 200.0, "cache": cacheHit},
+		{"repo": "actions/runner", "endpoint": "latest", "status": 200.0, "cache": cacheMiss},
+		{"endpoint": "", "status": 404.0},
+		{"endpoint": "probe", "status": 200.0},
+	}
+
+	var got []map[string]any
+	scanner := bufio.NewScanner(bytes.NewReader(logs.buf.Bytes()))
+	for scanner.Scan() {
+		var line map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("log line %q is not JSON: %v", scanner.Text(), err)
+		}
+		if line["msg"] == "request" {
+			got = append(got, line)
+		}
+	}
+	if len(got) != len(want) {
+		t.Fatalf("logged %d requests, want %d:\n%s", len(got), len(want), logs.buf.String())
+	}
+	for i, fields := range want {
+		for name, value := range fields {
+			if got[i][name] != value {
+				t.Errorf("request %d: %s = %v, want %v", i, name, got[i][name], value)
+			}
+		}
+		if _, ok := got[i]["latency_ms"].(float64); !ok {
+			t.Errorf("request %d: no latency_ms", i)
+		}
+		if got[i]["endpoint"] == "releases" && got[i]["bytes"].(float64) == 0 {
+			t.Errorf("request %d: bytes = 0", i)
+		}
+	}
+	if _, ok := got[3]["repo"]; ok {
+		t.Error("refused request logged with a repo")
+	}
+}
+
+func TestEndpoint(t *testing.T) {
+	tests := map[string]string{
+		"/repos/actions/runner":                 "repository",
+		"/repos/actions/runner/releases":        "releases",
+		"/repos/actions/runner/releases/latest": "latest",
+		"/repositories/1/releases":              "releases",
+		"/readyz":                               "probe",
+		"/webhooks/github":                      "webhook",
+		"/repos/actions/runner/issues":          "",
+	}
+	for path, want := range tests {
+		if got := endpoint(path); got != want {
+			t.Errorf("endpoint(%s) = %q, want %q", path, got, want)
+		}
+	}
+}
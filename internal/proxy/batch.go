@@ -0,0 +1,190 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// BatchPath is where batches of checks are posted
+const BatchPath = "/check/batch"
+
+// Limits on batches of checks
+const (
+	MaxBatchChecks   = 1000    // Checks in one request body, across every page
+	MaxBatchPerPage  = 100     // Checks run for one page of results
+	batchConcurrency = 4       // Checks run at once
+	maxBatchBody     = 1 << 20 // Request body size
+)
+
+// BatchCheck is one repository and version to check. An empty version checks
+// the latest release.
+type BatchCheck struct {
+	Repository string `json:"repository"`
+	Version    string `json:"version,omitempty"`
+}
+
+// BatchResult is the outcome of one check: the analysis as JSON, or an error
+type BatchResult struct {
+	Repository string          `json:"repository"`
+	Version    string          `json:"version,omitempty"`
+	Success    bool            `json:"success"`
+	Result     json.RawMessage `json:"result,omitempty"`
+	Error      any             `json:"error,omitempty"`
+}
+
+// CheckFunc runs one check of a batch
+type CheckFunc func(ctx context.Context, check BatchCheck) BatchResult
+
+// BatchRequest is the body posted to BatchPath
+type BatchRequest struct {
+	Checks []BatchCheck `json:"checks"`
+}
+
+// BatchResponse is one page of a batch's results, in request order. A failed
+// check fails only its own result, so the status is 200 whenever the request
+// itself is valid.
+type BatchResponse struct {
+	Results    []BatchResult `json:"results"`
+	Summary    BatchSummary  `json:"summary"`
+	Page       int           `json:"page"`
+	PerPage    int           `json:"per_page"`
+	TotalPages int           `json:"total_pages"`
+	Total      int           `json:"total_checks"`
+}
+
+// BatchSummary counts the results on a page
+type BatchSummary struct {
+	Checked   int `json:"checked"`
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+}
+
+// serveBatch runs the checks on the requested page of a posted batch.
+// Pages are chosen with ?page= and ?per_page=, as on GitHub, and each page
+// runs only its own checks, so a client can post a long manifest and page
+// through it, following the Link header, without any one request running long.
+func (s *Server) serveBatch(w http.ResponseWriter, r *http.Request) {
+	if s.Check == nil {
+		writeError(w, http.StatusNotFound, "Not Found: checks are not enabled on this proxy")
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed: POST a list of checks")
+		return
+	}
+
+	var req BatchRequest
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBatchBody))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+		return
+	}
+	if len(req.Checks) == 0 {
+		writeError(w, http.StatusBadRequest, "invalid request body: no checks")
+		return
+	}
+	if len(req.Checks) > MaxBatchChecks {
+		writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("too many checks: %d, at most %d", len(req.Checks), MaxBatchChecks))
+		return
+	}
+	page, perPage, err := pageParams(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	totalPages := (len(req.Checks) + perPage - 1) / perPage
+	start := min((page-1)*perPage, len(req.Checks))
+	end := min(start+perPage, len(req.Checks))
+	resp := BatchResponse{
+		Results:    s.runChecks(r.Context(), req.Checks[start:end]),
+		Page:       page,
+		PerPage:    perPage,
+		TotalPages: totalPages,
+		Total:      len(req.Checks),
+	}
+	for _, result := range resp.Results {
+		resp.Summary.Checked++
+		if result.Success {
+			resp.Summary.Succeeded++
+		} else {
+			resp.Summary.Failed++
+		}
+	}
+
+	if link := batchLink(r, page, perPage, totalPages); link != "" {
+		w.Header().Set("Link", link)
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// runChecks runs checks on a few workers, returning results in check order
+func (s *Server) runChecks(ctx context.Context, checks []BatchCheck) []BatchResult {
+	results := make([]BatchResult, len(checks))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for worker := 0; worker < min(batchConcurrency, len(checks)); worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = s.Check(ctx, checks[i])
+			}
+		}()
+	}
+	for i := range checks {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return results
+}
+
+// pageParams reads ?page= and ?per_page=, defaulting to the first page of
+// MaxBatchPerPage results
+func pageParams(query url.Values) (int, int, error) {
+	page, perPage := 1, MaxBatchPerPage
+	if v := query.Get("page"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return 0, 0, fmt.Errorf("invalid page %q: must be a positive number", v)
+		}
+		page = n
+	}
+	if v := query.Get("per_page"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > MaxBatchPerPage {
+			return 0, 0, fmt.Errorf("invalid per_page %q: must be between 1 and %d", v, MaxBatchPerPage)
+		}
+		perPage = n
+	}
+	return page, perPage, nil
+}
+
+// batchLink returns a Link header for the pages around page, as GitHub does
+func batchLink(r *http.Request, page, perPage, totalPages int) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	pageURL := func(n int) string {
+		return fmt.Sprintf("<%s://%s%s?page=%d&per_page=%d>", scheme, r.Host, BatchPath, n, perPage)
+	}
+	var links []string
+	if page < totalPages {
+		links = append(links, pageURL(page+1)+`; rel="next"`, pageURL(totalPages)+`; rel="last"`)
+	}
+	if page > 1 {
+		links = append(links, pageURL(min(page-1, totalPages))+`; rel="prev"`, pageURL(1)+`; rel="first"`)
+	}
+	return strings.Join(links, ", ")
+}
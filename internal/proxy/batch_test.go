@@ -0,0 +1,193 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newBatchProxy starts a proxy whose checks succeed unless the version is "bad"
+func newBatchProxy(t *testing.T) (*Server, *httptest.Server) {
+	t.Helper()
+	s, err := New("https://api.github.com/", "", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Check = func(ctx context.Context, check BatchCheck) BatchResult {
+		result := BatchResult{Repository: check.Repository, Version: check.Version}
+		if check.Version == "bad" {
+			result.Error = map[string]string{"code": "invalid_version"}
+			return result
+		}
+		result.Success = true
+		result.Result = json.RawMessage(`{"status":"current"}`)
+		return result
+	}
+	proxy := httptest.NewServer(s)
+	t.Cleanup(proxy.Close)
+	return s, proxy
+}
+
+func postBatch(t *testing.T, url, body string) (*http.Response, BatchResponse) {
+	t.Helper()
+	resp, err := http.Post(url, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var got BatchResponse
+	if resp.StatusCode == http.StatusOK {
+		if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+			t.Fatalf("invalid response: %v", err)
+		}
+	}
+	return resp, got
+}
+
+// batchBody returns a request for n checks, every third one failing
+func batchBody(n int) string {
+	var req BatchRequest
+	for i := 0; i < n; i++ {
+		version := "1.0.0"
+		if i%3 == 2 {
+			version = "bad"
+		}
+		req.Checks = append(req.Checks, BatchCheck{Repository: "acme/repo" + string(rune('a'+i%26)), Version: version})
+	}
+	data, _ := json.Marshal(req)
+	return string(data)
+}
+
+func TestServer_Batch(t *testing.T) {
+	_, proxy := newBatchProxy(t)
+
+	resp, got := postBatch(t, proxy.URL+BatchPath, batchBody(5))
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	want := BatchSummary{Checked: 5, Succeeded: 4, Failed: 1}
+	if got.Summary != want {
+		t.Errorf("summary = %+v, want %+v", got.Summary, want)
+	}
+	for i, result := range got.Results {
+		if wantRepo := "acme/repo" + string(rune('a'+i)); result.Repository != wantRepo {
+			t.Errorf("results[%d] = %s, want %s (request order)", i, result.Repository, wantRepo)
+		}
+		if result.Success != (i != 2) || (result.Error != nil) == result.Success {
+			t.Errorf("results[%d] success = %v, error = %v", i, result.Success, result.Error)
+		}
+	}
+	if got.Page != 1 || got.TotalPages != 1 || got.Total != 5 || resp.Header.Get("Link") != "" {
+		t.Errorf("paging = page %d of %d (%d checks), Link %q, want one page", got.Page, got.TotalPages, got.Total, resp.Header.Get("Link"))
+	}
+}
+
+func TestServer_BatchPages(t *testing.T) {
+	_, proxy := newBatchProxy(t)
+	body := batchBody(7)
+
+	tests := []struct {
+		query       string
+		wantResults int
+		wantFirst   string
+		wantLinks   []string
+	}{
+		{query: "?per_page=3", wantResults: 3, wantFirst: "acme/repoa", wantLinks: []string{`page=2&per_page=3>; rel="next"`, `page=3&per_page=3>; rel="last"`}},
+		{query: "?page=2&per_page=3", wantResults: 3, wantFirst: "acme/repod", wantLinks: []string{`rel="next"`, `page=1&per_page=3>; rel="prev"`, `rel="first"`}},
+		{query: "?page=3&per_page=3", wantResults: 1, wantFirst: "acme/repog", wantLinks: []string{`page=2&per_page=3>; rel="prev"`}},
+		{query: "?page=4&per_page=3", wantResults: 0, wantLinks: []string{`page=3&per_page=3>; rel="prev"`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			resp, got := postBatch(t, proxy.URL+BatchPath+tt.query, body)
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("status = %d, want 200", resp.StatusCode)
+			}
+			if len(got.Results) != tt.wantResults || got.TotalPages != 3 || got.Total != 7 {
+				t.Errorf("got %d results of %d pages, %d checks; want %d of 3 pages, 7 checks", len(got.Results), got.TotalPages, got.Total, tt.wantResults)
+			}
+			if tt.wantFirst != "" && (len(got.Results) == 0 || got.Results[0].Repository != tt.wantFirst) {
+				t.Errorf("first result = %+v, want %s", got.Results, tt.wantFirst)
+			}
+			link := resp.Header.Get("Link")
+			for _, want := range tt.wantLinks {
+				if !strings.Contains(link, want) {
+					t.Errorf("Link = %q, want it to contain %q", link, want)
+				}
+			}
+		})
+	}
+}
+
+func TestServer_BatchInvalid(t *testing.T) {
+	_, proxy := newBatchProxy(t)
+
+	tests := []struct {
+		name       string
+		query      string
+		body       string
+		wantStatus int
+	}{
+		{name: "not JSON", body: "actions/runner 2.328.0", wantStatus: http.StatusBadRequest},
+		{name: "unknown field", body: `{"checks": [], "repos": []}`, wantStatus: http.StatusBadRequest},
+		{name: "no checks", body: `{"checks": []}`, wantStatus: http.StatusBadRequest},
+		{name: "too many checks", body: batchBody(MaxBatchChecks + 1), wantStatus: http.StatusRequestEntityTooLarge},
+		{name: "per page too large", query: "?per_page=101", body: batchBody(1), wantStatus: http.StatusBadRequest},
+		{name: "page zero", query: "?page=0", body: batchBody(1), wantStatus: http.StatusBadRequest},
+		{name: "body too large", body: `{"checks": [{"repository": "` + strings.Repeat("a", maxBatchBody) + `"}]}`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, _ := postBatch(t, proxy.URL+BatchPath+tt.query, tt.body)
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+		})
+	}
+
+	resp, _ := get(t, proxy.URL+BatchPath, nil)
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("GET status = %d, want 405", resp.StatusCode)
+	}
+}
+
+func TestServer_BatchAccess(t *testing.T) {
+	s, proxy := newBatchProxy(t)
+	s.Tokens = []string{"alpha"}
+
+	resp, _ := postBatch(t, proxy.URL+BatchPath, batchBody(1))
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("without a token: status = %d, want 401", resp.StatusCode)
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, proxy.URL+BatchPath, bytes.NewBufferString(batchBody(1)))
+	req.Header.Set("Authorization", "Bearer alpha")
+	authed, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	authed.Body.Close()
+	if authed.StatusCode != http.StatusOK {
+		t.Errorf("with a token: status = %d, want 200", authed.StatusCode)
+	}
+
+	// Without a check function the endpoint does not exist
+	s.Check = nil
+	req, _ = http.NewRequest(http.MethodPost, proxy.URL+BatchPath, bytes.NewBufferString(batchBody(1)))
+	req.Header.Set("Authorization", "Bearer alpha")
+	disabled, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	disabled.Body.Close()
+	if disabled.StatusCode != http.StatusNotFound {
+		t.Errorf("without checks: status = %d, want 404", disabled.StatusCode)
+	}
+}
@@ -0,0 +1,296 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	pb "github.com/nickromney-org/github-release-version-checker/pkg/api/releasecheckerv1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// NewGRPCServer returns a gRPC server for s: the ReleaseChecker service, which
+// runs checks as POST /check/batch does, and the standard health service,
+// serving while /readyz would answer 200. Every call but health checks needs
+// one of s.Tokens as "authorization" metadata, when there are any.
+func (s *Server) NewGRPCServer(opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(s.unaryInterceptor),
+		grpc.ChainStreamInterceptor(s.streamInterceptor))
+	g := grpc.NewServer(opts...)
+	pb.RegisterReleaseCheckerServer(g, &checkService{server: s})
+	grpc_health_v1.RegisterHealthServer(g, &healthService{server: s})
+	return g
+}
+
+// unaryInterceptor authenticates and logs unary calls
+func (s *Server) unaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	resp, err := func() (any, error) {
+		if err := s.authenticate(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}()
+	s.logCall(ctx, info.FullMethod, err, time.Since(start))
+	return resp, err
+}
+
+// streamInterceptor authenticates and logs streaming calls
+func (s *Server) streamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := s.authenticate(ss.Context(), info.FullMethod)
+	if err == nil {
+		err = handler(srv, ss)
+	}
+	s.logCall(ss.Context(), info.FullMethod, err, time.Since(start))
+	return err
+}
+
+// authenticate checks the call's bearer token; health checks need none, like
+// the HTTP probes
+func (s *Server) authenticate(ctx context.Context, method string) error {
+	if method == grpc_health_v1.Health_Check_FullMethodName || method == grpc_health_v1.Health_Watch_FullMethodName {
+		return nil
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	var authorization string
+	if values := md.Get("authorization"); len(values) > 0 {
+		authorization = values[0]
+	}
+	if !s.validCredentials(authorization) {
+		return status.Error(codes.Unauthenticated, "bad credentials: send a proxy token as authorization metadata")
+	}
+	return nil
+}
+
+// logCall logs one gRPC call, as logAccess does HTTP requests
+func (s *Server) logCall(ctx context.Context, method string, err error, latency time.Duration) {
+	if s.Logger == nil {
+		return
+	}
+	s.Logger.InfoContext(ctx, "grpc call", "method", method, "code", status.Code(err).String(),
+		"latency_ms", float64(latency.Microseconds())/1000)
+}
+
+// checkService serves the ReleaseChecker service from the server's CheckFunc
+type checkService struct {
+	pb.UnimplementedReleaseCheckerServer
+	server *Server
+}
+
+func (c *checkService) Check(ctx context.Context, req *pb.CheckRequest) (*pb.CheckResult, error) {
+	if c.server.Check == nil {
+		return nil, status.Error(codes.Unimplemented, "checks are not enabled on this proxy")
+	}
+	return checkResult(c.server.Check(ctx, batchCheck(req))), nil
+}
+
+// CheckBatch runs one page of checks, chosen by page_size and page_token, as
+// POST /check/batch does with ?per_page= and ?page=
+func (c *checkService) CheckBatch(ctx context.Context, req *pb.CheckBatchRequest) (*pb.CheckBatchResponse, error) {
+	checks, err := c.batch(req)
+	if err != nil {
+		return nil, err
+	}
+	perPage := int(req.GetPageSize())
+	if perPage == 0 {
+		perPage = MaxBatchPerPage
+	}
+	if perPage < 0 || perPage > MaxBatchPerPage {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid page_size %d: must be between 1 and %d", perPage, MaxBatchPerPage)
+	}
+	start := 0
+	if token := req.GetPageToken(); token != "" {
+		// The token is the offset of the page's first check
+		if start, err = strconv.Atoi(token); err != nil || start < 0 || start >= len(checks) {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid page_token %q", token)
+		}
+	}
+	end := min(start+perPage, len(checks))
+
+	resp := &pb.CheckBatchResponse{TotalChecks: int32(len(checks))}
+	for _, result := range c.server.runChecks(ctx, checks[start:end]) {
+		resp.Results = append(resp.Results, checkResult(result))
+		resp.Checked++
+		if result.Success {
+			resp.Succeeded++
+		} else {
+			resp.Failed++
+		}
+	}
+	if end < len(checks) {
+		resp.NextPageToken = strconv.Itoa(end)
+	}
+	return resp, nil
+}
+
+// CheckStream runs every check, sending each result as it is ready
+func (c *checkService) CheckStream(req *pb.CheckBatchRequest, stream pb.ReleaseChecker_CheckStreamServer) error {
+	checks, err := c.batch(req)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	results := make(chan BatchResult)
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for worker := 0; worker < min(batchConcurrency, len(checks)); worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				select {
+				case results <- c.server.Check(ctx, checks[i]):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		defer close(indexes)
+		for i := range checks {
+			select {
+			case indexes <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// Sends stay on this goroutine, as a stream allows only one sender
+	for result := range results {
+		if err := stream.Send(checkResult(result)); err != nil {
+			cancel()
+			for range results {
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// batch validates the checks of a batch request, as serveBatch does a posted body
+func (c *checkService) batch(req *pb.CheckBatchRequest) ([]BatchCheck, error) {
+	if c.server.Check == nil {
+		return nil, status.Error(codes.Unimplemented, "checks are not enabled on this proxy")
+	}
+	switch n := len(req.GetChecks()); {
+	case n == 0:
+		return nil, status.Error(codes.InvalidArgument, "no checks")
+	case n > MaxBatchChecks:
+		return nil, status.Errorf(codes.InvalidArgument, "too many checks: %d, at most %d", n, MaxBatchChecks)
+	}
+	checks := make([]BatchCheck, len(req.GetChecks()))
+	for i, check := range req.GetChecks() {
+		checks[i] = batchCheck(check)
+	}
+	return checks, nil
+}
+
+func batchCheck(req *pb.CheckRequest) BatchCheck {
+	return BatchCheck{Repository: req.GetRepository(), Version: req.GetVersion()}
+}
+
+// checkResult converts a check's result, whose analysis and error are the
+// JSON --json prints, to its message
+func checkResult(result BatchResult) *pb.CheckResult {
+	out := &pb.CheckResult{Repository: result.Repository, Version: result.Version}
+	if !result.Success {
+		out.Outcome = &pb.CheckResult_Error{Error: checkError(result.Error)}
+		return out
+	}
+
+	var fields struct {
+		Status            string `json:"status"`
+		LatestVersion     string `json:"latest_version"`
+		ComparisonVersion string `json:"comparison_version"`
+		ReleasesBehind    int32  `json:"releases_behind"`
+		DriftScore        int32  `json:"drift_score"`
+		Degraded          bool   `json:"degraded"`
+	}
+	var details map[string]any
+	if err := json.Unmarshal(result.Result, &fields); err != nil {
+		return failed(out, err)
+	}
+	if err := json.Unmarshal(result.Result, &details); err != nil {
+		return failed(out, err)
+	}
+	analysis := &pb.Analysis{
+		Status:            fields.Status,
+		LatestVersion:     fields.LatestVersion,
+		ComparisonVersion: fields.ComparisonVersion,
+		ReleasesBehind:    fields.ReleasesBehind,
+		DriftScore:        fields.DriftScore,
+		Degraded:          fields.Degraded,
+	}
+	var err error
+	if analysis.Details, err = structpb.NewStruct(details); err != nil {
+		return failed(out, err)
+	}
+	out.Outcome = &pb.CheckResult_Analysis{Analysis: analysis}
+	return out
+}
+
+// checkError converts a check's error, shaped as in --json output, to its message
+func checkError(e any) *pb.Error {
+	var fields struct {
+		Code      string         `json:"code"`
+		Message   string         `json:"message"`
+		Retryable bool           `json:"retryable"`
+		Details   map[string]any `json:"details"`
+	}
+	data, err := json.Marshal(e)
+	if err == nil {
+		err = json.Unmarshal(data, &fields)
+	}
+	if err != nil {
+		return &pb.Error{Code: "internal_error", Message: fmt.Sprintf("invalid check error: %v", err)}
+	}
+	out := &pb.Error{Code: fields.Code, Message: fields.Message, Retryable: fields.Retryable}
+	if len(fields.Details) > 0 {
+		// Details that cannot be represented are dropped, not the error itself
+		out.Details, _ = structpb.NewStruct(fields.Details)
+	}
+	return out
+}
+
+// failed replaces an outcome that cannot be converted with an internal error
+func failed(out *pb.CheckResult, err error) *pb.CheckResult {
+	out.Outcome = &pb.CheckResult_Error{Error: &pb.Error{Code: "internal_error", Message: fmt.Sprintf("invalid analysis: %v", err)}}
+	return out
+}
+
+// healthService answers gRPC health checks from the readiness /readyz reports
+type healthService struct {
+	grpc_health_v1.UnimplementedHealthServer
+	server *Server
+}
+
+func (h *healthService) Check(ctx context.Context, req *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	switch req.GetService() {
+	case "", pb.ReleaseChecker_ServiceDesc.ServiceName:
+	default:
+		return nil, status.Errorf(codes.NotFound, "unknown service %q", req.GetService())
+	}
+	resp := &grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_NOT_SERVING}
+	if h.server.Readiness(ctx).Ready {
+		resp.Status = grpc_health_v1.HealthCheckResponse_SERVING
+	}
+	return resp, nil
+}
@@ -0,0 +1,242 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/nickromney-org/github-release-version-checker/internal/testing/githubtest"
+	pb "github.com/nickromney-org/github-release-version-checker/pkg/api/releasecheckerv1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newGRPCProxy serves a proxy over gRPC in memory, requiring tokens if any,
+// with checks that succeed unless the version is "bad", and returns a client
+// connection to it
+func newGRPCProxy(t *testing.T, tokens ...string) *grpc.ClientConn {
+	t.Helper()
+	upstream := githubtest.NewServer(githubtest.MustLoadFixture("actions/runner"))
+	t.Cleanup(upstream.Close)
+	s, err := New(upstream.URL, "", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Tokens = tokens
+	s.Check = func(ctx context.Context, check BatchCheck) BatchResult {
+		result := BatchResult{Repository: check.Repository, Version: check.Version}
+		if check.Version == "bad" {
+			result.Error = map[string]any{"code": "invalid_version", "message": "invalid version", "details": map[string]any{"input": "bad"}}
+			return result
+		}
+		result.Success = true
+		result.Result = json.RawMessage(`{"latest_version":"2.329.0","comparison_version":"` + check.Version + `","status":"warning","releases_behind":2,"drift_score":12,"degraded":false}`)
+		return result
+	}
+
+	listener := bufconn.Listen(1 << 20)
+	g := s.NewGRPCServer()
+	go g.Serve(listener)
+	t.Cleanup(g.Stop)
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// grpcChecks returns n checks, every third one failing
+func grpcChecks(n int) []*pb.CheckRequest {
+	var checks []*pb.CheckRequest
+	for i := 0; i < n; i++ {
+		version := "1.0.0"
+		if i%3 == 2 {
+			version = "bad"
+		}
+		checks = append(checks, &pb.CheckRequest{Repository: "acme/repo" + string(rune('a'+i%26)), Version: version})
+	}
+	return checks
+}
+
+func TestGRPC_Check(t *testing.T) {
+	client := pb.NewReleaseCheckerClient(newGRPCProxy(t))
+
+	got, err := client.Check(context.Background(), &pb.CheckRequest{Repository: "actions/runner", Version: "2.327.1"})
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	analysis := got.GetAnalysis()
+	if analysis == nil {
+		t.Fatalf("Check() = %v, want an analysis", got)
+	}
+	if analysis.Status != "warning" || analysis.LatestVersion != "2.329.0" || analysis.ComparisonVersion != "2.327.1" ||
+		analysis.ReleasesBehind != 2 || analysis.DriftScore != 12 {
+		t.Errorf("analysis = %v", analysis)
+	}
+	if v := analysis.Details.AsMap()["latest_version"]; v != "2.329.0" {
+		t.Errorf("details latest_version = %v, want 2.329.0", v)
+	}
+
+	got, err = client.Check(context.Background(), &pb.CheckRequest{Repository: "actions/runner", Version: "bad"})
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if e := got.GetError(); e == nil || e.Code != "invalid_version" || e.Message != "invalid version" || e.Details.AsMap()["input"] != "bad" {
+		t.Errorf("Check() = %v, want an invalid_version error with details", got)
+	}
+}
+
+func TestGRPC_CheckBatchPages(t *testing.T) {
+	client := pb.NewReleaseCheckerClient(newGRPCProxy(t))
+	checks := grpcChecks(5)
+
+	var results []*pb.CheckResult
+	var token string
+	for page := 1; ; page++ {
+		resp, err := client.CheckBatch(context.Background(), &pb.CheckBatchRequest{Checks: checks, PageSize: 2, PageToken: token})
+		if err != nil {
+			t.Fatalf("page %d: CheckBatch() error = %v", page, err)
+		}
+		if resp.TotalChecks != 5 || resp.Checked != int32(len(resp.Results)) || resp.Succeeded+resp.Failed != resp.Checked {
+			t.Errorf("page %d: counts = %d checked, %d succeeded, %d failed of %d", page, resp.Checked, resp.Succeeded, resp.Failed, resp.TotalChecks)
+		}
+		results = append(results, resp.Results...)
+		if token = resp.NextPageToken; token == "" {
+			if page != 3 {
+				t.Errorf("pages = %d, want 3", page)
+			}
+			break
+		}
+	}
+
+	if len(results) != len(checks) {
+		t.Fatalf("results = %d, want %d", len(results), len(checks))
+	}
+	for i, result := range results {
+		if result.Repository != checks[i].Repository || (result.GetError() != nil) != (checks[i].Version == "bad") {
+			t.Errorf("result %d = %v, want %s@%s in request order", i, result, checks[i].Repository, checks[i].Version)
+		}
+	}
+}
+
+func TestGRPC_CheckBatchInvalid(t *testing.T) {
+	client := pb.NewReleaseCheckerClient(newGRPCProxy(t))
+	tests := []struct {
+		name string
+		req  *pb.CheckBatchRequest
+	}{
+		{"no checks", &pb.CheckBatchRequest{}},
+		{"too many checks", &pb.CheckBatchRequest{Checks: grpcChecks(MaxBatchChecks + 1)}},
+		{"page size too large", &pb.CheckBatchRequest{Checks: grpcChecks(1), PageSize: MaxBatchPerPage + 1}},
+		{"page token not a number", &pb.CheckBatchRequest{Checks: grpcChecks(1), PageToken: "next"}},
+		{"page token past the end", &pb.CheckBatchRequest{Checks: grpcChecks(1), PageToken: "1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := client.CheckBatch(context.Background(), tt.req); status.Code(err) != codes.InvalidArgument {
+				t.Errorf("CheckBatch() error = %v, want InvalidArgument", err)
+			}
+		})
+	}
+}
+
+func TestGRPC_CheckStream(t *testing.T) {
+	client := pb.NewReleaseCheckerClient(newGRPCProxy(t))
+	checks := grpcChecks(7)
+
+	stream, err := client.CheckStream(context.Background(), &pb.CheckBatchRequest{Checks: checks})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	failed := 0
+	for {
+		result, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv() error = %v", err)
+		}
+		got = append(got, result.Repository)
+		if result.GetError() != nil {
+			failed++
+		}
+	}
+
+	// Results arrive in any order
+	var want []string
+	for _, check := range checks {
+		want = append(want, check.Repository)
+	}
+	sort.Strings(got)
+	sort.Strings(want)
+	if len(got) != len(want) {
+		t.Fatalf("results = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("results = %v, want %v", got, want)
+		}
+	}
+	if failed != 2 {
+		t.Errorf("failed results = %d, want 2", failed)
+	}
+}
+
+func TestGRPC_Authentication(t *testing.T) {
+	conn := newGRPCProxy(t, "client-token")
+	client := pb.NewReleaseCheckerClient(conn)
+	req := &pb.CheckRequest{Repository: "actions/runner", Version: "2.327.1"}
+
+	tests := []struct {
+		name          string
+		authorization string
+		want          codes.Code
+	}{
+		{"no token", "", codes.Unauthenticated},
+		{"wrong token", "Bearer other", codes.Unauthenticated},
+		{"token", "Bearer client-token", codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.authorization != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, "authorization", tt.authorization)
+			}
+			if _, err := client.Check(ctx, req); status.Code(err) != tt.want {
+				t.Errorf("Check() error = %v, want %s", err, tt.want)
+			}
+			stream, err := client.CheckStream(ctx, &pb.CheckBatchRequest{Checks: []*pb.CheckRequest{req}})
+			if err == nil {
+				_, err = stream.Recv()
+			}
+			if status.Code(err) != tt.want {
+				t.Errorf("CheckStream() error = %v, want %s", err, tt.want)
+			}
+		})
+	}
+
+	// Health checks need no token, like /readyz
+	health, err := grpc_health_v1.NewHealthClient(conn).Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	if err != nil {
+		t.Fatalf("health Check() error = %v", err)
+	}
+	if health.Status != grpc_health_v1.HealthCheckResponse_SERVING {
+		t.Errorf("health = %s, want SERVING", health.Status)
+	}
+}
@@ -0,0 +1,110 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ReadyCheckInterval is how long an upstream reachability check is reused, so
+// frequent readiness probes do not each reach GitHub
+const ReadyCheckInterval = 30 * time.Second
+
+// readyTimeout bounds an upstream reachability check
+const readyTimeout = 5 * time.Second
+
+// health tracks what readiness probes report
+type health struct {
+	mu             sync.Mutex
+	lastRefresh    time.Time // Last upstream response accepted into the cache
+	lastError      string    // Last failed refresh, cleared by a successful one
+	checkedAt      time.Time // Last reachability check
+	reachable      bool
+	reachableError string
+}
+
+// Readiness is the body served at /readyz
+type Readiness struct {
+	Ready             bool       `json:"ready"`
+	CachedResponses   int        `json:"cached_responses"`
+	UpstreamReachable bool       `json:"upstream_reachable"`
+	UpstreamCheckedAt time.Time  `json:"upstream_checked_at"`
+	UpstreamError     string     `json:"upstream_error,omitempty"`
+	LastRefresh       *time.Time `json:"last_refresh,omitempty"`
+	LastRefreshError  string     `json:"last_refresh_error,omitempty"`
+}
+
+// recordRefresh notes the outcome of a refresh upstream
+func (s *Server) recordRefresh(err error) {
+	s.health.mu.Lock()
+	defer s.health.mu.Unlock()
+	if err != nil {
+		s.health.lastError = err.Error()
+		return
+	}
+	s.health.lastRefresh, s.health.lastError = s.now(), ""
+}
+
+// serveHealth answers liveness probes: the process is serving requests
+func (s *Server) serveHealth(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	io.WriteString(w, "ok\n")
+}
+
+// serveReady answers readiness probes. The proxy is ready when GitHub is
+// reachable, or when it has responses cached to serve stale until it is again.
+func (s *Server) serveReady(w http.ResponseWriter, r *http.Request) {
+	readiness := s.Readiness(r.Context())
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	if !readiness.Ready {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(readiness)
+}
+
+// Readiness reports whether the proxy can serve releases, checking GitHub is
+// reachable at most once per ReadyCheckInterval
+func (s *Server) Readiness(ctx context.Context) Readiness {
+	cached := int(s.cached.Load())
+	s.health.mu.Lock()
+	defer s.health.mu.Unlock()
+	if s.health.checkedAt.IsZero() || s.now().Sub(s.health.checkedAt) >= ReadyCheckInterval {
+		s.health.reachable, s.health.reachableError = s.checkUpstream(ctx)
+		s.health.checkedAt = s.now()
+	}
+
+	readiness := Readiness{
+		Ready:             s.health.reachable || cached > 0,
+		CachedResponses:   cached,
+		UpstreamReachable: s.health.reachable,
+		UpstreamCheckedAt: s.health.checkedAt,
+		UpstreamError:     s.health.reachableError,
+		LastRefreshError:  s.health.lastError,
+	}
+	if !s.health.lastRefresh.IsZero() {
+		lastRefresh := s.health.lastRefresh
+		readiness.LastRefresh = &lastRefresh
+	}
+	return readiness
+}
+
+// checkUpstream asks GitHub for its rate limit, which costs none of it. Any
+// response below 500 counts as reachable: GitHub Enterprise Server answers 404
+// when rate limiting is disabled.
+func (s *Server) checkUpstream(ctx context.Context) (bool, string) {
+	ctx, cancel := context.WithTimeout(ctx, readyTimeout)
+	defer cancel()
+	resp, err := s.get(ctx, "rate_limit", "", "")
+	if err != nil {
+		return false, err.Error()
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return false, "upstream returned " + resp.Status
+	}
+	return true, ""
+}
@@ -0,0 +1,105 @@
+package proxy
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nickromney-org/github-release-version-checker/internal/testing/githubtest"
+)
+
+func readiness(t *testing.T, url string) (int, Readiness) {
+	t.Helper()
+	resp, body := get(t, url+"/readyz", nil)
+	var got Readiness
+	if err := json.Unmarshal([]byte(body), &got); err != nil {
+		t.Fatalf("invalid /readyz body %q: %v", body, err)
+	}
+	return resp.StatusCode, got
+}
+
+func TestServer_Health(t *testing.T) {
+	upstream, proxy, _ := newTestProxy(t)
+
+	// Liveness needs no token, even when clients do
+	proxy.Config.Handler.(*Server).Tokens = []string{"alpha"}
+	resp, body := get(t, proxy.URL+"/healthz", nil)
+	if resp.StatusCode != http.StatusOK || body != "ok\n" {
+		t.Errorf("/healthz = %d %q, want 200 ok", resp.StatusCode, body)
+	}
+
+	status, got := readiness(t, proxy.URL)
+	if status != http.StatusOK || !got.Ready || !got.UpstreamReachable || got.CachedResponses != 0 || got.LastRefresh != nil {
+		t.Errorf("/readyz = %d %+v, want ready with nothing cached", status, got)
+	}
+	// The check was the rate-limit endpoint, which costs nothing
+	if requests := upstream.Requests(); len(requests) != 1 || requests[0] != "/rate_limit" {
+		t.Errorf("upstream requests = %v, want /rate_limit", requests)
+	}
+}
+
+func TestServer_Readiness(t *testing.T) {
+	tests := []struct {
+		name          string
+		cache         bool // Fetch a response before GitHub goes away
+		wantStatus    int
+		wantCached    int
+		wantRefreshed bool
+	}{
+		{name: "unreachable with nothing cached is not ready", wantStatus: http.StatusServiceUnavailable},
+		{name: "unreachable with responses cached is ready", cache: true, wantStatus: http.StatusOK, wantCached: 1, wantRefreshed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			upstream := githubtest.NewServer(githubtest.MustLoadFixture("actions/runner"))
+			s, err := New(upstream.URL, "", time.Minute)
+			if err != nil {
+				t.Fatal(err)
+			}
+			now := time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC)
+			s.now = func() time.Time { return now }
+			proxy := httptest.NewServer(s)
+			defer proxy.Close()
+
+			if tt.cache {
+				get(t, proxy.URL+"/repos/actions/runner/releases", nil)
+			}
+			upstream.Close()
+
+			status, got := readiness(t, proxy.URL)
+			if status != tt.wantStatus {
+				t.Errorf("status = %d, want %d", status, tt.wantStatus)
+			}
+			if got.UpstreamReachable || got.UpstreamError == "" {
+				t.Errorf("upstream reachable = %v (%q), want unreachable with an error", got.UpstreamReachable, got.UpstreamError)
+			}
+			if got.CachedResponses != tt.wantCached {
+				t.Errorf("cached responses = %d, want %d", got.CachedResponses, tt.wantCached)
+			}
+			if (got.LastRefresh != nil) != tt.wantRefreshed {
+				t.Errorf("last refresh = %v, want set %v", got.LastRefresh, tt.wantRefreshed)
+			}
+		})
+	}
+}
+
+func TestServer_ReadinessReusesCheck(t *testing.T) {
+	upstream, proxy, now := newTestProxy(t)
+
+	readiness(t, proxy.URL)
+	upstream.Close()
+
+	// Within the interval the earlier check stands
+	*now = now.Add(ReadyCheckInterval / 2)
+	if _, got := readiness(t, proxy.URL); !got.UpstreamReachable {
+		t.Error("upstream rechecked within the interval")
+	}
+
+	*now = now.Add(ReadyCheckInterval)
+	if status, got := readiness(t, proxy.URL); got.UpstreamReachable || status != http.StatusServiceUnavailable {
+		t.Errorf("after the interval: status = %d, reachable = %v, want 503 and unreachable", status, got.UpstreamReachable)
+	}
+}
@@ -0,0 +1,393 @@
+// Package proxy serves GitHub's release endpoints from a shared cache, so many
+// checkers can point at one endpoint and share one upstream rate limit.
+package proxy
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultTTL is how long a response is served before it is refreshed upstream
+const DefaultTTL = 10 * time.Minute
+
+// DefaultUpstream is GitHub's REST API
+const DefaultUpstream = "https://api.github.com/"
+
+// maxBody bounds each upstream response kept in the cache
+const maxBody = 32 << 20
+
+// Cache states reported in the X-Cache response header
+const (
+	cacheHit         = "HIT"         // Served from the cache within its TTL
+	cacheMiss        = "MISS"        // Fetched upstream
+	cacheRevalidated = "REVALIDATED" // Expired, and upstream confirmed it unchanged
+	cacheStale       = "STALE"       // Expired, but upstream failed, so served anyway
+)
+
+// Headers kept from upstream responses
+var keptHeaders = []string{"Content-Type", "ETag", "Link"}
+
+// Server is an http.Handler answering GET requests for a repository, its
+// releases and its latest release from a cache, refreshing each from the
+// upstream API once its TTL has passed. Refreshes are conditional, so unchanged
+// releases do not count against the upstream rate limit. Other paths are
+// refused, so the token only ever reads releases.
+type Server struct {
+	Upstream  *url.URL      // API base URL, e.g. https://api.github.com/
+	Token     string        // Sent upstream; clients never see it
+	TTL       time.Duration // How long responses are served before refreshing
+	UserAgent string
+	Client    *http.Client // Default: http.DefaultClient
+	Logger    *slog.Logger // Optional; logs each request served and sent upstream
+
+	// Tokens are bearer tokens clients must send one of; none means no auth
+	Tokens []string
+	// Repositories are "owner/repo" glob patterns clients may read; none means any
+	Repositories []string
+	// Check, if set, serves POST /check/batch, running each check it is sent
+	Check CheckFunc
+	// WebhookSecret, if set, accepts release webhooks signed with it at WebhookPath
+	WebhookSecret string
+
+	now     func() time.Time
+	mu      sync.Mutex
+	entries map[string]*entry
+	cached  atomic.Int64 // Entries holding a response; counted apart so probes take no entry locks
+	health  health
+}
+
+// entry is a cached upstream response
+type entry struct {
+	mu        sync.Mutex // Held while refreshing, so one request per key goes upstream
+	status    int
+	header    http.Header
+	body      []byte
+	fetchedAt time.Time
+	dropped   bool // Removed by a webhook, so no longer counted in Server.cached
+}
+
+// New creates a server for the upstream API base URL (DefaultUpstream when
+// empty), authenticating with token if set
+func New(upstream, token string, ttl time.Duration) (*Server, error) {
+	if upstream == "" {
+		upstream = DefaultUpstream
+	}
+	u, err := url.Parse(strings.TrimRight(upstream, "/") + "/")
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return nil, fmt.Errorf("invalid upstream URL %q: must be an http(s) URL", upstream)
+	}
+	if ttl <= 0 {
+		return nil, fmt.Errorf("ttl must be positive")
+	}
+	return &Server{Upstream: u, Token: token, TTL: ttl, now: time.Now, entries: make(map[string]*entry)}, nil
+}
+
+// routePath reports whether path is a repository, its releases or its latest
+// release, including the /repositories/{id} form GitHub links pages with, and
+// returns the "owner/repo" it names ("" for the id form)
+func routePath(path string) (string, bool) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	var repository string
+	var rest []string
+	switch {
+	case len(parts) >= 3 && parts[0] == "repos" && parts[1] != "" && parts[2] != "":
+		repository, rest = parts[1]+"/"+parts[2], parts[3:]
+	case len(parts) >= 2 && parts[0] == "repositories" && parts[1] != "":
+		rest = parts[2:]
+	default:
+		return "", false
+	}
+	switch strings.Join(rest, "/") {
+	case "", "releases", "releases/latest":
+		return repository, true
+	}
+	return "", false
+}
+
+// authorised reports whether r carries one of the accepted bearer tokens, or
+// no tokens are required
+func (s *Server) authorised(r *http.Request) bool {
+	return s.validCredentials(r.Header.Get("Authorization"))
+}
+
+// validCredentials reports whether an Authorization header, or gRPC
+// authorization metadata, carries one of the accepted bearer tokens, or no
+// tokens are required
+func (s *Server) validCredentials(authorization string) bool {
+	if len(s.Tokens) == 0 {
+		return true
+	}
+	scheme, token, ok := strings.Cut(authorization, " ")
+	if !ok || (!strings.EqualFold(scheme, "Bearer") && !strings.EqualFold(scheme, "token")) {
+		return false
+	}
+	token = strings.TrimSpace(token)
+	match := 0
+	for _, accepted := range s.Tokens {
+		// Compare every token in constant time, so timing reveals none of them
+		match |= subtle.ConstantTimeCompare([]byte(token), []byte(accepted))
+	}
+	return match == 1
+}
+
+// allowedRepository reports whether repository matches the allowlist. The
+// /repositories/{id} form names no repository, so it is refused under one.
+func (s *Server) allowedRepository(repository string) bool {
+	if len(s.Repositories) == 0 {
+		return true
+	}
+	if repository == "" {
+		return false
+	}
+	for _, pattern := range s.Repositories {
+		if ok, _ := path.Match(strings.ToLower(pattern), strings.ToLower(repository)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidatePattern checks an allowlist pattern is "owner/repo", where either
+// part may be a glob such as "actions/*"
+func ValidatePattern(pattern string) error {
+	owner, repo, ok := strings.Cut(pattern, "/")
+	if !ok || owner == "" || repo == "" || strings.Contains(repo, "/") {
+		return fmt.Errorf("invalid repository pattern %q: use owner/repo, e.g. actions/*", pattern)
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return fmt.Errorf("invalid repository pattern %q: %w", pattern, err)
+	}
+	return nil
+}
+
+// ServeHTTP answers a request from the cache, refreshing it upstream if needed,
+// and logs it
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.Logger == nil {
+		s.serve(w, r)
+		return
+	}
+	start := time.Now()
+	rec := &recorder{ResponseWriter: w, status: http.StatusOK}
+	s.serve(rec, r)
+	s.logAccess(r, rec, time.Since(start))
+}
+
+// serve answers a request; ServeHTTP wraps it to log access
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	// Probes need no token, so orchestrators can reach them
+	switch r.URL.Path {
+	case "/healthz":
+		s.serveHealth(w, r)
+		return
+	case "/readyz":
+		s.serveReady(w, r)
+		return
+	case WebhookPath:
+		// GitHub cannot send a proxy token; the payload signature authenticates it
+		s.serveWebhook(w, r)
+		return
+	}
+	if !s.authorised(r) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="release proxy"`)
+		writeError(w, http.StatusUnauthorized, "Bad credentials: pass a proxy token with -t")
+		return
+	}
+	if r.URL.Path == BatchPath {
+		s.serveBatch(w, r)
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	repository, ok := routePath(r.URL.Path)
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found: the proxy serves /repos/{owner}/{repo}/releases")
+		return
+	}
+	if !s.allowedRepository(repository) {
+		writeError(w, http.StatusForbidden, "Repository not allowed by the proxy")
+		return
+	}
+
+	// Query parameters are sorted, so the same page is one entry however it is asked for
+	query := r.URL.Query().Encode()
+	e := s.entry(r.URL.Path + "?" + query)
+
+	e.mu.Lock()
+	state := cacheHit
+	if e.body == nil || s.now().Sub(e.fetchedAt) >= s.TTL {
+		var passthrough *http.Response
+		var err error
+		state, passthrough, err = s.refresh(r.Context(), e, r.URL.Path, query)
+		if passthrough != nil {
+			// Not cacheable (e.g. a rate limit) and nothing cached: the client sees it as is
+			e.mu.Unlock()
+			defer passthrough.Body.Close()
+			copyHeaders(w.Header(), passthrough.Header, "Content-Type", "Retry-After",
+				"X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset", "X-RateLimit-Used", "X-RateLimit-Resource")
+			w.Header().Set("X-Cache", cacheMiss)
+			w.WriteHeader(passthrough.StatusCode)
+			io.Copy(w, io.LimitReader(passthrough.Body, maxBody))
+			return
+		}
+		if err != nil {
+			e.mu.Unlock()
+			writeError(w, http.StatusBadGateway, fmt.Sprintf("upstream request failed: %v", err))
+			return
+		}
+	}
+	status, header, body, age := e.status, e.header.Clone(), e.body, s.now().Sub(e.fetchedAt)
+	e.mu.Unlock()
+
+	for name, values := range header {
+		w.Header()[name] = values
+	}
+	if link := header.Get("Link"); link != "" {
+		w.Header().Set("Link", s.rewriteLink(link, r))
+	}
+	w.Header().Set("X-Cache", state)
+	w.Header().Set("Age", fmt.Sprint(int(age.Seconds())))
+	if etag := header.Get("ETag"); etag != "" && r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.WriteHeader(status)
+	if r.Method == http.MethodGet {
+		w.Write(body)
+	}
+}
+
+// entry returns the cache entry for key, creating it if needed
+func (s *Server) entry(key string) *entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[key]
+	if !ok {
+		e = &entry{}
+		s.entries[key] = e
+	}
+	return e
+}
+
+// refresh fetches e upstream, conditionally when it is cached, and returns the
+// cache state to report. Successes and not-found responses are cached. Other
+// responses are returned to pass through when nothing is cached; when something
+// is, it is served stale, as it is on network errors.
+func (s *Server) refresh(ctx context.Context, e *entry, path, query string) (string, *http.Response, error) {
+	var etag string
+	if e.body != nil {
+		etag = e.header.Get("ETag")
+	}
+	resp, err := s.get(ctx, strings.TrimPrefix(path, "/"), query, etag)
+	if err != nil {
+		s.recordRefresh(err)
+		return s.stale(e, path, err)
+	}
+	if s.Logger != nil {
+		s.Logger.Info("upstream request", "path", path, "query", query, "status", resp.StatusCode)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		resp.Body.Close()
+		e.fetchedAt = s.now()
+		s.recordRefresh(nil)
+		return cacheRevalidated, nil, nil
+	case http.StatusOK, http.StatusNotFound:
+		defer resp.Body.Close()
+		body, err := io.ReadAll(io.LimitReader(resp.Body, maxBody))
+		if err != nil {
+			s.recordRefresh(err)
+			return s.stale(e, path, err)
+		}
+		if e.body == nil && !e.dropped {
+			s.cached.Add(1)
+		}
+		e.status, e.body, e.fetchedAt = resp.StatusCode, body, s.now()
+		e.header = http.Header{}
+		copyHeaders(e.header, resp.Header, keptHeaders...)
+		s.recordRefresh(nil)
+		return cacheMiss, nil, nil
+	}
+
+	s.recordRefresh(fmt.Errorf("upstream returned %s", resp.Status))
+	if e.body == nil {
+		return cacheMiss, resp, nil
+	}
+	resp.Body.Close()
+	return s.stale(e, path, fmt.Errorf("upstream returned %s", resp.Status))
+}
+
+// get sends a GET for path, relative to the upstream base URL, conditionally
+// when etag is set
+func (s *Server) get(ctx context.Context, path, query, etag string) (*http.Response, error) {
+	target := s.Upstream.ResolveReference(&url.URL{Path: path, RawQuery: query})
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if s.UserAgent != "" {
+		req.Header.Set("User-Agent", s.UserAgent)
+	}
+	if s.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.Token)
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return client.Do(req)
+}
+
+// stale serves e past its TTL after a failed refresh, or fails if nothing is cached
+func (s *Server) stale(e *entry, path string, err error) (string, *http.Response, error) {
+	if e.body == nil {
+		return "", nil, err
+	}
+	if s.Logger != nil {
+		s.Logger.Warn("upstream refresh failed, serving stale response", "path", path, "error", err)
+	}
+	return cacheStale, nil, nil
+}
+
+// rewriteLink points pagination links at the proxy rather than upstream
+func (s *Server) rewriteLink(link string, r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return strings.ReplaceAll(link, s.Upstream.String(), scheme+"://"+r.Host+"/")
+}
+
+// copyHeaders copies the named headers from src to dst
+func copyHeaders(dst, src http.Header, names ...string) {
+	for _, name := range names {
+		if values := src.Values(name); len(values) > 0 {
+			dst[http.CanonicalHeaderKey(name)] = append([]string(nil), values...)
+		}
+	}
+}
+
+// writeError writes an error body shaped like GitHub's
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, "{\"message\": %q}\n", message)
+}
@@ -0,0 +1,271 @@
+package proxy
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nickromney-org/github-release-version-checker/internal/testing/githubtest"
+)
+
+// newTestProxy starts a proxy in front of a fake GitHub, with a clock the test moves
+func newTestProxy(t *testing.T) (*githubtest.Server, *httptest.Server, *time.Time) {
+	t.Helper()
+	upstream := githubtest.NewServer(githubtest.MustLoadFixture("actions/runner"))
+	t.Cleanup(upstream.Close)
+
+	s, err := New(upstream.URL, "", time.Minute)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	now := time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	proxy := httptest.NewServer(s)
+	t.Cleanup(proxy.Close)
+	return upstream, proxy, &now
+}
+
+func get(t *testing.T, url string, header http.Header) (*http.Response, string) {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodGet, url, nil)
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp, string(body)
+}
+
+func TestServer_Caching(t *testing.T) {
+	upstream, proxy, now := newTestProxy(t)
+	url := proxy.URL + "/repos/actions/runner/releases?per_page=5"
+
+	steps := []struct {
+		name      string
+		advance   time.Duration
+		wantCache string
+		wantSent  int // Upstream requests so far
+	}{
+		{name: "first request goes upstream", wantCache: cacheMiss, wantSent: 1},
+		{name: "within the TTL is served from the cache", advance: 30 * time.Second, wantCache: cacheHit, wantSent: 1},
+		{name: "after the TTL is revalidated", advance: time.Minute, wantCache: cacheRevalidated, wantSent: 2},
+		{name: "revalidation renews the TTL", advance: 30 * time.Second, wantCache: cacheHit, wantSent: 2},
+	}
+
+	var first string
+	for _, step := range steps {
+		*now = now.Add(step.advance)
+		resp, body := get(t, url, nil)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("%s: status = %d, want 200", step.name, resp.StatusCode)
+		}
+		if got := resp.Header.Get("X-Cache"); got != step.wantCache {
+			t.Errorf("%s: X-Cache = %q, want %q", step.name, got, step.wantCache)
+		}
+		if got := len(upstream.Requests()); got != step.wantSent {
+			t.Errorf("%s: upstream requests = %d, want %d", step.name, got, step.wantSent)
+		}
+		if first == "" {
+			first = body
+		} else if body != first {
+			t.Errorf("%s: body changed", step.name)
+		}
+	}
+
+	// The revalidation was a 304, so only the first request was counted
+	resp, _ := get(t, upstream.URL+"/rate_limit", nil)
+	if got, want := resp.Header.Get("X-RateLimit-Used"), "1"; got != want {
+		t.Errorf("upstream rate limit used = %s, want %s", got, want)
+	}
+}
+
+func TestServer_Routes(t *testing.T) {
+	_, proxy, _ := newTestProxy(t)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		wantStatus int
+	}{
+		{name: "repository", path: "/repos/actions/runner", wantStatus: http.StatusOK},
+		{name: "latest release", path: "/repos/actions/runner/releases/latest", wantStatus: http.StatusOK},
+		{name: "unknown repository is passed on", path: "/repos/actions/missing/releases", wantStatus: http.StatusNotFound},
+		{name: "other endpoints are refused", path: "/repos/actions/runner/issues", wantStatus: http.StatusNotFound},
+		{name: "user endpoints are refused", path: "/user", wantStatus: http.StatusNotFound},
+		{name: "writes are refused", method: http.MethodPost, path: "/repos/actions/runner/releases", wantStatus: http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			req, _ := http.NewRequest(method, proxy.URL+tt.path, nil)
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestServer_RewritesLinks(t *testing.T) {
+	upstream, proxy, _ := newTestProxy(t)
+
+	resp, _ := get(t, proxy.URL+"/repos/actions/runner/releases?per_page=1", nil)
+	link := resp.Header.Get("Link")
+	if !strings.Contains(link, "<"+proxy.URL+"/repos/actions/runner/releases?") {
+		t.Errorf("Link = %q, want links to the proxy", link)
+	}
+	if strings.Contains(link, upstream.URL) {
+		t.Errorf("Link = %q still points upstream", link)
+	}
+}
+
+func TestServer_ClientETag(t *testing.T) {
+	_, proxy, _ := newTestProxy(t)
+	url := proxy.URL + "/repos/actions/runner/releases"
+
+	resp, _ := get(t, url, nil)
+	etag := resp.Header.Get("ETag")
+	if etag == "" {
+		t.Fatal("no ETag served")
+	}
+	resp, _ = get(t, url, http.Header{"If-None-Match": {etag}})
+	if resp.StatusCode != http.StatusNotModified {
+		t.Errorf("status = %d, want 304", resp.StatusCode)
+	}
+}
+
+func TestServer_UpstreamFailure(t *testing.T) {
+	upstream, proxy, now := newTestProxy(t)
+	url := proxy.URL + "/repos/actions/runner/releases"
+
+	// Nothing cached: the rate limit reaches the client as is
+	upstream.SecondaryRateLimit(60)
+	resp, _ := get(t, url, nil)
+	if resp.StatusCode != http.StatusForbidden || resp.Header.Get("Retry-After") != "60" {
+		t.Errorf("uncached: status = %d, Retry-After = %q, want 403 and 60", resp.StatusCode, resp.Header.Get("Retry-After"))
+	}
+
+	// Cached: the expired copy is served instead
+	get(t, url, nil)
+	*now = now.Add(2 * time.Minute)
+	upstream.SecondaryRateLimit(60)
+	resp, _ = get(t, url, nil)
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("X-Cache") != cacheStale {
+		t.Errorf("cached: status = %d, X-Cache = %q, want 200 and %s", resp.StatusCode, resp.Header.Get("X-Cache"), cacheStale)
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name     string
+		upstream string
+		ttl      time.Duration
+		want     string
+		wantErr  bool
+	}{
+		{name: "default upstream", ttl: time.Minute, want: DefaultUpstream},
+		{name: "adds trailing slash", upstream: "https://github.example.com/api/v3", ttl: time.Minute, want: "https://github.example.com/api/v3/"},
+		{name: "not http", upstream: "ftp://example.com", ttl: time.Minute, wantErr: true},
+		{name: "no host", upstream: "https://", ttl: time.Minute, wantErr: true},
+		{name: "zero ttl", ttl: 0, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := New(tt.upstream, "", tt.ttl)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && s.Upstream.String() != tt.want {
+				t.Errorf("Upstream = %s, want %s", s.Upstream, tt.want)
+			}
+		})
+	}
+}
+
+func TestServer_Access(t *testing.T) {
+	upstream := githubtest.NewServer(githubtest.MustLoadFixture("actions/runner"))
+	defer upstream.Close()
+	s, err := New(upstream.URL, "", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Tokens = []string{"alpha", "bravo"}
+	s.Repositories = []string{"Actions/*", "kubernetes/kubernetes"}
+	proxy := httptest.NewServer(s)
+	defer proxy.Close()
+
+	tests := []struct {
+		name          string
+		authorization string
+		path          string
+		wantStatus    int
+	}{
+		{name: "no token", path: "/repos/actions/runner/releases", wantStatus: http.StatusUnauthorized},
+		{name: "wrong token", authorization: "Bearer charlie", path: "/repos/actions/runner/releases", wantStatus: http.StatusUnauthorized},
+		{name: "not bearer", authorization: "Basic YWxwaGE6", path: "/repos/actions/runner/releases", wantStatus: http.StatusUnauthorized},
+		{name: "bearer token", authorization: "Bearer bravo", path: "/repos/actions/runner/releases", wantStatus: http.StatusOK},
+		{name: "token scheme", authorization: "token alpha", path: "/repos/actions/runner", wantStatus: http.StatusOK},
+		{name: "allowlist ignores case", authorization: "Bearer alpha", path: "/repos/actions/runner/releases/latest", wantStatus: http.StatusOK},
+		{name: "repository not allowed", authorization: "Bearer alpha", path: "/repos/hashicorp/terraform/releases", wantStatus: http.StatusForbidden},
+		{name: "id form refused under an allowlist", authorization: "Bearer alpha", path: "/repositories/1/releases", wantStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.authorization != "" {
+				header.Set("Authorization", tt.authorization)
+			}
+			resp, _ := get(t, proxy.URL+tt.path, header)
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+		})
+	}
+
+	// Refused requests never reach GitHub
+	for _, request := range upstream.Requests() {
+		if strings.Contains(request, "terraform") || strings.Contains(request, "repositories") {
+			t.Errorf("refused request %s was sent upstream", request)
+		}
+	}
+}
+
+func TestValidatePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		wantErr bool
+	}{
+		{pattern: "actions/runner"},
+		{pattern: "actions/*"},
+		{pattern: "*/*"},
+		{pattern: "actions", wantErr: true},
+		{pattern: "actions/", wantErr: true},
+		{pattern: "a/b/c", wantErr: true},
+		{pattern: "actions/[", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		if err := ValidatePattern(tt.pattern); (err != nil) != tt.wantErr {
+			t.Errorf("ValidatePattern(%q) error = %v, wantErr %v", tt.pattern, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package proxy
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// WebhookPath receives GitHub webhooks, so a new release is served at once
+// rather than after the TTL
+const WebhookPath = "/webhooks/github"
+
+// maxWebhookBody bounds a webhook payload; GitHub caps them at 25 MB
+const maxWebhookBody = 25 << 20
+
+// WebhookResult is the body answering a release webhook
+type WebhookResult struct {
+	Repository string `json:"repository"`
+	Dropped    int    `json:"dropped"` // Cached responses dropped
+}
+
+// webhookPayload holds the fields of a release event the proxy reads
+type webhookPayload struct {
+	Action     string `json:"action"`
+	Repository struct {
+		ID       int64  `json:"id"`
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+// serveWebhook answers POST /webhooks/github. GitHub signs each delivery with
+// the webhook's secret, which stands in for a bearer token; without one set,
+// webhooks are refused rather than accepted unsigned. A release event drops the
+// repository's cached responses, so the next request fetches them upstream.
+func (s *Server) serveWebhook(w http.ResponseWriter, r *http.Request) {
+	if s.WebhookSecret == "" {
+		writeError(w, http.StatusNotFound, "Not Found: webhooks need a secret, set with --webhook-secret-file")
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed: webhooks are POSTed")
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBody+1))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("failed to read payload: %v", err))
+		return
+	}
+	if len(body) > maxWebhookBody {
+		writeError(w, http.StatusRequestEntityTooLarge, "Payload too large")
+		return
+	}
+	if !validSignature(s.WebhookSecret, body, r.Header.Get("X-Hub-Signature-256")) {
+		writeError(w, http.StatusUnauthorized, "Bad signature: X-Hub-Signature-256 does not match the webhook secret")
+		return
+	}
+
+	switch r.Header.Get("X-GitHub-Event") {
+	case "ping":
+		w.WriteHeader(http.StatusOK)
+		return
+	case "release":
+	default:
+		// Subscribed to more than releases: acknowledged, so GitHub does not retry
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	var payload webhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil || payload.Repository.FullName == "" {
+		writeError(w, http.StatusBadRequest, "Invalid release event: no repository.full_name")
+		return
+	}
+	repository := payload.Repository.FullName
+	if !s.allowedRepository(repository) {
+		writeError(w, http.StatusNotFound, "Repository not served by the proxy")
+		return
+	}
+
+	dropped := s.drop(repository, payload.Repository.ID)
+	if s.Logger != nil {
+		s.Logger.Info("release webhook", "repository", repository, "action", payload.Action, "dropped", dropped)
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(WebhookResult{Repository: repository, Dropped: dropped})
+}
+
+// validSignature reports whether signature is "sha256=" and the hex HMAC-SHA256
+// of body keyed with secret
+func validSignature(secret string, body []byte, signature string) bool {
+	digest, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return false
+	}
+	got, err := hex.DecodeString(digest)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+// drop removes the cached responses for repository, under its name (matched
+// case-insensitively, as GitHub does) or its numeric id, and returns how many
+// held a response
+func (s *Server) drop(repository string, id int64) int {
+	prefixes := []string{"/repos/" + strings.ToLower(repository)}
+	if id != 0 {
+		prefixes = append(prefixes, "/repositories/"+strconv.FormatInt(id, 10))
+	}
+
+	s.mu.Lock()
+	var removed []*entry
+	for key, e := range s.entries {
+		path, _, _ := strings.Cut(key, "?")
+		path = strings.ToLower(strings.TrimRight(path, "/"))
+		for _, prefix := range prefixes {
+			if path == prefix || strings.HasPrefix(path, prefix+"/") {
+				removed = append(removed, e)
+				delete(s.entries, key)
+				break
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	dropped := 0
+	for _, e := range removed {
+		// A request already holding e may still refresh it, but it is no longer counted
+		e.mu.Lock()
+		if e.body != nil {
+			dropped++
+			s.cached.Add(-1)
+		}
+		e.dropped = true
+		e.mu.Unlock()
+	}
+	return dropped
+}
@@ -0,0 +1,147 @@
+package proxy
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nickromney-org/github-release-version-checker/internal/testing/githubtest"
+)
+
+const testWebhookSecret = "webhook-secret"
+
+// newWebhookProxy starts a proxy requiring a bearer token and accepting webhooks
+// signed with testWebhookSecret, in front of a fake GitHub
+func newWebhookProxy(t *testing.T) (*githubtest.Server, *Server, *httptest.Server) {
+	t.Helper()
+	upstream := githubtest.NewServer(githubtest.MustLoadFixture("actions/runner"))
+	t.Cleanup(upstream.Close)
+
+	s, err := New(upstream.URL, "", time.Hour)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	s.Tokens = []string{"client-token"}
+	s.Repositories = []string{"actions/*"}
+	s.WebhookSecret = testWebhookSecret
+
+	proxy := httptest.NewServer(s)
+	t.Cleanup(proxy.Close)
+	return upstream, s, proxy
+}
+
+func sign(secret, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func postWebhook(t *testing.T, url, event, signature, body string) (int, string) {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodPost, url+WebhookPath, strings.NewReader(body))
+	req.Header.Set("X-GitHub-Event", event)
+	if signature != "" {
+		req.Header.Set("X-Hub-Signature-256", signature)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST %s: %v", WebhookPath, err)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(data)
+}
+
+func TestServer_Webhook(t *testing.T) {
+	release := `{"action": "published", "repository": {"id": 1, "full_name": "actions/runner"}, "release": {"tag_name": "v2.330.0"}}`
+	tests := []struct {
+		name      string
+		event     string
+		signature string
+		body      string
+		want      int
+	}{
+		{name: "release", event: "release", signature: sign(testWebhookSecret, release), body: release, want: http.StatusOK},
+		{name: "ping", event: "ping", signature: sign(testWebhookSecret, `{"zen": "Keep it simple."}`), body: `{"zen": "Keep it simple."}`, want: http.StatusOK},
+		{name: "other events acknowledged", event: "push", signature: sign(testWebhookSecret, `{}`), body: `{}`, want: http.StatusAccepted},
+		{name: "unsigned", event: "release", body: release, want: http.StatusUnauthorized},
+		{name: "wrong secret", event: "release", signature: sign("other", release), body: release, want: http.StatusUnauthorized},
+		{name: "tampered body", event: "release", signature: sign(testWebhookSecret, release), body: strings.Replace(release, "v2.330.0", "v9.9.9", 1), want: http.StatusUnauthorized},
+		{name: "not hex", event: "release", signature: "sha256=zz", body: release, want: http.StatusUnauthorized},
+		{name: "no repository", event: "release", signature: sign(testWebhookSecret, `{"action": "published"}`), body: `{"action": "published"}`, want: http.StatusBadRequest},
+		{name: "not JSON", event: "release", signature: sign(testWebhookSecret, "release"), body: "release", want: http.StatusBadRequest},
+		{
+			name:      "repository not allowed",
+			event:     "release",
+			signature: sign(testWebhookSecret, `{"repository": {"full_name": "kubernetes/kubernetes"}}`),
+			body:      `{"repository": {"full_name": "kubernetes/kubernetes"}}`,
+			want:      http.StatusNotFound,
+		},
+	}
+
+	_, _, proxy := newWebhookProxy(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, body := postWebhook(t, proxy.URL, tt.event, tt.signature, tt.body); got != tt.want {
+				t.Errorf("status = %d, want %d: %s", got, tt.want, body)
+			}
+		})
+	}
+}
+
+func TestServer_WebhookDropsCache(t *testing.T) {
+	upstream, s, proxy := newWebhookProxy(t)
+	auth := http.Header{"Authorization": {"Bearer client-token"}}
+	releases := proxy.URL + "/repos/actions/runner/releases?per_page=5"
+	latest := proxy.URL + "/repos/actions/runner/releases/latest"
+	other := proxy.URL + "/repos/actions/checkout/releases"
+	for _, url := range []string{releases, latest, other} {
+		get(t, url, auth)
+	}
+	if got := s.cached.Load(); got != 3 {
+		t.Fatalf("cached responses = %d, want 3", got)
+	}
+
+	// GitHub names the repository as it is cased, which requests need not match
+	body := `{"action": "published", "repository": {"id": 1, "full_name": "Actions/Runner"}}`
+	status, data := postWebhook(t, proxy.URL, "release", sign(testWebhookSecret, body), body)
+	if status != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", status, data)
+	}
+	var result WebhookResult
+	if err := json.Unmarshal([]byte(data), &result); err != nil {
+		t.Fatalf("invalid result %q: %v", data, err)
+	}
+	if result.Repository != "Actions/Runner" || result.Dropped != 2 {
+		t.Errorf("result = %+v, want Actions/Runner with 2 dropped", result)
+	}
+	if got := s.cached.Load(); got != 1 {
+		t.Errorf("cached responses = %d, want 1", got)
+	}
+
+	sent := len(upstream.Requests())
+	if resp, _ := get(t, releases, auth); resp.Header.Get("X-Cache") != cacheMiss {
+		t.Errorf("after the webhook: X-Cache = %q, want %q", resp.Header.Get("X-Cache"), cacheMiss)
+	}
+	if resp, _ := get(t, other, auth); resp.Header.Get("X-Cache") != cacheHit {
+		t.Errorf("other repository: X-Cache = %q, want %q", resp.Header.Get("X-Cache"), cacheHit)
+	}
+	if got := len(upstream.Requests()) - sent; got != 1 {
+		t.Errorf("upstream requests after the webhook = %d, want 1", got)
+	}
+}
+
+func TestServer_WebhookWithoutSecret(t *testing.T) {
+	_, proxy, _ := newTestProxy(t)
+	body := `{"repository": {"full_name": "actions/runner"}}`
+	if got, _ := postWebhook(t, proxy.URL, "release", sign("", body), body); got != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", got)
+	}
+}
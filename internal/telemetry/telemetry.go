@@ -0,0 +1,540 @@
+// Package telemetry records OpenTelemetry trace spans and exports them with
+// OTLP over HTTP (JSON encoding). It covers what the CLI needs without the
+// OpenTelemetry SDK: spans, attributes, events, W3C trace context from the
+// TRACEPARENT environment variable, and the standard OTEL_* exporter settings.
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SpanKind is the OTLP span kind
+type SpanKind int
+
+const (
+	KindInternal SpanKind = 1
+	KindServer   SpanKind = 2
+	KindClient   SpanKind = 3
+)
+
+// Batching, as the OpenTelemetry SDK's batch span processor does by default
+const (
+	ExportInterval = 5 * time.Second // How often ExportEvery sends ended spans
+	MaxExportBatch = 512             // Spans per export request; this many waiting triggers an early export
+	MaxQueuedSpans = 2048            // Spans held awaiting export; later spans are dropped
+)
+
+// statusError is the OTLP status code for a failed span; zero is unset
+const statusError = 2
+
+// Attribute is a span or event attribute; Value is a string, int, int64 or bool
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// String returns a string attribute
+func String(key, value string) Attribute { return Attribute{key, value} }
+
+// Int returns an integer attribute
+func Int(key string, value int) Attribute { return Attribute{key, value} }
+
+// Bool returns a boolean attribute
+func Bool(key string, value bool) Attribute { return Attribute{key, value} }
+
+// Tracer collects spans and exports them in batches, with ExportEvery while
+// it runs and Export when it ends. A nil *Tracer records nothing, so callers
+// need not check whether tracing is on.
+type Tracer struct {
+	endpoint    string
+	headers     http.Header
+	serviceName string
+	version     string
+
+	traceID  [16]byte // The run's trace
+	parentID [8]byte  // From TRACEPARENT; zero when the trace starts here
+
+	mu      sync.Mutex
+	spans   []*Span       // Awaiting export
+	dropped int           // Spans not recorded because the queue was full
+	full    chan struct{} // Signalled when a batch of spans is waiting
+}
+
+// Span is one timed operation. A nil *Span ignores every call.
+type Span struct {
+	traceID  [16]byte
+	id       [8]byte
+	parentID [8]byte
+	name     string
+	kind     SpanKind
+	start    time.Time
+
+	mu         sync.Mutex
+	end        time.Time
+	attributes []Attribute
+	events     []event
+	status     int
+	message    string
+}
+
+// event is a timestamped note on a span
+type event struct {
+	name       string
+	time       time.Time
+	attributes []Attribute
+}
+
+// FromEnv returns a tracer configured by the standard OpenTelemetry environment
+// variables, or nil when no OTLP endpoint is set or the SDK is disabled
+func FromEnv(getenv func(string) string, version string) (*Tracer, error) {
+	if strings.EqualFold(getenv("OTEL_SDK_DISABLED"), "true") || strings.EqualFold(getenv("OTEL_TRACES_EXPORTER"), "none") {
+		return nil, nil
+	}
+
+	endpoint := getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT")
+	if endpoint == "" {
+		base := getenv("OTEL_EXPORTER_OTLP_ENDPOINT")
+		if base == "" {
+			return nil, nil
+		}
+		endpoint = strings.TrimRight(base, "/") + "/v1/traces"
+	}
+	if u, err := url.Parse(endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, fmt.Errorf("invalid OTLP endpoint %q: must be an http or https URL", endpoint)
+	}
+
+	headers, err := parseHeaders(getenv("OTEL_EXPORTER_OTLP_TRACES_HEADERS") + "," + getenv("OTEL_EXPORTER_OTLP_HEADERS"))
+	if err != nil {
+		return nil, err
+	}
+
+	t := &Tracer{
+		full:        make(chan struct{}, 1),
+		endpoint:    endpoint,
+		headers:     headers,
+		serviceName: getenv("OTEL_SERVICE_NAME"),
+		version:     version,
+	}
+	if t.serviceName == "" {
+		t.serviceName = "github-release-version-checker"
+	}
+	if traceID, parentID, ok := parseTraceparent(getenv("TRACEPARENT")); ok {
+		t.traceID, t.parentID = traceID, parentID
+	} else {
+		t.traceID = randomID16()
+	}
+	return t, nil
+}
+
+// parseHeaders parses comma-separated key=value pairs with URL-encoded values,
+// as in OTEL_EXPORTER_OTLP_HEADERS
+func parseHeaders(s string) (http.Header, error) {
+	headers := http.Header{}
+	for _, pair := range strings.Split(s, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid OTLP header %q: use key=value", pair)
+		}
+		decoded, err := url.QueryUnescape(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid OTLP header %q: %w", pair, err)
+		}
+		headers.Set(key, decoded)
+	}
+	return headers, nil
+}
+
+// parseTraceparent reads a W3C traceparent header value: 00-<trace id>-<span id>-<flags>
+func parseTraceparent(s string) (traceID [16]byte, spanID [8]byte, ok bool) {
+	parts := strings.Split(strings.TrimSpace(s), "-")
+	if len(parts) != 4 || parts[0] != "00" || len(parts[1]) != 32 || len(parts[2]) != 16 {
+		return traceID, spanID, false
+	}
+	if _, err := hex.Decode(traceID[:], []byte(parts[1])); err != nil || traceID == [16]byte{} {
+		return traceID, spanID, false
+	}
+	if _, err := hex.Decode(spanID[:], []byte(parts[2])); err != nil || spanID == [8]byte{} {
+		return traceID, spanID, false
+	}
+	return traceID, spanID, true
+}
+
+func randomID16() (id [16]byte) {
+	rand.Read(id[:])
+	return id
+}
+
+func randomID8() (id [8]byte) {
+	rand.Read(id[:])
+	return id
+}
+
+// spanKey is the context key for the current span
+type spanKey struct{}
+
+// SpanFromContext returns the span started by Start for ctx, or nil
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+// Start begins a span as a child of the span in ctx, or of the run's trace
+// when there is none, returning a context that carries it. On a nil tracer it
+// returns ctx and a nil span.
+func (t *Tracer) Start(ctx context.Context, name string, kind SpanKind, attrs ...Attribute) (context.Context, *Span) {
+	if t == nil {
+		return ctx, nil
+	}
+	span := &Span{traceID: t.traceID, parentID: t.parentID}
+	if parent := SpanFromContext(ctx); parent != nil {
+		span.traceID, span.parentID = parent.traceID, parent.id
+	}
+	return t.start(ctx, span, name, kind, attrs)
+}
+
+// StartTrace begins a span in a trace of its own, whatever span ctx carries,
+// for work such as one request to a long-running server. On a nil tracer it
+// returns ctx and a nil span.
+func (t *Tracer) StartTrace(ctx context.Context, name string, kind SpanKind, attrs ...Attribute) (context.Context, *Span) {
+	if t == nil {
+		return ctx, nil
+	}
+	return t.start(ctx, &Span{traceID: randomID16()}, name, kind, attrs)
+}
+
+// start records span, queueing it for export unless the queue is full
+func (t *Tracer) start(ctx context.Context, span *Span, name string, kind SpanKind, attrs []Attribute) (context.Context, *Span) {
+	span.id = randomID8()
+	span.name = name
+	span.kind = kind
+	span.start = time.Now()
+	span.attributes = attrs
+
+	t.mu.Lock()
+	if len(t.spans) < MaxQueuedSpans {
+		t.spans = append(t.spans, span)
+	} else {
+		t.dropped++
+	}
+	if len(t.spans) >= MaxExportBatch {
+		select {
+		case t.full <- struct{}{}:
+		default:
+		}
+	}
+	t.mu.Unlock()
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// ended reports whether End has been called
+func (s *Span) ended() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return !s.end.IsZero()
+}
+
+// SetAttributes adds attributes to the span
+func (s *Span) SetAttributes(attrs ...Attribute) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attributes = append(s.attributes, attrs...)
+}
+
+// AddEvent records a timestamped event on the span
+func (s *Span) AddEvent(name string, attrs ...Attribute) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, event{name: name, time: time.Now(), attributes: attrs})
+}
+
+// RecordError marks the span as failed with err's message; nil is ignored
+func (s *Span) RecordError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.setError(err.Error())
+	s.AddEvent("exception", String("exception.message", err.Error()))
+}
+
+// setError marks the span as failed with message
+func (s *Span) setError(message string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status = statusError
+	s.message = message
+}
+
+// End finishes the span; later calls are ignored
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.end.IsZero() {
+		s.end = time.Now()
+	}
+}
+
+// Instrument is a client request hook that records each HTTP request as a
+// client span under the span in the request's context
+func (t *Tracer) Instrument(req *http.Request) func(*http.Response, error) {
+	_, span := t.Start(req.Context(), "HTTP "+req.Method, KindClient,
+		String("http.request.method", req.Method),
+		String("url.full", req.URL.Redacted()),
+		String("server.address", req.URL.Hostname()),
+	)
+	return func(resp *http.Response, err error) {
+		if err != nil {
+			span.RecordError(err)
+		} else {
+			span.SetAttributes(Int("http.response.status_code", resp.StatusCode))
+			if remaining := resp.Header.Get("X-RateLimit-Remaining"); remaining != "" {
+				span.SetAttributes(String("github.rate_limit.remaining", remaining))
+			}
+			if resp.StatusCode >= 400 {
+				span.setError(resp.Status)
+			}
+		}
+		span.End()
+	}
+}
+
+// ExportEvery exports ended spans every interval, and as soon as a batch of
+// them is waiting, until ctx is done, reporting failures to onError. Spans
+// still open wait for a later export; Export sends the rest.
+func (t *Tracer) ExportEvery(ctx context.Context, client *http.Client, interval time.Duration, onError func(error)) {
+	if t == nil {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-t.full:
+		}
+		// An export under way finishes, within interval, even as ctx ends
+		exportCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), interval)
+		err := t.export(exportCtx, client, false)
+		cancel()
+		if err != nil && onError != nil {
+			onError(err)
+		}
+	}
+}
+
+// Export sends every span to the OTLP endpoint. Spans still open are ended
+// first, so a failed run is still traced.
+func (t *Tracer) Export(ctx context.Context, client *http.Client) error {
+	if t == nil {
+		return nil
+	}
+	return t.export(ctx, client, true)
+}
+
+// export sends the ended spans, or all of them, in batches of MaxExportBatch,
+// dropping them once sent or failed so memory stays bounded
+func (t *Tracer) export(ctx context.Context, client *http.Client, all bool) error {
+	t.mu.Lock()
+	var spans, open []*Span
+	for _, span := range t.spans {
+		if all || span.ended() {
+			spans = append(spans, span)
+		} else {
+			open = append(open, span)
+		}
+	}
+	t.spans = open
+	dropped := t.dropped
+	t.dropped = 0
+	t.mu.Unlock()
+
+	var errs []error
+	if dropped > 0 {
+		errs = append(errs, fmt.Errorf("dropped %d spans: more than %d awaited export", dropped, MaxQueuedSpans))
+	}
+	for len(spans) > 0 {
+		batch := spans[:min(len(spans), MaxExportBatch)]
+		spans = spans[len(batch):]
+		if err := t.send(ctx, client, batch); err != nil {
+			errs = append(errs, err)
+			break
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// send posts one batch of spans to the OTLP endpoint
+func (t *Tracer) send(ctx context.Context, client *http.Client, spans []*Span) error {
+	body, err := json.Marshal(t.request(spans))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header = t.headers.Clone()
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to export traces: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("failed to export traces: %s returned %s", t.endpoint, resp.Status)
+	}
+	return nil
+}
+
+// OTLP/HTTP JSON request body
+type (
+	otlpRequest struct {
+		ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+	}
+	otlpResourceSpans struct {
+		Resource   otlpResource     `json:"resource"`
+		ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+	}
+	otlpResource struct {
+		Attributes []otlpAttribute `json:"attributes"`
+	}
+	otlpScopeSpans struct {
+		Scope otlpScope  `json:"scope"`
+		Spans []otlpSpan `json:"spans"`
+	}
+	otlpScope struct {
+		Name    string `json:"name"`
+		Version string `json:"version,omitempty"`
+	}
+	otlpSpan struct {
+		TraceID           string          `json:"traceId"`
+		SpanID            string          `json:"spanId"`
+		ParentSpanID      string          `json:"parentSpanId,omitempty"`
+		Name              string          `json:"name"`
+		Kind              SpanKind        `json:"kind"`
+		StartTimeUnixNano string          `json:"startTimeUnixNano"`
+		EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+		Attributes        []otlpAttribute `json:"attributes,omitempty"`
+		Events            []otlpEvent     `json:"events,omitempty"`
+		Status            otlpStatus      `json:"status"`
+	}
+	otlpEvent struct {
+		TimeUnixNano string          `json:"timeUnixNano"`
+		Name         string          `json:"name"`
+		Attributes   []otlpAttribute `json:"attributes,omitempty"`
+	}
+	otlpStatus struct {
+		Code    int    `json:"code,omitempty"`
+		Message string `json:"message,omitempty"`
+	}
+	otlpAttribute struct {
+		Key   string    `json:"key"`
+		Value otlpValue `json:"value"`
+	}
+	otlpValue struct {
+		StringValue *string `json:"stringValue,omitempty"`
+		IntValue    *string `json:"intValue,omitempty"` // int64 values are strings in OTLP JSON
+		BoolValue   *bool   `json:"boolValue,omitempty"`
+	}
+)
+
+// request builds the OTLP request body for spans
+func (t *Tracer) request(spans []*Span) otlpRequest {
+	resource := []otlpAttribute{
+		otlpAttr(String("service.name", t.serviceName)),
+		otlpAttr(String("service.version", t.version)),
+	}
+
+	var out []otlpSpan
+	for _, s := range spans {
+		s.mu.Lock()
+		if s.end.IsZero() {
+			s.end = time.Now()
+		}
+		span := otlpSpan{
+			TraceID:           hex.EncodeToString(s.traceID[:]),
+			SpanID:            hex.EncodeToString(s.id[:]),
+			Name:              s.name,
+			Kind:              s.kind,
+			StartTimeUnixNano: unixNano(s.start),
+			EndTimeUnixNano:   unixNano(s.end),
+			Attributes:        otlpAttrs(s.attributes),
+			Status:            otlpStatus{Code: s.status, Message: s.message},
+		}
+		if s.parentID != [8]byte{} {
+			span.ParentSpanID = hex.EncodeToString(s.parentID[:])
+		}
+		for _, e := range s.events {
+			span.Events = append(span.Events, otlpEvent{TimeUnixNano: unixNano(e.time), Name: e.name, Attributes: otlpAttrs(e.attributes)})
+		}
+		s.mu.Unlock()
+		out = append(out, span)
+	}
+
+	return otlpRequest{ResourceSpans: []otlpResourceSpans{{
+		Resource:   otlpResource{Attributes: resource},
+		ScopeSpans: []otlpScopeSpans{{Scope: otlpScope{Name: t.serviceName, Version: t.version}, Spans: out}},
+	}}}
+}
+
+func otlpAttrs(attrs []Attribute) []otlpAttribute {
+	var out []otlpAttribute
+	for _, a := range attrs {
+		out = append(out, otlpAttr(a))
+	}
+	return out
+}
+
+func otlpAttr(a Attribute) otlpAttribute {
+	var v otlpValue
+	switch value := a.Value.(type) {
+	case bool:
+		v.BoolValue = &value
+	case int:
+		s := strconv.Itoa(value)
+		v.IntValue = &s
+	case int64:
+		s := strconv.FormatInt(value, 10)
+		v.IntValue = &s
+	default:
+		s := fmt.Sprint(value)
+		v.StringValue = &s
+	}
+	return otlpAttribute{Key: a.Key, Value: v}
+}
+
+func unixNano(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
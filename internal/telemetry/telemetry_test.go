@@ -0,0 +1,346 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// env returns a getenv function backed by vars
+func env(vars map[string]string) func(string) string {
+	return func(key string) string { return vars[key] }
+}
+
+// TestFromEnv tests which environments enable tracing and where spans are sent
+func TestFromEnv(t *testing.T) {
+	tests := []struct {
+		name         string
+		vars         map[string]string
+		wantEnabled  bool
+		wantEndpoint string
+		wantErr      bool
+	}{
+		{name: "no endpoint", vars: map[string]string{}},
+		{
+			name:         "base endpoint",
+			vars:         map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318/"},
+			wantEnabled:  true,
+			wantEndpoint: "http://collector:4318/v1/traces",
+		},
+		{
+			name: "traces endpoint wins",
+			vars: map[string]string{
+				"OTEL_EXPORTER_OTLP_ENDPOINT":        "http://collector:4318",
+				"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT": "https://traces.example.com/ingest",
+			},
+			wantEnabled:  true,
+			wantEndpoint: "https://traces.example.com/ingest",
+		},
+		{
+			name: "sdk disabled",
+			vars: map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318", "OTEL_SDK_DISABLED": "true"},
+		},
+		{
+			name: "exporter none",
+			vars: map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318", "OTEL_TRACES_EXPORTER": "none"},
+		},
+		{
+			name:    "grpc endpoint",
+			vars:    map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "collector:4317"},
+			wantErr: true,
+		},
+		{
+			name:    "bad header",
+			vars:    map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318", "OTEL_EXPORTER_OTLP_HEADERS": "api-key"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracer, err := FromEnv(env(tt.vars), "1.0.0")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FromEnv() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if (tracer != nil) != tt.wantEnabled {
+				t.Fatalf("FromEnv() enabled = %v, want %v", tracer != nil, tt.wantEnabled)
+			}
+			if tracer != nil && tracer.endpoint != tt.wantEndpoint {
+				t.Errorf("endpoint = %q, want %q", tracer.endpoint, tt.wantEndpoint)
+			}
+		})
+	}
+}
+
+// TestParseTraceparent tests joining a trace started by a parent process
+func TestParseTraceparent(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  string
+		wantOK bool
+	}{
+		{name: "valid", value: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", wantOK: true},
+		{name: "empty", value: ""},
+		{name: "unknown version", value: "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+		{name: "zero trace id", value: "00-00000000000000000000000000000000-00f067aa0ba902b7-01"},
+		{name: "not hex", value: "00-4bf92f3577b34da6a3ce929d0e0e47zz-00f067aa0ba902b7-01"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, ok := parseTraceparent(tt.value); ok != tt.wantOK {
+				t.Errorf("parseTraceparent(%q) ok = %v, want %v", tt.value, ok, tt.wantOK)
+			}
+		})
+	}
+}
+
+// TestNilTracer tests that a disabled tracer and its spans are safe to use
+func TestNilTracer(t *testing.T) {
+	var tracer *Tracer
+	ctx, span := tracer.Start(context.Background(), "run", KindInternal)
+	span.SetAttributes(String("k", "v"))
+	span.AddEvent("event")
+	span.RecordError(errors.New("failed"))
+	span.End()
+	if SpanFromContext(ctx) != nil {
+		t.Error("nil tracer put a span in the context")
+	}
+	if err := tracer.Export(ctx, http.DefaultClient); err != nil {
+		t.Errorf("Export() error = %v", err)
+	}
+}
+
+// TestExport tests the OTLP request a trace is exported as
+func TestExport(t *testing.T) {
+	var got otlpRequest
+	var gotHeaders http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeaders = r.Header.Clone()
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decoding export: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	tracer, err := FromEnv(env(map[string]string{
+		"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT": server.URL + "/v1/traces",
+		"OTEL_EXPORTER_OTLP_HEADERS":         "x-api-key=s%3Dcret",
+		"TRACEPARENT":                        "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	}), "1.2.3")
+	if err != nil {
+		t.Fatalf("FromEnv() error = %v", err)
+	}
+
+	ctx, root := tracer.Start(context.Background(), "run", KindInternal)
+	_, child := tracer.Start(ctx, "analyse", KindInternal, String("repository", "actions/runner"), Int("releases_behind", 2), Bool("degraded", false))
+	child.RecordError(errors.New("not found"))
+	child.End()
+	root.End()
+
+	if err := tracer.Export(context.Background(), server.Client()); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	if v := gotHeaders.Get("X-Api-Key"); v != "s=cret" {
+		t.Errorf("X-Api-Key = %q, want s=cret", v)
+	}
+	if len(got.ResourceSpans) != 1 || len(got.ResourceSpans[0].ScopeSpans) != 1 {
+		t.Fatalf("unexpected export shape: %+v", got)
+	}
+	spans := got.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 2 {
+		t.Fatalf("exported %d spans, want 2", len(spans))
+	}
+	run, analyse := spans[0], spans[1]
+
+	for _, s := range spans {
+		if s.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+			t.Errorf("%s traceId = %s, want the TRACEPARENT trace", s.Name, s.TraceID)
+		}
+	}
+	if run.ParentSpanID != "00f067aa0ba902b7" {
+		t.Errorf("run parentSpanId = %s, want the TRACEPARENT span", run.ParentSpanID)
+	}
+	if analyse.ParentSpanID != run.SpanID {
+		t.Errorf("analyse parentSpanId = %s, want %s", analyse.ParentSpanID, run.SpanID)
+	}
+	if analyse.Status.Code != statusError || analyse.Status.Message != "not found" {
+		t.Errorf("analyse status = %+v, want error \"not found\"", analyse.Status)
+	}
+	if len(analyse.Attributes) != 3 || *analyse.Attributes[1].Value.IntValue != "2" || *analyse.Attributes[2].Value.BoolValue {
+		t.Errorf("analyse attributes = %+v", analyse.Attributes)
+	}
+
+	// Exported spans are not sent again
+	if len(tracer.spans) != 0 {
+		t.Errorf("%d spans left after export", len(tracer.spans))
+	}
+}
+
+// TestInstrument tests HTTP requests are recorded as client spans
+func TestInstrument(t *testing.T) {
+	tracer, _ := FromEnv(env(map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318"}), "1.0.0")
+	ctx, root := tracer.Start(context.Background(), "run", KindInternal)
+
+	req := httptest.NewRequest(http.MethodGet, "https://api.github.com/repos/actions/runner/releases", nil).WithContext(ctx)
+	done := tracer.Instrument(req)
+	done(&http.Response{StatusCode: http.StatusNotFound, Status: "404 Not Found", Header: http.Header{}}, nil)
+
+	spans := tracer.request(tracer.spans).ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 2 {
+		t.Fatalf("recorded %d spans, want 2", len(spans))
+	}
+	span := spans[1]
+	if span.Name != "HTTP GET" || span.Kind != KindClient || span.ParentSpanID != spans[0].SpanID {
+		t.Errorf("span = %s kind %d parent %s, want HTTP GET client span under run", span.Name, span.Kind, span.ParentSpanID)
+	}
+	if span.Status.Code != statusError {
+		t.Errorf("404 status = %+v, want error", span.Status)
+	}
+	root.End()
+}
+
+// collector is a fake OTLP endpoint recording the spans of each export
+type collector struct {
+	*httptest.Server
+	mu      sync.Mutex
+	exports [][]otlpSpan
+}
+
+func newCollector(t *testing.T) *collector {
+	t.Helper()
+	c := &collector{}
+	c.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req otlpRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decoding export: %v", err)
+			return
+		}
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.exports = append(c.exports, req.ResourceSpans[0].ScopeSpans[0].Spans)
+	}))
+	t.Cleanup(c.Close)
+	return c
+}
+
+// spans returns the names of every span exported so far
+func (c *collector) spans() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var names []string
+	for _, export := range c.exports {
+		for _, span := range export {
+			names = append(names, span.Name)
+		}
+	}
+	return names
+}
+
+// waitFor polls until c has received n spans
+func (c *collector) waitFor(t *testing.T, n int) {
+	t.Helper()
+	for deadline := time.Now().Add(5 * time.Second); len(c.spans()) < n; {
+		if time.Now().After(deadline) {
+			t.Fatalf("exported %d spans, want %d", len(c.spans()), n)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// TestExportEvery tests that ended spans are exported while the tracer runs,
+// and dropped once sent, while open ones wait
+func TestExportEvery(t *testing.T) {
+	c := newCollector(t)
+	tracer, _ := FromEnv(env(map[string]string{"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT": c.URL}), "1.0.0")
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		tracer.ExportEvery(ctx, c.Client(), 10*time.Millisecond, func(err error) { t.Errorf("export error = %v", err) })
+	}()
+
+	runCtx, run := tracer.Start(context.Background(), "run", KindInternal)
+	_, check := tracer.Start(runCtx, "check", KindInternal)
+	check.End()
+	c.waitFor(t, 1)
+	cancel()
+	<-done
+
+	if got := c.spans(); len(got) != 1 || got[0] != "check" {
+		t.Errorf("exported %v, want only the ended check", got)
+	}
+	if len(tracer.spans) != 1 || tracer.spans[0] != run {
+		t.Errorf("%d spans queued, want the open run span", len(tracer.spans))
+	}
+
+	if err := tracer.Export(context.Background(), c.Client()); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if got := c.spans(); len(got) != 2 || got[1] != "run" {
+		t.Errorf("exported %v, want the run span last", got)
+	}
+}
+
+// TestExportEvery_FullBatch tests that a full batch is exported without
+// waiting for the interval
+func TestExportEvery_FullBatch(t *testing.T) {
+	c := newCollector(t)
+	tracer, _ := FromEnv(env(map[string]string{"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT": c.URL}), "1.0.0")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go tracer.ExportEvery(ctx, c.Client(), time.Hour, nil)
+
+	for i := 0; i < MaxExportBatch; i++ {
+		_, span := tracer.Start(context.Background(), "check", KindInternal)
+		span.End()
+	}
+	c.waitFor(t, MaxExportBatch)
+}
+
+// TestExport_Bounded tests that spans beyond the queue are dropped and
+// reported, and the rest sent in batches
+func TestExport_Bounded(t *testing.T) {
+	c := newCollector(t)
+	tracer, _ := FromEnv(env(map[string]string{"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT": c.URL}), "1.0.0")
+	for i := 0; i < MaxQueuedSpans+3; i++ {
+		_, span := tracer.Start(context.Background(), "check", KindInternal)
+		span.End()
+	}
+
+	err := tracer.Export(context.Background(), c.Client())
+	if err == nil || !strings.Contains(err.Error(), "dropped 3 spans") {
+		t.Errorf("Export() error = %v, want 3 spans dropped", err)
+	}
+	if len(c.spans()) != MaxQueuedSpans || len(c.exports) != MaxQueuedSpans/MaxExportBatch {
+		t.Errorf("exported %d spans in %d requests, want %d in %d", len(c.spans()), len(c.exports), MaxQueuedSpans, MaxQueuedSpans/MaxExportBatch)
+	}
+}
+
+// TestStartTrace tests that a trace started for a request is separate from the
+// run's, and its children join it
+func TestStartTrace(t *testing.T) {
+	tracer, _ := FromEnv(env(map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318"}), "1.0.0")
+	runCtx, _ := tracer.Start(context.Background(), "run", KindInternal)
+	first, _ := tracer.StartTrace(runCtx, "check", KindServer)
+	tracer.Start(first, "analyse", KindInternal)
+	tracer.StartTrace(runCtx, "check", KindServer)
+
+	spans := tracer.request(tracer.spans).ResourceSpans[0].ScopeSpans[0].Spans
+	run, check, analyse, other := spans[0], spans[1], spans[2], spans[3]
+	if check.TraceID == run.TraceID || other.TraceID == check.TraceID {
+		t.Errorf("trace ids = %s, %s and %s, want each check in its own trace", run.TraceID, check.TraceID, other.TraceID)
+	}
+	if check.ParentSpanID != "" {
+		t.Errorf("check parentSpanId = %s, want none", check.ParentSpanID)
+	}
+	if analyse.TraceID != check.TraceID || analyse.ParentSpanID != check.SpanID {
+		t.Errorf("analyse = trace %s parent %s, want under the check", analyse.TraceID, analyse.ParentSpanID)
+	}
+}
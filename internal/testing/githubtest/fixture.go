@@ -0,0 +1,147 @@
+// Package githubtest provides a fake GitHub REST API for tests, serving releases
+// recorded from real repositories. It covers the endpoints the client uses,
+// with GitHub's pagination links, conditional requests (ETag / 304) and
+// primary and secondary rate limits.
+package githubtest
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+//go:embed fixtures/*.json
+var fixtures embed.FS
+
+// Fixture is a repository's releases as returned by the GitHub API, newest first
+type Fixture struct {
+	Repository string    `json:"repository"` // owner/repo
+	RecordedAt time.Time `json:"recorded_at"`
+	LatestTag  string    `json:"latest_tag,omitempty"` // Marked latest release; empty if none
+	Releases   []Release `json:"releases"`
+}
+
+// Release is the subset of a GitHub release the client reads
+type Release struct {
+	TagName     string     `json:"tag_name"`
+	Name        string     `json:"name,omitempty"`
+	Draft       bool       `json:"draft"`
+	Prerelease  bool       `json:"prerelease"`
+	PublishedAt *time.Time `json:"published_at"` // Nil for drafts
+	HTMLURL     string     `json:"html_url"`
+}
+
+// FixtureFile returns the file name a repository's fixture is stored under,
+// e.g. actions-runner.json
+func FixtureFile(repository string) string {
+	return strings.ReplaceAll(repository, "/", "-") + ".json"
+}
+
+// LoadFixture returns a recorded fixture by repository, e.g. "actions/runner"
+func LoadFixture(repository string) (*Fixture, error) {
+	data, err := fixtures.ReadFile("fixtures/" + FixtureFile(repository))
+	if err != nil {
+		return nil, fmt.Errorf("no fixture recorded for %s: %w", repository, err)
+	}
+	var f Fixture
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse fixture for %s: %w", repository, err)
+	}
+	return &f, nil
+}
+
+// MustLoadFixture is LoadFixture for tests, panicking on a missing fixture
+func MustLoadFixture(repository string) *Fixture {
+	f, err := LoadFixture(repository)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// Save writes the fixture to dir as indented JSON
+func (f *Fixture) Save(dir string) (string, error) {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, FixtureFile(f.Repository))
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return "", fmt.Errorf("failed to write fixture: %w", err)
+	}
+	return path, nil
+}
+
+// errNotFound reports a 404, which for the latest release means none is marked
+var errNotFound = errors.New("not found")
+
+// nextLink matches the next page in a Link header
+var nextLink = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// Record fetches up to maxPages pages of a repository's releases, drafts and
+// prereleases included, and its marked latest release. apiURL is the API root,
+// e.g. https://api.github.com; headers are sent with every request.
+func Record(ctx context.Context, httpClient *http.Client, apiURL string, headers http.Header, repository string, maxPages int) (*Fixture, error) {
+	base := strings.TrimRight(apiURL, "/") + "/repos/" + repository
+	f := &Fixture{Repository: repository, RecordedAt: time.Now().UTC()}
+
+	next := base + "/releases?per_page=100"
+	for page := 1; next != "" && page <= maxPages; page++ {
+		var releases []Release
+		link, err := getJSON(ctx, httpClient, next, headers, &releases)
+		if err != nil {
+			return nil, err
+		}
+		f.Releases = append(f.Releases, releases...)
+
+		next = ""
+		if m := nextLink.FindStringSubmatch(link); m != nil {
+			next = m[1]
+		}
+	}
+
+	var latest Release
+	if _, err := getJSON(ctx, httpClient, base+"/releases/latest", headers, &latest); err != nil && !errors.Is(err, errNotFound) {
+		return nil, err
+	}
+	f.LatestTag = latest.TagName
+	return f, nil
+}
+
+// getJSON decodes a GET response into v, returning its Link header
+func getJSON(ctx context.Context, httpClient *http.Client, url string, headers http.Header, v any) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	for name, values := range headers {
+		req.Header[name] = values
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return "", fmt.Errorf("failed to fetch %s: %w", url, errNotFound)
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("failed to fetch %s: %s: %s", url, resp.Status, strings.TrimSpace(string(body)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return "", fmt.Errorf("failed to decode %s: %w", url, err)
+	}
+	return resp.Header.Get("Link"), nil
+}
@@ -0,0 +1,217 @@
+package githubtest
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultRateLimit is the unauthenticated core rate limit
+const DefaultRateLimit = 60
+
+// Server is a fake GitHub API serving fixtures. Close it when done.
+type Server struct {
+	*httptest.Server
+
+	mu         sync.Mutex
+	fixtures   map[string]*Fixture
+	limit      int
+	remaining  int
+	reset      time.Time
+	retryAfter int // Seconds; refuses the next request with a secondary rate limit
+	requests   []string
+}
+
+// NewServer starts a server for the given fixtures with a fresh rate-limit window
+func NewServer(fixtures ...*Fixture) *Server {
+	s := &Server{
+		fixtures:  make(map[string]*Fixture),
+		limit:     DefaultRateLimit,
+		remaining: DefaultRateLimit,
+		reset:     time.Now().Add(time.Hour).Truncate(time.Second),
+	}
+	for _, f := range fixtures {
+		s.fixtures[f.Repository] = f
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// SetRateLimit sets the requests allowed per window and how many are left
+func (s *Server) SetRateLimit(limit, remaining int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.limit, s.remaining = limit, remaining
+}
+
+// SecondaryRateLimit refuses the next request with a secondary rate limit,
+// asking the client to wait retryAfter seconds
+func (s *Server) SecondaryRateLimit(retryAfter int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.retryAfter = retryAfter
+}
+
+// Requests returns the path and query of every request received, in order
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, r.URL.RequestURI())
+
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed", "")
+		return
+	}
+
+	// The rate-limit endpoint is free, as on GitHub
+	if r.URL.Path == "/rate_limit" {
+		s.setRateHeaders(w)
+		writeJSON(w, http.StatusOK, map[string]any{"resources": map[string]any{"core": s.rate()}})
+		return
+	}
+
+	if s.retryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(s.retryAfter))
+		s.retryAfter = 0
+		s.setRateHeaders(w)
+		writeError(w, http.StatusForbidden, "You have exceeded a secondary rate limit.",
+			"https://docs.github.com/rest/overview/rate-limits-for-the-rest-api#about-secondary-rate-limits")
+		return
+	}
+	if s.remaining <= 0 {
+		s.setRateHeaders(w)
+		writeError(w, http.StatusForbidden, "API rate limit exceeded for 127.0.0.1.",
+			"https://docs.github.com/rest/overview/rate-limits-for-the-rest-api")
+		return
+	}
+
+	body, status, link := s.route(r)
+	data, _ := json.Marshal(body)
+	etag := fmt.Sprintf(`"%x"`, sha256.Sum256(data))
+
+	// Conditional requests answered with 304 do not count against the limit
+	if status == http.StatusOK && r.Header.Get("If-None-Match") == etag {
+		s.setRateHeaders(w)
+		w.Header().Set("ETag", etag)
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	s.remaining--
+	s.setRateHeaders(w)
+	if status == http.StatusOK {
+		w.Header().Set("ETag", etag)
+	}
+	if link != "" {
+		w.Header().Set("Link", link)
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	w.Write(data)
+}
+
+// route returns the response body, status and Link header for a request
+func (s *Server) route(r *http.Request) (any, int, string) {
+	notFound := errorBody("Not Found", "")
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 3 || parts[0] != "repos" {
+		return notFound, http.StatusNotFound, ""
+	}
+	f, ok := s.fixtures[parts[1]+"/"+parts[2]]
+	if !ok {
+		return notFound, http.StatusNotFound, ""
+	}
+
+	switch strings.Join(parts[3:], "/") {
+	case "":
+		return map[string]any{"full_name": f.Repository, "name": parts[2], "owner": map[string]string{"login": parts[1]}}, http.StatusOK, ""
+	case "releases":
+		releases, link := s.releasesPage(r, f)
+		return releases, http.StatusOK, link
+	case "releases/latest":
+		for _, release := range f.Releases {
+			if f.LatestTag != "" && release.TagName == f.LatestTag {
+				return release, http.StatusOK, ""
+			}
+		}
+	}
+	return notFound, http.StatusNotFound, ""
+}
+
+// releasesPage returns one page of releases and a Link header for the pages
+// around it, as GitHub does
+func (s *Server) releasesPage(r *http.Request, f *Fixture) ([]Release, string) {
+	query := r.URL.Query()
+	perPage, err := strconv.Atoi(query.Get("per_page"))
+	if err != nil || perPage <= 0 {
+		perPage = 30
+	}
+	perPage = min(perPage, 100)
+	page, err := strconv.Atoi(query.Get("page"))
+	if err != nil || page <= 0 {
+		page = 1
+	}
+
+	lastPage := max((len(f.Releases)+perPage-1)/perPage, 1)
+	pageURL := func(n int) string {
+		q := r.URL.Query()
+		q.Set("per_page", strconv.Itoa(perPage))
+		q.Set("page", strconv.Itoa(n))
+		return fmt.Sprintf("<%s%s?%s>", s.URL, r.URL.Path, q.Encode())
+	}
+	var links []string
+	if page < lastPage {
+		links = append(links, pageURL(page+1)+`; rel="next"`, pageURL(lastPage)+`; rel="last"`)
+	}
+	if page > 1 {
+		links = append(links, pageURL(page-1)+`; rel="prev"`, pageURL(1)+`; rel="first"`)
+	}
+
+	start := min((page-1)*perPage, len(f.Releases))
+	end := min(start+perPage, len(f.Releases))
+	return append([]Release{}, f.Releases[start:end]...), strings.Join(links, ", ")
+}
+
+// setRateHeaders reports the rate-limit window as GitHub does
+func (s *Server) setRateHeaders(w http.ResponseWriter) {
+	h := w.Header()
+	h.Set("X-RateLimit-Limit", strconv.Itoa(s.limit))
+	h.Set("X-RateLimit-Remaining", strconv.Itoa(max(s.remaining, 0)))
+	h.Set("X-RateLimit-Used", strconv.Itoa(s.limit-max(s.remaining, 0)))
+	h.Set("X-RateLimit-Reset", strconv.FormatInt(s.reset.Unix(), 10))
+	h.Set("X-RateLimit-Resource", "core")
+}
+
+func (s *Server) rate() map[string]any {
+	return map[string]any{"limit": s.limit, "remaining": max(s.remaining, 0), "reset": s.reset.Unix()}
+}
+
+func errorBody(message, documentationURL string) map[string]string {
+	body := map[string]string{"message": message}
+	if documentationURL != "" {
+		body["documentation_url"] = documentationURL
+	}
+	return body
+}
+
+func writeError(w http.ResponseWriter, status int, message, documentationURL string) {
+	writeJSON(w, status, errorBody(message, documentationURL))
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
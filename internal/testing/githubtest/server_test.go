@@ -0,0 +1,159 @@
+package githubtest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// get fetches path from the server with optional headers
+func get(t *testing.T, s *Server, path string, headers ...string) *http.Response {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodGet, s.URL+path, nil)
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	resp, err := s.Client().Do(req)
+	if err != nil {
+		t.Fatalf("GET %s: %v", path, err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+// TestLoadFixture tests the recorded fixtures are readable
+func TestLoadFixture(t *testing.T) {
+	f, err := LoadFixture("actions/runner")
+	if err != nil {
+		t.Fatalf("LoadFixture() error = %v", err)
+	}
+	if f.Repository != "actions/runner" || len(f.Releases) == 0 || f.LatestTag == "" {
+		t.Errorf("fixture = %s with %d releases, latest %q", f.Repository, len(f.Releases), f.LatestTag)
+	}
+	if _, err := LoadFixture("no/such-repo"); err == nil {
+		t.Error("LoadFixture() of an unrecorded repository succeeded")
+	}
+}
+
+// TestRecord tests recording from the fake server reproduces its fixture,
+// following pagination links
+func TestRecord(t *testing.T) {
+	want := MustLoadFixture("actions/runner")
+	s := NewServer(want)
+	defer s.Close()
+
+	got, err := Record(context.Background(), s.Client(), s.URL, nil, "actions/runner", 10)
+	if err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if got.LatestTag != want.LatestTag || !reflect.DeepEqual(got.Releases, want.Releases) {
+		t.Errorf("recorded %d releases, latest %q; want %d, latest %q", len(got.Releases), got.LatestTag, len(want.Releases), want.LatestTag)
+	}
+
+	pages := 0
+	for _, r := range s.Requests() {
+		if strings.Contains(r, "/releases?") {
+			pages++
+		}
+	}
+	if wantPages := (len(want.Releases) + 99) / 100; pages != wantPages {
+		t.Errorf("fetched %d pages, want %d", pages, wantPages)
+	}
+}
+
+// TestServerPagination tests the Link header of each page
+func TestServerPagination(t *testing.T) {
+	s := NewServer(MustLoadFixture("actions/runner"))
+	defer s.Close()
+
+	tests := []struct {
+		query     string
+		wantRels  []string
+		wantCount int
+	}{
+		{query: "?per_page=50&page=1", wantRels: []string{"next", "last"}, wantCount: 50},
+		{query: "?per_page=50&page=2", wantRels: []string{"next", "last", "prev", "first"}, wantCount: 50},
+		{query: "?per_page=100&page=2", wantRels: []string{"prev", "first"}, wantCount: 18},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			resp := get(t, s, "/repos/actions/runner/releases"+tt.query)
+			var releases []Release
+			if err := json.NewDecoder(resp.Body).Decode(&releases); err != nil {
+				t.Fatal(err)
+			}
+			if len(releases) != tt.wantCount {
+				t.Errorf("got %d releases, want %d", len(releases), tt.wantCount)
+			}
+			link := resp.Header.Get("Link")
+			for _, rel := range tt.wantRels {
+				if !strings.Contains(link, `rel="`+rel+`"`) {
+					t.Errorf("Link %q has no rel=%q", link, rel)
+				}
+			}
+		})
+	}
+}
+
+// TestServerConditionalRequest tests a matching If-None-Match is answered with
+// a 304 that does not use up the rate limit
+func TestServerConditionalRequest(t *testing.T) {
+	s := NewServer(MustLoadFixture("actions/runner"))
+	defer s.Close()
+
+	first := get(t, s, "/repos/actions/runner/releases/latest")
+	etag := first.Header.Get("ETag")
+	if etag == "" {
+		t.Fatal("no ETag on first response")
+	}
+
+	second := get(t, s, "/repos/actions/runner/releases/latest", "If-None-Match", etag)
+	if second.StatusCode != http.StatusNotModified {
+		t.Errorf("status = %d, want 304", second.StatusCode)
+	}
+	if got, want := second.Header.Get("X-RateLimit-Remaining"), first.Header.Get("X-RateLimit-Remaining"); got != want {
+		t.Errorf("remaining after 304 = %s, want %s", got, want)
+	}
+}
+
+// TestServerRateLimits tests primary and secondary rate-limit responses
+func TestServerRateLimits(t *testing.T) {
+	s := NewServer(MustLoadFixture("actions/runner"))
+	defer s.Close()
+
+	s.SecondaryRateLimit(30)
+	resp := get(t, s, "/repos/actions/runner")
+	if resp.StatusCode != http.StatusForbidden || resp.Header.Get("Retry-After") != "30" {
+		t.Errorf("secondary limit: status %d, Retry-After %q", resp.StatusCode, resp.Header.Get("Retry-After"))
+	}
+	if resp := get(t, s, "/repos/actions/runner"); resp.StatusCode != http.StatusOK {
+		t.Errorf("after secondary limit: status %d, want 200", resp.StatusCode)
+	}
+
+	s.SetRateLimit(60, 1)
+	if resp := get(t, s, "/repos/actions/runner"); resp.StatusCode != http.StatusOK || resp.Header.Get("X-RateLimit-Remaining") != "0" {
+		t.Errorf("last request: status %d, remaining %q", resp.StatusCode, resp.Header.Get("X-RateLimit-Remaining"))
+	}
+	if resp := get(t, s, "/repos/actions/runner"); resp.StatusCode != http.StatusForbidden {
+		t.Errorf("exhausted: status %d, want 403", resp.StatusCode)
+	}
+	if resp := get(t, s, "/rate_limit"); resp.StatusCode != http.StatusOK {
+		t.Errorf("rate_limit while exhausted: status %d, want 200", resp.StatusCode)
+	}
+}
+
+// TestServerNotFound tests unknown repositories and unmarked latest releases
+func TestServerNotFound(t *testing.T) {
+	unmarked := &Fixture{Repository: "owner/unmarked"}
+	s := NewServer(unmarked)
+	defer s.Close()
+
+	for _, path := range []string{"/repos/owner/missing/releases", "/repos/owner/unmarked/releases/latest", "/user"} {
+		if resp := get(t, s, path); resp.StatusCode != http.StatusNotFound {
+			t.Errorf("GET %s: status %d, want 404", path, resp.StatusCode)
+		}
+	}
+}
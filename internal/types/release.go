@@ -1,14 +1,11 @@
+// Package types aliases pkg/types for code written before the public API.
+//
+// Deprecated: use pkg/types, the stable v1 API.
 package types
 
-import (
-	"time"
+import "github.com/nickromney-org/github-release-version-checker/pkg/types"
 
-	"github.com/Masterminds/semver/v3"
-)
-
-// Release represents a GitHub release
-type Release struct {
-	Version     *semver.Version
-	PublishedAt time.Time
-	URL         string
-}
+// Release is an alias of types.Release from pkg/types
+//
+// Deprecated: use types.Release from pkg/types.
+type Release = types.Release
@@ -1,3 +1,7 @@
+// Package version is the original version checker, kept while callers move to
+// pkg/checker.
+//
+// Deprecated: use pkg/checker, the stable v1 API.
 package version
 
 import (
@@ -8,6 +12,7 @@ import (
 	"github.com/Masterminds/semver/v3"
 	"github.com/nickromney-org/github-release-version-checker/internal/data"
 	"github.com/nickromney-org/github-release-version-checker/pkg/policy"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
 )
 
 // GitHubClient defines the interface for fetching releases
@@ -18,6 +23,8 @@ type GitHubClient interface {
 }
 
 // Checker performs version analysis
+//
+// Deprecated: use checker.Checker.
 type Checker struct {
 	client GitHubClient
 	config CheckerConfig
@@ -25,6 +32,8 @@ type Checker struct {
 }
 
 // NewChecker creates a new version checker
+//
+// Deprecated: use checker.NewChecker.
 func NewChecker(client GitHubClient, config CheckerConfig) *Checker {
 	return &Checker{
 		client: client,
@@ -34,6 +43,8 @@ func NewChecker(client GitHubClient, config CheckerConfig) *Checker {
 }
 
 // NewCheckerWithPolicy creates a new version checker with a custom policy
+//
+// Deprecated: use checker.NewCheckerWithPolicy.
 func NewCheckerWithPolicy(client GitHubClient, config CheckerConfig, pol policy.VersionPolicy) *Checker {
 	return &Checker{
 		client: client,
@@ -483,7 +494,7 @@ func (c *Checker) generateMessage(analysis *Analysis) string {
 			analysis.ComparisonVersion,
 			prefix,
 			analysis.MinorVersionsBehind,
-			pluralSuffix(analysis.MinorVersionsBehind))
+			types.PluralSuffix(analysis.MinorVersionsBehind))
 
 		if analysis.IsExpired {
 			msg += fmt.Sprintf(" (maximum %d allowed)", maxAllowed)
@@ -499,7 +510,7 @@ func (c *Checker) generateMessage(analysis *Analysis) string {
 
 	// Count releases
 	if analysis.ReleasesBehind > 0 {
-		issues = append(issues, fmt.Sprintf("%d release%s behind", analysis.ReleasesBehind, pluralSuffix(analysis.ReleasesBehind)))
+		issues = append(issues, fmt.Sprintf("%d release%s behind", analysis.ReleasesBehind, types.PluralSuffix(analysis.ReleasesBehind)))
 	}
 
 	// Age status
@@ -531,14 +542,6 @@ func (c *Checker) generateMessage(analysis *Analysis) string {
 	return fmt.Sprintf("Version %s %s: %s", analysis.ComparisonVersion, prefix, issueStr)
 }
 
-// pluralSuffix returns "s" if count != 1, otherwise ""
-func pluralSuffix(count int) string {
-	if count == 1 {
-		return ""
-	}
-	return "s"
-}
-
 // daysBetween calculates the number of days between two dates
 func daysBetween(start, end time.Time) int {
 	duration := end.Sub(start)
@@ -20,6 +20,8 @@ const (
 )
 
 // Release is a type alias for types.Release for backward compatibility
+//
+// Deprecated: use types.Release from pkg/types.
 type Release = types.Release
 
 // ReleaseExpiry represents expiry information for a single release
@@ -52,6 +54,8 @@ func (r *ReleaseExpiry) MarshalJSON() ([]byte, error) {
 }
 
 // Analysis contains the full version analysis results
+//
+// Deprecated: use checker.Analysis.
 type Analysis struct {
 	LatestVersion         *semver.Version `json:"latest_version"`
 	ComparisonVersion     *semver.Version `json:"comparison_version,omitempty"`
@@ -120,6 +124,8 @@ func (a *Analysis) MarshalJSON() ([]byte, error) {
 }
 
 // CheckerConfig holds configuration for the version checker
+//
+// Deprecated: use checker.Config.
 type CheckerConfig struct {
 	CriticalAgeDays int
 	MaxAgeDays      int
@@ -6,6 +6,8 @@ import (
 	"github.com/nickromney-org/github-release-version-checker/cmd"
 )
 
+//go:generate go run . generate-cache
+
 // Version information (set via ldflags during build)
 var (
 	Version   = "dev"
@@ -18,6 +20,6 @@ func main() {
 	cmd.SetVersionInfo(Version, BuildTime, GitCommit)
 
 	if err := cmd.Execute(); err != nil {
-		os.Exit(1)
+		os.Exit(cmd.ExitCode(err))
 	}
 }
@@ -0,0 +1,225 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: releasechecker/v1/checker.proto
+
+// The release checker's gRPC API, served by `proxy --grpc-listen` alongside
+// its HTTP endpoints. Checks read releases through the proxy, so they share
+// its cache, GitHub token and repository allowlist.
+
+package releasecheckerv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	ReleaseChecker_Check_FullMethodName       = "/releasechecker.v1.ReleaseChecker/Check"
+	ReleaseChecker_CheckBatch_FullMethodName  = "/releasechecker.v1.ReleaseChecker/CheckBatch"
+	ReleaseChecker_CheckStream_FullMethodName = "/releasechecker.v1.ReleaseChecker/CheckStream"
+)
+
+// ReleaseCheckerClient is the client API for ReleaseChecker service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ReleaseCheckerClient interface {
+	// Check checks one repository and version.
+	Check(ctx context.Context, in *CheckRequest, opts ...grpc.CallOption) (*CheckResult, error)
+	// CheckBatch checks one page of a manifest; a failed check fails only its
+	// own result.
+	CheckBatch(ctx context.Context, in *CheckBatchRequest, opts ...grpc.CallOption) (*CheckBatchResponse, error)
+	// CheckStream checks every page of a manifest, sending each result as soon
+	// as it is ready, in any order.
+	CheckStream(ctx context.Context, in *CheckBatchRequest, opts ...grpc.CallOption) (ReleaseChecker_CheckStreamClient, error)
+}
+
+type releaseCheckerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewReleaseCheckerClient(cc grpc.ClientConnInterface) ReleaseCheckerClient {
+	return &releaseCheckerClient{cc}
+}
+
+func (c *releaseCheckerClient) Check(ctx context.Context, in *CheckRequest, opts ...grpc.CallOption) (*CheckResult, error) {
+	out := new(CheckResult)
+	err := c.cc.Invoke(ctx, ReleaseChecker_Check_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *releaseCheckerClient) CheckBatch(ctx context.Context, in *CheckBatchRequest, opts ...grpc.CallOption) (*CheckBatchResponse, error) {
+	out := new(CheckBatchResponse)
+	err := c.cc.Invoke(ctx, ReleaseChecker_CheckBatch_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *releaseCheckerClient) CheckStream(ctx context.Context, in *CheckBatchRequest, opts ...grpc.CallOption) (ReleaseChecker_CheckStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ReleaseChecker_ServiceDesc.Streams[0], ReleaseChecker_CheckStream_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &releaseCheckerCheckStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ReleaseChecker_CheckStreamClient interface {
+	Recv() (*CheckResult, error)
+	grpc.ClientStream
+}
+
+type releaseCheckerCheckStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *releaseCheckerCheckStreamClient) Recv() (*CheckResult, error) {
+	m := new(CheckResult)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ReleaseCheckerServer is the server API for ReleaseChecker service.
+// All implementations must embed UnimplementedReleaseCheckerServer
+// for forward compatibility
+type ReleaseCheckerServer interface {
+	// Check checks one repository and version.
+	Check(context.Context, *CheckRequest) (*CheckResult, error)
+	// CheckBatch checks one page of a manifest; a failed check fails only its
+	// own result.
+	CheckBatch(context.Context, *CheckBatchRequest) (*CheckBatchResponse, error)
+	// CheckStream checks every page of a manifest, sending each result as soon
+	// as it is ready, in any order.
+	CheckStream(*CheckBatchRequest, ReleaseChecker_CheckStreamServer) error
+	mustEmbedUnimplementedReleaseCheckerServer()
+}
+
+// UnimplementedReleaseCheckerServer must be embedded to have forward compatible implementations.
+type UnimplementedReleaseCheckerServer struct {
+}
+
+func (UnimplementedReleaseCheckerServer) Check(context.Context, *CheckRequest) (*CheckResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Check not implemented")
+}
+func (UnimplementedReleaseCheckerServer) CheckBatch(context.Context, *CheckBatchRequest) (*CheckBatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckBatch not implemented")
+}
+func (UnimplementedReleaseCheckerServer) CheckStream(*CheckBatchRequest, ReleaseChecker_CheckStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method CheckStream not implemented")
+}
+func (UnimplementedReleaseCheckerServer) mustEmbedUnimplementedReleaseCheckerServer() {}
+
+// UnsafeReleaseCheckerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ReleaseCheckerServer will
+// result in compilation errors.
+type UnsafeReleaseCheckerServer interface {
+	mustEmbedUnimplementedReleaseCheckerServer()
+}
+
+func RegisterReleaseCheckerServer(s grpc.ServiceRegistrar, srv ReleaseCheckerServer) {
+	s.RegisterService(&ReleaseChecker_ServiceDesc, srv)
+}
+
+func _ReleaseChecker_Check_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReleaseCheckerServer).Check(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ReleaseChecker_Check_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReleaseCheckerServer).Check(ctx, req.(*CheckRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReleaseChecker_CheckBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReleaseCheckerServer).CheckBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ReleaseChecker_CheckBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReleaseCheckerServer).CheckBatch(ctx, req.(*CheckBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReleaseChecker_CheckStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(CheckBatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ReleaseCheckerServer).CheckStream(m, &releaseCheckerCheckStreamServer{stream})
+}
+
+type ReleaseChecker_CheckStreamServer interface {
+	Send(*CheckResult) error
+	grpc.ServerStream
+}
+
+type releaseCheckerCheckStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *releaseCheckerCheckStreamServer) Send(m *CheckResult) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// ReleaseChecker_ServiceDesc is the grpc.ServiceDesc for ReleaseChecker service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ReleaseChecker_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "releasechecker.v1.ReleaseChecker",
+	HandlerType: (*ReleaseCheckerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Check",
+			Handler:    _ReleaseChecker_Check_Handler,
+		},
+		{
+			MethodName: "CheckBatch",
+			Handler:    _ReleaseChecker_CheckBatch_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "CheckStream",
+			Handler:       _ReleaseChecker_CheckStream_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "releasechecker/v1/checker.proto",
+}
@@ -0,0 +1,130 @@
+package checker
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+// AssetLister is implemented by clients that can list a release's assets, for
+// Config.RequiredAssets and releases without recorded platforms
+type AssetLister interface {
+	GetReleaseAssets(ctx context.Context, version *semver.Version) ([]string, error)
+}
+
+// maxAssetChecks caps how many releases' assets are listed, newest first
+const maxAssetChecks = 5
+
+// applyRequiredAssets checks the recommended release has an asset for every
+// required platform and shipped for Config.Platform. When it has not, e.g.
+// while assets are still being uploaded, the platforms are recorded and the
+// newest release before it that has them all is recommended instead, or the
+// comparison version if none is.
+func (c *Checker) applyRequiredAssets(ctx context.Context, analysis *Analysis, candidates []types.Release) {
+	if len(c.config.RequiredAssets) == 0 && c.config.Platform == "" {
+		return
+	}
+	comparison, recommended := analysis.ComparisonVersion, analysis.Recommended()
+	if comparison != nil && types.CompareVersions(recommended, comparison) <= 0 {
+		return
+	}
+	if analysis.SoakingVersion != nil && types.CompareVersions(recommended, analysis.SoakingVersion) == 0 {
+		return // Nothing old enough to recommend
+	}
+
+	// Releases that could be recommended instead, newest first
+	allowPrerelease := recommended.Prerelease() != ""
+	cutoff := time.Now().Add(-c.config.MinReleaseAge)
+	var releases []types.Release
+	for _, r := range candidates {
+		switch {
+		case types.CompareVersions(r.Version, recommended) > 0,
+			comparison != nil && types.CompareVersions(r.Version, comparison) <= 0,
+			findYanked(c.config.Yanked, r.Version) != nil,
+			c.config.MinReleaseAge > 0 && r.PublishedAt.After(cutoff),
+			r.Version.Prerelease() != "" && !allowPrerelease:
+			continue
+		}
+		releases = append(releases, r)
+	}
+	slices.SortFunc(releases, func(a, b types.Release) int { return types.CompareVersions(b.Version, a.Version) })
+
+	listed := 0
+	for i, r := range releases {
+		if listed == maxAssetChecks {
+			break
+		}
+		missing, didList, err := c.missingAssets(ctx, r)
+		if didList {
+			listed++
+		}
+		if err != nil {
+			c.log(slog.LevelWarn, "release assets unavailable", "version", r.Version.String(), "error", err)
+			analysis.DegradedReasons = append(analysis.DegradedReasons, DegradedAssetsUnchecked)
+			return
+		}
+		if i == 0 && len(missing) > 0 {
+			analysis.IncompleteRelease = r.Version
+			analysis.MissingAssets = missing
+		}
+		if len(missing) == 0 {
+			if i > 0 {
+				analysis.RecommendedVersion = r.Version
+			}
+			return
+		}
+	}
+	if comparison != nil {
+		analysis.RecommendedVersion = comparison
+	}
+}
+
+// errNoAssetLister is returned when assets must be listed but the client cannot
+var errNoAssetLister = errors.New("client cannot list release assets")
+
+// missingAssets returns the required platforms and Config.Platform that the
+// release has no assets for, reporting whether its assets had to be listed:
+// always for RequiredAssets, and for the platform when none were recorded
+func (c *Checker) missingAssets(ctx context.Context, r types.Release) (missing []string, listed bool, err error) {
+	platform, _ := types.ParsePlatform(c.config.Platform)
+	var names []string
+	if len(c.config.RequiredAssets) > 0 || platform != "" && r.Platforms == nil {
+		lister, ok := c.client.(AssetLister)
+		if !ok {
+			return nil, false, errNoAssetLister
+		}
+		if names, err = lister.GetReleaseAssets(ctx, r.Version); err != nil {
+			return nil, true, err
+		}
+		listed = true
+	}
+
+	missing = MissingAssets(names, c.config.RequiredAssets)
+	if platform != "" {
+		platforms := r.Platforms
+		if platforms == nil {
+			platforms = types.AssetPlatforms(names)
+		}
+		if !slices.Contains(platforms, platform) {
+			missing = append(missing, platform)
+		}
+	}
+	return missing, listed, nil
+}
+
+// MissingAssets returns the platforms, e.g. "linux-x64", that no asset name contains
+func MissingAssets(names, platforms []string) []string {
+	var missing []string
+	for _, platform := range platforms {
+		if !slices.ContainsFunc(names, func(name string) bool { return strings.Contains(name, platform) }) {
+			missing = append(missing, platform)
+		}
+	}
+	return missing
+}
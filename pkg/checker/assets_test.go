@@ -0,0 +1,181 @@
+package checker
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+// assetClient is a mock client that also lists release assets
+type assetClient struct {
+	MockGitHubClient
+	assets map[string][]string // By version; versions not listed fail
+	calls  int
+}
+
+func (m *assetClient) GetReleaseAssets(ctx context.Context, version *semver.Version) ([]string, error) {
+	m.calls++
+	names, ok := m.assets[version.String()]
+	if !ok {
+		return nil, errors.New("release not found")
+	}
+	return names, nil
+}
+
+func TestAnalyse_RequiredAssets(t *testing.T) {
+	latest := newTestRelease("2.330.0", 1)
+	releases := []types.Release{latest, newTestRelease("2.329.0", 20), newTestRelease("2.328.0", 40), newTestRelease("2.327.1", 60)}
+	full := func(version string) []string {
+		return []string{"actions-runner-linux-x64-" + version + ".tar.gz", "actions-runner-linux-arm64-" + version + ".tar.gz", "actions-runner-win-x64-" + version + ".zip"}
+	}
+	platforms := []string{"linux-x64", "linux-arm64", "win-x64"}
+
+	tests := []struct {
+		name            string
+		assets          map[string][]string
+		required        []string
+		version         string
+		wantRecommended string
+		wantIncomplete  string
+		wantMissing     []string
+		wantDegraded    bool
+	}{
+		{name: "not required", version: "2.328.0", wantRecommended: "2.330.0"},
+		{name: "all published", assets: map[string][]string{"2.330.0": full("2.330.0")}, required: platforms, version: "2.328.0", wantRecommended: "2.330.0"},
+		{
+			name:            "latest incomplete",
+			assets:          map[string][]string{"2.330.0": {"actions-runner-linux-x64-2.330.0.tar.gz"}, "2.329.0": full("2.329.0")},
+			required:        platforms,
+			version:         "2.328.0",
+			wantRecommended: "2.329.0",
+			wantIncomplete:  "2.330.0",
+			wantMissing:     []string{"linux-arm64", "win-x64"},
+		},
+		{
+			name:            "nothing newer complete",
+			assets:          map[string][]string{"2.330.0": {}},
+			required:        platforms,
+			version:         "2.329.0",
+			wantRecommended: "2.329.0",
+			wantIncomplete:  "2.330.0",
+			wantMissing:     platforms,
+		},
+		{
+			name:            "latest lookup",
+			assets:          map[string][]string{"2.330.0": {}, "2.329.0": full("2.329.0")},
+			required:        []string{"linux-x64"},
+			wantRecommended: "2.329.0",
+			wantIncomplete:  "2.330.0",
+			wantMissing:     []string{"linux-x64"},
+		},
+		{name: "lookup fails", required: platforms, version: "2.328.0", wantRecommended: "2.330.0", wantDegraded: true},
+		{name: "up to date", required: platforms, version: "2.330.0", wantRecommended: "2.330.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &assetClient{MockGitHubClient: MockGitHubClient{LatestRelease: &latest, AllReleases: releases}, assets: tt.assets}
+			checker := NewChecker(client, Config{CriticalAgeDays: 12, MaxAgeDays: 30, NoCache: true, RequiredAssets: tt.required})
+			analysis, err := checker.Analyse(context.Background(), tt.version)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := analysis.Recommended().String(); got != tt.wantRecommended {
+				t.Errorf("Recommended() = %s, want %s", got, tt.wantRecommended)
+			}
+			if got := versionString(analysis.IncompleteRelease); got != tt.wantIncomplete {
+				t.Errorf("IncompleteRelease = %q, want %q", got, tt.wantIncomplete)
+			}
+			if got := strings.Join(analysis.MissingAssets, ","); got != strings.Join(tt.wantMissing, ",") {
+				t.Errorf("MissingAssets = %v, want %v", analysis.MissingAssets, tt.wantMissing)
+			}
+			if analysis.IsDegraded() != tt.wantDegraded {
+				t.Errorf("IsDegraded() = %t, want %t (%v)", analysis.IsDegraded(), tt.wantDegraded, analysis.DegradedReasons)
+			}
+		})
+	}
+}
+
+func TestAnalyse_RequiredAssetsNotCached(t *testing.T) {
+	latest := newTestRelease("2.330.0", 1)
+	client := &assetClient{
+		MockGitHubClient: MockGitHubClient{LatestRelease: &latest, AllReleases: []types.Release{latest, newTestRelease("2.329.0", 20), newTestRelease("2.328.0", 40)}},
+		assets:           map[string][]string{"2.330.0": {}, "2.329.0": {"actions-runner-linux-x64-2.329.0.tar.gz"}},
+	}
+	checker := NewChecker(client, Config{CriticalAgeDays: 12, MaxAgeDays: 30, NoCache: true, RequiredAssets: []string{"linux-x64"}})
+	checker.SetAnalysisCache(NewMemoryAnalysisCache(), "actions/runner")
+
+	for i := 0; i < 2; i++ {
+		if _, err := checker.Analyse(context.Background(), "2.328.0"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	client.assets["2.330.0"] = []string{"actions-runner-linux-x64-2.330.0.tar.gz"}
+	analysis, err := checker.Analyse(context.Background(), "2.328.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := analysis.Recommended().String(); got != "2.330.0" {
+		t.Errorf("Recommended() = %s once assets are published, want 2.330.0", got)
+	}
+}
+
+func TestAnalyse_Platform(t *testing.T) {
+	withPlatforms := func(r types.Release, platforms ...string) types.Release {
+		r.Platforms = platforms
+		return r
+	}
+	latest := withPlatforms(newTestRelease("2.330.0", 1), "linux/amd64")
+	releases := []types.Release{
+		latest,
+		withPlatforms(newTestRelease("2.329.0", 20), "linux/amd64", "linux/arm64"),
+		newTestRelease("2.328.0", 40), // Platforms not recorded
+		withPlatforms(newTestRelease("2.327.1", 60), "linux/amd64", "linux/arm64"),
+	}
+
+	tests := []struct {
+		name            string
+		platform        string
+		assets          map[string][]string
+		version         string
+		wantRecommended string
+		wantMissing     []string
+		wantCalls       int
+	}{
+		{name: "shipped", platform: "linux/amd64", version: "2.327.1", wantRecommended: "2.330.0"},
+		{name: "recorded without it", platform: "linux/arm64", version: "2.327.1", wantRecommended: "2.329.0", wantMissing: []string{"linux/arm64"}},
+		{name: "aliases", platform: "Linux/aarch64", version: "2.327.1", wantRecommended: "2.329.0", wantMissing: []string{"linux/arm64"}},
+		{
+			name:            "listed when not recorded",
+			platform:        "windows/amd64",
+			assets:          map[string][]string{"2.328.0": {"actions-runner-win-x64-2.328.0.zip"}},
+			version:         "2.327.1",
+			wantRecommended: "2.328.0",
+			wantMissing:     []string{"windows/amd64"},
+			wantCalls:       1,
+		},
+		{name: "never shipped", platform: "darwin/arm64", assets: map[string][]string{"2.328.0": {}}, version: "2.327.1", wantRecommended: "2.327.1", wantMissing: []string{"darwin/arm64"}, wantCalls: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &assetClient{MockGitHubClient: MockGitHubClient{LatestRelease: &latest, AllReleases: releases}, assets: tt.assets}
+			checker := NewChecker(client, Config{CriticalAgeDays: 12, MaxAgeDays: 30, NoCache: true, Platform: tt.platform})
+			analysis, err := checker.Analyse(context.Background(), tt.version)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := analysis.Recommended().String(); got != tt.wantRecommended {
+				t.Errorf("Recommended() = %s, want %s", got, tt.wantRecommended)
+			}
+			if got := strings.Join(analysis.MissingAssets, ","); got != strings.Join(tt.wantMissing, ",") {
+				t.Errorf("MissingAssets = %v, want %v", analysis.MissingAssets, tt.wantMissing)
+			}
+			if client.calls != tt.wantCalls {
+				t.Errorf("listed assets %d times, want %d", client.calls, tt.wantCalls)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package checker
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/nickromney-org/github-release-version-checker/pkg/releaseset"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+// channelPattern matches comparison channels such as "latest-1" and "n-2"
+var channelPattern = regexp.MustCompile(`(?i)^\s*(?:latest|n)-(\d+)\s*$`)
+
+// ParseChannel reports whether input names a channel rather than a version:
+// "latest-N" or "n-N", meaning the newest release N minor versions behind the
+// latest. It returns N.
+func ParseChannel(input string) (int, bool) {
+	m := channelPattern.FindStringSubmatch(input)
+	if m == nil {
+		return 0, false
+	}
+	behind, err := strconv.Atoi(m[1])
+	if err != nil {
+		return 0, false
+	}
+	return behind, true
+}
+
+// ChannelNotFoundError is returned when a channel is further behind the latest
+// release than there are minor versions
+type ChannelNotFoundError struct {
+	Channel   string
+	Behind    int
+	Available int // Minor versions behind the latest that have a stable release
+	Latest    *semver.Version
+}
+
+func (e *ChannelNotFoundError) Error() string {
+	return fmt.Sprintf("channel %s is %d minor versions behind v%s, but only %d older minor versions have releases",
+		e.Channel, e.Behind, e.Latest, e.Available)
+}
+
+// resolveChannel returns the newest stable release of the minor version behind
+// places below the latest's. Minor versions count across majors, so one behind
+// 3.0 may be 2.9; releases newer than latest are ignored.
+func resolveChannel(releases []types.Release, latest *semver.Version, behind int) (*semver.Version, int) {
+	sorted := releaseset.FilterStable(releases)
+	releaseset.SortByVersion(sorted)
+
+	lines := 0
+	var current *semver.Version
+	for _, r := range sorted {
+		if types.CompareVersions(r.Version, latest) > 0 {
+			continue
+		}
+		if current != nil && r.Version.Major() == current.Major() && r.Version.Minor() == current.Minor() {
+			continue
+		}
+		// First (newest) release of the next minor version down
+		if current != nil {
+			lines++
+		}
+		current = r.Version
+		if lines == behind {
+			return current, lines
+		}
+	}
+	return nil, lines
+}
@@ -0,0 +1,108 @@
+package checker
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+func TestParseChannel(t *testing.T) {
+	tests := []struct {
+		input     string
+		behind    int
+		isChannel bool
+	}{
+		{"latest-1", 1, true},
+		{"n-2", 2, true},
+		{"N-0", 0, true},
+		{" Latest-3 ", 3, true},
+		{"latest", 0, false},
+		{"n-", 0, false},
+		{"latest+1", 0, false},
+		{"2.329.0", 0, false},
+		{"", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			behind, ok := ParseChannel(tt.input)
+			if ok != tt.isChannel || behind != tt.behind {
+				t.Errorf("ParseChannel(%q) = %d, %t, want %d, %t", tt.input, behind, ok, tt.behind, tt.isChannel)
+			}
+		})
+	}
+}
+
+func TestAnalyse_Channel(t *testing.T) {
+	releases := []types.Release{
+		newTestRelease("3.1.0", 2),
+		newTestRelease("3.0.2", 10),
+		newTestRelease("3.0.1", 20),
+		newTestRelease("3.0.0", 40),
+		newTestRelease("3.1.0-rc.1", 45),
+		newTestRelease("2.9.4", 60),
+		newTestRelease("2.9.0", 90),
+	}
+	client := &MockGitHubClient{LatestRelease: &releases[0], AllReleases: releases}
+
+	tests := []struct {
+		channel string
+		want    string
+	}{
+		{"latest-0", "3.1.0"},
+		{"latest-1", "3.0.2"}, // Newest patch of the previous minor
+		{"n-2", "2.9.4"},      // Minor versions count across majors
+	}
+	for _, tt := range tests {
+		t.Run(tt.channel, func(t *testing.T) {
+			checker := NewChecker(client, Config{CriticalAgeDays: 12, MaxAgeDays: 30, NoCache: true})
+			analysis, err := checker.Analyse(context.Background(), tt.channel)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if analysis.ComparisonVersion.String() != tt.want {
+				t.Errorf("ComparisonVersion = %s, want %s", analysis.ComparisonVersion, tt.want)
+			}
+			if analysis.Channel != tt.channel {
+				t.Errorf("Channel = %q, want %q", analysis.Channel, tt.channel)
+			}
+		})
+	}
+}
+
+func TestAnalyse_ChannelTooFarBehind(t *testing.T) {
+	releases := []types.Release{newTestRelease("1.1.0", 2), newTestRelease("1.0.0", 30)}
+	client := &MockGitHubClient{LatestRelease: &releases[0], AllReleases: releases}
+
+	checker := NewChecker(client, Config{CriticalAgeDays: 12, MaxAgeDays: 30, NoCache: true})
+	_, err := checker.Analyse(context.Background(), "latest-2")
+
+	var noChannel *ChannelNotFoundError
+	if !errors.As(err, &noChannel) {
+		t.Fatalf("expected ChannelNotFoundError, got %v", err)
+	}
+	if noChannel.Behind != 2 || noChannel.Available != 1 {
+		t.Errorf("Behind, Available = %d, %d, want 2, 1", noChannel.Behind, noChannel.Available)
+	}
+}
+
+func TestAnalyse_ChannelSharesCachedAnalysis(t *testing.T) {
+	releases := []types.Release{newTestRelease("1.1.0", 2), newTestRelease("1.0.0", 30)}
+	client := &MockGitHubClient{LatestRelease: &releases[0], AllReleases: releases}
+
+	checker := NewChecker(client, Config{CriticalAgeDays: 12, MaxAgeDays: 30, NoCache: true})
+	checker.SetAnalysisCache(NewMemoryAnalysisCache(), "example/app")
+
+	byChannel, err := checker.Analyse(context.Background(), "n-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	byVersion, err := checker.Analyse(context.Background(), "1.0.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if byChannel.Channel != "n-1" || byVersion.Channel != "" {
+		t.Errorf("Channel = %q and %q, want \"n-1\" and \"\"", byChannel.Channel, byVersion.Channel)
+	}
+}
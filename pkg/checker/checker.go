@@ -3,11 +3,15 @@ package checker
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/nickromney-org/github-release-version-checker/internal/data"
 	"github.com/nickromney-org/github-release-version-checker/pkg/policy"
+	"github.com/nickromney-org/github-release-version-checker/pkg/releaseset"
 	"github.com/nickromney-org/github-release-version-checker/pkg/types"
 )
 
@@ -18,11 +22,29 @@ type GitHubClient interface {
 	GetRecentReleases(ctx context.Context, count int) ([]types.Release, error)
 }
 
+// TruncationReporter is implemented by clients that cap how many releases
+// GetAllReleases returns
+type TruncationReporter interface {
+	// Truncated reports whether the last GetAllReleases stopped before the end
+	Truncated() bool
+}
+
+// IncrementalLister is implemented by clients that can fetch only the releases
+// published since a time, e.g. to refresh a cache without fetching every release
+type IncrementalLister interface {
+	GetReleasesSince(ctx context.Context, since time.Time) ([]types.Release, error)
+}
+
 // Checker performs version analysis
 type Checker struct {
 	client GitHubClient
 	config Config
 	policy policy.VersionPolicy // Optional: if set, overrides config-based logic
+	logger *slog.Logger         // Optional: traces cache decisions
+
+	analyses   AnalysisCache // Optional: reuses analyses of unchanged data
+	repository string        // Distinguishes repositories in analysis cache keys
+	hooks      Hooks         // Optional: instrumentation callbacks
 }
 
 // NewChecker creates a new version checker
@@ -43,118 +65,268 @@ func NewCheckerWithPolicy(client GitHubClient, config Config, pol policy.Version
 	}
 }
 
-// versionExists checks if a version exists in the releases list
-func (c *Checker) versionExists(releases []types.Release, version *semver.Version) bool {
-	for _, release := range releases {
-		if release.Version.Equal(version) {
-			return true
-		}
+// SetLogger sets a logger for tracing cache decisions; nil disables tracing
+func (c *Checker) SetLogger(logger *slog.Logger) {
+	c.logger = logger
+}
+
+// SetAnalysisCache reuses analyses from cache when the repository, version,
+// settings and release data are unchanged; nil disables caching
+func (c *Checker) SetAnalysisCache(cache AnalysisCache, repository string) {
+	c.analyses = cache
+	c.repository = repository
+}
+
+// log writes a trace message if a logger is set
+func (c *Checker) log(level slog.Level, msg string, args ...any) {
+	if c.logger != nil {
+		c.logger.Log(context.Background(), level, msg, args...)
 	}
-	return false
 }
 
-// mergeReleases combines embedded and recent releases, deduplicating by version
-func (c *Checker) mergeReleases(embedded, recent []types.Release) []types.Release {
-	// Use map to deduplicate by version
-	seen := make(map[string]bool)
-	var merged []types.Release
+// timelineWindowDays returns the timeline window in days, falling back to the default
+func (c *Checker) timelineWindowDays() int {
+	if c.config.TimelineWindowDays > 0 {
+		return c.config.TimelineWindowDays
+	}
+	return DefaultTimelineWindowDays
+}
 
-	// Add recent first (they're authoritative)
-	for _, r := range recent {
-		key := r.Version.String()
-		if !seen[key] {
-			seen[key] = true
-			merged = append(merged, r)
-		}
+// timelineMinRows returns the minimum timeline rows, falling back to the default
+func (c *Checker) timelineMinRows() int {
+	if c.config.TimelineMinRows > 0 {
+		return c.config.TimelineMinRows
+	}
+	return DefaultTimelineMinRows
+}
+
+// maxAgeDays returns the days-based expiry window, preferring the policy's
+// threshold over the config so rendered dates match the policy result
+func (c *Checker) maxAgeDays() int {
+	if c.policy != nil && c.policy.GetMaxDays() > 0 {
+		return c.policy.GetMaxDays()
 	}
+	return c.config.MaxAgeDays
+}
 
-	// Add embedded (skip duplicates)
-	for _, r := range embedded {
-		key := r.Version.String()
-		if !seen[key] {
-			seen[key] = true
-			merged = append(merged, r)
+// criticalAgeDays returns the days-based critical threshold, preferring the policy's
+func (c *Checker) criticalAgeDays() int {
+	if c.policy != nil && c.policy.GetMaxDays() > 0 {
+		return c.policy.GetCriticalDays()
+	}
+	return c.config.CriticalAgeDays
+}
+
+// olderThanAll reports whether version is lower than every release
+func olderThanAll(releases []types.Release, version *semver.Version) bool {
+	for _, release := range releases {
+		if types.CompareVersions(release.Version, version) <= 0 {
+			return false
 		}
 	}
+	return true
+}
 
-	return merged
+// versionExists checks if a version exists in the releases list
+func (c *Checker) versionExists(releases []types.Release, version *semver.Version) bool {
+	for _, release := range releases {
+		if types.CompareVersions(release.Version, version) == 0 {
+			return true
+		}
+	}
+	return false
 }
 
-// Analyse performs the version analysis
+// Analyse performs the version analysis. comparisonVersionStr is a version, a
+// channel such as "latest-1" (see ParseChannel), a release line such as "1.29"
+// (see ParseReleaseLine), or empty for the latest only.
 func (c *Checker) Analyse(ctx context.Context, comparisonVersionStr string) (*Analysis, error) {
 	// Validate config
 	if err := c.config.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid configuration: %w", err)
 	}
 
-	// Determine which dataset to use
-	var allReleases []types.Release
-	var err error
-
-	if c.config.NoCache {
-		// Bypass embedded cache - fetch all releases from API
-		allReleases, err = c.client.GetAllReleases(ctx)
+	// Parse comparison version before fetching anything, so bad input fails fast.
+	// Channels such as "latest-1" are resolved once the releases are known.
+	var comparisonVersion *semver.Version
+	channelBehind, isChannel := ParseChannel(comparisonVersionStr)
+	input := comparisonVersionStr
+	if c.config.VersionSuffix != nil {
+		input = c.config.VersionSuffix.ReplaceAllString(input, "")
+	}
+	// Lines such as "1.29" are resolved to their latest patch likewise
+	line, isLine := ParseReleaseLine(input, c.config.Normalisation)
+	if comparisonVersionStr != "" && !isChannel && !isLine {
+		var err error
+		comparisonVersion, err = ParseComparisonVersion(input, c.config.Normalisation)
 		if err != nil {
-			return nil, fmt.Errorf("failed to fetch all releases: %w", err)
+			return nil, err
 		}
-	} else {
-		// Use embedded cache with validation
-		// Load embedded releases
-		embeddedData, err := data.LoadEmbeddedReleases()
+		if comparisonVersion.Original() != comparisonVersionStr {
+			c.log(slog.LevelInfo, "comparison version normalised", "input", comparisonVersionStr, "version", comparisonVersion.Original())
+		}
+		if notFound := c.cachedNotFound(comparisonVersion); notFound != nil && c.failsUnknown() {
+			return nil, notFound
+		}
+	}
+
+	allReleases, merged, err := c.loadReleases(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ensure we have releases
+	if len(allReleases) == 0 {
+		return nil, fmt.Errorf("no releases available")
+	}
+
+	// Caches can be trimmed to recent releases, or bootstrapped without
+	// prereleases, so look up a version older than all of them, or a tracked
+	// prerelease, in the full list before reporting it missing
+	if merged && isLine && resolveLine(allReleases, line) == nil && olderThanAll(allReleases, line) ||
+		merged && comparisonVersion != nil && !c.versionExists(allReleases, comparisonVersion) &&
+			(olderThanAll(allReleases, comparisonVersion) || c.keepsPrereleases() && comparisonVersion.Prerelease() != "") {
+		c.log(slog.LevelInfo, "version missing from cached releases, fetching all releases", "version", versionString(comparisonVersion), "line", versionString(line))
+		allReleases, err = c.fetchReleases(ctx, FetchAll, func() ([]types.Release, error) { return c.client.GetAllReleases(ctx) })
 		if err != nil {
-			return nil, fmt.Errorf("failed to load embedded releases: %w", err)
+			return nil, fmt.Errorf("failed to fetch all releases: %w", err)
 		}
+	}
 
-		// Convert data.Release to types.Release
-		embeddedReleases := make([]types.Release, len(embeddedData))
-		for i, r := range embeddedData {
-			embeddedReleases[i] = types.Release{
-				Version:     r.Version,
-				PublishedAt: r.PublishedAt,
-				URL:         r.URL,
-			}
+	var degraded []DegradedReason
+	if t, ok := c.client.(TruncationReporter); ok && t.Truncated() {
+		c.log(slog.LevelWarn, "release list truncated at the page limit", "releases", len(allReleases))
+		degraded = append(degraded, DegradedTruncated)
+	}
+
+	// Releases that can count as newer than the comparison version: those on
+	// the release channel, or marked prereleases only when tracked
+	candidates := allReleases
+	latestStable, latestIncluding := latestByTrack(allReleases)
+	switch {
+	case c.config.Channel != "":
+		candidates = channelReleases(allReleases, c.config.Channel, c.config.ChannelPatterns)
+		if len(candidates) == 0 {
+			return nil, fmt.Errorf("no releases on channel %q", c.config.Channel)
 		}
+	case c.config.Track != TrackPrerelease:
+		if latestStable == nil {
+			return nil, fmt.Errorf("no stable releases available")
+		}
+		candidates = releaseset.WithoutPrereleases(allReleases)
+	}
 
-		// Fetch 5 most recent releases from API
-		recentReleases, err := c.client.GetRecentReleases(ctx, 5)
-		if err != nil {
-			return nil, fmt.Errorf("failed to fetch recent releases: %w", err)
+	// Releases upstream is rolling back do not count until their waivers lapse
+	candidates, rolledBack := setAsideRollbacks(candidates, c.config.Waivers, time.Now())
+	for _, w := range rolledBack {
+		c.log(slog.LevelInfo, "release set aside for upstream rollback", "version", w.Version.String(), "until", w.Expires.Format(time.DateOnly))
+	}
+
+	// Get latest release from dataset, unless GitHub's latest mark is preferred
+	latestRelease := *releaseset.Latest(candidates)
+	highestVersion := latestRelease.Version
+	markedLatest := c.markedLatest(ctx)
+	var markedVersion *semver.Version
+	if markedLatest != nil {
+		markedVersion = markedLatest.Version
+		if types.CompareVersions(markedVersion, highestVersion) != 0 {
+			c.log(slog.LevelWarn, "marked latest release is not the highest version",
+				"marked", markedVersion.String(), "highest", highestVersion.String())
+		}
+		if c.config.LatestPreference == LatestMarked {
+			latestRelease = *markedLatest
+			candidates = releasesUpTo(candidates, markedVersion)
 		}
+	}
 
-		if !c.isEmbeddedCurrent(embeddedReleases, recentReleases) {
-			// Embedded data is stale (>5 releases behind)
-			// Fall back to full API query
-			allReleases, err = c.client.GetAllReleases(ctx)
-			if err != nil {
-				return nil, fmt.Errorf("failed to fetch all releases: %w", err)
-			}
-		} else {
-			// Merge embedded + recent (deduplicating)
-			allReleases = c.mergeReleases(embeddedReleases, recentReleases)
+	if isChannel {
+		resolved, available := resolveChannel(candidates, latestRelease.Version, channelBehind)
+		if resolved == nil {
+			return nil, &ChannelNotFoundError{Channel: comparisonVersionStr, Behind: channelBehind, Available: available, Latest: latestRelease.Version}
+		}
+		c.log(slog.LevelInfo, "channel resolved", "channel", comparisonVersionStr, "version", resolved.String())
+		comparisonVersion = resolved
+	}
+	lineResolved := false
+	if isLine {
+		// A line without releases is analysed as MAJOR.MINOR.0, which does not exist
+		comparisonVersion = line
+		if resolved := resolveLine(candidates, line); resolved != nil {
+			c.log(slog.LevelInfo, "release line resolved", "line", comparisonVersionStr, "version", resolved.String())
+			comparisonVersion = resolved
+			lineResolved = true
 		}
 	}
 
-	// Ensure we have releases
-	if len(allReleases) == 0 {
-		return nil, fmt.Errorf("no releases available")
+	// Reuse an earlier analysis of the same version against the same data
+	var cacheKey string
+	var analysis *Analysis
+	if c.analyses != nil && len(c.config.RequiredAssets) == 0 && c.config.Platform == "" {
+		cacheKey = c.analysisKey(comparisonVersion, allReleases, markedVersion, degraded)
+		if cached, ok := c.analyses.Get(cacheKey); ok {
+			c.log(slog.LevelInfo, "analysis cache hit", "version", versionString(comparisonVersion))
+			analysis = cached
+		}
 	}
 
-	// Get latest release from dataset
-	latestRelease := allReleases[0]
-	for _, r := range allReleases {
-		if r.Version.GreaterThan(latestRelease.Version) {
-			latestRelease = r
+	if analysis == nil {
+		analysis, err = c.analyse(ctx, comparisonVersion, allReleases, latestRelease, candidates, highestVersion, markedVersion, degraded)
+		if err != nil {
+			c.rememberNotFound(err)
+			return nil, err
+		}
+		applyYanked(analysis, c.config.Yanked, candidates)
+		applyMinReleaseAge(analysis, candidates, c.config.Yanked, c.config.MinReleaseAge, time.Now())
+		c.applyRequiredAssets(ctx, analysis, candidates)
+		applyReview(analysis, c.config.BreakingNotes)
+		analysis.Links = releaseLinks(allReleases, analysis.ComparisonVersion, analysis.Recommended())
+		// Last, as the checks above look through every newer release
+		analysis.TotalNewer = len(analysis.NewerReleases)
+		if maxNewer := c.config.MaxNewer; maxNewer > 0 && len(analysis.NewerReleases) > maxNewer {
+			analysis.NewerReleases = analysis.NewerReleases[len(analysis.NewerReleases)-maxNewer:]
+		}
+		if latestIncluding != nil {
+			analysis.LatestStable = latestStable
+			analysis.LatestIncludingPrerelease = latestIncluding
+		}
+		analysis.ReleaseChannel = c.config.Channel
+		analysis.RolledBack = rolledBack
+		if cacheKey != "" {
+			if err := c.analyses.Put(cacheKey, analysis); err != nil {
+				c.log(slog.LevelWarn, "analysis cache write failed", "error", err)
+			}
 		}
 	}
 
+	// Cached analyses are shared between channels, lines and versions, so label a copy
+	if isChannel {
+		labelled := *analysis
+		labelled.Channel = strings.ToLower(strings.TrimSpace(comparisonVersionStr))
+		analysis = &labelled
+	}
+	if lineResolved {
+		labelled := *analysis
+		labelled.ResolvedFrom = strings.TrimSpace(comparisonVersionStr)
+		analysis = &labelled
+	}
+	return analysis, nil
+}
+
+// analyse compares a version against the loaded releases; comparisonVersion is
+// nil when only the latest version is wanted
+func (c *Checker) analyse(ctx context.Context, comparisonVersion *semver.Version, allReleases []types.Release, latestRelease types.Release,
+	candidates []types.Release, highestVersion, markedVersion *semver.Version, degraded []DegradedReason) (*Analysis, error) {
 	// If no comparison version, just return latest
-	if comparisonVersionStr == "" {
+	if comparisonVersion == nil {
 		analysis := &Analysis{
 			LatestVersion:   latestRelease.Version,
 			IsLatest:        false,
-			CriticalAgeDays: c.config.CriticalAgeDays,
-			MaxAgeDays:      c.config.MaxAgeDays,
-			Message:         fmt.Sprintf("Latest version: %s", latestRelease.Version),
+			CriticalAgeDays: c.criticalAgeDays(),
+			MaxAgeDays:      c.maxAgeDays(),
+			Message:         fmt.Sprintf("Latest version: %s", types.FormatVersion(latestRelease.Version)),
+			HighestVersion:  highestVersion,
+			MarkedLatest:    markedVersion,
+			DegradedReasons: degraded,
 		}
 
 		// Set policy type if available
@@ -168,32 +340,32 @@ func (c *Checker) Analyse(ctx context.Context, comparisonVersionStr string) (*An
 		return analysis, nil
 	}
 
-	// Parse comparison version
-	comparisonVersion, err := semver.NewVersion(comparisonVersionStr)
-	if err != nil {
-		return nil, fmt.Errorf("invalid comparison version %q: %w", comparisonVersionStr, err)
-	}
-
 	// Check if already on latest
-	if comparisonVersion.Equal(latestRelease.Version) {
+	if types.CompareVersions(comparisonVersion, latestRelease.Version) == 0 {
 		return &Analysis{
 			LatestVersion:     latestRelease.Version,
 			ComparisonVersion: comparisonVersion,
 			IsLatest:          true,
-			CriticalAgeDays:   c.config.CriticalAgeDays,
-			MaxAgeDays:        c.config.MaxAgeDays,
-			Message:           fmt.Sprintf("✅ Version %s is up to date", comparisonVersion),
+			CriticalAgeDays:   c.criticalAgeDays(),
+			MaxAgeDays:        c.maxAgeDays(),
+			Message:           fmt.Sprintf("✅ Version %s is up to date", types.FormatVersion(comparisonVersion)),
+			HighestVersion:    highestVersion,
+			MarkedLatest:      markedVersion,
+			DegradedReasons:   degraded,
 		}, nil
 	}
 
 	// Validate version exists
 	if !c.versionExists(allReleases, comparisonVersion) {
-		return nil, fmt.Errorf("version %s does not exist in GitHub releases (latest: %s)",
-			comparisonVersion, latestRelease.Version)
+		if c.failsUnknown() {
+			return nil, &VersionNotFoundError{Version: comparisonVersion, Latest: latestRelease.Version,
+				Nearest: nearestVersions(candidates, comparisonVersion, nearestSuggestions)}
+		}
+		return c.unknownVersionAnalysis(comparisonVersion, allReleases, latestRelease, candidates, highestVersion, markedVersion, degraded), nil
 	}
 
 	// Find releases newer than comparison version
-	newerReleases := c.findNewerReleases(allReleases, comparisonVersion)
+	newerReleases := c.findNewerReleases(candidates, comparisonVersion)
 
 	// Build analysis
 	analysis := &Analysis{
@@ -202,16 +374,21 @@ func (c *Checker) Analyse(ctx context.Context, comparisonVersionStr string) (*An
 		IsLatest:          false,
 		ReleasesBehind:    len(newerReleases),
 		NewerReleases:     newerReleases,
-		CriticalAgeDays:   c.config.CriticalAgeDays,
-		MaxAgeDays:        c.config.MaxAgeDays,
+		CriticalAgeDays:   c.criticalAgeDays(),
+		MaxAgeDays:        c.maxAgeDays(),
+		HighestVersion:    highestVersion,
+		MarkedLatest:      markedVersion,
+		Ordering:          c.config.Ordering,
+		DegradedReasons:   degraded,
 	}
+	countBehind(analysis)
 
 	// Calculate recent releases for timeline table
 	analysis.RecentReleases = c.CalculateRecentReleases(allReleases, comparisonVersion, latestRelease.Version)
 
 	// Find comparison version release date
 	for _, release := range allReleases {
-		if release.Version.Equal(comparisonVersion) {
+		if types.CompareVersions(release.Version, comparisonVersion) == 0 {
 			analysis.ComparisonReleasedAt = &release.PublishedAt
 			break
 		}
@@ -244,41 +421,227 @@ func (c *Checker) Analyse(ctx context.Context, comparisonVersionStr string) (*An
 			analysis.IsCritical = policyResult.IsCritical
 			analysis.PolicyType = c.policy.Type()
 			analysis.MinorVersionsBehind = policyResult.VersionsBehind
+			c.policyEvaluated(ctx, PolicyEvent{
+				PolicyType:     analysis.PolicyType,
+				Comparison:     comparisonVersion,
+				Latest:         latestRelease.Version,
+				ReleasesBehind: analysis.ReleasesBehind,
+				Result:         policyResult,
+			})
 		} else {
 			// Use legacy config-based logic
 			analysis.IsExpired = analysis.DaysSinceUpdate >= c.config.MaxAgeDays
 			analysis.IsCritical = !analysis.IsExpired && analysis.DaysSinceUpdate >= c.config.CriticalAgeDays
 			analysis.PolicyType = "days"
+			c.policyEvaluated(ctx, PolicyEvent{
+				PolicyType:     analysis.PolicyType,
+				Comparison:     comparisonVersion,
+				Latest:         latestRelease.Version,
+				ReleasesBehind: analysis.ReleasesBehind,
+				Result: policy.PolicyResult{
+					IsExpired:  analysis.IsExpired,
+					IsCritical: analysis.IsCritical,
+					IsWarning:  !analysis.IsExpired && !analysis.IsCritical,
+					DaysOld:    analysis.DaysSinceUpdate,
+				},
+			})
 		}
 	}
 
+	if schedule, ok := c.policy.(policy.SupportSchedulePolicy); ok {
+		if phase, end, ok := schedule.Support(comparisonVersion); ok {
+			analysis.SupportPhase = phase
+			analysis.EndOfLife = &end
+		}
+	}
+
+	applyWaiver(analysis, c.config.Waivers, time.Now())
+	applyNoteKeywords(analysis, c.config.NoteKeywords)
+	analysis.MaintenanceWindow = c.maintenanceWindow(analysis)
+
 	// Generate message
 	analysis.Message = c.generateMessage(analysis)
 
 	return analysis, nil
 }
 
+// maintenanceWindow returns the last maintenance window before the analysed
+// version expires, or nil without a calendar or once it has expired
+func (c *Checker) maintenanceWindow(analysis *Analysis) *time.Time {
+	calendarPolicy, ok := c.policy.(policy.CalendarPolicy)
+	if !ok || analysis.IsExpired || analysis.Waived {
+		return nil
+	}
+	calendar := calendarPolicy.MaintenanceCalendar()
+	expiry := analysis.ExpiryDate()
+	if calendar == nil || expiry == nil {
+		return nil
+	}
+	window, ok := calendar.LastBefore(*expiry)
+	if !ok {
+		return nil
+	}
+	return &window
+}
+
+// markedLatest returns the release GitHub marks as latest, or nil if it cannot be
+// fetched; the analysis does not depend on it unless LatestMarked is preferred
+func (c *Checker) markedLatest(ctx context.Context) *types.Release {
+	if c.config.Offline {
+		return nil
+	}
+	var release *types.Release
+	_, err := c.fetchReleases(ctx, FetchLatest, func() ([]types.Release, error) {
+		var err error
+		if release, err = c.client.GetLatestRelease(ctx); err != nil || release == nil {
+			return nil, err
+		}
+		return []types.Release{*release}, nil
+	})
+	if err != nil || release == nil || release.Version == nil {
+		if err != nil {
+			c.log(slog.LevelInfo, "marked latest release unavailable", "error", err)
+		}
+		return nil
+	}
+	return release
+}
+
+// keepsPrereleases reports whether releases marked as prereleases are analysed
+func (c *Checker) keepsPrereleases() bool {
+	return c.config.Track == TrackPrerelease || c.config.Channel != "" && c.config.Channel != ReleaseChannelStable
+}
+
+// latestByTrack returns the latest release not marked as a prerelease, and the
+// latest of all releases when any is marked, or nil when none is
+func latestByTrack(releases []types.Release) (stable, including *semver.Version) {
+	if latest := releaseset.Latest(releaseset.WithoutPrereleases(releases)); latest != nil {
+		stable = latest.Version
+	}
+	for _, r := range releases {
+		if r.Prerelease {
+			including = releaseset.Latest(releases).Version
+			break
+		}
+	}
+	return stable, including
+}
+
+// releasesUpTo returns the releases no higher than version
+func releasesUpTo(releases []types.Release, version *semver.Version) []types.Release {
+	var kept []types.Release
+	for _, r := range releases {
+		if types.CompareVersions(r.Version, version) <= 0 {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
+
+// loadReleases returns the releases to analyse: the embedded cache merged with
+// recent releases when it is current, otherwise every release from the API.
+// Also reports whether cached releases were merged in.
+func (c *Checker) loadReleases(ctx context.Context) ([]types.Release, bool, error) {
+	var allReleases []types.Release
+	var merged bool
+	var err error
+
+	if c.config.Offline {
+		// Nothing can be fetched, so cached releases are used however old they are
+		if len(c.config.CachedReleases) == 0 {
+			return nil, false, ErrNoCachedReleases
+		}
+		allReleases = c.config.CachedReleases
+		if !c.keepsPrereleases() {
+			allReleases = releaseset.WithoutPrereleases(allReleases)
+		}
+		c.log(slog.LevelInfo, "offline, using cached releases", "releases", len(allReleases))
+		c.cacheDecided(ctx, CacheOffline, "offline")
+	} else if c.config.NoCache {
+		// Bypass embedded cache - fetch all releases from API
+		c.log(slog.LevelInfo, "embedded cache bypassed, fetching all releases", "reason", "--no-cache")
+		c.cacheDecided(ctx, CacheBypassed, "--no-cache")
+		allReleases, err = c.fetchReleases(ctx, FetchAll, func() ([]types.Release, error) { return c.client.GetAllReleases(ctx) })
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to fetch all releases: %w", err)
+		}
+	} else {
+		// Use embedded cache (or cached releases given in the config) with validation
+		embeddedReleases := c.config.CachedReleases
+		if len(embeddedReleases) > 0 {
+			c.log(slog.LevelInfo, "release cache loaded", "releases", len(embeddedReleases))
+		} else {
+			embeddedData, err := data.LoadEmbeddedReleases()
+			if err != nil {
+				return nil, false, fmt.Errorf("failed to load embedded releases: %w", err)
+			}
+
+			// Convert data.Release to types.Release
+			embeddedReleases = make([]types.Release, len(embeddedData))
+			for i, r := range embeddedData {
+				embeddedReleases[i] = types.Release{
+					Version:     r.Version,
+					PublishedAt: r.PublishedAt,
+					URL:         r.URL,
+					Prerelease:  r.Prerelease,
+				}
+			}
+			if latest := releaseset.Latest(embeddedReleases); latest != nil {
+				c.log(slog.LevelInfo, "embedded cache loaded", "releases", len(embeddedReleases), "latest", latest.Version.String())
+			}
+		}
+		// Caches bootstrapped with prereleases mark them; only prerelease tracking uses them
+		if !c.keepsPrereleases() {
+			embeddedReleases = releaseset.WithoutPrereleases(embeddedReleases)
+		}
+
+		// Fetch 5 most recent releases from API
+		recentReleases, err := c.fetchReleases(ctx, FetchRecent, func() ([]types.Release, error) { return c.client.GetRecentReleases(ctx, 5) })
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to fetch recent releases: %w", err)
+		}
+
+		if !c.isEmbeddedCurrent(embeddedReleases, recentReleases) {
+			// Embedded data is stale (>5 releases behind)
+			// Fall back to full API query
+			reason := c.staleReason(embeddedReleases, recentReleases)
+			c.log(slog.LevelInfo, "embedded cache is stale, fetching all releases", "reason", reason)
+			c.cacheDecided(ctx, CacheStale, reason)
+			allReleases, err = c.fetchReleases(ctx, FetchAll, func() ([]types.Release, error) { return c.client.GetAllReleases(ctx) })
+			if err != nil {
+				return nil, false, fmt.Errorf("failed to fetch all releases: %w", err)
+			}
+		} else {
+			// Merge embedded + recent (deduplicating)
+			allReleases = releaseset.Merge(recentReleases, embeddedReleases)
+			c.log(slog.LevelInfo, "embedded cache is current, merged with recent releases",
+				"recent", len(recentReleases), "total", len(allReleases))
+			c.cacheDecided(ctx, CacheCurrent, "")
+			merged = true
+		}
+	}
+
+	return allReleases, merged, nil
+}
+
 // CalculateRecentReleases returns releases for the expiry timeline table
-// Shows all releases from last 90 days, or minimum 4 releases
+// Shows all releases within the timeline window (default 90 days), or a minimum
+// number of releases (default 4), capped at TimelineMaxRows if set
 func (c *Checker) CalculateRecentReleases(allReleases []types.Release, comparisonVersion *semver.Version, latestVersion *semver.Version) []ReleaseExpiry {
 	now := time.Now()
 
 	// For version-based policies, show recent minor versions instead of time-based window
-	isVersionPolicy := c.policy != nil && c.policy.Type() == "versions"
+	// Release lines on a support schedule are shown like version-based ones, each
+	// release expiring with its line
+	schedule, scheduled := c.policy.(policy.SupportSchedulePolicy)
+	isVersionPolicy := c.policy != nil && c.policy.Type() == "versions" || scheduled
 
 	var recentReleases []types.Release
 
 	if isVersionPolicy {
 		// Sort all releases by version (newest first)
-		sorted := make([]types.Release, len(allReleases))
-		copy(sorted, allReleases)
-		for i := 0; i < len(sorted)-1; i++ {
-			for j := i + 1; j < len(sorted); j++ {
-				if sorted[i].Version.LessThan(sorted[j].Version) {
-					sorted[i], sorted[j] = sorted[j], sorted[i]
-				}
-			}
-		}
+		sorted := append([]types.Release(nil), allReleases...)
+		releaseset.SortByVersion(sorted)
 
 		// Get max versions behind from policy
 		maxVersionsBehind := 3 // Default
@@ -342,20 +705,21 @@ func (c *Checker) CalculateRecentReleases(allReleases []types.Release, compariso
 			}
 		}
 
-		// Now for each version group, add first and latest patch releases
-		for _, releases := range versionReleases {
+		// Now for each version group, add first and latest patch releases, in
+		// key order rather than the map's, which changes from run to run
+		keys := make([]string, 0, len(versionReleases))
+		for key := range versionReleases {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			releases := versionReleases[key]
 			if len(releases) == 0 {
 				continue
 			}
 
 			// Sort releases by version (highest to lowest)
-			for i := 0; i < len(releases)-1; i++ {
-				for j := i + 1; j < len(releases); j++ {
-					if releases[i].Version.LessThan(releases[j].Version) {
-						releases[i], releases[j] = releases[j], releases[i]
-					}
-				}
-			}
+			releaseset.SortByVersion(releases)
 
 			latest := releases[0]
 			first := releases[len(releases)-1]
@@ -364,14 +728,14 @@ func (c *Checker) CalculateRecentReleases(allReleases []types.Release, compariso
 			recentReleases = append(recentReleases, first)
 
 			// Add latest patch if different from first
-			if !latest.Version.Equal(first.Version) {
+			if types.CompareVersions(latest.Version, first.Version) != 0 {
 				recentReleases = append(recentReleases, latest)
 			}
 
 			// Add user's version if it's different from both first and latest
-			if !comparisonVersion.Equal(first.Version) && !comparisonVersion.Equal(latest.Version) {
+			if types.CompareVersions(comparisonVersion, first.Version) != 0 && types.CompareVersions(comparisonVersion, latest.Version) != 0 {
 				for _, r := range releases {
-					if r.Version.Equal(comparisonVersion) {
+					if types.CompareVersions(r.Version, comparisonVersion) == 0 {
 						recentReleases = append(recentReleases, r)
 						break
 					}
@@ -379,73 +743,77 @@ func (c *Checker) CalculateRecentReleases(allReleases []types.Release, compariso
 			}
 		}
 	} else {
-		// For days-based policies, use 90-day window
-		ninetyDaysAgo := now.AddDate(0, 0, -90)
+		// For days-based policies, use the configured window
+		windowStart := now.AddDate(0, 0, -c.timelineWindowDays())
 
-		// Collect releases from last 90 days
+		// Collect releases within the window
 		for _, release := range allReleases {
-			if release.PublishedAt.After(ninetyDaysAgo) {
+			if release.PublishedAt.After(windowStart) {
 				recentReleases = append(recentReleases, release)
 			}
 		}
 
-		// Ensure minimum 4 releases
-		if len(recentReleases) < 4 && len(allReleases) >= 4 {
+		// Ensure minimum number of releases
+		minRows := c.timelineMinRows()
+		if len(recentReleases) < minRows {
 			// Sort all releases by date (newest first)
-			sorted := make([]types.Release, len(allReleases))
-			copy(sorted, allReleases)
-			for i := 0; i < len(sorted)-1; i++ {
-				for j := i + 1; j < len(sorted); j++ {
-					if sorted[i].PublishedAt.Before(sorted[j].PublishedAt) {
-						sorted[i], sorted[j] = sorted[j], sorted[i]
-					}
-				}
+			sorted := append([]types.Release(nil), allReleases...)
+			releaseset.SortByDate(sorted)
+			if minRows > len(sorted) {
+				minRows = len(sorted)
 			}
-			// Take first 4
-			recentReleases = sorted[:4]
+			recentReleases = sorted[:minRows]
 		}
 	}
 
-	// Sort for display
+	// Sort for display, oldest first: by version for version-based policies,
+	// otherwise by date
 	if isVersionPolicy {
-		// For version-based policies, sort by version number (oldest first)
-		for i := 0; i < len(recentReleases)-1; i++ {
-			for j := i + 1; j < len(recentReleases); j++ {
-				if recentReleases[i].Version.GreaterThan(recentReleases[j].Version) {
-					recentReleases[i], recentReleases[j] = recentReleases[j], recentReleases[i]
-				}
-			}
-		}
+		releaseset.SortByVersion(recentReleases)
 	} else {
-		// For days-based policies, sort by date (oldest first)
-		for i := 0; i < len(recentReleases)-1; i++ {
-			for j := i + 1; j < len(recentReleases); j++ {
-				if recentReleases[i].PublishedAt.After(recentReleases[j].PublishedAt) {
-					recentReleases[i], recentReleases[j] = recentReleases[j], recentReleases[i]
-				}
-			}
-		}
+		releaseset.SortByDate(recentReleases)
+	}
+	slices.Reverse(recentReleases)
+
+	// Cap rows for busy repositories, keeping the newest
+	if maxRows := c.config.TimelineMaxRows; maxRows > 0 && len(recentReleases) > maxRows {
+		recentReleases = recentReleases[len(recentReleases)-maxRows:]
 	}
 
 	// Convert to ReleaseExpiry
+	maxAge := c.maxAgeDays()
+	if maxAge == 0 {
+		maxAge = DefaultMaxAgeDays
+	}
+
+	// Gaps are measured to the previous release of all, not the previous row
+	byDate := append([]types.Release(nil), allReleases...)
+	releaseset.SortByDate(byDate)
+	slices.Reverse(byDate)
+
 	var result []ReleaseExpiry
 	for i, release := range recentReleases {
 		expiry := ReleaseExpiry{
 			Version:    release.Version,
 			ReleasedAt: release.PublishedAt,
-			IsLatest:   release.Version.Equal(latestVersion),
+			IsLatest:   types.CompareVersions(release.Version, latestVersion) == 0,
+			GapDays:    gapDays(byDate, release.PublishedAt),
 		}
 
-		if isVersionPolicy {
+		if end, ok := scheduledEnd(schedule, release.Version); ok {
+			expiry.ExpiresAt = &end
+			expiry.DaysUntilExpiry = daysBetween(now, end)
+			expiry.IsExpired = !now.Before(end)
+		} else if isVersionPolicy {
 			// For version-based policies, don't calculate time-based expiry
 			expiry.ExpiresAt = nil
 			expiry.DaysUntilExpiry = 0
 			expiry.IsExpired = false
 		} else {
-			// For days-based policies, calculate expiry (30 days after next release)
+			// For days-based policies, calculate expiry (max age days after next release)
 			if i < len(recentReleases)-1 {
 				nextRelease := recentReleases[i+1]
-				expiryDate := nextRelease.PublishedAt.AddDate(0, 0, 30)
+				expiryDate := nextRelease.PublishedAt.AddDate(0, 0, maxAge)
 				expiry.ExpiresAt = &expiryDate
 				expiry.DaysUntilExpiry = daysBetween(now, expiryDate)
 				expiry.IsExpired = now.After(expiryDate)
@@ -463,42 +831,84 @@ func (c *Checker) CalculateRecentReleases(allReleases []types.Release, compariso
 	return result
 }
 
+// gapDays returns the days from the last release published before publishedAt
+// to it, or nil if none was; byDate is sorted oldest first
+func gapDays(byDate []types.Release, publishedAt time.Time) *int {
+	var previous *time.Time
+	for i := range byDate {
+		if !byDate[i].PublishedAt.Before(publishedAt) {
+			break
+		}
+		previous = &byDate[i].PublishedAt
+	}
+	if previous == nil {
+		return nil
+	}
+	gap := daysBetween(*previous, publishedAt)
+	return &gap
+}
+
 // findNewerReleases returns releases newer than the comparison version, sorted oldest-first
 func (c *Checker) findNewerReleases(releases []types.Release, comparisonVersion *semver.Version) []types.Release {
 	var newer []types.Release
 
-	for _, release := range releases {
-		if release.Version.GreaterThan(comparisonVersion) {
-			newer = append(newer, release)
+	// In date order, releases published before the comparison version do not count
+	var publishedAfter *time.Time
+	if c.config.Ordering == OrderingDate {
+		for _, release := range releases {
+			if types.CompareVersions(release.Version, comparisonVersion) == 0 {
+				publishedAt := release.PublishedAt
+				publishedAfter = &publishedAt
+				break
+			}
 		}
 	}
 
-	// Sort by published date (oldest first) - this gives us the first update
-	for i := 0; i < len(newer)-1; i++ {
-		for j := i + 1; j < len(newer); j++ {
-			if newer[i].PublishedAt.After(newer[j].PublishedAt) {
-				newer[i], newer[j] = newer[j], newer[i]
-			}
+	for _, release := range releaseset.NewerThan(releases, comparisonVersion) {
+		if publishedAfter != nil && !release.PublishedAt.After(*publishedAfter) {
+			continue
 		}
+		newer = append(newer, release)
 	}
 
+	// Sort by published date (oldest first) - this gives us the first update
+	releaseset.SortByDate(newer)
+	slices.Reverse(newer)
+
 	return newer
 }
 
+// countBehind counts the newer releases by the part of the comparison version
+// they change, so a version behind only on patches can be told apart
+func countBehind(analysis *Analysis) {
+	from := analysis.ComparisonVersion
+	for _, r := range analysis.NewerReleases {
+		switch {
+		case r.Version.Major() != from.Major():
+			analysis.MajorsBehind++
+		case r.Version.Minor() != from.Minor():
+			analysis.MinorsBehind++
+		default:
+			analysis.PatchesBehind++
+		}
+	}
+}
+
 // generateMessage creates a human-readable status message
 func (c *Checker) generateMessage(analysis *Analysis) string {
 	if analysis.IsLatest {
-		return fmt.Sprintf("Version %s is up to date", analysis.ComparisonVersion)
+		return fmt.Sprintf("Version %s is up to date", types.FormatVersion(analysis.ComparisonVersion))
 	}
 
 	// Handle version-based policies
 	if analysis.PolicyType == "versions" {
 		var prefix string
-		if analysis.IsExpired {
+		switch analysis.Status() {
+		case StatusExpired:
 			prefix = "UNSUPPORTED"
-		} else if analysis.IsCritical {
+		case StatusCritical:
 			prefix = "CRITICAL"
-		} else {
+		default:
 			prefix = "Warning"
 		}
 
@@ -512,15 +922,20 @@ func (c *Checker) generateMessage(analysis *Analysis) string {
 		}
 
 		msg := fmt.Sprintf("Version %s %s: %d minor version%s behind",
-			analysis.ComparisonVersion,
+			types.FormatVersion(analysis.ComparisonVersion),
 			prefix,
 			analysis.MinorVersionsBehind,
-			pluralSuffix(analysis.MinorVersionsBehind))
+			types.PluralSuffix(analysis.MinorVersionsBehind))
 
 		if analysis.IsExpired {
 			msg += fmt.Sprintf(" (maximum %d allowed)", maxAllowed)
 		} else if analysis.IsCritical {
 			msg += fmt.Sprintf(" (at maximum %d allowed)", maxAllowed)
+		} else if analysis.Waived {
+			msg += fmt.Sprintf(" (maximum %d allowed, expiry waived until %s)", maxAllowed, analysis.Waiver.Expires.Format(time.DateOnly))
+		}
+		if notes := describeNoteMatches(analysis.NoteMatches); notes != "" {
+			msg += " AND " + notes
 		}
 
 		return msg
@@ -531,17 +946,30 @@ func (c *Checker) generateMessage(analysis *Analysis) string {
 
 	// Count releases
 	if analysis.ReleasesBehind > 0 {
-		issues = append(issues, fmt.Sprintf("%d release%s behind", analysis.ReleasesBehind, pluralSuffix(analysis.ReleasesBehind)))
+		issues = append(issues, fmt.Sprintf("%d release%s behind", analysis.ReleasesBehind, types.PluralSuffix(analysis.ReleasesBehind)))
 	}
 
 	// Age status
-	if analysis.IsExpired {
-		daysOver := analysis.DaysSinceUpdate - c.config.MaxAgeDays
+	if analysis.EndOfLife != nil {
+		if issue := endOfLifeIssue(analysis); issue != "" {
+			issues = append(issues, issue)
+		}
+	} else if analysis.IsExpired {
+		daysOver := analysis.DaysSinceUpdate - analysis.MaxAgeDays
 		issues = append(issues, fmt.Sprintf("%d days overdue", daysOver))
 	} else if analysis.IsCritical {
-		daysLeft := c.config.MaxAgeDays - analysis.DaysSinceUpdate
+		daysLeft := analysis.DaysUntilExpiry()
 		issues = append(issues, fmt.Sprintf("expires in %d days", daysLeft))
 	}
+	if analysis.Waived {
+		issues = append(issues, fmt.Sprintf("expiry waived until %s", analysis.Waiver.Expires.Format(time.DateOnly)))
+	}
+	if analysis.MaintenanceWindow != nil {
+		issues = append(issues, fmt.Sprintf("must be included in the %s window", analysis.MaintenanceWindow.Format(time.DateOnly)))
+	}
+	if notes := describeNoteMatches(analysis.NoteMatches); notes != "" {
+		issues = append(issues, notes)
+	}
 
 	issueStr := ""
 	if len(issues) > 0 {
@@ -552,23 +980,40 @@ func (c *Checker) generateMessage(analysis *Analysis) string {
 	}
 
 	var prefix string
-	if analysis.IsExpired {
+	switch analysis.Status() {
+	case StatusExpired:
 		prefix = "EXPIRED"
-	} else if analysis.IsCritical {
+	case StatusCritical:
 		prefix = "CRITICAL"
-	} else {
+	default:
 		prefix = "Warning"
 	}
 
-	return fmt.Sprintf("Version %s %s: %s", analysis.ComparisonVersion, prefix, issueStr)
+	return fmt.Sprintf("Version %s %s: %s", types.FormatVersion(analysis.ComparisonVersion), prefix, issueStr)
+}
+
+// scheduledEnd returns the end of life of v's release line, if schedule covers it
+func scheduledEnd(schedule policy.SupportSchedulePolicy, v *semver.Version) (time.Time, bool) {
+	if schedule == nil {
+		return time.Time{}, false
+	}
+	_, end, ok := schedule.Support(v)
+	return end, ok
 }
 
-// pluralSuffix returns "s" if count != 1, otherwise ""
-func pluralSuffix(count int) string {
-	if count == 1 {
-		return ""
+// endOfLifeIssue describes where the comparison version's release line is in
+// its support schedule, or "" while it is actively supported
+func endOfLifeIssue(analysis *Analysis) string {
+	end := analysis.EndOfLife.Format(time.DateOnly)
+	switch {
+	case analysis.SupportPhase == policy.PhaseEndOfLife:
+		return fmt.Sprintf("%d.x reached end of life on %s", analysis.ComparisonVersion.Major(), end)
+	case analysis.IsCritical:
+		return fmt.Sprintf("%d.x reaches end of life in %d days", analysis.ComparisonVersion.Major(), analysis.DaysUntilExpiry())
+	case analysis.SupportPhase == policy.PhaseMaintenance:
+		return fmt.Sprintf("%d.x is in maintenance until %s", analysis.ComparisonVersion.Major(), end)
 	}
-	return "s"
+	return ""
 }
 
 // daysBetween calculates the number of days between two dates
@@ -578,19 +1023,24 @@ func daysBetween(start, end time.Time) int {
 }
 
 // FindLatestRelease finds the release with the highest version number
+//
+// Deprecated: use releaseset.Latest.
 func FindLatestRelease(releases []types.Release) *types.Release {
-	if len(releases) == 0 {
-		return nil
-	}
+	return releaseset.Latest(releases)
+}
 
-	latest := &releases[0]
-	for i := range releases {
-		if releases[i].Version.GreaterThan(latest.Version) {
-			latest = &releases[i]
-		}
+// staleReason explains why isEmbeddedCurrent rejected the embedded data
+func (c *Checker) staleReason(embedded, recent []types.Release) string {
+	if len(embedded) == 0 {
+		return "embedded cache is empty"
 	}
-
-	return latest
+	if len(recent) == 0 {
+		return "no recent releases returned by the API"
+	}
+	latestEmbedded := releaseset.Latest(embedded)
+	latestRecent := releaseset.Latest(recent)
+	return fmt.Sprintf("latest embedded release %s is not among the %d most recent releases (latest %s)",
+		latestEmbedded.Version, len(recent), latestRecent.Version)
 }
 
 // isEmbeddedCurrent checks if embedded data contains the latest release
@@ -601,14 +1051,14 @@ func (c *Checker) isEmbeddedCurrent(embedded, recent []types.Release) bool {
 	}
 
 	// Find latest embedded release
-	latestEmbedded := FindLatestRelease(embedded)
+	latestEmbedded := releaseset.Latest(embedded)
 	if latestEmbedded == nil {
 		return false
 	}
 
 	// Check if it exists in recent 5
 	for _, r := range recent {
-		if r.Version.Equal(latestEmbedded.Version) {
+		if types.CompareVersions(r.Version, latestEmbedded.Version) == 0 {
 			return true
 		}
 	}
@@ -1,12 +1,18 @@
 package checker
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"log/slog"
+	"regexp"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/Masterminds/semver/v3"
+	"github.com/nickromney-org/github-release-version-checker/pkg/policy"
 	"github.com/nickromney-org/github-release-version-checker/pkg/types"
 )
 
@@ -105,6 +111,30 @@ func TestAnalyse_CurrentVersion(t *testing.T) {
 	}
 }
 
+func TestAnalyse_VersionSuffix(t *testing.T) {
+	latest := newTestRelease("2.329.0", 3)
+	previous := newTestRelease("2.328.0", 60)
+
+	client := &MockGitHubClient{
+		LatestRelease: &latest,
+		AllReleases:   []types.Release{latest, previous},
+	}
+
+	checker := NewChecker(client, Config{
+		CriticalAgeDays: 12,
+		MaxAgeDays:      30,
+		VersionSuffix:   regexp.MustCompile(`(?:-corp\.\d+)$`),
+	})
+
+	analysis, err := checker.Analyse(context.Background(), "v2.328.0-corp.4")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if analysis.ComparisonVersion.String() != "2.328.0" || analysis.ReleasesBehind != 1 {
+		t.Errorf("ComparisonVersion = %s, ReleasesBehind = %d, want 2.328.0, 1", analysis.ComparisonVersion, analysis.ReleasesBehind)
+	}
+}
+
 func TestAnalyse_ExpiredVersion(t *testing.T) {
 	latest := newTestRelease("2.329.0", 3)
 	newer := newTestRelease("2.328.0", 65) // Released 65 days ago
@@ -200,6 +230,11 @@ func TestAnalyse_NonExistentVersion(t *testing.T) {
 	if !strings.Contains(err.Error(), expectedMsg) {
 		t.Errorf("expected error containing %q, got %q", expectedMsg, err.Error())
 	}
+
+	var notFound *VersionNotFoundError
+	if !errors.As(err, &notFound) || notFound.Latest.String() != "2.329.0" {
+		t.Errorf("expected VersionNotFoundError with latest 2.329.0, got %#v", err)
+	}
 }
 
 func TestAnalyse_PopulatesRecentReleases(t *testing.T) {
@@ -238,6 +273,142 @@ func TestAnalyse_PopulatesRecentReleases(t *testing.T) {
 	}
 }
 
+func TestAnalyse_MaxNewer(t *testing.T) {
+	releases := []types.Release{
+		newTestRelease("2.329.0", 5),
+		newTestRelease("2.328.0", 25),
+		newTestRelease("2.327.1", 50),
+		newTestRelease("2.327.0", 80),
+	}
+	client := &MockGitHubClient{LatestRelease: &releases[0], AllReleases: releases}
+
+	tests := []struct {
+		name     string
+		maxNewer int
+		policy   policy.VersionPolicy
+		want     []string
+	}{
+		{name: "no limit", want: []string{"2.327.1", "2.328.0", "2.329.0"}},
+		{name: "newest kept", maxNewer: 2, want: []string{"2.328.0", "2.329.0"}},
+		{name: "limit above the count", maxNewer: 5, want: []string{"2.327.1", "2.328.0", "2.329.0"}},
+		{name: "days policy", maxNewer: 1, policy: policy.NewDaysPolicy(12, 30), want: []string{"2.329.0"}},
+		{name: "versions policy", maxNewer: 1, policy: policy.NewVersionsPolicy(3), want: []string{"2.329.0"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Config{CriticalAgeDays: 12, MaxAgeDays: 30, NoCache: true, MaxNewer: tt.maxNewer}
+			checker := NewChecker(client, config)
+			if tt.policy != nil {
+				checker = NewCheckerWithPolicy(client, config, tt.policy)
+			}
+			analysis, err := checker.Analyse(context.Background(), "2.327.0")
+			if err != nil {
+				t.Fatalf("Analyse failed: %v", err)
+			}
+			var got []string
+			for _, r := range analysis.NewerReleases {
+				got = append(got, r.Version.String())
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("NewerReleases = %v, want %v", got, tt.want)
+			}
+			if analysis.TotalNewer != 3 || analysis.ReleasesBehind != 3 {
+				t.Errorf("TotalNewer = %d, ReleasesBehind = %d, want 3", analysis.TotalNewer, analysis.ReleasesBehind)
+			}
+			if want := 3 - len(tt.want); analysis.NewerOmitted() != want {
+				t.Errorf("NewerOmitted() = %d, want %d", analysis.NewerOmitted(), want)
+			}
+			// The first newer release is still the oldest, so expiry is unchanged
+			if analysis.FirstNewerVersion.String() != "2.327.1" {
+				t.Errorf("FirstNewerVersion = %s, want 2.327.1", analysis.FirstNewerVersion)
+			}
+		})
+	}
+}
+
+func TestAnalyse_BehindByKind(t *testing.T) {
+	releases := []types.Release{
+		newTestRelease("3.0.0", 5),
+		newTestRelease("2.330.0", 10),
+		newTestRelease("2.329.1", 15),
+		newTestRelease("2.329.0", 20),
+		newTestRelease("2.328.2", 25),
+		newTestRelease("2.328.1", 30),
+		newTestRelease("2.328.0", 40),
+	}
+	client := &MockGitHubClient{LatestRelease: &releases[0], AllReleases: releases}
+
+	tests := []struct {
+		version                string
+		patches, minors, major int
+	}{
+		{version: "2.328.0", patches: 2, minors: 3, major: 1},
+		{version: "2.329.0", patches: 1, minors: 1, major: 1},
+		{version: "2.330.0", major: 1},
+		{version: "3.0.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			checker := NewChecker(client, Config{CriticalAgeDays: 12, MaxAgeDays: 30, NoCache: true})
+			analysis, err := checker.Analyse(context.Background(), tt.version)
+			if err != nil {
+				t.Fatalf("Analyse failed: %v", err)
+			}
+			if analysis.PatchesBehind != tt.patches || analysis.MinorsBehind != tt.minors || analysis.MajorsBehind != tt.major {
+				t.Errorf("behind = %d patches, %d minors, %d majors, want %d, %d, %d",
+					analysis.PatchesBehind, analysis.MinorsBehind, analysis.MajorsBehind, tt.patches, tt.minors, tt.major)
+			}
+		})
+	}
+}
+
+func TestAnalyse_Deterministic(t *testing.T) {
+	// Releases published together, as in a cache that only knows days, in
+	// several minor lines, so neither dates nor map order can decide the order
+	published := time.Now().AddDate(0, 0, -10).Truncate(24 * time.Hour)
+	var releases []types.Release
+	for _, v := range []string{"1.34.1", "1.34.0", "1.33.2", "1.33.1", "1.33.0", "1.32.1", "1.32.0", "1.31.4", "1.31.0"} {
+		releases = append(releases, types.Release{Version: semver.MustParse(v), PublishedAt: published})
+	}
+
+	tests := []struct {
+		name   string
+		policy policy.VersionPolicy
+	}{
+		{"days", policy.NewDaysPolicy(12, 30)},
+		{"versions", policy.NewVersionsPolicy(3)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var first string
+			for run := 0; run < 20; run++ {
+				// Rotate the input too, as API pages and caches order releases differently
+				shift := run % len(releases)
+				input := append(append([]types.Release(nil), releases[shift:]...), releases[:shift]...)
+				client := &MockGitHubClient{LatestRelease: &releases[0], AllReleases: input}
+				analysis, err := NewCheckerWithPolicy(client, Config{NoCache: true}, tt.policy).Analyse(context.Background(), "1.31.4")
+				if err != nil {
+					t.Fatalf("Analyse failed: %v", err)
+				}
+				var order []string
+				for _, r := range analysis.NewerReleases {
+					order = append(order, r.Version.String())
+				}
+				order = append(order, "|")
+				for _, r := range analysis.RecentReleases {
+					order = append(order, r.Version.String())
+				}
+				got := strings.Join(order, " ")
+				if run == 0 {
+					first = got
+				} else if got != first {
+					t.Fatalf("run %d ordered releases %s, first run %s", run, got, first)
+				}
+			}
+		})
+	}
+}
+
 func TestCalculateRecentReleases_Last90Days(t *testing.T) {
 	// Create releases spanning 120 days
 	releases := []types.Release{
@@ -280,6 +451,140 @@ func TestCalculateRecentReleases_Minimum4(t *testing.T) {
 	}
 }
 
+func TestCalculateRecentReleases_GapDays(t *testing.T) {
+	now := time.Now().UTC()
+	release := func(version string, daysAgo int) types.Release {
+		return types.Release{Version: semver.MustParse(version), PublishedAt: now.AddDate(0, 0, -daysAgo)}
+	}
+	// 2.327.0 is outside the window but still the release before 2.328.0
+	releases := []types.Release{
+		release("2.329.1", 5),
+		release("2.329.0", 5),
+		release("2.328.0", 25),
+		release("2.327.0", 100),
+	}
+
+	checker := &Checker{config: Config{TimelineWindowDays: 30, TimelineMinRows: 1}}
+	recent := checker.CalculateRecentReleases(releases, semver.MustParse("2.328.0"), semver.MustParse("2.329.1"))
+
+	want := map[string]int{"2.328.0": 75, "2.329.0": 20, "2.329.1": 20}
+	if len(recent) != len(want) {
+		t.Fatalf("expected %d releases, got %d", len(want), len(recent))
+	}
+	for _, r := range recent {
+		if r.GapDays == nil || *r.GapDays != want[r.Version.String()] {
+			t.Errorf("%s: GapDays = %v, want %d", r.Version, r.GapDays, want[r.Version.String()])
+		}
+	}
+
+	// The first release has no gap
+	recent = checker.CalculateRecentReleases(releases[3:], semver.MustParse("2.327.0"), semver.MustParse("2.327.0"))
+	if len(recent) != 1 || recent[0].GapDays != nil {
+		t.Errorf("expected no gap for the first release, got %+v", recent)
+	}
+}
+
+func TestCalculateRecentReleases_TimelineConfig(t *testing.T) {
+	releases := []types.Release{
+		newTestRelease("2.330.0", 2),
+		newTestRelease("2.329.0", 5),
+		newTestRelease("2.328.0", 25),
+		newTestRelease("2.327.1", 50),
+		newTestRelease("2.327.0", 80),
+		newTestRelease("2.326.0", 100),
+	}
+
+	comparisonVersion := semver.MustParse("2.327.0")
+	latestVersion := semver.MustParse("2.330.0")
+
+	tests := []struct {
+		name   string
+		config Config
+		want   []string
+	}{
+		{
+			name:   "defaults",
+			config: Config{},
+			want:   []string{"2.327.0", "2.327.1", "2.328.0", "2.329.0", "2.330.0"},
+		},
+		{
+			name:   "narrow window",
+			config: Config{TimelineWindowDays: 30, TimelineMinRows: 1},
+			want:   []string{"2.328.0", "2.329.0", "2.330.0"},
+		},
+		{
+			name:   "minimum rows tops up window",
+			config: Config{TimelineWindowDays: 3, TimelineMinRows: 2},
+			want:   []string{"2.329.0", "2.330.0"},
+		},
+		{
+			name:   "row cap keeps newest",
+			config: Config{TimelineMaxRows: 3},
+			want:   []string{"2.328.0", "2.329.0", "2.330.0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := NewChecker(nil, tt.config)
+			recent := checker.CalculateRecentReleases(releases, comparisonVersion, latestVersion)
+
+			var got []string
+			for _, r := range recent {
+				got = append(got, r.Version.String())
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculateRecentReleases_UsesPolicyMaxDays(t *testing.T) {
+	releases := []types.Release{
+		newTestRelease("2.329.0", 5),
+		newTestRelease("2.328.0", 25),
+	}
+
+	comparisonVersion := semver.MustParse("2.328.0")
+	latestVersion := semver.MustParse("2.329.0")
+
+	// Config and policy disagree: the policy's window must win
+	checker := NewCheckerWithPolicy(nil, Config{CriticalAgeDays: 12, MaxAgeDays: 30}, policy.NewDaysPolicy(3, 7))
+	recent := checker.CalculateRecentReleases(releases, comparisonVersion, latestVersion)
+
+	if len(recent) != 2 {
+		t.Fatalf("expected 2 releases, got %d", len(recent))
+	}
+
+	expected := releases[0].PublishedAt.AddDate(0, 0, 7)
+	if recent[0].ExpiresAt == nil || !recent[0].ExpiresAt.Equal(expected) {
+		t.Errorf("expected expiry %v, got %v", expected, recent[0].ExpiresAt)
+	}
+	if checker.maxAgeDays() != 7 || checker.criticalAgeDays() != 3 {
+		t.Errorf("expected policy thresholds 3/7, got %d/%d", checker.criticalAgeDays(), checker.maxAgeDays())
+	}
+}
+
+func TestAnalysis_ExpiryDate(t *testing.T) {
+	firstNewer := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	analysis := &Analysis{FirstNewerReleaseDate: &firstNewer, MaxAgeDays: 14, DaysSinceUpdate: 10}
+	expiry := analysis.ExpiryDate()
+	if expiry == nil || !expiry.Equal(firstNewer.AddDate(0, 0, 14)) {
+		t.Errorf("expected expiry 14 days after first newer release, got %v", expiry)
+	}
+	if analysis.DaysUntilExpiry() != 4 {
+		t.Errorf("expected 4 days until expiry, got %d", analysis.DaysUntilExpiry())
+	}
+
+	// Version-based policies have no expiry date
+	analysis.MaxAgeDays = 0
+	if analysis.ExpiryDate() != nil {
+		t.Error("expected no expiry date without a days-based window")
+	}
+}
+
 func TestAnalyse_NoComparisonVersion(t *testing.T) {
 	// Test that analysis works with no comparison version (verbose mode)
 	client := &MockGitHubClient{
@@ -373,6 +678,46 @@ func TestConfig_Validate(t *testing.T) {
 			config:  Config{CriticalAgeDays: 0, MaxAgeDays: 0},
 			wantErr: false,
 		},
+		{
+			name:    "marked latest preference",
+			config:  Config{CriticalAgeDays: 12, MaxAgeDays: 30, LatestPreference: LatestMarked},
+			wantErr: false,
+		},
+		{
+			name:    "unknown ordering",
+			config:  Config{CriticalAgeDays: 12, MaxAgeDays: 30, Ordering: "alphabetical"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown track",
+			config:  Config{CriticalAgeDays: 12, MaxAgeDays: 30, Track: "nightly"},
+			wantErr: true,
+		},
+		{
+			name:    "offline without the cache",
+			config:  Config{CriticalAgeDays: 12, MaxAgeDays: 30, Offline: true, NoCache: true},
+			wantErr: true,
+		},
+		{
+			name:    "track prerelease with a channel",
+			config:  Config{CriticalAgeDays: 12, MaxAgeDays: 30, Track: TrackPrerelease, Channel: "rc"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown latest preference",
+			config:  Config{CriticalAgeDays: 12, MaxAgeDays: 30, LatestPreference: "newest"},
+			wantErr: true,
+		},
+		{
+			name:    "negative max newer",
+			config:  Config{CriticalAgeDays: 12, MaxAgeDays: 30, MaxNewer: -1},
+			wantErr: true,
+		},
+		{
+			name:    "platform without an architecture",
+			config:  Config{CriticalAgeDays: 12, MaxAgeDays: 30, Platform: "linux"},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -437,36 +782,379 @@ func TestChecker_IsEmbeddedCurrent(t *testing.T) {
 	}
 }
 
-func TestChecker_MergeReleases(t *testing.T) {
-	embedded := []types.Release{
-		newTestRelease("2.327.0", 50),
-		newTestRelease("2.326.0", 60),
+func TestAnalyse_LogsCacheDecision(t *testing.T) {
+	// Releases unrelated to the embedded runner cache, so it is reported stale
+	client := &MockGitHubClient{
+		AllReleases: []types.Release{
+			newTestRelease("1.31.0", 3),
+			newTestRelease("1.30.0", 40),
+		},
 	}
 
-	recent := []types.Release{
+	var buf bytes.Buffer
+	checker := NewChecker(client, Config{})
+	checker.SetLogger(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo})))
+
+	if _, err := checker.Analyse(context.Background(), ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	logs := buf.String()
+	if !strings.Contains(logs, "embedded cache is stale") {
+		t.Errorf("expected stale cache decision to be logged, got:\n%s", logs)
+	}
+	if !strings.Contains(logs, "is not among the 2 most recent releases (latest 1.31.0)") {
+		t.Errorf("expected stale reason to be logged, got:\n%s", logs)
+	}
+}
+
+func TestAnalyse_VersionOlderThanCache(t *testing.T) {
+	all := []types.Release{
 		newTestRelease("2.329.0", 5),
-		newTestRelease("2.328.0", 20),
-		newTestRelease("2.327.0", 50), // Duplicate
+		newTestRelease("2.328.0", 40),
+		newTestRelease("2.327.0", 70),
+		newTestRelease("2.326.0", 100),
+		newTestRelease("2.325.0", 130),
+		newTestRelease("2.300.0", 400),
+		newTestRelease("2.299.0", 420),
 	}
+	trimmed := all[:5] // As kept by a cache with a retention limit
 
-	checker := &Checker{}
-	merged := checker.mergeReleases(embedded, recent)
+	tests := []struct {
+		name    string
+		version string
+		wantErr bool
+	}{
+		{"version in cache", "2.328.0", false},
+		{"version trimmed from cache", "2.300.0", false},
+		{"missing version within cache", "2.328.5", true},
+		{"missing version older than cache", "2.100.0", true},
+	}
 
-	// Should have 4 unique releases (deduplicated 2.327.0)
-	if len(merged) != 4 {
-		t.Errorf("expected 4 releases, got %d", len(merged))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := NewChecker(&MockGitHubClient{AllReleases: all}, Config{CriticalAgeDays: 12, MaxAgeDays: 30, CachedReleases: trimmed})
+			analysis, err := checker.Analyse(context.Background(), tt.version)
+			var notFound *VersionNotFoundError
+			if tt.wantErr {
+				if !errors.As(err, &notFound) {
+					t.Errorf("err = %v, want VersionNotFoundError", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if analysis.ComparisonVersion.String() != tt.version {
+				t.Errorf("ComparisonVersion = %s, want %s", analysis.ComparisonVersion, tt.version)
+			}
+		})
 	}
+}
 
-	// Check all versions present
-	versions := make(map[string]bool)
-	for _, r := range merged {
-		versions[r.Version.String()] = true
+func TestAnalyse_SkipsMarkedPrereleasesInCache(t *testing.T) {
+	stable := []types.Release{newTestRelease("2.329.0", 5), newTestRelease("2.328.0", 40)}
+	prerelease := newTestRelease("2.330.0", 1)
+	prerelease.Prerelease = true
+	cached := append([]types.Release{prerelease}, stable...)
+
+	checker := NewChecker(&MockGitHubClient{AllReleases: stable}, Config{CriticalAgeDays: 12, MaxAgeDays: 30, CachedReleases: cached})
+	analysis, err := checker.Analyse(context.Background(), "2.329.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if analysis.LatestVersion.String() != "2.329.0" || !analysis.IsLatest {
+		t.Errorf("LatestVersion = %s, IsLatest = %v; want 2.329.0, true", analysis.LatestVersion, analysis.IsLatest)
 	}
+}
 
-	expected := []string{"2.329.0", "2.328.0", "2.327.0", "2.326.0"}
-	for _, v := range expected {
-		if !versions[v] {
-			t.Errorf("missing version %s in merged releases", v)
-		}
+func TestAnalyse_Offline(t *testing.T) {
+	cached := []types.Release{newTestRelease("2.329.0", 5), newTestRelease("2.328.0", 40), newTestRelease("2.300.0", 400)}
+	// Every API call fails, as on an air-gapped network
+	client := &MockGitHubClient{Error: errors.New("dial tcp: no route to host")}
+
+	checker := NewChecker(client, Config{CriticalAgeDays: 12, MaxAgeDays: 30, CachedReleases: cached, Offline: true})
+	analysis, err := checker.Analyse(context.Background(), "2.328.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if analysis.LatestVersion.String() != "2.329.0" || analysis.ReleasesBehind != 1 {
+		t.Errorf("LatestVersion = %s, ReleasesBehind = %d; want 2.329.0, 1", analysis.LatestVersion, analysis.ReleasesBehind)
+	}
+
+	// Versions older than the cache cannot be looked up
+	var notFound *VersionNotFoundError
+	if _, err := checker.Analyse(context.Background(), "2.100.0"); !errors.As(err, &notFound) {
+		t.Errorf("err = %v, want VersionNotFoundError", err)
+	}
+
+	empty := NewChecker(client, Config{CriticalAgeDays: 12, MaxAgeDays: 30, Offline: true})
+	if _, err := empty.Analyse(context.Background(), ""); !errors.Is(err, ErrNoCachedReleases) {
+		t.Errorf("err = %v, want ErrNoCachedReleases", err)
+	}
+}
+
+func TestAnalyse_Track(t *testing.T) {
+	rc := newTestRelease("2.330.0-rc.1", 2)
+	rc.Prerelease = true
+	releases := []types.Release{rc, newTestRelease("2.329.0", 10), newTestRelease("2.328.0", 40)}
+
+	tests := []struct {
+		name         string
+		track        Track
+		version      string
+		wantLatest   string
+		wantBehind   int
+		wantIsLatest bool
+	}{
+		{"stable ignores the prerelease", TrackStable, "2.329.0", "2.329.0", 0, true},
+		{"stable counts only stable releases behind", "", "2.328.0", "2.329.0", 1, false},
+		{"prerelease tracks the candidate", TrackPrerelease, "2.329.0", "2.330.0-rc.1", 1, false},
+		{"prerelease on the candidate", TrackPrerelease, "2.330.0-rc.1", "2.330.0-rc.1", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := NewChecker(&MockGitHubClient{AllReleases: releases}, Config{CriticalAgeDays: 12, MaxAgeDays: 30, NoCache: true, Track: tt.track})
+			analysis, err := checker.Analyse(context.Background(), tt.version)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if analysis.LatestVersion.String() != tt.wantLatest || analysis.ReleasesBehind != tt.wantBehind || analysis.IsLatest != tt.wantIsLatest {
+				t.Errorf("LatestVersion, ReleasesBehind, IsLatest = %s, %d, %v; want %s, %d, %v",
+					analysis.LatestVersion, analysis.ReleasesBehind, analysis.IsLatest, tt.wantLatest, tt.wantBehind, tt.wantIsLatest)
+			}
+			if analysis.LatestStable.String() != "2.329.0" || analysis.LatestIncludingPrerelease.String() != "2.330.0-rc.1" {
+				t.Errorf("LatestStable, LatestIncludingPrerelease = %v, %v", analysis.LatestStable, analysis.LatestIncludingPrerelease)
+			}
+		})
+	}
+}
+
+func TestAnalyse_TrackWithoutPrereleaseData(t *testing.T) {
+	releases := []types.Release{newTestRelease("2.329.0", 10), newTestRelease("2.328.0", 40)}
+	checker := NewChecker(&MockGitHubClient{AllReleases: releases}, Config{CriticalAgeDays: 12, MaxAgeDays: 30, NoCache: true, Track: TrackPrerelease})
+	analysis, err := checker.Analyse(context.Background(), "2.328.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if analysis.LatestStable != nil || analysis.LatestIncludingPrerelease != nil {
+		t.Errorf("LatestStable, LatestIncludingPrerelease = %v, %v; want both unset", analysis.LatestStable, analysis.LatestIncludingPrerelease)
+	}
+}
+
+// truncatingClient reports its release list as cut off at the page limit
+type truncatingClient struct {
+	MockGitHubClient
+}
+
+func (c *truncatingClient) Truncated() bool { return true }
+
+func TestAnalyse_DegradedWhenTruncated(t *testing.T) {
+	latest := newTestRelease("2.329.0", 3)
+	releases := []types.Release{latest, newTestRelease("2.328.0", 20)}
+
+	tests := []struct {
+		name   string
+		client GitHubClient
+		want   []DegradedReason
+	}{
+		{name: "complete list", client: &MockGitHubClient{AllReleases: releases}},
+		{name: "truncated list", client: &truncatingClient{MockGitHubClient{AllReleases: releases}}, want: []DegradedReason{DegradedTruncated}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := NewChecker(tt.client, Config{CriticalAgeDays: 12, MaxAgeDays: 30, NoCache: true})
+			analysis, err := checker.Analyse(context.Background(), "2.328.0")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(analysis.DegradedReasons) != len(tt.want) || analysis.IsDegraded() != (len(tt.want) > 0) {
+				t.Errorf("DegradedReasons = %v, want %v", analysis.DegradedReasons, tt.want)
+			}
+
+			data, err := analysis.MarshalJSON()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if wantJSON := `"degraded": ` + map[bool]string{true: "true", false: "false"}[len(tt.want) > 0]; !strings.Contains(string(data), wantJSON) {
+				t.Errorf("JSON missing %s:\n%s", wantJSON, data)
+			}
+		})
+	}
+}
+
+func TestAnalyse_LatestDiscrepancy(t *testing.T) {
+	// A maintenance release on the 1.x branch, published after 2.1.0, is marked latest
+	maintenance := newTestRelease("1.9.5", 2)
+	releases := []types.Release{
+		maintenance,
+		newTestRelease("2.1.0", 10),
+		newTestRelease("2.0.0", 40),
+		newTestRelease("1.9.4", 50),
+	}
+
+	tests := []struct {
+		name        string
+		marked      *types.Release
+		preference  LatestPreference
+		wantLatest  string
+		wantBehind  int
+		discrepancy bool
+	}{
+		{name: "no marked release", wantLatest: "2.1.0", wantBehind: 3},
+		{name: "marked is highest", marked: &releases[1], wantLatest: "2.1.0", wantBehind: 3},
+		{name: "discrepancy, highest preferred", marked: &maintenance, wantLatest: "2.1.0", wantBehind: 3, discrepancy: true},
+		{name: "discrepancy, marked preferred", marked: &maintenance, preference: LatestMarked, wantLatest: "1.9.5", wantBehind: 1, discrepancy: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &MockGitHubClient{LatestRelease: tt.marked, AllReleases: releases}
+			checker := NewChecker(client, Config{CriticalAgeDays: 12, MaxAgeDays: 60, NoCache: true, LatestPreference: tt.preference})
+
+			analysis, err := checker.Analyse(context.Background(), "1.9.4")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if analysis.LatestVersion.String() != tt.wantLatest {
+				t.Errorf("LatestVersion = %s, want %s", analysis.LatestVersion, tt.wantLatest)
+			}
+			if analysis.ReleasesBehind != tt.wantBehind {
+				t.Errorf("ReleasesBehind = %d, want %d", analysis.ReleasesBehind, tt.wantBehind)
+			}
+			if analysis.HighestVersion.String() != "2.1.0" {
+				t.Errorf("HighestVersion = %s, want 2.1.0", analysis.HighestVersion)
+			}
+			if analysis.LatestDiscrepancy() != tt.discrepancy {
+				t.Errorf("LatestDiscrepancy() = %v, want %v", analysis.LatestDiscrepancy(), tt.discrepancy)
+			}
+		})
+	}
+}
+
+func TestAnalyse_DateOrdering(t *testing.T) {
+	// 2.0.1 is a backport published after 2.1.0
+	releases := []types.Release{
+		newTestRelease("2.2.0", 2),
+		newTestRelease("2.0.1", 5),
+		newTestRelease("2.1.0", 45),
+		newTestRelease("2.0.0", 60),
+	}
+
+	tests := []struct {
+		name       string
+		ordering   Ordering
+		version    string
+		wantBehind int
+		wantStatus Status
+	}{
+		{name: "semver counts releases published earlier", ordering: OrderingSemver, version: "2.0.1", wantBehind: 2, wantStatus: StatusExpired},
+		{name: "date ignores releases published earlier", ordering: OrderingDate, version: "2.0.1", wantBehind: 1, wantStatus: StatusWarning},
+		{name: "date still counts later higher releases", ordering: OrderingDate, version: "2.0.0", wantBehind: 3, wantStatus: StatusExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &MockGitHubClient{AllReleases: releases}
+			checker := NewChecker(client, Config{CriticalAgeDays: 12, MaxAgeDays: 30, NoCache: true, Ordering: tt.ordering})
+
+			analysis, err := checker.Analyse(context.Background(), tt.version)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if analysis.ReleasesBehind != tt.wantBehind {
+				t.Errorf("ReleasesBehind = %d, want %d", analysis.ReleasesBehind, tt.wantBehind)
+			}
+			if analysis.Status() != tt.wantStatus {
+				t.Errorf("Status() = %s, want %s", analysis.Status(), tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestAnalyse_MaintenanceWindow(t *testing.T) {
+	latest := newTestRelease("1.1.0", 20)
+	releases := []types.Release{latest, newTestRelease("1.0.0", 60)}
+	client := &MockGitHubClient{LatestRelease: &latest, AllReleases: releases}
+
+	calendar, err := policy.ParseCalendar("every monday")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pol := policy.NewDaysPolicy(12, 30)
+	pol.Calendar = calendar
+
+	checker := NewCheckerWithPolicy(client, Config{NoCache: true}, pol)
+	analysis, err := checker.Analyse(context.Background(), "1.0.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want, _ := calendar.LastBefore(*analysis.ExpiryDate())
+	if analysis.MaintenanceWindow == nil || !analysis.MaintenanceWindow.Equal(want) {
+		t.Fatalf("MaintenanceWindow = %v, want %s", analysis.MaintenanceWindow, want.Format(time.DateOnly))
+	}
+	if !strings.Contains(analysis.Message, "must be included in the "+want.Format(time.DateOnly)+" window") {
+		t.Errorf("Message = %q", analysis.Message)
+	}
+
+	// Expired versions have no window left
+	pol.MaxDays = 10
+	analysis, err = checker.Analyse(context.Background(), "1.0.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if analysis.MaintenanceWindow != nil {
+		t.Errorf("expected no window once expired, got %s", analysis.MaintenanceWindow)
+	}
+}
+
+func TestAnalyse_SupportSchedule(t *testing.T) {
+	latest := newTestRelease("24.1.0", 5)
+	releases := []types.Release{latest, newTestRelease("22.3.0", 30), newTestRelease("20.9.0", 60), newTestRelease("18.5.0", 400)}
+	client := &MockGitHubClient{LatestRelease: &latest, AllReleases: releases}
+
+	day := func(days int) time.Time { return time.Now().AddDate(0, 0, days).Truncate(24 * time.Hour) }
+	pol := policy.NewLTSPolicy(policy.LTSSchedule{
+		18: {Start: day(-900), LTS: day(-700), Maintenance: day(-400), End: day(-10)},
+		20: {Start: day(-600), LTS: day(-400), Maintenance: day(-100), End: day(30)},
+		22: {Start: day(-300), LTS: day(-100), Maintenance: day(200), End: day(500)},
+		24: {Start: day(-5), End: day(900)},
+	}, 90)
+	checker := NewCheckerWithPolicy(client, Config{NoCache: true}, pol)
+
+	tests := []struct {
+		version     string
+		wantStatus  Status
+		wantPhase   string
+		wantMessage string
+	}{
+		{"18.5.0", StatusExpired, policy.PhaseEndOfLife, "18.x reached end of life on " + day(-10).Format(time.DateOnly)},
+		{"20.9.0", StatusCritical, policy.PhaseMaintenance, "20.x reaches end of life in"},
+		{"22.3.0", StatusWarning, policy.PhaseActive, "Warning: 1 release behind"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			analysis, err := checker.Analyse(context.Background(), tt.version)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if analysis.Status() != tt.wantStatus || analysis.SupportPhase != tt.wantPhase || analysis.PolicyType != "lts" {
+				t.Errorf("status %s, phase %s, policy %s; want %s, %s, lts", analysis.Status(), analysis.SupportPhase, analysis.PolicyType, tt.wantStatus, tt.wantPhase)
+			}
+			if analysis.EndOfLife == nil || !analysis.ExpiryDate().Equal(*analysis.EndOfLife) {
+				t.Errorf("ExpiryDate() = %v, want the end of life %v", analysis.ExpiryDate(), analysis.EndOfLife)
+			}
+			if !strings.Contains(analysis.Message, tt.wantMessage) {
+				t.Errorf("Message = %q, want containing %q", analysis.Message, tt.wantMessage)
+			}
+			for _, r := range analysis.RecentReleases {
+				if r.ExpiresAt == nil {
+					t.Errorf("timeline release %s has no end of life", r.Version)
+				}
+			}
+		})
 	}
 }
@@ -0,0 +1,14 @@
+// Package checker analyses how far a version is behind the latest release of a
+// GitHub repository, and whether it has expired under a policy.
+//
+// # Stability
+//
+// This package is part of the stable v1 API, together with pkg/types and
+// pkg/policy. Within v1, exported identifiers are not removed, renamed or
+// moved to another package, and function signatures and struct field types do
+// not change. New struct fields, functions and constants may be added, so use
+// keyed struct literals. The GitHubClient and TruncationReporter interfaces
+// never gain methods; new client capabilities are detected through additional
+// optional interfaces instead. Every change is checked with apidiff (see
+// scripts/apidiff.sh).
+package checker
@@ -0,0 +1,95 @@
+package checker
+
+import (
+	"context"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/nickromney-org/github-release-version-checker/pkg/policy"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+// FetchKind names the GitHubClient call behind a fetch event
+type FetchKind string
+
+const (
+	FetchLatest FetchKind = "latest" // GetLatestRelease
+	FetchAll    FetchKind = "all"    // GetAllReleases
+	FetchRecent FetchKind = "recent" // GetRecentReleases
+)
+
+// FetchEvent describes a release fetch. Releases, Duration and Err are only set
+// when the fetch has ended.
+type FetchEvent struct {
+	Kind     FetchKind
+	Releases int
+	Duration time.Duration
+	Err      error
+}
+
+// CacheDecision is how the embedded release cache was used
+type CacheDecision string
+
+const (
+	CacheBypassed CacheDecision = "bypassed" // Config.NoCache; every release fetched
+	CacheCurrent  CacheDecision = "current"  // Embedded releases merged with recent ones
+	CacheStale    CacheDecision = "stale"    // Embedded releases too old; every release fetched
+	CacheOffline  CacheDecision = "offline"  // Config.Offline; cached releases used as they are
+)
+
+// CacheEvent describes a decision about the embedded release cache
+type CacheEvent struct {
+	Decision CacheDecision
+	Reason   string // Why the cache was stale or bypassed
+}
+
+// PolicyEvent describes the expiry policy's verdict on a comparison version
+type PolicyEvent struct {
+	PolicyType     string // "days" or "versions"
+	Comparison     *semver.Version
+	Latest         *semver.Version
+	ReleasesBehind int
+	Result         policy.PolicyResult
+}
+
+// Hooks are called as a Checker works, so embedding applications can record
+// metrics and logs. Any hook may be nil. Hooks run synchronously on the
+// analysing goroutine and should return quickly.
+type Hooks struct {
+	OnFetchStart      func(ctx context.Context, event FetchEvent)
+	OnFetchEnd        func(ctx context.Context, event FetchEvent)
+	OnCacheDecision   func(ctx context.Context, event CacheEvent)
+	OnPolicyEvaluated func(ctx context.Context, event PolicyEvent)
+}
+
+// SetHooks sets the hooks called during analysis; the zero Hooks disables them
+func (c *Checker) SetHooks(hooks Hooks) {
+	c.hooks = hooks
+}
+
+// fetchReleases calls fetch, reporting it to the fetch hooks
+func (c *Checker) fetchReleases(ctx context.Context, kind FetchKind, fetch func() ([]types.Release, error)) ([]types.Release, error) {
+	if c.hooks.OnFetchStart != nil {
+		c.hooks.OnFetchStart(ctx, FetchEvent{Kind: kind})
+	}
+	start := time.Now()
+	releases, err := fetch()
+	if c.hooks.OnFetchEnd != nil {
+		c.hooks.OnFetchEnd(ctx, FetchEvent{Kind: kind, Releases: len(releases), Duration: time.Since(start), Err: err})
+	}
+	return releases, err
+}
+
+// cacheDecided reports a decision about the embedded cache to its hook
+func (c *Checker) cacheDecided(ctx context.Context, decision CacheDecision, reason string) {
+	if c.hooks.OnCacheDecision != nil {
+		c.hooks.OnCacheDecision(ctx, CacheEvent{Decision: decision, Reason: reason})
+	}
+}
+
+// policyEvaluated reports a policy verdict to its hook
+func (c *Checker) policyEvaluated(ctx context.Context, event PolicyEvent) {
+	if c.hooks.OnPolicyEvaluated != nil {
+		c.hooks.OnPolicyEvaluated(ctx, event)
+	}
+}
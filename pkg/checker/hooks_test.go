@@ -0,0 +1,102 @@
+package checker
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/nickromney-org/github-release-version-checker/pkg/policy"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+// recordHooks returns hooks that append a line per event to events
+func recordHooks(events *[]string) Hooks {
+	return Hooks{
+		OnFetchStart: func(ctx context.Context, e FetchEvent) {
+			*events = append(*events, "start "+string(e.Kind))
+		},
+		OnFetchEnd: func(ctx context.Context, e FetchEvent) {
+			*events = append(*events, "end "+string(e.Kind))
+		},
+		OnCacheDecision: func(ctx context.Context, e CacheEvent) {
+			*events = append(*events, "cache "+string(e.Decision))
+		},
+		OnPolicyEvaluated: func(ctx context.Context, e PolicyEvent) {
+			status := "ok"
+			switch {
+			case e.Result.IsExpired:
+				status = "expired"
+			case e.Result.IsCritical:
+				status = "critical"
+			}
+			*events = append(*events, "policy "+e.PolicyType+" "+e.Comparison.String()+" "+status)
+		},
+	}
+}
+
+func TestAnalyse_Hooks(t *testing.T) {
+	releases := []types.Release{
+		newTestRelease("1.31.0", 3),
+		newTestRelease("1.30.0", 40),
+	}
+
+	tests := []struct {
+		name    string
+		config  Config
+		policy  policy.VersionPolicy
+		version string
+		want    string
+	}{
+		{
+			name:    "no cache with days policy",
+			config:  Config{NoCache: true},
+			policy:  policy.NewDaysPolicy(12, 30),
+			version: "1.30.0",
+			want:    "cache bypassed, start all, end all, start latest, end latest, policy days 1.30.0 ok",
+		},
+		{
+			name:    "stale embedded cache without a policy",
+			config:  Config{CriticalAgeDays: 1, MaxAgeDays: 2},
+			version: "1.30.0",
+			want:    "start recent, end recent, cache stale, start all, end all, start latest, end latest, policy days 1.30.0 expired",
+		},
+		{
+			name:    "latest only evaluates no policy",
+			config:  Config{NoCache: true},
+			version: "",
+			want:    "cache bypassed, start all, end all, start latest, end latest",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var events []string
+			checker := NewCheckerWithPolicy(&MockGitHubClient{AllReleases: releases}, tt.config, tt.policy)
+			checker.SetHooks(recordHooks(&events))
+
+			if _, err := checker.Analyse(context.Background(), tt.version); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := strings.Join(events, ", "); got != tt.want {
+				t.Errorf("events:\n  %s\nwant:\n  %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyse_FetchEndReportsResult(t *testing.T) {
+	var ends []FetchEvent
+	checker := NewChecker(&MockGitHubClient{AllReleases: []types.Release{newTestRelease("1.0.0", 1)}}, Config{NoCache: true})
+	checker.SetHooks(Hooks{OnFetchEnd: func(ctx context.Context, e FetchEvent) { ends = append(ends, e) }})
+
+	if _, err := checker.Analyse(context.Background(), ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ends) != 2 || ends[0].Kind != FetchAll || ends[0].Releases != 1 || ends[0].Err != nil {
+		t.Fatalf("fetch end events = %+v", ends)
+	}
+	// The mock marks no release as latest
+	if ends[1].Kind != FetchLatest || ends[1].Releases != 0 {
+		t.Errorf("latest fetch event = %+v", ends[1])
+	}
+}
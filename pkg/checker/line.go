@@ -0,0 +1,65 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/nickromney-org/github-release-version-checker/pkg/releaseset"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+// LineNotFoundError is returned when a minor version line has no releases
+type LineNotFoundError struct {
+	Major, Minor uint64
+	Latest       *semver.Version
+}
+
+func (e *LineNotFoundError) Error() string {
+	return fmt.Sprintf("no releases on line %d.%d (latest: %s)", e.Major, e.Minor, types.FormatVersion(e.Latest))
+}
+
+// Line returns the releases on the minor version line of version, e.g. every
+// 1.29.x for "1.29" or "1.29.3", highest version first. Releases are loaded as
+// for Analyse, so the embedded cache is used when current.
+func (c *Checker) Line(ctx context.Context, version string) ([]types.Release, error) {
+	v, err := ParseComparisonVersion(version, c.config.Normalisation)
+	if err != nil {
+		return nil, err
+	}
+	allReleases, merged, err := c.loadReleases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !c.keepsPrereleases() {
+		allReleases = releaseset.WithoutPrereleases(allReleases)
+	}
+
+	line := releaseset.Line(allReleases, v.Major(), v.Minor())
+	// Caches can be trimmed to recent releases, so look for an older line in the full list
+	lineStart := semver.New(v.Major(), v.Minor(), 0, "", "")
+	if len(line) == 0 && merged && olderThanAll(allReleases, lineStart) {
+		if allReleases, err = c.fetchReleases(ctx, FetchAll, func() ([]types.Release, error) { return c.client.GetAllReleases(ctx) }); err != nil {
+			return nil, fmt.Errorf("failed to fetch all releases: %w", err)
+		}
+		line = releaseset.Line(allReleases, v.Major(), v.Minor())
+	}
+	if len(line) == 0 {
+		notFound := &LineNotFoundError{Major: v.Major(), Minor: v.Minor()}
+		if latest := releaseset.Latest(allReleases); latest != nil {
+			notFound.Latest = latest.Version
+		}
+		return nil, notFound
+	}
+	return line, nil
+}
+
+// LatestPatch returns the newest release on the minor version line of version,
+// answering "what is the newest patch on my line?"
+func (c *Checker) LatestPatch(ctx context.Context, version string) (*types.Release, error) {
+	line, err := c.Line(ctx, version)
+	if err != nil {
+		return nil, err
+	}
+	return &line[0], nil
+}
@@ -0,0 +1,68 @@
+package checker
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+func TestLine(t *testing.T) {
+	all := []types.Release{
+		newTestRelease("1.30.1", 5),
+		newTestRelease("1.29.4", 10),
+		newTestRelease("1.30.0", 20),
+		newTestRelease("1.28.9", 30),
+		newTestRelease("1.29.0", 120),
+		newTestRelease("1.27.2", 200),
+		newTestRelease("1.27.1", 220),
+	}
+	trimmed := all[:5] // As kept by a cache with a retention limit
+
+	tests := []struct {
+		name    string
+		version string
+		want    string
+		wantErr bool
+	}{
+		{"line", "1.29", "1.29.4 1.29.0", false},
+		{"version on the line", "v1.29.0", "1.29.4 1.29.0", false},
+		{"line trimmed from cache", "1.27", "1.27.2 1.27.1", false},
+		{"missing line", "1.31", "", true},
+		{"invalid version", "one.two", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := NewChecker(&MockGitHubClient{AllReleases: all}, Config{CriticalAgeDays: 12, MaxAgeDays: 30, CachedReleases: trimmed})
+			line, err := checker.Line(context.Background(), tt.version)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Line() = %v, want an error", line)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got := make([]string, len(line))
+			for i, r := range line {
+				got[i] = r.Version.String()
+			}
+			if strings.Join(got, " ") != tt.want {
+				t.Errorf("Line() = %s, want %s", strings.Join(got, " "), tt.want)
+			}
+		})
+	}
+
+	checker := NewChecker(&MockGitHubClient{AllReleases: all}, Config{CriticalAgeDays: 12, MaxAgeDays: 30, NoCache: true})
+	if patch, err := checker.LatestPatch(context.Background(), "1.30.0"); err != nil || patch.Version.String() != "1.30.1" {
+		t.Errorf("LatestPatch() = %v, %v; want 1.30.1", patch, err)
+	}
+	var notFound *LineNotFoundError
+	if _, err := checker.LatestPatch(context.Background(), "2.0"); !errors.As(err, &notFound) || notFound.Latest.String() != "1.30.1" {
+		t.Errorf("LatestPatch() error = %v, want LineNotFoundError", err)
+	}
+}
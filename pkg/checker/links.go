@@ -0,0 +1,63 @@
+package checker
+
+import (
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+// Links point at the release pages an analysis refers to, and at the changes
+// between them, for tickets and summaries
+type Links struct {
+	Comparison  string `json:"comparison,omitempty"`  // Release page of the comparison version
+	Recommended string `json:"recommended,omitempty"` // Release page of the recommended version
+	Compare     string `json:"compare,omitempty"`     // Changes from the comparison to the recommended version
+}
+
+// releaseTagPath separates a repository's web URL from the tag in release page URLs
+const releaseTagPath = "/releases/tag/"
+
+// releaseLinks returns links to the comparison and recommended releases among
+// releases, or nil when neither has a page
+func releaseLinks(releases []types.Release, comparison, recommended *semver.Version) *Links {
+	links := Links{
+		Comparison:  releaseURL(releases, comparison),
+		Recommended: releaseURL(releases, recommended),
+	}
+	if comparison != nil && recommended != nil && types.CompareVersions(recommended, comparison) > 0 {
+		links.Compare = compareURL(links.Comparison, links.Recommended)
+	}
+	if links == (Links{}) {
+		return nil
+	}
+	return &links
+}
+
+// releaseURL returns the page of version's release, or "" if it has none
+func releaseURL(releases []types.Release, version *semver.Version) string {
+	if version == nil {
+		return ""
+	}
+	for _, r := range releases {
+		if types.CompareVersions(r.Version, version) == 0 {
+			return r.URL
+		}
+	}
+	return ""
+}
+
+// compareURL returns the URL of the changes between two releases of the same
+// repository from their pages, e.g. .../compare/v2.327.1...v2.329.0, or "" if
+// either is not a release page
+func compareURL(from, to string) string {
+	fromRepo, fromTag, ok := strings.Cut(from, releaseTagPath)
+	if !ok || fromTag == "" {
+		return ""
+	}
+	toRepo, toTag, ok := strings.Cut(to, releaseTagPath)
+	if !ok || toTag == "" || toRepo != fromRepo {
+		return ""
+	}
+	return fromRepo + "/compare/" + fromTag + "..." + toTag
+}
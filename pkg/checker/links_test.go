@@ -0,0 +1,88 @@
+package checker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+func TestCompareURL(t *testing.T) {
+	tests := []struct {
+		name, from, to, want string
+	}{
+		{
+			name: "release pages",
+			from: "https://github.com/actions/runner/releases/tag/v2.327.1",
+			to:   "https://github.com/actions/runner/releases/tag/v2.329.0",
+			want: "https://github.com/actions/runner/compare/v2.327.1...v2.329.0",
+		},
+		{
+			name: "enterprise server",
+			from: "https://github.example.com/tools/runner/releases/tag/1.0.0",
+			to:   "https://github.example.com/tools/runner/releases/tag/1.1.0",
+			want: "https://github.example.com/tools/runner/compare/1.0.0...1.1.0",
+		},
+		{name: "not release pages", from: "https://example.com", to: "https://example.com"},
+		{
+			name: "different repositories",
+			from: "https://github.com/mycorp/runner/releases/tag/v2.327.1",
+			to:   "https://github.com/actions/runner/releases/tag/v2.329.0",
+		},
+		{name: "missing page", to: "https://github.com/actions/runner/releases/tag/v2.329.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := compareURL(tt.from, tt.to); got != tt.want {
+				t.Errorf("compareURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyse_Links(t *testing.T) {
+	page := func(r types.Release) types.Release {
+		r.URL = "https://github.com/actions/runner/releases/tag/v" + r.Version.String()
+		return r
+	}
+	latest := page(newTestRelease("2.329.0", 3))
+	releases := []types.Release{latest, page(newTestRelease("2.328.0", 20)), page(newTestRelease("2.327.1", 40))}
+	client := &MockGitHubClient{LatestRelease: &latest, AllReleases: releases}
+
+	tests := []struct {
+		name    string
+		version string
+		want    Links
+	}{
+		{
+			name:    "behind",
+			version: "2.327.1",
+			want: Links{
+				Comparison:  "https://github.com/actions/runner/releases/tag/v2.327.1",
+				Recommended: "https://github.com/actions/runner/releases/tag/v2.329.0",
+				Compare:     "https://github.com/actions/runner/compare/v2.327.1...v2.329.0",
+			},
+		},
+		{
+			name:    "up to date",
+			version: "2.329.0",
+			want: Links{
+				Comparison:  "https://github.com/actions/runner/releases/tag/v2.329.0",
+				Recommended: "https://github.com/actions/runner/releases/tag/v2.329.0",
+			},
+		},
+		{name: "latest only", want: Links{Recommended: "https://github.com/actions/runner/releases/tag/v2.329.0"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := NewChecker(client, Config{CriticalAgeDays: 12, MaxAgeDays: 30, NoCache: true})
+			analysis, err := checker.Analyse(context.Background(), tt.version)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if analysis.Links == nil || *analysis.Links != tt.want {
+				t.Errorf("Links = %+v, want %+v", analysis.Links, tt.want)
+			}
+		})
+	}
+}
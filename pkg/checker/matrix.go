@@ -0,0 +1,79 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/nickromney-org/github-release-version-checker/pkg/releaseset"
+)
+
+// MinorLine is one minor version line of a support matrix, e.g. 1.29.x
+type MinorLine struct {
+	Line                  string          `json:"line"` // e.g. "1.29"
+	FirstRelease          *semver.Version `json:"first_release"`
+	FirstReleasedAt       time.Time       `json:"first_released_at"`
+	LatestPatch           *semver.Version `json:"latest_patch"`
+	LatestPatchReleasedAt time.Time       `json:"latest_patch_released_at"`
+	Releases              int             `json:"releases"`
+	AgeDays               int             `json:"age_days"` // Since the line's first release
+
+	// Status of the latest patch under the checker's policy, and when it
+	// expires under a days-based policy
+	Status    Status     `json:"status"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// SupportMatrix is the support status of a repository's minor lines, newest first
+type SupportMatrix struct {
+	LatestVersion *semver.Version `json:"latest_version"`
+	PolicyType    string          `json:"policy_type,omitempty"`
+	Lines         []MinorLine     `json:"lines"`
+}
+
+// Matrix builds the support matrix of the newest lines minor lines (0 for all)
+// of stable releases, checking each line's latest patch against the policy
+func (c *Checker) Matrix(ctx context.Context, lines int) (*SupportMatrix, error) {
+	if err := c.config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	allReleases, _, err := c.loadReleases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	stable := releaseset.FilterStable(allReleases)
+	if len(stable) == 0 {
+		return nil, fmt.Errorf("no stable releases available")
+	}
+	latest := *releaseset.Latest(stable)
+
+	matrix := &SupportMatrix{LatestVersion: latest.Version, PolicyType: "days"}
+	if c.policy != nil {
+		matrix.PolicyType = c.policy.Type()
+	}
+
+	now := time.Now()
+	for _, group := range releaseset.GroupByMinor(stable) {
+		if lines > 0 && len(matrix.Lines) == lines {
+			break
+		}
+		first, last := group[len(group)-1], group[0]
+		analysis, err := c.analyse(ctx, last.Version, stable, latest, stable, latest.Version, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		matrix.Lines = append(matrix.Lines, MinorLine{
+			Line:                  fmt.Sprintf("%d.%d", last.Version.Major(), last.Version.Minor()),
+			FirstRelease:          first.Version,
+			FirstReleasedAt:       first.PublishedAt,
+			LatestPatch:           last.Version,
+			LatestPatchReleasedAt: last.PublishedAt,
+			Releases:              len(group),
+			AgeDays:               daysBetween(first.PublishedAt, now),
+			Status:                analysis.Status(),
+			ExpiresAt:             analysis.ExpiryDate(),
+		})
+	}
+	return matrix, nil
+}
@@ -0,0 +1,84 @@
+package checker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nickromney-org/github-release-version-checker/pkg/policy"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+func TestMatrix(t *testing.T) {
+	releases := []types.Release{
+		newTestRelease("1.31.0-rc.1", 1),
+		newTestRelease("1.30.1", 5),
+		newTestRelease("1.30.0", 20),
+		newTestRelease("1.29.4", 10),
+		newTestRelease("1.29.0", 120),
+		newTestRelease("1.28.9", 30),
+		newTestRelease("1.27.2", 200),
+	}
+
+	tests := []struct {
+		name       string
+		policy     policy.VersionPolicy
+		lines      int
+		wantLines  []string
+		wantStatus []Status
+	}{
+		{
+			name:       "versions policy",
+			policy:     policy.NewVersionsPolicy(2),
+			wantLines:  []string{"1.30", "1.29", "1.28", "1.27"},
+			wantStatus: []Status{StatusCurrent, StatusWarning, StatusCritical, StatusExpired},
+		},
+		{
+			name:       "limited to the newest lines",
+			policy:     policy.NewVersionsPolicy(2),
+			lines:      2,
+			wantLines:  []string{"1.30", "1.29"},
+			wantStatus: []Status{StatusCurrent, StatusWarning},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := NewCheckerWithPolicy(&MockGitHubClient{AllReleases: releases}, Config{NoCache: true}, tt.policy)
+			matrix, err := checker.Matrix(context.Background(), tt.lines)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if matrix.LatestVersion.String() != "1.30.1" || matrix.PolicyType != "versions" {
+				t.Errorf("LatestVersion, PolicyType = %s, %s; want 1.30.1, versions", matrix.LatestVersion, matrix.PolicyType)
+			}
+			if len(matrix.Lines) != len(tt.wantLines) {
+				t.Fatalf("got %d lines, want %d", len(matrix.Lines), len(tt.wantLines))
+			}
+			for i, line := range matrix.Lines {
+				if line.Line != tt.wantLines[i] || line.Status != tt.wantStatus[i] {
+					t.Errorf("line %d = %s %s, want %s %s", i, line.Line, line.Status, tt.wantLines[i], tt.wantStatus[i])
+				}
+			}
+		})
+	}
+}
+
+func TestMatrix_LineDetails(t *testing.T) {
+	releases := []types.Release{
+		newTestRelease("2.329.0", 5),
+		newTestRelease("2.328.1", 40),
+		newTestRelease("2.328.0", 60),
+	}
+	checker := NewChecker(&MockGitHubClient{AllReleases: releases}, Config{CriticalAgeDays: 12, MaxAgeDays: 30, NoCache: true})
+	matrix, err := checker.Matrix(context.Background(), 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	line := matrix.Lines[1]
+	if line.FirstRelease.String() != "2.328.0" || line.LatestPatch.String() != "2.328.1" || line.Releases != 2 || line.AgeDays != 60 {
+		t.Errorf("line = %+v", line)
+	}
+	if line.ExpiresAt == nil || matrix.Lines[0].ExpiresAt != nil {
+		t.Errorf("ExpiresAt = %v, %v; want only the older line to expire", matrix.Lines[0].ExpiresAt, line.ExpiresAt)
+	}
+}
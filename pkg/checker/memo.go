@@ -0,0 +1,156 @@
+package checker
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+// AnalysisCache stores analyses by key, so identical checks are not recomputed.
+// Keys cover the repository, comparison version, settings, release data and the
+// current UTC date, so a new release or a new day never reuses a stale result.
+type AnalysisCache interface {
+	Get(key string) (*Analysis, bool)
+	Put(key string, analysis *Analysis) error
+}
+
+// MemoryAnalysisCache keeps analyses for the life of the process; it is safe for
+// concurrent use
+type MemoryAnalysisCache struct {
+	mu       sync.Mutex
+	analyses map[string]Analysis
+	notFound map[string]notFoundEntry
+}
+
+// NewMemoryAnalysisCache creates an empty in-process analysis cache
+func NewMemoryAnalysisCache() *MemoryAnalysisCache {
+	return &MemoryAnalysisCache{analyses: make(map[string]Analysis), notFound: make(map[string]notFoundEntry)}
+}
+
+// Get returns a copy of the analysis stored under key
+func (m *MemoryAnalysisCache) Get(key string) (*Analysis, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	analysis, ok := m.analyses[key]
+	if !ok {
+		return nil, false
+	}
+	return &analysis, true
+}
+
+// Put stores a copy of analysis under key
+func (m *MemoryAnalysisCache) Put(key string, analysis *Analysis) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.analyses[key] = *analysis
+	return nil
+}
+
+// FileAnalysisCache stores analyses as JSON files in a directory, sharing them
+// between runs. Entries are never updated in place, so stale files can be
+// deleted at any time.
+type FileAnalysisCache struct {
+	Dir string
+}
+
+// NewFileAnalysisCache creates an analysis cache in dir, which is created on first write
+func NewFileAnalysisCache(dir string) *FileAnalysisCache {
+	return &FileAnalysisCache{Dir: dir}
+}
+
+// storedAnalysis is an Analysis without its custom JSON encoding, so it reads
+// back exactly as written
+type storedAnalysis Analysis
+
+// Get reads the analysis stored under key; unreadable entries are misses
+func (f *FileAnalysisCache) Get(key string) (*Analysis, bool) {
+	data, err := os.ReadFile(f.path(key))
+	if err != nil {
+		return nil, false
+	}
+	var stored storedAnalysis
+	if err := json.Unmarshal(data, &stored); err != nil || stored.LatestVersion == nil {
+		return nil, false
+	}
+	analysis := Analysis(stored)
+	return &analysis, true
+}
+
+// Put writes analysis under key, replacing the file atomically
+func (f *FileAnalysisCache) Put(key string, analysis *Analysis) error {
+	data, err := json.Marshal((*storedAnalysis)(analysis))
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(f.Dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create analysis cache directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(f.Dir, key+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write analysis cache: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write analysis cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write analysis cache: %w", err)
+	}
+	return os.Rename(tmp.Name(), f.path(key))
+}
+
+// Prune deletes entries written before cutoff, returning how many were removed
+func (f *FileAnalysisCache) Prune(cutoff time.Time) (int, error) {
+	entries, err := os.ReadDir(f.Dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	removed := 0
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || entry.IsDir() || filepath.Ext(entry.Name()) != ".json" || !info.ModTime().Before(cutoff) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(f.Dir, entry.Name())); err == nil {
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+func (f *FileAnalysisCache) path(key string) string {
+	return filepath.Join(f.Dir, key+".json")
+}
+
+// analysisKey identifies an analysis by everything it depends on: repository,
+// comparison version, settings, policy, release data and today's date
+func (c *Checker) analysisKey(comparisonVersion *semver.Version, releases []types.Release, markedLatest *semver.Version, degraded []DegradedReason) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "repo=%s\nversion=%s\nmarked=%s\ndate=%s\n",
+		c.repository, versionString(comparisonVersion), versionString(markedLatest), time.Now().UTC().Format(time.DateOnly))
+	config := c.config
+	config.CachedReleases = nil // Covered by the release data below
+	config.NoteKeywords = nil   // By pattern below, as the pointers differ between runs
+	fmt.Fprintf(h, "config=%+v\npolicy=%T%+v\ndegraded=%v\n", config, c.policy, c.policy, degraded)
+	for _, k := range c.config.NoteKeywords {
+		fmt.Fprintf(h, "note=%s:%s:%s\n", k.Name, k.Pattern, k.Escalate)
+	}
+	for _, r := range releases {
+		fmt.Fprintf(h, "%s@%d\n", r.Version, r.PublishedAt.Unix())
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,105 @@
+package checker
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+// countingCache counts hits on a wrapped analysis cache
+type countingCache struct {
+	AnalysisCache
+	hits int
+}
+
+func (c *countingCache) Get(key string) (*Analysis, bool) {
+	analysis, ok := c.AnalysisCache.Get(key)
+	if ok {
+		c.hits++
+	}
+	return analysis, ok
+}
+
+func TestAnalyse_AnalysisCache(t *testing.T) {
+	releases := []types.Release{
+		newTestRelease("2.329.0", 5),
+		newTestRelease("2.328.0", 40),
+	}
+	cache := &countingCache{AnalysisCache: NewMemoryAnalysisCache()}
+	analyse := func(repo string, releases []types.Release, version string) *Analysis {
+		t.Helper()
+		checker := NewChecker(&MockGitHubClient{AllReleases: releases}, Config{CriticalAgeDays: 12, MaxAgeDays: 30, NoCache: true})
+		checker.SetAnalysisCache(cache, repo)
+		analysis, err := checker.Analyse(context.Background(), version)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return analysis
+	}
+
+	first := analyse("actions/runner", releases, "2.328.0")
+	first.Repository = "changed by the caller"
+	second := analyse("actions/runner", releases, "2.328.0")
+	if cache.hits != 1 {
+		t.Fatalf("hits = %d after repeating a check, want 1", cache.hits)
+	}
+	if second.Repository != "" || second.ReleasesBehind != first.ReleasesBehind {
+		t.Errorf("cached analysis = %+v, want an unmodified copy", second)
+	}
+
+	analyse("actions/runner", releases, "2.329.0")
+	analyse("other/runner", releases, "2.328.0")
+	analyse("actions/runner", append([]types.Release{newTestRelease("2.330.0", 1)}, releases...), "2.328.0")
+	if cache.hits != 1 {
+		t.Errorf("hits = %d, want no hits for a new version, repository or release", cache.hits)
+	}
+}
+
+func TestFileAnalysisCache(t *testing.T) {
+	dir := t.TempDir()
+	releases := []types.Release{
+		newTestRelease("2.329.0", 5),
+		newTestRelease("2.328.0", 40),
+		newTestRelease("2.327.1", 70),
+	}
+	analyse := func() (*Analysis, int) {
+		t.Helper()
+		cache := &countingCache{AnalysisCache: NewFileAnalysisCache(dir)}
+		checker := NewChecker(&MockGitHubClient{AllReleases: releases}, Config{CriticalAgeDays: 12, MaxAgeDays: 30, NoCache: true})
+		checker.SetAnalysisCache(cache, "actions/runner")
+		analysis, err := checker.Analyse(context.Background(), "2.327.1")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return analysis, cache.hits
+	}
+
+	want, _ := analyse()
+	got, hits := analyse()
+	if hits != 1 {
+		t.Fatalf("hits = %d on a second run, want 1", hits)
+	}
+	if !got.ComparisonVersion.Equal(want.ComparisonVersion) || !got.LatestVersion.Equal(want.LatestVersion) ||
+		got.Status() != want.Status() || got.ReleasesBehind != want.ReleasesBehind ||
+		len(got.NewerReleases) != len(want.NewerReleases) || len(got.RecentReleases) != len(want.RecentReleases) ||
+		!got.FirstNewerReleaseDate.Equal(*want.FirstNewerReleaseDate) {
+		t.Errorf("read back %+v, want %+v", got, want)
+	}
+
+	entries, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("cache files = %v (%v), want 1", entries, err)
+	}
+	old := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(entries[0], old, old); err != nil {
+		t.Fatal(err)
+	}
+	removed, err := NewFileAnalysisCache(dir).Prune(time.Now().Add(-24 * time.Hour))
+	if err != nil || removed != 1 {
+		t.Errorf("Prune removed %d (%v), want 1", removed, err)
+	}
+}
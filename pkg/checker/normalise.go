@@ -0,0 +1,95 @@
+package checker
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+// Normalisation selects the clean-ups applied to the comparison version before
+// it is parsed. The zero value applies none.
+type Normalisation uint8
+
+const (
+	NormaliseTrimSpace  Normalisation = 1 << iota // Trim surrounding whitespace
+	NormaliseUppercaseV                           // Accept "V1.2.3" as well as "v1.2.3"
+	NormaliseStripBuild                           // Drop "+build" metadata, keeping a "+rev.N" revision
+
+	NormaliseAll = NormaliseTrimSpace | NormaliseUppercaseV | NormaliseStripBuild
+)
+
+// InvalidVersionError is returned when the comparison version cannot be parsed,
+// describing the normalisation applied first
+type InvalidVersionError struct {
+	Input      string   // As given
+	Normalised string   // After normalisation
+	Applied    []string // Normalisation steps that changed the input
+	Err        error
+}
+
+func (e *InvalidVersionError) Error() string {
+	if len(e.Applied) == 0 {
+		return fmt.Sprintf("invalid comparison version %q: %v", e.Input, e.Err)
+	}
+	return fmt.Sprintf("invalid comparison version %q (after %s: %q): %v",
+		e.Input, strings.Join(e.Applied, ", "), e.Normalised, e.Err)
+}
+
+func (e *InvalidVersionError) Unwrap() error {
+	return e.Err
+}
+
+// NormaliseVersion applies the selected clean-ups, returning the result and a
+// description of each step that changed the input
+func NormaliseVersion(input string, n Normalisation) (string, []string) {
+	v := input
+	var applied []string
+
+	if n&NormaliseTrimSpace != 0 {
+		if trimmed := strings.TrimSpace(v); trimmed != v {
+			v = trimmed
+			applied = append(applied, "trimming whitespace")
+		}
+	}
+	if n&NormaliseUppercaseV != 0 && strings.HasPrefix(v, "V") {
+		v = "v" + v[1:]
+		applied = append(applied, "lower-casing the V prefix")
+	}
+	if n&NormaliseStripBuild != 0 {
+		if i := strings.IndexByte(v, '+'); i != -1 {
+			if stripped := v[:i] + revisionMetadata(v[i+1:]); stripped != v {
+				v = stripped
+				applied = append(applied, "stripping build metadata")
+			}
+		}
+	}
+	return v, applied
+}
+
+// revisionMetadata returns "+rev.N" if metadata starts with the revision that
+// types.ParseVersion records for a fourth component, so that stripping build
+// metadata does not turn 1.2.3.4 into 1.2.3; otherwise ""
+func revisionMetadata(metadata string) string {
+	rest, ok := strings.CutPrefix(metadata, "rev.")
+	if !ok {
+		return ""
+	}
+	digits, _, _ := strings.Cut(rest, ".")
+	if _, err := strconv.ParseUint(digits, 10, 64); err != nil {
+		return ""
+	}
+	return "+rev." + digits
+}
+
+// ParseComparisonVersion normalises and parses a comparison version
+func ParseComparisonVersion(input string, n Normalisation) (*semver.Version, error) {
+	normalised, applied := NormaliseVersion(input, n)
+	version, err := types.ParseVersion(normalised)
+	if err != nil {
+		return nil, &InvalidVersionError{Input: input, Normalised: normalised, Applied: applied, Err: err}
+	}
+	return version, nil
+}
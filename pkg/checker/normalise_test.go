@@ -0,0 +1,76 @@
+package checker
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+func TestParseComparisonVersion(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		normalisation Normalisation
+		want          string
+		wantErr       string
+	}{
+		{name: "plain", input: "2.328.0", want: "2.328.0"},
+		{name: "lower-case v without normalisation", input: "v2.328.0", want: "2.328.0"},
+		{name: "whitespace", input: " 2.328.0\n", normalisation: NormaliseAll, want: "2.328.0"},
+		{name: "upper-case V", input: "V2.328.0", normalisation: NormaliseAll, want: "2.328.0"},
+		{name: "build metadata", input: "2.328.0+build.7", normalisation: NormaliseAll, want: "2.328.0"},
+		{name: "build metadata kept", input: "2.328.0+build.7", want: "2.328.0+build.7"},
+		{name: "revision kept", input: "1.2.3+rev.4", normalisation: NormaliseAll, want: "1.2.3+rev.4"},
+		{name: "revision kept, other metadata stripped", input: "1.2.3+rev.4.win64", normalisation: NormaliseAll, want: "1.2.3+rev.4"},
+		{name: "not a revision", input: "1.2.3+rev.x", normalisation: NormaliseAll, want: "1.2.3"},
+		{name: "four components", input: "1.2.3.4", normalisation: NormaliseAll, want: "1.2.3+rev.4"},
+		{name: "whitespace without normalisation", input: " 2.328.0", wantErr: `invalid comparison version " 2.328.0": `},
+		{name: "upper-case V without normalisation", input: "V2.328.0", normalisation: NormaliseTrimSpace, wantErr: `invalid comparison version "V2.328.0": `},
+		{name: "normalisation described", input: " V2.x.0+b ", normalisation: NormaliseAll, wantErr: `invalid comparison version " V2.x.0+b " (after trimming whitespace, lower-casing the V prefix, stripping build metadata: "v2.x.0"): `},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseComparisonVersion(tt.input, tt.normalisation)
+			if tt.wantErr != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want prefix %q", err, tt.wantErr)
+				}
+				if !errors.Is(err, semver.ErrInvalidSemVer) {
+					t.Errorf("error %v does not wrap ErrInvalidSemVer", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("version = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// Versions in JSON output, which use the semver form, must parse back unchanged
+func TestParseComparisonVersion_RoundTrip(t *testing.T) {
+	for _, input := range []string{"2.328.0", "1.2.3.4", "v10.0.19041.1", "1.2.3.4-beta.1"} {
+		t.Run(input, func(t *testing.T) {
+			v, err := ParseComparisonVersion(input, NormaliseAll)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, s := range []string{v.String(), types.FormatVersion(v)} {
+				got, err := ParseComparisonVersion(s, NormaliseAll)
+				if err != nil {
+					t.Fatalf("ParseComparisonVersion(%q): %v", s, err)
+				}
+				if !got.Equal(v) || types.Revision(got) != types.Revision(v) {
+					t.Errorf("ParseComparisonVersion(%q) = %s, want %s", s, got, v)
+				}
+			}
+		})
+	}
+}
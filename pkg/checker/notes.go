@@ -0,0 +1,83 @@
+package checker
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+// NoteKeyword flags newer releases whose notes match Pattern, e.g. security
+// fixes: a match raises the status to at least Escalate, when it is set
+type NoteKeyword struct {
+	Name     string         `json:"name"`
+	Pattern  *regexp.Regexp `json:"-"`
+	Escalate Status         `json:"escalate,omitempty"`
+}
+
+// NoteMatch is a newer release whose notes match a keyword
+type NoteMatch struct {
+	Keyword  string          `json:"keyword"`
+	Version  *semver.Version `json:"version"`
+	Escalate Status          `json:"escalate,omitempty"`
+}
+
+// validate checks the keyword has a pattern and a known escalation
+func (k NoteKeyword) validate() error {
+	if k.Pattern == nil {
+		return fmt.Errorf("note keyword %q has no pattern", k.Name)
+	}
+	switch k.Escalate {
+	case "", StatusWarning, StatusCritical, StatusExpired:
+		return nil
+	default:
+		return fmt.Errorf("note keyword %q: invalid escalation %q: must be %q, %q or %q",
+			k.Name, k.Escalate, StatusWarning, StatusCritical, StatusExpired)
+	}
+}
+
+// statusRank orders statuses from current (0) to expired (3)
+func statusRank(s Status) int {
+	switch s {
+	case StatusWarning:
+		return 1
+	case StatusCritical:
+		return 2
+	case StatusExpired:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// applyNoteKeywords records the newer releases whose notes match a keyword,
+// and the highest escalation they call for. Releases without notes, e.g. ones
+// only known from a cache, never match.
+func applyNoteKeywords(analysis *Analysis, keywords []NoteKeyword) {
+	for _, r := range analysis.NewerReleases {
+		if r.Notes == "" {
+			continue
+		}
+		for _, k := range keywords {
+			if k.Pattern == nil || !k.Pattern.MatchString(r.Notes) {
+				continue
+			}
+			analysis.NoteMatches = append(analysis.NoteMatches, NoteMatch{Keyword: k.Name, Version: r.Version, Escalate: k.Escalate})
+			if statusRank(k.Escalate) > statusRank(analysis.NoteEscalation) {
+				analysis.NoteEscalation = k.Escalate
+			}
+		}
+	}
+}
+
+// describeNoteMatches summarises matches for the analysis message, e.g.
+// "security in v2.330.0 release notes", or returns "" if there are none
+func describeNoteMatches(matches []NoteMatch) string {
+	var parts []string
+	for _, m := range matches {
+		parts = append(parts, fmt.Sprintf("%s in v%s release notes", m.Keyword, types.FormatVersion(m.Version)))
+	}
+	return strings.Join(parts, ", ")
+}
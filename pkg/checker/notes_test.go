@@ -0,0 +1,82 @@
+package checker
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+func TestAnalyse_NoteKeywords(t *testing.T) {
+	latest := newTestRelease("2.329.0", 2)
+	latest.Notes = "Fixes a SECURITY issue in job isolation"
+	feature := newTestRelease("2.328.0", 21)
+	feature.Notes = "Adds job summaries"
+	releases := []types.Release{latest, feature, newTestRelease("2.327.1", 60)}
+	client := &MockGitHubClient{LatestRelease: &latest, AllReleases: releases}
+	security := NoteKeyword{Name: "security", Pattern: regexp.MustCompile(`(?i)security`), Escalate: StatusCritical}
+	breaking := NoteKeyword{Name: "breaking", Pattern: regexp.MustCompile(`(?i)breaking`), Escalate: StatusExpired}
+	isolation := NoteKeyword{Name: "isolation", Pattern: regexp.MustCompile(`isolation`)}
+	summaries := NoteKeyword{Name: "summaries", Pattern: regexp.MustCompile(`summaries`), Escalate: StatusWarning}
+
+	tests := []struct {
+		name        string
+		keywords    []NoteKeyword
+		version     string
+		wantMatches []string
+		wantStatus  Status
+	}{
+		{name: "no keywords", version: "2.328.0", wantStatus: StatusWarning},
+		{name: "security fix escalates", keywords: []NoteKeyword{security}, version: "2.328.0", wantMatches: []string{"security@2.329.0"}, wantStatus: StatusCritical},
+		{name: "no match", keywords: []NoteKeyword{breaking}, version: "2.328.0", wantStatus: StatusWarning},
+		{name: "flag without escalation", keywords: []NoteKeyword{isolation}, version: "2.328.0", wantMatches: []string{"isolation@2.329.0"}, wantStatus: StatusWarning},
+		{name: "policy status already higher", keywords: []NoteKeyword{summaries}, version: "2.327.1", wantMatches: []string{"summaries@2.328.0"}, wantStatus: StatusCritical},
+		{name: "oldest newer release first", keywords: []NoteKeyword{security, summaries}, version: "2.327.1", wantMatches: []string{"summaries@2.328.0", "security@2.329.0"}, wantStatus: StatusCritical},
+		{name: "latest has nothing newer", keywords: []NoteKeyword{security}, version: "2.329.0", wantStatus: StatusCurrent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := NewChecker(client, Config{CriticalAgeDays: 12, MaxAgeDays: 30, NoCache: true, NoteKeywords: tt.keywords})
+			analysis, err := checker.Analyse(context.Background(), tt.version)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var got []string
+			for _, m := range analysis.NoteMatches {
+				got = append(got, m.Keyword+"@"+m.Version.String())
+			}
+			if strings.Join(got, ",") != strings.Join(tt.wantMatches, ",") {
+				t.Errorf("NoteMatches = %v, want %v", got, tt.wantMatches)
+			}
+			if got := analysis.Status(); got != tt.wantStatus {
+				t.Errorf("Status() = %s, want %s", got, tt.wantStatus)
+			}
+			if len(tt.wantMatches) > 0 && !strings.Contains(analysis.Message, "release notes") {
+				t.Errorf("Message = %q, want the matches described", analysis.Message)
+			}
+		})
+	}
+}
+
+func TestConfigValidate_NoteKeywords(t *testing.T) {
+	tests := []struct {
+		name    string
+		keyword NoteKeyword
+		wantErr bool
+	}{
+		{name: "valid", keyword: NoteKeyword{Name: "security", Pattern: regexp.MustCompile("security"), Escalate: StatusCritical}},
+		{name: "flag only", keyword: NoteKeyword{Name: "deprecat", Pattern: regexp.MustCompile("deprecat")}},
+		{name: "no pattern", keyword: NoteKeyword{Name: "security"}, wantErr: true},
+		{name: "escalate to current", keyword: NoteKeyword{Name: "security", Pattern: regexp.MustCompile("security"), Escalate: StatusCurrent}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Config{NoteKeywords: []NoteKeyword{tt.keyword}}.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %t", err, tt.wantErr)
+			}
+		})
+	}
+}
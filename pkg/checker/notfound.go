@@ -0,0 +1,180 @@
+package checker
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+// NotFoundCache remembers versions found not to exist, so repeated checks of a
+// mistyped version fail without fetching every release. The analysis caches
+// implement it; one set with SetAnalysisCache is used when Config.NotFoundTTL is set.
+type NotFoundCache interface {
+	GetNotFound(key string) (notFound *VersionNotFoundError, checkedAt time.Time, ok bool)
+	PutNotFound(key string, notFound *VersionNotFoundError, checkedAt time.Time) error
+}
+
+// notFoundEntry is a remembered VersionNotFoundError
+type notFoundEntry struct {
+	Version   string    `json:"version"`
+	Latest    string    `json:"latest"`
+	Nearest   []string  `json:"nearest,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+func newNotFoundEntry(notFound *VersionNotFoundError, checkedAt time.Time) notFoundEntry {
+	entry := notFoundEntry{Version: notFound.Version.Original(), Latest: notFound.Latest.Original(), CheckedAt: checkedAt}
+	for _, v := range notFound.Nearest {
+		entry.Nearest = append(entry.Nearest, v.Original())
+	}
+	return entry
+}
+
+// notFound converts the entry back to an error, or returns nil if it is unreadable
+func (e notFoundEntry) notFound() *VersionNotFoundError {
+	version, err := semver.NewVersion(e.Version)
+	if err != nil {
+		return nil
+	}
+	latest, err := semver.NewVersion(e.Latest)
+	if err != nil {
+		return nil
+	}
+	notFound := &VersionNotFoundError{Version: version, Latest: latest, Cached: true}
+	for _, n := range e.Nearest {
+		if v, err := semver.NewVersion(n); err == nil {
+			notFound.Nearest = append(notFound.Nearest, v)
+		}
+	}
+	return notFound
+}
+
+// nearestSuggestions is how many releases a VersionNotFoundError suggests
+const nearestSuggestions = 3
+
+// nearestVersions returns up to n releases closest to version in semver order,
+// lowest first, taking alternately from just below and just above it
+func nearestVersions(releases []types.Release, version *semver.Version, n int) []*semver.Version {
+	sorted := make([]*semver.Version, 0, len(releases))
+	for _, r := range releases {
+		sorted = append(sorted, r.Version)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return types.CompareVersions(sorted[i], sorted[j]) < 0 })
+
+	at := sort.Search(len(sorted), func(i int) bool { return types.CompareVersions(sorted[i], version) > 0 })
+	lo, hi := at, at
+	for hi-lo < n && (lo > 0 || hi < len(sorted)) {
+		if lo > 0 {
+			lo--
+		}
+		if hi-lo < n && hi < len(sorted) {
+			hi++
+		}
+	}
+	return sorted[lo:hi]
+}
+
+// GetNotFound returns the remembered error for key and when it was found
+func (m *MemoryAnalysisCache) GetNotFound(key string) (*VersionNotFoundError, time.Time, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry, ok := m.notFound[key]
+	if !ok {
+		return nil, time.Time{}, false
+	}
+	notFound := entry.notFound()
+	return notFound, entry.CheckedAt, notFound != nil
+}
+
+// PutNotFound remembers notFound under key
+func (m *MemoryAnalysisCache) PutNotFound(key string, notFound *VersionNotFoundError, checkedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.notFound[key] = newNotFoundEntry(notFound, checkedAt)
+	return nil
+}
+
+// GetNotFound reads the error stored under key; unreadable entries are misses
+func (f *FileAnalysisCache) GetNotFound(key string) (*VersionNotFoundError, time.Time, bool) {
+	data, err := os.ReadFile(f.notFoundPath(key))
+	if err != nil {
+		return nil, time.Time{}, false
+	}
+	var entry notFoundEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, time.Time{}, false
+	}
+	notFound := entry.notFound()
+	return notFound, entry.CheckedAt, notFound != nil
+}
+
+// PutNotFound writes notFound under key; it is pruned with the analyses
+func (f *FileAnalysisCache) PutNotFound(key string, notFound *VersionNotFoundError, checkedAt time.Time) error {
+	data, err := json.Marshal(newNotFoundEntry(notFound, checkedAt))
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(f.Dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create analysis cache directory: %w", err)
+	}
+	if err := os.WriteFile(f.notFoundPath(key), data, 0o644); err != nil {
+		return fmt.Errorf("failed to write analysis cache: %w", err)
+	}
+	return nil
+}
+
+func (f *FileAnalysisCache) notFoundPath(key string) string {
+	return filepath.Join(f.Dir, "notfound-"+key+".json")
+}
+
+// notFoundKey identifies a version of the checker's repository
+func (c *Checker) notFoundKey(version *semver.Version) string {
+	h := sha256.Sum256([]byte(fmt.Sprintf("repo=%s\nversion=%s\n", c.repository, version)))
+	return hex.EncodeToString(h[:])
+}
+
+// notFoundCache returns the cache for missing versions, or nil when not enabled
+func (c *Checker) notFoundCache() NotFoundCache {
+	cache, ok := c.analyses.(NotFoundCache)
+	if !ok || c.config.NotFoundTTL <= 0 {
+		return nil
+	}
+	return cache
+}
+
+// cachedNotFound returns the remembered error for a version found missing
+// within NotFoundTTL, or nil
+func (c *Checker) cachedNotFound(version *semver.Version) *VersionNotFoundError {
+	cache := c.notFoundCache()
+	if cache == nil {
+		return nil
+	}
+	notFound, checkedAt, ok := cache.GetNotFound(c.notFoundKey(version))
+	if !ok || time.Since(checkedAt) >= c.config.NotFoundTTL {
+		return nil
+	}
+	c.log(slog.LevelInfo, "not-found cache hit", "version", version.String(), "checked_at", checkedAt)
+	return notFound
+}
+
+// rememberNotFound records err if it is a version that does not exist
+func (c *Checker) rememberNotFound(err error) {
+	var notFound *VersionNotFoundError
+	cache := c.notFoundCache()
+	if cache == nil || !errors.As(err, &notFound) {
+		return
+	}
+	if err := cache.PutNotFound(c.notFoundKey(notFound.Version), notFound, time.Now()); err != nil {
+		c.log(slog.LevelWarn, "not-found cache write failed", "error", err)
+	}
+}
@@ -0,0 +1,95 @@
+package checker
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+func TestAnalyse_NotFoundCache(t *testing.T) {
+	releases := []types.Release{
+		newTestRelease("2.329.0", 5),
+		newTestRelease("2.328.0", 40),
+	}
+	fetchErr := errors.New("failed to fetch releases")
+
+	tests := []struct {
+		name       string
+		cache      func(t *testing.T) AnalysisCache
+		ttl        time.Duration
+		wantCached bool
+	}{
+		{"memory cache", func(*testing.T) AnalysisCache { return NewMemoryAnalysisCache() }, time.Minute, true},
+		{"file cache", func(t *testing.T) AnalysisCache { return NewFileAnalysisCache(t.TempDir()) }, time.Minute, true},
+		{"disabled", func(*testing.T) AnalysisCache { return NewMemoryAnalysisCache() }, 0, false},
+		{"expired", func(*testing.T) AnalysisCache { return NewMemoryAnalysisCache() }, time.Nanosecond, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := tt.cache(t)
+			analyse := func(client *MockGitHubClient) error {
+				checker := NewChecker(client, Config{CriticalAgeDays: 12, MaxAgeDays: 30, NoCache: true, NotFoundTTL: tt.ttl})
+				checker.SetAnalysisCache(cache, "actions/runner")
+				_, err := checker.Analyse(context.Background(), "2.300.0")
+				return err
+			}
+
+			var notFound *VersionNotFoundError
+			if err := analyse(&MockGitHubClient{AllReleases: releases}); !errors.As(err, &notFound) || notFound.Cached {
+				t.Fatalf("first check: err = %v, want an uncached VersionNotFoundError", err)
+			}
+
+			// The second check fails if it fetches, so a cached miss shows as not found
+			err := analyse(&MockGitHubClient{Error: fetchErr})
+			if !tt.wantCached {
+				if !errors.Is(err, fetchErr) {
+					t.Errorf("second check: err = %v, want a fetch", err)
+				}
+				return
+			}
+			if !errors.As(err, &notFound) || !notFound.Cached {
+				t.Fatalf("second check: err = %v, want a cached VersionNotFoundError", err)
+			}
+			if notFound.Version.String() != "2.300.0" || notFound.Latest.String() != "2.329.0" {
+				t.Errorf("cached error = %v, want 2.300.0 missing with latest 2.329.0", notFound)
+			}
+			if len(notFound.Nearest) != 2 {
+				t.Errorf("cached Nearest = %v, want both releases", notFound.Nearest)
+			}
+		})
+	}
+}
+
+func TestNearestVersions(t *testing.T) {
+	var releases []types.Release
+	for _, v := range []string{"2.329.0", "2.327.1", "2.328.0", "2.326.0", "2.325.0"} {
+		releases = append(releases, types.Release{Version: semver.MustParse(v)})
+	}
+
+	tests := []struct {
+		version string
+		n       int
+		want    string
+	}{
+		{"2.327.9", 3, "2.326.0 2.327.1 2.328.0"},
+		{"2.326.5", 2, "2.326.0 2.327.1"},
+		{"2.400.0", 3, "2.327.1 2.328.0 2.329.0"},
+		{"1.0.0", 2, "2.325.0 2.326.0"},
+		{"2.328.1", 10, "2.325.0 2.326.0 2.327.1 2.328.0 2.329.0"},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, v := range nearestVersions(releases, semver.MustParse(tt.version), tt.n) {
+			got = append(got, v.String())
+		}
+		if strings.Join(got, " ") != tt.want {
+			t.Errorf("nearestVersions(%s, %d) = %v, want %s", tt.version, tt.n, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package checker
+
+import (
+	"regexp"
+	"strconv"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+// linePattern matches comparison versions naming a release line, such as "1.29"
+var linePattern = regexp.MustCompile(`^v?(\d+)\.(\d+)$`)
+
+// ParseReleaseLine reports whether input, once normalised, names a release line
+// as MAJOR.MINOR rather than a full version, returning the line as MAJOR.MINOR.0.
+// Lines are resolved to their latest patch release once the releases are known.
+func ParseReleaseLine(input string, n Normalisation) (*semver.Version, bool) {
+	normalised, _ := NormaliseVersion(input, n)
+	m := linePattern.FindStringSubmatch(normalised)
+	if m == nil {
+		return nil, false
+	}
+	major, err := strconv.ParseUint(m[1], 10, 64)
+	if err != nil {
+		return nil, false
+	}
+	minor, err := strconv.ParseUint(m[2], 10, 64)
+	if err != nil {
+		return nil, false
+	}
+	return semver.New(major, minor, 0, "", ""), true
+}
+
+// resolveLine returns the highest patch release on line's major and minor
+// version, skipping prereleases, or nil if there is none
+func resolveLine(releases []types.Release, line *semver.Version) *semver.Version {
+	var best *semver.Version
+	for _, r := range releases {
+		if r.Version.Major() != line.Major() || r.Version.Minor() != line.Minor() || r.Version.Prerelease() != "" {
+			continue
+		}
+		if best == nil || types.CompareVersions(r.Version, best) > 0 {
+			best = r.Version
+		}
+	}
+	return best
+}
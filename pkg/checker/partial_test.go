@@ -0,0 +1,79 @@
+package checker
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+func TestParseReleaseLine(t *testing.T) {
+	tests := []struct {
+		input  string
+		want   string
+		wantOK bool
+	}{
+		{"1.29", "1.29.0", true},
+		{"v2.328", "2.328.0", true},
+		{" V2.328 ", "2.328.0", true},
+		{"2.328.0", "", false},
+		{"2", "", false},
+		{"latest-1", "", false},
+		{"1.29-rc", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseReleaseLine(tt.input, NormaliseAll)
+		if ok != tt.wantOK || ok && got.String() != tt.want {
+			t.Errorf("ParseReleaseLine(%q) = %v, %t; want %s, %t", tt.input, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestAnalyse_ReleaseLine(t *testing.T) {
+	latest := newTestRelease("2.329.0", 3)
+	releases := []types.Release{
+		latest,
+		newTestRelease("2.328.2-rc.1", 5),
+		newTestRelease("2.328.1", 20),
+		newTestRelease("2.328.0", 60),
+		newTestRelease("2.327.1", 80),
+	}
+	client := &MockGitHubClient{LatestRelease: &latest, AllReleases: releases}
+
+	tests := []struct {
+		name        string
+		input       string
+		wantVersion string
+		wantFrom    string
+		wantErr     bool
+	}{
+		{name: "latest patch", input: "2.328", wantVersion: "2.328.1", wantFrom: "2.328"},
+		{name: "v prefix", input: "v2.327", wantVersion: "2.327.1", wantFrom: "v2.327"},
+		{name: "latest line", input: "2.329", wantVersion: "2.329.0", wantFrom: "2.329"},
+		{name: "full version", input: "2.328.0", wantVersion: "2.328.0"},
+		{name: "line without releases", input: "2.326", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := NewChecker(client, Config{CriticalAgeDays: 12, MaxAgeDays: 30, NoCache: true, Normalisation: NormaliseAll})
+			analysis, err := checker.Analyse(context.Background(), tt.input)
+			if tt.wantErr {
+				var notFound *VersionNotFoundError
+				if !errors.As(err, &notFound) {
+					t.Fatalf("Analyse() error = %v, want a VersionNotFoundError", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := analysis.ComparisonVersion.String(); got != tt.wantVersion {
+				t.Errorf("ComparisonVersion = %s, want %s", got, tt.wantVersion)
+			}
+			if analysis.ResolvedFrom != tt.wantFrom {
+				t.Errorf("ResolvedFrom = %q, want %q", analysis.ResolvedFrom, tt.wantFrom)
+			}
+		})
+	}
+}
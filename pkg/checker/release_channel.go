@@ -0,0 +1,54 @@
+package checker
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+// ReleaseChannelStable is the channel of releases that are neither marked as
+// prereleases nor have a prerelease part
+const ReleaseChannelStable = "stable"
+
+// ChannelPattern assigns releases whose tag matches Pattern to the named
+// release channel, e.g. {"beta", regexp.MustCompile(`-beta\.`)}
+type ChannelPattern struct {
+	Name    string
+	Pattern *regexp.Regexp
+}
+
+// releaseChannel returns the release channel of r: the first pattern matching
+// its tag, otherwise the leading identifier of its prerelease part ("rc" for
+// 1.30.0-rc.1), "prerelease" when only marked as one, or ReleaseChannelStable
+func releaseChannel(r types.Release, patterns []ChannelPattern) string {
+	tag := r.Version.Original()
+	for _, p := range patterns {
+		if p.Pattern.MatchString(tag) {
+			return p.Name
+		}
+	}
+	if pre := r.Version.Prerelease(); pre != "" {
+		name := strings.ToLower(strings.TrimRight(strings.SplitN(pre, ".", 2)[0], "0123456789-"))
+		if name != "" {
+			return name
+		}
+		return "prerelease"
+	}
+	if r.Prerelease {
+		return "prerelease"
+	}
+	return ReleaseChannelStable
+}
+
+// channelReleases returns the releases checked on channel: its own and, as
+// they supersede its candidates, stable ones
+func channelReleases(releases []types.Release, channel string, patterns []ChannelPattern) []types.Release {
+	var kept []types.Release
+	for _, r := range releases {
+		if c := releaseChannel(r, patterns); c == channel || c == ReleaseChannelStable {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,80 @@
+package checker
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+func TestReleaseChannel(t *testing.T) {
+	patterns := []ChannelPattern{{Name: "preview", Pattern: regexp.MustCompile(`-pre\d*$`)}}
+	marked := newTestRelease("2.331.0", 1)
+	marked.Prerelease = true
+
+	tests := []struct {
+		release types.Release
+		want    string
+	}{
+		{newTestRelease("1.30.0", 1), "stable"},
+		{newTestRelease("1.30.0-rc.1", 1), "rc"},
+		{newTestRelease("1.30.0-beta.2", 1), "beta"},
+		{newTestRelease("1.30.0-RC1", 1), "rc"},
+		{newTestRelease("1.30.0-1", 1), "prerelease"},
+		{newTestRelease("1.30.0-pre2", 1), "preview"},
+		{marked, "prerelease"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.release.Version.Original(), func(t *testing.T) {
+			if got := releaseChannel(tt.release, patterns); got != tt.want {
+				t.Errorf("releaseChannel() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyse_ReleaseChannel(t *testing.T) {
+	releases := []types.Release{
+		newTestRelease("1.31.0-beta.0", 2),
+		newTestRelease("1.30.0-rc.2", 4),
+		newTestRelease("1.30.0-rc.1", 9),
+		newTestRelease("1.29.3", 12),
+		newTestRelease("1.29.2", 40),
+	}
+	for i := range releases[:3] {
+		releases[i].Prerelease = true
+	}
+
+	tests := []struct {
+		name       string
+		channel    string
+		version    string
+		wantLatest string
+		wantBehind int
+	}{
+		{"rc channel ignores betas", "rc", "1.30.0-rc.1", "1.30.0-rc.2", 1},
+		{"beta channel ignores rcs", "beta", "1.29.3", "1.31.0-beta.0", 1},
+		{"stable channel", "stable", "1.29.2", "1.29.3", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := NewChecker(&MockGitHubClient{AllReleases: releases}, Config{CriticalAgeDays: 12, MaxAgeDays: 30, NoCache: true, Channel: tt.channel})
+			analysis, err := checker.Analyse(context.Background(), tt.version)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if analysis.LatestVersion.String() != tt.wantLatest || analysis.ReleasesBehind != tt.wantBehind || analysis.ReleaseChannel != tt.channel {
+				t.Errorf("LatestVersion, ReleasesBehind, ReleaseChannel = %s, %d, %q; want %s, %d, %q",
+					analysis.LatestVersion, analysis.ReleasesBehind, analysis.ReleaseChannel, tt.wantLatest, tt.wantBehind, tt.channel)
+			}
+		})
+	}
+
+	checker := NewChecker(&MockGitHubClient{AllReleases: releases[:3]}, Config{CriticalAgeDays: 12, MaxAgeDays: 30, NoCache: true, Channel: "alpha"})
+	if _, err := checker.Analyse(context.Background(), ""); err == nil {
+		t.Error("expected an error for a channel without releases")
+	}
+}
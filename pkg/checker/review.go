@@ -0,0 +1,39 @@
+package checker
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+// DefaultBreakingNotes matches the usual breaking-change markers in release notes
+var DefaultBreakingNotes = regexp.MustCompile(`(?i)\bbreaking\b|backwards?[ -]incompatible`)
+
+// applyReview flags an upgrade to the recommended version that needs a human:
+// one crossing a major version (or a 0.x minor version, which semver allows to
+// break), or one past a release whose notes match breaking
+func applyReview(analysis *Analysis, breaking *regexp.Regexp) {
+	from, to := analysis.ComparisonVersion, analysis.Recommended()
+	if from == nil || to == nil || types.CompareVersions(to, from) <= 0 {
+		return
+	}
+	if breaking == nil {
+		breaking = DefaultBreakingNotes
+	}
+
+	var reasons []string
+	switch {
+	case to.Major() > from.Major():
+		reasons = append(reasons, fmt.Sprintf("crosses major version %d to %d", from.Major(), to.Major()))
+	case from.Major() == 0 && to.Minor() > from.Minor():
+		reasons = append(reasons, fmt.Sprintf("crosses 0.x minor version 0.%d to 0.%d", from.Minor(), to.Minor()))
+	}
+	for _, r := range analysis.NewerReleases {
+		if types.CompareVersions(r.Version, to) <= 0 && breaking.MatchString(r.Notes) {
+			reasons = append(reasons, fmt.Sprintf("breaking changes in v%s release notes", types.FormatVersion(r.Version)))
+		}
+	}
+	analysis.RequiresManualReview = len(reasons) > 0
+	analysis.ReviewReasons = reasons
+}
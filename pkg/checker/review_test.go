@@ -0,0 +1,93 @@
+package checker
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+func TestAnalyse_ManualReview(t *testing.T) {
+	withNotes := func(version string, daysAgo int, notes string) types.Release {
+		r := newTestRelease(version, daysAgo)
+		r.Notes = notes
+		return r
+	}
+
+	tests := []struct {
+		name        string
+		releases    []types.Release
+		yanked      []YankedRelease
+		breaking    *regexp.Regexp
+		version     string
+		wantReview  bool
+		wantReasons []string
+	}{
+		{
+			name:     "patch update",
+			releases: []types.Release{withNotes("2.329.1", 2, "Bug fixes"), newTestRelease("2.329.0", 20)},
+			version:  "2.329.0",
+		},
+		{
+			name:        "major update",
+			releases:    []types.Release{newTestRelease("3.0.0", 2), newTestRelease("2.329.0", 20)},
+			version:     "2.329.0",
+			wantReview:  true,
+			wantReasons: []string{"crosses major version 2 to 3"},
+		},
+		{
+			name:        "0.x minor update",
+			releases:    []types.Release{newTestRelease("0.5.0", 2), newTestRelease("0.4.2", 20)},
+			version:     "0.4.2",
+			wantReview:  true,
+			wantReasons: []string{"crosses 0.x minor version 0.4 to 0.5"},
+		},
+		{
+			name: "breaking change in notes",
+			releases: []types.Release{
+				withNotes("2.330.0", 2, "Adds arm64 images"),
+				withNotes("2.329.0", 10, "BREAKING: drops Node 16"),
+				newTestRelease("2.328.0", 40),
+			},
+			version:     "2.328.0",
+			wantReview:  true,
+			wantReasons: []string{"breaking changes in v2.329.0 release notes"},
+		},
+		{
+			name:     "custom markers",
+			releases: []types.Release{withNotes("2.329.0", 2, "BREAKING: drops Node 16"), newTestRelease("2.328.0", 20)},
+			breaking: regexp.MustCompile(`(?i)migration required`),
+			version:  "2.328.0",
+		},
+		{
+			name:     "breaking release beyond the recommendation",
+			releases: []types.Release{withNotes("2.330.0", 2, "Breaking change"), newTestRelease("2.329.0", 10), newTestRelease("2.328.0", 40)},
+			yanked:   []YankedRelease{{Version: semver.MustParse("2.330.0"), Reason: "regression"}},
+			version:  "2.328.0",
+		},
+		{
+			name:     "up to date",
+			releases: []types.Release{newTestRelease("3.0.0", 2)},
+			version:  "3.0.0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &MockGitHubClient{LatestRelease: &tt.releases[0], AllReleases: tt.releases}
+			checker := NewChecker(client, Config{CriticalAgeDays: 12, MaxAgeDays: 30, NoCache: true, Yanked: tt.yanked, BreakingNotes: tt.breaking})
+			analysis, err := checker.Analyse(context.Background(), tt.version)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if analysis.RequiresManualReview != tt.wantReview {
+				t.Errorf("RequiresManualReview = %t, want %t", analysis.RequiresManualReview, tt.wantReview)
+			}
+			if got := strings.Join(analysis.ReviewReasons, ","); got != strings.Join(tt.wantReasons, ",") {
+				t.Errorf("ReviewReasons = %v, want %v", analysis.ReviewReasons, tt.wantReasons)
+			}
+		})
+	}
+}
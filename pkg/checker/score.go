@@ -0,0 +1,28 @@
+package checker
+
+// Drift score weights: how many points each unit of drift adds
+const (
+	DriftWeightRelease       = 1  // Per release behind
+	DriftWeightMinorVersion  = 5  // Per minor version behind, for versions policies
+	DriftWeightDayOverdue    = 2  // Per day past expiry, for days policies
+	DriftWeightVulnerability = 25 // Per known vulnerability fixed by updating
+)
+
+// DriftScore rates how far the comparison version has drifted from the latest,
+// so results across repositories can be ranked: higher means update sooner.
+// It weights releases behind, minor versions behind (versions policies), days
+// past expiry (days policies, even under a waiver) and any known
+// vulnerabilities the caller has set. Zero means up to date.
+func (a *Analysis) DriftScore() int {
+	if a.ComparisonVersion == nil || a.IsLatest {
+		return 0
+	}
+	score := a.ReleasesBehind*DriftWeightRelease + a.Vulnerabilities*DriftWeightVulnerability
+	if a.PolicyType == "versions" {
+		score += a.MinorVersionsBehind * DriftWeightMinorVersion
+	} else if a.ExpiryDate() != nil {
+		// Days policies count releases as minor versions, so only releases count
+		score += max(0, -a.DaysUntilExpiry()) * DriftWeightDayOverdue
+	}
+	return score
+}
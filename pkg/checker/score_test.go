@@ -0,0 +1,76 @@
+package checker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+func TestAnalysis_DriftScore(t *testing.T) {
+	firstNewer := time.Date(2025, 8, 13, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		analysis Analysis
+		want     int
+	}{
+		{
+			name:     "no comparison version",
+			analysis: Analysis{ReleasesBehind: 3},
+			want:     0,
+		},
+		{
+			name:     "latest",
+			analysis: Analysis{ComparisonVersion: semver.MustParse("1.0.0"), IsLatest: true},
+			want:     0,
+		},
+		{
+			name: "behind within expiry",
+			analysis: Analysis{
+				ComparisonVersion: semver.MustParse("1.0.0"), ReleasesBehind: 2,
+				FirstNewerReleaseDate: &firstNewer, DaysSinceUpdate: 10, MaxAgeDays: 30, PolicyType: "days",
+			},
+			want: 2,
+		},
+		{
+			name: "days overdue",
+			analysis: Analysis{
+				ComparisonVersion: semver.MustParse("1.0.0"), ReleasesBehind: 2, IsExpired: true,
+				FirstNewerReleaseDate: &firstNewer, DaysSinceUpdate: 40, MaxAgeDays: 30, PolicyType: "days",
+			},
+			want: 2 + 10*DriftWeightDayOverdue,
+		},
+		{
+			// Days policies set MinorVersionsBehind to ReleasesBehind, which counts once
+			name: "days policy minor versions",
+			analysis: Analysis{
+				ComparisonVersion: semver.MustParse("2.320.0"), ReleasesBehind: 10, MinorVersionsBehind: 10, IsExpired: true,
+				FirstNewerReleaseDate: &firstNewer, DaysSinceUpdate: 703, MaxAgeDays: 30, PolicyType: "days",
+			},
+			want: 10 + 673*DriftWeightDayOverdue,
+		},
+		{
+			name: "minor versions behind",
+			analysis: Analysis{
+				ComparisonVersion: semver.MustParse("1.31.0"), ReleasesBehind: 9, MinorVersionsBehind: 3,
+				FirstNewerReleaseDate: &firstNewer, DaysSinceUpdate: 400, MaxAgeDays: 30, PolicyType: "versions",
+			},
+			want: 9 + 3*DriftWeightMinorVersion,
+		},
+		{
+			name: "vulnerabilities",
+			analysis: Analysis{
+				ComparisonVersion: semver.MustParse("1.0.0"), ReleasesBehind: 1, Vulnerabilities: 2,
+			},
+			want: 1 + 2*DriftWeightVulnerability,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.analysis.DriftScore(); got != tt.want {
+				t.Errorf("DriftScore() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package checker
+
+import (
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+// applyMinReleaseAge holds back a recommended release published less than
+// minAge before now, recording it as soaking and recommending the newest
+// release before it that is old enough, or the comparison version if none is
+func applyMinReleaseAge(analysis *Analysis, candidates []types.Release, yanked []YankedRelease, minAge time.Duration, now time.Time) {
+	if minAge <= 0 {
+		return
+	}
+	comparison, recommended := analysis.ComparisonVersion, analysis.Recommended()
+	if comparison != nil && types.CompareVersions(recommended, comparison) <= 0 {
+		return
+	}
+	cutoff := now.Add(-minAge)
+	if r := findRelease(candidates, recommended); r == nil || !r.PublishedAt.After(cutoff) {
+		return
+	}
+	analysis.SoakingVersion = recommended
+
+	allowPrerelease := recommended.Prerelease() != ""
+	var best *semver.Version
+	for _, r := range candidates {
+		switch {
+		case types.CompareVersions(r.Version, recommended) >= 0,
+			comparison != nil && types.CompareVersions(r.Version, comparison) <= 0,
+			r.PublishedAt.After(cutoff),
+			findYanked(yanked, r.Version) != nil,
+			r.Version.Prerelease() != "" && !allowPrerelease:
+			continue
+		}
+		if best == nil || types.CompareVersions(r.Version, best) > 0 {
+			best = r.Version
+		}
+	}
+	if best == nil {
+		best = comparison
+	}
+	analysis.RecommendedVersion = best
+}
+
+// findRelease returns the release of version, or nil if there is none
+func findRelease(releases []types.Release, version *semver.Version) *types.Release {
+	for i := range releases {
+		if types.CompareVersions(releases[i].Version, version) == 0 {
+			return &releases[i]
+		}
+	}
+	return nil
+}
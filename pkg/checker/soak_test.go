@@ -0,0 +1,53 @@
+package checker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+func TestAnalyse_MinReleaseAge(t *testing.T) {
+	latest := newTestRelease("2.330.0", 1)
+	releases := []types.Release{latest, newTestRelease("2.329.1", 2), newTestRelease("2.329.0", 10), newTestRelease("2.328.0", 40), newTestRelease("2.327.1", 60)}
+	client := &MockGitHubClient{LatestRelease: &latest, AllReleases: releases}
+	days := func(n int) time.Duration { return time.Duration(n) * 24 * time.Hour }
+
+	tests := []struct {
+		name            string
+		minAge          time.Duration
+		yanked          []YankedRelease
+		version         string
+		wantRecommended string
+		wantSoaking     string
+		wantStatus      Status
+	}{
+		{name: "no minimum", version: "2.328.0", wantRecommended: "2.330.0"},
+		{name: "latest old enough", minAge: time.Hour, version: "2.328.0", wantRecommended: "2.330.0"},
+		{name: "latest soaking", minAge: days(3), version: "2.328.0", wantRecommended: "2.329.0", wantSoaking: "2.330.0", wantStatus: StatusWarning},
+		{name: "soaked release yanked", minAge: days(3), yanked: []YankedRelease{{Version: semver.MustParse("2.329.0"), Reason: "regression"}}, version: "2.327.1", wantRecommended: "2.328.0", wantSoaking: "2.330.0"},
+		{name: "nothing newer has soaked", minAge: days(30), version: "2.329.0", wantRecommended: "2.329.0", wantSoaking: "2.330.0"},
+		{name: "latest lookup", minAge: days(3), wantRecommended: "2.329.0", wantSoaking: "2.330.0"},
+		{name: "up to date", minAge: days(3), version: "2.330.0", wantRecommended: "2.330.0", wantStatus: StatusCurrent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := NewChecker(client, Config{CriticalAgeDays: 12, MaxAgeDays: 30, NoCache: true, Yanked: tt.yanked, MinReleaseAge: tt.minAge})
+			analysis, err := checker.Analyse(context.Background(), tt.version)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := analysis.Recommended().String(); got != tt.wantRecommended {
+				t.Errorf("Recommended() = %s, want %s", got, tt.wantRecommended)
+			}
+			if got := versionString(analysis.SoakingVersion); got != tt.wantSoaking {
+				t.Errorf("SoakingVersion = %q, want %q", got, tt.wantSoaking)
+			}
+			if tt.wantStatus != "" && analysis.Status() != tt.wantStatus {
+				t.Errorf("Status() = %s, want %s (the minimum age only changes the recommendation)", analysis.Status(), tt.wantStatus)
+			}
+		})
+	}
+}
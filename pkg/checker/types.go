@@ -2,13 +2,25 @@ package checker
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/nickromney-org/github-release-version-checker/pkg/types"
 )
 
+// DefaultMaxAgeDays is the expiry window used when no days-based threshold is configured
+const DefaultMaxAgeDays = 30
+
+// Defaults for the days-based expiry timeline table
+const (
+	DefaultTimelineWindowDays = 90
+	DefaultTimelineMinRows    = 4
+)
+
 // Status represents the current state of a version
 type Status string
 
@@ -19,6 +31,79 @@ const (
 	StatusExpired  Status = "expired"
 )
 
+// AtLeast reports whether s is as severe as other, or more
+func (s Status) AtLeast(other Status) bool {
+	return statusRank(s) >= statusRank(other)
+}
+
+// DegradedReason explains why an analysis may have missed releases
+type DegradedReason string
+
+const (
+	// DegradedTruncated means the release list stopped at the client's page limit
+	DegradedTruncated DegradedReason = "truncated_pagination"
+	// DegradedAssetsUnchecked means Config.RequiredAssets or Config.Platform could not be checked
+	DegradedAssetsUnchecked DegradedReason = "assets_unchecked"
+)
+
+// LatestPreference chooses which release is the latest when the release GitHub
+// marks as latest is not the highest version
+type LatestPreference string
+
+const (
+	LatestHighest LatestPreference = "highest" // Highest semantic version (default)
+	LatestMarked  LatestPreference = "marked"  // The release GitHub marks as latest
+)
+
+// Track chooses whether releases marked as prereleases can be the latest
+type Track string
+
+const (
+	TrackStable     Track = "stable"     // Only stable releases drive the status (default)
+	TrackPrerelease Track = "prerelease" // Prereleases count too, for early adopters tracking RCs
+)
+
+// Ordering decides which releases count as newer than the comparison version
+type Ordering string
+
+const (
+	OrderingSemver Ordering = "semver" // Any higher version (default)
+	OrderingDate   Ordering = "date"   // Higher versions published after the comparison version
+)
+
+// UnknownVersionPolicy decides what a comparison version that is not a release,
+// such as a custom build, results in
+type UnknownVersionPolicy string
+
+const (
+	UnknownVersionError   UnknownVersionPolicy = "error"   // A VersionNotFoundError (default)
+	UnknownVersionExpired UnknownVersionPolicy = "expired" // An expired analysis
+	UnknownVersionWarning UnknownVersionPolicy = "warning" // A warning analysis
+)
+
+// VersionNotFoundError is returned when the comparison version is not a release
+type VersionNotFoundError struct {
+	Version *semver.Version
+	Latest  *semver.Version
+	Nearest []*semver.Version // The releases closest to Version, lowest first, as suggestions
+	Cached  bool              // Remembered from an earlier check (see NotFoundCache)
+}
+
+func (e *VersionNotFoundError) Error() string {
+	msg := fmt.Sprintf("version %s does not exist in GitHub releases (latest: %s)", types.FormatVersion(e.Version), types.FormatVersion(e.Latest))
+	if len(e.Nearest) > 0 {
+		nearest := make([]string, len(e.Nearest))
+		for i, v := range e.Nearest {
+			nearest[i] = v.String()
+		}
+		msg += "; nearest releases: " + strings.Join(nearest, ", ")
+	}
+	return msg
+}
+
+// ErrNoCachedReleases is returned offline when there are no cached releases to analyse
+var ErrNoCachedReleases = errors.New("no cached releases to check offline")
+
 // ReleaseExpiry represents expiry information for a single release
 type ReleaseExpiry struct {
 	Version         *semver.Version `json:"version"`
@@ -27,6 +112,7 @@ type ReleaseExpiry struct {
 	DaysUntilExpiry int             `json:"days_until_expiry"`
 	IsExpired       bool            `json:"is_expired"`
 	IsLatest        bool            `json:"is_latest"`
+	GapDays         *int            `json:"gap_days,omitempty"` // Days since the previous release; nil for the first
 }
 
 // MarshalJSON implements custom JSON marshalling for ReleaseExpiry
@@ -38,6 +124,7 @@ func (r *ReleaseExpiry) MarshalJSON() ([]byte, error) {
 		DaysUntilExpiry int     `json:"days_until_expiry"`
 		IsExpired       bool    `json:"is_expired"`
 		IsLatest        bool    `json:"is_latest"`
+		GapDays         *int    `json:"gap_days,omitempty"`
 	}{
 		Version:         r.Version.String(),
 		ReleasedAt:      r.ReleasedAt.Format(time.RFC3339),
@@ -45,6 +132,7 @@ func (r *ReleaseExpiry) MarshalJSON() ([]byte, error) {
 		DaysUntilExpiry: r.DaysUntilExpiry,
 		IsExpired:       r.IsExpired,
 		IsLatest:        r.IsLatest,
+		GapDays:         r.GapDays,
 	})
 }
 
@@ -61,6 +149,10 @@ type Analysis struct {
 	FirstNewerVersion     *semver.Version `json:"first_newer_version,omitempty"`
 	FirstNewerReleaseDate *time.Time      `json:"first_newer_release_date,omitempty"`
 	NewerReleases         []types.Release `json:"newer_releases,omitempty"`
+	TotalNewer            int             `json:"total_newer"`    // All newer releases, even when NewerReleases is capped by Config.MaxNewer
+	PatchesBehind         int             `json:"patches_behind"` // Newer releases on the comparison version's minor line
+	MinorsBehind          int             `json:"minors_behind"`  // Newer releases on later minor lines of its major version
+	MajorsBehind          int             `json:"majors_behind"`  // Newer releases on later major versions
 	RecentReleases        []ReleaseExpiry `json:"recent_releases,omitempty"`
 	Message               string          `json:"message"`
 
@@ -69,12 +161,106 @@ type Analysis struct {
 	MaxAgeDays      int `json:"max_age_days"`
 
 	// Policy information
-	PolicyType          string `json:"policy_type,omitempty"`           // "days" or "versions"
+	PolicyType          string `json:"policy_type,omitempty"`           // "days", "versions" or "lts"
 	MinorVersionsBehind int    `json:"minor_versions_behind,omitempty"` // For version-based policies
+
+	// For policies with a support schedule: the comparison version's release
+	// line's phase, e.g. "maintenance", and its end of life
+	SupportPhase string     `json:"support_phase,omitempty"`
+	EndOfLife    *time.Time `json:"end_of_life,omitempty"`
+
+	// The last maintenance window before expiry, when the policy has a calendar;
+	// the update must be rolled out in it
+	MaintenanceWindow *time.Time `json:"maintenance_window,omitempty"`
+
+	// The waiver covering the comparison version, if any; Waived is true while
+	// it turns what would be an expired version into a warning
+	Waiver *Waiver `json:"waiver,omitempty"`
+	Waived bool    `json:"waived"`
+
+	// Rollback waivers whose newer releases were set aside while upstream rolls
+	// them back, so they count neither as newer nor as the latest
+	RolledBack []Waiver `json:"rolled_back,omitempty"`
+
+	// Whether the comparison version is not a release, analysed under
+	// Config.UnknownVersion rather than failing
+	UnknownVersion bool `json:"unknown_version"`
+
+	// Known-bad releases: Yanked is set when the comparison version is one, and
+	// RecommendedVersion when the latest release is one (see Recommended)
+	Yanked             *YankedRelease  `json:"yanked,omitempty"`
+	RecommendedVersion *semver.Version `json:"recommended_version,omitempty"`
+
+	// Newer releases whose notes match a configured keyword, and the status
+	// the matches raise this analysis to (see Config.NoteKeywords)
+	NoteMatches    []NoteMatch `json:"note_matches,omitempty"`
+	NoteEscalation Status      `json:"note_escalation,omitempty"`
+
+	// Whether updating to the recommended version should be reviewed by a
+	// person rather than merged automatically, and why
+	RequiresManualReview bool     `json:"requires_manual_review"`
+	ReviewReasons        []string `json:"review_reasons,omitempty"`
+
+	// The newest release held back as younger than Config.MinReleaseAge
+	SoakingVersion *semver.Version `json:"soaking_version,omitempty"`
+
+	// The newest release that would be recommended but lacks assets for some of
+	// Config.RequiredAssets or for Config.Platform, and the platforms it lacks them for
+	IncompleteRelease *semver.Version `json:"incomplete_release,omitempty"`
+	MissingAssets     []string        `json:"missing_assets,omitempty"`
+
+	// Latest release candidates, which differ when a maintenance release on an
+	// older branch is marked latest; MarkedLatest is nil if it could not be fetched
+	HighestVersion *semver.Version `json:"highest_version,omitempty"`
+	MarkedLatest   *semver.Version `json:"marked_latest,omitempty"`
+
+	// The latest with and without releases marked as prereleases, set once the
+	// release data includes them; the config's Track chooses which is LatestVersion
+	LatestStable              *semver.Version `json:"latest_stable,omitempty"`
+	LatestIncludingPrerelease *semver.Version `json:"latest_including_prerelease,omitempty"`
+
+	// How newer releases were chosen; empty for the default semver ordering
+	Ordering Ordering `json:"ordering,omitempty"`
+
+	// The channel the comparison version was resolved from, e.g. "latest-1";
+	// empty when a version was given
+	Channel string `json:"channel,omitempty"`
+
+	// The release line the comparison version was resolved from, as given, e.g.
+	// "1.29"; empty when a full version was given
+	ResolvedFrom string `json:"resolved_from,omitempty"`
+
+	// The release channel checked, e.g. "beta" (see Config.Channel); empty for the default
+	ReleaseChannel string `json:"release_channel,omitempty"`
+
+	// Release pages of the comparison and recommended versions, and the changes
+	// between them; nil when the releases have no pages
+	Links *Links `json:"links,omitempty"`
+
+	// Why the data behind the analysis may be incomplete; empty when it is not
+	DegradedReasons []DegradedReason `json:"degraded_reasons,omitempty"`
+
+	// Request context, set by the caller
+	Repository  string `json:"repository,omitempty"`   // owner/repo
+	Upstream    string `json:"upstream,omitempty"`     // owner/repo whose releases were checked, for forks and mirrors
+	TokenSource string `json:"token_source,omitempty"` // e.g., "env (GITHUB_TOKEN)", "gh-cli", "none"
+
+	// Known vulnerabilities in the comparison version that updating fixes, set
+	// by callers with a vulnerability source; counted in DriftScore
+	Vulnerabilities int `json:"vulnerabilities,omitempty"`
 }
 
 // Status returns the current status level
 func (a *Analysis) Status() Status {
+	status := a.policyStatus()
+	if statusRank(a.NoteEscalation) > statusRank(status) {
+		return a.NoteEscalation
+	}
+	return status
+}
+
+// policyStatus returns the status before any release note escalation
+func (a *Analysis) policyStatus() Status {
 	if a.ComparisonVersion == nil {
 		return StatusCurrent
 	}
@@ -87,13 +273,68 @@ func (a *Analysis) Status() Status {
 		return StatusCritical
 	}
 
-	if a.ReleasesBehind > 0 {
+	if a.ReleasesBehind > 0 || a.Yanked != nil || a.UnknownVersion {
 		return StatusWarning
 	}
 
 	return StatusCurrent
 }
 
+// Recommended returns the version to update to: the latest release, unless it
+// is yanked and an earlier release is not
+func (a *Analysis) Recommended() *semver.Version {
+	if a.RecommendedVersion != nil {
+		return a.RecommendedVersion
+	}
+	return a.LatestVersion
+}
+
+// NewerOmitted returns how many newer releases Config.MaxNewer left out of
+// NewerReleases, the oldest ones; TotalNewer still counts them all
+func (a *Analysis) NewerOmitted() int {
+	return max(a.TotalNewer-len(a.NewerReleases), 0)
+}
+
+// IsDegraded reports whether the analysis ran on possibly incomplete data, so the
+// result is only as good as the data it could see
+func (a *Analysis) IsDegraded() bool {
+	return len(a.DegradedReasons) > 0
+}
+
+// LatestDiscrepancy reports whether GitHub marks a release other than the highest
+// version as latest
+func (a *Analysis) LatestDiscrepancy() bool {
+	return a.HighestVersion != nil && a.MarkedLatest != nil && types.CompareVersions(a.HighestVersion, a.MarkedLatest) != 0
+}
+
+// PrereleaseAhead reports whether a prerelease is newer than the latest stable release
+func (a *Analysis) PrereleaseAhead() bool {
+	return a.LatestStable != nil && a.LatestIncludingPrerelease != nil &&
+		types.CompareVersions(a.LatestIncludingPrerelease, a.LatestStable) > 0
+}
+
+// ExpiryDate returns when the comparison version expires: its end of life under a
+// support schedule, or under a days-based policy MaxAgeDays after the first newer
+// release. Returns nil when not applicable.
+func (a *Analysis) ExpiryDate() *time.Time {
+	if a.EndOfLife != nil {
+		return a.EndOfLife
+	}
+	if a.FirstNewerReleaseDate == nil || a.MaxAgeDays <= 0 {
+		return nil
+	}
+	expiry := a.FirstNewerReleaseDate.AddDate(0, 0, a.MaxAgeDays)
+	return &expiry
+}
+
+// DaysUntilExpiry returns the days remaining before expiry (negative once overdue)
+func (a *Analysis) DaysUntilExpiry() int {
+	if a.EndOfLife != nil {
+		return daysBetween(time.Now(), *a.EndOfLife)
+	}
+	return a.MaxAgeDays - a.DaysSinceUpdate
+}
+
 // MarshalJSON implements custom JSON marshalling
 func (a *Analysis) MarshalJSON() ([]byte, error) {
 	type Alias Analysis
@@ -103,7 +344,14 @@ func (a *Analysis) MarshalJSON() ([]byte, error) {
 		ComparisonReleasedAt  *string `json:"comparison_released_at,omitempty"`
 		FirstNewerVersion     string  `json:"first_newer_version,omitempty"`
 		FirstNewerReleaseDate *string `json:"first_newer_release_date,omitempty"`
+		ExpiresAt             *string `json:"expires_at,omitempty"`
+		MaintenanceWindow     *string `json:"maintenance_window,omitempty"`
+		HighestVersion        string  `json:"highest_version,omitempty"`
+		MarkedLatest          string  `json:"marked_latest,omitempty"`
+		LatestDiscrepancy     bool    `json:"latest_discrepancy"`
 		Status                Status  `json:"status"`
+		Degraded              bool    `json:"degraded"`
+		DriftScore            int     `json:"drift_score"`
 		*Alias
 	}{
 		LatestVersion:         a.LatestVersion.String(),
@@ -111,7 +359,14 @@ func (a *Analysis) MarshalJSON() ([]byte, error) {
 		ComparisonReleasedAt:  timeString(a.ComparisonReleasedAt),
 		FirstNewerVersion:     versionString(a.FirstNewerVersion),
 		FirstNewerReleaseDate: timeString(a.FirstNewerReleaseDate),
+		ExpiresAt:             timeString(a.ExpiryDate()),
+		MaintenanceWindow:     timeString(a.MaintenanceWindow),
+		HighestVersion:        versionString(a.HighestVersion),
+		MarkedLatest:          versionString(a.MarkedLatest),
+		LatestDiscrepancy:     a.LatestDiscrepancy(),
 		Status:                a.Status(),
+		Degraded:              a.IsDegraded(),
+		DriftScore:            a.DriftScore(),
 		Alias:                 (*Alias)(a),
 	}, "", "  ")
 }
@@ -121,6 +376,83 @@ type Config struct {
 	CriticalAgeDays int
 	MaxAgeDays      int
 	NoCache         bool // If true, bypass embedded cache and always fetch from API
+
+	// Releases used in place of the embedded cache, e.g. from a warmed cache file;
+	// checked against the most recent releases in the same way
+	CachedReleases []types.Release
+
+	// If true, analyse CachedReleases as they are without calling the API, e.g.
+	// on air-gapped networks; analyses fail when there are none
+	Offline bool
+
+	// How long a version found not to exist is remembered by the analysis cache,
+	// if it implements NotFoundCache; 0 never remembers
+	NotFoundTTL time.Duration
+
+	// Which release is the latest when GitHub's mark and the highest version
+	// differ; empty means LatestHighest
+	LatestPreference LatestPreference
+
+	// Which releases are newer than the comparison version; empty means OrderingSemver
+	Ordering Ordering
+
+	// Whether releases marked as prereleases can be the latest; empty means
+	// TrackStable. TrackPrerelease needs a client that keeps them.
+	Track Track
+
+	// The release channel checked, e.g. "beta" or "rc", for repositories that
+	// release parallel channels; empty follows Track. ChannelPatterns assign
+	// tags to channels, first match wins, before the prerelease part decides.
+	Channel         string
+	ChannelPatterns []ChannelPattern
+
+	// What a comparison version that is not a release results in; empty means
+	// UnknownVersionError. Fleets running custom builds can report them instead.
+	UnknownVersion UnknownVersionPolicy
+
+	// Clean-ups applied to the comparison version before parsing; zero applies none
+	Normalisation Normalisation
+
+	// Removed from the comparison version before parsing, so a fork's versions
+	// compare with upstream releases, e.g. regexp.MustCompile(`-corp\.\d+$`)
+	VersionSuffix *regexp.Regexp
+
+	// Timeline table (days-based policies); zero values use the defaults
+	TimelineWindowDays int // Show releases published within this many days
+	TimelineMinRows    int // Always show at least this many releases
+	TimelineMaxRows    int // Show at most this many releases, newest kept (0 = no cap)
+
+	// List at most this many newer releases in Analysis.NewerReleases, newest
+	// kept, so badly lagging versions stay readable; 0 lists them all
+	MaxNewer int
+
+	// Approved exemptions that downgrade an expired version to a warning until they lapse
+	Waivers []Waiver
+
+	// Known-bad releases, never recommended and reported when analysed
+	Yanked []YankedRelease
+
+	// Keywords looked for in the notes of newer releases, e.g. "security"; a
+	// match can raise the status, so an urgent fix outranks an older feature release
+	NoteKeywords []NoteKeyword
+
+	// Release note markers of breaking changes, which make an update need
+	// manual review; nil uses DefaultBreakingNotes
+	BreakingNotes *regexp.Regexp
+
+	// Platforms the recommended release must have an asset for, matched as part
+	// of the asset name, e.g. "linux-x64"; needs a client implementing AssetLister.
+	// Analyses are not cached while it is set, as assets can appear at any time.
+	RequiredAssets []string
+
+	// Platform the recommended release must have shipped for, as OS/ARCH, e.g.
+	// "linux/arm64". Recorded Release.Platforms are used where known, otherwise
+	// the assets are listed as for RequiredAssets.
+	Platform string
+
+	// How long a release must have been out before it is recommended, for teams
+	// that never adopt day-zero releases; 0 recommends the latest at once
+	MinReleaseAge time.Duration
 }
 
 // Validate checks if the configuration is valid
@@ -131,6 +463,60 @@ func (c Config) Validate() error {
 	if c.MaxAgeDays < 0 {
 		return fmt.Errorf("max_age_days must be non-negative")
 	}
+	if c.TimelineWindowDays < 0 {
+		return fmt.Errorf("timeline_window_days must be non-negative")
+	}
+	if c.TimelineMinRows < 0 {
+		return fmt.Errorf("timeline_min_rows must be non-negative")
+	}
+	if c.TimelineMaxRows < 0 {
+		return fmt.Errorf("timeline_max_rows must be non-negative")
+	}
+	if c.TimelineMaxRows > 0 && c.TimelineMinRows > c.TimelineMaxRows {
+		return fmt.Errorf("timeline_min_rows must not exceed timeline_max_rows")
+	}
+	if c.MaxNewer < 0 {
+		return fmt.Errorf("max_newer must be non-negative")
+	}
+	if c.Offline && c.NoCache {
+		return fmt.Errorf("offline and no_cache cannot both be set")
+	}
+	switch c.LatestPreference {
+	case "", LatestHighest, LatestMarked:
+	default:
+		return fmt.Errorf("invalid latest preference %q: must be %q or %q", c.LatestPreference, LatestHighest, LatestMarked)
+	}
+	switch c.Track {
+	case "", TrackStable, TrackPrerelease:
+	default:
+		return fmt.Errorf("invalid track %q: must be %q or %q", c.Track, TrackStable, TrackPrerelease)
+	}
+	if c.Channel != "" && c.Track == TrackPrerelease {
+		return fmt.Errorf("track %q and channel %q cannot both be set", c.Track, c.Channel)
+	}
+	switch c.UnknownVersion {
+	case "", UnknownVersionError, UnknownVersionExpired, UnknownVersionWarning:
+	default:
+		return fmt.Errorf("invalid unknown version policy %q: must be %q, %q or %q", c.UnknownVersion, UnknownVersionError, UnknownVersionExpired, UnknownVersionWarning)
+	}
+	switch c.Ordering {
+	case "", OrderingSemver, OrderingDate:
+	default:
+		return fmt.Errorf("invalid ordering %q: must be %q or %q", c.Ordering, OrderingSemver, OrderingDate)
+	}
+	if c.MinReleaseAge < 0 {
+		return fmt.Errorf("min_release_age must be non-negative")
+	}
+	if c.Platform != "" {
+		if _, err := types.ParsePlatform(c.Platform); err != nil {
+			return err
+		}
+	}
+	for _, k := range c.NoteKeywords {
+		if err := k.validate(); err != nil {
+			return err
+		}
+	}
 	// Skip validation if both are 0 (indicates version-based policy)
 	if c.MaxAgeDays > 0 && c.CriticalAgeDays >= c.MaxAgeDays {
 		return fmt.Errorf("critical_age_days must be less than max_age_days")
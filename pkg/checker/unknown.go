@@ -0,0 +1,55 @@
+package checker
+
+import (
+	"fmt"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+// failsUnknown reports whether a comparison version that is not a release is
+// a VersionNotFoundError rather than an analysis
+func (c *Checker) failsUnknown() bool {
+	return c.config.UnknownVersion == "" || c.config.UnknownVersion == UnknownVersionError
+}
+
+// unknownVersionAnalysis reports a comparison version that is not a release,
+// such as a custom build, as Config.UnknownVersion says: expired, or a warning
+// counting the releases above it
+func (c *Checker) unknownVersionAnalysis(comparisonVersion *semver.Version, allReleases []types.Release, latestRelease types.Release,
+	candidates []types.Release, highestVersion, markedVersion *semver.Version, degraded []DegradedReason) *Analysis {
+	newerReleases := c.findNewerReleases(candidates, comparisonVersion)
+	analysis := &Analysis{
+		LatestVersion:     latestRelease.Version,
+		ComparisonVersion: comparisonVersion,
+		UnknownVersion:    true,
+		IsExpired:         c.config.UnknownVersion == UnknownVersionExpired,
+		ReleasesBehind:    len(newerReleases),
+		NewerReleases:     newerReleases,
+		CriticalAgeDays:   c.criticalAgeDays(),
+		MaxAgeDays:        c.maxAgeDays(),
+		HighestVersion:    highestVersion,
+		MarkedLatest:      markedVersion,
+		Ordering:          c.config.Ordering,
+		DegradedReasons:   degraded,
+	}
+	countBehind(analysis)
+	if c.policy != nil {
+		analysis.PolicyType = c.policy.Type()
+	}
+	if len(newerReleases) > 0 {
+		analysis.FirstNewerVersion = newerReleases[0].Version
+		analysis.FirstNewerReleaseDate = &newerReleases[0].PublishedAt
+	}
+	analysis.RecentReleases = c.CalculateRecentReleases(allReleases, latestRelease.Version, latestRelease.Version)
+
+	prefix := "Warning"
+	if analysis.IsExpired {
+		prefix = "EXPIRED"
+	}
+	analysis.Message = fmt.Sprintf("Version %s %s: not a release (latest: %s)", types.FormatVersion(comparisonVersion), prefix, types.FormatVersion(latestRelease.Version))
+	if analysis.ReleasesBehind > 0 {
+		analysis.Message += fmt.Sprintf(" AND %d release%s behind", analysis.ReleasesBehind, types.PluralSuffix(analysis.ReleasesBehind))
+	}
+	return analysis
+}
@@ -0,0 +1,67 @@
+package checker
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+func TestAnalyse_UnknownVersion(t *testing.T) {
+	latest := newTestRelease("2.329.0", 3)
+	releases := []types.Release{latest, newTestRelease("2.328.0", 60), newTestRelease("2.327.1", 80)}
+	client := &MockGitHubClient{LatestRelease: &latest, AllReleases: releases}
+
+	tests := []struct {
+		name        string
+		policy      UnknownVersionPolicy
+		version     string
+		wantErr     bool
+		wantStatus  Status
+		wantBehind  int
+		wantMessage string
+	}{
+		{name: "default fails", version: "2.328.5", wantErr: true},
+		{name: "error fails", policy: UnknownVersionError, version: "2.328.5", wantErr: true},
+		{name: "expired", policy: UnknownVersionExpired, version: "2.328.5", wantStatus: StatusExpired, wantBehind: 1, wantMessage: "EXPIRED: not a release (latest: 2.329.0) AND 1 release behind"},
+		{name: "warning", policy: UnknownVersionWarning, version: "2.328.5", wantStatus: StatusWarning, wantBehind: 1, wantMessage: "Warning: not a release"},
+		{name: "warning above the latest", policy: UnknownVersionWarning, version: "2.330.0", wantStatus: StatusWarning, wantMessage: "Warning: not a release (latest: 2.329.0)"},
+		{name: "releases are unaffected", policy: UnknownVersionExpired, version: "2.329.0", wantStatus: StatusCurrent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := NewChecker(client, Config{CriticalAgeDays: 12, MaxAgeDays: 30, NoCache: true, UnknownVersion: tt.policy})
+			analysis, err := checker.Analyse(context.Background(), tt.version)
+			if tt.wantErr {
+				var notFound *VersionNotFoundError
+				if !errors.As(err, &notFound) {
+					t.Fatalf("Analyse() error = %v, want a VersionNotFoundError", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := analysis.Status(); got != tt.wantStatus {
+				t.Errorf("Status() = %s, want %s", got, tt.wantStatus)
+			}
+			if analysis.ReleasesBehind != tt.wantBehind {
+				t.Errorf("ReleasesBehind = %d, want %d", analysis.ReleasesBehind, tt.wantBehind)
+			}
+			if analysis.UnknownVersion != (tt.wantMessage != "") {
+				t.Errorf("UnknownVersion = %t, want %t", analysis.UnknownVersion, tt.wantMessage != "")
+			}
+			if !strings.Contains(analysis.Message, tt.wantMessage) {
+				t.Errorf("Message = %q, want containing %q", analysis.Message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestConfig_ValidateUnknownVersion(t *testing.T) {
+	if err := (Config{UnknownVersion: "ignore"}).Validate(); err == nil {
+		t.Error("Validate() accepted unknown version policy \"ignore\"")
+	}
+}
@@ -0,0 +1,88 @@
+package checker
+
+import (
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+// Waiver is an approved exemption that lets a version run past its expiry:
+// while it is active an expired version is reported as a warning instead.
+// A rollback waiver instead names a newer release upstream has announced it is
+// rolling back, which is set aside while the waiver is active so it does not
+// escalate the status of versions before it.
+type Waiver struct {
+	Version    *semver.Version `json:"version"`
+	Expires    time.Time       `json:"expires"` // The waiver lapses at this time
+	Reason     string          `json:"reason"`
+	ApprovedBy string          `json:"approved_by"`
+	Rollback   bool            `json:"rollback,omitempty"` // Version is a release being rolled back upstream
+}
+
+// Active reports whether the waiver still applies at now
+func (w *Waiver) Active(now time.Time) bool {
+	return now.Before(w.Expires)
+}
+
+// findWaiver returns the waiver for version, preferring the one that lasts longest
+func findWaiver(waivers []Waiver, version *semver.Version) *Waiver {
+	var found *Waiver
+	for i := range waivers {
+		w := &waivers[i]
+		if w.Rollback || types.CompareVersions(w.Version, version) != 0 {
+			continue
+		}
+		if found == nil || w.Expires.After(found.Expires) {
+			found = w
+		}
+	}
+	return found
+}
+
+// applyWaiver records any waiver for the analysed version and, while it is
+// active, downgrades an expired version to a warning
+func applyWaiver(analysis *Analysis, waivers []Waiver, now time.Time) {
+	w := findWaiver(waivers, analysis.ComparisonVersion)
+	if w == nil {
+		return
+	}
+	waiver := *w
+	analysis.Waiver = &waiver
+	if analysis.IsExpired && waiver.Active(now) {
+		analysis.IsExpired = false
+		analysis.IsCritical = false
+		analysis.Waived = true
+	}
+}
+
+// setAsideRollbacks returns candidates without the releases of rollback
+// waivers active at now, and those waivers. Nothing is set aside when it would
+// leave no releases.
+func setAsideRollbacks(candidates []types.Release, waivers []Waiver, now time.Time) ([]types.Release, []Waiver) {
+	var active []Waiver
+	kept := make([]types.Release, 0, len(candidates))
+	for _, r := range candidates {
+		w := findRollback(waivers, r.Version, now)
+		if w == nil {
+			kept = append(kept, r)
+		} else {
+			active = append(active, *w)
+		}
+	}
+	if len(active) == 0 || len(kept) == 0 {
+		return candidates, nil
+	}
+	return kept, active
+}
+
+// findRollback returns the active rollback waiver for version, or nil
+func findRollback(waivers []Waiver, version *semver.Version, now time.Time) *Waiver {
+	for i := range waivers {
+		w := &waivers[i]
+		if w.Rollback && w.Active(now) && types.CompareVersions(w.Version, version) == 0 {
+			return w
+		}
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package checker
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+func TestAnalyse_Waiver(t *testing.T) {
+	latest := newTestRelease("1.1.0", 40)
+	releases := []types.Release{latest, newTestRelease("1.0.0", 60)}
+	client := &MockGitHubClient{LatestRelease: &latest, AllReleases: releases}
+
+	tests := []struct {
+		name        string
+		waivers     []Waiver
+		wantExpired bool
+		wantWaived  bool
+		wantWaiver  bool
+	}{
+		{
+			name:        "no waiver",
+			wantExpired: true,
+		},
+		{
+			name:       "active waiver",
+			waivers:    []Waiver{{Version: semver.MustParse("1.0.0"), Expires: time.Now().AddDate(0, 0, 7), Reason: "freeze", ApprovedBy: "ops"}},
+			wantWaived: true,
+			wantWaiver: true,
+		},
+		{
+			name:        "lapsed waiver",
+			waivers:     []Waiver{{Version: semver.MustParse("1.0.0"), Expires: time.Now().AddDate(0, 0, -1), Reason: "freeze", ApprovedBy: "ops"}},
+			wantExpired: true,
+			wantWaiver:  true,
+		},
+		{
+			name:        "other version",
+			waivers:     []Waiver{{Version: semver.MustParse("0.9.0"), Expires: time.Now().AddDate(0, 0, 7), Reason: "freeze", ApprovedBy: "ops"}},
+			wantExpired: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := NewChecker(client, Config{CriticalAgeDays: 12, MaxAgeDays: 30, NoCache: true, Waivers: tt.waivers})
+			analysis, err := checker.Analyse(context.Background(), "1.0.0")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if analysis.IsExpired != tt.wantExpired || analysis.Waived != tt.wantWaived {
+				t.Errorf("IsExpired, Waived = %t, %t, want %t, %t", analysis.IsExpired, analysis.Waived, tt.wantExpired, tt.wantWaived)
+			}
+			if (analysis.Waiver != nil) != tt.wantWaiver {
+				t.Errorf("Waiver = %+v, want present %t", analysis.Waiver, tt.wantWaiver)
+			}
+			if tt.wantWaived {
+				if analysis.Status() != StatusWarning {
+					t.Errorf("Status = %s, want warning", analysis.Status())
+				}
+				if !strings.Contains(analysis.Message, "expiry waived until") {
+					t.Errorf("Message = %q", analysis.Message)
+				}
+			}
+		})
+	}
+}
+
+func TestFindWaiver_PrefersLongest(t *testing.T) {
+	now := time.Now()
+	waivers := []Waiver{
+		{Version: semver.MustParse("1.0.0"), Expires: now.AddDate(0, 0, 7)},
+		{Version: semver.MustParse("v1.0.0"), Expires: now.AddDate(0, 0, 30)},
+		{Version: semver.MustParse("1.0.1"), Expires: now.AddDate(0, 0, 90)},
+	}
+	got := findWaiver(waivers, semver.MustParse("1.0.0"))
+	if got == nil || !got.Expires.Equal(waivers[1].Expires) {
+		t.Errorf("findWaiver = %+v, want the 30-day waiver", got)
+	}
+}
+
+func TestAnalyse_RollbackWaiver(t *testing.T) {
+	latest := newTestRelease("1.2.0", 40)
+	releases := []types.Release{latest, newTestRelease("1.1.0", 50), newTestRelease("1.0.0", 60)}
+	client := &MockGitHubClient{LatestRelease: &latest, AllReleases: releases}
+	rollback := func(version string, days int) Waiver {
+		return Waiver{Version: semver.MustParse(version), Expires: time.Now().AddDate(0, 0, days), Reason: "upstream rollback", ApprovedBy: "ops", Rollback: true}
+	}
+
+	tests := []struct {
+		name           string
+		waivers        []Waiver
+		version        string
+		wantLatest     string
+		wantStatus     Status
+		wantRolledBack int
+	}{
+		{name: "no waiver", version: "1.1.0", wantLatest: "1.2.0", wantStatus: StatusExpired},
+		{name: "release set aside", waivers: []Waiver{rollback("1.2.0", 7)}, version: "1.1.0", wantLatest: "1.1.0", wantStatus: StatusCurrent, wantRolledBack: 1},
+		{name: "still behind the rest", waivers: []Waiver{rollback("1.2.0", 7)}, version: "1.0.0", wantLatest: "1.1.0", wantStatus: StatusExpired, wantRolledBack: 1},
+		{name: "lapsed", waivers: []Waiver{rollback("1.2.0", -1)}, version: "1.1.0", wantLatest: "1.2.0", wantStatus: StatusExpired},
+		{name: "running the rolled-back release", waivers: []Waiver{rollback("1.2.0", 7)}, version: "1.2.0", wantLatest: "1.1.0", wantStatus: StatusCurrent, wantRolledBack: 1},
+		{name: "never sets aside every release", waivers: []Waiver{rollback("1.2.0", 7), rollback("1.1.0", 7), rollback("1.0.0", 7)}, version: "1.1.0", wantLatest: "1.2.0", wantStatus: StatusExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := NewChecker(client, Config{CriticalAgeDays: 12, MaxAgeDays: 30, NoCache: true, Waivers: tt.waivers})
+			analysis, err := checker.Analyse(context.Background(), tt.version)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := analysis.LatestVersion.String(); got != tt.wantLatest {
+				t.Errorf("LatestVersion = %s, want %s", got, tt.wantLatest)
+			}
+			if got := analysis.Status(); got != tt.wantStatus {
+				t.Errorf("Status() = %s, want %s", got, tt.wantStatus)
+			}
+			if len(analysis.RolledBack) != tt.wantRolledBack {
+				t.Errorf("RolledBack = %v, want %d", analysis.RolledBack, tt.wantRolledBack)
+			}
+			if analysis.Waiver != nil {
+				t.Errorf("Waiver = %+v, want nil: rollback waivers do not exempt the version in use", analysis.Waiver)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package checker
+
+import (
+	"github.com/Masterminds/semver/v3"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+// YankedRelease is a known-bad release, e.g. one with a regression: it is never
+// recommended, and running it is reported as a warning
+type YankedRelease struct {
+	Version *semver.Version `json:"version"`
+	Reason  string          `json:"reason"`
+}
+
+// findYanked returns the entry for version, or nil if it is not yanked
+func findYanked(yanked []YankedRelease, version *semver.Version) *YankedRelease {
+	if version == nil {
+		return nil
+	}
+	for i := range yanked {
+		if types.CompareVersions(yanked[i].Version, version) == 0 {
+			entry := yanked[i]
+			return &entry
+		}
+	}
+	return nil
+}
+
+// applyYanked flags a yanked comparison version and, when the latest release
+// is yanked, recommends the highest release up to it that is not. Prereleases
+// are only recommended when the latest release is one.
+func applyYanked(analysis *Analysis, yanked []YankedRelease, candidates []types.Release) {
+	if len(yanked) == 0 {
+		return
+	}
+	analysis.Yanked = findYanked(yanked, analysis.ComparisonVersion)
+	if findYanked(yanked, analysis.LatestVersion) == nil {
+		return
+	}
+
+	allowPrerelease := analysis.LatestVersion.Prerelease() != ""
+	var best *semver.Version
+	for _, r := range candidates {
+		if types.CompareVersions(r.Version, analysis.LatestVersion) > 0 || findYanked(yanked, r.Version) != nil {
+			continue
+		}
+		if r.Version.Prerelease() != "" && !allowPrerelease {
+			continue
+		}
+		if best == nil || types.CompareVersions(r.Version, best) > 0 {
+			best = r.Version
+		}
+	}
+	analysis.RecommendedVersion = best
+}
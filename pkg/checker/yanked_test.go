@@ -0,0 +1,60 @@
+package checker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+func TestAnalyse_Yanked(t *testing.T) {
+	latest := newTestRelease("2.329.0", 3)
+	releases := []types.Release{
+		latest,
+		newTestRelease("2.329.0-rc.1", 8),
+		newTestRelease("2.328.0", 60),
+		newTestRelease("2.327.1", 80),
+	}
+	client := &MockGitHubClient{LatestRelease: &latest, AllReleases: releases}
+	yanked := func(versions ...string) []YankedRelease {
+		var list []YankedRelease
+		for _, v := range versions {
+			list = append(list, YankedRelease{Version: semver.MustParse(v), Reason: "regression"})
+		}
+		return list
+	}
+
+	tests := []struct {
+		name            string
+		yanked          []YankedRelease
+		version         string
+		wantYanked      bool
+		wantRecommended string
+		wantStatus      Status
+	}{
+		{name: "nothing yanked", version: "2.329.0", wantRecommended: "2.329.0", wantStatus: StatusCurrent},
+		{name: "latest yanked", yanked: yanked("2.329.0"), version: "2.329.0", wantYanked: true, wantRecommended: "2.328.0", wantStatus: StatusWarning},
+		{name: "behind a yanked latest", yanked: yanked("2.329.0"), version: "2.327.1", wantRecommended: "2.328.0", wantStatus: StatusExpired},
+		{name: "comparison yanked", yanked: yanked("2.327.1"), version: "2.327.1", wantYanked: true, wantRecommended: "2.329.0", wantStatus: StatusExpired},
+		{name: "everything yanked", yanked: yanked("2.329.0", "2.328.0", "2.327.1"), version: "2.328.0", wantYanked: true, wantRecommended: "2.329.0", wantStatus: StatusWarning},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := NewChecker(client, Config{CriticalAgeDays: 12, MaxAgeDays: 30, NoCache: true, Yanked: tt.yanked})
+			analysis, err := checker.Analyse(context.Background(), tt.version)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if (analysis.Yanked != nil) != tt.wantYanked {
+				t.Errorf("Yanked = %+v, want present %t", analysis.Yanked, tt.wantYanked)
+			}
+			if got := analysis.Recommended().String(); got != tt.wantRecommended {
+				t.Errorf("Recommended() = %s, want %s", got, tt.wantRecommended)
+			}
+			if got := analysis.Status(); got != tt.wantStatus {
+				t.Errorf("Status() = %s, want %s", got, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Masterminds/semver/v3"
+	gh "github.com/google/go-github/v57/github"
+)
+
+// ErrReleaseNotFound is returned when no release has the version's tag
+var ErrReleaseNotFound = errors.New("release not found")
+
+// GetReleaseAssets returns the names of the release's uploaded assets, leaving
+// out any still being uploaded. The release's tag is looked up with and
+// without a "v" prefix.
+func (c *Client) GetReleaseAssets(ctx context.Context, version *semver.Version) ([]string, error) {
+	for _, tag := range releaseTags(version) {
+		release, resp, err := c.gh.Repositories.GetReleaseByTag(ctx, c.Owner, c.Repo, tag)
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get release %s: %w", tag, err)
+		}
+		return uploadedAssets(release), nil
+	}
+	return nil, fmt.Errorf("failed to get release %s: %w", version, ErrReleaseNotFound)
+}
+
+// uploadedAssets returns the names of the release's assets, leaving out any
+// still being uploaded
+func uploadedAssets(release *gh.RepositoryRelease) []string {
+	names := []string{}
+	for _, asset := range release.Assets {
+		if state := asset.GetState(); state == "" || state == "uploaded" {
+			names = append(names, asset.GetName())
+		}
+	}
+	return names
+}
+
+// releaseTags returns the tags a release of version may have, as published
+// first: e.g. v2.329.0, then 2.329.0
+func releaseTags(version *semver.Version) []string {
+	tags := []string{version.Original()}
+	for _, tag := range []string{"v" + version.String(), version.String()} {
+		if tag != tags[0] {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
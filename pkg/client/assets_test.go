@@ -0,0 +1,49 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// TestGetReleaseAssets tests that uploaded assets are listed, trying tags with and without a v prefix
+func TestGetReleaseAssets(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/owner/repo/releases/tags/v2.329.0":
+			fmt.Fprint(w, `{"tag_name":"v2.329.0","assets":[
+				{"name":"actions-runner-linux-x64-2.329.0.tar.gz","state":"uploaded"},
+				{"name":"actions-runner-win-x64-2.329.0.zip","state":"open"}]}`)
+		case "/repos/owner/repo/releases/tags/1.4.0":
+			fmt.Fprint(w, `{"tag_name":"1.4.0","assets":[]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	tests := []struct {
+		version string
+		want    string
+		wantErr error
+	}{
+		{version: "2.329.0", want: "actions-runner-linux-x64-2.329.0.tar.gz"},
+		{version: "1.4.0", want: ""},
+		{version: "9.9.9", wantErr: ErrReleaseNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			names, err := client.GetReleaseAssets(context.Background(), semver.MustParse(tt.version))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetReleaseAssets() error = %v, want %v", err, tt.wantErr)
+			}
+			if got := strings.Join(names, ","); err == nil && got != tt.want {
+				t.Errorf("GetReleaseAssets() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	gh "github.com/google/go-github/v57/github"
+)
+
+// ErrBadCredentials is returned when GitHub rejects the token
+var ErrBadCredentials = errors.New("bad credentials")
+
+// AuthStatus describes the token and rate limit as reported by the GitHub API
+type AuthStatus struct {
+	Scopes         []string  // OAuth scopes granted to a classic token
+	ScopesReported bool      // False for fine-grained, app and Actions tokens, which report no scopes
+	Remaining      int       // Core API requests remaining
+	Limit          int       // Core API requests allowed per hour
+	Reset          time.Time // When the core rate limit resets
+}
+
+// GetAuthStatus checks the token and core rate limit; the rate limit endpoint is
+// not itself rate limited, so this is safe to call when the limit is exhausted
+func (c *Client) GetAuthStatus(ctx context.Context) (*AuthStatus, error) {
+	limits, resp, err := c.gh.RateLimit.Get(ctx)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusUnauthorized {
+			return nil, fmt.Errorf("failed to get rate limit: %w", ErrBadCredentials)
+		}
+		return nil, fmt.Errorf("failed to get rate limit: %w", err)
+	}
+
+	status := &AuthStatus{}
+	if limits != nil && limits.Core != nil {
+		status.Remaining = limits.Core.Remaining
+		status.Limit = limits.Core.Limit
+		status.Reset = limits.Core.Reset.Time
+	}
+
+	if header, ok := resp.Header["X-Oauth-Scopes"]; ok {
+		status.ScopesReported = true
+		for _, scope := range strings.Split(strings.Join(header, ","), ",") {
+			if scope = strings.TrimSpace(scope); scope != "" {
+				status.Scopes = append(status.Scopes, scope)
+			}
+		}
+	}
+
+	return status, nil
+}
+
+// ErrNoAccess is returned when the token is valid but may not read the repository
+var ErrNoAccess = errors.New("token lacks access to the repository")
+
+// ErrNotFound is returned when the repository does not exist or is hidden from the token
+var ErrNotFound = errors.New("repository not found")
+
+// CheckAccess confirms the token can read the repository, so permission problems
+// surface as a clear error before any release is fetched
+func (c *Client) CheckAccess(ctx context.Context) error {
+	_, resp, err := c.gh.Repositories.Get(ctx, c.Owner, c.Repo)
+	if err == nil {
+		return nil
+	}
+	if resp == nil {
+		return fmt.Errorf("failed to check repository access: %w", err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusUnauthorized:
+		return fmt.Errorf("failed to check repository access: %w", ErrBadCredentials)
+	case http.StatusForbidden:
+		var rateErr *gh.RateLimitError
+		var abuseErr *gh.AbuseRateLimitError
+		if errors.As(err, &rateErr) || errors.As(err, &abuseErr) {
+			return fmt.Errorf("failed to check repository access: %w", err) // Rate limited, not a permission problem
+		}
+		var ghErr *gh.ErrorResponse
+		if errors.As(err, &ghErr) && ghErr.Message != "" {
+			return fmt.Errorf("%w: %s", ErrNoAccess, ghErr.Message)
+		}
+		return ErrNoAccess
+	case http.StatusNotFound:
+		return ErrNotFound
+	default:
+		return fmt.Errorf("failed to check repository access: %w", err)
+	}
+}
+
+// RateLimitDetails describes a rate-limit failure
+type RateLimitDetails struct {
+	Limit      int           // Requests allowed per hour; 0 if unknown
+	Remaining  int           // Requests left when the failure was reported
+	Reset      time.Time     // When the limit resets; zero if unknown
+	RetryAfter time.Duration // Pause requested by a secondary rate limit; 0 if none
+	Secondary  bool          // A secondary (abuse) limit rather than the hourly one
+}
+
+// RateLimitInfo returns the details of a rate-limit failure, reporting false if
+// err does not come from a rate limit
+func RateLimitInfo(err error) (*RateLimitDetails, bool) {
+	var rateErr *gh.RateLimitError
+	var abuseErr *gh.AbuseRateLimitError
+	var exhaustedErr *RateLimitExhaustedError
+	switch {
+	case errors.As(err, &rateErr):
+		return &RateLimitDetails{
+			Limit:     rateErr.Rate.Limit,
+			Remaining: rateErr.Rate.Remaining,
+			Reset:     rateErr.Rate.Reset.Time,
+		}, true
+	case errors.As(err, &abuseErr):
+		details := &RateLimitDetails{Secondary: true}
+		if abuseErr.RetryAfter != nil {
+			details.RetryAfter = *abuseErr.RetryAfter
+		}
+		return details, true
+	case errors.As(err, &exhaustedErr):
+		return &RateLimitDetails{Limit: exhaustedErr.Limit, Reset: exhaustedErr.Reset}, true
+	case errors.Is(err, ErrRateLimitExhausted):
+		return &RateLimitDetails{}, true
+	default:
+		return nil, false
+	}
+}
+
+// IsRateLimited reports whether err comes from GitHub's primary or secondary rate
+// limits, or from a shared budget that has run out
+func IsRateLimited(err error) bool {
+	_, ok := RateLimitInfo(err)
+	return ok
+}
@@ -0,0 +1,200 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	gh "github.com/google/go-github/v57/github"
+)
+
+// newTestClient returns a client pointed at a test server
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client := NewClient("", "owner", "repo")
+	baseURL, _ := url.Parse(server.URL + "/")
+	client.gh.BaseURL = baseURL
+	return client
+}
+
+// TestGetAuthStatus tests token and rate limit reporting
+func TestGetAuthStatus(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-OAuth-Scopes", "repo, read:org")
+		fmt.Fprint(w, `{"resources":{"core":{"limit":5000,"remaining":4321,"reset":1760000000}}}`)
+	})
+
+	status, err := client.GetAuthStatus(context.Background())
+	if err != nil {
+		t.Fatalf("GetAuthStatus() error = %v", err)
+	}
+	if status.Remaining != 4321 || status.Limit != 5000 {
+		t.Errorf("rate limit = %d/%d, want 4321/5000", status.Remaining, status.Limit)
+	}
+	if !status.ScopesReported || len(status.Scopes) != 2 || status.Scopes[1] != "read:org" {
+		t.Errorf("scopes = %v (reported %v), want [repo read:org]", status.Scopes, status.ScopesReported)
+	}
+}
+
+// TestGetAuthStatus_BadCredentials tests that a rejected token is reported
+func TestGetAuthStatus_BadCredentials(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"message":"Bad credentials"}`)
+	})
+
+	_, err := client.GetAuthStatus(context.Background())
+	if !errors.Is(err, ErrBadCredentials) {
+		t.Errorf("expected ErrBadCredentials, got %v", err)
+	}
+}
+
+// TestCheckAccess tests mapping of repository access failures
+func TestCheckAccess(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr error
+	}{
+		{name: "readable", status: http.StatusOK, body: `{"full_name":"owner/repo"}`},
+		{name: "bad credentials", status: http.StatusUnauthorized, body: `{"message":"Bad credentials"}`, wantErr: ErrBadCredentials},
+		{name: "fine-grained token without access", status: http.StatusForbidden, body: `{"message":"Resource not accessible by personal access token"}`, wantErr: ErrNoAccess},
+		{name: "hidden or missing", status: http.StatusNotFound, body: `{"message":"Not Found"}`, wantErr: ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, tt.body)
+			})
+
+			err := client.CheckAccess(context.Background())
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("CheckAccess() error = %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("CheckAccess() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestRequestHeaders tests the custom User-Agent and extra headers reach the server
+func TestRequestHeaders(t *testing.T) {
+	var got http.Header
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		fmt.Fprint(w, `{"resources":{"core":{"limit":60,"remaining":60,"reset":1760000000}}}`)
+	})
+	client.UserAgent = "release-checker/1.2.3"
+	client.Headers = http.Header{"X-Proxy-Team": []string{"platform"}}
+
+	if _, err := client.GetAuthStatus(context.Background()); err != nil {
+		t.Fatalf("GetAuthStatus() error = %v", err)
+	}
+	if ua := got.Get("User-Agent"); ua != "release-checker/1.2.3" {
+		t.Errorf("User-Agent = %q, want release-checker/1.2.3", ua)
+	}
+	if v := got.Get("X-Proxy-Team"); v != "platform" {
+		t.Errorf("X-Proxy-Team = %q, want platform", v)
+	}
+}
+
+// TestInstrument tests the instrumentation callback sees each request and its outcome
+func TestInstrument(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"resources":{"core":{"limit":60,"remaining":60,"reset":1760000000}}}`)
+	})
+	var paths []string
+	var statuses []int
+	client.Instrument = func(req *http.Request) func(*http.Response, error) {
+		paths = append(paths, req.URL.Path)
+		return func(resp *http.Response, err error) {
+			if err != nil {
+				t.Errorf("unexpected request error: %v", err)
+				return
+			}
+			statuses = append(statuses, resp.StatusCode)
+		}
+	}
+
+	if _, err := client.GetAuthStatus(context.Background()); err != nil {
+		t.Fatalf("GetAuthStatus() error = %v", err)
+	}
+	if len(paths) != 1 || paths[0] != "/rate_limit" || len(statuses) != 1 || statuses[0] != http.StatusOK {
+		t.Errorf("instrumented paths %v with statuses %v, want [/rate_limit] and [200]", paths, statuses)
+	}
+}
+
+// TestIsRateLimited tests rate limit detection through wrapping
+func TestIsRateLimited(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "primary", err: fmt.Errorf("failed to list releases: %w", &gh.RateLimitError{Message: "API rate limit exceeded"}), want: true},
+		{name: "secondary", err: &gh.AbuseRateLimitError{Message: "secondary rate limit"}, want: true},
+		{name: "shared budget", err: &url.Error{Op: "Get", URL: "https://api.github.com", Err: ErrRateLimitExhausted}, want: true},
+		{name: "other", err: ErrNotFound, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRateLimited(tt.err); got != tt.want {
+				t.Errorf("IsRateLimited() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestRateLimitInfo tests the details extracted from each kind of rate-limit error
+func TestRateLimitInfo(t *testing.T) {
+	reset := time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC)
+	retryAfter := 90 * time.Second
+	tests := []struct {
+		name string
+		err  error
+		want RateLimitDetails
+	}{
+		{
+			name: "primary",
+			err:  fmt.Errorf("failed to list releases: %w", &gh.RateLimitError{Rate: gh.Rate{Limit: 60, Remaining: 0, Reset: gh.Timestamp{Time: reset}}}),
+			want: RateLimitDetails{Limit: 60, Reset: reset},
+		},
+		{
+			name: "secondary",
+			err:  &gh.AbuseRateLimitError{RetryAfter: &retryAfter},
+			want: RateLimitDetails{RetryAfter: retryAfter, Secondary: true},
+		},
+		{
+			name: "shared budget",
+			err:  &url.Error{Op: "Get", URL: "https://api.github.com", Err: &RateLimitExhaustedError{Limit: 5000, Reset: reset}},
+			want: RateLimitDetails{Limit: 5000, Reset: reset},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := RateLimitInfo(tt.err)
+			if !ok {
+				t.Fatal("RateLimitInfo() reported no rate limit")
+			}
+			if *got != tt.want {
+				t.Errorf("RateLimitInfo() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+
+	if _, ok := RateLimitInfo(ErrNotFound); ok {
+		t.Error("RateLimitInfo(ErrNotFound) reported a rate limit")
+	}
+}
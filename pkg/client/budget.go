@@ -0,0 +1,112 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrRateLimitExhausted is returned without contacting GitHub once a shared
+// budget has seen the rate limit run out
+var ErrRateLimitExhausted = errors.New("rate limit exhausted")
+
+// RateLimitExhaustedError is the ErrRateLimitExhausted returned by a budget,
+// with the limit and reset time it last saw
+type RateLimitExhaustedError struct {
+	Limit   int
+	Reset   time.Time
+	ResetIn time.Duration // Time to the reset when the request was refused
+}
+
+func (e *RateLimitExhaustedError) Error() string {
+	return fmt.Sprintf("%s: rate reset in %s", ErrRateLimitExhausted, e.ResetIn.Round(time.Second))
+}
+
+func (e *RateLimitExhaustedError) Is(target error) bool {
+	return target == ErrRateLimitExhausted
+}
+
+// RateBudget shares rate-limit accounting between clients, so concurrent checks
+// of several repositories stop together when the limit runs out and all back
+// off when GitHub asks for a pause (secondary rate limits)
+type RateBudget struct {
+	mu         sync.Mutex
+	known      bool
+	remaining  int
+	limit      int
+	reset      time.Time
+	pauseUntil time.Time
+}
+
+// NewRateBudget creates an empty budget, filled in from response headers
+func NewRateBudget() *RateBudget {
+	return &RateBudget{}
+}
+
+// Remaining returns the requests left and the limit, as last reported by GitHub
+func (b *RateBudget) Remaining() (remaining, limit int, known bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.remaining, b.limit, b.known
+}
+
+// wait blocks while GitHub has asked clients to pause, and fails fast while the
+// rate limit is exhausted
+func (b *RateBudget) wait(ctx context.Context) error {
+	b.mu.Lock()
+	now := time.Now()
+	if b.known && b.remaining <= 0 && now.Before(b.reset) {
+		err := &RateLimitExhaustedError{Limit: b.limit, Reset: b.reset, ResetIn: b.reset.Sub(now)}
+		b.mu.Unlock()
+		return err
+	}
+	pause := b.pauseUntil.Sub(now)
+	b.mu.Unlock()
+
+	if pause <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(pause)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// observe records the rate-limit headers of a response
+func (b *RateBudget) observe(resp *http.Response, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// Secondary rate limits ask for a pause with Retry-After
+	if resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+			if until := now.Add(time.Duration(seconds) * time.Second); until.After(b.pauseUntil) {
+				b.pauseUntil = until
+			}
+		}
+	}
+
+	// Only the core limit is shared by release and repository requests
+	if resource := resp.Header.Get("X-RateLimit-Resource"); resource != "" && resource != "core" {
+		return
+	}
+	remaining, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	limit, _ := strconv.Atoi(resp.Header.Get("X-RateLimit-Limit"))
+	reset, _ := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+
+	b.known = true
+	b.remaining = remaining
+	b.limit = limit
+	b.reset = time.Unix(reset, 0)
+}
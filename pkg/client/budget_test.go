@@ -0,0 +1,67 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// TestRateBudgetShared tests that an exhausted limit seen by one client stops another
+func TestRateBudgetShared(t *testing.T) {
+	reset := time.Now().Add(time.Hour).Unix()
+	requests := 0
+	budget := NewRateBudget()
+
+	first := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("X-RateLimit-Resource", "core")
+		w.Header().Set("X-RateLimit-Limit", "60")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset, 10))
+		fmt.Fprint(w, `{"full_name":"owner/repo"}`)
+	})
+	first.Budget = budget
+	second := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, `{"full_name":"owner/repo"}`)
+	})
+	second.Budget = budget
+
+	if err := first.CheckAccess(context.Background()); err != nil {
+		t.Fatalf("first CheckAccess() error = %v", err)
+	}
+	if remaining, limit, known := budget.Remaining(); !known || remaining != 0 || limit != 60 {
+		t.Errorf("Remaining() = %d/%d (known %v), want 0/60", remaining, limit, known)
+	}
+
+	err := second.CheckAccess(context.Background())
+	if !errors.Is(err, ErrRateLimitExhausted) {
+		t.Errorf("second CheckAccess() error = %v, want ErrRateLimitExhausted", err)
+	}
+	if details, ok := RateLimitInfo(err); !ok || details.Limit != 60 || details.Reset.Unix() != reset {
+		t.Errorf("RateLimitInfo() = %+v, want limit 60 and the reset from the first response", details)
+	}
+	if requests != 1 {
+		t.Errorf("server saw %d requests, want 1", requests)
+	}
+}
+
+// TestRateBudgetRetryAfter tests that a secondary rate limit pauses later requests
+func TestRateBudgetRetryAfter(t *testing.T) {
+	budget := NewRateBudget()
+	now := time.Now()
+	budget.observe(&http.Response{
+		StatusCode: http.StatusForbidden,
+		Header:     http.Header{"Retry-After": {"30"}},
+	}, now)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := budget.wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("wait() error = %v, want to block until the deadline", err)
+	}
+}
@@ -3,9 +3,15 @@ package client
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
 
-	"github.com/Masterminds/semver/v3"
 	gh "github.com/google/go-github/v57/github"
+	"github.com/nickromney-org/github-release-version-checker/pkg/releaseset"
 	"github.com/nickromney-org/github-release-version-checker/pkg/types"
 	"golang.org/x/oauth2"
 )
@@ -15,27 +21,162 @@ type Client struct {
 	gh    *gh.Client
 	Owner string
 	Repo  string
+
+	// Progress, if set, is called after each page fetched by GetAllReleases
+	Progress ProgressFunc
+
+	// Logger, if set, traces each HTTP request and skipped release at debug level
+	Logger *slog.Logger
+
+	// UserAgent, if set, replaces the go-github User-Agent on every request
+	UserAgent string
+
+	// Headers are added to every request, e.g. for proxies that allow-list by header
+	Headers http.Header
+
+	// Budget, if set, shares rate-limit accounting with other clients
+	Budget *RateBudget
+
+	// IncludePrereleases keeps releases marked as prereleases, with
+	// Release.Prerelease set, instead of skipping them
+	IncludePrereleases bool
+
+	// TagSuffix, if set, is stripped from release tags before they are parsed, for
+	// repositories that tag releases with a build suffix, e.g. 1.23.2-11234567890
+	TagSuffix *regexp.Regexp
+
+	// Instrument, if set, is called before each HTTP request; the function it
+	// returns is called with the outcome, e.g. to end a tracing span
+	Instrument func(req *http.Request) func(resp *http.Response, err error)
+
+	truncated bool // Set when GetAllReleases stops at maxReleasePages
+	pages     int  // Pages fetched by the last GetAllReleases
 }
 
+// maxReleasePages caps GetAllReleases at 1,000 releases
+const maxReleasePages = 10
+
+// Progress describes how far a paginated release fetch has got
+type Progress struct {
+	Page          int // Pages fetched so far
+	Releases      int // Releases collected so far
+	RateRemaining int // API requests remaining in the current rate-limit window
+	RateLimit     int // API requests allowed per rate-limit window
+}
+
+// ProgressFunc receives progress updates during long API fetches
+type ProgressFunc func(Progress)
+
 // NewClient creates a new GitHub API client
 func NewClient(token, owner, repo string) *Client {
-	var client *gh.Client
+	c := &Client{
+		Owner: owner,
+		Repo:  repo,
+	}
 
+	httpClient := &http.Client{Transport: &tracingTransport{base: http.DefaultTransport, client: c}}
 	if token != "" {
 		ts := oauth2.StaticTokenSource(
 			&oauth2.Token{AccessToken: token},
 		)
-		tc := oauth2.NewClient(context.Background(), ts)
-		client = gh.NewClient(tc)
-	} else {
-		client = gh.NewClient(nil)
+		ctx := context.WithValue(context.Background(), oauth2.HTTPClient, httpClient)
+		httpClient = oauth2.NewClient(ctx, ts)
 	}
+	c.gh = gh.NewClient(httpClient)
 
-	return &Client{
-		gh:    client,
-		Owner: owner,
-		Repo:  repo,
+	return c
+}
+
+// SetBaseURL points the client at another GitHub API, such as a GitHub Enterprise
+// Server or a test server
+func (c *Client) SetBaseURL(rawURL string) error {
+	u, err := url.Parse(strings.TrimRight(rawURL, "/") + "/")
+	if err != nil {
+		return fmt.Errorf("invalid API URL %q: %w", rawURL, err)
+	}
+	c.gh.BaseURL = u
+	return nil
+}
+
+// tracingTransport applies the owning client's User-Agent and headers, and logs
+// each HTTP request through its Logger
+type tracingTransport struct {
+	base   http.RoundTripper
+	client *Client
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.client.UserAgent != "" || len(t.client.Headers) > 0 {
+		req = req.Clone(req.Context()) // RoundTrippers must not modify the caller's request
+		for name, values := range t.client.Headers {
+			req.Header.Del(name)
+			for _, value := range values {
+				req.Header.Add(name, value)
+			}
+		}
+		if t.client.UserAgent != "" {
+			req.Header.Set("User-Agent", t.client.UserAgent)
+		}
+	}
+
+	if t.client.Budget != nil {
+		if err := t.client.Budget.wait(req.Context()); err != nil {
+			return nil, err
+		}
+	}
+
+	start := time.Now()
+	var done func(*http.Response, error)
+	if t.client.Instrument != nil {
+		done = t.client.Instrument(req)
+	}
+	resp, err := t.base.RoundTrip(req)
+	if done != nil {
+		done(resp, err)
+	}
+	if err == nil && t.client.Budget != nil {
+		t.client.Budget.observe(resp, time.Now())
+	}
+	if t.client.Logger == nil {
+		return resp, err
 	}
+
+	duration := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		t.client.Logger.Debug("http request failed", "method", req.Method, "url", req.URL.String(), "duration", duration, "error", err)
+		return resp, err
+	}
+	t.client.Logger.Debug("http request", "method", req.Method, "url", req.URL.String(), "status", resp.StatusCode,
+		"duration", duration, "rate_remaining", resp.Header.Get("X-RateLimit-Remaining"))
+	return resp, err
+}
+
+// skipRelease reports whether a release should be left out, logging the reason
+func (c *Client) skipRelease(ghRelease *gh.RepositoryRelease) (*types.Release, bool) {
+	reason := ""
+	switch {
+	case ghRelease.GetDraft():
+		reason = "draft"
+	case ghRelease.GetPrerelease() && !c.IncludePrereleases:
+		reason = "prerelease"
+	}
+
+	var release *types.Release
+	if reason == "" {
+		var err error
+		if release, err = c.parseRelease(ghRelease); err != nil {
+			reason = err.Error()
+		}
+	}
+
+	if reason != "" {
+		if c.Logger != nil {
+			c.Logger.Debug("skipping release", "tag", ghRelease.GetTagName(), "reason", reason)
+		}
+		return nil, true
+	}
+	return release, false
 }
 
 // GetLatestRelease fetches the latest release from GitHub
@@ -50,42 +191,93 @@ func (c *Client) GetLatestRelease(ctx context.Context) (*types.Release, error) {
 
 // GetAllReleases fetches all releases from GitHub
 func (c *Client) GetAllReleases(ctx context.Context) ([]types.Release, error) {
+	return c.listReleases(ctx, time.Time{})
+}
+
+// GetReleasesSince fetches the releases published at or after since, stopping at
+// the first page that reaches back before it, so refreshing a recent cache costs
+// a page or two
+func (c *Client) GetReleasesSince(ctx context.Context, since time.Time) ([]types.Release, error) {
+	return c.listReleases(ctx, since)
+}
+
+// listReleases pages through releases, newest first, until the last page or,
+// when since is set, a page reaching back before since
+func (c *Client) listReleases(ctx context.Context, since time.Time) ([]types.Release, error) {
 	var allReleases []types.Release
 
 	opts := &gh.ListOptions{PerPage: 100}
+	c.truncated = false
+	c.pages = 0
 
-	for page := 1; page <= 10; page++ { // Safety limit of 10 pages
+	for page := 1; page <= maxReleasePages; page++ {
 		opts.Page = page
+		c.pages = page
 
 		releases, resp, err := c.gh.Repositories.ListReleases(ctx, c.Owner, c.Repo, opts)
 		if err != nil {
 			return nil, fmt.Errorf("failed to list releases (page %d): %w", page, err)
 		}
 
+		reachedSince := false
 		for _, ghRelease := range releases {
-			// Skip drafts and prereleases
-			if ghRelease.GetDraft() || ghRelease.GetPrerelease() {
+			if !since.IsZero() && releaseTime(ghRelease).Before(since) {
+				reachedSince = true
 				continue
 			}
 
-			release, err := c.parseRelease(ghRelease)
-			if err != nil {
-				// Log but don't fail - just skip invalid releases
+			// Skip drafts, prereleases and invalid releases
+			release, skip := c.skipRelease(ghRelease)
+			if skip {
 				continue
 			}
 
 			allReleases = append(allReleases, *release)
 		}
 
-		// Check if we've reached the last page
-		if resp.NextPage == 0 {
+		if c.Progress != nil {
+			c.Progress(Progress{
+				Page:          page,
+				Releases:      len(allReleases),
+				RateRemaining: resp.Rate.Remaining,
+				RateLimit:     resp.Rate.Limit,
+			})
+		}
+
+		// Check if we've reached the last page, or releases older than wanted
+		if resp.NextPage == 0 || reachedSince {
 			break
 		}
+		c.truncated = page == maxReleasePages
 	}
 
 	return allReleases, nil
 }
 
+// releaseTime returns when a release was published, or created for drafts
+func releaseTime(ghRelease *gh.RepositoryRelease) time.Time {
+	if published := ghRelease.GetPublishedAt(); !published.IsZero() {
+		return published.Time
+	}
+	return ghRelease.GetCreatedAt().Time
+}
+
+// Truncated reports whether the last GetAllReleases stopped at the page limit
+// with releases left unfetched
+func (c *Client) Truncated() bool {
+	return c.truncated
+}
+
+// Pages returns how many release pages the last GetAllReleases fetched
+func (c *Client) Pages() int {
+	return c.pages
+}
+
+// APIHost returns the host of the API the client fetches from, e.g. api.github.com
+func (c *Client) APIHost() string {
+	return c.gh.BaseURL.Host
+}
+
 // GetRecentReleases fetches only the N most recent releases
 func (c *Client) GetRecentReleases(ctx context.Context, count int) ([]types.Release, error) {
 	opts := &gh.ListOptions{PerPage: count}
@@ -97,14 +289,9 @@ func (c *Client) GetRecentReleases(ctx context.Context, count int) ([]types.Rele
 
 	var result []types.Release
 	for _, ghRelease := range releases {
-		// Skip drafts and prereleases
-		if ghRelease.GetDraft() || ghRelease.GetPrerelease() {
-			continue
-		}
-
-		release, err := c.parseRelease(ghRelease)
-		if err != nil {
-			// Log but don't fail - just skip invalid releases
+		// Skip drafts, prereleases and invalid releases
+		release, skip := c.skipRelease(ghRelease)
+		if skip {
 			continue
 		}
 
@@ -114,6 +301,17 @@ func (c *Client) GetRecentReleases(ctx context.Context, count int) ([]types.Rele
 	return result, nil
 }
 
+// GetReleasesByMinor fetches the releases of one minor version line, e.g. all
+// 1.29.x, highest version first. GitHub cannot filter releases by version, so
+// this pages through them all.
+func (c *Client) GetReleasesByMinor(ctx context.Context, major, minor uint64) ([]types.Release, error) {
+	releases, err := c.GetAllReleases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return releaseset.Line(releases, major, minor), nil
+}
+
 // parseRelease converts a GitHub release to our Release type
 func (c *Client) parseRelease(ghRelease *gh.RepositoryRelease) (*types.Release, error) {
 	tagName := ghRelease.GetTagName()
@@ -121,8 +319,12 @@ func (c *Client) parseRelease(ghRelease *gh.RepositoryRelease) (*types.Release,
 		return nil, fmt.Errorf("release has no tag name")
 	}
 
+	if c.TagSuffix != nil {
+		tagName = c.TagSuffix.ReplaceAllString(tagName, "")
+	}
+
 	// Parse version (removing 'v' prefix if present)
-	ver, err := semver.NewVersion(tagName)
+	ver, err := types.ParseVersion(tagName)
 	if err != nil {
 		return nil, fmt.Errorf("invalid version %q: %w", tagName, err)
 	}
@@ -137,6 +339,9 @@ func (c *Client) parseRelease(ghRelease *gh.RepositoryRelease) (*types.Release,
 		Version:     ver,
 		PublishedAt: publishedAt.Time,
 		URL:         ghRelease.GetHTMLURL(),
+		Prerelease:  ghRelease.GetPrerelease(),
+		Notes:       ghRelease.GetBody(),
+		Platforms:   types.AssetPlatforms(uploadedAssets(ghRelease)),
 	}, nil
 }
 
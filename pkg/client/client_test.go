@@ -3,6 +3,10 @@ package client
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
 	"testing"
 	"time"
 
@@ -57,6 +61,7 @@ func TestParseRelease(t *testing.T) {
 		wantVer   string
 		checkURL  bool
 		expectURL string
+		platforms []string
 	}{
 		{
 			name: "valid release",
@@ -80,6 +85,20 @@ func TestParseRelease(t *testing.T) {
 			wantErr: false,
 			wantVer: "2.329.0",
 		},
+		{
+			name: "platforms from uploaded assets",
+			release: &gh.RepositoryRelease{
+				TagName:     stringPtr("v2.329.0"),
+				PublishedAt: &gh.Timestamp{Time: now},
+				Assets: []*gh.ReleaseAsset{
+					{Name: stringPtr("actions-runner-linux-arm64-2.329.0.tar.gz"), State: stringPtr("uploaded")},
+					{Name: stringPtr("actions-runner-linux-x64-2.329.0.tar.gz"), State: stringPtr("uploaded")},
+					{Name: stringPtr("actions-runner-win-x64-2.329.0.zip"), State: stringPtr("starter")},
+				},
+			},
+			wantVer:   "2.329.0",
+			platforms: []string{"linux/amd64", "linux/arm64"},
+		},
 		{
 			name: "missing tag name",
 			release: &gh.RepositoryRelease{
@@ -139,6 +158,10 @@ func TestParseRelease(t *testing.T) {
 			if release.PublishedAt.IsZero() {
 				t.Error("PublishedAt is zero")
 			}
+
+			if !slices.Equal(release.Platforms, tt.platforms) {
+				t.Errorf("Platforms = %v, want %v", release.Platforms, tt.platforms)
+			}
 		})
 	}
 }
@@ -363,3 +386,155 @@ func newTestRelease(versionStr, owner, repo string, daysAgo int) types.Release {
 func stringPtr(s string) *string {
 	return &s
 }
+
+// TestGetAllReleases_Progress tests progress reporting across pages
+func TestGetAllReleases_Progress(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "4990")
+		published := time.Now().UTC().Format(time.RFC3339)
+		if r.URL.Query().Get("page") == "1" {
+			w.Header().Set("Link", fmt.Sprintf(`<%s%s?page=2>; rel="next"`, "http://"+r.Host, r.URL.Path))
+			fmt.Fprintf(w, `[{"tag_name":"v2.0.0","published_at":%q},{"tag_name":"v1.9.0","published_at":%q}]`, published, published)
+			return
+		}
+		fmt.Fprintf(w, `[{"tag_name":"v1.8.0","published_at":%q}]`, published)
+	})
+
+	var updates []Progress
+	client.Progress = func(p Progress) { updates = append(updates, p) }
+
+	releases, err := client.GetAllReleases(context.Background())
+	if err != nil {
+		t.Fatalf("GetAllReleases() error = %v", err)
+	}
+	if len(releases) != 3 {
+		t.Errorf("expected 3 releases, got %d", len(releases))
+	}
+
+	want := []Progress{
+		{Page: 1, Releases: 2, RateRemaining: 4990, RateLimit: 5000},
+		{Page: 2, Releases: 3, RateRemaining: 4990, RateLimit: 5000},
+	}
+	if len(updates) != len(want) {
+		t.Fatalf("expected %d progress updates, got %d", len(want), len(updates))
+	}
+	for i := range want {
+		if updates[i] != want[i] {
+			t.Errorf("update %d = %+v, want %+v", i, updates[i], want[i])
+		}
+	}
+}
+
+// TestGetAllReleases_Truncated tests that hitting the page limit is reported
+func TestGetAllReleases_Truncated(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		w.Header().Set("Link", fmt.Sprintf(`<%s%s?page=%d>; rel="next"`, "http://"+r.Host, r.URL.Path, page+1))
+		fmt.Fprintf(w, `[{"tag_name":"v1.%d.0","published_at":%q}]`, page, time.Now().UTC().Format(time.RFC3339))
+	})
+
+	releases, err := client.GetAllReleases(context.Background())
+	if err != nil {
+		t.Fatalf("GetAllReleases() error = %v", err)
+	}
+	if len(releases) != maxReleasePages || !client.Truncated() || client.Pages() != maxReleasePages {
+		t.Errorf("got %d releases, Truncated() = %v, Pages() = %d; want %d, true and %d", len(releases), client.Truncated(), client.Pages(), maxReleasePages, maxReleasePages)
+	}
+}
+
+// TestGetAllReleases_IncludePrereleases tests that prereleases are kept and marked when asked
+func TestGetAllReleases_IncludePrereleases(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		published := time.Now().UTC().Format(time.RFC3339)
+		fmt.Fprintf(w, `[{"tag_name":"v2.0.0-rc.1","prerelease":true,"published_at":%q},{"tag_name":"v1.9.0","published_at":%q}]`, published, published)
+	})
+
+	releases, err := client.GetAllReleases(context.Background())
+	if err != nil || len(releases) != 1 {
+		t.Fatalf("GetAllReleases() = %d releases, %v; want the prerelease skipped", len(releases), err)
+	}
+
+	client.IncludePrereleases = true
+	releases, err = client.GetAllReleases(context.Background())
+	if err != nil || len(releases) != 2 {
+		t.Fatalf("GetAllReleases() = %d releases, %v; want 2", len(releases), err)
+	}
+	if !releases[0].Prerelease || releases[1].Prerelease {
+		t.Errorf("Prerelease = %v, %v; want true, false", releases[0].Prerelease, releases[1].Prerelease)
+	}
+}
+
+// TestGetAllReleases_Notes tests that release notes are kept for keyword matching
+func TestGetAllReleases_Notes(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `[{"tag_name":"v1.9.1","body":"Security fix for CVE-2026-1234","published_at":%q}]`, time.Now().UTC().Format(time.RFC3339))
+	})
+
+	releases, err := client.GetAllReleases(context.Background())
+	if err != nil || len(releases) != 1 {
+		t.Fatalf("GetAllReleases() = %d releases, %v; want 1", len(releases), err)
+	}
+	if want := "Security fix for CVE-2026-1234"; releases[0].Notes != want {
+		t.Errorf("Notes = %q, want %q", releases[0].Notes, want)
+	}
+}
+
+// TestGetAllReleases_TagSuffix tests that a build suffix is stripped from tags
+func TestGetAllReleases_TagSuffix(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		published := time.Now().UTC().Format(time.RFC3339)
+		fmt.Fprintf(w, `[{"tag_name":"1.23.2-11234567890","published_at":%q},{"tag_name":"1.24.0-rc.1-11234567891","published_at":%q}]`, published, published)
+	})
+	client.TagSuffix = regexp.MustCompile(`-\d+$`)
+
+	releases, err := client.GetAllReleases(context.Background())
+	if err != nil || len(releases) != 2 {
+		t.Fatalf("GetAllReleases() = %d releases, %v; want 2", len(releases), err)
+	}
+	if got := releases[0].Version.String() + " " + releases[1].Version.String(); got != "1.23.2 1.24.0-rc.1" {
+		t.Errorf("versions = %s, want 1.23.2 1.24.0-rc.1", got)
+	}
+}
+
+// TestGetReleasesByMinor tests filtering all releases to one minor line
+func TestGetReleasesByMinor(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		published := time.Now().UTC().Format(time.RFC3339)
+		fmt.Fprintf(w, `[{"tag_name":"v1.30.0","published_at":%q},{"tag_name":"v1.29.4","published_at":%q},{"tag_name":"v1.29.10","published_at":%q}]`, published, published, published)
+	})
+
+	releases, err := client.GetReleasesByMinor(context.Background(), 1, 29)
+	if err != nil {
+		t.Fatalf("GetReleasesByMinor() error = %v", err)
+	}
+	if len(releases) != 2 || releases[0].Version.String() != "1.29.10" {
+		t.Errorf("GetReleasesByMinor() = %+v, want 1.29.10 and 1.29.4", releases)
+	}
+}
+
+// TestGetReleasesSince tests that paging stops once releases are older than wanted
+func TestGetReleasesSince(t *testing.T) {
+	now := time.Now().UTC()
+	requests := 0
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		w.Header().Set("Link", fmt.Sprintf(`<%s%s?page=%d>; rel="next"`, "http://"+r.Host, r.URL.Path, page+1))
+		// Two releases a page, each page ten days older than the last
+		newer := now.AddDate(0, 0, -10*(page-1)).Format(time.RFC3339)
+		older := now.AddDate(0, 0, -10*(page-1)-5).Format(time.RFC3339)
+		fmt.Fprintf(w, `[{"tag_name":"v1.%d.1","published_at":%q},{"tag_name":"v1.%d.0","published_at":%q}]`, 100-page, newer, 100-page, older)
+	})
+
+	releases, err := client.GetReleasesSince(context.Background(), now.AddDate(0, 0, -12))
+	if err != nil {
+		t.Fatalf("GetReleasesSince() error = %v", err)
+	}
+	if requests != 2 || len(releases) != 3 {
+		t.Errorf("got %d releases from %d requests, want 3 from 2", len(releases), requests)
+	}
+	if client.Truncated() {
+		t.Error("Truncated() = true, want false after reaching the time")
+	}
+}
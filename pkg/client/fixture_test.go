@@ -0,0 +1,114 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	gh "github.com/google/go-github/v57/github"
+	"github.com/nickromney-org/github-release-version-checker/internal/testing/githubtest"
+)
+
+// newFixtureClient returns a client for repository served by a fake GitHub API
+func newFixtureClient(t *testing.T, repository string) (*Client, *githubtest.Server) {
+	t.Helper()
+	fixture := githubtest.MustLoadFixture(repository)
+	server := githubtest.NewServer(fixture)
+	t.Cleanup(server.Close)
+
+	owner, repo, _ := strings.Cut(repository, "/")
+	client := NewClient("", owner, repo)
+	if err := client.SetBaseURL(server.URL); err != nil {
+		t.Fatal(err)
+	}
+	return client, server
+}
+
+// TestFixture_GetAllReleases tests paging through a recorded release history
+func TestFixture_GetAllReleases(t *testing.T) {
+	client, server := newFixtureClient(t, "actions/runner")
+	fixture := githubtest.MustLoadFixture("actions/runner")
+
+	releases, err := client.GetAllReleases(context.Background())
+	if err != nil {
+		t.Fatalf("GetAllReleases() error = %v", err)
+	}
+	if len(releases) != len(fixture.Releases) || client.Truncated() {
+		t.Errorf("got %d releases (truncated %v), want %d", len(releases), client.Truncated(), len(fixture.Releases))
+	}
+	if wantPages := (len(fixture.Releases) + 99) / 100; len(server.Requests()) != wantPages {
+		t.Errorf("made %d requests, want %d pages: %v", len(server.Requests()), wantPages, server.Requests())
+	}
+
+	latest, err := client.GetLatestRelease(context.Background())
+	if err != nil {
+		t.Fatalf("GetLatestRelease() error = %v", err)
+	}
+	if "v"+latest.Version.String() != fixture.LatestTag {
+		t.Errorf("latest = %s, want %s", latest.Version, fixture.LatestTag)
+	}
+}
+
+// TestFixture_RateLimitMidPagination tests running out of requests between pages
+func TestFixture_RateLimitMidPagination(t *testing.T) {
+	client, server := newFixtureClient(t, "actions/runner")
+	server.SetRateLimit(60, 1)
+
+	_, err := client.GetAllReleases(context.Background())
+	if !IsRateLimited(err) {
+		t.Fatalf("GetAllReleases() error = %v, want a rate limit", err)
+	}
+	limits, ok := RateLimitInfo(err)
+	if !ok || limits.Limit != 60 || limits.Secondary {
+		t.Errorf("RateLimitInfo() = %+v, %v; want the primary limit of 60", limits, ok)
+	}
+}
+
+// TestFixture_SecondaryRateLimit tests a secondary rate limit is reported with
+// its Retry-After, and that later calls fail without contacting GitHub until it passes
+func TestFixture_SecondaryRateLimit(t *testing.T) {
+	client, server := newFixtureClient(t, "actions/runner")
+	server.SecondaryRateLimit(60)
+
+	_, err := client.GetLatestRelease(context.Background())
+	var abuse *gh.AbuseRateLimitError
+	if !errors.As(err, &abuse) || abuse.RetryAfter == nil || *abuse.RetryAfter != time.Minute {
+		t.Fatalf("GetLatestRelease() error = %v, want a secondary rate limit with Retry-After 1m", err)
+	}
+
+	requests := len(server.Requests())
+	if _, err := client.GetLatestRelease(context.Background()); !IsRateLimited(err) {
+		t.Errorf("GetLatestRelease() during the pause error = %v, want a rate limit", err)
+	}
+	if len(server.Requests()) != requests {
+		t.Error("request sent during the secondary rate-limit pause")
+	}
+}
+
+// TestFixture_NotModified tests a 304, e.g. from a caching proxy answering a
+// conditional request, is an error rather than an empty release list
+func TestFixture_NotModified(t *testing.T) {
+	client, _ := newFixtureClient(t, "actions/runner")
+
+	var etag string
+	client.Instrument = func(*http.Request) func(*http.Response, error) {
+		return func(resp *http.Response, err error) {
+			if err == nil {
+				etag = resp.Header.Get("ETag")
+			}
+		}
+	}
+	if _, err := client.GetRecentReleases(context.Background(), 5); err != nil {
+		t.Fatalf("GetRecentReleases() error = %v", err)
+	}
+
+	client.Headers = http.Header{"If-None-Match": []string{etag}}
+	releases, err := client.GetRecentReleases(context.Background(), 5)
+	var errResp *gh.ErrorResponse
+	if !errors.As(err, &errResp) || errResp.Response.StatusCode != http.StatusNotModified {
+		t.Errorf("GetRecentReleases() = %d releases, error %v; want a 304 error", len(releases), err)
+	}
+}
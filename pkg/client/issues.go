@@ -0,0 +1,54 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	gh "github.com/google/go-github/v57/github"
+)
+
+// Issue describes an issue to open
+type Issue struct {
+	Title  string
+	Body   string   // Markdown
+	Labels []string // Created on the repository if missing
+}
+
+// CreateIssue opens an issue in the client's repository, returning its URL.
+// The token needs write access to issues.
+func (c *Client) CreateIssue(ctx context.Context, issue Issue) (string, error) {
+	req := &gh.IssueRequest{Title: gh.String(issue.Title), Body: gh.String(issue.Body)}
+	if len(issue.Labels) > 0 {
+		req.Labels = &issue.Labels
+	}
+	created, _, err := c.gh.Issues.Create(ctx, c.Owner, c.Repo, req)
+	if err != nil {
+		return "", fmt.Errorf("failed to create issue: %w", err)
+	}
+	return created.GetHTMLURL(), nil
+}
+
+// FindOpenIssue returns the URL of an open issue titled title and labelled
+// label in the client's repository, or "" if there is none. Only the 100 most
+// recently updated such issues are searched.
+func (c *Client) FindOpenIssue(ctx context.Context, title, label string) (string, error) {
+	opts := &gh.IssueListByRepoOptions{
+		State:       "open",
+		Sort:        "updated",
+		ListOptions: gh.ListOptions{PerPage: 100},
+	}
+	if label != "" {
+		opts.Labels = []string{label}
+	}
+	issues, _, err := c.gh.Issues.ListByRepo(ctx, c.Owner, c.Repo, opts)
+	if err != nil {
+		return "", fmt.Errorf("failed to list issues: %w", err)
+	}
+	for _, issue := range issues {
+		// The issues API lists pull requests too
+		if !issue.IsPullRequest() && issue.GetTitle() == title {
+			return issue.GetHTMLURL(), nil
+		}
+	}
+	return "", nil
+}
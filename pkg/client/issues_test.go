@@ -0,0 +1,85 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCreateIssue(t *testing.T) {
+	var got struct {
+		Title  string   `json:"title"`
+		Body   string   `json:"body"`
+		Labels []string `json:"labels"`
+	}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/repos/owner/repo/issues" {
+			t.Errorf("request = %s %s, want POST /repos/owner/repo/issues", r.Method, r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Fatal(err)
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"number":12,"html_url":"https://github.com/owner/repo/issues/12"}`)
+	})
+
+	url, err := client.CreateIssue(context.Background(), Issue{Title: "Update actions/runner", Body: "body", Labels: []string{"release-version-checker"}})
+	if err != nil {
+		t.Fatalf("CreateIssue() error = %v", err)
+	}
+	if url != "https://github.com/owner/repo/issues/12" {
+		t.Errorf("url = %q", url)
+	}
+	if got.Title != "Update actions/runner" || got.Body != "body" || !reflect.DeepEqual(got.Labels, []string{"release-version-checker"}) {
+		t.Errorf("request body = %+v", got)
+	}
+}
+
+func TestCreateIssue_Error(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusGone)
+		fmt.Fprint(w, `{"message":"Issues are disabled for this repo"}`)
+	})
+
+	_, err := client.CreateIssue(context.Background(), Issue{Title: "Update actions/runner"})
+	if err == nil || !strings.Contains(err.Error(), "failed to create issue") {
+		t.Errorf("CreateIssue() error = %v", err)
+	}
+}
+
+func TestFindOpenIssue(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/owner/repo/issues" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		if q := r.URL.Query(); q.Get("state") != "open" || q.Get("labels") != "release-version-checker" {
+			t.Errorf("query = %s, want open issues with the label", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `[
+			{"title": "Update actions/runner", "html_url": "https://github.com/owner/repo/pull/3", "pull_request": {"url": "x"}},
+			{"title": "Update kubernetes/kubernetes", "html_url": "https://github.com/owner/repo/issues/4"},
+			{"title": "Update actions/runner", "html_url": "https://github.com/owner/repo/issues/5"}
+		]`)
+	})
+
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{title: "Update actions/runner", want: "https://github.com/owner/repo/issues/5"},
+		{title: "Update nodejs/node", want: ""},
+	}
+	for _, tt := range tests {
+		got, err := client.FindOpenIssue(context.Background(), tt.title, "release-version-checker")
+		if err != nil {
+			t.Fatalf("FindOpenIssue() error = %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("FindOpenIssue(%q) = %q, want %q", tt.title, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	gh "github.com/google/go-github/v57/github"
+)
+
+// PullRequest describes a pull request to open
+type PullRequest struct {
+	Title string
+	Body  string // Markdown
+	Head  string // Branch with the changes
+	Base  string // Branch to merge into
+}
+
+// CreatePullRequest opens a pull request in the client's repository, returning
+// its URL. The token needs write access to pull requests.
+func (c *Client) CreatePullRequest(ctx context.Context, pr PullRequest) (string, error) {
+	created, _, err := c.gh.PullRequests.Create(ctx, c.Owner, c.Repo, &gh.NewPullRequest{
+		Title: gh.String(pr.Title),
+		Body:  gh.String(pr.Body),
+		Head:  gh.String(pr.Head),
+		Base:  gh.String(pr.Base),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create pull request: %w", err)
+	}
+	return created.GetHTMLURL(), nil
+}
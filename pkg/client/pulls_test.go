@@ -0,0 +1,49 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestCreatePullRequest(t *testing.T) {
+	var got map[string]string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/repos/owner/repo/pulls" {
+			t.Errorf("request = %s %s, want POST /repos/owner/repo/pulls", r.Method, r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Fatal(err)
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"number":7,"html_url":"https://github.com/owner/repo/pull/7"}`)
+	})
+
+	url, err := client.CreatePullRequest(context.Background(), PullRequest{
+		Title: "Bump RUNNER_VERSION to 2.329.0", Body: "body", Head: "bump", Base: "main",
+	})
+	if err != nil {
+		t.Fatalf("CreatePullRequest() error = %v", err)
+	}
+	if url != "https://github.com/owner/repo/pull/7" {
+		t.Errorf("url = %q", url)
+	}
+	if got["title"] != "Bump RUNNER_VERSION to 2.329.0" || got["head"] != "bump" || got["base"] != "main" || got["body"] != "body" {
+		t.Errorf("request body = %v", got)
+	}
+}
+
+func TestCreatePullRequest_Error(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		fmt.Fprint(w, `{"message":"Validation Failed","errors":[{"message":"A pull request already exists for owner:bump."}]}`)
+	})
+
+	_, err := client.CreatePullRequest(context.Background(), PullRequest{Head: "bump", Base: "main"})
+	if err == nil || !strings.Contains(err.Error(), "failed to create pull request") {
+		t.Errorf("CreatePullRequest() error = %v", err)
+	}
+}
@@ -0,0 +1,139 @@
+package policy
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// searchDays bounds how far a Calendar looks for a window
+const searchDays = 366
+
+// ordinals maps the week of the month a window falls in; -1 is the last
+var ordinals = map[string]int{
+	"first": 1, "1st": 1,
+	"second": 2, "2nd": 2,
+	"third": 3, "3rd": 3,
+	"fourth": 4, "4th": 4,
+	"last": -1,
+}
+
+// weekdays maps weekday names, full or abbreviated, to time.Weekday
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+// windowRule is one recurring maintenance window
+type windowRule struct {
+	weekday time.Weekday
+	ordinal int // Week of the month, -1 for the last, 0 for every week
+}
+
+// matches reports whether the rule has a window on day
+func (r windowRule) matches(day time.Time) bool {
+	if day.Weekday() != r.weekday {
+		return false
+	}
+	switch {
+	case r.ordinal == 0:
+		return true
+	case r.ordinal < 0:
+		return day.AddDate(0, 0, 7).Month() != day.Month()
+	default:
+		return (day.Day()-1)/7+1 == r.ordinal
+	}
+}
+
+// Calendar is a maintenance calendar: the days on which updates can be rolled
+// out, such as the first Tuesday of every month
+type Calendar struct {
+	spec  string
+	rules []windowRule
+}
+
+// ParseCalendar parses a maintenance calendar of comma-separated windows, each
+// "<ordinal> <weekday> monthly" (e.g. "first tuesday monthly", "last fri
+// monthly") or "every <weekday>" (e.g. "every wednesday")
+func ParseCalendar(spec string) (*Calendar, error) {
+	cal := &Calendar{spec: strings.TrimSpace(spec)}
+	for _, part := range strings.Split(spec, ",") {
+		fields := strings.Fields(strings.ToLower(part))
+		if len(fields) > 0 && fields[0] == "the" {
+			fields = fields[1:]
+		}
+
+		var rule windowRule
+		var ok bool
+		switch {
+		case len(fields) == 2 && fields[0] == "every":
+			rule.weekday, ok = weekdays[fields[1]]
+		case len(fields) == 3 && fields[2] == "monthly":
+			if rule.ordinal, ok = ordinals[fields[0]]; ok {
+				rule.weekday, ok = weekdays[fields[1]]
+			}
+		}
+		if !ok {
+			return nil, fmt.Errorf("invalid maintenance window %q: use e.g. 'first tuesday monthly' or 'every wednesday'", strings.TrimSpace(part))
+		}
+		cal.rules = append(cal.rules, rule)
+	}
+	return cal, nil
+}
+
+// String returns the calendar as it was given
+func (c *Calendar) String() string {
+	return c.spec
+}
+
+// isWindow reports whether any rule has a window on day
+func (c *Calendar) isWindow(day time.Time) bool {
+	for _, rule := range c.rules {
+		if rule.matches(day) {
+			return true
+		}
+	}
+	return false
+}
+
+// Next returns the first window on or after t, as a UTC date
+func (c *Calendar) Next(t time.Time) (time.Time, bool) {
+	day := truncateDay(t)
+	for i := 0; i < searchDays; i++ {
+		if c.isWindow(day) {
+			return day, true
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return time.Time{}, false
+}
+
+// LastBefore returns the last window strictly before the day of t, as a UTC
+// date: the last chance to roll out an update that must land before t
+func (c *Calendar) LastBefore(t time.Time) (time.Time, bool) {
+	day := truncateDay(t)
+	for i := 0; i < searchDays; i++ {
+		day = day.AddDate(0, 0, -1)
+		if c.isWindow(day) {
+			return day, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// CalendarPolicy is implemented by policies that roll out updates in
+// maintenance windows; a nil Calendar means updates can land on any day
+type CalendarPolicy interface {
+	MaintenanceCalendar() *Calendar
+}
+
+// truncateDay returns midnight UTC on the day of t
+func truncateDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,61 @@
+package policy
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCalendar(t *testing.T) {
+	valid := []string{"first tuesday monthly", "The Last Fri monthly", "every wednesday", "2nd mon monthly, 4th mon monthly"}
+	for _, spec := range valid {
+		if _, err := ParseCalendar(spec); err != nil {
+			t.Errorf("ParseCalendar(%q) error = %v", spec, err)
+		}
+	}
+
+	invalid := []string{"", "fifth tuesday monthly", "first someday monthly", "every", "tuesday", "first tuesday, every friday"}
+	for _, spec := range invalid {
+		if _, err := ParseCalendar(spec); err == nil {
+			t.Errorf("ParseCalendar(%q) expected an error", spec)
+		}
+	}
+}
+
+func TestCalendar_Windows(t *testing.T) {
+	date := func(s string) time.Time {
+		d, err := time.Parse(time.DateOnly, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+
+	tests := []struct {
+		spec       string
+		at         string
+		next       string
+		lastBefore string
+	}{
+		// First Tuesdays of 2025: 4 Feb, 4 Mar, 1 Apr
+		{"first tuesday monthly", "2025-03-04", "2025-03-04", "2025-02-04"},
+		{"first tuesday monthly", "2025-03-10", "2025-04-01", "2025-03-04"},
+		// Last Fridays: 28 Feb, 28 Mar
+		{"last friday monthly", "2025-03-01", "2025-03-28", "2025-02-28"},
+		{"every wednesday", "2025-03-06", "2025-03-12", "2025-03-05"},
+		{"first monday monthly, third monday monthly", "2025-03-04", "2025-03-17", "2025-03-03"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec+"@"+tt.at, func(t *testing.T) {
+			cal, err := ParseCalendar(tt.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if next, ok := cal.Next(date(tt.at)); !ok || !next.Equal(date(tt.next)) {
+				t.Errorf("Next() = %s, want %s", next.Format(time.DateOnly), tt.next)
+			}
+			if last, ok := cal.LastBefore(date(tt.at)); !ok || !last.Equal(date(tt.lastBefore)) {
+				t.Errorf("LastBefore() = %s, want %s", last.Format(time.DateOnly), tt.lastBefore)
+			}
+		})
+	}
+}
@@ -0,0 +1,11 @@
+// Package policy decides whether a version has expired, by its age in days, by
+// how many minor versions it is behind, or by when its release line reaches end
+// of life on an LTS schedule.
+//
+// Policies need no checker: EvaluateVersions evaluates one against releases the
+// caller supplies, with their own dates, and never touches the network.
+//
+// This package is part of the stable v1 API; see the checker package for what
+// that guarantees. The VersionPolicy interface never gains methods within v1,
+// so custom policies keep compiling.
+package policy
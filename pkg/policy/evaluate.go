@@ -0,0 +1,58 @@
+package policy
+
+import (
+	"slices"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/nickromney-org/github-release-version-checker/pkg/releaseset"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+// EvaluateVersions evaluates p for comparison against releases the caller
+// already has, e.g. from an inventory or a mirror, without a checker or any
+// network access. releases can be in any order and need not include
+// comparison, whose date is then taken as now, as the checker does. A nil
+// latest means the highest of releases; releases above latest are ignored.
+func EvaluateVersions(p VersionPolicy, comparison, latest *semver.Version, releases []types.Release) PolicyResult {
+	if latest == nil {
+		highest := releaseset.Latest(releases)
+		if highest == nil {
+			return PolicyResult{}
+		}
+		latest = highest.Version
+	}
+	comparisonDate := time.Now()
+	if r := find(releases, comparison); r != nil {
+		comparisonDate = r.PublishedAt
+	}
+	var latestDate time.Time
+	if r := find(releases, latest); r != nil {
+		latestDate = r.PublishedAt
+	}
+	return p.Evaluate(comparison, comparisonDate, latest, latestDate, NewerReleases(comparison, latest, releases))
+}
+
+// NewerReleases returns the releases above comparison and up to latest, oldest
+// first, as VersionPolicy.Evaluate expects them; a nil latest has no upper bound
+func NewerReleases(comparison, latest *semver.Version, releases []types.Release) []types.Release {
+	var newer []types.Release
+	for _, r := range releaseset.NewerThan(releases, comparison) {
+		if latest == nil || types.CompareVersions(r.Version, latest) <= 0 {
+			newer = append(newer, r)
+		}
+	}
+	releaseset.SortByDate(newer)
+	slices.Reverse(newer)
+	return newer
+}
+
+// find returns the release of version v, or nil if there is none
+func find(releases []types.Release, v *semver.Version) *types.Release {
+	for i := range releases {
+		if types.CompareVersions(releases[i].Version, v) == 0 {
+			return &releases[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+func TestEvaluateVersions(t *testing.T) {
+	// Unsorted, as callers' own data may be
+	releases := []types.Release{
+		makeRelease("1.31.0", 200),
+		makeRelease("1.34.0", 10),
+		makeRelease("1.32.0", 150),
+		makeRelease("1.33.0", 60),
+		makeRelease("1.31.1", 40),
+	}
+
+	tests := []struct {
+		name         string
+		policy       VersionPolicy
+		comparison   string
+		latest       string
+		wantExpired  bool
+		wantCritical bool
+		wantDaysOld  int
+		wantBehind   int
+	}{
+		{name: "days, expired", policy: NewDaysPolicy(12, 30), comparison: "1.31.0", wantExpired: true, wantDaysOld: 150, wantBehind: 4},
+		{name: "days, up to date", policy: NewDaysPolicy(12, 30), comparison: "1.34.0"},
+		{name: "days, latest given", policy: NewDaysPolicy(12, 30), comparison: "1.33.0", latest: "1.33.0"},
+		{name: "versions, critical", policy: NewVersionsPolicy(3), comparison: "1.31.1", wantCritical: true, wantBehind: 3},
+		{name: "versions, below latest given", policy: NewVersionsPolicy(3), comparison: "1.31.1", latest: "1.33.0", wantBehind: 2},
+		{name: "not among the releases", policy: NewVersionsPolicy(1), comparison: "1.30.5", wantExpired: true, wantBehind: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var latest *semver.Version
+			if tt.latest != "" {
+				latest = semver.MustParse(tt.latest)
+			}
+			got := EvaluateVersions(tt.policy, semver.MustParse(tt.comparison), latest, releases)
+			if got.IsExpired != tt.wantExpired || got.IsCritical != tt.wantCritical {
+				t.Errorf("IsExpired = %v, IsCritical = %v, want %v, %v", got.IsExpired, got.IsCritical, tt.wantExpired, tt.wantCritical)
+			}
+			// Within a day, as release dates cross clock changes
+			if got.DaysOld < tt.wantDaysOld-1 || got.DaysOld > tt.wantDaysOld {
+				t.Errorf("DaysOld = %d, want %d", got.DaysOld, tt.wantDaysOld)
+			}
+			if got.VersionsBehind != tt.wantBehind {
+				t.Errorf("VersionsBehind = %d, want %d", got.VersionsBehind, tt.wantBehind)
+			}
+		})
+	}
+}
+
+func TestEvaluateVersions_NoReleases(t *testing.T) {
+	got := EvaluateVersions(NewDaysPolicy(12, 30), semver.MustParse("1.0.0"), nil, nil)
+	if got.IsExpired || got.IsCritical || got.IsWarning {
+		t.Errorf("EvaluateVersions() = %+v, want no status", got)
+	}
+}
+
+func TestNewerReleases(t *testing.T) {
+	releases := []types.Release{
+		makeRelease("2.0.0", 1),
+		makeRelease("1.2.0", 5),
+		makeRelease("1.0.0", 30),
+		makeRelease("1.1.0", 20),
+	}
+	got := NewerReleases(semver.MustParse("1.0.0"), semver.MustParse("1.2.0"), releases)
+	want := []string{"1.1.0", "1.2.0"}
+	if len(got) != len(want) {
+		t.Fatalf("NewerReleases() returned %d releases, want %d", len(got), len(want))
+	}
+	for i, r := range got {
+		if r.Version.String() != want[i] {
+			t.Errorf("NewerReleases()[%d] = %s, want %s", i, r.Version, want[i])
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+// Support phases of a release line on an LTS schedule
+const (
+	PhaseCurrent     = "current"     // Released, not yet LTS
+	PhaseActive      = "active"      // Active LTS
+	PhaseMaintenance = "maintenance" // Maintenance LTS: critical fixes only
+	PhaseEndOfLife   = "end-of-life" // No longer supported
+)
+
+// LTSLine is when one major release line enters each support phase; LTS and
+// Maintenance are zero for lines that never become LTS
+type LTSLine struct {
+	Start       time.Time
+	LTS         time.Time
+	Maintenance time.Time
+	End         time.Time
+	Codename    string
+}
+
+// Phase returns the line's support phase at now
+func (l LTSLine) Phase(now time.Time) string {
+	switch {
+	case !now.Before(l.End):
+		return PhaseEndOfLife
+	case !l.Maintenance.IsZero() && !now.Before(l.Maintenance):
+		return PhaseMaintenance
+	case !l.LTS.IsZero() && !now.Before(l.LTS):
+		return PhaseActive
+	default:
+		return PhaseCurrent
+	}
+}
+
+// LTSSchedule maps major versions to their support schedule
+type LTSSchedule map[uint64]LTSLine
+
+// ParseNodeSchedule parses the Node.js release schedule, schedule.json in the
+// nodejs/Release repository
+func ParseNodeSchedule(data []byte) (LTSSchedule, error) {
+	var raw map[string]struct {
+		Start       string `json:"start"`
+		LTS         string `json:"lts"`
+		Maintenance string `json:"maintenance"`
+		End         string `json:"end"`
+		Codename    string `json:"codename"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid release schedule: %w", err)
+	}
+
+	schedule := make(LTSSchedule, len(raw))
+	for name, entry := range raw {
+		major, err := strconv.ParseUint(strings.TrimPrefix(name, "v"), 10, 64)
+		if err != nil {
+			continue // Pre-1.0 lines such as v0.12 are named by minor
+		}
+		var line LTSLine
+		for _, field := range []struct {
+			value string
+			dest  *time.Time
+		}{{entry.Start, &line.Start}, {entry.LTS, &line.LTS}, {entry.Maintenance, &line.Maintenance}, {entry.End, &line.End}} {
+			if field.value == "" {
+				continue
+			}
+			if *field.dest, err = time.Parse(time.DateOnly, field.value); err != nil {
+				return nil, fmt.Errorf("invalid release schedule for %s: %w", name, err)
+			}
+		}
+		if line.End.IsZero() {
+			return nil, fmt.Errorf("invalid release schedule for %s: no end date", name)
+		}
+		line.Codename = entry.Codename
+		schedule[major] = line
+	}
+	return schedule, nil
+}
+
+// SupportSchedulePolicy is implemented by policies that retire whole release
+// lines on a published schedule, such as LTSPolicy
+type SupportSchedulePolicy interface {
+	// Support returns v's support phase and end of life; ok is false when the
+	// schedule does not cover v
+	Support(v *semver.Version) (phase string, endOfLife time.Time, ok bool)
+}
+
+// LTSPolicy expires versions when their major release line reaches end of life
+// on an LTS schedule, rather than by counting versions behind
+type LTSPolicy struct {
+	Schedule     LTSSchedule
+	CriticalDays int // Days before end of life a version becomes critical
+
+	// Fallback evaluates versions the schedule does not cover, such as when it
+	// could not be fetched; nil leaves them at warning
+	Fallback VersionPolicy
+
+	now func() time.Time // For tests; nil means time.Now
+}
+
+// NewLTSPolicy creates a schedule-based policy
+func NewLTSPolicy(schedule LTSSchedule, criticalDays int) *LTSPolicy {
+	return &LTSPolicy{Schedule: schedule, CriticalDays: criticalDays}
+}
+
+func (p *LTSPolicy) clock() time.Time {
+	if p.now != nil {
+		return p.now()
+	}
+	return time.Now()
+}
+
+// Support implements SupportSchedulePolicy
+func (p *LTSPolicy) Support(v *semver.Version) (string, time.Time, bool) {
+	line, ok := p.Schedule[v.Major()]
+	if !ok {
+		return "", time.Time{}, false
+	}
+	return line.Phase(p.clock()), line.End, true
+}
+
+func (p *LTSPolicy) Evaluate(
+	comparison *semver.Version,
+	comparisonDate time.Time,
+	latest *semver.Version,
+	latestDate time.Time,
+	newerReleases []types.Release,
+) PolicyResult {
+	phase, end, ok := p.Support(comparison)
+	if !ok {
+		if p.Fallback != nil {
+			return p.Fallback.Evaluate(comparison, comparisonDate, latest, latestDate, newerReleases)
+		}
+		return PolicyResult{IsWarning: len(newerReleases) > 0, Message: fmt.Sprintf("no support schedule for %d.x", comparison.Major())}
+	}
+
+	// Minor versions behind within the line; a newer line is no reason to move
+	// while this one is supported
+	minors := make(map[uint64]bool)
+	for _, r := range newerReleases {
+		if r.Version.Major() == comparison.Major() && r.Version.Minor() > comparison.Minor() {
+			minors[r.Version.Minor()] = true
+		}
+	}
+	behind := len(minors)
+
+	daysLeft := int(end.Sub(p.clock()).Hours() / 24)
+	result := PolicyResult{VersionsBehind: behind}
+	switch {
+	case phase == PhaseEndOfLife:
+		result.IsExpired = true
+		result.Message = fmt.Sprintf("%d.x reached end of life on %s", comparison.Major(), end.Format(time.DateOnly))
+	case daysLeft <= p.CriticalDays:
+		result.IsCritical = true
+		result.Message = fmt.Sprintf("%d.x reaches end of life in %d days", comparison.Major(), daysLeft)
+	default:
+		result.IsWarning = phase == PhaseMaintenance || len(newerReleases) > 0
+		result.Message = fmt.Sprintf("%d.x is in %s support until %s", comparison.Major(), phase, end.Format(time.DateOnly))
+	}
+	return result
+}
+
+// fallingBack reports whether every version goes to the fallback, as with no schedule
+func (p *LTSPolicy) fallingBack() bool {
+	return len(p.Schedule) == 0 && p.Fallback != nil
+}
+
+// Type is "lts", or the fallback's type without a schedule
+func (p *LTSPolicy) Type() string {
+	if p.fallingBack() {
+		return p.Fallback.Type()
+	}
+	return "lts"
+}
+
+func (p *LTSPolicy) GetCriticalDays() int {
+	if p.fallingBack() {
+		return p.Fallback.GetCriticalDays()
+	}
+	return p.CriticalDays
+}
+
+func (p *LTSPolicy) GetMaxDays() int {
+	if p.fallingBack() {
+		return p.Fallback.GetMaxDays()
+	}
+	return 0 // Not applicable
+}
+
+func (p *LTSPolicy) GetMaxVersionsBehind() int {
+	if p.fallingBack() {
+		return p.Fallback.GetMaxVersionsBehind()
+	}
+	return 0 // Not applicable
+}
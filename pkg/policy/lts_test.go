@@ -0,0 +1,122 @@
+package policy
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+const testNodeSchedule = `{
+  "v0.12": {"start": "2015-02-06", "end": "2016-12-31"},
+  "v18": {"start": "2022-04-19", "lts": "2022-10-25", "maintenance": "2023-10-18", "end": "2025-04-30", "codename": "Hydrogen"},
+  "v20": {"start": "2023-04-18", "lts": "2023-10-24", "maintenance": "2024-10-22", "end": "2026-04-30", "codename": "Iron"},
+  "v22": {"start": "2024-04-24", "lts": "2024-10-29", "maintenance": "2025-10-21", "end": "2027-04-30", "codename": "Jod"},
+  "v23": {"start": "2024-10-16", "maintenance": "2025-04-01", "end": "2025-06-01"},
+  "v24": {"start": "2025-05-06", "lts": "2025-10-28", "maintenance": "2026-10-20", "end": "2028-04-30", "codename": ""}
+}`
+
+func date(s string) time.Time {
+	t, _ := time.Parse(time.DateOnly, s)
+	return t
+}
+
+func TestParseNodeSchedule(t *testing.T) {
+	schedule, err := ParseNodeSchedule([]byte(testNodeSchedule))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(schedule) != 5 {
+		t.Errorf("schedule has %d lines, want 5 (v0.12 skipped)", len(schedule))
+	}
+	if got := schedule[20]; got.Codename != "Iron" || !got.End.Equal(date("2026-04-30")) {
+		t.Errorf("v20 = %+v", got)
+	}
+
+	for _, invalid := range []string{`[]`, `{"v20": {"start": "2023-04-18"}}`, `{"v20": {"end": "30 April 2026"}}`} {
+		if _, err := ParseNodeSchedule([]byte(invalid)); err == nil {
+			t.Errorf("ParseNodeSchedule(%s) succeeded, want an error", invalid)
+		}
+	}
+}
+
+func TestLTSLine_Phase(t *testing.T) {
+	schedule, _ := ParseNodeSchedule([]byte(testNodeSchedule))
+	tests := []struct {
+		major uint64
+		now   string
+		want  string
+	}{
+		{22, "2024-06-01", PhaseCurrent},
+		{22, "2024-10-29", PhaseActive},
+		{22, "2025-11-01", PhaseMaintenance},
+		{22, "2027-04-30", PhaseEndOfLife},
+		{23, "2025-01-01", PhaseCurrent}, // Odd lines never become LTS
+		{23, "2025-04-15", PhaseMaintenance},
+	}
+	for _, tt := range tests {
+		if got := schedule[tt.major].Phase(date(tt.now)); got != tt.want {
+			t.Errorf("v%d at %s = %s, want %s", tt.major, tt.now, got, tt.want)
+		}
+	}
+}
+
+func TestLTSPolicy_Evaluate(t *testing.T) {
+	schedule, _ := ParseNodeSchedule([]byte(testNodeSchedule))
+	p := NewLTSPolicy(schedule, 90)
+	p.now = func() time.Time { return date("2026-02-01") }
+
+	release := func(v string) types.Release { return types.Release{Version: semver.MustParse(v)} }
+	newer := []types.Release{release("22.21.0"), release("22.22.0"), release("24.13.0")}
+
+	tests := []struct {
+		name         string
+		comparison   string
+		newer        []types.Release
+		wantExpired  bool
+		wantCritical bool
+		wantWarning  bool
+		wantBehind   int
+		wantMessage  string
+	}{
+		{"end of life", "18.20.8", newer, true, false, false, 0, "18.x reached end of life on 2025-04-30"},
+		{"near end of life", "20.19.0", newer, false, true, false, 0, "20.x reaches end of life in 88 days"},
+		{"maintenance", "22.22.0", newer[2:], false, false, true, 0, "22.x is in maintenance support until 2027-04-30"},
+		{"active and behind", "24.11.0", []types.Release{release("24.12.0"), release("24.13.0")}, false, false, true, 2, "24.x is in active support"},
+		{"no schedule", "26.0.0", nil, false, false, false, 0, "no support schedule for 26.x"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := p.Evaluate(semver.MustParse(tt.comparison), time.Time{}, semver.MustParse("24.13.0"), time.Time{}, tt.newer)
+			if got.IsExpired != tt.wantExpired || got.IsCritical != tt.wantCritical || got.IsWarning != tt.wantWarning {
+				t.Errorf("Evaluate() = expired %v, critical %v, warning %v; want %v, %v, %v",
+					got.IsExpired, got.IsCritical, got.IsWarning, tt.wantExpired, tt.wantCritical, tt.wantWarning)
+			}
+			if got.VersionsBehind != tt.wantBehind {
+				t.Errorf("VersionsBehind = %d, want %d", got.VersionsBehind, tt.wantBehind)
+			}
+			if !strings.Contains(got.Message, tt.wantMessage) {
+				t.Errorf("Message = %q, want containing %q", got.Message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestLTSPolicy_Fallback(t *testing.T) {
+	p := NewLTSPolicy(nil, 90)
+	p.Fallback = NewVersionsPolicy(3)
+	if p.Type() != "versions" || p.GetMaxVersionsBehind() != 3 {
+		t.Errorf("without a schedule: Type() = %q, GetMaxVersionsBehind() = %d; want the fallback's", p.Type(), p.GetMaxVersionsBehind())
+	}
+	got := p.Evaluate(semver.MustParse("18.0.0"), time.Time{}, semver.MustParse("24.0.0"), time.Time{}, []types.Release{{Version: semver.MustParse("24.0.0")}})
+	if !got.IsExpired {
+		t.Errorf("fallback Evaluate() = %+v, want expired across majors", got)
+	}
+
+	p.Schedule, _ = ParseNodeSchedule([]byte(testNodeSchedule))
+	if p.Type() != "lts" || p.GetMaxVersionsBehind() != 0 || p.GetCriticalDays() != 90 {
+		t.Errorf("with a schedule: Type() = %q, want lts", p.Type())
+	}
+}
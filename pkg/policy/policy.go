@@ -61,6 +61,7 @@ func NewVersionsPolicy(maxMinorVersionsBehind int) *VersionsPolicy {
 type DaysPolicy struct {
 	CriticalDays int
 	MaxDays      int
+	Calendar     *Calendar // Maintenance windows updates are rolled out in; nil for any day
 }
 
 func (p *DaysPolicy) Evaluate(
@@ -97,9 +98,33 @@ func (p *DaysPolicy) GetCriticalDays() int      { return p.CriticalDays }
 func (p *DaysPolicy) GetMaxDays() int           { return p.MaxDays }
 func (p *DaysPolicy) GetMaxVersionsBehind() int { return 0 } // Not applicable
 
+// MaintenanceCalendar returns the policy's maintenance windows, if any
+func (p *DaysPolicy) MaintenanceCalendar() *Calendar { return p.Calendar }
+
+// ZeroMajor chooses how VersionsPolicy treats versions below 1.0.0
+type ZeroMajor string
+
+const (
+	// ZeroMajorBreaking treats 0.x minor bumps as breaking, as semver allows:
+	// the minor version acts as the major and the patch as the minor (default)
+	ZeroMajorBreaking ZeroMajor = "minor-breaking"
+	// ZeroMajorSemver counts 0.x versions like any other
+	ZeroMajorSemver ZeroMajor = "semver"
+)
+
 // VersionsPolicy implements version-based expiry
 type VersionsPolicy struct {
 	MaxMinorVersionsBehind int
+	ZeroMajor              ZeroMajor // Empty means ZeroMajorBreaking
+}
+
+// versionLine splits a version into its compatibility line and its step within
+// the line: major and minor, or 0.minor and patch when 0.x minors are breaking
+func (p *VersionsPolicy) versionLine(v, comparison *semver.Version) (line string, step uint64) {
+	if comparison.Major() == 0 && p.ZeroMajor != ZeroMajorSemver {
+		return fmt.Sprintf("%d.%d", v.Major(), v.Minor()), v.Patch()
+	}
+	return fmt.Sprintf("%d", v.Major()), v.Minor()
 }
 
 func (p *VersionsPolicy) Evaluate(
@@ -109,34 +134,34 @@ func (p *VersionsPolicy) Evaluate(
 	latestDate time.Time,
 	newerReleases []types.Release,
 ) PolicyResult {
-	if comparison.Equal(latest) {
+	if types.CompareVersions(comparison, latest) == 0 {
 		return PolicyResult{IsExpired: false, IsCritical: false}
 	}
 
 	// Count how many minor versions behind
 	minorVersionsBehind := 0
-	currentMinor := comparison.Minor()
-	seenMinors := make(map[uint64]bool)
+	currentLine, currentStep := p.versionLine(comparison, comparison)
+	seenSteps := make(map[uint64]bool)
 
 	for _, rel := range newerReleases {
-		// Only count distinct minor versions within the same major version
-		if rel.Version.Major() == comparison.Major() && rel.Version.Minor() > currentMinor {
-			if !seenMinors[rel.Version.Minor()] {
-				minorVersionsBehind++
-				seenMinors[rel.Version.Minor()] = true
-			}
-		}
+		line, step := p.versionLine(rel.Version, comparison)
 
-		// If major version changed, all bets are off - mark as expired
-		if rel.Version.Major() > comparison.Major() {
+		// If the major version (or a breaking 0.x minor) changed, all bets are off - mark as expired
+		if line != currentLine {
 			return PolicyResult{
 				IsExpired:      true,
 				IsCritical:     false,
 				IsWarning:      false,
 				VersionsBehind: minorVersionsBehind,
-				Message:        fmt.Sprintf("major version changed (%d -> %d)", comparison.Major(), rel.Version.Major()),
+				Message:        fmt.Sprintf("major version changed (%s -> %s)", currentLine, line),
 			}
 		}
+
+		// Only count distinct minor versions within the same major version
+		if step > currentStep && !seenSteps[step] {
+			minorVersionsBehind++
+			seenSteps[step] = true
+		}
 	}
 
 	isExpired := minorVersionsBehind > p.MaxMinorVersionsBehind
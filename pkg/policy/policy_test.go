@@ -246,6 +246,76 @@ func TestVersionsPolicy_Evaluate(t *testing.T) {
 	}
 }
 
+func TestVersionsPolicy_EvaluateZeroMajor(t *testing.T) {
+	tests := []struct {
+		name               string
+		zeroMajor          ZeroMajor
+		comparison         string
+		newerReleases      []types.Release
+		wantExpired        bool
+		wantCritical       bool
+		wantVersionsBehind int
+	}{
+		{
+			name:       "patches count as minors by default",
+			comparison: "0.11.40",
+			newerReleases: []types.Release{
+				makeRelease("0.11.43", 1),
+				makeRelease("0.11.42", 10),
+				makeRelease("0.11.41", 20),
+			},
+			wantCritical:       true,
+			wantVersionsBehind: 3,
+		},
+		{
+			name:       "minor bump is breaking by default",
+			comparison: "0.11.50",
+			newerReleases: []types.Release{
+				makeRelease("0.12.0", 1),
+			},
+			wantExpired: true,
+		},
+		{
+			name:       "semver counts minors",
+			zeroMajor:  ZeroMajorSemver,
+			comparison: "0.11.40",
+			newerReleases: []types.Release{
+				makeRelease("0.12.1", 1),
+				makeRelease("0.12.0", 10),
+				makeRelease("0.11.41", 20),
+			},
+			wantVersionsBehind: 1,
+		},
+		{
+			name:       "1.x unaffected",
+			comparison: "1.11.40",
+			newerReleases: []types.Release{
+				makeRelease("1.11.43", 1),
+				makeRelease("1.11.42", 10),
+			},
+			wantVersionsBehind: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := &VersionsPolicy{MaxMinorVersionsBehind: 3, ZeroMajor: tt.zeroMajor}
+			latest := tt.newerReleases[0].Version
+			result := policy.Evaluate(semver.MustParse(tt.comparison), time.Now(), latest, time.Now(), tt.newerReleases)
+
+			if result.IsExpired != tt.wantExpired {
+				t.Errorf("IsExpired = %v, want %v (%s)", result.IsExpired, tt.wantExpired, result.Message)
+			}
+			if result.IsCritical != tt.wantCritical {
+				t.Errorf("IsCritical = %v, want %v", result.IsCritical, tt.wantCritical)
+			}
+			if !tt.wantExpired && result.VersionsBehind != tt.wantVersionsBehind {
+				t.Errorf("VersionsBehind = %v, want %v", result.VersionsBehind, tt.wantVersionsBehind)
+			}
+		})
+	}
+}
+
 func TestNewDaysPolicy(t *testing.T) {
 	policy := NewDaysPolicy(12, 30)
 	if policy.Type() != "days" {
@@ -0,0 +1,153 @@
+// Package releaseset provides operations on lists of releases, such as merging
+// cached and fetched releases or finding the latest, for use with or without a
+// checker.Checker.
+//
+// Functions returning a list leave their input unchanged; the Sort functions
+// sort in place. Orders never depend on the order of the input, so output built
+// from them is the same from run to run.
+package releaseset
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+// Merge combines release lists into one without duplicate versions. Where a
+// version appears more than once the first is kept, so pass the most
+// authoritative list first.
+func Merge(lists ...[]types.Release) []types.Release {
+	seen := make(map[string]bool)
+	var merged []types.Release
+	for _, releases := range lists {
+		for _, r := range releases {
+			key := r.Version.String()
+			if !seen[key] {
+				seen[key] = true
+				merged = append(merged, r)
+			}
+		}
+	}
+	return merged
+}
+
+// Dedupe returns the releases without duplicate versions, keeping the first of each
+func Dedupe(releases []types.Release) []types.Release {
+	return Merge(releases)
+}
+
+// Latest returns the release with the highest version, or nil if there are none.
+// The result points into releases; of equal versions, the first is returned.
+func Latest(releases []types.Release) *types.Release {
+	if len(releases) == 0 {
+		return nil
+	}
+
+	latest := &releases[0]
+	for i := range releases {
+		if types.CompareVersions(releases[i].Version, latest.Version) > 0 {
+			latest = &releases[i]
+		}
+	}
+	return latest
+}
+
+// Compare orders two releases by version, as types.CompareVersions does, and
+// versions of equal precedence, such as ones differing only in build metadata,
+// by their tags
+func Compare(a, b types.Release) int {
+	if c := types.CompareVersions(a.Version, b.Version); c != 0 {
+		return c
+	}
+	if c := strings.Compare(a.Version.String(), b.Version.String()); c != 0 {
+		return c
+	}
+	return strings.Compare(a.Version.Original(), b.Version.Original())
+}
+
+// SortByVersion sorts releases in place, highest version first
+func SortByVersion(releases []types.Release) {
+	sort.SliceStable(releases, func(i, j int) bool {
+		return Compare(releases[i], releases[j]) > 0
+	})
+}
+
+// SortByDate sorts releases in place, most recently published first; releases
+// published at the same time, e.g. when only the day is known, highest version first
+func SortByDate(releases []types.Release) {
+	sort.SliceStable(releases, func(i, j int) bool {
+		if c := releases[i].PublishedAt.Compare(releases[j].PublishedAt); c != 0 {
+			return c > 0
+		}
+		return Compare(releases[i], releases[j]) > 0
+	})
+}
+
+// FilterStable returns the releases whose version has no prerelease part
+// and which are not marked as prereleases
+func FilterStable(releases []types.Release) []types.Release {
+	var stable []types.Release
+	for _, r := range releases {
+		if r.Version.Prerelease() == "" && !r.Prerelease {
+			stable = append(stable, r)
+		}
+	}
+	return stable
+}
+
+// WithoutPrereleases returns the releases not marked as prereleases on GitHub
+func WithoutPrereleases(releases []types.Release) []types.Release {
+	kept := make([]types.Release, 0, len(releases))
+	for _, r := range releases {
+		if !r.Prerelease {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
+
+// Line returns the releases of one minor version line, e.g. all 1.29.x, highest
+// version first
+func Line(releases []types.Release, major, minor uint64) []types.Release {
+	var line []types.Release
+	for _, r := range releases {
+		if r.Version.Major() == major && r.Version.Minor() == minor {
+			line = append(line, r)
+		}
+	}
+	SortByVersion(line)
+	return line
+}
+
+// GroupByMinor splits releases into minor version lines, highest line first and
+// each line's releases highest version first
+func GroupByMinor(releases []types.Release) [][]types.Release {
+	sorted := append([]types.Release(nil), releases...)
+	SortByVersion(sorted)
+
+	var groups [][]types.Release
+	for _, r := range sorted {
+		if n := len(groups); n > 0 {
+			prev := groups[n-1][0].Version
+			if prev.Major() == r.Version.Major() && prev.Minor() == r.Version.Minor() {
+				groups[n-1] = append(groups[n-1], r)
+				continue
+			}
+		}
+		groups = append(groups, []types.Release{r})
+	}
+	return groups
+}
+
+// NewerThan returns the releases with a higher version than v, in their original order
+func NewerThan(releases []types.Release, v *semver.Version) []types.Release {
+	var newer []types.Release
+	for _, r := range releases {
+		if types.CompareVersions(r.Version, v) > 0 {
+			newer = append(newer, r)
+		}
+	}
+	return newer
+}
@@ -0,0 +1,170 @@
+package releaseset
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+// release builds a release published on day of October 2025
+func release(version string, day int) types.Release {
+	return types.Release{
+		Version:     semver.MustParse(version),
+		PublishedAt: time.Date(2025, 10, day, 0, 0, 0, 0, time.UTC),
+		URL:         "https://github.com/actions/runner/releases/tag/v" + version,
+	}
+}
+
+// versions joins the versions of releases, for comparison
+func versions(releases []types.Release) string {
+	parts := make([]string, len(releases))
+	for i, r := range releases {
+		parts[i] = r.Version.String()
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestMerge(t *testing.T) {
+	recent := []types.Release{release("2.329.0", 14), release("2.328.0", 13)}
+	embedded := []types.Release{release("2.328.0", 1), release("2.327.1", 2)}
+
+	merged := Merge(recent, embedded)
+	if got, want := versions(merged), "2.329.0 2.328.0 2.327.1"; got != want {
+		t.Errorf("Merge() = %s, want %s", got, want)
+	}
+	// The first list wins for duplicate versions
+	if merged[1].PublishedAt.Day() != 13 {
+		t.Errorf("expected 2.328.0 from the first list, got published day %d", merged[1].PublishedAt.Day())
+	}
+}
+
+func TestDedupe(t *testing.T) {
+	releases := []types.Release{release("1.0.0", 1), release("1.1.0", 2), release("1.0.0", 3), release("1.0.0+rev.1", 4)}
+	if got, want := versions(Dedupe(releases)), "1.0.0 1.1.0 1.0.0+rev.1"; got != want {
+		t.Errorf("Dedupe() = %s, want %s", got, want)
+	}
+}
+
+func TestLatest(t *testing.T) {
+	tests := []struct {
+		name     string
+		releases []types.Release
+		want     string
+	}{
+		{"empty", nil, ""},
+		{"highest version, not most recent", []types.Release{release("2.9.0", 20), release("2.10.0", 1)}, "2.10.0"},
+		{"prerelease below release", []types.Release{release("3.0.0-rc.1", 20), release("3.0.0", 1)}, "3.0.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			latest := Latest(tt.releases)
+			if tt.want == "" {
+				if latest != nil {
+					t.Errorf("Latest() = %s, want nil", latest.Version)
+				}
+				return
+			}
+			if latest == nil || latest.Version.String() != tt.want {
+				t.Errorf("Latest() = %v, want %s", latest, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortByVersion(t *testing.T) {
+	releases := []types.Release{release("1.2.0", 3), release("1.10.0", 1), release("1.2.0+rev.1", 2), release("1.9.0", 4)}
+	SortByVersion(releases)
+	if got, want := versions(releases), "1.10.0 1.9.0 1.2.0+rev.1 1.2.0"; got != want {
+		t.Errorf("SortByVersion() = %s, want %s", got, want)
+	}
+}
+
+func TestSortByDate(t *testing.T) {
+	// Backports make date order differ from version order
+	releases := []types.Release{release("1.9.0", 2), release("1.8.5", 10), release("1.9.1", 5)}
+	SortByDate(releases)
+	if got, want := versions(releases), "1.8.5 1.9.1 1.9.0"; got != want {
+		t.Errorf("SortByDate() = %s, want %s", got, want)
+	}
+}
+
+func TestSort_IgnoresInputOrder(t *testing.T) {
+	// Same-day releases and versions differing only in build metadata tie on
+	// the sort key, so they must not keep whatever order they came in
+	releases := []types.Release{release("1.9.1", 5), release("1.9.0", 5), release("1.8.5", 5), release("1.2.0+build.2", 1), release("1.2.0+build.1", 1)}
+	tests := []struct {
+		name string
+		sort func([]types.Release)
+		want string
+	}{
+		{"by version", SortByVersion, "1.9.1 1.9.0 1.8.5 1.2.0+build.2 1.2.0+build.1"},
+		{"by date", SortByDate, "1.9.1 1.9.0 1.8.5 1.2.0+build.2 1.2.0+build.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for shift := range releases {
+				input := append(append([]types.Release(nil), releases[shift:]...), releases[:shift]...)
+				slices.Reverse(input[:shift])
+				tt.sort(input)
+				if got := versions(input); got != tt.want {
+					t.Errorf("rotated by %d: got %s, want %s", shift, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestFilterStable(t *testing.T) {
+	releases := []types.Release{release("2.0.0-beta.1", 1), release("1.9.0", 2), release("2.0.0-rc.1", 3), release("2.0.0", 4)}
+	if got, want := versions(FilterStable(releases)), "1.9.0 2.0.0"; got != want {
+		t.Errorf("FilterStable() = %s, want %s", got, want)
+	}
+
+	marked := release("2.1.0", 5)
+	marked.Prerelease = true
+	if got, want := versions(FilterStable(append(releases, marked))), "1.9.0 2.0.0"; got != want {
+		t.Errorf("FilterStable() with marked prerelease = %s, want %s", got, want)
+	}
+}
+
+func TestWithoutPrereleases(t *testing.T) {
+	marked := release("2.1.0", 3)
+	marked.Prerelease = true
+	releases := []types.Release{release("2.0.0-rc.1", 1), release("2.0.0", 2), marked}
+	if got, want := versions(WithoutPrereleases(releases)), "2.0.0-rc.1 2.0.0"; got != want {
+		t.Errorf("WithoutPrereleases() = %s, want %s", got, want)
+	}
+}
+
+func TestNewerThan(t *testing.T) {
+	releases := []types.Release{release("2.329.0", 14), release("2.327.1", 2), release("2.328.0", 13), release("2.327.1+rev.1", 3)}
+	if got, want := versions(NewerThan(releases, semver.MustParse("2.327.1"))), "2.329.0 2.328.0 2.327.1+rev.1"; got != want {
+		t.Errorf("NewerThan() = %s, want %s", got, want)
+	}
+}
+
+func TestLine(t *testing.T) {
+	releases := []types.Release{release("1.29.0", 1), release("1.30.0", 2), release("1.29.4", 3), release("2.29.1", 4)}
+	if got, want := versions(Line(releases, 1, 29)), "1.29.4 1.29.0"; got != want {
+		t.Errorf("Line() = %s, want %s", got, want)
+	}
+	if got := Line(releases, 1, 31); len(got) != 0 {
+		t.Errorf("Line() = %s, want none", versions(got))
+	}
+}
+
+func TestGroupByMinor(t *testing.T) {
+	releases := []types.Release{release("1.29.0", 1), release("1.30.0", 2), release("1.29.4", 3), release("2.0.0", 4)}
+	groups := GroupByMinor(releases)
+	var got []string
+	for _, g := range groups {
+		got = append(got, versions(g))
+	}
+	if want := "2.0.0|1.30.0|1.29.4 1.29.0"; strings.Join(got, "|") != want {
+		t.Errorf("GroupByMinor() = %s, want %s", strings.Join(got, "|"), want)
+	}
+}
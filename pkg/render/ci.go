@@ -0,0 +1,155 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nickromney-org/github-release-version-checker/pkg/checker"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+// AnnotationNone suppresses the annotation for a status
+const AnnotationNone = "none"
+
+// DefaultAnnotationLevels maps each status to its GitHub Actions workflow command
+var DefaultAnnotationLevels = map[checker.Status]string{
+	checker.StatusCurrent:  "notice",
+	checker.StatusWarning:  "notice",
+	checker.StatusCritical: "warning",
+	checker.StatusExpired:  "error",
+}
+
+// annotationTitles holds the annotation title for each status
+var annotationTitles = map[checker.Status]string{
+	checker.StatusCurrent:  "Runner Version Current",
+	checker.StatusWarning:  "Runner Version Behind",
+	checker.StatusCritical: "Runner Version Critical",
+	checker.StatusExpired:  "Runner Version Expired",
+}
+
+// Annotation returns the workflow command for a status, or "" if suppressed
+func Annotation(levels map[checker.Status]string, status checker.Status, message string) string {
+	level, ok := levels[status]
+	if !ok || level == AnnotationNone {
+		return ""
+	}
+	return fmt.Sprintf("::%s title=%s::%s", level, annotationTitles[status], message)
+}
+
+// CI renders an analysis as GitHub Actions workflow commands. The first line is
+// always the latest version, for script compatibility.
+func CI(analysis *checker.Analysis, opts Options) string {
+	var b strings.Builder
+	fmt.Fprintln(&b, types.FormatVersion(analysis.LatestVersion))
+
+	if analysis.IsDegraded() {
+		fmt.Fprintf(&b, "::warning title=Incomplete release data::%s\n", DescribeDegraded(analysis.DegradedReasons))
+	}
+	if analysis.LatestDiscrepancy() {
+		fmt.Fprintf(&b, "::notice title=Latest release discrepancy::%s\n", DescribeLatestDiscrepancy(analysis))
+	}
+
+	if analysis.ComparisonVersion == nil {
+		return b.String()
+	}
+
+	status := analysis.Status()
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, "::group::📊 Runner Version Check")
+	fmt.Fprintf(&b, "Latest version: v%s\n", types.FormatVersion(analysis.LatestVersion))
+	fmt.Fprintf(&b, "Your version: v%s\n", types.FormatVersion(analysis.ComparisonVersion))
+	if analysis.Channel != "" {
+		fmt.Fprintf(&b, "Channel: %s\n", analysis.Channel)
+	}
+	if analysis.ResolvedFrom != "" {
+		fmt.Fprintf(&b, "Resolved from: %s\n", analysis.ResolvedFrom)
+	}
+	if analysis.Upstream != "" {
+		fmt.Fprintf(&b, "Upstream: %s\n", analysis.Upstream)
+	}
+	fmt.Fprintf(&b, "Status: %s\n", StatusText(status))
+	fmt.Fprintln(&b, "::endgroup::")
+	fmt.Fprintln(&b)
+
+	levels := opts.AnnotationLevels
+	if levels == nil {
+		levels = DefaultAnnotationLevels
+	}
+	if annotation := Annotation(levels, status, StatusIcon(status)+" "+statusLine(analysis, opts, false)); annotation != "" {
+		fmt.Fprintln(&b, annotation)
+	}
+	if waiver := DescribeWaiver(analysis, opts); waiver != "" {
+		fmt.Fprintf(&b, "::notice title=Expiry waiver::%s\n", waiver)
+	}
+	if rollbacks := DescribeRollbacks(analysis, opts); rollbacks != "" {
+		fmt.Fprintf(&b, "::notice title=Upstream rollback::%s\n", rollbacks)
+	}
+	if yanked := DescribeYanked(analysis); yanked != "" {
+		fmt.Fprintf(&b, "::warning title=Known-bad release::%s\n", yanked)
+	}
+	if soaking := DescribeSoaking(analysis, opts); soaking != "" {
+		fmt.Fprintf(&b, "::notice title=Release soaking::%s\n", soaking)
+	}
+	if assets := DescribeAssets(analysis); assets != "" {
+		fmt.Fprintf(&b, "::warning title=Release assets missing::%s\n", assets)
+	}
+	if review := DescribeReview(analysis); review != "" {
+		fmt.Fprintf(&b, "::notice title=Manual review::%s\n", review)
+	}
+
+	if len(analysis.RecentReleases) > 0 {
+		fmt.Fprintln(&b)
+		fmt.Fprintln(&b, "::group::📅 Release Expiry Timeline")
+		fmt.Fprintf(&b, "%-10s %-14s %-14s %s\n", "Version", "Release Date", "Expiry Date", "Status")
+
+		for _, release := range analysis.RecentReleases {
+			var expiresStr, statusStr string
+			if release.IsLatest {
+				expiresStr = "-"
+				statusStr = fmt.Sprintf("Latest (%s)", FormatDaysAgo(opts.daysSince(release.ReleasedAt)))
+			} else if release.ExpiresAt != nil {
+				expiresStr = opts.FormatDate(*release.ExpiresAt)
+				if release.IsExpired {
+					statusStr = fmt.Sprintf("Expired %s", FormatDaysAgo(-release.DaysUntilExpiry))
+				} else {
+					statusStr = fmt.Sprintf("Valid (%s left)", FormatDaysInFuture(release.DaysUntilExpiry))
+				}
+			}
+
+			arrow := ""
+			if types.CompareVersions(release.Version, analysis.ComparisonVersion) == 0 {
+				arrow = "  [Your version]"
+			}
+
+			fmt.Fprintf(&b, "  %-10s %-14s %-14s %s%s\n",
+				types.FormatVersion(release.Version), opts.FormatDate(release.ReleasedAt), expiresStr, statusStr, arrow)
+		}
+
+		fmt.Fprintf(&b, "\n  Checked at: %s\n", opts.checkedAt())
+		fmt.Fprintln(&b, "::endgroup::")
+	}
+
+	return b.String()
+}
+
+// Outputs returns the step outputs for $GITHUB_OUTPUT as ordered name/value pairs
+func Outputs(analysis *checker.Analysis) [][2]string {
+	var links checker.Links
+	if analysis.Links != nil {
+		links = *analysis.Links
+	}
+	return [][2]string{
+		{"latest_version", analysis.LatestVersion.String()},
+		{"status", string(analysis.Status())},
+		{"releases_behind", fmt.Sprintf("%d", analysis.ReleasesBehind)},
+		{"recommended_version", analysis.Recommended().String()},
+		{"degraded", fmt.Sprintf("%t", analysis.IsDegraded())},
+		{"latest_discrepancy", fmt.Sprintf("%t", analysis.LatestDiscrepancy())},
+		{"waived", fmt.Sprintf("%t", analysis.Waived)},
+		{"drift_score", fmt.Sprintf("%d", analysis.DriftScore())},
+		{"yanked", fmt.Sprintf("%t", analysis.Yanked != nil)},
+		{"requires_manual_review", fmt.Sprintf("%t", analysis.RequiresManualReview)},
+		{"release_url", links.Recommended},
+		{"compare_url", links.Compare},
+	}
+}
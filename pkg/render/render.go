@@ -0,0 +1,396 @@
+// Package render formats analyses for the terminal, GitHub Actions (CI), job
+// summaries and JSON. Each renderer returns the output rather than writing it,
+// so callers decide where it goes and tests can compare it with golden files.
+package render
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	colour "github.com/fatih/color"
+	"github.com/nickromney-org/github-release-version-checker/pkg/checker"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+// DateFormat pairs a date layout with the matching timestamp layout
+type DateFormat struct {
+	Date      string
+	Timestamp string
+}
+
+// DateFormats maps preset names to layouts
+var DateFormats = map[string]DateFormat{
+	"uk":  {Date: "02 Jan 2006", Timestamp: "2 Jan 2006 15:04:05 MST"},
+	"us":  {Date: "Jan 02, 2006", Timestamp: "Jan 2, 2006 15:04:05 MST"},
+	"eu":  {Date: "02.01.2006", Timestamp: "02.01.2006 15:04:05 MST"},
+	"iso": {Date: "2006-01-02", Timestamp: time.RFC3339},
+}
+
+// Options control how an analysis is rendered. The zero value renders with
+// UK dates, every timeline column, no width limit and the current time.
+type Options struct {
+	DateFormat       DateFormat                // Zero value: DateFormats["uk"]
+	Columns          []string                  // Timeline columns; nil shows Columns
+	MaxWidth         int                       // Terminal width limit; 0 = no limit
+	Quiet            bool                      // Terminal: omit the timeline
+	Details          bool                      // Terminal: add the detailed analysis
+	AnnotationLevels map[checker.Status]string // CI: nil uses DefaultAnnotationLevels
+	SummaryExclude   []string                  // Job summary sections to leave out
+	Now              time.Time                 // Reference time; zero = time.Now()
+}
+
+// now returns the reference time for ages and "Checked at" stamps
+func (o Options) now() time.Time {
+	if o.Now.IsZero() {
+		return time.Now()
+	}
+	return o.Now
+}
+
+func (o Options) dateFormat() DateFormat {
+	if o.DateFormat.Date == "" {
+		return DateFormats["uk"]
+	}
+	return o.DateFormat
+}
+
+// FormatDate formats a date with the options' date format
+func (o Options) FormatDate(t time.Time) string {
+	return t.Format(o.dateFormat().Date)
+}
+
+// FormatTimestamp formats a date and time with the options' date format
+func (o Options) FormatTimestamp(t time.Time) string {
+	return t.Format(o.dateFormat().Timestamp)
+}
+
+// checkedAt is the "Checked at" stamp closing each report
+func (o Options) checkedAt() string {
+	return o.FormatTimestamp(o.now().UTC())
+}
+
+// daysSince returns whole days from t to the reference time
+func (o Options) daysSince(t time.Time) int {
+	return int(o.now().Sub(t).Hours() / 24)
+}
+
+// Terminal colours
+var (
+	green  = colour.New(colour.FgGreen, colour.Bold)
+	yellow = colour.New(colour.FgYellow, colour.Bold)
+	red    = colour.New(colour.FgRed, colour.Bold)
+	cyan   = colour.New(colour.FgCyan)
+	grey   = colour.New(colour.FgHiBlack) // Faint grey for timestamps
+	bold   = colour.New(colour.Bold)
+)
+
+// StatusText names a status for display
+func StatusText(status checker.Status) string {
+	switch status {
+	case checker.StatusCurrent:
+		return "Current"
+	case checker.StatusWarning:
+		return "Behind"
+	case checker.StatusCritical:
+		return "Critical"
+	case checker.StatusExpired:
+		return "Expired"
+	default:
+		return "Unknown"
+	}
+}
+
+// StatusIcon returns the emoji shown with a status
+func StatusIcon(status checker.Status) string {
+	switch status {
+	case checker.StatusCurrent:
+		return "✅"
+	case checker.StatusWarning:
+		return "⚠️ "
+	case checker.StatusCritical:
+		return "🔶"
+	case checker.StatusExpired:
+		return "🚨"
+	default:
+		return "ℹ️ "
+	}
+}
+
+// StatusColour returns the terminal colour for a status
+func StatusColour(status checker.Status) *colour.Color {
+	switch status {
+	case checker.StatusCurrent:
+		return green
+	case checker.StatusWarning, checker.StatusCritical:
+		return yellow
+	case checker.StatusExpired:
+		return red
+	default:
+		return cyan
+	}
+}
+
+// FormatDaysAgo returns a human-readable string for days
+func FormatDaysAgo(days int) string {
+	if days < 0 {
+		return FormatDaysInFuture(-days)
+	}
+	if days == 0 {
+		return "today"
+	}
+	if days == 1 {
+		return "1 day ago"
+	}
+	return fmt.Sprintf("%d days ago", days)
+}
+
+// FormatDaysInFuture returns a human-readable string for future days
+func FormatDaysInFuture(days int) string {
+	if days == 0 {
+		return "today"
+	}
+	if days == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", days)
+}
+
+// DescribeDegraded explains why an analysis may have missed releases
+func DescribeDegraded(reasons []checker.DegradedReason) string {
+	var parts []string
+	for _, reason := range reasons {
+		switch reason {
+		case checker.DegradedTruncated:
+			parts = append(parts, "the release list was cut off at 1,000 releases, so older releases were not checked")
+		default:
+			parts = append(parts, string(reason))
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+// DescribeLatestDiscrepancy explains that GitHub marks a release other than the
+// highest version as latest, and which one the check used
+func DescribeLatestDiscrepancy(analysis *checker.Analysis) string {
+	using := "using the highest version; --latest-from marked follows GitHub"
+	if types.CompareVersions(analysis.LatestVersion, analysis.MarkedLatest) == 0 {
+		using = "following GitHub; --latest-from highest uses the highest version"
+	}
+	return fmt.Sprintf("GitHub marks v%s as latest, but v%s is the highest version (%s)",
+		types.FormatVersion(analysis.MarkedLatest), types.FormatVersion(analysis.HighestVersion), using)
+}
+
+// DescribePrereleaseAhead explains that a prerelease is newer than the latest
+// stable release, and which one the check used
+func DescribePrereleaseAhead(analysis *checker.Analysis) string {
+	using := "tracking stable releases; --track prerelease follows prereleases"
+	switch {
+	case analysis.ReleaseChannel != "":
+		using = fmt.Sprintf("checking the %s channel", analysis.ReleaseChannel)
+	case types.CompareVersions(analysis.LatestVersion, analysis.LatestIncludingPrerelease) == 0:
+		using = "tracking prereleases; --track stable ignores them"
+	}
+	return fmt.Sprintf("Prerelease v%s is newer than the latest stable release v%s (%s)",
+		analysis.LatestIncludingPrerelease, analysis.LatestStable, using)
+}
+
+// DescribeWaiver describes the analysis's waiver for audit, or "" if it has none
+func DescribeWaiver(analysis *checker.Analysis, opts Options) string {
+	w := analysis.Waiver
+	if w == nil {
+		return ""
+	}
+	switch {
+	case analysis.Waived:
+		return fmt.Sprintf("Expiry waived until %s, approved by %s: %s", opts.FormatDate(w.Expires), w.ApprovedBy, w.Reason)
+	case !w.Active(opts.now()):
+		return fmt.Sprintf("Waiver lapsed %s (approved by %s: %s)", opts.FormatDate(w.Expires), w.ApprovedBy, w.Reason)
+	default:
+		return fmt.Sprintf("Waiver on file until %s, approved by %s: %s", opts.FormatDate(w.Expires), w.ApprovedBy, w.Reason)
+	}
+}
+
+// DescribeRollbacks describes the newer releases set aside while upstream rolls
+// them back, or returns "" if there are none
+func DescribeRollbacks(analysis *checker.Analysis, opts Options) string {
+	parts := make([]string, len(analysis.RolledBack))
+	for i, w := range analysis.RolledBack {
+		parts[i] = fmt.Sprintf("v%s set aside until %s while upstream rolls it back, approved by %s: %s",
+			w.Version, opts.FormatDate(w.Expires), w.ApprovedBy, w.Reason)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// DescribeYanked explains a yanked comparison or latest version and what is
+// recommended instead, or returns "" if neither is yanked
+func DescribeYanked(analysis *checker.Analysis) string {
+	recommended := analysis.Recommended()
+	switch {
+	case analysis.Yanked != nil && types.CompareVersions(recommended, analysis.ComparisonVersion) != 0:
+		return fmt.Sprintf("Version %s is a known-bad release (%s): use v%s instead", types.FormatVersion(analysis.ComparisonVersion), analysis.Yanked.Reason, types.FormatVersion(recommended))
+	case analysis.Yanked != nil:
+		return fmt.Sprintf("Version %s is a known-bad release (%s), with no alternative available", types.FormatVersion(analysis.ComparisonVersion), analysis.Yanked.Reason)
+	case analysis.RecommendedVersion != nil && analysis.IncompleteRelease == nil && analysis.SoakingVersion == nil:
+		return fmt.Sprintf("Latest release v%s is a known-bad release; v%s is recommended instead", types.FormatVersion(analysis.LatestVersion), types.FormatVersion(recommended))
+	default:
+		return ""
+	}
+}
+
+// DescribeSoaking explains a release held back as too new to recommend, or
+// returns "" if none was
+func DescribeSoaking(analysis *checker.Analysis, opts Options) string {
+	soaking := analysis.SoakingVersion
+	if soaking == nil {
+		return ""
+	}
+	held := fmt.Sprintf("v%s is still soaking", types.FormatVersion(soaking))
+	for _, r := range analysis.NewerReleases {
+		if types.CompareVersions(r.Version, soaking) == 0 {
+			held = fmt.Sprintf("v%s (released %s) is still soaking", types.FormatVersion(soaking), opts.FormatDate(r.PublishedAt))
+		}
+	}
+	switch recommended := analysis.Recommended(); {
+	case types.CompareVersions(recommended, soaking) == 0:
+		return held
+	case analysis.ComparisonVersion != nil && types.CompareVersions(recommended, analysis.ComparisonVersion) == 0:
+		return fmt.Sprintf("%s; stay on v%s until it has been out longer", held, types.FormatVersion(recommended))
+	default:
+		return fmt.Sprintf("%s; v%s is recommended until then", held, types.FormatVersion(recommended))
+	}
+}
+
+// DescribeAssets explains a release held back because it lacks assets for
+// required platforms, or returns "" if none was
+func DescribeAssets(analysis *checker.Analysis) string {
+	incomplete := analysis.IncompleteRelease
+	if incomplete == nil {
+		return ""
+	}
+	missing := fmt.Sprintf("v%s has no assets for %s yet", incomplete, strings.Join(analysis.MissingAssets, ", "))
+	switch recommended := analysis.Recommended(); {
+	case types.CompareVersions(recommended, incomplete) == 0:
+		return missing
+	case analysis.ComparisonVersion != nil && types.CompareVersions(recommended, analysis.ComparisonVersion) == 0:
+		return fmt.Sprintf("%s; stay on v%s until they are published", missing, types.FormatVersion(recommended))
+	default:
+		return fmt.Sprintf("%s; v%s is recommended instead", missing, types.FormatVersion(recommended))
+	}
+}
+
+// DescribeReview explains why updating to the recommended version needs manual
+// review, or returns "" if it does not
+func DescribeReview(analysis *checker.Analysis) string {
+	if !analysis.RequiresManualReview {
+		return ""
+	}
+	return fmt.Sprintf("Updating to v%s needs manual review: %s", types.FormatVersion(analysis.Recommended()), strings.Join(analysis.ReviewReasons, "; "))
+}
+
+// FormatReleasesBehind returns how many releases behind the analysed version
+// is, with the kinds of release they are, e.g. "6 (all patches)" or
+// "3 (1 minor, 2 patches)"
+func FormatReleasesBehind(analysis *checker.Analysis) string {
+	type kind struct {
+		count          int
+		single, plural string
+	}
+	var kinds []kind
+	total := 0
+	for _, k := range []kind{
+		{analysis.MajorsBehind, "major", "majors"},
+		{analysis.MinorsBehind, "minor", "minors"},
+		{analysis.PatchesBehind, "patch", "patches"},
+	} {
+		if k.count > 0 {
+			kinds = append(kinds, k)
+			total += k.count
+		}
+	}
+	// Analyses cached before releases were counted by kind have no counts
+	if total == 0 || total != analysis.ReleasesBehind {
+		return fmt.Sprintf("%d", analysis.ReleasesBehind)
+	}
+	if len(kinds) == 1 {
+		if total == 1 {
+			return fmt.Sprintf("1 (%s)", kinds[0].single)
+		}
+		return fmt.Sprintf("%d (all %s)", total, kinds[0].plural)
+	}
+	parts := make([]string, len(kinds))
+	for i, k := range kinds {
+		name := k.plural
+		if k.count == 1 {
+			name = k.single
+		}
+		parts[i] = fmt.Sprintf("%d %s", k.count, name)
+	}
+	return fmt.Sprintf("%d (%s)", total, strings.Join(parts, ", "))
+}
+
+// JSON returns the analysis as a JSON document
+func JSON(analysis *checker.Analysis) ([]byte, error) {
+	return analysis.MarshalJSON()
+}
+
+// statusLine describes the comparison version's standing in one line, e.g.
+// "Version 2.327.0 (25 Jul 2025) EXPIRED 24 Aug 2025: Update to v2.329.0"
+func statusLine(analysis *checker.Analysis, opts Options, versionPolicy bool) string {
+	if analysis.IsLatest {
+		if analysis.ComparisonReleasedAt != nil {
+			return fmt.Sprintf("Version %s (%s) is the latest version",
+				types.FormatVersion(analysis.ComparisonVersion), opts.FormatDate(*analysis.ComparisonReleasedAt))
+		}
+		return fmt.Sprintf("Version %s is the latest version", types.FormatVersion(analysis.ComparisonVersion))
+	}
+
+	comparisonDate := ""
+	if analysis.ComparisonReleasedAt != nil {
+		comparisonDate = fmt.Sprintf(" (%s)", opts.FormatDate(*analysis.ComparisonReleasedAt))
+	}
+
+	expiryInfo := ""
+	if versionPolicy {
+		// For version-based policies, show version skew info
+		if analysis.IsExpired {
+			expiryInfo = fmt.Sprintf(" UNSUPPORTED (%d minor versions behind)", analysis.MinorVersionsBehind)
+		} else if analysis.Waived {
+			expiryInfo = fmt.Sprintf(" UNSUPPORTED (%d minor versions behind, waived until %s)", analysis.MinorVersionsBehind, opts.FormatDate(analysis.Waiver.Expires))
+		} else if analysis.IsCritical {
+			expiryInfo = fmt.Sprintf(" CRITICAL (%d minor versions behind)", analysis.MinorVersionsBehind)
+		} else if analysis.MinorVersionsBehind > 0 {
+			expiryInfo = fmt.Sprintf(" (%d minor versions behind)", analysis.MinorVersionsBehind)
+		}
+	} else if expiryDate := analysis.ExpiryDate(); expiryDate != nil {
+		// For days-based policies, show expiry dates
+		if analysis.IsExpired {
+			expiryInfo = fmt.Sprintf(" EXPIRED %s", opts.FormatDate(*expiryDate))
+		} else if analysis.Waived {
+			expiryInfo = fmt.Sprintf(" EXPIRED %s (waived until %s)", opts.FormatDate(*expiryDate), opts.FormatDate(analysis.Waiver.Expires))
+		} else if window := analysis.MaintenanceWindow; window != nil {
+			// The maintenance window is the real deadline
+			if window.Before(opts.now().Truncate(24 * time.Hour)) {
+				expiryInfo = fmt.Sprintf(" missed its last maintenance window, %s, and expires %s", opts.FormatDate(*window), opts.FormatDate(*expiryDate))
+			} else {
+				expiryInfo = fmt.Sprintf(" must be included in the %s maintenance window (expires %s)", opts.FormatDate(*window), opts.FormatDate(*expiryDate))
+			}
+		} else if analysis.IsCritical {
+			expiryInfo = fmt.Sprintf(" EXPIRES %s (%d days)", opts.FormatDate(*expiryDate), analysis.DaysUntilExpiry())
+		} else {
+			expiryInfo = fmt.Sprintf(" expires %s", opts.FormatDate(*expiryDate))
+		}
+	}
+
+	recommended := analysis.Recommended()
+	recommendedDate := ""
+	for _, r := range analysis.RecentReleases {
+		if types.CompareVersions(r.Version, recommended) == 0 {
+			recommendedDate = fmt.Sprintf(" (Released %s)", opts.FormatDate(r.ReleasedAt))
+			break
+		}
+	}
+
+	return fmt.Sprintf("Version %s%s%s: Update to v%s%s",
+		types.FormatVersion(analysis.ComparisonVersion), comparisonDate, expiryInfo, types.FormatVersion(recommended), recommendedDate)
+}
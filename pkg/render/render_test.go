@@ -0,0 +1,685 @@
+package render
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	colour "github.com/fatih/color"
+	"github.com/nickromney-org/github-release-version-checker/pkg/checker"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+func TestMain(m *testing.M) {
+	colour.NoColor = true
+	os.Exit(m.Run())
+}
+
+// testNow is the reference time for every golden file
+var testNow = time.Date(2025, 10, 20, 9, 30, 0, 0, time.UTC)
+
+func mustVersion(v string) *semver.Version {
+	return semver.MustParse(v)
+}
+
+func day(s string) time.Time {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func dayPtr(s string) *time.Time {
+	t := day(s)
+	return &t
+}
+
+// runnerTimeline is the actions/runner timeline used by the days-based analyses
+func runnerTimeline() []checker.ReleaseExpiry {
+	return []checker.ReleaseExpiry{
+		{Version: mustVersion("2.329.0"), ReleasedAt: day("2025-10-14"), IsLatest: true},
+		{Version: mustVersion("2.328.0"), ReleasedAt: day("2025-08-13"), ExpiresAt: dayPtr("2025-11-13"), DaysUntilExpiry: 24},
+		{Version: mustVersion("2.327.1"), ReleasedAt: day("2025-07-25"), ExpiresAt: dayPtr("2025-09-12"), IsExpired: true, DaysUntilExpiry: -38},
+	}
+}
+
+// goldenAnalyses are the analyses rendered to golden files, by name
+func goldenAnalyses() map[string]*checker.Analysis {
+	return map[string]*checker.Analysis{
+		"current": {
+			LatestVersion:        mustVersion("2.329.0"),
+			ComparisonVersion:    mustVersion("2.329.0"),
+			ComparisonReleasedAt: dayPtr("2025-10-14"),
+			IsLatest:             true,
+			RecentReleases:       runnerTimeline(),
+			CriticalAgeDays:      12,
+			MaxAgeDays:           30,
+			PolicyType:           "days",
+		},
+		"warning": {
+			LatestVersion:         mustVersion("2.329.0"),
+			ComparisonVersion:     mustVersion("2.328.0"),
+			ComparisonReleasedAt:  dayPtr("2025-08-13"),
+			ReleasesBehind:        1,
+			DaysSinceUpdate:       6,
+			FirstNewerVersion:     mustVersion("2.329.0"),
+			FirstNewerReleaseDate: dayPtr("2025-10-14"),
+			NewerReleases: []types.Release{
+				{Version: mustVersion("2.329.0"), PublishedAt: day("2025-10-14"), URL: "https://github.com/actions/runner/releases/tag/v2.329.0"},
+			},
+			TotalNewer:   1,
+			MinorsBehind: 1,
+			Links: &checker.Links{
+				Comparison:  "https://github.com/actions/runner/releases/tag/v2.328.0",
+				Recommended: "https://github.com/actions/runner/releases/tag/v2.329.0",
+				Compare:     "https://github.com/actions/runner/compare/v2.328.0...v2.329.0",
+			},
+			RecentReleases:  runnerTimeline(),
+			CriticalAgeDays: 12,
+			MaxAgeDays:      30,
+			PolicyType:      "days",
+		},
+		"critical": {
+			LatestVersion:         mustVersion("2.329.0"),
+			ComparisonVersion:     mustVersion("2.328.0"),
+			ComparisonReleasedAt:  dayPtr("2025-08-13"),
+			IsCritical:            true,
+			ReleasesBehind:        1,
+			DaysSinceUpdate:       20,
+			FirstNewerVersion:     mustVersion("2.329.0"),
+			FirstNewerReleaseDate: dayPtr("2025-09-30"),
+			RecentReleases:        runnerTimeline(),
+			CriticalAgeDays:       12,
+			MaxAgeDays:            30,
+			PolicyType:            "days",
+			TokenSource:           "env (GITHUB_TOKEN)",
+		},
+		"expired": {
+			LatestVersion:         mustVersion("2.329.0"),
+			ComparisonVersion:     mustVersion("2.327.1"),
+			ComparisonReleasedAt:  dayPtr("2025-07-25"),
+			IsExpired:             true,
+			ReleasesBehind:        2,
+			DaysSinceUpdate:       68,
+			FirstNewerVersion:     mustVersion("2.328.0"),
+			FirstNewerReleaseDate: dayPtr("2025-08-13"),
+			NewerReleases: []types.Release{
+				{Version: mustVersion("2.329.0"), PublishedAt: day("2025-10-14"), URL: "https://github.com/actions/runner/releases/tag/v2.329.0"},
+				{Version: mustVersion("2.328.0"), PublishedAt: day("2025-08-13"), URL: "https://github.com/actions/runner/releases/tag/v2.328.0"},
+			},
+			TotalNewer:      2,
+			MinorsBehind:    2,
+			RecentReleases:  runnerTimeline(),
+			CriticalAgeDays: 12,
+			MaxAgeDays:      30,
+			PolicyType:      "days",
+		},
+		"versions": {
+			LatestVersion:       mustVersion("1.34.1"),
+			ComparisonVersion:   mustVersion("1.31.4"),
+			IsExpired:           true,
+			ReleasesBehind:      9,
+			PolicyType:          "versions",
+			MinorVersionsBehind: 3,
+			RecentReleases: []checker.ReleaseExpiry{
+				{Version: mustVersion("1.34.1"), ReleasedAt: day("2025-09-10"), IsLatest: true},
+				{Version: mustVersion("1.34.0"), ReleasedAt: day("2025-08-27")},
+				{Version: mustVersion("1.33.4"), ReleasedAt: day("2025-08-12")},
+				{Version: mustVersion("1.31.4"), ReleasedAt: day("2024-12-10")},
+				{Version: mustVersion("0.9.0"), ReleasedAt: day("2016-01-01")},
+			},
+		},
+		"channel": {
+			LatestVersion:        mustVersion("2.329.0"),
+			ComparisonVersion:    mustVersion("2.328.0"),
+			ComparisonReleasedAt: dayPtr("2025-08-13"),
+			ReleasesBehind:       1,
+			DaysSinceUpdate:      6,
+			FirstNewerVersion:    mustVersion("2.329.0"),
+			Channel:              "latest-1",
+			RecentReleases:       runnerTimeline(),
+			CriticalAgeDays:      12,
+			MaxAgeDays:           30,
+			PolicyType:           "days",
+		},
+		"release-line": {
+			LatestVersion:        mustVersion("2.329.0"),
+			ComparisonVersion:    mustVersion("2.328.0"),
+			ComparisonReleasedAt: dayPtr("2025-08-13"),
+			ReleasesBehind:       1,
+			DaysSinceUpdate:      6,
+			FirstNewerVersion:    mustVersion("2.329.0"),
+			ResolvedFrom:         "2.328",
+			RecentReleases:       runnerTimeline(),
+			CriticalAgeDays:      12,
+			MaxAgeDays:           30,
+			PolicyType:           "days",
+		},
+		"maintenance-window": {
+			LatestVersion:         mustVersion("2.329.0"),
+			ComparisonVersion:     mustVersion("2.328.0"),
+			ComparisonReleasedAt:  dayPtr("2025-08-13"),
+			IsCritical:            true,
+			ReleasesBehind:        1,
+			DaysSinceUpdate:       20,
+			FirstNewerVersion:     mustVersion("2.329.0"),
+			FirstNewerReleaseDate: dayPtr("2025-09-30"),
+			MaintenanceWindow:     dayPtr("2025-10-28"),
+			RecentReleases:        runnerTimeline(),
+			CriticalAgeDays:       12,
+			MaxAgeDays:            30,
+			PolicyType:            "days",
+		},
+		"fork": {
+			LatestVersion:        mustVersion("2.329.0"),
+			ComparisonVersion:    mustVersion("2.329.0"),
+			ComparisonReleasedAt: dayPtr("2025-10-14"),
+			IsLatest:             true,
+			Repository:           "mycorp/runner-fork",
+			Upstream:             "actions/runner",
+			RecentReleases:       runnerTimeline(),
+			CriticalAgeDays:      12,
+			MaxAgeDays:           30,
+			PolicyType:           "days",
+		},
+		"yanked": {
+			LatestVersion:        mustVersion("2.329.0"),
+			ComparisonVersion:    mustVersion("2.329.0"),
+			ComparisonReleasedAt: dayPtr("2025-10-14"),
+			IsLatest:             true,
+			Yanked:               &checker.YankedRelease{Version: mustVersion("2.329.0"), Reason: "jobs hang on Windows"},
+			RecommendedVersion:   mustVersion("2.328.0"),
+			RecentReleases:       runnerTimeline(),
+			CriticalAgeDays:      12,
+			MaxAgeDays:           30,
+			PolicyType:           "days",
+		},
+		"newer-capped": {
+			LatestVersion:         mustVersion("1.34.1"),
+			ComparisonVersion:     mustVersion("1.31.4"),
+			IsExpired:             true,
+			ReleasesBehind:        12,
+			DaysSinceUpdate:       303,
+			FirstNewerVersion:     mustVersion("1.31.5"),
+			FirstNewerReleaseDate: dayPtr("2024-12-21"),
+			PolicyType:            "versions",
+			MinorVersionsBehind:   3,
+			NewerReleases: []types.Release{
+				{Version: mustVersion("1.34.0"), PublishedAt: day("2025-08-27"), URL: "https://github.com/kubernetes/kubernetes/releases/tag/v1.34.0"},
+				{Version: mustVersion("1.34.1"), PublishedAt: day("2025-09-10"), URL: "https://github.com/kubernetes/kubernetes/releases/tag/v1.34.1"},
+			},
+			TotalNewer:    12,
+			PatchesBehind: 3,
+			MinorsBehind:  9,
+		},
+		"rollback": {
+			LatestVersion:        mustVersion("2.329.0"),
+			ComparisonVersion:    mustVersion("2.329.0"),
+			ComparisonReleasedAt: dayPtr("2025-10-14"),
+			IsLatest:             true,
+			RolledBack: []checker.Waiver{{
+				Version:    mustVersion("2.330.0"),
+				Expires:    day("2025-10-27"),
+				Reason:     "upstream is rolling back a job dispatch regression",
+				ApprovedBy: "platform-team",
+				Rollback:   true,
+			}},
+			RecentReleases:  runnerTimeline(),
+			CriticalAgeDays: 12,
+			MaxAgeDays:      30,
+			PolicyType:      "days",
+		},
+		"waived": {
+			LatestVersion:         mustVersion("2.329.0"),
+			ComparisonVersion:     mustVersion("2.327.1"),
+			ComparisonReleasedAt:  dayPtr("2025-07-25"),
+			ReleasesBehind:        2,
+			DaysSinceUpdate:       68,
+			FirstNewerVersion:     mustVersion("2.328.0"),
+			FirstNewerReleaseDate: dayPtr("2025-08-13"),
+			Waiver: &checker.Waiver{
+				Version:    mustVersion("2.327.1"),
+				Expires:    day("2025-11-01"),
+				Reason:     "change freeze until the October release",
+				ApprovedBy: "platform-team",
+			},
+			Waived:          true,
+			RecentReleases:  runnerTimeline(),
+			CriticalAgeDays: 12,
+			MaxAgeDays:      30,
+			PolicyType:      "days",
+		},
+		"degraded": {
+			LatestVersion:     mustVersion("2.329.0"),
+			ComparisonVersion: mustVersion("2.329.0"),
+			IsLatest:          true,
+			HighestVersion:    mustVersion("2.329.0"),
+			MarkedLatest:      mustVersion("2.328.0"),
+			DegradedReasons:   []checker.DegradedReason{checker.DegradedTruncated},
+		},
+		"prerelease-ahead": {
+			LatestVersion:             mustVersion("2.329.0"),
+			ComparisonVersion:         mustVersion("2.329.0"),
+			IsLatest:                  true,
+			LatestStable:              mustVersion("2.329.0"),
+			LatestIncludingPrerelease: mustVersion("2.330.0-rc.1"),
+		},
+		"release-channel": {
+			LatestVersion:             mustVersion("2.330.0-rc.1"),
+			ComparisonVersion:         mustVersion("2.330.0-rc.1"),
+			IsLatest:                  true,
+			LatestStable:              mustVersion("2.329.0"),
+			LatestIncludingPrerelease: mustVersion("2.330.0-rc.1"),
+			ReleaseChannel:            "rc",
+		},
+		"latest-only": {
+			LatestVersion:  mustVersion("2.329.0"),
+			RecentReleases: runnerTimeline(),
+		},
+	}
+}
+
+// assertGolden compares got with testdata/name, rewriting it with -update
+func assertGolden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run go test ./pkg/render -update to create it)", err)
+	}
+	if got != string(want) {
+		t.Errorf("output differs from %s (run go test ./pkg/render -update to accept)\n--- got ---\n%s\n--- want ---\n%s", path, got, want)
+	}
+}
+
+// TestGolden renders every analysis in every format
+func TestGolden(t *testing.T) {
+	opts := Options{Now: testNow}
+	for name, analysis := range goldenAnalyses() {
+		t.Run(name, func(t *testing.T) {
+			assertGolden(t, name+".terminal.golden", Terminal(analysis, opts))
+			assertGolden(t, name+".ci.golden", CI(analysis, opts))
+
+			data, err := JSON(analysis)
+			if err != nil {
+				t.Fatalf("JSON() error = %v", err)
+			}
+			assertGolden(t, name+".json.golden", string(data)+"\n")
+
+			if analysis.ComparisonVersion != nil {
+				assertGolden(t, name+".summary.golden", Summary(analysis, opts))
+			}
+		})
+	}
+}
+
+// oneOmitted returns analysis with one newer release left out of NewerReleases
+func oneOmitted(analysis *checker.Analysis) *checker.Analysis {
+	one := *analysis
+	one.ReleasesBehind = len(one.NewerReleases) + 1
+	one.TotalNewer = one.ReleasesBehind
+	one.PatchesBehind, one.MinorsBehind = 0, one.ReleasesBehind
+	return &one
+}
+
+// TestGolden_Options tests the options that change terminal and CI output
+func TestGolden_Options(t *testing.T) {
+	analyses := goldenAnalyses()
+	tests := []struct {
+		name   string
+		render func() string
+	}{
+		{"expired-details.terminal.golden", func() string {
+			return Terminal(analyses["expired"], Options{Now: testNow, Details: true})
+		}},
+		{"newer-capped-details.terminal.golden", func() string {
+			return Terminal(analyses["newer-capped"], Options{Now: testNow, Details: true})
+		}},
+		{"newer-capped-one-details.terminal.golden", func() string {
+			return Terminal(oneOmitted(analyses["newer-capped"]), Options{Now: testNow, Details: true})
+		}},
+		{"newer-capped-one.summary.golden", func() string {
+			return Summary(oneOmitted(analyses["newer-capped"]), Options{Now: testNow})
+		}},
+		{"expired-gap.terminal.golden", func() string {
+			analysis := analyses["expired"]
+			gaps := []int{62, 19}
+			for i := range gaps {
+				analysis.RecentReleases[i].GapDays = &gaps[i]
+			}
+			return Timeline(analysis, "", Options{Now: testNow, Columns: []string{ColumnVersion, ColumnReleased, ColumnGap, ColumnStatus}})
+		}},
+		{"expired-quiet.terminal.golden", func() string {
+			return Terminal(analyses["expired"], Options{Now: testNow, Quiet: true})
+		}},
+		{"latest-only-details.terminal.golden", func() string {
+			return Terminal(analyses["latest-only"], Options{Now: testNow, Details: true})
+		}},
+		{"expired-iso-narrow.terminal.golden", func() string {
+			return Terminal(analyses["expired"], Options{Now: testNow, DateFormat: DateFormats["iso"], Columns: []string{ColumnVersion, ColumnStatus}, MaxWidth: 30})
+		}},
+		{"expired-annotation-warning.ci.golden", func() string {
+			return CI(analyses["expired"], Options{Now: testNow, AnnotationLevels: map[checker.Status]string{checker.StatusExpired: "warning"}})
+		}},
+		{"expired-no-updates.summary.golden", func() string {
+			return Summary(analyses["expired"], Options{Now: testNow, SummaryExclude: []string{"updates", "Timestamp"}})
+		}},
+		{"requested-missing.timeline.golden", func() string {
+			return Timeline(analyses["current"], "2.328.5", Options{Now: testNow})
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertGolden(t, tt.name, tt.render())
+		})
+	}
+}
+
+// TestTimeline_MaxWidth tests no line exceeds the width limit
+func TestTimeline_MaxWidth(t *testing.T) {
+	out := Timeline(goldenAnalyses()["expired"], "", Options{Now: testNow, MaxWidth: 30})
+	for _, line := range strings.Split(out, "\n") {
+		if len([]rune(line)) > 30 {
+			t.Errorf("line exceeds max width: %q", line)
+		}
+	}
+}
+
+// TestSummaryTemplate tests a user template sees the analysis and helpers
+func TestSummaryTemplate(t *testing.T) {
+	tmpl := template.Must(template.New("summary").Funcs(TemplateFuncs(Options{})).Parse(
+		"{{ .StatusIcon }} v{{ .Analysis.ComparisonVersion }} is {{ .Status }} ({{ .DaysOverdue }} days overdue, checked {{ .CheckedAt }})\n" +
+			"{{ range .Analysis.NewerReleases }}- v{{ .Version }} {{ date .PublishedAt }}, {{ daysAgo .PublishedAt }} days ago\n{{ end }}"))
+
+	got, err := SummaryTemplate(tmpl, goldenAnalyses()["expired"], Options{Now: testNow, DateFormat: DateFormats["iso"]})
+	if err != nil {
+		t.Fatalf("SummaryTemplate() error = %v", err)
+	}
+	want := "🚨 v2.327.1 is expired (38 days overdue, checked 2025-10-20T09:30:00Z)\n" +
+		"- v2.329.0 2025-10-14, 6 days ago\n- v2.328.0 2025-08-13, 68 days ago\n"
+	if got != want {
+		t.Errorf("SummaryTemplate() =\n%q\nwant\n%q", got, want)
+	}
+}
+
+// TestStatusText tests status names
+func TestStatusText(t *testing.T) {
+	tests := []struct {
+		status checker.Status
+		text   string
+		icon   string
+	}{
+		{checker.StatusCurrent, "Current", "✅"},
+		{checker.StatusWarning, "Behind", "⚠️ "},
+		{checker.StatusCritical, "Critical", "🔶"},
+		{checker.StatusExpired, "Expired", "🚨"},
+		{checker.Status("invalid"), "Unknown", "ℹ️ "},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			if got := StatusText(tt.status); got != tt.text {
+				t.Errorf("StatusText() = %q, want %q", got, tt.text)
+			}
+			if got := StatusIcon(tt.status); got != tt.icon {
+				t.Errorf("StatusIcon() = %q, want %q", got, tt.icon)
+			}
+		})
+	}
+}
+
+// TestFormatDate tests the default and preset date formats
+func TestFormatDate(t *testing.T) {
+	date := time.Date(2024, 10, 5, 14, 30, 0, 0, time.UTC)
+	tests := []struct {
+		format DateFormat
+		want   string
+	}{
+		{DateFormat{}, "05 Oct 2024"},
+		{DateFormats["uk"], "05 Oct 2024"},
+		{DateFormats["us"], "Oct 05, 2024"},
+		{DateFormats["eu"], "05.10.2024"},
+		{DateFormats["iso"], "2024-10-05"},
+	}
+	for _, tt := range tests {
+		if got := (Options{DateFormat: tt.format}).FormatDate(date); got != tt.want {
+			t.Errorf("FormatDate() with %q = %q, want %q", tt.format.Date, got, tt.want)
+		}
+	}
+}
+
+// TestTruncateWidth tests shortening to a width in characters
+func TestTruncateWidth(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+		want  string
+	}{
+		{"2.329.0", 0, "2.329.0"},
+		{"2.329.0", 7, "2.329.0"},
+		{"2.329.0", 5, "2.32…"},
+		{"✅ Latest", 3, "✅ …"},
+	}
+	for _, tt := range tests {
+		if got := TruncateWidth(tt.s, tt.width); got != tt.want {
+			t.Errorf("TruncateWidth(%q, %d) = %q, want %q", tt.s, tt.width, got, tt.want)
+		}
+	}
+}
+
+// TestAnnotation tests workflow commands and suppressed levels
+func TestAnnotation(t *testing.T) {
+	levels := map[checker.Status]string{
+		checker.StatusExpired:  "warning",
+		checker.StatusCritical: AnnotationNone,
+	}
+
+	got := Annotation(levels, checker.StatusExpired, "🚨 Version 2.327.0 EXPIRED")
+	want := "::warning title=Runner Version Expired::🚨 Version 2.327.0 EXPIRED"
+	if got != want {
+		t.Errorf("Annotation() = %q, want %q", got, want)
+	}
+	if got := Annotation(levels, checker.StatusCritical, "message"); got != "" {
+		t.Errorf("expected suppressed annotation, got %q", got)
+	}
+}
+
+// TestDescribeDegraded tests the explanation of incomplete data
+func TestDescribeDegraded(t *testing.T) {
+	got := DescribeDegraded([]checker.DegradedReason{checker.DegradedTruncated, "custom reason"})
+	if !strings.Contains(got, "1,000 releases") || !strings.HasSuffix(got, "; custom reason") {
+		t.Errorf("DescribeDegraded() = %q", got)
+	}
+}
+
+// TestStatusLine_MissedMaintenanceWindow tests a window that has already passed
+func TestStatusLine_MissedMaintenanceWindow(t *testing.T) {
+	analysis := goldenAnalyses()["maintenance-window"]
+	analysis.MaintenanceWindow = dayPtr("2025-10-14")
+
+	got := statusLine(analysis, Options{Now: testNow}, false)
+	if !strings.Contains(got, "missed its last maintenance window, 14 Oct 2025, and expires 30 Oct 2025") {
+		t.Errorf("statusLine() = %q", got)
+	}
+}
+
+// TestStatusLine_FourComponents tests that four-component versions print as released
+func TestStatusLine_FourComponents(t *testing.T) {
+	analysis := &checker.Analysis{
+		ComparisonVersion: mustVersion("10.0.19041+rev.1"),
+		LatestVersion:     mustVersion("10.0.19041+rev.4"),
+	}
+
+	got := statusLine(analysis, Options{Now: testNow}, false)
+	if want := "Version 10.0.19041.1: Update to v10.0.19041.4"; got != want {
+		t.Errorf("statusLine() = %q, want %q", got, want)
+	}
+}
+
+func TestDescribeWaiver(t *testing.T) {
+	waiver := &checker.Waiver{Version: mustVersion("2.327.1"), Expires: day("2025-11-01"), Reason: "freeze", ApprovedBy: "ops"}
+	opts := Options{Now: testNow, DateFormat: DateFormats["iso"]}
+
+	tests := []struct {
+		name     string
+		analysis *checker.Analysis
+		now      time.Time
+		want     string
+	}{
+		{"none", &checker.Analysis{}, testNow, ""},
+		{"waived", &checker.Analysis{Waiver: waiver, Waived: true}, testNow, "Expiry waived until 2025-11-01, approved by ops: freeze"},
+		{"not needed", &checker.Analysis{Waiver: waiver}, testNow, "Waiver on file until 2025-11-01, approved by ops: freeze"},
+		{"lapsed", &checker.Analysis{Waiver: waiver}, day("2025-11-02"), "Waiver lapsed 2025-11-01 (approved by ops: freeze)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts.Now = tt.now
+			if got := DescribeWaiver(tt.analysis, opts); got != tt.want {
+				t.Errorf("DescribeWaiver() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDescribeYanked(t *testing.T) {
+	bad := &checker.YankedRelease{Version: mustVersion("2.329.0"), Reason: "regression"}
+	tests := []struct {
+		name     string
+		analysis *checker.Analysis
+		want     string
+	}{
+		{"none", &checker.Analysis{LatestVersion: mustVersion("2.329.0"), ComparisonVersion: mustVersion("2.328.0")}, ""},
+		{"comparison yanked", &checker.Analysis{LatestVersion: mustVersion("2.329.0"), ComparisonVersion: mustVersion("2.329.0"), Yanked: bad, RecommendedVersion: mustVersion("2.328.0")},
+			"Version 2.329.0 is a known-bad release (regression): use v2.328.0 instead"},
+		{"no alternative", &checker.Analysis{LatestVersion: mustVersion("2.329.0"), ComparisonVersion: mustVersion("2.329.0"), Yanked: bad},
+			"Version 2.329.0 is a known-bad release (regression), with no alternative available"},
+		{"latest yanked", &checker.Analysis{LatestVersion: mustVersion("2.329.0"), ComparisonVersion: mustVersion("2.327.1"), RecommendedVersion: mustVersion("2.328.0")},
+			"Latest release v2.329.0 is a known-bad release; v2.328.0 is recommended instead"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DescribeYanked(tt.analysis); got != tt.want {
+				t.Errorf("DescribeYanked() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDescribeReview(t *testing.T) {
+	tests := []struct {
+		name     string
+		analysis *checker.Analysis
+		want     string
+	}{
+		{"not needed", &checker.Analysis{LatestVersion: mustVersion("2.329.1"), ComparisonVersion: mustVersion("2.329.0")}, ""},
+		{"needed", &checker.Analysis{LatestVersion: mustVersion("3.0.0"), ComparisonVersion: mustVersion("2.329.0"), RequiresManualReview: true,
+			ReviewReasons: []string{"crosses major version 2 to 3", "breaking changes in v3.0.0 release notes"}},
+			"Updating to v3.0.0 needs manual review: crosses major version 2 to 3; breaking changes in v3.0.0 release notes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DescribeReview(tt.analysis); got != tt.want {
+				t.Errorf("DescribeReview() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatReleasesBehind(t *testing.T) {
+	tests := []struct {
+		name     string
+		analysis *checker.Analysis
+		want     string
+	}{
+		{"up to date", &checker.Analysis{}, "0"},
+		{"one patch", &checker.Analysis{ReleasesBehind: 1, PatchesBehind: 1}, "1 (patch)"},
+		{"all patches", &checker.Analysis{ReleasesBehind: 6, PatchesBehind: 6}, "6 (all patches)"},
+		{"mixed", &checker.Analysis{ReleasesBehind: 8, MajorsBehind: 1, MinorsBehind: 2, PatchesBehind: 5}, "8 (1 major, 2 minors, 5 patches)"},
+		{"not counted by kind", &checker.Analysis{ReleasesBehind: 4}, "4"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatReleasesBehind(tt.analysis); got != tt.want {
+				t.Errorf("FormatReleasesBehind() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDescribeAssets(t *testing.T) {
+	incomplete := func(comparison, recommended string) *checker.Analysis {
+		a := &checker.Analysis{LatestVersion: mustVersion("2.330.0"), IncompleteRelease: mustVersion("2.330.0"), MissingAssets: []string{"linux-arm64", "win-x64"}}
+		if comparison != "" {
+			a.ComparisonVersion = mustVersion(comparison)
+		}
+		if recommended != "" {
+			a.RecommendedVersion = mustVersion(recommended)
+		}
+		return a
+	}
+	tests := []struct {
+		name     string
+		analysis *checker.Analysis
+		want     string
+	}{
+		{"complete", &checker.Analysis{LatestVersion: mustVersion("2.330.0"), ComparisonVersion: mustVersion("2.328.0")}, ""},
+		{"earlier release", incomplete("2.328.0", "2.329.0"), "v2.330.0 has no assets for linux-arm64, win-x64 yet; v2.329.0 is recommended instead"},
+		{"stay", incomplete("2.329.0", "2.329.0"), "v2.330.0 has no assets for linux-arm64, win-x64 yet; stay on v2.329.0 until they are published"},
+		{"no alternative", incomplete("", ""), "v2.330.0 has no assets for linux-arm64, win-x64 yet"},
+		{"latest lookup", incomplete("", "2.329.0"), "v2.330.0 has no assets for linux-arm64, win-x64 yet; v2.329.0 is recommended instead"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DescribeAssets(tt.analysis); got != tt.want {
+				t.Errorf("DescribeAssets() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDescribeSoaking(t *testing.T) {
+	soaking := func(comparison, recommended string) *checker.Analysis {
+		a := &checker.Analysis{
+			LatestVersion:  mustVersion("2.330.0"),
+			SoakingVersion: mustVersion("2.330.0"),
+			NewerReleases:  []types.Release{{Version: mustVersion("2.330.0"), PublishedAt: day("2025-10-15")}},
+		}
+		if comparison != "" {
+			a.ComparisonVersion = mustVersion(comparison)
+		}
+		if recommended != "" {
+			a.RecommendedVersion = mustVersion(recommended)
+		}
+		return a
+	}
+	tests := []struct {
+		name     string
+		analysis *checker.Analysis
+		want     string
+	}{
+		{"not soaking", &checker.Analysis{LatestVersion: mustVersion("2.330.0"), ComparisonVersion: mustVersion("2.328.0")}, ""},
+		{"earlier release", soaking("2.328.0", "2.329.0"), "v2.330.0 (released 15 Oct 2025) is still soaking; v2.329.0 is recommended until then"},
+		{"stay", soaking("2.329.0", "2.329.0"), "v2.330.0 (released 15 Oct 2025) is still soaking; stay on v2.329.0 until it has been out longer"},
+		{"no alternative", soaking("", ""), "v2.330.0 (released 15 Oct 2025) is still soaking"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DescribeSoaking(tt.analysis, Options{}); got != tt.want {
+				t.Errorf("DescribeSoaking() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
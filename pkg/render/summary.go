@@ -0,0 +1,203 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/nickromney-org/github-release-version-checker/pkg/checker"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+// SummarySections lists the sections of the default job summary, in order
+var SummarySections = []string{"header", "table", "action", "updates", "timestamp"}
+
+// SummaryData is the data passed to a job summary template
+type SummaryData struct {
+	Analysis        *checker.Analysis
+	Status          checker.Status
+	StatusIcon      string
+	StatusText      string
+	DaysOverdue     int
+	DaysUntilExpiry int
+	CheckedAt       string
+}
+
+// TemplateFuncs returns the helper functions available to job summary templates
+func TemplateFuncs(opts Options) template.FuncMap {
+	return template.FuncMap{
+		"ukDate":        func(t time.Time) string { return t.Format(DateFormats["uk"].Date) },
+		"date":          opts.FormatDate,
+		"daysAgo":       opts.daysSince,
+		"formatDaysAgo": FormatDaysAgo,
+	}
+}
+
+// sectionEnabled reports whether a default job summary section is included
+func (o Options) sectionEnabled(name string) bool {
+	for _, excluded := range o.SummaryExclude {
+		if strings.EqualFold(strings.TrimSpace(excluded), name) {
+			return false
+		}
+	}
+	return true
+}
+
+// Summary renders the markdown job summary for $GITHUB_STEP_SUMMARY
+func Summary(analysis *checker.Analysis, opts Options) string {
+	var b strings.Builder
+	status := analysis.Status()
+	statusEmoji := StatusIcon(status)
+	statusText := StatusText(status)
+
+	if opts.sectionEnabled("header") {
+		fmt.Fprintf(&b, "## %s Runner Version Status: %s\n\n", statusEmoji, statusText)
+	}
+
+	if opts.sectionEnabled("table") {
+		fmt.Fprintf(&b, "| Metric | Value |\n")
+		fmt.Fprintf(&b, "|--------|-------|\n")
+		fmt.Fprintf(&b, "| Current Version | v%s |\n", types.FormatVersion(analysis.ComparisonVersion))
+		if analysis.Channel != "" {
+			fmt.Fprintf(&b, "| Channel | %s |\n", analysis.Channel)
+		}
+		if analysis.ResolvedFrom != "" {
+			fmt.Fprintf(&b, "| Resolved From | %s |\n", analysis.ResolvedFrom)
+		}
+		if analysis.Upstream != "" {
+			fmt.Fprintf(&b, "| Upstream | %s |\n", analysis.Upstream)
+		}
+		fmt.Fprintf(&b, "| Latest Version | v%s |\n", types.FormatVersion(analysis.LatestVersion))
+		fmt.Fprintf(&b, "| Status | %s %s |\n", statusEmoji, statusText)
+		fmt.Fprintf(&b, "| Releases Behind | %s |\n", FormatReleasesBehind(analysis))
+		fmt.Fprintf(&b, "| Drift Score | %d |\n", analysis.DriftScore())
+		if links := analysis.Links; links != nil {
+			if links.Recommended != "" && links.Recommended != links.Comparison {
+				fmt.Fprintf(&b, "| Release Notes | [v%s](%s) |\n", types.FormatVersion(analysis.Recommended()), links.Recommended)
+			}
+			if links.Compare != "" {
+				fmt.Fprintf(&b, "| Changes | [v%s...v%s](%s) |\n", types.FormatVersion(analysis.ComparisonVersion), types.FormatVersion(analysis.Recommended()), links.Compare)
+			}
+		}
+
+		if waiver := DescribeWaiver(analysis, opts); waiver != "" {
+			fmt.Fprintf(&b, "| Waiver | %s |\n", waiver)
+		}
+		if rollbacks := DescribeRollbacks(analysis, opts); rollbacks != "" {
+			fmt.Fprintf(&b, "| Upstream Rollback | %s |\n", rollbacks)
+		}
+		if yanked := DescribeYanked(analysis); yanked != "" {
+			fmt.Fprintf(&b, "| Known-Bad Release | %s |\n", yanked)
+		}
+		if soaking := DescribeSoaking(analysis, opts); soaking != "" {
+			fmt.Fprintf(&b, "| Soaking | %s |\n", soaking)
+		}
+		if assets := DescribeAssets(analysis); assets != "" {
+			fmt.Fprintf(&b, "| Release Assets | %s |\n", assets)
+		}
+		if review := DescribeReview(analysis); review != "" {
+			fmt.Fprintf(&b, "| Manual Review | %s |\n", review)
+		}
+		if analysis.MaintenanceWindow != nil {
+			fmt.Fprintf(&b, "| Maintenance Window | %s |\n", opts.FormatDate(*analysis.MaintenanceWindow))
+		}
+
+		if analysis.DaysSinceUpdate > 0 {
+			if analysis.IsExpired || analysis.Waived {
+				fmt.Fprintf(&b, "| Days Overdue | %d |\n", -analysis.DaysUntilExpiry())
+			} else {
+				fmt.Fprintf(&b, "| Days Until Expiry | %d |\n", analysis.DaysUntilExpiry())
+			}
+		}
+	}
+
+	if opts.sectionEnabled("action") {
+		// Version policies can expire a version without a first newer release
+		update := analysis.FirstNewerVersion
+		if update == nil {
+			update = analysis.Recommended()
+		}
+		switch status {
+		case checker.StatusExpired:
+			fmt.Fprintf(&b, "\n### ⚠️ Action Required\n\n")
+			fmt.Fprintf(&b, "**Update to v%s or later immediately.** ", types.FormatVersion(update))
+			fmt.Fprintf(&b, "GitHub will not queue jobs to runners with expired versions.\n")
+		case checker.StatusCritical:
+			fmt.Fprintf(&b, "\n### ⚠️ Update Soon\n\n")
+			fmt.Fprintf(&b, "Version expires in **%d days**. Update to v%s or later.\n", analysis.DaysUntilExpiry(), types.FormatVersion(update))
+		case checker.StatusWarning:
+			fmt.Fprintf(&b, "\n### ℹ️ Update Available\n\n")
+			if analysis.Yanked != nil {
+				fmt.Fprintf(&b, "%s.\n", DescribeYanked(analysis))
+			} else if analysis.Waived {
+				fmt.Fprintf(&b, "Expiry is waived until %s. Update to v%s or later before then.\n", opts.FormatDate(analysis.Waiver.Expires), types.FormatVersion(update))
+			} else {
+				fmt.Fprintf(&b, "A newer version (v%s) is available.\n", types.FormatVersion(analysis.Recommended()))
+			}
+		}
+	}
+
+	if opts.sectionEnabled("updates") && len(analysis.NewerReleases) > 0 {
+		fmt.Fprintf(&b, "\n### 📦 Available Updates\n\n")
+		if omitted := analysis.NewerOmitted(); omitted > 0 {
+			fmt.Fprintf(&b, "- … and %d older release%s\n", omitted, types.PluralSuffix(omitted))
+		}
+		for _, release := range analysis.NewerReleases {
+			fmt.Fprintf(&b, "- [v%s](%s) - Released %s (%d days ago)\n",
+				types.FormatVersion(release.Version), release.URL, opts.FormatDate(release.PublishedAt), opts.daysSince(release.PublishedAt))
+		}
+	}
+
+	if opts.sectionEnabled("timestamp") {
+		fmt.Fprintf(&b, "\n*Checked at: %s*\n", opts.checkedAt())
+	}
+
+	fmt.Fprintf(&b, "\n---\n\n")
+	return b.String()
+}
+
+// SummaryTemplate renders the job summary with a user-supplied template. The
+// template's helper functions are rebound to opts, so it may be parsed with
+// TemplateFuncs(Options{}).
+func SummaryTemplate(tmpl *template.Template, analysis *checker.Analysis, opts Options) (string, error) {
+	return executeTemplate(tmpl, analysis, opts)
+}
+
+// DefaultNotificationTemplate is the notification message when no template is given
+const DefaultNotificationTemplate = `{{ .StatusIcon }} {{ with .Analysis.Repository }}{{ . }} {{ end }}v{{ .Analysis.ComparisonVersion }}: {{ .StatusText }}. Update to v{{ .Analysis.Recommended }}.`
+
+// Notification renders a chat or webhook notification message with a template
+// over the same data as job summary templates, e.g. DefaultNotificationTemplate
+func Notification(tmpl *template.Template, analysis *checker.Analysis, opts Options) (string, error) {
+	message, err := executeTemplate(tmpl, analysis, opts)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(message), nil
+}
+
+// executeTemplate runs a user template over SummaryData for the analysis
+func executeTemplate(tmpl *template.Template, analysis *checker.Analysis, opts Options) (string, error) {
+	status := analysis.Status()
+	data := SummaryData{
+		Analysis:   analysis,
+		Status:     status,
+		StatusIcon: StatusIcon(status),
+		StatusText: StatusText(status),
+		CheckedAt:  opts.checkedAt(),
+	}
+	if analysis.DaysSinceUpdate > 0 {
+		if analysis.IsExpired {
+			data.DaysOverdue = -analysis.DaysUntilExpiry()
+		} else {
+			data.DaysUntilExpiry = analysis.DaysUntilExpiry()
+		}
+	}
+
+	var b strings.Builder
+	if err := tmpl.Funcs(TemplateFuncs(opts)).Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,382 @@
+package render
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/nickromney-org/github-release-version-checker/pkg/checker"
+	"github.com/nickromney-org/github-release-version-checker/pkg/types"
+)
+
+// Timeline table column names, in display order
+const (
+	ColumnVersion  = "version"
+	ColumnReleased = "released"
+	ColumnExpires  = "expires"
+	ColumnStatus   = "status"
+	ColumnGap      = "gap"
+)
+
+// Columns lists the timeline table columns shown by default
+var Columns = []string{ColumnVersion, ColumnReleased, ColumnExpires, ColumnStatus}
+
+// OptionalColumns lists the timeline table columns shown only when chosen in
+// Options.Columns
+var OptionalColumns = []string{ColumnGap}
+
+// columnHeaders maps column names to their table headings
+var columnHeaders = map[string]string{
+	ColumnVersion:  "Version",
+	ColumnReleased: "Release Date",
+	ColumnExpires:  "Expiry Date",
+	ColumnStatus:   "Status",
+	ColumnGap:      "Gap",
+}
+
+// Terminal renders an analysis for the terminal. The first line is always the
+// latest version, for script compatibility.
+func Terminal(analysis *checker.Analysis, opts Options) string {
+	var b strings.Builder
+	fmt.Fprintln(&b, types.FormatVersion(analysis.LatestVersion))
+
+	// Without a comparison version, the timeline is a detail
+	if analysis.ComparisonVersion == nil {
+		if opts.Details && len(analysis.RecentReleases) > 0 {
+			fmt.Fprintln(&b)
+			b.WriteString(Timeline(analysis, "", opts))
+		}
+		return b.String()
+	}
+
+	fmt.Fprintln(&b)
+	status := analysis.Status()
+	StatusColour(status).Fprintln(&b, StatusIcon(status)+" "+statusLine(analysis, opts, analysis.PolicyType == "versions"))
+	if analysis.Channel != "" {
+		grey.Fprintf(&b, "ℹ️  Channel %s is v%s\n", analysis.Channel, types.FormatVersion(analysis.ComparisonVersion))
+	}
+	if analysis.ResolvedFrom != "" {
+		grey.Fprintf(&b, "ℹ️  %s resolved to v%s, its latest patch release\n", analysis.ResolvedFrom, types.FormatVersion(analysis.ComparisonVersion))
+	}
+	if analysis.ReleaseChannel != "" {
+		grey.Fprintf(&b, "ℹ️  Checked against the %s channel and stable releases\n", analysis.ReleaseChannel)
+	}
+	if analysis.Upstream != "" {
+		grey.Fprintf(&b, "ℹ️  Checked against %s releases\n", analysis.Upstream)
+	}
+	if waiver := DescribeWaiver(analysis, opts); waiver != "" {
+		yellow.Fprintf(&b, "📝 %s\n", waiver)
+	}
+	if rollbacks := DescribeRollbacks(analysis, opts); rollbacks != "" {
+		yellow.Fprintf(&b, "↩️  %s\n", rollbacks)
+	}
+	if yanked := DescribeYanked(analysis); yanked != "" {
+		yellow.Fprintf(&b, "🚫 %s\n", yanked)
+	}
+	if soaking := DescribeSoaking(analysis, opts); soaking != "" {
+		grey.Fprintf(&b, "🕒 %s\n", soaking)
+	}
+	if assets := DescribeAssets(analysis); assets != "" {
+		yellow.Fprintf(&b, "📦 %s\n", assets)
+	}
+	if review := DescribeReview(analysis); review != "" {
+		yellow.Fprintf(&b, "🔍 %s\n", review)
+	}
+	if analysis.IsDegraded() {
+		yellow.Fprintf(&b, "⚠️  Incomplete data: %s\n", DescribeDegraded(analysis.DegradedReasons))
+	}
+	if analysis.LatestDiscrepancy() {
+		yellow.Fprintf(&b, "ℹ️  %s\n", DescribeLatestDiscrepancy(analysis))
+	}
+	if analysis.PrereleaseAhead() {
+		grey.Fprintf(&b, "ℹ️  %s\n", DescribePrereleaseAhead(analysis))
+	}
+
+	if !opts.Quiet {
+		b.WriteString(Timeline(analysis, "", opts))
+	}
+
+	if opts.Details {
+		fmt.Fprintln(&b)
+		b.WriteString(details(analysis, opts))
+	}
+	return b.String()
+}
+
+// Timeline renders the release timeline table, starting with a blank line.
+// A requested version that is not a release is shown in its place among the
+// releases, marked as not existing.
+func Timeline(analysis *checker.Analysis, requested string, opts Options) string {
+	if len(analysis.RecentReleases) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	isVersionPolicy := analysis.PolicyType == "versions"
+	columns := opts.Columns
+	if columns == nil {
+		columns = Columns
+	}
+	table := newTimelineTable(columns, isVersionPolicy, opts.MaxWidth)
+
+	fmt.Fprintln(&b)
+	if isVersionPolicy {
+		cyan.Fprintln(&b, TruncateWidth("📋 Release Timeline", opts.MaxWidth))
+	} else {
+		cyan.Fprintln(&b, TruncateWidth("📅 Release Expiry Timeline", opts.MaxWidth))
+	}
+	cyan.Fprintln(&b, table.rule())
+	fmt.Fprintln(&b, table.header())
+
+	var phantomVersion *semver.Version
+	if requested != "" {
+		if v, err := types.ParseVersion(requested); err == nil {
+			phantomVersion = v
+		}
+	}
+	phantomRow := map[string]string{
+		ColumnVersion:  "",
+		ColumnReleased: "-",
+		ColumnExpires:  "-",
+		ColumnStatus:   "❌ Does Not Exist",
+		ColumnGap:      "-",
+	}
+
+	phantomPrinted := false
+	for i, release := range analysis.RecentReleases {
+		// Print the requested version before the first newer release
+		if phantomVersion != nil && !phantomPrinted && types.CompareVersions(phantomVersion, release.Version) < 0 {
+			phantomRow[ColumnVersion] = phantomVersion.String()
+			bold.Fprintln(&b, table.row(phantomRow, "  ← Your requested version"))
+			phantomPrinted = true
+		}
+
+		var expiresStr, statusStr string
+		if isVersionPolicy {
+			expiresStr = FormatDaysAgo(opts.daysSince(release.ReleasedAt))
+			statusStr = versionSkew(analysis, release)
+		} else if release.IsLatest {
+			expiresStr = "-"
+			statusStr = fmt.Sprintf("✅ Latest (%s)", FormatDaysAgo(opts.daysSince(release.ReleasedAt)))
+		} else if release.ExpiresAt != nil {
+			expiresStr = opts.FormatDate(*release.ExpiresAt)
+			if release.IsExpired {
+				statusStr = fmt.Sprintf("❌ Expired %s", FormatDaysAgo(-release.DaysUntilExpiry))
+			} else {
+				statusStr = fmt.Sprintf("✅ Valid (%s left)", FormatDaysInFuture(release.DaysUntilExpiry))
+			}
+		}
+
+		cells := map[string]string{
+			ColumnVersion:  release.Version.String(),
+			ColumnReleased: opts.FormatDate(release.ReleasedAt),
+			ColumnExpires:  expiresStr,
+			ColumnStatus:   statusStr,
+			ColumnGap:      formatGap(release.GapDays),
+		}
+
+		// Mark the user's version with bold and an arrow
+		if analysis.ComparisonVersion != nil && types.CompareVersions(release.Version, analysis.ComparisonVersion) == 0 {
+			bold.Fprintln(&b, table.row(cells, "  ← Your version"))
+		} else {
+			fmt.Fprintln(&b, table.row(cells, ""))
+		}
+
+		// Otherwise the requested version goes after the last release
+		if phantomVersion != nil && !phantomPrinted && i == len(analysis.RecentReleases)-1 {
+			phantomRow[ColumnVersion] = phantomVersion.String()
+			bold.Fprintln(&b, table.row(phantomRow, "  ← Your requested version"))
+			phantomPrinted = true
+		}
+	}
+
+	if cadence := describeCadence(analysis, opts); cadence != "" && table.shows(ColumnGap) {
+		grey.Fprintf(&b, "\n%s", TruncateWidth(cadence, opts.MaxWidth))
+	}
+	grey.Fprintf(&b, "\n%s\n", TruncateWidth("Checked at: "+opts.checkedAt(), opts.MaxWidth))
+	return b.String()
+}
+
+// formatGap describes the days since the previous release, "-" for the first
+func formatGap(days *int) string {
+	switch {
+	case days == nil:
+		return "-"
+	case *days == 0:
+		return "same day"
+	case *days == 1:
+		return "1 day"
+	default:
+		return fmt.Sprintf("%d days", *days)
+	}
+}
+
+// describeCadence compares the typical gap between the timeline's releases,
+// their median, with the time since the latest, so a quiet spell can be told
+// apart from the usual cadence; "" without gaps to compare
+func describeCadence(analysis *checker.Analysis, opts Options) string {
+	var gaps []int
+	var latest *checker.ReleaseExpiry
+	for i, r := range analysis.RecentReleases {
+		if r.GapDays != nil {
+			gaps = append(gaps, *r.GapDays)
+		}
+		if r.IsLatest {
+			latest = &analysis.RecentReleases[i]
+		}
+	}
+	if len(gaps) == 0 || latest == nil {
+		return ""
+	}
+	slices.Sort(gaps)
+	typical := (gaps[(len(gaps)-1)/2] + gaps[len(gaps)/2]) / 2
+	return fmt.Sprintf("Typical gap: %s; latest release %s", formatGap(&typical), FormatDaysAgo(opts.daysSince(latest.ReleasedAt)))
+}
+
+// versionSkew describes how far a release is behind the latest under a
+// version-based policy, e.g. "-2 minor  ← Minor release"
+func versionSkew(analysis *checker.Analysis, release checker.ReleaseExpiry) string {
+	isMinorRelease := release.Version.Patch() == 0
+	suffix := ""
+	if isMinorRelease {
+		suffix = "  ← Minor release"
+	}
+
+	if release.IsLatest {
+		return "✅ Latest" + suffix
+	}
+	if release.Version.Major() != analysis.LatestVersion.Major() {
+		return fmt.Sprintf("v%d", release.Version.Major())
+	}
+
+	minorDiff := int(analysis.LatestVersion.Minor()) - int(release.Version.Minor())
+	switch {
+	case minorDiff > 0:
+		return fmt.Sprintf("-%d minor%s", minorDiff, suffix)
+	case minorDiff < 0:
+		return "" // Newer than latest; should not happen
+	}
+
+	patchDiff := int(analysis.LatestVersion.Patch()) - int(release.Version.Patch())
+	if patchDiff > 0 {
+		return fmt.Sprintf("-%d patch%s", patchDiff, suffix)
+	}
+	return "Same as latest"
+}
+
+// details renders the detailed analysis shown with -v
+func details(analysis *checker.Analysis, opts Options) string {
+	var b strings.Builder
+	cyan.Fprintln(&b, "📊 Detailed Analysis")
+	cyan.Fprintln(&b, "─────────────────────────────────────")
+
+	fmt.Fprintf(&b, "  Current version:      v%s\n", types.FormatVersion(analysis.ComparisonVersion))
+	fmt.Fprintf(&b, "  Latest version:       v%s\n", types.FormatVersion(analysis.LatestVersion))
+	if analysis.RecommendedVersion != nil {
+		fmt.Fprintf(&b, "  Recommended version:  v%s\n", types.FormatVersion(analysis.RecommendedVersion))
+	}
+	fmt.Fprintf(&b, "  Status:               %s\n", analysis.Status())
+	fmt.Fprintf(&b, "  Releases behind:      %s\n", FormatReleasesBehind(analysis))
+	fmt.Fprintf(&b, "  Drift score:          %d\n", analysis.DriftScore())
+	if analysis.TokenSource != "" {
+		fmt.Fprintf(&b, "  Token source:         %s\n", analysis.TokenSource)
+	}
+
+	if analysis.FirstNewerVersion != nil {
+		fmt.Fprintf(&b, "  First newer release:  v%s\n", types.FormatVersion(analysis.FirstNewerVersion))
+		if analysis.FirstNewerReleaseDate != nil {
+			fmt.Fprintf(&b, "  Released on:          %s\n", analysis.FirstNewerReleaseDate.Format("2006-01-02"))
+			fmt.Fprintf(&b, "  Days since update:    %d\n", analysis.DaysSinceUpdate)
+
+			if analysis.MaxAgeDays > 0 {
+				if daysLeft := analysis.DaysUntilExpiry(); daysLeft > 0 {
+					fmt.Fprintf(&b, "  Days until expired:   %d\n", daysLeft)
+				} else {
+					fmt.Fprintf(&b, "  Days overdue:         %d\n", -daysLeft)
+				}
+			}
+		}
+	}
+
+	if len(analysis.NewerReleases) > 0 {
+		fmt.Fprintln(&b)
+		cyan.Fprintln(&b, "📋 Available Updates")
+		cyan.Fprintln(&b, "─────────────────────────────────────")
+		if omitted := analysis.NewerOmitted(); omitted > 0 {
+			fmt.Fprintf(&b, "  … and %d older release%s\n", omitted, types.PluralSuffix(omitted))
+		}
+		for _, release := range analysis.NewerReleases {
+			fmt.Fprintf(&b, "  • v%s (%s, %d days ago)\n",
+				types.FormatVersion(release.Version), release.PublishedAt.Format("2006-01-02"), opts.daysSince(release.PublishedAt))
+		}
+	}
+	return b.String()
+}
+
+// timelineTable lays out the timeline table for the selected columns and width
+type timelineTable struct {
+	columns  []string
+	widths   map[string]int
+	maxWidth int
+}
+
+// newTimelineTable builds a table layout; version-based policies have no expiry column
+func newTimelineTable(columns []string, isVersionPolicy bool, maxWidth int) *timelineTable {
+	widths := map[string]int{ColumnVersion: 10, ColumnReleased: 14, ColumnExpires: 14, ColumnGap: 9}
+	if isVersionPolicy {
+		widths[ColumnVersion] = 12
+	}
+
+	var visible []string
+	for _, column := range columns {
+		if isVersionPolicy && column == ColumnExpires {
+			continue
+		}
+		visible = append(visible, column)
+	}
+
+	return &timelineTable{columns: visible, widths: widths, maxWidth: maxWidth}
+}
+
+// shows reports whether the table has column
+func (t *timelineTable) shows(column string) bool {
+	return slices.Contains(t.columns, column)
+}
+
+// header returns the heading row
+func (t *timelineTable) header() string {
+	return t.row(columnHeaders, "")
+}
+
+// rule returns the horizontal rule under the table title
+func (t *timelineTable) rule() string {
+	return TruncateWidth(strings.Repeat("─", 53), t.maxWidth)
+}
+
+// row formats one table row from cells keyed by column name, followed by an optional marker
+func (t *timelineTable) row(cells map[string]string, marker string) string {
+	var b strings.Builder
+	for i, column := range t.columns {
+		if i == len(t.columns)-1 {
+			b.WriteString(cells[column])
+		} else {
+			fmt.Fprintf(&b, "%-*s ", t.widths[column], cells[column])
+		}
+	}
+	b.WriteString(marker)
+
+	return TruncateWidth(strings.TrimRight(b.String(), " "), t.maxWidth)
+}
+
+// TruncateWidth shortens s to at most width characters, ending with "…" (0 = no limit)
+func TruncateWidth(s string, width int) string {
+	runes := []rune(s)
+	if width <= 0 || len(runes) <= width {
+		return s
+	}
+	if width == 1 {
+		return "…"
+	}
+	return string(runes[:width-1]) + "…"
+}
@@ -0,0 +1,6 @@
+// Package types holds the release and version types shared by the checker,
+// client and policy packages.
+//
+// This package is part of the stable v1 API; see the checker package for
+// what that guarantees.
+package types
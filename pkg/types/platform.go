@@ -0,0 +1,84 @@
+package types
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// platformAliases maps the names releases use in asset names to the GOOS and
+// GOARCH style names platforms are given as, e.g. "linux/arm64"
+var platformAliases = struct {
+	os, arch map[string][]string
+}{
+	os: map[string][]string{
+		"linux":   {"linux"},
+		"darwin":  {"darwin", "osx", "macos", "mac"},
+		"windows": {"windows", "win", "win32", "win64"},
+		"freebsd": {"freebsd"},
+	},
+	arch: map[string][]string{
+		"amd64":   {"amd64", "x64", "x86_64", "x86-64"},
+		"arm64":   {"arm64", "aarch64"},
+		"arm":     {"arm", "armv6", "armv7", "armhf"},
+		"386":     {"386", "x86", "i386", "i686"},
+		"ppc64le": {"ppc64le"},
+		"s390x":   {"s390x"},
+		"riscv64": {"riscv64"},
+	},
+}
+
+// ParsePlatform normalises a platform given as OS/ARCH, e.g. "linux/arm64" or
+// "Linux/aarch64", to GOOS and GOARCH names: "linux/arm64"
+func ParsePlatform(s string) (string, error) {
+	osName, arch, ok := strings.Cut(strings.ToLower(strings.TrimSpace(s)), "/")
+	if !ok {
+		return "", fmt.Errorf("invalid platform %q: must be OS/ARCH, e.g. linux/arm64", s)
+	}
+	goos, ok := platformName(platformAliases.os, osName)
+	if !ok {
+		return "", fmt.Errorf("invalid platform %q: unknown OS %q", s, osName)
+	}
+	goarch, ok := platformName(platformAliases.arch, arch)
+	if !ok {
+		return "", fmt.Errorf("invalid platform %q: unknown architecture %q", s, arch)
+	}
+	return goos + "/" + goarch, nil
+}
+
+// platformName returns the name a platform's OS or architecture alias stands for
+func platformName(aliases map[string][]string, alias string) (string, bool) {
+	for name, names := range aliases {
+		if slices.Contains(names, alias) {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// assetToken splits asset names into the words platforms are named by, keeping
+// "x86_64" and "x86-64" whole
+var assetToken = regexp.MustCompile(`x86[_-]64|[a-z0-9]+`)
+
+// AssetPlatforms returns the platforms, e.g. "linux/arm64", that asset names
+// such as "actions-runner-linux-arm64-2.329.0.tar.gz" were built for, sorted.
+// Names without both an OS and an architecture are left out.
+func AssetPlatforms(names []string) []string {
+	var platforms []string
+	for _, name := range names {
+		var goos, goarch string
+		for _, token := range assetToken.FindAllString(strings.ToLower(name), -1) {
+			if os, ok := platformName(platformAliases.os, token); ok && goos == "" {
+				goos = os
+			} else if arch, ok := platformName(platformAliases.arch, token); ok && goarch == "" {
+				goarch = arch
+			}
+		}
+		if goos != "" && goarch != "" && !slices.Contains(platforms, goos+"/"+goarch) {
+			platforms = append(platforms, goos+"/"+goarch)
+		}
+	}
+	slices.Sort(platforms)
+	return platforms
+}
@@ -0,0 +1,69 @@
+package types
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParsePlatform(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{input: "linux/arm64", want: "linux/arm64"},
+		{input: " Linux/AArch64 ", want: "linux/arm64"},
+		{input: "osx/x64", want: "darwin/amd64"},
+		{input: "win/x86_64", want: "windows/amd64"},
+		{input: "linux", wantErr: true},
+		{input: "plan9/amd64", wantErr: true},
+		{input: "linux/mips", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParsePlatform(tt.input)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParsePlatform(%q) = %q, %v; want %q, error %t", tt.input, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestAssetPlatforms(t *testing.T) {
+	tests := []struct {
+		name  string
+		names []string
+		want  []string
+	}{
+		{
+			name: "runner",
+			names: []string{
+				"actions-runner-linux-x64-2.329.0.tar.gz",
+				"actions-runner-linux-arm64-2.329.0.tar.gz",
+				"actions-runner-osx-arm64-2.329.0.tar.gz",
+				"actions-runner-win-x64-2.329.0.zip",
+				"actions-runner-win-x64-2.329.0.zip.sha256",
+			},
+			want: []string{"darwin/arm64", "linux/amd64", "linux/arm64", "windows/amd64"},
+		},
+		{
+			name:  "go style",
+			names: []string{"terraform_1.9.0_linux_amd64.zip", "terraform_1.9.0_darwin_arm64.zip"},
+			want:  []string{"darwin/arm64", "linux/amd64"},
+		},
+		{
+			name:  "x86_64 kept whole",
+			names: []string{"tool-x86_64-unknown-linux-gnu.tar.gz"},
+			want:  []string{"linux/amd64"},
+		},
+		{
+			name:  "no platform",
+			names: []string{"checksums.txt", "source.tar.gz", "tool-linux.tar.gz"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AssetPlatforms(tt.names); !slices.Equal(got, tt.want) {
+				t.Errorf("AssetPlatforms() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,10 @@
+package types
+
+// PluralSuffix returns "s" if count != 1, otherwise "", for messages such as
+// fmt.Sprintf("%d release%s", n, PluralSuffix(n))
+func PluralSuffix(count int) string {
+	if count == 1 {
+		return ""
+	}
+	return "s"
+}
@@ -0,0 +1,19 @@
+package types
+
+import "testing"
+
+func TestPluralSuffix(t *testing.T) {
+	tests := []struct {
+		count int
+		want  string
+	}{
+		{0, "s"},
+		{1, ""},
+		{2, "s"},
+	}
+	for _, tt := range tests {
+		if got := PluralSuffix(tt.count); got != tt.want {
+			t.Errorf("PluralSuffix(%d) = %q, want %q", tt.count, got, tt.want)
+		}
+	}
+}
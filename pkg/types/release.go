@@ -11,4 +11,10 @@ type Release struct {
 	Version     *semver.Version
 	PublishedAt time.Time
 	URL         string
+	Prerelease  bool   `json:",omitempty"` // Marked as a prerelease on GitHub
+	Notes       string `json:"-"`          // Release notes, when fetched from the API; never cached
+
+	// Platforms the release has assets for, as OS/ARCH such as "linux/arm64";
+	// nil when not recorded
+	Platforms []string `json:",omitempty"`
 }
@@ -0,0 +1,89 @@
+package types
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// revisionPrefix marks the fourth component of a version in its build metadata
+const revisionPrefix = "rev."
+
+// fourComponentVersion matches 1.2.3.4, with optional v prefix, prerelease and metadata
+var fourComponentVersion = regexp.MustCompile(`^(v?\d+\.\d+\.\d+)\.(\d+)(-[0-9A-Za-z.-]+)?(\+[0-9A-Za-z.-]+)?$`)
+
+// ParseVersion parses a semantic version, also accepting four-component versions
+// such as 1.2.3.4, common for Windows tooling. The fourth component is kept as a
+// revision in the build metadata (1.2.3+rev.4), which CompareVersions orders by.
+func ParseVersion(s string) (*semver.Version, error) {
+	m := fourComponentVersion.FindStringSubmatch(s)
+	if m == nil {
+		return semver.NewVersion(s)
+	}
+	metadata := revisionPrefix + m[2]
+	if m[4] != "" {
+		metadata += "." + strings.TrimPrefix(m[4], "+")
+	}
+	return semver.NewVersion(m[1] + m[3] + "+" + metadata)
+}
+
+// Revision returns the fourth component of a version parsed by ParseVersion,
+// or 0 for a three-component version
+func Revision(v *semver.Version) uint64 {
+	rest, ok := strings.CutPrefix(v.Metadata(), revisionPrefix)
+	if !ok {
+		return 0
+	}
+	digits, _, _ := strings.Cut(rest, ".")
+	revision, err := strconv.ParseUint(digits, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return revision
+}
+
+// FormatVersion returns a version as its project writes it: a revision parsed
+// by ParseVersion goes back to being the fourth component, so 1.2.3+rev.4 is
+// 1.2.3.4, with any other build metadata after it. Use it for versions written
+// to files or shown to people; String keeps the semver form. Returns "" for nil.
+func FormatVersion(v *semver.Version) string {
+	if v == nil {
+		return ""
+	}
+	rest, ok := strings.CutPrefix(v.Metadata(), revisionPrefix)
+	if !ok {
+		return v.String()
+	}
+	digits, metadata, _ := strings.Cut(rest, ".")
+	if _, err := strconv.ParseUint(digits, 10, 64); err != nil {
+		return v.String()
+	}
+	s := fmt.Sprintf("%d.%d.%d.%s", v.Major(), v.Minor(), v.Patch(), digits)
+	if v.Prerelease() != "" {
+		s += "-" + v.Prerelease()
+	}
+	if metadata != "" {
+		s += "+" + metadata
+	}
+	return s
+}
+
+// CompareVersions compares versions by semver precedence, then by revision, so
+// 1.2.3.5 is newer than 1.2.3.4. Returns -1, 0 or 1.
+func CompareVersions(a, b *semver.Version) int {
+	if c := a.Compare(b); c != 0 {
+		return c
+	}
+	ra, rb := Revision(a), Revision(b)
+	switch {
+	case ra < rb:
+		return -1
+	case ra > rb:
+		return 1
+	default:
+		return 0
+	}
+}
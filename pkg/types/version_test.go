@@ -0,0 +1,113 @@
+package types
+
+import (
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		want         string
+		wantRevision uint64
+		wantErr      bool
+	}{
+		{name: "three components", input: "2.328.0", want: "2.328.0"},
+		{name: "v prefix", input: "v2.328.0", want: "2.328.0"},
+		{name: "four components", input: "1.2.3.4", want: "1.2.3+rev.4", wantRevision: 4},
+		{name: "four components with v prefix", input: "v10.0.19041.1", want: "10.0.19041+rev.1", wantRevision: 1},
+		{name: "four components with prerelease", input: "1.2.3.4-beta.1", want: "1.2.3-beta.1+rev.4", wantRevision: 4},
+		{name: "four components with metadata", input: "1.2.3.4+win64", want: "1.2.3+rev.4.win64", wantRevision: 4},
+		{name: "metadata without revision", input: "1.2.3+build.7", want: "1.2.3+build.7"},
+		{name: "five components", input: "1.2.3.4.5", wantErr: true},
+		{name: "not a version", input: "latest", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseVersion(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseVersion(%q) = %s, want error", tt.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("version = %s, want %s", got, tt.want)
+			}
+			if rev := Revision(got); rev != tt.wantRevision {
+				t.Errorf("revision = %d, want %d", rev, tt.wantRevision)
+			}
+		})
+	}
+}
+
+func TestFormatVersion(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "2.328.0", want: "2.328.0"},
+		{input: "1.2.3.4", want: "1.2.3.4"},
+		{input: "v10.0.19041.1", want: "10.0.19041.1"},
+		{input: "1.2.3.4-beta.1", want: "1.2.3.4-beta.1"},
+		{input: "1.2.3.4+win64", want: "1.2.3.4+win64"},
+		{input: "1.2.3+build.7", want: "1.2.3+build.7"},
+		{input: "1.2.3+rev.x", want: "1.2.3+rev.x"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			v, err := ParseVersion(tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got := FormatVersion(v)
+			if got != tt.want {
+				t.Errorf("FormatVersion() = %s, want %s", got, tt.want)
+			}
+			// What is written back parses as the same version
+			again, err := ParseVersion(got)
+			if err != nil || again.String() != v.String() {
+				t.Errorf("ParseVersion(%q) = %v, %v, want %s", got, again, err, v)
+			}
+		})
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.2.3", "1.2.3", 0},
+		{"1.2.3.4", "1.2.3.4", 0},
+		{"1.2.3.4", "1.2.3", 1},
+		{"1.2.3.5", "1.2.3.4", 1},
+		{"1.2.3.10", "1.2.3.9", 1},
+		{"1.2.4", "1.2.3.99", 1},
+		{"1.2.3.4-rc.1", "1.2.3.4", -1},
+		{"1.2.3+build.7", "1.2.3", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" vs "+tt.b, func(t *testing.T) {
+			a, err := ParseVersion(tt.a)
+			if err != nil {
+				t.Fatalf("parse %q: %v", tt.a, err)
+			}
+			b, err := ParseVersion(tt.b)
+			if err != nil {
+				t.Fatalf("parse %q: %v", tt.b, err)
+			}
+			if got := CompareVersions(a, b); got != tt.want {
+				t.Errorf("CompareVersions(%s, %s) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+			if got := CompareVersions(b, a); got != -tt.want {
+				t.Errorf("CompareVersions(%s, %s) = %d, want %d", tt.b, tt.a, got, -tt.want)
+			}
+		})
+	}
+}
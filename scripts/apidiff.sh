@@ -0,0 +1,44 @@
+#!/bin/bash
+# Report incompatible changes to the stable v1 API against a base ref
+set -euo pipefail
+
+BASE_REF="${1:-origin/main}"
+PACKAGES=(pkg/checker pkg/types pkg/policy)
+MODULE=$(go list -m)
+
+if ! command -v apidiff >/dev/null; then
+  echo "Installing apidiff..."
+  go install golang.org/x/exp/cmd/apidiff@latest
+  PATH="$(go env GOPATH)/bin:$PATH"
+fi
+
+WORK=$(mktemp -d)
+cleanup() {
+  git worktree remove --force "$WORK/base" >/dev/null 2>&1 || true
+  rm -rf "$WORK"
+}
+trap cleanup EXIT
+
+git worktree add --detach "$WORK/base" "$BASE_REF" >/dev/null 2>&1
+
+status=0
+for pkg in "${PACKAGES[@]}"; do
+  if [ ! -d "$WORK/base/$pkg" ]; then
+    echo "➕ $pkg: new package"
+    continue
+  fi
+
+  export_file="$WORK/${pkg//\//-}.export"
+  (cd "$WORK/base" && apidiff -w "$export_file" "$MODULE/$pkg")
+
+  report=$(apidiff -incompatible "$export_file" "$MODULE/$pkg")
+  if [ -n "$report" ]; then
+    echo "❌ $pkg: incompatible with $BASE_REF"
+    echo "$report" | sed 's/^/  /'
+    status=1
+  else
+    echo "✅ $pkg: compatible with $BASE_REF"
+  fi
+done
+
+exit $status
@@ -6,10 +6,9 @@ OUTPUT_FILE="internal/data/releases.json"
 
 echo "Fetching all releases from GitHub..."
 
-# Use go run to bootstrap
-go run ./cmd/bootstrap-releases \
-  ${GITHUB_TOKEN:+--token "$GITHUB_TOKEN"} \
-  --output "$OUTPUT_FILE"
+# Regenerate every embedded cache (see the go:generate line in main.go)
+go run . generate-cache \
+  ${GITHUB_TOKEN:+--token "$GITHUB_TOKEN"}
 
 RELEASE_COUNT=$(jq '.releases | length' "$OUTPUT_FILE")
 echo "✅ Updated $OUTPUT_FILE with $RELEASE_COUNT releases"